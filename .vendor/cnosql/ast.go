@@ -208,8 +208,10 @@ func (*Query) node()     {}
 func (Statements) node() {}
 
 func (*AlterTimeToLiveStatement) node()          {}
+func (*BackfillContinuousQueryStatement) node()  {}
 func (*CreateContinuousQueryStatement) node()    {}
 func (*CreateDatabaseStatement) node()           {}
+func (*CreateRoleStatement) node()               {}
 func (*CreateTimeToLiveStatement) node()         {}
 func (*CreateSubscriptionStatement) node()       {}
 func (*CreateUserStatement) node()               {}
@@ -219,6 +221,7 @@ func (*DeleteStatement) node()                   {}
 func (*DropContinuousQueryStatement) node()      {}
 func (*DropDatabaseStatement) node()             {}
 func (*DropMetricStatement) node()               {}
+func (*DropRoleStatement) node()                 {}
 func (*DropTimeToLiveStatement) node()           {}
 func (*DropSeriesStatement) node()               {}
 func (*DropShardStatement) node()                {}
@@ -227,12 +230,19 @@ func (*DropUserStatement) node()                 {}
 func (*ExplainStatement) node()                  {}
 func (*GrantStatement) node()                    {}
 func (*GrantAdminStatement) node()               {}
+func (*GrantRoleStatement) node()                {}
 func (*KillQueryStatement) node()                {}
+func (*DropQuotaStatement) node()                {}
+func (*SetQuotaStatement) node()                 {}
+func (*ShowQuotasStatement) node()               {}
 func (*RevokeStatement) node()                   {}
 func (*RevokeAdminStatement) node()              {}
+func (*RevokeRoleStatement) node()               {}
+func (*RunContinuousQueryStatement) node()       {}
 func (*SelectStatement) node()                   {}
 func (*SetPasswordUserStatement) node()          {}
 func (*ShowContinuousQueriesStatement) node()    {}
+func (*ShowGrantsForRoleStatement) node()        {}
 func (*ShowGrantsForUserStatement) node()        {}
 func (*ShowDatabasesStatement) node()            {}
 func (*ShowFieldKeyCardinalityStatement) node()  {}
@@ -244,6 +254,7 @@ func (*ShowQueriesStatement) node()              {}
 func (*ShowSeriesStatement) node()               {}
 func (*ShowSeriesCardinalityStatement) node()    {}
 func (*ShowRegionsStatement) node()              {}
+func (*ShowRolesStatement) node()                {}
 func (*ShowShardsStatement) node()               {}
 func (*ShowStatsStatement) node()                {}
 func (*ShowSubscriptionsStatement) node()        {}
@@ -265,6 +276,7 @@ func (*IntegerLiteral) node()  {}
 func (*UnsignedLiteral) node() {}
 func (*Field) node()           {}
 func (Fields) node()           {}
+func (*Join) node()            {}
 func (*Metric) node()          {}
 func (Metrics) node()          {}
 func (*NilLiteral) node()      {}
@@ -337,8 +349,10 @@ type ExecutionPrivilege struct {
 type ExecutionPrivileges []ExecutionPrivilege
 
 func (*AlterTimeToLiveStatement) stmt()          {}
+func (*BackfillContinuousQueryStatement) stmt()  {}
 func (*CreateContinuousQueryStatement) stmt()    {}
 func (*CreateDatabaseStatement) stmt()           {}
+func (*CreateRoleStatement) stmt()               {}
 func (*CreateTimeToLiveStatement) stmt()         {}
 func (*CreateSubscriptionStatement) stmt()       {}
 func (*CreateUserStatement) stmt()               {}
@@ -347,6 +361,7 @@ func (*DeleteStatement) stmt()                   {}
 func (*DropContinuousQueryStatement) stmt()      {}
 func (*DropDatabaseStatement) stmt()             {}
 func (*DropMetricStatement) stmt()               {}
+func (*DropRoleStatement) stmt()                 {}
 func (*DropTimeToLiveStatement) stmt()           {}
 func (*DropSeriesStatement) stmt()               {}
 func (*DropSubscriptionStatement) stmt()         {}
@@ -354,8 +369,13 @@ func (*DropUserStatement) stmt()                 {}
 func (*ExplainStatement) stmt()                  {}
 func (*GrantStatement) stmt()                    {}
 func (*GrantAdminStatement) stmt()               {}
+func (*GrantRoleStatement) stmt()                {}
 func (*KillQueryStatement) stmt()                {}
+func (*DropQuotaStatement) stmt()                {}
+func (*SetQuotaStatement) stmt()                 {}
+func (*ShowQuotasStatement) stmt()               {}
 func (*ShowContinuousQueriesStatement) stmt()    {}
+func (*ShowGrantsForRoleStatement) stmt()        {}
 func (*ShowGrantsForUserStatement) stmt()        {}
 func (*ShowDatabasesStatement) stmt()            {}
 func (*ShowFieldKeyCardinalityStatement) stmt()  {}
@@ -367,6 +387,7 @@ func (*ShowTimeToLivesStatement) stmt()          {}
 func (*ShowSeriesStatement) stmt()               {}
 func (*ShowSeriesCardinalityStatement) stmt()    {}
 func (*ShowRegionsStatement) stmt()              {}
+func (*ShowRolesStatement) stmt()                {}
 func (*ShowShardsStatement) stmt()               {}
 func (*ShowStatsStatement) stmt()                {}
 func (*DropShardStatement) stmt()                {}
@@ -379,6 +400,8 @@ func (*ShowTagValuesStatement) stmt()            {}
 func (*ShowUsersStatement) stmt()                {}
 func (*RevokeStatement) stmt()                   {}
 func (*RevokeAdminStatement) stmt()              {}
+func (*RevokeRoleStatement) stmt()               {}
+func (*RunContinuousQueryStatement) stmt()       {}
 func (*SelectStatement) stmt()                   {}
 func (*SetPasswordUserStatement) stmt()          {}
 
@@ -438,6 +461,7 @@ type Source interface {
 
 func (*Metric) source()   {}
 func (*SubQuery) source() {}
+func (*Join) source()     {}
 
 // Sources represents a list of sources.
 type Sources []Source
@@ -466,6 +490,8 @@ func (a Sources) Metrics() []*Metric {
 			mms = append(mms, src)
 		case *SubQuery:
 			mms = append(mms, src.Statement.Sources.Metrics()...)
+		case *Join:
+			mms = append(mms, src.Left, src.Right)
 		}
 	}
 	return mms
@@ -514,6 +540,11 @@ func (a Sources) RequiredPrivileges() (ExecutionPrivileges, error) {
 				return nil, err
 			}
 			ep = append(ep, privs...)
+		case *Join:
+			ep = append(ep,
+				ExecutionPrivilege{Name: source.Left.Database, Privilege: ReadPrivilege},
+				ExecutionPrivilege{Name: source.Right.Database, Privilege: ReadPrivilege},
+			)
 		default:
 			return nil, fmt.Errorf("invalid source: %s", source)
 		}
@@ -724,6 +755,44 @@ func (s *DropUserStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// CreateRoleStatement represents a command for creating a new role.
+type CreateRoleStatement struct {
+	// Name of the role to be created.
+	Name string
+}
+
+// String returns a string representation of the create role statement.
+func (s *CreateRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("CREATE ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a CreateRoleStatement.
+func (s *CreateRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DropRoleStatement represents a command for dropping a role.
+type DropRoleStatement struct {
+	// Name of the role to drop.
+	Name string
+}
+
+// String returns a string representation of the drop role statement.
+func (s *DropRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("DROP ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a DropRoleStatement.
+func (s *DropRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // Privilege is a type of action a user can be granted the right to use.
 type Privilege int
 
@@ -764,8 +833,18 @@ type GrantStatement struct {
 	// Database to grant the privilege to.
 	On string
 
+	// Metric of the database to grant the privilege to. The privilege is
+	// granted on all metrics of the database if Metric is empty.
+	Metric string
+
+	// Condition on the tags of the series the privilege is granted to.
+	Condition Expr
+
 	// Who to grant the privilege to.
 	User string
+
+	// Role to grant the privilege to, instead of a user.
+	Role string
 }
 
 // String returns a string representation of the grant statement.
@@ -774,9 +853,9 @@ func (s *GrantStatement) String() string {
 	_, _ = buf.WriteString("GRANT ")
 	_, _ = buf.WriteString(s.Privilege.String())
 	_, _ = buf.WriteString(" ON ")
-	_, _ = buf.WriteString(QuoteIdent(s.On))
+	writePrivilegeTarget(&buf, s.On, s.Metric, s.Condition)
 	_, _ = buf.WriteString(" TO ")
-	_, _ = buf.WriteString(QuoteIdent(s.User))
+	writeGrantee(&buf, s.User, s.Role)
 	return buf.String()
 }
 
@@ -816,6 +895,9 @@ type KillQueryStatement struct {
 
 	// The host to delegate the kill to.
 	Host string
+
+	// The ID of the node to delegate the kill to.
+	NodeID uint64
 }
 
 // String returns a string representation of the kill query statement.
@@ -823,7 +905,10 @@ func (s *KillQueryStatement) String() string {
 	var buf strings.Builder
 	_, _ = buf.WriteString("KILL QUERY ")
 	_, _ = buf.WriteString(strconv.FormatUint(s.QueryID, 10))
-	if s.Host != "" {
+	if s.NodeID != 0 {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(strconv.FormatUint(s.NodeID, 10))
+	} else if s.Host != "" {
 		_, _ = buf.WriteString(" ON ")
 		_, _ = buf.WriteString(QuoteIdent(s.Host))
 	}
@@ -859,6 +944,98 @@ func (s *SetPasswordUserStatement) RequiredPrivileges() (ExecutionPrivileges, er
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// SetQuotaStatement represents a command for setting the quota of a database
+// or a user. Only the limits that are set are changed, and a zero limit
+// removes it.
+type SetQuotaStatement struct {
+	// Database or user whose quota is set.
+	Database string
+	User     string
+
+	// Maximum number of points and bytes written per second.
+	WritePoints *int64
+	WriteBytes  *int64
+
+	// Maximum number of queries running at the same time.
+	Queries *int
+
+	// Maximum number of series a SELECT statement may read.
+	SelectSeries *int
+}
+
+// String returns a string representation of the set quota statement.
+func (s *SetQuotaStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("SET QUOTA FOR ")
+	writeQuotaTarget(&buf, s.Database, s.User)
+	if s.WritePoints != nil {
+		_, _ = buf.WriteString(" WRITE POINTS ")
+		_, _ = buf.WriteString(strconv.FormatInt(*s.WritePoints, 10))
+	}
+	if s.WriteBytes != nil {
+		_, _ = buf.WriteString(" WRITE BYTES ")
+		_, _ = buf.WriteString(strconv.FormatInt(*s.WriteBytes, 10))
+	}
+	if s.Queries != nil {
+		_, _ = buf.WriteString(" QUERIES ")
+		_, _ = buf.WriteString(strconv.Itoa(*s.Queries))
+	}
+	if s.SelectSeries != nil {
+		_, _ = buf.WriteString(" SELECT SERIES ")
+		_, _ = buf.WriteString(strconv.Itoa(*s.SelectSeries))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a SetQuotaStatement.
+func (s *SetQuotaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DropQuotaStatement represents a command for removing all the limits of a
+// database or a user.
+type DropQuotaStatement struct {
+	// Database or user whose quota is dropped.
+	Database string
+	User     string
+}
+
+// String returns a string representation of the drop quota statement.
+func (s *DropQuotaStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("DROP QUOTA FOR ")
+	writeQuotaTarget(&buf, s.Database, s.User)
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a DropQuotaStatement.
+func (s *DropQuotaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// writeQuotaTarget writes the database or the user of a quota.
+func writeQuotaTarget(buf *strings.Builder, database, user string) {
+	if user != "" {
+		_, _ = buf.WriteString("USER ")
+		_, _ = buf.WriteString(QuoteIdent(user))
+		return
+	}
+	_, _ = buf.WriteString("DATABASE ")
+	_, _ = buf.WriteString(QuoteIdent(database))
+}
+
+// ShowQuotasStatement represents a command for listing the quotas of the
+// databases and the users.
+type ShowQuotasStatement struct{}
+
+// String returns a string representation of the show quotas statement.
+func (s *ShowQuotasStatement) String() string { return "SHOW QUOTAS" }
+
+// RequiredPrivileges returns the privilege required to execute a ShowQuotasStatement.
+func (s *ShowQuotasStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // RevokeStatement represents a command to revoke a privilege from a user.
 type RevokeStatement struct {
 	// The privilege to be revoked.
@@ -867,8 +1044,16 @@ type RevokeStatement struct {
 	// Database to revoke the privilege from.
 	On string
 
+	// Metric and condition of the revoked privilege, which must match the
+	// ones of the granted privilege.
+	Metric    string
+	Condition Expr
+
 	// Who to revoke privilege from.
 	User string
+
+	// Role to revoke the privilege from, instead of a user.
+	Role string
 }
 
 // String returns a string representation of the revoke statement.
@@ -877,9 +1062,9 @@ func (s *RevokeStatement) String() string {
 	_, _ = buf.WriteString("REVOKE ")
 	_, _ = buf.WriteString(s.Privilege.String())
 	_, _ = buf.WriteString(" ON ")
-	_, _ = buf.WriteString(QuoteIdent(s.On))
+	writePrivilegeTarget(&buf, s.On, s.Metric, s.Condition)
 	_, _ = buf.WriteString(" FROM ")
-	_, _ = buf.WriteString(QuoteIdent(s.User))
+	writeGrantee(&buf, s.User, s.Role)
 	return buf.String()
 }
 
@@ -893,6 +1078,124 @@ func (s *RevokeStatement) DefaultDatabase() string {
 	return s.On
 }
 
+// writePrivilegeTarget writes the target of a granted or revoked privilege.
+func writePrivilegeTarget(buf *strings.Builder, database, metric string, cond Expr) {
+	_, _ = buf.WriteString(QuoteIdent(database))
+	if metric != "" {
+		_ = buf.WriteByte('.')
+		_, _ = buf.WriteString(QuoteIdent(metric))
+	}
+	if cond != nil {
+		_, _ = buf.WriteString(" WHERE ")
+		_, _ = buf.WriteString(cond.String())
+	}
+}
+
+// writeGrantee writes the user, or the role if it is set, that a privilege
+// or a role is granted to or revoked from.
+func writeGrantee(buf *strings.Builder, user, role string) {
+	if role != "" {
+		_, _ = buf.WriteString("ROLE ")
+		_, _ = buf.WriteString(QuoteIdent(role))
+		return
+	}
+	_, _ = buf.WriteString(QuoteIdent(user))
+}
+
+// GrantRoleStatement represents a command for granting a role to a user, or
+// to a role that inherits the privileges of the granted role.
+type GrantRoleStatement struct {
+	// The role to be granted.
+	Name string
+
+	// Who to grant the role to.
+	User string
+
+	// Role to grant the role to, instead of a user.
+	Role string
+}
+
+// String returns a string representation of the grant role statement.
+func (s *GrantRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("GRANT ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" TO ")
+	writeGrantee(&buf, s.User, s.Role)
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a GrantRoleStatement.
+func (s *GrantRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// RevokeRoleStatement represents a command for revoking a role from a user,
+// or from a role that inherits it.
+type RevokeRoleStatement struct {
+	// The role to be revoked.
+	Name string
+
+	// Who to revoke the role from.
+	User string
+
+	// Role to revoke the role from, instead of a user.
+	Role string
+}
+
+// String returns a string representation of the revoke role statement.
+func (s *RevokeRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("REVOKE ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" FROM ")
+	writeGrantee(&buf, s.User, s.Role)
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a RevokeRoleStatement.
+func (s *RevokeRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ValidateSeriesCondition returns an error if cond isn't a condition on the
+// tags of a series. The condition may only compare tags with strings or
+// regexes, and combine the comparisons with AND and OR.
+func ValidateSeriesCondition(cond Expr) error {
+	switch expr := cond.(type) {
+	case *ParenExpr:
+		return ValidateSeriesCondition(expr.Expr)
+	case *BinaryExpr:
+		switch expr.Op {
+		case AND, OR:
+			if err := ValidateSeriesCondition(expr.LHS); err != nil {
+				return err
+			}
+			return ValidateSeriesCondition(expr.RHS)
+		case EQ, NEQ:
+			if _, ok := expr.RHS.(*StringLiteral); !ok {
+				return fmt.Errorf("invalid series condition: %s: tags may only be compared with strings", expr)
+			}
+		case EQREGEX, NEQREGEX:
+			if _, ok := expr.RHS.(*RegexLiteral); !ok {
+				return fmt.Errorf("invalid series condition: %s: tags may only be matched with regexes", expr)
+			}
+		default:
+			return fmt.Errorf("invalid series condition: %s: unsupported operator %s", expr, expr.Op)
+		}
+
+		ref, ok := expr.LHS.(*VarRef)
+		if !ok {
+			return fmt.Errorf("invalid series condition: %s: expected a tag on the left-hand side", expr)
+		} else if strings.ToLower(ref.Val) == "time" {
+			return fmt.Errorf("invalid series condition: %s: time is not a tag", expr)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid series condition: %s", cond)
+	}
+}
+
 // RevokeAdminStatement represents a command to revoke admin privilege from a user.
 type RevokeAdminStatement struct {
 	// Who to revoke admin privilege from.
@@ -1166,6 +1469,8 @@ func cloneSource(s Source) Source {
 		return s.Clone()
 	case *SubQuery:
 		return &SubQuery{Statement: s.Statement.Clone()}
+	case *Join:
+		return s.Clone()
 	default:
 		panic("unreachable")
 	}
@@ -2396,6 +2701,38 @@ func (s *ShowGrantsForUserStatement) RequiredPrivileges() (ExecutionPrivileges,
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// ShowGrantsForRoleStatement represents a command for listing role privileges.
+type ShowGrantsForRoleStatement struct {
+	// Name of the role to display privileges.
+	Name string
+}
+
+// String returns a string representation of the show grants for role.
+func (s *ShowGrantsForRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("SHOW GRANTS FOR ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a ShowGrantsForRoleStatement
+func (s *ShowGrantsForRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ShowRolesStatement represents a command for listing roles.
+type ShowRolesStatement struct{}
+
+// String returns a string representation of the ShowRolesStatement.
+func (s *ShowRolesStatement) String() string {
+	return "SHOW ROLES"
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowRolesStatement
+func (s *ShowRolesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // ShowDatabasesStatement represents a command for listing all databases in the cluster.
 type ShowDatabasesStatement struct{}
 
@@ -2510,6 +2847,57 @@ func (s *DropContinuousQueryStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// BackfillContinuousQueryStatement represents a command for running a
+// continuous query over a past time range.
+type BackfillContinuousQueryStatement struct {
+	Name     string
+	Database string
+
+	// The time range to compute, from StartTime up to but excluding EndTime.
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// String returns a string representation of the statement.
+func (s *BackfillContinuousQueryStatement) String() string {
+	return fmt.Sprintf("BACKFILL CONTINUOUS QUERY %s ON %s FROM %s TO %s",
+		QuoteIdent(s.Name), QuoteIdent(s.Database),
+		QuoteString(s.StartTime.UTC().Format(time.RFC3339Nano)),
+		QuoteString(s.EndTime.UTC().Format(time.RFC3339Nano)))
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a BackfillContinuousQueryStatement.
+func (s *BackfillContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *BackfillContinuousQueryStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// RunContinuousQueryStatement represents a command for running a continuous
+// query now, regardless of the last time it ran.
+type RunContinuousQueryStatement struct {
+	Name     string
+	Database string
+}
+
+// String returns a string representation of the statement.
+func (s *RunContinuousQueryStatement) String() string {
+	return fmt.Sprintf("RUN CONTINUOUS QUERY %s ON %s", QuoteIdent(s.Name), QuoteIdent(s.Database))
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a RunContinuousQueryStatement.
+func (s *RunContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *RunContinuousQueryStatement) DefaultDatabase() string {
+	return s.Database
+}
+
 // ShowMetricCardinalityStatement represents a command for listing metric cardinality.
 type ShowMetricCardinalityStatement struct {
 	Exact         bool // If false then cardinality estimation will be used.
@@ -2770,6 +3158,7 @@ type CreateSubscriptionStatement struct {
 	TimeToLive   string
 	Destinations []string
 	Mode         string
+	Durable      bool
 }
 
 // String returns a string representation of the CreateSubscriptionStatement.
@@ -2790,6 +3179,9 @@ func (s *CreateSubscriptionStatement) String() string {
 		}
 		_, _ = buf.WriteString(QuoteString(dest))
 	}
+	if s.Durable {
+		_, _ = buf.WriteString(" DURABLE")
+	}
 
 	return buf.String()
 }
@@ -3475,6 +3867,143 @@ func (s *SubQuery) String() string {
 	return fmt.Sprintf("(%s)", s.Statement.String())
 }
 
+// JoinType is the type of a join.
+type JoinType int
+
+const (
+	// InnerJoin only returns the points of the left metric matching points
+	// of the right metric.
+	InnerJoin JoinType = iota
+	// LeftJoin returns all the points of the left metric, with null values
+	// for the right metric if they do not match any of its points.
+	LeftJoin
+)
+
+// String returns the keyword of the join type.
+func (t JoinType) String() string {
+	switch t {
+	case InnerJoin:
+		return "INNER"
+	case LeftJoin:
+		return "LEFT"
+	}
+	return ""
+}
+
+// Join is a source joining the points of two metrics with equal tag values
+// and times. The condition is a conjunction of equalities between a tag of
+// the left metric and a tag of the right metric, and the fields and the tags
+// of the join are qualified by the name of their metric, e.g. cpu.host.
+type Join struct {
+	Type      JoinType
+	Left      *Metric
+	Right     *Metric
+	Condition Expr
+}
+
+// Clone returns a deep copy of the join.
+func (j *Join) Clone() *Join {
+	return &Join{
+		Type:      j.Type,
+		Left:      j.Left.Clone(),
+		Right:     j.Right.Clone(),
+		Condition: CloneExpr(j.Condition),
+	}
+}
+
+// String returns a string representation of the join.
+func (j *Join) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString(j.Left.String())
+	_, _ = buf.WriteString(" ")
+	_, _ = buf.WriteString(j.Type.String())
+	_, _ = buf.WriteString(" JOIN ")
+	_, _ = buf.WriteString(j.Right.String())
+	_, _ = buf.WriteString(" ON ")
+	_, _ = buf.WriteString(j.Condition.String())
+	return buf.String()
+}
+
+// Resolve returns the metric of the join qualifying a variable name and the
+// name without its qualifier. It returns nil if the name is not qualified
+// by one of the metrics.
+func (j *Join) Resolve(name string) (*Metric, string) {
+	var m *Metric
+	for _, mm := range []*Metric{j.Left, j.Right} {
+		if strings.HasPrefix(name, mm.Name+".") && (m == nil || len(mm.Name) > len(m.Name)) {
+			m = mm
+		}
+	}
+	if m == nil {
+		return nil, ""
+	}
+	return m, name[len(m.Name)+1:]
+}
+
+// IsKey returns true if the unqualified name is the one of a tag compared
+// by the condition of the join. Such names refer to the tag of the left
+// metric.
+func (j *Join) IsKey(name string) bool {
+	left, _, err := j.Keys()
+	if err != nil {
+		return false
+	}
+	for _, k := range left {
+		if k == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the tags of the left and of the right metric compared by the
+// condition of the join, in the order of the condition.
+func (j *Join) Keys() (left, right []string, err error) {
+	var visit func(expr Expr) error
+	visit = func(expr Expr) error {
+		switch expr := expr.(type) {
+		case *ParenExpr:
+			return visit(expr.Expr)
+		case *BinaryExpr:
+			switch expr.Op {
+			case AND:
+				if err := visit(expr.LHS); err != nil {
+					return err
+				}
+				return visit(expr.RHS)
+			case EQ:
+				lhs, ok := expr.LHS.(*VarRef)
+				if !ok {
+					break
+				}
+				rhs, ok := expr.RHS.(*VarRef)
+				if !ok {
+					break
+				}
+
+				lm, lk := j.Resolve(lhs.Val)
+				rm, rk := j.Resolve(rhs.Val)
+				if lm == j.Right && rm == j.Left {
+					lm, lk, rm, rk = rm, rk, lm, lk
+				}
+				if lm == j.Left && rm == j.Right {
+					left, right = append(left, lk), append(right, rk)
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("invalid join condition %s: expected equalities between tags of %s and %s", expr, j.Left.Name, j.Right.Name)
+	}
+
+	if j.Condition == nil {
+		return nil, nil, errors.New("join condition required")
+	}
+	if err := visit(j.Condition); err != nil {
+		return nil, nil, err
+	}
+	return left, right, nil
+}
+
 // VarRef represents a reference to a variable.
 type VarRef struct {
 	Val  string
@@ -3974,6 +4503,11 @@ func Walk(v Visitor, node Node) {
 	case *SubQuery:
 		Walk(v, n.Statement)
 
+	case *Join:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+		Walk(v, n.Condition)
+
 	case Statements:
 		for _, s := range n {
 			Walk(v, s)
@@ -4675,6 +5209,14 @@ func (v *TypeValuerEval) evalVarRefExprType(expr *VarRef) (DataType, error) {
 						}
 					}
 				}
+			case *Join:
+				if m, name := src.Resolve(expr.Val); m != nil {
+					if t := v.TypeMapper.MapType(m, name); typ.LessThan(t) {
+						typ = t
+					}
+				} else if src.IsKey(expr.Val) && typ.LessThan(Tag) {
+					typ = Tag
+				}
 			}
 		}
 	}
@@ -4833,6 +5375,25 @@ func FieldDimensions(sources Sources, m FieldMapper) (fields map[string]DataType
 					dimensions[expr.Val] = struct{}{}
 				}
 			}
+		case *Join:
+			// The fields and the tags of a join are qualified by the name
+			// of their metric.
+			for _, mm := range []*Metric{src.Left, src.Right} {
+				f, d, err := m.FieldDimensions(mm)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				for k, typ := range f {
+					k = mm.Name + "." + k
+					if fields[k].LessThan(typ) {
+						fields[k] = typ
+					}
+				}
+				for k := range d {
+					dimensions[mm.Name+"."+k] = struct{}{}
+				}
+			}
 		}
 	}
 	return
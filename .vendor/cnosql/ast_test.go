@@ -1723,25 +1723,34 @@ func Test_EnforceHasDefaultDatabase(t *testing.T) {
 	// this is a list of statements that do not have a database context
 	exemptStatements := []string{
 		"CreateDatabaseStatement",
+		"CreateRoleStatement",
 		"CreateUserStatement",
 		"DeleteSeriesStatement",
 		"DropDatabaseStatement",
 		"DropMetricStatement",
 		"DropSeriesStatement",
 		"DropShardStatement",
+		"DropQuotaStatement",
+		"DropRoleStatement",
 		"DropUserStatement",
 		"ExplainStatement",
 		"GrantAdminStatement",
+		"GrantRoleStatement",
 		"KillQueryStatement",
 		"RevokeAdminStatement",
+		"RevokeRoleStatement",
 		"SelectStatement",
 		"SetPasswordUserStatement",
+		"SetQuotaStatement",
 		"ShowContinuousQueriesStatement",
 		"ShowDatabasesStatement",
 		"ShowDiagnosticsStatement",
+		"ShowGrantsForRoleStatement",
 		"ShowGrantsForUserStatement",
 		"ShowQueriesStatement",
+		"ShowQuotasStatement",
 		"ShowRegionsStatement",
+		"ShowRolesStatement",
 		"ShowShardsStatement",
 		"ShowStatsStatement",
 		"ShowSubscriptionsStatement",
@@ -125,7 +125,7 @@ func init() {
 			})
 		})
 		show.Group(GRANTS).Handle(FOR, func(p *Parser) (Statement, error) {
-			return p.parseGrantsForUserStatement()
+			return p.parseShowGrantsForStatement()
 		})
 		show.Group(METRIC).Handle(EXACT, func(p *Parser) (Statement, error) {
 			return p.parseShowMetricCardinalityStatement(true)
@@ -139,6 +139,12 @@ func init() {
 		show.Handle(QUERIES, func(p *Parser) (Statement, error) {
 			return p.parseShowQueriesStatement()
 		})
+		show.Handle(QUOTAS, func(p *Parser) (Statement, error) {
+			return p.parseShowQuotasStatement()
+		})
+		show.Handle(ROLES, func(p *Parser) (Statement, error) {
+			return p.parseShowRolesStatement()
+		})
 		show.Handle(REGIONS, func(p *Parser) (Statement, error) {
 			return p.parseShowRegionsStatement()
 		})
@@ -182,6 +188,9 @@ func init() {
 		create.Handle(USER, func(p *Parser) (Statement, error) {
 			return p.parseCreateUserStatement()
 		})
+		create.Handle(ROLE, func(p *Parser) (Statement, error) {
+			return p.parseCreateRoleStatement()
+		})
 		create.Handle(SUBSCRIPTION, func(p *Parser) (Statement, error) {
 			return p.parseCreateSubscriptionStatement()
 		})
@@ -214,6 +223,12 @@ func init() {
 		drop.Handle(USER, func(p *Parser) (Statement, error) {
 			return p.parseDropUserStatement()
 		})
+		drop.Handle(ROLE, func(p *Parser) (Statement, error) {
+			return p.parseDropRoleStatement()
+		})
+		drop.Group(QUOTA).Handle(FOR, func(p *Parser) (Statement, error) {
+			return p.parseDropQuotaStatement()
+		})
 	})
 	Language.Handle(EXPLAIN, func(p *Parser) (Statement, error) {
 		return p.parseExplainStatement()
@@ -230,7 +245,16 @@ func init() {
 	Language.Group(SET, PASSWORD).Handle(FOR, func(p *Parser) (Statement, error) {
 		return p.parseSetPasswordUserStatement()
 	})
+	Language.Group(SET, QUOTA).Handle(FOR, func(p *Parser) (Statement, error) {
+		return p.parseSetQuotaStatement()
+	})
 	Language.Group(KILL).Handle(QUERY, func(p *Parser) (Statement, error) {
 		return p.parseKillQueryStatement()
 	})
+	Language.Group(BACKFILL, CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
+		return p.parseBackfillContinuousQueryStatement()
+	})
+	Language.Group(RUN, CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
+		return p.parseRunContinuousQueryStatement()
+	})
 }
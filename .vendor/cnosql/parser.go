@@ -124,6 +124,109 @@ func (p *Parser) parseSetPasswordUserStatement() (*SetPasswordUserStatement, err
 	return stmt, nil
 }
 
+// parseSetQuotaStatement parses a string and returns a SetQuotaStatement.
+// This function assumes the "SET QUOTA FOR" tokens have already been consumed.
+func (p *Parser) parseSetQuotaStatement() (*SetQuotaStatement, error) {
+	stmt := &SetQuotaStatement{}
+
+	var err error
+	if stmt.Database, stmt.User, err = p.parseQuotaTarget(); err != nil {
+		return nil, err
+	}
+
+	// Parse the limits, at least one is required.
+	for found := false; ; found = true {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		switch tok {
+		case WRITE:
+			// POINTS and BYTES are not keywords so that they can still be
+			// used as identifiers.
+			tok, pos, lit := p.ScanIgnoreWhitespace()
+			if tok != IDENT || (!strings.EqualFold(lit, "POINTS") && !strings.EqualFold(lit, "BYTES")) {
+				return nil, newParseError(tokstr(tok, lit), []string{"POINTS", "BYTES"}, pos)
+			}
+
+			n, err := p.parseQuotaLimit()
+			if err != nil {
+				return nil, err
+			}
+			if strings.EqualFold(lit, "POINTS") {
+				stmt.WritePoints = &n
+			} else {
+				stmt.WriteBytes = &n
+			}
+		case QUERIES:
+			n, err := p.ParseInt(0, math.MaxInt32)
+			if err != nil {
+				return nil, err
+			}
+			stmt.Queries = &n
+		case SELECT:
+			if err := p.parseTokens([]Token{SERIES}); err != nil {
+				return nil, err
+			}
+			n, err := p.ParseInt(0, math.MaxInt32)
+			if err != nil {
+				return nil, err
+			}
+			stmt.SelectSeries = &n
+		default:
+			if !found {
+				return nil, newParseError(tokstr(tok, lit), []string{"WRITE", "QUERIES", "SELECT"}, pos)
+			}
+			p.Unscan()
+			return stmt, nil
+		}
+	}
+}
+
+// parseDropQuotaStatement parses a string and returns a DropQuotaStatement.
+// This function assumes the "DROP QUOTA FOR" tokens have already been consumed.
+func (p *Parser) parseDropQuotaStatement() (*DropQuotaStatement, error) {
+	stmt := &DropQuotaStatement{}
+
+	var err error
+	if stmt.Database, stmt.User, err = p.parseQuotaTarget(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseQuotaTarget parses the database or the user of a quota, as
+// "DATABASE <name>" or "USER <name>".
+func (p *Parser) parseQuotaTarget() (database, user string, err error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	switch tok {
+	case DATABASE:
+		database, err = p.ParseIdent()
+	case USER:
+		user, err = p.ParseIdent()
+	default:
+		err = newParseError(tokstr(tok, lit), []string{"DATABASE", "USER"}, pos)
+	}
+	return database, user, err
+}
+
+// parseQuotaLimit parses a non-negative 64-bit limit of a quota.
+func (p *Parser) parseQuotaLimit() (int64, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != INTEGER {
+		return 0, newParseError(tokstr(tok, lit), []string{"integer"}, pos)
+	}
+
+	n, err := strconv.ParseInt(lit, 10, 64)
+	if err != nil {
+		return 0, &ParseError{Message: err.Error(), Pos: pos}
+	}
+	return n, nil
+}
+
+// parseShowQuotasStatement parses a string and returns a ShowQuotasStatement.
+// This function assumes the "SHOW QUOTAS" tokens have already been consumed.
+func (p *Parser) parseShowQuotasStatement() (*ShowQuotasStatement, error) {
+	return &ShowQuotasStatement{}, nil
+}
+
 // parseKillQueryStatement parses a string and returns a kill statement.
 // This function assumes the KILL token has already been consumed.
 func (p *Parser) parseKillQueryStatement() (*KillQueryStatement, error) {
@@ -132,16 +235,23 @@ func (p *Parser) parseKillQueryStatement() (*KillQueryStatement, error) {
 		return nil, err
 	}
 
-	var host string
+	stmt := &KillQueryStatement{QueryID: qid}
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
-		host, err = p.ParseIdent()
+		// The node is either identified by its ID or by its host.
+		tok, _, _ := p.ScanIgnoreWhitespace()
+		p.Unscan()
+		if tok == INTEGER {
+			stmt.NodeID, err = p.ParseUInt64()
+		} else {
+			stmt.Host, err = p.ParseIdent()
+		}
 		if err != nil {
 			return nil, err
 		}
 	} else {
 		p.Unscan()
 	}
-	return &KillQueryStatement{QueryID: qid, Host: host}, nil
+	return stmt, nil
 }
 
 // parseCreateSubscriptionStatement parses a string and returns a CreateSubscriptionStatement.
@@ -196,6 +306,13 @@ func (p *Parser) parseCreateSubscriptionStatement() (*CreateSubscriptionStatemen
 	}
 	stmt.Destinations = destinations
 
+	// Parse optional DURABLE keyword.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == DURABLE {
+		stmt.Durable = true
+	} else {
+		p.Unscan()
+	}
+
 	return stmt, nil
 }
 
@@ -542,6 +659,12 @@ func (p *Parser) parseStringList() ([]string, error) {
 // parseRevokeStatement parses a string and returns a revoke statement.
 // This function assumes the REVOKE token has already been consumed.
 func (p *Parser) parseRevokeStatement() (Statement, error) {
+	// Check for the revoke of a role.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		return p.parseRevokeRoleStatement()
+	}
+	p.Unscan()
+
 	// Parse the privilege to be revoked.
 	priv, err := p.parsePrivilege()
 	if err != nil {
@@ -577,12 +700,12 @@ func (p *Parser) parseRevokeStatement() (Statement, error) {
 func (p *Parser) parseRevokeOnStatement() (*RevokeStatement, error) {
 	stmt := &RevokeStatement{}
 
-	// Parse the name of the database.
-	lit, err := p.ParseIdent()
-	if err != nil {
+	// Parse the name of the database, the optional metric and the optional
+	// condition on the series.
+	var err error
+	if stmt.On, stmt.Metric, stmt.Condition, err = p.parsePrivilegeTarget(); err != nil {
 		return nil, err
 	}
-	stmt.On = lit
 
 	// Parse FROM clause.
 	tok, pos, lit := p.ScanIgnoreWhitespace()
@@ -592,12 +715,45 @@ func (p *Parser) parseRevokeOnStatement() (*RevokeStatement, error) {
 		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
 	}
 
-	// Parse the name of the user.
-	lit, err = p.ParseIdent()
+	// Parse the name of the user, or of the role.
+	if stmt.Role, err = p.parseRoleGrantee(stmt.Metric, stmt.Condition); err != nil {
+		return nil, err
+	} else if stmt.Role != "" {
+		return stmt, nil
+	}
+	if stmt.User, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// parseRevokeRoleStatement parses a string and returns a revoke role statement.
+// This function assumes the REVOKE ROLE tokens have already been consumed.
+func (p *Parser) parseRevokeRoleStatement() (*RevokeRoleStatement, error) {
+	stmt := &RevokeRoleStatement{}
+
+	// Parse the name of the role.
+	var err error
+	if stmt.Name, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	// Parse FROM clause.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FROM {
+		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
+	}
+
+	// Parse the name of the user, or of the role inheriting the role.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		stmt.Role, err = p.ParseIdent()
+	} else {
+		p.Unscan()
+		stmt.User, err = p.ParseIdent()
+	}
 	if err != nil {
 		return nil, err
 	}
-	stmt.User = lit
 
 	return stmt, nil
 }
@@ -621,6 +777,12 @@ func (p *Parser) parseRevokeAdminStatement() (*RevokeAdminStatement, error) {
 // parseGrantStatement parses a string and returns a grant statement.
 // This function assumes the GRANT token has already been consumed.
 func (p *Parser) parseGrantStatement() (Statement, error) {
+	// Check for the grant of a role.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		return p.parseGrantRoleStatement()
+	}
+	p.Unscan()
+
 	// Parse the privilege to be granted.
 	priv, err := p.parsePrivilege()
 	if err != nil {
@@ -656,12 +818,12 @@ func (p *Parser) parseGrantStatement() (Statement, error) {
 func (p *Parser) parseGrantOnStatement() (*GrantStatement, error) {
 	stmt := &GrantStatement{}
 
-	// Parse the name of the database.
-	lit, err := p.ParseIdent()
-	if err != nil {
+	// Parse the name of the database, the optional metric and the optional
+	// condition on the series.
+	var err error
+	if stmt.On, stmt.Metric, stmt.Condition, err = p.parsePrivilegeTarget(); err != nil {
 		return nil, err
 	}
-	stmt.On = lit
 
 	// Parse TO clause.
 	tok, pos, lit := p.ScanIgnoreWhitespace()
@@ -671,16 +833,94 @@ func (p *Parser) parseGrantOnStatement() (*GrantStatement, error) {
 		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
 	}
 
-	// Parse the name of the user.
-	lit, err = p.ParseIdent()
+	// Parse the name of the user, or of the role.
+	if stmt.Role, err = p.parseRoleGrantee(stmt.Metric, stmt.Condition); err != nil {
+		return nil, err
+	} else if stmt.Role != "" {
+		return stmt, nil
+	}
+	if stmt.User, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// parseRoleGrantee parses the name of the role a privilege is granted to or
+// revoked from, or returns an empty name if the ROLE token is missing. Roles
+// are only granted privileges on whole databases.
+func (p *Parser) parseRoleGrantee(metric string, cond Expr) (string, error) {
+	tok, pos, _ := p.ScanIgnoreWhitespace()
+	if tok != ROLE {
+		p.Unscan()
+		return "", nil
+	}
+
+	if metric != "" || cond != nil {
+		return "", &ParseError{Message: "privileges on series cannot be granted to roles", Pos: pos}
+	}
+	return p.ParseIdent()
+}
+
+// parseGrantRoleStatement parses a string and returns a grant role statement.
+// This function assumes the GRANT ROLE tokens have already been consumed.
+func (p *Parser) parseGrantRoleStatement() (*GrantRoleStatement, error) {
+	stmt := &GrantRoleStatement{}
+
+	// Parse the name of the role.
+	var err error
+	if stmt.Name, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	// Parse TO clause.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+
+	// Parse the name of the user, or of the role inheriting the role.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		stmt.Role, err = p.ParseIdent()
+	} else {
+		p.Unscan()
+		stmt.User, err = p.ParseIdent()
+	}
 	if err != nil {
 		return nil, err
 	}
-	stmt.User = lit
 
 	return stmt, nil
 }
 
+// parsePrivilegeTarget parses the target of a granted or revoked privilege,
+// which is a database, an optional metric of the database and an optional
+// condition on the tags of the series, e.g. db.cpu WHERE tenant = 'a'.
+func (p *Parser) parsePrivilegeTarget() (database, metric string, cond Expr, err error) {
+	// Parse the name of the database.
+	if database, err = p.ParseIdent(); err != nil {
+		return "", "", nil, err
+	}
+
+	// Parse the optional name of the metric.
+	if tok, _, _ := p.Scan(); tok == DOT {
+		if metric, err = p.ParseIdent(); err != nil {
+			return "", "", nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse the optional condition.
+	if cond, err = p.parseCondition(); err != nil {
+		return "", "", nil, err
+	} else if cond != nil {
+		if err := ValidateSeriesCondition(cond); err != nil {
+			return "", "", nil, err
+		}
+	}
+	return database, metric, cond, nil
+}
+
 // parseGrantAdminStatement parses a string and returns a grant admin statement.
 // This function assumes the ALL [PRVILEGES] TO tokens have already been consumed.
 func (p *Parser) parseGrantAdminStatement() (*GrantAdminStatement, error) {
@@ -1622,6 +1862,38 @@ func (p *Parser) parseShowContinuousQueriesStatement() (*ShowContinuousQueriesSt
 	return &ShowContinuousQueriesStatement{}, nil
 }
 
+// parseShowGrantsForStatement parses a string and returns a ShowGrantsForUserStatement,
+// or a ShowGrantsForRoleStatement. This function assumes the "SHOW GRANTS FOR" tokens
+// have already been consumed.
+func (p *Parser) parseShowGrantsForStatement() (Statement, error) {
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		return p.parseShowGrantsForRoleStatement()
+	}
+	p.Unscan()
+	return p.parseGrantsForUserStatement()
+}
+
+// parseShowGrantsForRoleStatement parses a string and returns a ShowGrantsForRoleStatement.
+// This function assumes the "SHOW GRANTS FOR ROLE" tokens have already been consumed.
+func (p *Parser) parseShowGrantsForRoleStatement() (*ShowGrantsForRoleStatement, error) {
+	stmt := &ShowGrantsForRoleStatement{}
+
+	// Parse the name of the role to be displayed.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = lit
+
+	return stmt, nil
+}
+
+// parseShowRolesStatement parses a string and returns a ShowRolesStatement.
+// This function assumes the "SHOW ROLES" tokens have already been consumed.
+func (p *Parser) parseShowRolesStatement() (*ShowRolesStatement, error) {
+	return &ShowRolesStatement{}, nil
+}
+
 // parseGrantsForUserStatement parses a string and returns a ShowGrantsForUserStatement.
 // This function assumes the "SHOW GRANTS" tokens have already been consumed.
 func (p *Parser) parseGrantsForUserStatement() (*ShowGrantsForUserStatement, error) {
@@ -1922,6 +2194,36 @@ func (p *Parser) parseDropUserStatement() (*DropUserStatement, error) {
 	return stmt, nil
 }
 
+// parseCreateRoleStatement parses a string and returns a CreateRoleStatement.
+// This function assumes the "CREATE ROLE" tokens have already been consumed.
+func (p *Parser) parseCreateRoleStatement() (*CreateRoleStatement, error) {
+	stmt := &CreateRoleStatement{}
+
+	// Parse the name of the role to be created.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = lit
+
+	return stmt, nil
+}
+
+// parseDropRoleStatement parses a string and returns a DropRoleStatement.
+// This function assumes the "DROP ROLE" tokens have already been consumed.
+func (p *Parser) parseDropRoleStatement() (*DropRoleStatement, error) {
+	stmt := &DropRoleStatement{}
+
+	// Parse the name of the role to be dropped.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = lit
+
+	return stmt, nil
+}
+
 // parseExplainStatement parses a string and return an ExplainStatement.
 // This function assumes the EXPLAIN token has already been consumed.
 func (p *Parser) parseExplainStatement() (*ExplainStatement, error) {
@@ -2012,6 +2314,83 @@ func (p *Parser) parseDropContinuousQueryStatement() (*DropContinuousQueryStatem
 	return stmt, nil
 }
 
+// parseBackfillContinuousQueryStatement parses a string and returns a BackfillContinuousQueryStatement.
+// This function assumes the "BACKFILL CONTINUOUS QUERY" tokens have already been consumed.
+func (p *Parser) parseBackfillContinuousQueryStatement() (*BackfillContinuousQueryStatement, error) {
+	stmt := &BackfillContinuousQueryStatement{}
+
+	// Read the name and the database of the query.
+	var err error
+	if stmt.Name, stmt.Database, err = p.parseContinuousQueryNameOn(); err != nil {
+		return nil, err
+	}
+
+	// Read the time range.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FROM {
+		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
+	}
+	if stmt.StartTime, err = p.parseTimeString(); err != nil {
+		return nil, err
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+	if stmt.EndTime, err = p.parseTimeString(); err != nil {
+		return nil, err
+	}
+
+	if !stmt.EndTime.After(stmt.StartTime) {
+		return nil, errors.New("backfill end time must be after the start time")
+	}
+	return stmt, nil
+}
+
+// parseRunContinuousQueryStatement parses a string and returns a RunContinuousQueryStatement.
+// This function assumes the "RUN CONTINUOUS QUERY" tokens have already been consumed.
+func (p *Parser) parseRunContinuousQueryStatement() (*RunContinuousQueryStatement, error) {
+	stmt := &RunContinuousQueryStatement{}
+
+	var err error
+	if stmt.Name, stmt.Database, err = p.parseContinuousQueryNameOn(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseContinuousQueryNameOn parses the name of a continuous query followed
+// by "ON" and the name of its database.
+func (p *Parser) parseContinuousQueryNameOn() (name, database string, err error) {
+	if name, err = p.ParseIdent(); err != nil {
+		return "", "", err
+	}
+
+	// Expect an "ON" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return "", "", newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	if database, err = p.ParseIdent(); err != nil {
+		return "", "", err
+	}
+	return name, database, nil
+}
+
+// parseTimeString parses a string literal holding a time, such as
+// '2020-01-01T00:00:00Z' or '2020-01-01 00:00:00'.
+func (p *Parser) parseTimeString() (time.Time, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != STRING {
+		return time.Time{}, newParseError(tokstr(tok, lit), []string{"string"}, pos)
+	}
+
+	t, err := (&StringLiteral{Val: lit}).ToTimeLiteral(time.UTC)
+	if err != nil {
+		return time.Time{}, &ParseError{Message: "unable to parse time " + QuoteString(lit), Pos: pos}
+	}
+	return t.Val, nil
+}
+
 // parseFields parses a list of one or more fields.
 func (p *Parser) parseFields() (Fields, error) {
 	var fields Fields
@@ -2123,6 +2502,13 @@ func (p *Parser) parseSources(subqueries bool) (Sources, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		// Metrics may be joined in the queries allowing subqueries.
+		if m, ok := s.(*Metric); ok && subqueries {
+			if s, err = p.parseJoin(m); err != nil {
+				return nil, err
+			}
+		}
 		sources = append(sources, s)
 
 		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != COMMA {
@@ -2217,6 +2603,59 @@ func (p *Parser) parseSource(subqueries bool) (Source, error) {
 	return m, nil
 }
 
+// parseJoin parses the join of a metric with another one, if it exists.
+func (p *Parser) parseJoin(left *Metric) (Source, error) {
+	join := &Join{Type: InnerJoin, Left: left}
+
+	// Parse the type of the join, an inner join by default.
+	tok, pos, _ := p.ScanIgnoreWhitespace()
+	switch tok {
+	case INNER, LEFT:
+		if tok == LEFT {
+			join.Type = LeftJoin
+		}
+		if err := p.parseTokens([]Token{JOIN}); err != nil {
+			return nil, err
+		}
+	case JOIN:
+	default:
+		p.Unscan()
+		return left, nil
+	}
+
+	if left.Regex != nil {
+		return nil, &ParseError{Message: fmt.Sprintf("cannot join regex metric %s", left), Pos: pos}
+	}
+
+	// Parse the joined metric.
+	_, pos, _ = p.ScanIgnoreWhitespace()
+	p.Unscan()
+	right, err := p.parseSource(false)
+	if err != nil {
+		return nil, err
+	}
+	join.Right = right.(*Metric)
+	if join.Right.Regex != nil {
+		return nil, &ParseError{Message: fmt.Sprintf("cannot join regex metric %s", join.Right), Pos: pos}
+	} else if join.Right.Name == left.Name {
+		return nil, &ParseError{Message: fmt.Sprintf("cannot join metric %s with itself", QuoteIdent(left.Name)), Pos: pos}
+	}
+
+	// Parse the condition of the join.
+	if err := p.parseTokens([]Token{ON}); err != nil {
+		return nil, err
+	}
+	_, pos, _ = p.ScanIgnoreWhitespace()
+	p.Unscan()
+	if join.Condition, err = p.ParseExpr(); err != nil {
+		return nil, err
+	}
+	if _, _, err := join.Keys(); err != nil {
+		return nil, &ParseError{Message: err.Error(), Pos: pos}
+	}
+	return join, nil
+}
+
 // parseCondition parses the "WHERE" clause of the query, if it exists.
 func (p *Parser) parseCondition() (Expr, error) {
 	// Check if the WHERE token exists.
@@ -1520,6 +1520,41 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SELECT statement with a join
+		{
+			s: `SELECT mean(cpu.usage) FROM cpu LEFT JOIN host_info ON cpu.host = host_info.host GROUP BY time(1h)`,
+			stmt: &cnosql.SelectStatement{
+				Fields: []*cnosql.Field{{
+					Expr: &cnosql.Call{
+						Name: "mean",
+						Args: []cnosql.Expr{
+							&cnosql.VarRef{Val: "cpu.usage"},
+						},
+					},
+				}},
+				Dimensions: []*cnosql.Dimension{{
+					Expr: &cnosql.Call{
+						Name: "time",
+						Args: []cnosql.Expr{
+							&cnosql.DurationLiteral{Val: time.Hour},
+						},
+					},
+				}},
+				Sources: []cnosql.Source{
+					&cnosql.Join{
+						Type:  cnosql.LeftJoin,
+						Left:  &cnosql.Metric{Name: "cpu"},
+						Right: &cnosql.Metric{Name: "host_info"},
+						Condition: &cnosql.BinaryExpr{
+							Op:  cnosql.EQ,
+							LHS: &cnosql.VarRef{Val: "cpu.host"},
+							RHS: &cnosql.VarRef{Val: "host_info.host"},
+						},
+					},
+				},
+			},
+		},
+
 		{
 			s: `SELECT sum(mean) FROM (SELECT mean(value) FROM cpu GROUP BY time(1h)) WHERE time >= now() - 1d`,
 			stmt: &cnosql.SelectStatement{
@@ -2061,6 +2096,15 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// KILL QUERY 4 ON 2
+		{
+			s: `KILL QUERY 4 ON 2`,
+			stmt: &cnosql.KillQueryStatement{
+				QueryID: 4,
+				NodeID:  2,
+			},
+		},
+
 		// SHOW TTLS
 		{
 			s:    `SHOW TTLS`,
@@ -3089,12 +3133,64 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SET QUOTA FOR DATABASE
+		{
+			s: `SET QUOTA FOR DATABASE db0 WRITE POINTS 1000 WRITE BYTES 100000 QUERIES 5 SELECT SERIES 10000`,
+			stmt: &cnosql.SetQuotaStatement{
+				Database:     "db0",
+				WritePoints:  int64ptr(1000),
+				WriteBytes:   int64ptr(100000),
+				Queries:      intptr(5),
+				SelectSeries: intptr(10000),
+			},
+		},
+
+		// SET QUOTA FOR USER
+		{
+			s: `SET QUOTA FOR USER bob QUERIES 0`,
+			stmt: &cnosql.SetQuotaStatement{
+				User:    "bob",
+				Queries: intptr(0),
+			},
+		},
+
+		// DROP QUOTA
+		{
+			s: `DROP QUOTA FOR USER bob`,
+			stmt: &cnosql.DropQuotaStatement{
+				User: "bob",
+			},
+		},
+
+		// SHOW QUOTAS
+		{
+			s:    `SHOW QUOTAS`,
+			stmt: &cnosql.ShowQuotasStatement{},
+		},
+
 		// DROP CONTINUOUS QUERY statement
 		{
 			s:    `DROP CONTINUOUS QUERY myquery ON foo`,
 			stmt: &cnosql.DropContinuousQueryStatement{Name: "myquery", Database: "foo"},
 		},
 
+		// BACKFILL CONTINUOUS QUERY statement
+		{
+			s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM '2020-01-01T00:00:00Z' TO '2020-01-02 00:00:00'`,
+			stmt: &cnosql.BackfillContinuousQueryStatement{
+				Name:      "myquery",
+				Database:  "foo",
+				StartTime: mustParseTime("2020-01-01T00:00:00Z"),
+				EndTime:   mustParseTime("2020-01-02T00:00:00Z"),
+			},
+		},
+
+		// RUN CONTINUOUS QUERY statement
+		{
+			s:    `RUN CONTINUOUS QUERY myquery ON foo`,
+			stmt: &cnosql.RunContinuousQueryStatement{Name: "myquery", Database: "foo"},
+		},
+
 		// DROP DATABASE statement
 		{
 			s: `DROP DATABASE testdb`,
@@ -3164,6 +3260,70 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// GRANT READ on a metric
+		{
+			s: `GRANT READ ON testdb.cpu TO jdoe`,
+			stmt: &cnosql.GrantStatement{
+				Privilege: cnosql.ReadPrivilege,
+				On:        "testdb",
+				Metric:    "cpu",
+				User:      "jdoe",
+			},
+		},
+
+		// GRANT READ on the series of a metric
+		{
+			s: `GRANT READ ON testdb.cpu WHERE tenant = 'a' TO jdoe`,
+			stmt: &cnosql.GrantStatement{
+				Privilege: cnosql.ReadPrivilege,
+				On:        "testdb",
+				Metric:    "cpu",
+				Condition: &cnosql.BinaryExpr{
+					Op:  cnosql.EQ,
+					LHS: &cnosql.VarRef{Val: "tenant"},
+					RHS: &cnosql.StringLiteral{Val: "a"},
+				},
+				User: "jdoe",
+			},
+		},
+
+		// GRANT WRITE on the series of a database
+		{
+			s: `GRANT WRITE ON testdb WHERE tenant =~ /^a/ TO jdoe`,
+			stmt: &cnosql.GrantStatement{
+				Privilege: cnosql.WritePrivilege,
+				On:        "testdb",
+				Condition: &cnosql.BinaryExpr{
+					Op:  cnosql.EQREGEX,
+					LHS: &cnosql.VarRef{Val: "tenant"},
+					RHS: &cnosql.RegexLiteral{Val: regexp.MustCompile(`^a`)},
+				},
+				User: "jdoe",
+			},
+		},
+
+		// GRANT READ to a role
+		{
+			s: `GRANT READ ON testdb TO ROLE readers`,
+			stmt: &cnosql.GrantStatement{
+				Privilege: cnosql.ReadPrivilege,
+				On:        "testdb",
+				Role:      "readers",
+			},
+		},
+
+		// GRANT ROLE to a user
+		{
+			s:    `GRANT ROLE readers TO jdoe`,
+			stmt: &cnosql.GrantRoleStatement{Name: "readers", User: "jdoe"},
+		},
+
+		// GRANT ROLE to a role
+		{
+			s:    `GRANT ROLE readers TO ROLE writers`,
+			stmt: &cnosql.GrantRoleStatement{Name: "readers", Role: "writers"},
+		},
+
 		// GRANT ALL admin privilege
 		{
 			s: `GRANT ALL TO jdoe`,
@@ -3220,6 +3380,68 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// REVOKE READ on the series of a metric
+		{
+			s: `REVOKE READ ON testdb.cpu WHERE tenant = 'a' FROM jdoe`,
+			stmt: &cnosql.RevokeStatement{
+				Privilege: cnosql.ReadPrivilege,
+				On:        "testdb",
+				Metric:    "cpu",
+				Condition: &cnosql.BinaryExpr{
+					Op:  cnosql.EQ,
+					LHS: &cnosql.VarRef{Val: "tenant"},
+					RHS: &cnosql.StringLiteral{Val: "a"},
+				},
+				User: "jdoe",
+			},
+		},
+
+		// REVOKE WRITE from a role
+		{
+			s: `REVOKE WRITE ON testdb FROM ROLE writers`,
+			stmt: &cnosql.RevokeStatement{
+				Privilege: cnosql.WritePrivilege,
+				On:        "testdb",
+				Role:      "writers",
+			},
+		},
+
+		// REVOKE ROLE from a user
+		{
+			s:    `REVOKE ROLE readers FROM jdoe`,
+			stmt: &cnosql.RevokeRoleStatement{Name: "readers", User: "jdoe"},
+		},
+
+		// REVOKE ROLE from a role
+		{
+			s:    `REVOKE ROLE readers FROM ROLE writers`,
+			stmt: &cnosql.RevokeRoleStatement{Name: "readers", Role: "writers"},
+		},
+
+		// CREATE ROLE
+		{
+			s:    `CREATE ROLE readers`,
+			stmt: &cnosql.CreateRoleStatement{Name: "readers"},
+		},
+
+		// DROP ROLE
+		{
+			s:    `DROP ROLE readers`,
+			stmt: &cnosql.DropRoleStatement{Name: "readers"},
+		},
+
+		// SHOW ROLES
+		{
+			s:    `SHOW ROLES`,
+			stmt: &cnosql.ShowRolesStatement{},
+		},
+
+		// SHOW GRANTS FOR ROLE
+		{
+			s:    `SHOW GRANTS FOR ROLE readers`,
+			stmt: &cnosql.ShowGrantsForRoleStatement{Name: "readers"},
+		},
+
 		// REVOKE ALL admin privilege
 		{
 			s: `REVOKE ALL FROM jdoe`,
@@ -3406,6 +3628,17 @@ func TestParser_ParseStatement(t *testing.T) {
 				Mode:         "ANY",
 			},
 		},
+		{
+			s: `CREATE SUBSCRIPTION "name" ON "db"."ttl" DESTINATIONS ALL 'http://host1:8086' DURABLE`,
+			stmt: &cnosql.CreateSubscriptionStatement{
+				Name:         "name",
+				Database:     "db",
+				TimeToLive:   "ttl",
+				Destinations: []string{"http://host1:8086"},
+				Mode:         "ALL",
+				Durable:      true,
+			},
+		},
 
 		// DROP SUBSCRIPTION
 		{
@@ -3424,9 +3657,9 @@ func TestParser_ParseStatement(t *testing.T) {
 		},
 
 		// Errors
-		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL, BACKFILL, RUN at line 1, char 1`},
 		{s: `SELECT`, err: `found EOF, expected identifier, string, number, bool at line 1, char 8`},
-		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL, BACKFILL, RUN at line 1, char 1`},
 		{s: `SELECT field1 X`, err: `found X, expected FROM at line 1, char 15`},
 		{s: `SELECT field1 FROM "series" WHERE X +;`, err: `found ;, expected identifier, string, number, bool at line 1, char 38`},
 		{s: `SELECT field1 FROM myseries GROUP`, err: `found EOF, expected BY at line 1, char 35`},
@@ -3440,6 +3673,11 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `SELECT field1 FROM myseries ORDER BY 1`, err: `found 1, expected identifier, ASC, DESC at line 1, char 38`},
 		{s: `SELECT field1 FROM myseries ORDER BY time ASC,`, err: `found EOF, expected identifier at line 1, char 47`},
 		{s: `SELECT field1 FROM myseries ORDER BY time, field1`, err: `only ORDER BY time supported at this time`},
+		{s: `SELECT value FROM cpu INNER host_info`, err: `found host_info, expected JOIN at line 1, char 29`},
+		{s: `SELECT value FROM cpu JOIN host_info`, err: `found EOF, expected ON at line 1, char 38`},
+		{s: `SELECT value FROM /cpu/ JOIN host_info ON cpu.host = host_info.host`, err: `cannot join regex metric /cpu/ at line 1, char 25`},
+		{s: `SELECT value FROM cpu JOIN cpu ON cpu.host = cpu.host`, err: `cannot join metric cpu with itself at line 1, char 28`},
+		{s: `SELECT value FROM cpu JOIN host_info ON cpu.host = 'a'`, err: `invalid join condition "cpu.host" = 'a': expected equalities between tags of cpu and host_info at line 1, char 41`},
 		{s: `SELECT field1 AS`, err: `found EOF, expected identifier at line 1, char 18`},
 		{s: `SELECT field1 FROM 12`, err: `found 12, expected identifier at line 1, char 20`},
 		{s: `SELECT 1000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000 FROM myseries`, err: `unable to parse integer at line 1, char 8`},
@@ -3476,12 +3714,16 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `DROP CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 23`},
 		{s: `DROP CONTINUOUS QUERY myquery`, err: `found EOF, expected ON at line 1, char 31`},
 		{s: `DROP CONTINUOUS QUERY myquery ON`, err: `found EOF, expected identifier at line 1, char 34`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo`, err: `found EOF, expected FROM at line 1, char 42`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM 'x' TO '2020-01-01T00:00:00Z'`, err: `unable to parse time 'x' at line 1, char 46`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM '2020-01-02T00:00:00Z' TO '2020-01-01T00:00:00Z'`, err: `backfill end time must be after the start time`},
+		{s: `RUN CONTINUOUS QUERY myquery`, err: `found EOF, expected ON at line 1, char 30`},
 		{s: `CREATE CONTINUOUS`, err: `found EOF, expected QUERY at line 1, char 19`},
 		{s: `CREATE CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 25`},
 		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
 		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE EVERY 10s FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(5s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
-		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, METRIC, SERIES, SHARD, SUBSCRIPTION, TTL, USER at line 1, char 6`},
-		{s: `CREATE FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, USER, SUBSCRIPTION, TTL at line 1, char 8`},
+		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, METRIC, SERIES, SHARD, SUBSCRIPTION, TTL, USER, ROLE, QUOTA at line 1, char 6`},
+		{s: `CREATE FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, USER, ROLE, SUBSCRIPTION, TTL at line 1, char 8`},
 		{s: `CREATE DATABASE`, err: `found EOF, expected identifier at line 1, char 17`},
 		{s: `CREATE DATABASE "testdb" WITH`, err: `found EOF, expected DURATION, NAME, REPLICATION, SHARD at line 1, char 31`},
 		{s: `CREATE DATABASE "testdb" WITH DURATION`, err: `found EOF, expected duration at line 1, char 40`},
@@ -3520,6 +3762,15 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `GRANT READ ON testdb`, err: `found EOF, expected TO at line 1, char 22`},
 		{s: `GRANT READ ON testdb TO`, err: `found EOF, expected identifier at line 1, char 25`},
 		{s: `GRANT READ TO`, err: `found TO, expected ON at line 1, char 12`},
+		{s: `GRANT READ ON testdb.cpu TO ROLE readers`, err: `privileges on series cannot be granted to roles at line 1, char 29`},
+		{s: `GRANT ROLE readers`, err: `found EOF, expected TO at line 1, char 20`},
+		{s: `GRANT ROLE readers TO ROLE`, err: `found EOF, expected identifier at line 1, char 28`},
+		{s: `REVOKE ROLE readers TO jdoe`, err: `found TO, expected FROM at line 1, char 21`},
+		{s: `CREATE ROLE`, err: `found EOF, expected identifier at line 1, char 13`},
+		{s: `GRANT READ ON testdb.`, err: `found EOF, expected identifier at line 1, char 22`},
+		{s: `GRANT READ ON testdb.cpu WHERE value > 1 TO jdoe`, err: `invalid series condition: value > 1: unsupported operator >`},
+		{s: `GRANT READ ON testdb.cpu WHERE time = 'a' TO jdoe`, err: `invalid series condition: time = 'a': time is not a tag`},
+		{s: `GRANT READ ON testdb.cpu WHERE tenant = 1 TO jdoe`, err: `invalid series condition: tenant = 1: tags may only be compared with strings`},
 		{s: `GRANT WRITE`, err: `found EOF, expected ON at line 1, char 13`},
 		{s: `GRANT WRITE FROM`, err: `found FROM, expected ON at line 1, char 13`},
 		{s: `GRANT WRITE ON`, err: `found EOF, expected identifier at line 1, char 16`},
@@ -3597,14 +3848,18 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `ALTER TTL ttl1 ON testdb REPLICATION 1 REPLICATION 2`, err: `found duplicate REPLICATION option at line 1, char 56`},
 		{s: `ALTER TTL ttl1 ON testdb DURATION 15251w`, err: `overflowed duration 15251w: choose a smaller duration or INF at line 1, char 51`},
 		{s: `ALTER TTL ttl1 ON testdb DURATION INF SHARD DURATION INF`, err: `invalid duration INF for shard duration at line 1, char 70`},
-		{s: `SET`, err: `found EOF, expected PASSWORD at line 1, char 5`},
+		{s: `SET`, err: `found EOF, expected PASSWORD, QUOTA at line 1, char 5`},
 		{s: `SET PASSWORD`, err: `found EOF, expected FOR at line 1, char 14`},
 		{s: `SET PASSWORD something`, err: `found something, expected FOR at line 1, char 14`},
 		{s: `SET PASSWORD FOR`, err: `found EOF, expected identifier at line 1, char 18`},
 		{s: `SET PASSWORD FOR dejan`, err: `found EOF, expected = at line 1, char 24`},
 		{s: `SET PASSWORD FOR dejan =`, err: `found EOF, expected string at line 1, char 25`},
 		{s: `SET PASSWORD FOR dejan = bla`, err: `found bla, expected string at line 1, char 26`},
-		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `SET QUOTA FOR db0`, err: `found db0, expected DATABASE, USER at line 1, char 15`},
+		{s: `SET QUOTA FOR DATABASE db0`, err: `found EOF, expected WRITE, QUERIES, SELECT at line 1, char 28`},
+		{s: `SET QUOTA FOR DATABASE db0 WRITE ROWS 1`, err: `found ROWS, expected POINTS, BYTES at line 1, char 34`},
+		{s: `SET QUOTA FOR DATABASE db0 QUERIES -1`, err: `found -, expected integer at line 1, char 36`},
+		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL, BACKFILL, RUN at line 1, char 1`},
 		{s: `SELECT * FROM cpu WHERE "tagkey" = $$`, err: `empty bound parameter`},
 
 		// Create a database with a bound parameter.
@@ -4380,3 +4635,7 @@ func duration(v time.Duration) *time.Duration {
 func intptr(v int) *int {
 	return &v
 }
+
+func int64ptr(v int64) *int64 {
+	return &v
+}
@@ -144,12 +144,16 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `PRIVILEGES`, tok: cnosql.PRIVILEGES},
 		{s: `QUERIES`, tok: cnosql.QUERIES},
 		{s: `QUERY`, tok: cnosql.QUERY},
+		{s: `QUOTA`, tok: cnosql.QUOTA},
+		{s: `QUOTAS`, tok: cnosql.QUOTAS},
 		{s: `READ`, tok: cnosql.READ},
 		{s: `REGION`, tok: cnosql.REGION},
 		{s: `REGIONS`, tok: cnosql.REGIONS},
 		{s: `REPLICATION`, tok: cnosql.REPLICATION},
 		{s: `RESAMPLE`, tok: cnosql.RESAMPLE},
 		{s: `REVOKE`, tok: cnosql.REVOKE},
+		{s: `ROLE`, tok: cnosql.ROLE},
+		{s: `ROLES`, tok: cnosql.ROLES},
 		{s: `SELECT`, tok: cnosql.SELECT},
 		{s: `SERIES`, tok: cnosql.SERIES},
 		{s: `TAG`, tok: cnosql.TAG},
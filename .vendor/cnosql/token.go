@@ -71,6 +71,7 @@ const (
 	ANY
 	AS
 	ASC
+	BACKFILL
 	BEGIN
 	BY
 	CARDINALITY
@@ -85,6 +86,7 @@ const (
 	DIAGNOSTICS
 	DISTINCT
 	DROP
+	DURABLE
 	DURATION
 	END
 	EVERY
@@ -98,11 +100,14 @@ const (
 	GROUP
 	IN
 	INF
+	INNER
 	INSERT
 	INTO
+	JOIN
 	KEY
 	KEYS
 	KILL
+	LEFT
 	LIMIT
 	METRIC
 	METRICS
@@ -114,12 +119,17 @@ const (
 	PRIVILEGES
 	QUERIES
 	QUERY
+	QUOTA
+	QUOTAS
 	READ
 	REGION
 	REGIONS
 	REPLICATION
 	RESAMPLE
 	REVOKE
+	ROLE
+	ROLES
+	RUN
 	SELECT
 	SERIES
 	SET
@@ -194,6 +204,7 @@ var tokens = [...]string{
 	ANY:           "ANY",
 	AS:            "AS",
 	ASC:           "ASC",
+	BACKFILL:      "BACKFILL",
 	BEGIN:         "BEGIN",
 	BY:            "BY",
 	CARDINALITY:   "CARDINALITY",
@@ -208,6 +219,7 @@ var tokens = [...]string{
 	DIAGNOSTICS:   "DIAGNOSTICS",
 	DISTINCT:      "DISTINCT",
 	DROP:          "DROP",
+	DURABLE:       "DURABLE",
 	DURATION:      "DURATION",
 	END:           "END",
 	EVERY:         "EVERY",
@@ -221,11 +233,14 @@ var tokens = [...]string{
 	GROUP:         "GROUP",
 	IN:            "IN",
 	INF:           "INF",
+	INNER:         "INNER",
 	INSERT:        "INSERT",
 	INTO:          "INTO",
+	JOIN:          "JOIN",
 	KEY:           "KEY",
 	KEYS:          "KEYS",
 	KILL:          "KILL",
+	LEFT:          "LEFT",
 	LIMIT:         "LIMIT",
 	METRIC:        "METRIC",
 	METRICS:       "METRICS",
@@ -237,12 +252,17 @@ var tokens = [...]string{
 	PRIVILEGES:    "PRIVILEGES",
 	QUERIES:       "QUERIES",
 	QUERY:         "QUERY",
+	QUOTA:         "QUOTA",
+	QUOTAS:        "QUOTAS",
 	READ:          "READ",
 	REGION:        "REGION",
 	REGIONS:       "REGIONS",
 	REPLICATION:   "REPLICATION",
 	RESAMPLE:      "RESAMPLE",
 	REVOKE:        "REVOKE",
+	ROLE:          "ROLE",
+	ROLES:         "ROLES",
+	RUN:           "RUN",
 	SELECT:        "SELECT",
 	SERIES:        "SERIES",
 	SET:           "SET",
@@ -188,11 +188,45 @@ func (c *compiledStatement) compile(stmt *cnosql.SelectStatement) error {
 			if err := c.subquery(source.Statement); err != nil {
 				return err
 			}
+		case *cnosql.Join:
+			if err := c.join(stmt, source); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// join validates a join, which must be the only source of the statement so
+// that its variables are resolved against the metrics of the join.
+func (c *compiledStatement) join(stmt *cnosql.SelectStatement, join *cnosql.Join) error {
+	if len(stmt.Sources) > 1 {
+		return errors.New("a join must be the only source of a query")
+	}
+
+	// Every variable must be qualified by one of the metrics, except the
+	// time and the keys of the join.
+	var err error
+	validate := func(n cnosql.Node) {
+		ref, ok := n.(*cnosql.VarRef)
+		if !ok || err != nil || ref.Val == "time" {
+			return
+		}
+		if m, _ := join.Resolve(ref.Val); m == nil && !join.IsKey(ref.Val) {
+			err = fmt.Errorf("%s must be qualified by %s or %s in a join", ref.Val, join.Left.Name, join.Right.Name)
+		}
+	}
+	cnosql.WalkFunc(stmt.Fields, validate)
+	cnosql.WalkFunc(stmt.Dimensions, validate)
+	if err != nil {
+		return err
+	}
+
+	// Each expression of the condition must refer to one of the metrics.
+	_, _, err = joinConditions(join, c.Condition)
+	return err
+}
+
 func (c *compiledStatement) compileFields(stmt *cnosql.SelectStatement) error {
 	valuer := MathValuer{}
 
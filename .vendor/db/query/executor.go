@@ -135,6 +135,9 @@ type ExecutionOptions struct {
 	// The time-to-live the query is running against.
 	TimeToLive string
 
+	// The user running the query. It is empty if authentication is disabled.
+	UserID string
+
 	// Authorizer handles series-level authorization
 	Authorizer FineAuthorizer
 
@@ -150,6 +153,10 @@ type ExecutionOptions struct {
 	// Node to execute on.
 	NodeID uint64
 
+	// AllowPartial returns the results from the available shards, with a
+	// warning, instead of failing when a shard can't be read from any node.
+	AllowPartial bool
+
 	// Quiet suppresses non-essential output from the query executor.
 	Quiet bool
 
@@ -608,6 +608,10 @@ type IteratorOptions struct {
 	// Limits on the creation of iterators.
 	MaxSeriesN int
 
+	// Limits the number of points buffered by the iterators which read
+	// their whole input, such as joins.
+	MaxPointN int
+
 	// If this channel is set and is closed, the iterator should try to exit
 	// and close as soon as possible.
 	InterruptCh <-chan struct{}
@@ -681,6 +685,7 @@ func newIteratorOptionsStmt(stmt *cnosql.SelectStatement, sopt SelectOptions) (o
 	opt.Limit, opt.Offset = stmt.Limit, stmt.Offset
 	opt.SLimit, opt.SOffset = stmt.SLimit, stmt.SOffset
 	opt.MaxSeriesN = sopt.MaxSeriesN
+	opt.MaxPointN = sopt.MaxPointN
 	opt.Authorizer = sopt.Authorizer
 
 	return opt, nil
@@ -690,6 +695,7 @@ func newIteratorOptionsSubstatement(ctx context.Context, stmt *cnosql.SelectStat
 	subOpt, err := newIteratorOptionsStmt(stmt, SelectOptions{
 		Authorizer: opt.Authorizer,
 		MaxSeriesN: opt.MaxSeriesN,
+		MaxPointN:  opt.MaxPointN,
 	})
 	if err != nil {
 		return IteratorOptions{}, err
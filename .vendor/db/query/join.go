@@ -0,0 +1,553 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cnosdatabase/cnosql"
+)
+
+// joinBuilder builds the iterators reading a join. The fields and the tags
+// of both metrics are read by auxiliary iterators which are then joined by
+// a joinIterator.
+type joinBuilder struct {
+	ic   IteratorCreator
+	join *cnosql.Join
+}
+
+// buildAuxIterator constructs an auxiliary Iterator from a join.
+func (b *joinBuilder) buildAuxIterator(ctx context.Context, opt IteratorOptions) (Iterator, error) {
+	return b.buildJoinIterator(ctx, opt.Aux, opt)
+}
+
+// buildVarRefIterator constructs an Iterator driven by a variable of a join.
+func (b *joinBuilder) buildVarRefIterator(ctx context.Context, expr *cnosql.VarRef, opt IteratorOptions) (Iterator, error) {
+	refs := make([]cnosql.VarRef, 0, len(opt.Aux)+1)
+	refs = append(refs, *expr)
+	refs = append(refs, opt.Aux...)
+
+	itr, err := b.buildJoinIterator(ctx, refs, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the values of the joined points to the driver and the auxiliary
+	// fields.
+	driver := FieldMap{Index: 0, Type: expr.Type}
+	fields := make([]IteratorMap, len(opt.Aux))
+	for i, ref := range opt.Aux {
+		fields[i] = FieldMap{Index: i + 1, Type: ref.Type}
+	}
+	return NewIteratorMapper(newJoinCursor(itr, refs), driver, fields, opt), nil
+}
+
+// buildJoinIterator creates the iterators of both metrics of the join and
+// joins them. The values of the joined points are the ones of refs.
+func (b *joinBuilder) buildJoinIterator(ctx context.Context, refs []cnosql.VarRef, opt IteratorOptions) (*joinIterator, error) {
+	leftKeys, rightKeys, err := b.join.Keys()
+	if err != nil {
+		return nil, err
+	}
+	leftCond, rightCond, err := joinConditions(b.join, opt.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	itr := &joinIterator{
+		typ: b.join.Type,
+		opt: opt,
+	}
+	left := &joinSide{metric: b.join.Left, keys: leftKeys, condition: leftCond}
+	right := &joinSide{metric: b.join.Right, keys: rightKeys, condition: rightCond}
+
+	// Resolve the values of the joined points, the unqualified variables
+	// being the keys read from the left metric.
+	itr.columns = make([]joinColumn, len(refs))
+	for i, ref := range refs {
+		m, name := b.join.Resolve(ref.Val)
+		if m == nil {
+			m, name = b.join.Left, ref.Val
+		}
+		if ref.Type == cnosql.Unknown {
+			itr.columns[i] = joinColumn{index: -1}
+			continue
+		}
+
+		side := left
+		if m == b.join.Right {
+			side = right
+		}
+		itr.columns[i] = joinColumn{right: side == right, index: side.aux(cnosql.VarRef{Val: name, Type: ref.Type})}
+	}
+
+	// Resolve the tags of the joined points, which are the dimensions of
+	// the query.
+	itr.dimensions = make([]joinColumn, len(opt.Dimensions))
+	for i, d := range opt.Dimensions {
+		m, name := b.join.Resolve(d)
+		if m == nil {
+			m, name = b.join.Left, d
+		}
+		if m == b.join.Right {
+			itr.dimensions[i] = joinColumn{right: true, tag: name}
+			right.dimensions = append(right.dimensions, name)
+		} else {
+			itr.dimensions[i] = joinColumn{tag: name}
+			left.dimensions = append(left.dimensions, name)
+		}
+	}
+	itr.names = opt.Dimensions
+	itr.leftKeys, itr.rightKeys = leftKeys, rightKeys
+
+	if itr.left.itr, err = left.createIterator(ctx, b.ic, opt); err != nil {
+		return nil, err
+	}
+	if itr.right.itr, err = right.createIterator(ctx, b.ic, opt); err != nil {
+		itr.left.itr.Close()
+		return nil, err
+	}
+	return itr, nil
+}
+
+// joinSide holds what is read from one of the metrics of a join.
+type joinSide struct {
+	metric     *cnosql.Metric
+	keys       []string
+	dimensions []string
+	condition  cnosql.Expr
+	refs       []cnosql.VarRef
+}
+
+// aux returns the index of a variable of the metric in the auxiliary
+// fields read from it.
+func (s *joinSide) aux(ref cnosql.VarRef) int {
+	for i, other := range s.refs {
+		if other == ref {
+			return i
+		}
+	}
+	s.refs = append(s.refs, ref)
+	return len(s.refs) - 1
+}
+
+// createIterator creates the auxiliary iterator reading the metric. Its
+// points are read in time order across all the series of the metric, and
+// their tags are the keys of the join and the ones needed by the dimensions
+// of the query.
+func (s *joinSide) createIterator(ctx context.Context, ic IteratorCreator, opt IteratorOptions) (FloatIterator, error) {
+	refs := s.refs
+	if !hasField(refs) {
+		// The points are the ones of the fields, so read all the fields of
+		// the metric to know where it has points.
+		m, ok := ic.(cnosql.FieldMapper)
+		if !ok {
+			return nil, fmt.Errorf("unable to read the fields of %s", s.metric.Name)
+		}
+		fields, _, err := m.FieldDimensions(s.metric)
+		if err != nil {
+			return nil, err
+		}
+		all := make([]cnosql.VarRef, 0, len(fields))
+		for k, typ := range fields {
+			all = append(all, cnosql.VarRef{Val: k, Type: typ})
+		}
+		sort.Sort(cnosql.VarRefs(all))
+		refs = append(refs[:len(refs):len(refs)], all...)
+	}
+
+	sideOpt := opt
+	sideOpt.Expr = nil
+	sideOpt.Aux = refs
+	sideOpt.Condition = s.condition
+	sideOpt.Interval = Interval{}
+	sideOpt.Ordered = true
+	sideOpt.Dedupe = false
+	sideOpt.Limit, sideOpt.Offset = 0, 0
+	sideOpt.SLimit, sideOpt.SOffset = 0, 0
+	sideOpt.Fill, sideOpt.FillValue = cnosql.NullFill, nil
+
+	sideOpt.Dimensions = nil
+	sideOpt.GroupBy = make(map[string]struct{}, len(s.keys)+len(s.dimensions))
+	for _, names := range [][]string{s.keys, s.dimensions} {
+		for _, name := range names {
+			sideOpt.GroupBy[name] = struct{}{}
+		}
+	}
+
+	input, err := ic.CreateIterator(ctx, s.metric, sideOpt)
+	if err != nil {
+		return nil, err
+	} else if input == nil {
+		return &nilFloatIterator{}, nil
+	}
+
+	itr, ok := input.(FloatIterator)
+	if !ok {
+		input.Close()
+		return nil, fmt.Errorf("unexpected iterator of %s: %T", s.metric.Name, input)
+	}
+	return itr, nil
+}
+
+// hasField returns true if one of the variables is a field.
+func hasField(refs []cnosql.VarRef) bool {
+	for _, ref := range refs {
+		if ref.Type != cnosql.Tag {
+			return true
+		}
+	}
+	return false
+}
+
+// joinColumn locates a value of the joined points in the points of one of
+// the metrics, either an auxiliary field or a tag.
+type joinColumn struct {
+	right bool
+	index int
+	tag   string
+}
+
+// point returns the point of the metric holding the value.
+func (c joinColumn) point(left, right *FloatPoint) *FloatPoint {
+	if c.right {
+		return right
+	}
+	return left
+}
+
+// joinIterator joins the points of two metrics whose key tags are equal,
+// at the same time or, if the query is grouped by time, in the same
+// interval. Both metrics are read in time order, one time or interval at a
+// time, so that only the points of the current one are buffered. The joined
+// points are in time order too, which is the order of their series if the
+// query isn't grouped by tags. Otherwise they are all buffered and sorted by
+// tags. The buffered points are limited by the maximum number of points of
+// the query. The name and the time of the joined points are the ones of the
+// left points.
+type joinIterator struct {
+	left, right joinInput
+	typ         cnosql.JoinType
+	opt         IteratorOptions
+
+	leftKeys, rightKeys []string
+	columns             []joinColumn
+	dimensions          []joinColumn
+	names               []string
+
+	points []FloatPoint
+	done   bool
+}
+
+// joinInput reads the points of a metric of a join, a point being peeked
+// at before it is read.
+type joinInput struct {
+	itr FloatIterator
+	buf *FloatPoint
+}
+
+// peek returns the next point without reading it.
+func (in *joinInput) peek() (*FloatPoint, error) {
+	if in.buf == nil {
+		p, err := in.itr.Next()
+		if err != nil {
+			return nil, err
+		}
+		in.buf = p
+	}
+	return in.buf, nil
+}
+
+// next reads the point returned by peek, which may be reused once another
+// point is peeked at.
+func (in *joinInput) next() *FloatPoint {
+	p := in.buf
+	in.buf = nil
+	return p
+}
+
+// Stats returns stats from both metrics.
+func (itr *joinIterator) Stats() IteratorStats {
+	stats := itr.left.itr.Stats()
+	stats.Add(itr.right.itr.Stats())
+	return stats
+}
+
+// Close closes the iterators of both metrics.
+func (itr *joinIterator) Close() error {
+	itr.right.itr.Close()
+	return itr.left.itr.Close()
+}
+
+// Next returns the next joined point.
+func (itr *joinIterator) Next() (*FloatPoint, error) {
+	for len(itr.points) == 0 {
+		if itr.done {
+			return nil, nil
+		}
+		itr.points = nil
+
+		if len(itr.opt.Dimensions) == 0 {
+			if err := itr.readWindow(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for !itr.done {
+			if err := itr.readWindow(); err != nil {
+				return nil, err
+			}
+		}
+		sort.SliceStable(itr.points, func(i, j int) bool {
+			return itr.points[i].Tags.ID() < itr.points[j].Tags.ID()
+		})
+	}
+
+	p := &itr.points[0]
+	itr.points = itr.points[1:]
+	return p, nil
+}
+
+// readWindow joins the points of both metrics at the time, or in the
+// interval, of the next left point. The joined points are appended to the
+// ones of the iterator.
+func (itr *joinIterator) readWindow() error {
+	p, err := itr.left.peek()
+	if err != nil {
+		return err
+	} else if p == nil {
+		// Left points without a match are the only joined points without
+		// a right point, so the join ends with the left points.
+		itr.done = true
+		return nil
+	}
+	window := itr.window(p)
+
+	var lefts []*FloatPoint
+	for {
+		p, err := itr.left.peek()
+		if err != nil {
+			return err
+		} else if p == nil || itr.window(p) != window {
+			break
+		}
+		lefts = append(lefts, itr.left.next().Clone())
+		if err := itr.checkPointN(len(lefts) + len(itr.points)); err != nil {
+			return err
+		}
+	}
+
+	var rightN int
+	rights := make(map[string][]*FloatPoint)
+	for {
+		p, err := itr.right.peek()
+		if err != nil {
+			return err
+		} else if p == nil {
+			break
+		}
+
+		// Skip the right points of the windows without left points.
+		if t := itr.window(p); t != window {
+			if (itr.opt.Ascending && t > window) || (!itr.opt.Ascending && t < window) {
+				break
+			}
+			itr.right.next()
+			continue
+		}
+
+		p = itr.right.next()
+		key := itr.key(p, itr.rightKeys)
+		rights[key] = append(rights[key], p.Clone())
+		rightN++
+		if err := itr.checkPointN(len(lefts) + rightN + len(itr.points)); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range lefts {
+		matches := rights[itr.key(p, itr.leftKeys)]
+		if len(matches) == 0 && itr.typ == cnosql.LeftJoin {
+			itr.points = append(itr.points, itr.point(p, nil))
+		}
+		for _, right := range matches {
+			itr.points = append(itr.points, itr.point(p, right))
+		}
+		if err := itr.checkPointN(len(itr.points)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPointN returns an error if buffering n points exceeds the maximum
+// number of points of the query.
+func (itr *joinIterator) checkPointN(n int) error {
+	if itr.opt.MaxPointN > 0 && n > itr.opt.MaxPointN {
+		return ErrMaxSelectPointsLimitExceeded(n, itr.opt.MaxPointN)
+	}
+	return nil
+}
+
+// window returns the time of a point, or the start of its interval, which
+// must be equal for the points to join.
+func (itr *joinIterator) window(p *FloatPoint) int64 {
+	if itr.opt.Interval.IsZero() {
+		return p.Time
+	}
+	t, _ := itr.opt.Window(p.Time)
+	return t
+}
+
+// key returns the values of the key tags of a point, which must be equal
+// for the points to join.
+func (itr *joinIterator) key(p *FloatPoint, keys []string) string {
+	var buf strings.Builder
+	for _, k := range keys {
+		buf.WriteString(p.Tags.Value(k))
+		buf.WriteByte(0)
+	}
+	return buf.String()
+}
+
+// point returns the point joining a left point with a right one, which is
+// nil for the left points without a match.
+func (itr *joinIterator) point(left, right *FloatPoint) FloatPoint {
+	aux := make([]interface{}, len(itr.columns))
+	for i, c := range itr.columns {
+		if p := c.point(left, right); p != nil && c.index >= 0 && c.index < len(p.Aux) {
+			aux[i] = p.Aux[c.index]
+		}
+	}
+
+	var tags Tags
+	if len(itr.dimensions) > 0 {
+		m := make(map[string]string, len(itr.dimensions))
+		for i, c := range itr.dimensions {
+			if p := c.point(left, right); p != nil {
+				if v := p.Tags.Value(c.tag); v != "" {
+					m[itr.names[i]] = v
+				}
+			}
+		}
+		tags = NewTags(m)
+	}
+
+	return FloatPoint{
+		Name: left.Name,
+		Tags: tags,
+		Time: left.Time,
+		Aux:  aux,
+	}
+}
+
+// joinCursor reads the points of a join iterator as rows whose values are
+// the auxiliary fields of the points.
+type joinCursor struct {
+	itr     *joinIterator
+	columns []cnosql.VarRef
+	series  Series
+	err     error
+}
+
+func newJoinCursor(itr *joinIterator, columns []cnosql.VarRef) *joinCursor {
+	return &joinCursor{itr: itr, columns: columns}
+}
+
+func (cur *joinCursor) Scan(row *Row) bool {
+	p, err := cur.itr.Next()
+	if err != nil {
+		cur.err = err
+		return false
+	} else if p == nil {
+		return false
+	}
+
+	row.Time = p.Time
+	if p.Name != cur.series.Name || p.Tags.ID() != cur.series.Tags.ID() {
+		cur.series.Name = p.Name
+		cur.series.Tags = p.Tags
+		cur.series.id++
+	}
+	row.Series = cur.series
+	row.Values = p.Aux
+	return true
+}
+
+func (cur *joinCursor) Stats() IteratorStats     { return cur.itr.Stats() }
+func (cur *joinCursor) Err() error               { return cur.err }
+func (cur *joinCursor) Columns() []cnosql.VarRef { return cur.columns }
+func (cur *joinCursor) Close() error             { return cur.itr.Close() }
+
+// joinConditions splits the condition of a query reading a join into the
+// conditions of its left and right metrics, without the qualifiers of the
+// variables. Each expression of the conjunction must only refer to one of
+// the metrics.
+func joinConditions(join *cnosql.Join, cond cnosql.Expr) (left, right cnosql.Expr, err error) {
+	var exprs []cnosql.Expr
+	var split func(expr cnosql.Expr)
+	split = func(expr cnosql.Expr) {
+		switch e := expr.(type) {
+		case *cnosql.ParenExpr:
+			split(e.Expr)
+			return
+		case *cnosql.BinaryExpr:
+			if e.Op == cnosql.AND {
+				split(e.LHS)
+				split(e.RHS)
+				return
+			}
+		}
+		exprs = append(exprs, expr)
+	}
+	if cond != nil {
+		split(cond)
+	}
+
+	for _, expr := range exprs {
+		var m *cnosql.Metric
+		orig := expr
+		expr = cnosql.RewriteExpr(cnosql.CloneExpr(expr), func(e cnosql.Expr) cnosql.Expr {
+			ref, ok := e.(*cnosql.VarRef)
+			if !ok || err != nil {
+				return e
+			}
+
+			mm, name := join.Resolve(ref.Val)
+			if mm == nil {
+				if !join.IsKey(ref.Val) {
+					err = fmt.Errorf("%s must be qualified by %s or %s in a join", ref.Val, join.Left.Name, join.Right.Name)
+					return e
+				}
+				mm, name = join.Left, ref.Val
+			}
+			if m != nil && m != mm {
+				err = fmt.Errorf("condition %s must only refer to one metric of the join", orig)
+				return e
+			}
+			m = mm
+			return &cnosql.VarRef{Val: name, Type: ref.Type}
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if m == join.Right {
+			right = joinAnd(right, expr)
+		} else {
+			left = joinAnd(left, expr)
+		}
+	}
+	return left, right, nil
+}
+
+// joinAnd returns the conjunction of two conditions, the first one being
+// possibly nil.
+func joinAnd(lhs, rhs cnosql.Expr) cnosql.Expr {
+	if lhs == nil {
+		return rhs
+	}
+	return &cnosql.BinaryExpr{Op: cnosql.AND, LHS: lhs, RHS: rhs}
+}
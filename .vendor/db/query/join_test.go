@@ -0,0 +1,262 @@
+package query
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cnosdatabase/cnosql"
+)
+
+// joinPoint is a point of a metric read by a joinRegion.
+type joinPoint struct {
+	time   time.Duration
+	tags   map[string]string
+	fields map[string]interface{}
+}
+
+// joinRegion is a region holding the points of the metrics of a join. Its
+// iterators read the fields and the tags of the points in time order, as
+// the joined metrics are read.
+type joinRegion struct {
+	fields  map[string]map[string]cnosql.DataType
+	points  map[string][]joinPoint
+	created []IteratorOptions
+}
+
+func newJoinRegion() *joinRegion {
+	return &joinRegion{
+		fields: map[string]map[string]cnosql.DataType{
+			"cpu":       {"usage": cnosql.Float},
+			"host_info": {"cores": cnosql.Float},
+		},
+		points: map[string][]joinPoint{
+			"cpu": {
+				{0, map[string]string{"host": "a"}, map[string]interface{}{"usage": 1.0}},
+				{0, map[string]string{"host": "b"}, map[string]interface{}{"usage": 2.0}},
+				{10 * time.Second, map[string]string{"host": "a"}, map[string]interface{}{"usage": 3.0}},
+				{10 * time.Second, map[string]string{"host": "c"}, map[string]interface{}{"usage": 4.0}},
+				{12 * time.Second, map[string]string{"host": "b"}, map[string]interface{}{"usage": 6.0}},
+				{20 * time.Second, map[string]string{"host": "a"}, map[string]interface{}{"usage": 5.0}},
+			},
+			"host_info": {
+				{0, map[string]string{"host": "a"}, map[string]interface{}{"cores": 4.0}},
+				{0, map[string]string{"host": "b"}, map[string]interface{}{"cores": 8.0}},
+				{15 * time.Second, map[string]string{"host": "b"}, map[string]interface{}{"cores": 2.0}},
+				{20 * time.Second, map[string]string{"host": "a"}, map[string]interface{}{"cores": 16.0}},
+				{25 * time.Second, map[string]string{"host": "d"}, map[string]interface{}{"cores": 32.0}},
+			},
+		},
+	}
+}
+
+func (r *joinRegion) MapShards(sources cnosql.Sources, t cnosql.TimeRange, opt SelectOptions) (Region, error) {
+	return r, nil
+}
+
+func (r *joinRegion) CreateIterator(ctx context.Context, m *cnosql.Metric, opt IteratorOptions) (Iterator, error) {
+	r.created = append(r.created, opt)
+
+	var points []FloatPoint
+	for _, p := range r.points[m.Name] {
+		if t := int64(p.time); t < opt.StartTime || t > opt.EndTime {
+			continue
+		}
+
+		aux := make([]interface{}, len(opt.Aux))
+		for i, ref := range opt.Aux {
+			if ref.Type == cnosql.Tag {
+				aux[i] = p.tags[ref.Val]
+			} else {
+				aux[i] = p.fields[ref.Val]
+			}
+		}
+		points = append(points, FloatPoint{Name: m.Name, Tags: NewTags(p.tags), Time: int64(p.time), Aux: aux})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		if opt.Ascending {
+			return points[i].Time < points[j].Time
+		}
+		return points[i].Time > points[j].Time
+	})
+	return &joinSliceIterator{points: points}, nil
+}
+
+func (r *joinRegion) IteratorCost(m *cnosql.Metric, opt IteratorOptions) (IteratorCost, error) {
+	return IteratorCost{}, nil
+}
+
+func (r *joinRegion) FieldDimensions(m *cnosql.Metric) (map[string]cnosql.DataType, map[string]struct{}, error) {
+	return r.fields[m.Name], map[string]struct{}{"host": {}}, nil
+}
+
+func (r *joinRegion) MapType(m *cnosql.Metric, field string) cnosql.DataType {
+	if typ, ok := r.fields[m.Name][field]; ok {
+		return typ
+	} else if field == "host" {
+		return cnosql.Tag
+	}
+	return cnosql.Unknown
+}
+
+func (r *joinRegion) Close() error { return nil }
+
+// joinSliceIterator iterates over a slice of points.
+type joinSliceIterator struct {
+	points []FloatPoint
+}
+
+func (itr *joinSliceIterator) Stats() IteratorStats { return IteratorStats{} }
+func (itr *joinSliceIterator) Close() error         { return nil }
+
+func (itr *joinSliceIterator) Next() (*FloatPoint, error) {
+	if len(itr.points) == 0 {
+		return nil, nil
+	}
+	p := itr.points[0].Clone()
+	itr.points = itr.points[1:]
+	return p, nil
+}
+
+// joinRow is a row read from a query, without its time column.
+type joinRow struct {
+	time   time.Duration
+	tags   map[string]string
+	values []interface{}
+}
+
+// selectJoin runs a query on the region, and returns its rows.
+func selectJoin(t *testing.T, r *joinRegion, s string, opt SelectOptions) ([]joinRow, error) {
+	t.Helper()
+	stmt, err := cnosql.ParseStatement(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cur, err := Select(context.Background(), stmt.(*cnosql.SelectStatement), r, opt)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close()
+
+	var rows []joinRow
+	var row Row
+	for cur.Scan(&row) {
+		tags := row.Series.Tags.KeyValues()
+		if len(tags) == 0 {
+			tags = nil
+		}
+		values := append([]interface{}(nil), row.Values[1:]...)
+		rows = append(rows, joinRow{time: time.Duration(row.Time), tags: tags, values: values})
+	}
+	return rows, cur.Err()
+}
+
+func TestJoin(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		s    string
+		rows []joinRow
+	}{
+		{
+			name: "Inner",
+			s:    `SELECT cpu.usage, host_info.cores FROM cpu JOIN host_info ON cpu.host = host_info.host WHERE time >= 0 AND time < 60s`,
+			rows: []joinRow{
+				{0, nil, []interface{}{1.0, 4.0}},
+				{0, nil, []interface{}{2.0, 8.0}},
+				{20 * time.Second, nil, []interface{}{5.0, 16.0}},
+			},
+		},
+		{
+			name: "Inner_Descending",
+			s:    `SELECT cpu.usage, host_info.cores FROM cpu JOIN host_info ON cpu.host = host_info.host WHERE time >= 0 AND time < 60s ORDER BY time DESC`,
+			rows: []joinRow{
+				{20 * time.Second, nil, []interface{}{5.0, 16.0}},
+				{0, nil, []interface{}{1.0, 4.0}},
+				{0, nil, []interface{}{2.0, 8.0}},
+			},
+		},
+		{
+			name: "Left",
+			s:    `SELECT cpu.usage, host_info.cores FROM cpu LEFT JOIN host_info ON cpu.host = host_info.host WHERE time >= 0 AND time < 60s`,
+			rows: []joinRow{
+				{0, nil, []interface{}{1.0, 4.0}},
+				{0, nil, []interface{}{2.0, 8.0}},
+				{10 * time.Second, nil, []interface{}{3.0, nil}},
+				{10 * time.Second, nil, []interface{}{4.0, nil}},
+				{12 * time.Second, nil, []interface{}{6.0, nil}},
+				{20 * time.Second, nil, []interface{}{5.0, 16.0}},
+			},
+		},
+		{
+			name: "Inner_GroupByTime",
+			s:    `SELECT sum(cpu.usage), max(host_info.cores) FROM cpu JOIN host_info ON cpu.host = host_info.host WHERE time >= 0 AND time < 60s GROUP BY time(10s) fill(none)`,
+			rows: []joinRow{
+				{0, nil, []interface{}{3.0, 8.0}},
+				{10 * time.Second, nil, []interface{}{6.0, 2.0}},
+				{20 * time.Second, nil, []interface{}{5.0, 16.0}},
+			},
+		},
+		{
+			name: "Left_GroupByTime",
+			s:    `SELECT sum(cpu.usage), max(host_info.cores) FROM cpu LEFT JOIN host_info ON cpu.host = host_info.host WHERE time >= 0 AND time < 60s GROUP BY time(10s) fill(none)`,
+			rows: []joinRow{
+				{0, nil, []interface{}{3.0, 8.0}},
+				{10 * time.Second, nil, []interface{}{13.0, 2.0}},
+				{20 * time.Second, nil, []interface{}{5.0, 16.0}},
+			},
+		},
+		{
+			name: "Left_GroupByTimeAndTag",
+			s:    `SELECT sum(cpu.usage), max(host_info.cores) FROM cpu LEFT JOIN host_info ON cpu.host = host_info.host WHERE time >= 0 AND time < 60s GROUP BY time(10s), host fill(none)`,
+			rows: []joinRow{
+				{0, map[string]string{"host": "a"}, []interface{}{1.0, 4.0}},
+				{10 * time.Second, map[string]string{"host": "a"}, []interface{}{3.0, nil}},
+				{20 * time.Second, map[string]string{"host": "a"}, []interface{}{5.0, 16.0}},
+				{0, map[string]string{"host": "b"}, []interface{}{2.0, 8.0}},
+				{10 * time.Second, map[string]string{"host": "b"}, []interface{}{6.0, 2.0}},
+				{10 * time.Second, map[string]string{"host": "c"}, []interface{}{4.0, nil}},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newJoinRegion()
+			rows, err := selectJoin(t, r, tt.s, SelectOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if !reflect.DeepEqual(rows, tt.rows) {
+				t.Fatalf("unexpected rows:\n got %v\n exp %v", rows, tt.rows)
+			}
+
+			// Both metrics are read in time order, without grouping.
+			for _, opt := range r.created {
+				if !opt.Ordered || len(opt.Dimensions) != 0 || !opt.Interval.IsZero() {
+					t.Fatalf("unexpected iterator options: ordered=%v dimensions=%v interval=%v", opt.Ordered, opt.Dimensions, opt.Interval)
+				}
+			}
+		})
+	}
+}
+
+func TestJoin_MaxPointN(t *testing.T) {
+	for _, s := range []string{
+		`SELECT cpu.usage, host_info.cores FROM cpu JOIN host_info ON cpu.host = host_info.host WHERE time >= 0 AND time < 60s`,
+		`SELECT sum(cpu.usage) FROM cpu LEFT JOIN host_info ON cpu.host = host_info.host WHERE time >= 0 AND time < 60s GROUP BY time(10s), host`,
+	} {
+		_, err := selectJoin(t, newJoinRegion(), s, SelectOptions{MaxPointN: 3})
+		if err == nil || !strings.Contains(err.Error(), "max-select-point limit") {
+			t.Fatalf("got error %v, exp the point limit to be exceeded", err)
+		}
+	}
+
+	// The buffered points of a window are within the limit.
+	s := `SELECT cpu.usage, host_info.cores FROM cpu JOIN host_info ON cpu.host = host_info.host WHERE time >= 0 AND time < 60s`
+	if rows, err := selectJoin(t, newJoinRegion(), s, SelectOptions{MaxPointN: 4}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(rows) != 3 {
+		t.Fatalf("got %d rows, exp 3", len(rows))
+	}
+}
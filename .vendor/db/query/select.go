@@ -37,6 +37,10 @@ type SelectOptions struct {
 
 	// Maximum number of buckets for a statement.
 	MaxBucketsN int
+
+	// AllowPartial returns the results from the available shards instead
+	// of failing when a shard can't be read from any node.
+	AllowPartial bool
 }
 
 // ShardMapper retrieves and maps shards into an IteratorCreator that can later be
@@ -188,6 +192,17 @@ func (b *exprIteratorBuilder) buildVarRefIterator(ctx context.Context, expr *cno
 				} else if input != nil {
 					inputs = append(inputs, input)
 				}
+			case *cnosql.Join:
+				join := joinBuilder{
+					ic:   b.ic,
+					join: source,
+				}
+
+				input, err := join.buildVarRefIterator(ctx, expr, b.opt)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input)
 			}
 		}
 		return nil
@@ -583,7 +598,7 @@ func (b *exprIteratorBuilder) callIterator(ctx context.Context, expr *cnosql.Cal
 					return err
 				}
 				inputs = append(inputs, input)
-			case *cnosql.SubQuery:
+			case *cnosql.SubQuery, *cnosql.Join:
 				// Identify the name of the field we are using.
 				arg0 := expr.Args[0].(*cnosql.VarRef)
 
@@ -794,6 +809,17 @@ func buildAuxIterator(ctx context.Context, ic IteratorCreator, sources cnosql.So
 				} else if input != nil {
 					inputs = append(inputs, input)
 				}
+			case *cnosql.Join:
+				b := joinBuilder{
+					ic:   ic,
+					join: source,
+				}
+
+				input, err := b.buildAuxIterator(ctx, opt)
+				if err != nil {
+					return err
+				}
+				inputs = append(inputs, input)
 			}
 		}
 		return nil
@@ -69,6 +69,10 @@ const (
 
 	// DefaultSeriesIDSetCacheSize is the default number of series ID sets to cache in the TSI index.
 	DefaultSeriesIDSetCacheSize = 100
+
+	// DefaultWALArchiveInterval is the default longest time a WAL segment with
+	// writes stays open when WAL archiving is enabled.
+	DefaultWALArchiveInterval = 10 * time.Second
 )
 
 // Config holds the configuration for the tsbd package.
@@ -85,6 +89,15 @@ type Config struct {
 	// disks or when WAL write contention is seen.  A value of 0 fsyncs every write to the WAL.
 	WALFsyncDelay toml.Duration `toml:"wal-fsync-delay"`
 
+	// WALArchiveDir is the directory closed WAL segments are archived into until
+	// they are shipped to a backup target by "cnosdb backup --wal", for
+	// point-in-time restores. Archiving is disabled if it's empty.
+	WALArchiveDir string `toml:"wal-archive-dir"`
+
+	// WALArchiveInterval is the longest time a WAL segment with writes stays open
+	// when archiving is enabled, bounding the writes a point-in-time restore may lose.
+	WALArchiveInterval toml.Duration `toml:"wal-archive-interval"`
+
 	// Enables unicode validation on series keys on write.
 	ValidateKeys bool `toml:"validate-keys"`
 
@@ -145,6 +158,8 @@ func NewConfig() Config {
 
 		QueryLogEnabled: true,
 
+		WALArchiveInterval: toml.Duration(DefaultWALArchiveInterval),
+
 		CacheMaxMemorySize:             toml.Size(DefaultCacheMaxMemorySize),
 		CacheSnapshotMemorySize:        toml.Size(DefaultCacheSnapshotMemorySize),
 		CacheSnapshotWriteColdDuration: toml.Duration(DefaultCacheSnapshotWriteColdDuration),
@@ -172,6 +187,10 @@ func (c *Config) Validate() error {
 		return errors.New("Data.WALDir must be specified")
 	}
 
+	if c.WALArchiveDir != "" && c.WALArchiveInterval < 0 {
+		return errors.New("wal-archive-interval must be non-negative")
+	}
+
 	if c.MaxConcurrentCompactions < 0 {
 		return errors.New("max-concurrent-compactions must be non-negative")
 	}
@@ -211,6 +230,8 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"dir":                                c.Dir,
 		"wal-dir":                            c.WALDir,
 		"wal-fsync-delay":                    c.WALFsyncDelay,
+		"wal-archive-dir":                    c.WALArchiveDir,
+		"wal-archive-interval":               c.WALArchiveInterval,
 		"cache-max-memory-size":              c.CacheMaxMemorySize,
 		"cache-snapshot-memory-size":         c.CacheSnapshotMemorySize,
 		"cache-snapshot-write-cold-duration": c.CacheSnapshotWriteColdDuration,
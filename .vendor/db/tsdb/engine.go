@@ -43,6 +43,7 @@ type Engine interface {
 
 	CreateSnapshot() (string, error)
 	Backup(w io.Writer, basePath string, since time.Time) error
+	BackupFiles(w io.Writer, basePath string, include func(name string, size int64) bool) error
 	Export(w io.Writer, basePath string, start time.Time, end time.Time) error
 	Restore(r io.Reader, basePath string) error
 	Import(r io.Reader, basePath string) error
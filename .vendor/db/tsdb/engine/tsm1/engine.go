@@ -207,6 +207,14 @@ func NewEngine(id uint64, idx tsdb.Index, path string, walPath string, sfile *ts
 	if opt.WALEnabled {
 		wal = NewWAL(walPath)
 		wal.syncDelay = time.Duration(opt.Config.WALFsyncDelay)
+
+		// Archived segments are laid out as the WAL directory is.
+		if opt.Config.WALArchiveDir != "" {
+			if rel, err := filepath.Rel(opt.Config.WALDir, walPath); err == nil {
+				wal.ArchivePath = filepath.Join(opt.Config.WALArchiveDir, rel)
+				wal.ArchiveInterval = time.Duration(opt.Config.WALArchiveInterval)
+			}
+		}
 	}
 
 	fs := NewFileStore(path)
@@ -922,6 +930,26 @@ func (e *Engine) Backup(w io.Writer, basePath string, since time.Time) error {
 	return intar.Stream(w, path, basePath, intar.SinceFilterTarFile(since))
 }
 
+// BackupFiles writes a tar archive of a snapshot of the engine to w, like
+// Backup, leaving out the files for which include returns false. include is
+// called with the name of each file in the archive and its size, before the
+// file is read.
+func (e *Engine) BackupFiles(w io.Writer, basePath string, include func(name string, size int64) bool) error {
+	path, err := e.CreateSnapshot()
+	if err != nil {
+		return err
+	}
+	// Remove the temporary snapshot dir
+	defer os.RemoveAll(path)
+
+	return intar.Stream(w, path, basePath, func(f os.FileInfo, shardRelativePath, fullPath string, tw *tar.Writer) error {
+		if !f.IsDir() && !include(filepath.ToSlash(filepath.Join(shardRelativePath, f.Name())), f.Size()) {
+			return nil
+		}
+		return intar.StreamFile(f, shardRelativePath, fullPath, tw)
+	})
+}
+
 func (e *Engine) timeStampFilterTarFile(start, end time.Time) func(f os.FileInfo, shardRelativePath, fullPath string, tw *tar.Writer) error {
 	return func(fi os.FileInfo, shardRelativePath, fullPath string, tw *tar.Writer) error {
 		if !strings.HasSuffix(fi.Name(), ".tsm") {
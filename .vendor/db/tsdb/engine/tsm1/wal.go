@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"os"
 	"path/filepath"
@@ -34,6 +35,10 @@ const (
 	// WALFilePrefix is the prefix on all wal segment files.
 	WALFilePrefix = "_"
 
+	// walArchiveStagingDir is the directory of the WAL holding the closed
+	// segments waiting to be copied into the archive.
+	walArchiveStagingDir = "archive"
+
 	// walEncodeBufSize is the size of the wal entry encoding buffer
 	walEncodeBufSize = 4 * 1024 * 1024
 
@@ -77,6 +82,7 @@ const (
 	statWALCurrentBytes = "currentSegmentDiskBytes"
 	statWriteOk         = "writeOk"
 	statWriteErr        = "writeErr"
+	statArchiveErr      = "archiveErr"
 )
 
 // WAL represents the write-ahead log used for writing TSM files.
@@ -111,6 +117,18 @@ type WAL struct {
 	// SegmentSize is the file size at which a segment file will be rotated
 	SegmentSize int
 
+	// ArchivePath is the directory closed segment files are archived into, so
+	// that they can be shipped to a backup. Archiving is disabled if it's empty.
+	ArchivePath string
+
+	// ArchiveInterval is the longest time a segment with writes stays open
+	// when archiving is enabled. Segments are only closed when full if zero.
+	ArchiveInterval time.Duration
+
+	// archiving is signaled when a segment is staged to be copied into the
+	// archive.
+	archiving chan struct{}
+
 	// statistics for the WAL
 	stats   *WALStatistics
 	limiter limiter.Fixed
@@ -125,6 +143,7 @@ func NewWAL(path string) *WAL {
 		// these options should be overridden by any options in the config
 		SegmentSize: DefaultSegmentSize,
 		closing:     make(chan struct{}),
+		archiving:   make(chan struct{}, 1),
 		syncWaiters: make(chan chan error, 1024),
 		stats:       &WALStatistics{},
 		limiter:     limiter.NewFixed(defaultWaitingWALWrites),
@@ -156,6 +175,7 @@ type WALStatistics struct {
 	CurrentBytes int64
 	WriteOK      int64
 	WriteErr     int64
+	ArchiveErr   int64
 }
 
 // Statistics returns statistics for periodic monitoring.
@@ -168,6 +188,7 @@ func (l *WAL) Statistics(tags map[string]string) []models.Statistic {
 			statWALCurrentBytes: atomic.LoadInt64(&l.stats.CurrentBytes),
 			statWriteOk:         atomic.LoadInt64(&l.stats.WriteOK),
 			statWriteErr:        atomic.LoadInt64(&l.stats.WriteErr),
+			statArchiveErr:      atomic.LoadInt64(&l.stats.ArchiveErr),
 		},
 	}}
 }
@@ -247,9 +268,50 @@ func (l *WAL) Open() error {
 
 	l.closing = make(chan struct{})
 
+	if l.ArchivePath != "" {
+		// Copy the segments staged before the last shutdown.
+		select {
+		case l.archiving <- struct{}{}:
+		default:
+		}
+		go l.archiveStagedSegments(l.closing)
+	}
+	if l.ArchivePath != "" && l.ArchiveInterval > 0 {
+		go l.closeSegmentsEvery(l.ArchiveInterval, l.closing)
+	}
+
 	return nil
 }
 
+// closeSegmentsEvery closes the current segment every interval if it has
+// writes, so that they are archived, until closing is closed.
+func (l *WAL) closeSegmentsEvery(interval time.Duration, closing <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-closing:
+			return
+		case <-t.C:
+		}
+
+		l.mu.Lock()
+		select {
+		case <-closing:
+			l.mu.Unlock()
+			return
+		default:
+		}
+		if l.currentSegmentWriter != nil && l.currentSegmentWriter.size > 0 {
+			if err := l.newSegmentFile(); err != nil {
+				l.logger.Info("Error closing WAL segment for archiving", zap.String("path", l.path), zap.Error(err))
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
 // scheduleSync will schedule an fsync to the current wal segment and notify any
 // waiting gorutines.  If an fsync is already scheduled, subsequent calls will
 // not schedule a new fsync and will be handle by the existing scheduled fsync.
@@ -567,6 +629,10 @@ func (l *WAL) newSegmentFile() error {
 			return err
 		}
 		atomic.StoreInt64(&l.stats.OldBytes, int64(l.currentSegmentWriter.size))
+
+		if l.ArchivePath != "" && l.currentSegmentWriter.size > 0 {
+			l.archiveSegment(l.currentSegmentWriter.path())
+		}
 	}
 
 	fileName := filepath.Join(l.path, fmt.Sprintf("%s%05d.%s", WALFilePrefix, l.currentSegmentID, WALFileExtension))
@@ -582,6 +648,128 @@ func (l *WAL) newSegmentFile() error {
 	return nil
 }
 
+// archiveSegment archives a closed segment file. It is hard linked into the
+// archive if it is on the same file system. Otherwise it is hard linked into
+// the staging directory of the WAL, and copied into the archive in the
+// background so that the copy doesn't block the writes. A failure doesn't
+// fail the writes, it is logged and counted instead.
+func (l *WAL) archiveSegment(path string) {
+	name := ArchivedSegmentName(path, time.Now())
+	if err := os.MkdirAll(l.ArchivePath, 0700); err == nil {
+		if err := os.Link(path, filepath.Join(l.ArchivePath, name)); err == nil {
+			return
+		}
+	}
+
+	staging := filepath.Join(l.path, walArchiveStagingDir)
+	if err := os.MkdirAll(staging, 0700); err != nil {
+		l.archiveFailed(path, err)
+		return
+	} else if err := os.Link(path, filepath.Join(staging, name)); err != nil {
+		l.archiveFailed(path, err)
+		return
+	}
+
+	select {
+	case l.archiving <- struct{}{}:
+	default:
+	}
+}
+
+// archiveStagedSegments copies the staged segment files into the archive
+// when signaled, until closing is closed. Segments still staged when the WAL
+// closes are copied once it is opened again.
+func (l *WAL) archiveStagedSegments(closing <-chan struct{}) {
+	staging := filepath.Join(l.path, walArchiveStagingDir)
+	for {
+		select {
+		case <-closing:
+			return
+		case <-l.archiving:
+		}
+
+		fis, err := ioutil.ReadDir(staging)
+		if err != nil && !os.IsNotExist(err) {
+			l.archiveFailed(staging, err)
+			continue
+		}
+		for _, fi := range fis {
+			select {
+			case <-closing:
+				return
+			default:
+			}
+
+			src := filepath.Join(staging, fi.Name())
+			if err := archiveFile(src, filepath.Join(l.ArchivePath, fi.Name())); err != nil {
+				l.archiveFailed(src, err)
+				continue
+			}
+			os.Remove(src)
+		}
+	}
+}
+
+// archiveFailed logs and counts a failure to archive the segment at path.
+func (l *WAL) archiveFailed(path string, err error) {
+	atomic.AddInt64(&l.stats.ArchiveErr, 1)
+	l.logger.Info("Error archiving WAL segment", zap.String("path", path), zap.Error(err))
+}
+
+// archiveFile copies src to dst.
+func archiveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// ArchivedSegmentName returns the name of the segment file at path archived
+// when it was closed, e.g. 01634567890123456789-_00001.wal. Archived segments
+// sort by the time they were closed.
+func ArchivedSegmentName(path string, closed time.Time) string {
+	return fmt.Sprintf("%020d-%s", closed.UnixNano(), filepath.Base(path))
+}
+
+// ParseArchivedSegmentName returns the time an archived segment was closed.
+func ParseArchivedSegmentName(name string) (time.Time, error) {
+	i := strings.IndexByte(name, '-')
+	if i < 0 || !strings.HasSuffix(name, "."+WALFileExtension) {
+		return time.Time{}, fmt.Errorf("invalid archived WAL segment name: %s", name)
+	}
+	ns, err := strconv.ParseInt(name[:i], 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid archived WAL segment name: %s", name)
+	}
+	return time.Unix(0, ns).UTC(), nil
+}
+
 // WALEntry is record stored in each WAL segment.  Each entry has a type
 // and an opaque, type dependent byte slice data attribute.
 type WALEntry interface {
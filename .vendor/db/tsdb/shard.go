@@ -1067,6 +1067,16 @@ func (s *Shard) Backup(w io.Writer, basePath string, since time.Time) error {
 	return engine.Backup(w, basePath, since)
 }
 
+// BackupFiles writes a backup of the files of the shard to w, leaving out
+// the files for which include returns false.
+func (s *Shard) BackupFiles(w io.Writer, basePath string, include func(name string, size int64) bool) error {
+	engine, err := s.Engine()
+	if err != nil {
+		return err
+	}
+	return engine.BackupFiles(w, basePath, include)
+}
+
 func (s *Shard) Export(w io.Writer, basePath string, start time.Time, end time.Time) error {
 	engine, err := s.Engine()
 	if err != nil {
@@ -1200,6 +1200,23 @@ func (s *Store) BackupShard(id uint64, since time.Time, w io.Writer) error {
 	return shard.Backup(w, path, since)
 }
 
+// BackupShardFiles writes a backup of the files of a shard to w, leaving out
+// the files for which include returns false. include is called with the name
+// of each file in the archive and its size.
+func (s *Store) BackupShardFiles(id uint64, include func(name string, size int64) bool, w io.Writer) error {
+	shard := s.Shard(id)
+	if shard == nil {
+		return fmt.Errorf("shard %d doesn't exist on this server", id)
+	}
+
+	path, err := relativePath(s.path, shard.path)
+	if err != nil {
+		return err
+	}
+
+	return shard.BackupFiles(w, path, include)
+}
+
 func (s *Store) ExportShard(id uint64, start time.Time, end time.Time, w io.Writer) error {
 	shard := s.Shard(id)
 	if shard == nil {
@@ -2039,6 +2056,10 @@ func IsRetryable(err error) bool {
 		return true
 	}
 
+	// The points that weren't dropped were written.
+	if _, ok := err.(PartialWriteError); ok {
+		return false
+	}
 	if strings.Contains(err.Error(), "field type conflict") {
 		return false
 	}
@@ -47,6 +47,10 @@ type Client interface {
 	// the UDP client.
 	QueryAsChunk(q Query) (*ChunkedResponse, error)
 
+	// Export streams the points selected by e in the format of e. The
+	// error of an export failing once started is returned by the reader.
+	Export(e Export) (io.ReadCloser, error)
+
 	// Close releases any resources a Client may be using.
 	Close() error
 }
@@ -394,6 +398,10 @@ type Query struct {
 	Chunked    bool
 	ChunkSize  int
 	Parameters map[string]interface{}
+
+	// AllowPartial returns the results of the available shards, with a
+	// warning message, when some shards can't be read from any data node.
+	AllowPartial bool
 }
 
 // NewQuery returns a query object.
@@ -618,6 +626,9 @@ func (c *client) createDefaultRequest(q Query) (*http.Request, error) {
 	if q.Precision != "" {
 		params.Set("epoch", q.Precision)
 	}
+	if q.AllowPartial {
+		params.Set("allow_partial", "true")
+	}
 	req.URL.RawQuery = params.Encode()
 
 	return req, nil
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+)
+
+// Formats of an export.
+const (
+	ExportLineProtocol = "lp"
+	ExportCSV          = "csv"
+	ExportParquet      = "parquet"
+)
+
+// Export defines the points to export from a database.
+type Export struct {
+	Database   string
+	TimeToLive string
+
+	// Metric is a regex matching the metrics to export, all of them if
+	// it's empty.
+	Metric string
+
+	// Where is a CnosQL condition on the tags of the series to export.
+	Where string
+
+	// Start and End bound the time of the points to export, End being
+	// excluded. A zero time leaves the range open.
+	Start time.Time
+	End   time.Time
+
+	// Format is the format of the export, line protocol by default.
+	Format string
+}
+
+// Export streams the points selected by e from the server. Line protocol
+// and CSV are transparently gzipped by the transport.
+func (c *client) Export(e Export) (io.ReadCloser, error) {
+	u := c.url
+	u.Path = path.Join(u.Path, "export")
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", c.useragent)
+
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	params := req.URL.Query()
+	params.Set("db", e.Database)
+	if e.TimeToLive != "" {
+		params.Set("ttl", e.TimeToLive)
+	}
+	if e.Metric != "" {
+		params.Set("metric", e.Metric)
+	}
+	if e.Where != "" {
+		params.Set("where", e.Where)
+	}
+	if !e.Start.IsZero() {
+		params.Set("start", e.Start.UTC().Format(time.RFC3339Nano))
+	}
+	if !e.End.IsZero() {
+		params.Set("end", e.End.UTC().Format(time.RFC3339Nano))
+	}
+	if e.Format != "" {
+		params.Set("format", e.Format)
+	}
+	req.URL.RawQuery = params.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		if err := checkResponse(resp); err != nil {
+			return nil, err
+		}
+		var response Response
+		dec := json.NewDecoder(resp.Body)
+		if err := dec.Decode(&response); err != nil {
+			return nil, fmt.Errorf("export failed with status: %v", resp.StatusCode)
+		}
+		if response.Err == "" {
+			return nil, fmt.Errorf("export failed with status: %v", resp.StatusCode)
+		}
+		return nil, errors.New(response.Err)
+	}
+	return &exportReader{resp: resp}, nil
+}
+
+// exportReader reads the body of an export, and returns the error sent by
+// the server in the trailer once the body is read.
+type exportReader struct {
+	resp *http.Response
+}
+
+func (r *exportReader) Read(p []byte) (int, error) {
+	n, err := r.resp.Body.Read(p)
+	if err == io.EOF {
+		if msg := r.resp.Trailer.Get("X-CnosDB-Error"); msg != "" {
+			return n, errors.New(msg)
+		}
+	}
+	return n, err
+}
+
+// Close closes the response.
+func (r *exportReader) Close() error {
+	return r.resp.Body.Close()
+}
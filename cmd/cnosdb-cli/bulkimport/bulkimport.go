@@ -1,4 +1,4 @@
-package _import
+package bulkimport
 
 import (
 	"fmt"
@@ -47,6 +47,9 @@ func GetCommand() *cobra.Command {
 			DisableNoDescFlag:   true,
 		},
 		Run: func(cmd *cobra.Command, args []string) {
+			if config.Path == "" && len(args) > 0 {
+				config.Path = args[0]
+			}
 			addr := net.JoinHostPort(env.Host, strconv.Itoa(env.Port))
 			u, err := parseConnectionString(addr, env.Ssl)
 			if err != nil {
@@ -70,15 +73,27 @@ func GetCommand() *cobra.Command {
 	flags.StringVarP(&config.ClientConfig.Password, "password", "p", "", `Password to login to the server. If password is not given, it's the same as using (--password="").`)
 	flags.BoolVar(&env.Ssl, "ssl", false, "Use https for connecting to cluster.")
 
-	flags.StringVar(&config.Precision, "precision", defaultPrecision, "Precision specifies the format of the timestamp:  rfc3339,h,m,s,ms,u or ns.")
+	flags.StringVar(&config.Precision, "precision", defaultPrecision, "Precision specifies the format of the timestamp:  h,m,s,ms,u or ns.")
 	flags.StringVar(&config.WriteConsistency, "consistency", "all", "Set write consistency level: any, one, quorum, or all.")
 	flags.StringVar(&config.Path, "path", "", "Path to the file to import.")
 	flags.IntVar(&config.PPS, "pps", defaultPPS, "How many points per second the import will allow.  By default it is zero and will not throttle importing.")
 	flags.BoolVar(&config.Compressed, "compressed", false, "set to true if the import file is compressed")
+
+	flags.StringVar(&config.Format, "format", importer.FormatLineProtocol, "Format of the file: lp, csv or json (JSON lines).")
+	flags.StringVar(&config.CSVMapping, "csv-mapping", "", "Roles of the CSV columns, as <column>:<role> separated by commas. The roles are metric, tag, time, rfc3339, float, integer, unsigned, string, boolean and ignore.")
+	flags.StringVar(&config.Metric, "metric", "", "Metric of the CSV rows without a metric column.")
+	flags.StringVar(&config.Database, "database", "", "Database to import into, until a CONTEXT-DATABASE comment of a line protocol file.")
+	flags.StringVar(&config.TimeToLive, "ttl", "", "Time to live to import into, until a CONTEXT-TTL comment of a line protocol file.")
+	flags.IntVar(&config.Workers, "workers", importer.DefaultWorkers, "How many batches are written concurrently.")
+	flags.IntVar(&config.BatchSize, "batch-size", importer.DefaultBatchSize, "How many lines are written by a request.")
+	flags.StringVar(&config.Checkpoint, "checkpoint", "", "File recording the progress of the import. An import started again with the same checkpoint resumes where it stopped.")
+	flags.StringVar(&config.DeadLetter, "dead-letter", "", "File the lines rejected by the server are appended to, after a comment holding the error.")
 	return c
 }
 
-var description = `Import a previous database export from file. [Long]`
+var description = `Import a file of line protocol, CSV or JSON lines. The batches of lines are written
+concurrently, the progress can be checkpointed to resume a failed import, and the lines
+rejected by the server can be kept in a dead-letter file.`
 
 var examples = `Import a previous database export from file. [Example]`
 
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cnosdatabase/cnosdb/client"
 	"github.com/cnosdatabase/cnosdb/pkg/escape"
 	"github.com/cnosdatabase/cnosql"
 	"github.com/cnosdatabase/db/models"
@@ -35,7 +36,7 @@ func GetCommand() *cobra.Command {
 	c := &cobra.Command{
 		Use:     "export",
 		Short:   "exports raw data from a shard to line protocol",
-		Long:    "Exports TSM files into CnosDB line protocol format.",
+		Long:    "Exports TSM files into CnosDB line protocol format, or with --online the data of a server\nin line protocol, CSV or Parquet.",
 		Example: examples,
 		CompletionOptions: cobra.CompletionOptions{
 			DisableDefaultCmd:   true,
@@ -71,7 +72,11 @@ func GetCommand() *cobra.Command {
 			}
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := exportCmd.export(); err != nil {
+			export := exportCmd.export
+			if exportCmd.online {
+				export = exportCmd.exportOnline
+			}
+			if err := export(); err != nil {
 				fmt.Printf("[ERR] %s\n", err)
 				return
 			}
@@ -99,6 +104,16 @@ func GetCommand() *cobra.Command {
 	fs.StringVar(&end, "end", "", "Optional: the end time to export (RFC3339 format)")
 	fs.BoolVar(&exportCmd.compress, "compress", false, "Compress the output")
 
+	fs.BoolVar(&exportCmd.online, "online", false, "Export through the HTTP API of a running server instead of the files")
+	fs.StringVar(&exportCmd.host, "host", client.DEFAULT_HOST, "Host of the CnosDB instance to connect to (requires -online)")
+	fs.IntVar(&exportCmd.port, "port", client.DEFAULT_PORT, "Port of the CnosDB instance to connect to (requires -online)")
+	fs.StringVarP(&exportCmd.username, "username", "u", "", "Username to login to the server (requires -online)")
+	fs.StringVarP(&exportCmd.password, "password", "p", "", "Password to login to the server (requires -online)")
+	fs.BoolVar(&exportCmd.ssl, "ssl", false, "Use https for connecting to the server (requires -online)")
+	fs.StringVar(&exportCmd.format, "format", client.ExportLineProtocol, "Optional: the format of the export, lp, csv or parquet (requires -online)")
+	fs.StringVar(&exportCmd.metric, "metric", "", "Optional: a regex matching the metrics to export (requires -online)")
+	fs.StringVar(&exportCmd.where, "where", "", "Optional: a condition on the tags of the series to export (requires -online)")
+
 	return c
 }
 
@@ -117,6 +132,16 @@ type Command struct {
 	endTime    int64
 	compress   bool
 
+	online   bool
+	host     string
+	port     int
+	username string
+	password string
+	ssl      bool
+	format   string
+	metric   string
+	where    string
+
 	manifest map[string]struct{}
 	tsmFiles map[string][]string
 	walFiles map[string][]string
@@ -141,6 +166,12 @@ func (cmd *Command) validate() error {
 	if cmd.startTime != 0 && cmd.endTime != 0 && cmd.endTime < cmd.startTime {
 		return fmt.Errorf("end time before start time")
 	}
+	if cmd.online && cmd.database == "" {
+		return fmt.Errorf("must specify a db")
+	}
+	if !cmd.online && (cmd.format != client.ExportLineProtocol || cmd.metric != "" || cmd.where != "") {
+		return fmt.Errorf("format, metric and where require online")
+	}
 	return nil
 }
 
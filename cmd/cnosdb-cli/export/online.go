@@ -0,0 +1,83 @@
+package export
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/client"
+)
+
+// exportOnline exports the data of a running server through its /export
+// endpoint.
+func (cmd *Command) exportOnline() error {
+	u := url.URL{
+		Scheme: "http",
+		Host:   net.JoinHostPort(cmd.host, strconv.Itoa(cmd.port)),
+	}
+	if cmd.ssl {
+		u.Scheme = "https"
+	}
+	c, err := client.NewHTTPClient(client.HTTPConfig{
+		Addr:     u.String(),
+		Username: cmd.username,
+		Password: cmd.password,
+	})
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	e := client.Export{
+		Database:   cmd.database,
+		TimeToLive: cmd.timeToLive,
+		Metric:     cmd.metric,
+		Where:      cmd.where,
+		Format:     cmd.format,
+	}
+	if cmd.startTime != math.MinInt64 {
+		e.Start = time.Unix(0, cmd.startTime)
+	}
+	if cmd.endTime != math.MaxInt64 {
+		e.End = time.Unix(0, cmd.endTime)
+	}
+
+	r, err := c.Export(e)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	f, err := os.Create(cmd.out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriterSize(f, 1024*1024)
+	var w io.Writer = bw
+	var gw *gzip.Writer
+	if cmd.compress && cmd.format != client.ExportParquet {
+		gw = gzip.NewWriter(bw)
+		w = gw
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		return err
+	}
+	if gw != nil {
+		if err := gw.Close(); err != nil {
+			return err
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return f.Close()
+}
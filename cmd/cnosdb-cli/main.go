@@ -3,14 +3,14 @@ package main
 import (
 	"fmt"
 
-	"github.com/cnosdatabase/cnosdb/cmd/cnosdb-cli/_import"
+	"github.com/cnosdatabase/cnosdb/cmd/cnosdb-cli/bulkimport"
 	"github.com/cnosdatabase/cnosdb/cmd/cnosdb-cli/cli"
 	"github.com/cnosdatabase/cnosdb/cmd/cnosdb-cli/export"
 )
 
 func main() {
 	cliCmd := cli.GetCommand()
-	importCmd := _import.GetCommand()
+	importCmd := bulkimport.GetCommand()
 	cliCmd.AddCommand(importCmd)
 	exportCmd := export.GetCommand()
 	cliCmd.AddCommand(exportCmd)
@@ -15,6 +15,12 @@ func main() {
 	mainCmd.AddCommand(node.GetRemoveMetaCommand())
 	mainCmd.AddCommand(node.GetAddDataCommand())
 	mainCmd.AddCommand(node.GetRemoveDataCommand())
+	mainCmd.AddCommand(node.GetCopyShardCommand())
+	mainCmd.AddCommand(node.GetMoveShardCommand())
+	mainCmd.AddCommand(node.GetRebalanceCommand())
+	mainCmd.AddCommand(node.GetCopyShardStatusCommand())
+	mainCmd.AddCommand(node.GetKillCopyShardCommand())
+	mainCmd.AddCommand(node.GetEntropyCommand())
 
 	if err := mainCmd.Execute(); err != nil {
 		fmt.Printf("Error : %+v\n", err)
@@ -33,6 +39,13 @@ func GetCommand() *cobra.Command {
 	}
 
 	c.PersistentFlags().StringVar(&options.Env.Bind, "bind", "127.0.0.1:8091", "")
+	c.PersistentFlags().BoolVar(&options.Env.MetaTLS, "meta-tls", false, "use TLS to connect to the meta nodes")
+	c.PersistentFlags().BoolVar(&options.Env.MetaInsecureTLS, "meta-insecure-tls", false, "skip the verification of the meta node certificates")
+	c.PersistentFlags().StringVar(&options.Env.MetaCACert, "meta-ca-cert", "", "CA certificate verifying the meta nodes")
+	c.PersistentFlags().StringVar(&options.Env.MetaClientCert, "meta-client-cert", "", "client certificate for meta nodes requiring mutual TLS")
+	c.PersistentFlags().StringVar(&options.Env.MetaClientKey, "meta-client-key", "", "private key of the client certificate")
+	c.PersistentFlags().StringVar(&options.Env.MetaSharedSecret, "meta-secret", "", "internal shared secret of the meta nodes, signing the tokens sent to them")
+	c.PersistentFlags().StringVar(&options.Env.MetaToken, "meta-token", "", "token sent to the meta nodes")
 
 	return c
 }
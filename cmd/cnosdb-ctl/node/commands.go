@@ -2,9 +2,13 @@ package node
 
 import (
 	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
 
 	"github.com/cnosdatabase/cnosdb/cmd/cnosdb-ctl/options"
-	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/cnosdb/server/ae"
+	"github.com/cnosdatabase/cnosdb/server/copier"
 	"github.com/spf13/cobra"
 )
 
@@ -17,20 +21,10 @@ func GetShowCommand() *cobra.Command {
 		PreRun: func(cmd *cobra.Command, args []string) {
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			peers, err := getMetaServers(options.Env.Bind)
+			metaClient, err := openMetaClient(options.Env.Bind)
 			if err != nil {
 				return err
 			}
-
-			if len(peers) == 0 {
-				return ErrEmptyPeers
-			}
-
-			metaClient := meta.NewRemoteClient()
-			metaClient.SetMetaServers(peers)
-			if err := metaClient.Open(); err != nil {
-				return err
-			}
 			defer metaClient.Close()
 
 			metaNodes, err := metaClient.MetaNodes()
@@ -42,13 +36,13 @@ func GetShowCommand() *cobra.Command {
 				return err
 			}
 
-			fmt.Fprintln(cmd.OutOrStdout(), "Data Nodes:\n==========\n")
+			fmt.Fprint(cmd.OutOrStdout(), "Data Nodes:\n==========\n\n")
 			for _, n := range dataNodes {
 				fmt.Fprintln(cmd.OutOrStdout(), n.ID, "    ", n.TCPHost)
 			}
 			fmt.Fprintln(cmd.OutOrStdout(), "")
 
-			fmt.Fprintln(cmd.OutOrStdout(), "Meta Nodes:\n==========\n")
+			fmt.Fprint(cmd.OutOrStdout(), "Meta Nodes:\n==========\n\n")
 			for _, n := range metaNodes {
 				fmt.Fprintln(cmd.OutOrStdout(), n.ID, "    ", n.Host)
 			}
@@ -130,3 +124,187 @@ func GetRemoveDataCommand() *cobra.Command {
 		},
 	}
 }
+
+func GetCopyShardCommand() *cobra.Command {
+	var rateLimit int64
+	c := &cobra.Command{
+		Use:     "copy-shard",
+		Short:   "copies a shard between data nodes",
+		Long:    "Copies a shard from a source data node to a destination data node. Only the shards of regions that ended can be copied. Press Ctrl-C to abort the copy.",
+		Example: "  cnosdb-ctl copy-shard localhost:8088 localhost:8188 1",
+		Args:    cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shardID, err := strconv.ParseUint(args[2], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid shard id: %s", args[2])
+			}
+			return copyShard(cmd.OutOrStdout(), args[0], args[1], shardID, rateLimit)
+		},
+	}
+	c.Flags().Int64Var(&rateLimit, "rate-limit", 0, "maximum bytes per second read from the source data node, 0 for no limit")
+	return c
+}
+
+func GetMoveShardCommand() *cobra.Command {
+	var rateLimit int64
+	c := &cobra.Command{
+		Use:     "move-shard",
+		Short:   "moves a shard between data nodes",
+		Long:    "Copies a shard from a source data node to a destination data node, then removes it from the source data node. Only the shards of regions that ended can be moved. Press Ctrl-C to abort the copy.",
+		Example: "  cnosdb-ctl move-shard localhost:8088 localhost:8188 1",
+		Args:    cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shardID, err := strconv.ParseUint(args[2], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid shard id: %s", args[2])
+			}
+
+			metaClient, err := openMetaClient(options.Env.Bind)
+			if err != nil {
+				return err
+			}
+			defer metaClient.Close()
+
+			return moveShard(cmd.OutOrStdout(), metaClient, args[0], args[1], shardID, rateLimit)
+		},
+	}
+	c.Flags().Int64Var(&rateLimit, "rate-limit", 0, "maximum bytes per second read from the source data node, 0 for no limit")
+	return c
+}
+
+func GetRebalanceCommand() *cobra.Command {
+	var (
+		dryRun    bool
+		tolerance float64
+		rateLimit int64
+	)
+	c := &cobra.Command{
+		Use:     "rebalance",
+		Short:   "rebalances shards across data nodes",
+		Long:    "Moves shards between data nodes so that each data node owns about the same disk size of shards. Shards that are still written to are not moved. The shard copies are throttled by --rate-limit.",
+		Example: "  cnosdb-ctl rebalance --dry-run",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if rateLimit < 0 {
+				return fmt.Errorf("invalid rate limit: %d", rateLimit)
+			}
+			return rebalance(cmd.OutOrStdout(), options.Env.Bind, tolerance, rateLimit, dryRun)
+		},
+	}
+	c.Flags().BoolVar(&dryRun, "dry-run", false, "print the shard moves without running them")
+	c.Flags().Float64Var(&tolerance, "tolerance", 0.1, "allowed difference between the most and least loaded data nodes, as a fraction of the mean load")
+	c.Flags().Int64Var(&rateLimit, "rate-limit", copier.DefaultRebalanceRateLimit, "maximum bytes per second read from the source data nodes, 0 for no limit")
+	return c
+}
+
+func GetCopyShardStatusCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "copy-shard-status",
+		Short:   "shows the shard copies to a data node",
+		Long:    "Shows the running and finished shard copies to a data node.",
+		Example: "  cnosdb-ctl copy-shard-status localhost:8188",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			copies, err := copier.NewClient(args[0]).Status()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tDatabase\tTTL\tSource\tState\tBytes Copied\tStarted\tEnded\tError")
+			for _, c := range copies {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+					c.ShardID, c.Database, c.TimeToLive, c.Source, c.State, c.BytesCopied,
+					formatTime(c.StartedAt), formatTime(c.EndedAt), c.Err)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func GetKillCopyShardCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "kill-copy-shard",
+		Short:   "aborts a shard copy to a data node",
+		Long:    "Aborts a running shard copy to a data node and removes the partially copied shard.",
+		Example: "  cnosdb-ctl kill-copy-shard localhost:8188 1",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shardID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid shard id: %s", args[1])
+			}
+
+			if err := copier.NewClient(args[0]).KillCopyShard(shardID); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Aborted copy of shard %d\n", shardID)
+			return nil
+		},
+	}
+}
+
+func GetEntropyCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "entropy",
+		Short: "manages anti-entropy of shard replicas",
+		Long:  "Shows and repairs the divergent shard replicas of a data node.",
+	}
+	c.AddCommand(getEntropyShowCommand())
+	c.AddCommand(getEntropyRepairCommand())
+	return c
+}
+
+func getEntropyShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "show",
+		Short:   "shows the status of the shard replicas of a data node",
+		Long:    "Shows the anti-entropy status of the shard replicas of a data node.",
+		Example: "  cnosdb-ctl entropy show localhost:8088",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shards, err := ae.NewClient(args[0]).Status()
+			if err != nil {
+				return err
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 8, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tDatabase\tTTL\tState\tSource\tMissing Keys\tLast Check\tLast Repair\tError")
+			for _, s := range shards {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%d\t%s\t%s\t%s\n",
+					s.ShardID, s.Database, s.TimeToLive, s.State, s.Source, s.MissingKeys,
+					formatTime(s.LastCheck), formatTime(s.LastRepair), s.Err)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func getEntropyRepairCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:     "repair",
+		Short:   "repairs a shard replica of a data node",
+		Long:    "Checks a shard replica of a data node and repairs it from the other owners if it diverged.",
+		Example: "  cnosdb-ctl entropy repair localhost:8088 1",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			shardID, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid shard id: %s", args[1])
+			}
+
+			if err := ae.NewClient(args[0]).Repair(shardID); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Checked shard %d, divergent replicas are being repaired\n", shardID)
+			return nil
+		},
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -2,15 +2,23 @@ package node
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
 
+	"github.com/cnosdatabase/cnosdb/cmd/cnosdb-ctl/options"
 	"github.com/cnosdatabase/cnosdb/meta"
 	"github.com/cnosdatabase/cnosdb/server"
+	"github.com/cnosdatabase/cnosdb/server/copier"
 )
 
 var (
@@ -18,7 +26,12 @@ var (
 )
 
 func getNodeInfo(metaAddr string) (*meta.NodeInfo, error) {
-	resp, err := http.Get(fmt.Sprintf("http://%s/node", metaAddr))
+	client, err := metaHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Get(metaURL(metaAddr, "/node"))
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +57,12 @@ func getNodeInfo(metaAddr string) (*meta.NodeInfo, error) {
 }
 
 func getMetaServers(metaAddr string) ([]string, error) {
-	resp, err := http.Get(fmt.Sprintf("http://%s/meta-servers", metaAddr))
+	client, err := metaHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Get(metaURL(metaAddr, "/meta-servers"))
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +103,12 @@ func addMetaServer(metaAddr, newNodeAddr string) error {
 		return err
 	}
 
-	resp, err := http.Post(fmt.Sprintf("http://%s/join-cluster", newNodeAddr),
+	client, err := metaHTTPClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(metaURL(newNodeAddr, "/join-cluster"),
 		"application/json",
 		bytes.NewBuffer(b))
 	if err != nil {
@@ -125,7 +148,12 @@ func removeMetaServer(metaAddr, remoteNodeAddr string) error {
 	if err != nil {
 		return err
 	}
-	resp, err := http.Post(fmt.Sprintf("http://%s/remove-meta", metaAddr),
+	client, err := metaHTTPClient()
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Post(metaURL(metaAddr, "/remove-meta"),
 		"application/json",
 		bytes.NewBuffer(b))
 	if err != nil {
@@ -208,8 +236,10 @@ func remoteDataServer(metaAddr, remoteNodeAddr string) error {
 		return ErrEmptyPeers
 	}
 
-	metaClient := meta.NewRemoteClient()
-	metaClient.SetMetaServers(peers)
+	metaClient, err := newMetaClient(peers)
+	if err != nil {
+		return err
+	}
 	if err := metaClient.Open(); err != nil {
 		return err
 	}
@@ -228,3 +258,210 @@ func remoteDataServer(metaAddr, remoteNodeAddr string) error {
 
 	return nil
 }
+
+// copyShard copies a shard from the source data node to the destination
+// data node and waits for the copy to finish.
+func copyShard(w io.Writer, sourceAddr, destAddr string, shardID uint64, rateLimit int64) error {
+	client := copier.NewClient(destAddr)
+	if err := client.CopyShard(shardID, sourceAddr, rateLimit); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Copying shard %d from %s to %s\n", shardID, sourceAddr, destAddr)
+
+	// Abort the copy on interrupt.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-interrupt:
+			if err := client.KillCopyShard(shardID); err != nil {
+				return err
+			}
+			return copier.ErrCopyAborted
+		case <-ticker.C:
+		}
+
+		copies, err := client.Status()
+		if err != nil {
+			return err
+		}
+		for _, c := range copies {
+			if c.ShardID != shardID {
+				continue
+			}
+
+			switch c.State {
+			case copier.StateCopying:
+				fmt.Fprintf(w, "Copied %d bytes\n", c.BytesCopied)
+			case copier.StateCompleted:
+				fmt.Fprintf(w, "Copied shard %d (%d bytes) in %s\n", shardID, c.BytesCopied, c.EndedAt.Sub(c.StartedAt))
+				return nil
+			default:
+				return fmt.Errorf("copy shard %d %s: %s", shardID, c.State, c.Err)
+			}
+		}
+	}
+}
+
+// openMetaClient returns a client of the meta servers of the cluster.
+func openMetaClient(metaAddr string) (*meta.RemoteClient, error) {
+	peers, err := getMetaServers(metaAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(peers) == 0 {
+		return nil, ErrEmptyPeers
+	}
+
+	metaClient, err := newMetaClient(peers)
+	if err != nil {
+		return nil, err
+	}
+	if err := metaClient.Open(); err != nil {
+		return nil, err
+	}
+	return metaClient, nil
+}
+
+// metaTLSConfig returns the TLS config for the meta nodes given on the
+// command line, or nil if TLS is disabled.
+func metaTLSConfig() (*tls.Config, error) {
+	if !options.Env.MetaTLS {
+		return nil, nil
+	}
+	return meta.ClientTLSConfig(options.Env.MetaCACert, options.Env.MetaClientCert, options.Env.MetaClientKey, options.Env.MetaInsecureTLS)
+}
+
+// metaHTTPClient returns an HTTP client sending the credentials given on the
+// command line to the meta nodes.
+func metaHTTPClient() (*http.Client, error) {
+	tlsConfig, err := metaTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return meta.NewHTTPClient(tlsConfig, options.Env.MetaSharedSecret, options.Env.MetaToken), nil
+}
+
+// metaURL returns the URL of a path of the meta node API.
+func metaURL(addr, path string) string {
+	if options.Env.MetaTLS {
+		return "https://" + addr + path
+	}
+	return "http://" + addr + path
+}
+
+// newMetaClient returns a client of the meta servers, which sends the
+// credentials given on the command line.
+func newMetaClient(peers []string) (*meta.RemoteClient, error) {
+	tlsConfig, err := metaTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	metaClient := meta.NewRemoteClient()
+	metaClient.SetMetaServers(peers)
+	if tlsConfig != nil {
+		metaClient.SetTLSConfig(tlsConfig)
+	}
+	if options.Env.MetaSharedSecret != "" {
+		metaClient.SetSharedSecret(options.Env.MetaSharedSecret)
+	}
+	if options.Env.MetaToken != "" {
+		metaClient.SetToken(options.Env.MetaToken)
+	}
+	return metaClient, nil
+}
+
+// moveShard copies a shard from the source data node to the destination
+// data node, then removes the source data node from the owners of the
+// shard and deletes its copy of the shard.
+//
+// Points written to the source data node after the copy started would be
+// lost with its copy of the shard, so only the shards of regions that ended
+// are moved, like the shards moved by rebalance.
+func moveShard(w io.Writer, metaClient meta.MetaClient, sourceAddr, destAddr string, shardID uint64, rateLimit int64) error {
+	n, err := metaClient.DataNodeByTCPHost(sourceAddr)
+	if err != nil {
+		return err
+	}
+
+	_, _, rg := metaClient.ShardOwner(shardID)
+	if rg == nil {
+		return fmt.Errorf("shard %d does not exist", shardID)
+	} else if !rg.EndTime.Before(time.Now()) {
+		return fmt.Errorf("shard %d may still be written to: its region ends at %s", shardID, rg.EndTime.UTC().Format(time.RFC3339))
+	}
+
+	if err := copyShard(w, sourceAddr, destAddr, shardID, rateLimit); err != nil {
+		return err
+	}
+
+	if err := metaClient.UpdateShardOwners(shardID, nil, []uint64{n.ID}); err != nil {
+		return err
+	}
+
+	if err := copier.NewClient(sourceAddr).RemoveShard(shardID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Moved shard %d from %s to %s\n", shardID, sourceAddr, destAddr)
+
+	return nil
+}
+
+// rebalance moves shards between the data nodes of the cluster to even out
+// the disk size of the shards they own. The moves are only printed if
+// dryRun is set.
+func rebalance(w io.Writer, metaAddr string, tolerance float64, rateLimit int64, dryRun bool) error {
+	metaClient, err := openMetaClient(metaAddr)
+	if err != nil {
+		return err
+	}
+	defer metaClient.Close()
+
+	data := metaClient.Data()
+	hosts := make(map[uint64]string, len(data.DataNodes))
+	sizes := make(map[uint64]map[uint64]int64, len(data.DataNodes))
+	for _, n := range data.DataNodes {
+		s, err := copier.NewClient(n.TCPHost).ShardSizes()
+		if err != nil {
+			return fmt.Errorf("shard sizes of data node %d: %s", n.ID, err)
+		}
+		hosts[n.ID] = n.TCPHost
+		sizes[n.ID] = s
+	}
+
+	moves := copier.PlanRebalance(&data, sizes, time.Now().UTC(), tolerance)
+	if len(moves) == 0 {
+		fmt.Fprintln(w, "Cluster is balanced")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tDatabase\tTTL\tSource\tDestination\tSize")
+	for _, m := range moves {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%d\n",
+			m.ShardID, m.Database, m.TimeToLive, hosts[m.Source], hosts[m.Destination], m.Size)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if dryRun {
+		return nil
+	}
+
+	for _, m := range moves {
+		if err := moveShard(w, metaClient, hosts[m.Source], hosts[m.Destination], m.ShardID, rateLimit); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "Moved %d shards\n", len(moves))
+
+	return nil
+}
@@ -2,6 +2,15 @@ package options
 
 type options struct {
 	Bind string
+
+	// The credentials for the meta nodes.
+	MetaTLS          bool
+	MetaInsecureTLS  bool
+	MetaCACert       string
+	MetaClientCert   string
+	MetaClientKey    string
+	MetaSharedSecret string
+	MetaToken        string
 }
 
 var Env = options{}
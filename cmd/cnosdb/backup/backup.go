@@ -1,7 +1,10 @@
 package backup
 
 import (
+	"archive/tar"
+	"crypto/sha256"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,11 +15,13 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cnosdatabase/cnosdb/cmd/cnosdb/backup_util"
 	"github.com/cnosdatabase/cnosdb/pkg/network"
 	"github.com/cnosdatabase/cnosdb/server/snapshotter"
+	"github.com/cnosdatabase/db/tsdb/engine/tsm1"
 	gzip "github.com/klauspost/pgzip"
 	"github.com/spf13/cobra"
 )
@@ -33,7 +38,10 @@ const (
 	BackupFilePattern = "%s.%s.%05d"
 )
 
-var backup_examples = `  cnosdb backup --start 2021-10-10T12:12:00Z`
+var backup_examples = `  cnosdb backup --start 2021-10-10T12:12:00Z
+  cnosdb backup --incremental /var/backups/cnosdb
+  cnosdb backup --incremental --compress --retain 3 --s3-endpoint http://localhost:9000 s3://backups/cnosdb
+  cnosdb backup --wal --interval 1m /var/backups/cnosdb`
 
 // options represents the program execution for "cnosdb backup".
 type options struct {
@@ -61,10 +69,30 @@ type options struct {
 	portableFileBase string
 	continueOnError  bool
 
+	// incremental backups only download the files which aren't in the
+	// chain of the latest backup.
+	incremental bool
+	chain       *backup_util.Chain
+
+	// target stores the backup files, they are downloaded into the
+	// staging directory first.
+	target        backup_util.Target
+	targetOptions backup_util.TargetOptions
+	staging       string
+
+	// retain is the number of backup sets kept in the target, all of them
+	// if zero.
+	retain int
+
+	// wal ships the WAL segments archived by the server instead of backing
+	// up the shards, every interval if it isn't zero.
+	wal      bool
+	interval time.Duration
+
 	BackupFiles []string
 }
 
-var env = options{}
+var env = options{Stdout: os.Stdout, Stderr: os.Stderr}
 
 func GetCommand() *cobra.Command {
 	c := &cobra.Command{
@@ -103,22 +131,71 @@ func GetCommand() *cobra.Command {
 				}
 			}
 
+			if env.incremental && (env.startArg != "" || env.endArg != "") {
+				return errors.New("incremental backups can't be limited by start or end dates")
+			}
+
 			// Ensure that only one arg is specified.
 			if len(args) != 1 {
 				return errors.New("Exactly one backup path is required.")
 			}
 			env.path = args[0]
 
-			err = os.MkdirAll(env.path, 0700)
+			if env.retain < 0 {
+				return errors.New("retain must be positive")
+			}
 
-			return err
+			if env.wal {
+				if env.database != "" || env.timeToLive != "" || env.shardID != "" ||
+					env.startArg != "" || env.endArg != "" || env.incremental || env.portable {
+					return errors.New("WAL segments can't be shipped with a shard backup")
+				}
+			} else if env.interval != 0 {
+				return errors.New("interval requires shipping WAL segments")
+			}
+			if env.interval < 0 {
+				return errors.New("interval must be positive")
+			}
+
+			if !backup_util.IsS3Location(env.path) {
+				if err := os.MkdirAll(env.path, 0700); err != nil {
+					return err
+				}
+			}
+			if env.target, err = backup_util.NewTarget(env.path, env.targetOptions); err != nil {
+				return err
+			}
+
+			env.manifest = backup_util.Manifest{}
+			env.chain = &backup_util.Chain{Target: env.target}
+			if env.incremental {
+				if env.chain, err = backup_util.LoadChain(env.target); err != nil {
+					return err
+				}
+				env.manifest.Parent = env.chain.Latest()
+			}
+			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Set up logger.
 			env.StdoutLogger = log.New(env.Stdout, "", log.LstdFlags)
 			env.StderrLogger = log.New(env.Stderr, "", log.LstdFlags)
 
+			// Local backups are staged in the backup directory, so that
+			// the files are moved rather than copied into place.
+			var stagingDir string
+			if !backup_util.IsS3Location(env.path) {
+				stagingDir = env.path
+			}
 			var err error
+			if env.staging, err = ioutil.TempDir(stagingDir, ".staging"); err != nil {
+				return err
+			}
+			defer os.RemoveAll(env.staging)
+
+			if env.wal {
+				return env.backupWAL()
+			}
 
 			if env.shardID != "" {
 				// always backup the metastore
@@ -157,9 +234,9 @@ func GetCommand() *cobra.Command {
 
 			}
 
-			if env.portable {
+			if err == nil {
 				filename := env.portableFileBase + ".manifest"
-				if err := env.manifest.Save(filepath.Join(env.path, filename)); err != nil {
+				if err := env.manifest.Save(env.target, filename); err != nil {
 					env.StderrLogger.Printf("manifest save failed: %v", err)
 					return err
 				}
@@ -172,7 +249,18 @@ func GetCommand() *cobra.Command {
 			}
 			env.StdoutLogger.Println("backup complete:")
 			for _, v := range env.BackupFiles {
-				env.StdoutLogger.Println("\t" + filepath.Join(env.path, v))
+				env.StdoutLogger.Println("\t" + env.location(v))
+			}
+
+			if env.retain > 0 {
+				removed, err := backup_util.Prune(env.target, env.retain)
+				for _, v := range removed {
+					env.StdoutLogger.Printf("removed expired backup file %s", env.location(v))
+				}
+				if err != nil {
+					env.StderrLogger.Printf("prune failed: %v", err)
+					return err
+				}
 			}
 
 			return nil
@@ -186,6 +274,14 @@ func GetCommand() *cobra.Command {
 	c.Flags().StringVar(&env.startArg, "start", "", "Include all points starting with specified timestamp (RFC3339 format).")
 	c.Flags().StringVar(&env.endArg, "end", "", "Exclude all points after timestamp (RFC3339 format).")
 	c.Flags().BoolVar(&env.continueOnError, "skip-errors", false, "Optional flag to continue backing up the remaining shards when the current shard fails to backup.")
+	c.Flags().BoolVar(&env.incremental, "incremental", false, "Only back up the files which changed since the latest backup in PATH. A full backup is made if PATH holds none.")
+	c.Flags().BoolVar(&env.targetOptions.Compress, "compress", false, "Compress the backup files with gzip.")
+	c.Flags().StringVar(&env.targetOptions.KeyFile, "encryption-key-file", "", "File holding the AES-256 key encrypting the backup files, as 32 bytes or 64 hex digits. Optional.")
+	c.Flags().StringVar(&env.targetOptions.S3.Endpoint, "s3-endpoint", "", "URL of the S3-compatible store when PATH is s3://bucket/prefix. Optional. Defaults to AWS. Credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.")
+	c.Flags().StringVar(&env.targetOptions.S3.Region, "s3-region", backup_util.DefaultS3Region, "Region of the S3-compatible store.")
+	c.Flags().IntVar(&env.retain, "retain", 0, "Number of backup sets to keep in PATH, a full backup with its incremental backups being a set. Older sets are removed after the backup. Optional. Defaults to keeping all of them.")
+	c.Flags().BoolVar(&env.wal, "wal", false, "Ship the WAL segments archived by the server to PATH instead of backing up the shards, for point-in-time restores. Requires wal-archive-dir in the data config of the server.")
+	c.Flags().DurationVar(&env.interval, "interval", 0, "Keep shipping the archived WAL segments at this interval. Optional. Defaults to shipping them once.")
 
 	return c
 }
@@ -196,44 +292,56 @@ func (cmd *options) backupShard(db, ttl, sid string) error {
 		return err
 	}
 
-	shardArchivePath, err := cmd.nextPath(filepath.Join(cmd.path, fmt.Sprintf(backup_util.BackupFilePattern, db, ttl, id)))
+	shardArchiveName, err := cmd.nextPath(fmt.Sprintf(backup_util.BackupFilePattern, db, ttl, id))
 	if err != nil {
 		return err
 	}
 
-	cmd.StdoutLogger.Printf("backing up db=%v ttl=%v shard=%v to %s with boundaries start=%s, end=%s",
-		db, ttl, sid, shardArchivePath, cmd.start.Format(time.RFC3339), cmd.end.Format(time.RFC3339))
-
-	req := &snapshotter.Request{
-		Type:             snapshotter.RequestShardExport,
-		BackupDatabase:   db,
-		BackupTimeToLive: ttl,
-		ShardID:          id,
-		ExportStart:      cmd.start,
-		ExportEnd:        cmd.end,
+	var written bool
+	if cmd.start.IsZero() && cmd.end.IsZero() {
+		cmd.StdoutLogger.Printf("backing up db=%v ttl=%v shard=%v to %s", db, ttl, sid, cmd.location(shardArchiveName))
+		written, err = cmd.downloadShardFiles(db, ttl, id, shardArchiveName)
+	} else {
+		cmd.StdoutLogger.Printf("backing up db=%v ttl=%v shard=%v to %s with boundaries start=%s, end=%s",
+			db, ttl, sid, cmd.location(shardArchiveName), cmd.start.Format(time.RFC3339), cmd.end.Format(time.RFC3339))
+
+		req := &snapshotter.Request{
+			Type:             snapshotter.RequestShardExport,
+			BackupDatabase:   db,
+			BackupTimeToLive: ttl,
+			ShardID:          id,
+			ExportStart:      cmd.start,
+			ExportEnd:        cmd.end,
+		}
+		var n int64
+		n, _, err = cmd.downloadAndVerify(req, shardArchiveName, func(file string) error {
+			return cmd.addShardArchive(db, ttl, id, shardArchiveName, file)
+		})
+		written = n > 0
 	}
-
-	// TODO: verify shard backup data
-	err = cmd.downloadAndVerify(req, shardArchivePath, nil)
 	if err != nil {
-		os.Remove(shardArchivePath)
+		cmd.target.Remove(shardArchiveName)
 		return err
 	}
+	if !written {
+		// Nothing changed since the previous backup.
+		return nil
+	}
 	if !cmd.portable {
-		cmd.BackupFiles = append(cmd.BackupFiles, shardArchivePath)
+		cmd.BackupFiles = append(cmd.BackupFiles, shardArchiveName)
 	}
 
 	if cmd.portable {
-		f, err := os.Open(shardArchivePath)
+		f, err := cmd.target.Open(shardArchiveName)
 		if err != nil {
 			return err
 		}
 		defer f.Close()
-		defer os.Remove(shardArchivePath)
+		defer cmd.target.Remove(shardArchiveName)
 
 		filePrefix := cmd.portableFileBase + ".s" + sid
 		filename := filePrefix + ".tar.gz"
-		out, err := os.OpenFile(filepath.Join(cmd.path, filename), os.O_CREATE|os.O_RDWR, 0600)
+		out, err := cmd.target.Create(filename)
 		if err != nil {
 			return err
 		}
@@ -289,6 +397,330 @@ func (cmd *options) backupShard(db, ttl, sid string) error {
 
 }
 
+// downloadShardFiles downloads the files of a shard which aren't in the
+// backup chain into the named archive of the target, and adds all files of
+// the shard to the manifest. Every downloaded file is checked against the
+// checksum sent by the server. No archive is written if no file changed.
+func (cmd *options) downloadShardFiles(db, ttl string, id uint64, name string) (bool, error) {
+	req := &snapshotter.Request{
+		Type:             snapshotter.RequestShardIncrementalBackup,
+		BackupDatabase:   db,
+		BackupTimeToLive: ttl,
+		ShardID:          id,
+	}
+
+	known := make(map[string]backup_util.FileEntry)
+	if prev := cmd.chain.Shard(id); prev != nil {
+		for _, f := range prev.Files {
+			known[f.Name] = f
+			req.KnownFiles = append(req.KnownFiles, f.Name)
+		}
+	}
+
+	tmppath := filepath.Join(cmd.staging, name+backup_util.Suffix)
+	defer os.Remove(tmppath)
+	if err := cmd.download(req, tmppath); err != nil {
+		return false, err
+	}
+
+	received, list, err := copyShardArchive(tmppath, cmd.target, name)
+	if err != nil {
+		return false, err
+	}
+
+	entry := backup_util.ShardEntry{Database: db, Policy: ttl, ShardID: id}
+	for _, f := range list {
+		if f.Checksum == "" {
+			// The server left out a file already backed up.
+			prev, ok := known[f.Name]
+			if !ok || prev.Size != f.Size {
+				return false, fmt.Errorf("shard %d: %s isn't in the backup chain", id, f.Name)
+			}
+			entry.Files = append(entry.Files, prev)
+			continue
+		}
+
+		fe, ok := received[f.Name]
+		if !ok || fe.Size != f.Size || fe.Checksum != f.Checksum {
+			return false, fmt.Errorf("shard %d: checksum mismatch for %s", id, f.Name)
+		}
+		fe.Archive = name
+		entry.Files = append(entry.Files, fe)
+	}
+	entry.Generation = tsmGeneration(entry.Files)
+	cmd.manifest.Shards = append(cmd.manifest.Shards, entry)
+	return len(received) > 0, nil
+}
+
+// copyShardArchive copies the files of the incremental shard backup at src
+// into the named tar archive of the target. The archive is only created if
+// the backup holds a file. It returns the copied files with their
+// checksums, and the list of the files of the shard sent by the server.
+func copyShardArchive(src string, t backup_util.Target, name string) (map[string]backup_util.FileEntry, []snapshotter.BackupFile, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer in.Close()
+
+	var out io.WriteCloser
+	var tw *tar.Writer
+	defer func() {
+		if out != nil {
+			out.Close()
+		}
+	}()
+
+	files := make(map[string]backup_util.FileEntry)
+	var list []snapshotter.BackupFile
+	tr := tar.NewReader(in)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		if hdr.Name == snapshotter.BackupFileList {
+			if err := json.NewDecoder(tr).Decode(&list); err != nil {
+				return nil, nil, fmt.Errorf("read file list: %s", err)
+			}
+			continue
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+
+		if out == nil {
+			if out, err = t.Create(name); err != nil {
+				return nil, nil, err
+			}
+			tw = tar.NewWriter(out)
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, nil, err
+		}
+
+		h := sha256.New()
+		n, err := io.Copy(io.MultiWriter(tw, h), tr)
+		if err != nil {
+			return nil, nil, err
+		}
+		files[hdr.Name] = backup_util.FileEntry{Name: hdr.Name, Size: n, Checksum: hex.EncodeToString(h.Sum(nil))}
+	}
+
+	if list == nil {
+		return nil, nil, errors.New("incomplete shard backup: missing file list")
+	}
+	if out == nil {
+		return files, list, nil
+	}
+	if err := tw.Close(); err != nil {
+		return nil, nil, err
+	}
+	w := out
+	out = nil
+	return files, list, w.Close()
+}
+
+// addShardArchive adds the files of the shard archive at path, saved as the
+// named archive of the target, to the manifest with their checksums.
+func (cmd *options) addShardArchive(db, ttl string, id uint64, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	entry := backup_util.ShardEntry{Database: db, Policy: ttl, ShardID: id}
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		} else if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+
+		h := sha256.New()
+		n, err := io.Copy(h, tr)
+		if err != nil {
+			return err
+		}
+		entry.Files = append(entry.Files, backup_util.FileEntry{
+			Name:     hdr.Name,
+			Size:     n,
+			Checksum: hex.EncodeToString(h.Sum(nil)),
+			Archive:  name,
+		})
+	}
+	if len(entry.Files) == 0 {
+		return nil
+	}
+	entry.Generation = tsmGeneration(entry.Files)
+	cmd.manifest.Shards = append(cmd.manifest.Shards, entry)
+	return nil
+}
+
+// tsmGeneration returns the highest generation of the TSM files.
+func tsmGeneration(files []backup_util.FileEntry) int {
+	var max int
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name, "."+tsm1.TSMFileExtension) {
+			continue
+		}
+		if gen, _, err := tsm1.DefaultParseFileName(f.Name); err == nil && gen > max {
+			max = gen
+		}
+	}
+	return max
+}
+
+// backupWAL ships the WAL segments archived by the server to the target.
+// Every round acknowledges the segments shipped by the previous one, which
+// the server then removes from its archive. Rounds run until one ships no
+// segment, or forever every interval.
+func (cmd *options) backupWAL() error {
+	// The segments already in the target are acknowledged by the first
+	// round, in case a previous run stopped before acknowledging them.
+	names, err := cmd.target.List("wal.")
+	if err != nil {
+		return err
+	}
+	var shipped []string
+	for _, name := range names {
+		db, ttl, id, segment, _, err := backup_util.ParseWALFileName(name)
+		if err != nil {
+			continue
+		}
+		shipped = append(shipped, strings.Join([]string{db, ttl, strconv.FormatUint(id, 10), segment}, "/"))
+	}
+
+	for {
+		if shipped, err = cmd.shipWAL(shipped); err != nil {
+			cmd.StderrLogger.Printf("WAL backup failed: %v", err)
+			return err
+		}
+
+		if cmd.retain > 0 {
+			removed, err := backup_util.Prune(cmd.target, cmd.retain)
+			for _, v := range removed {
+				cmd.StdoutLogger.Printf("removed expired backup file %s", cmd.location(v))
+			}
+			if err != nil {
+				cmd.StderrLogger.Printf("prune failed: %v", err)
+				return err
+			}
+		}
+
+		if len(shipped) == 0 {
+			if cmd.interval == 0 {
+				cmd.StdoutLogger.Println("WAL backup complete")
+				return nil
+			}
+			time.Sleep(cmd.interval)
+		}
+	}
+}
+
+// shipWAL acknowledges the shipped segments, and copies the other segments
+// archived by the server to the target. Every segment is checked against the
+// checksum sent by the server. It returns the names of the copied segments
+// in the archive of the server.
+func (cmd *options) shipWAL(shipped []string) ([]string, error) {
+	req := &snapshotter.Request{
+		Type:       snapshotter.RequestWALArchive,
+		KnownFiles: shipped,
+	}
+
+	tmppath := filepath.Join(cmd.staging, "wal"+backup_util.Suffix)
+	defer os.Remove(tmppath)
+	if err := cmd.download(req, tmppath); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(tmppath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var list []snapshotter.BackupFile
+	var segments []string
+	written := make(map[string]string)
+	checksums := make(map[string]string)
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if hdr.Name == snapshotter.BackupFileList {
+			if err := json.NewDecoder(tr).Decode(&list); err != nil {
+				return nil, fmt.Errorf("read file list: %s", err)
+			}
+			continue
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+
+		// Segments are archived as <db>/<ttl>/<shardID>/<segment>.
+		parts := strings.Split(hdr.Name, "/")
+		if len(parts) != 4 {
+			return nil, fmt.Errorf("invalid archived WAL segment: %s", hdr.Name)
+		}
+		id, err := strconv.ParseUint(parts[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid archived WAL segment: %s", hdr.Name)
+		}
+		name := backup_util.WALFileName(parts[0], parts[1], id, parts[3])
+
+		w, err := cmd.target.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(w, h), tr); err != nil {
+			w.Close()
+			cmd.target.Remove(name)
+			return nil, fmt.Errorf("write %s: %s", name, err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("write %s: %s", name, err)
+		}
+		written[hdr.Name] = name
+		checksums[hdr.Name] = hex.EncodeToString(h.Sum(nil))
+	}
+
+	if list == nil {
+		return nil, errors.New("incomplete WAL archive: missing file list, is WAL archiving enabled on the server?")
+	}
+
+	// A segment which doesn't match its checksum isn't acknowledged, so
+	// that the next run ships it again.
+	for _, file := range list {
+		name, ok := written[file.Name]
+		if !ok {
+			return nil, fmt.Errorf("incomplete WAL archive: missing %s", file.Name)
+		} else if checksums[file.Name] != file.Checksum {
+			cmd.target.Remove(name)
+			return nil, fmt.Errorf("checksum mismatch for %s", file.Name)
+		}
+		segments = append(segments, file.Name)
+		cmd.StdoutLogger.Printf("shipped WAL segment %s", cmd.location(name))
+	}
+	return segments, nil
+}
+
 // backupDatabase will request the database information from the server and then backup
 // every shard in every time to live in the database. Each shard will be written to a separate file.
 func (cmd *options) backupDatabase() error {
@@ -351,18 +783,18 @@ func (cmd *options) backupResponsePaths(response *snapshotter.Response) error {
 // backupMetastore will backup the whole metastore on the host to the backup path
 // if useDB is non-empty, it will backup metadata only for the named database.
 func (cmd *options) backupMetastore() error {
-	metastoreArchivePath, err := cmd.nextPath(filepath.Join(cmd.path, backup_util.Metafile))
+	metastoreArchiveName, err := cmd.nextPath(backup_util.Metafile)
 	if err != nil {
 		return err
 	}
 
-	cmd.StdoutLogger.Printf("backing up metastore to %s", metastoreArchivePath)
+	cmd.StdoutLogger.Printf("backing up metastore to %s", cmd.location(metastoreArchiveName))
 
 	req := &snapshotter.Request{
 		Type: snapshotter.RequestMetastoreBackup,
 	}
 
-	err = cmd.downloadAndVerify(req, metastoreArchivePath, func(file string) error {
+	size, checksum, err := cmd.downloadAndVerify(req, metastoreArchiveName, func(file string) error {
 		f, err := os.Open(file)
 		if err != nil {
 			return err
@@ -393,12 +825,22 @@ func (cmd *options) backupMetastore() error {
 	}
 
 	if !cmd.portable {
-		cmd.BackupFiles = append(cmd.BackupFiles, metastoreArchivePath)
+		cmd.manifest.Meta = backup_util.MetaEntry{
+			FileName: metastoreArchiveName,
+			Size:     size,
+			Checksum: checksum,
+		}
+		cmd.BackupFiles = append(cmd.BackupFiles, metastoreArchiveName)
 	}
 
 	if cmd.portable {
-		metaBytes, err := backup_util.GetMetaBytes(metastoreArchivePath)
-		defer os.Remove(metastoreArchivePath)
+		f, err := cmd.target.Open(metastoreArchiveName)
+		if err != nil {
+			return err
+		}
+		metaBytes, err := backup_util.GetMetaBytes(f)
+		f.Close()
+		defer cmd.target.Remove(metastoreArchiveName)
 		if err != nil {
 			return err
 		}
@@ -408,7 +850,7 @@ func (cmd *options) backupMetastore() error {
 		if err != nil {
 			return err
 		}
-		if err := ioutil.WriteFile(filepath.Join(cmd.path, filename), protoBytes, 0644); err != nil {
+		if err := backup_util.WriteFile(cmd.target, filename, protoBytes); err != nil {
 			fmt.Fprintln(cmd.Stdout, "Error.")
 			return err
 		}
@@ -421,52 +863,84 @@ func (cmd *options) backupMetastore() error {
 	return nil
 }
 
-// nextPath returns the next file to write to.
-func (cmd *options) nextPath(path string) (string, error) {
+// nextPath returns the name of the next file of the target to write to.
+func (cmd *options) nextPath(name string) (string, error) {
+	names, err := cmd.target.List(name)
+	if err != nil {
+		return "", err
+	}
+	exists := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		exists[n] = struct{}{}
+	}
+
 	// Iterate through incremental files until one is available.
 	for i := 0; ; i++ {
-		s := fmt.Sprintf(path+".%02d", i)
-		if _, err := os.Stat(s); os.IsNotExist(err) {
+		s := fmt.Sprintf(name+".%02d", i)
+		if _, ok := exists[s]; !ok {
 			return s, nil
-		} else if err != nil {
-			return "", err
 		}
 	}
 }
 
+// location returns the location of the named file of the target, for display.
+func (cmd *options) location(name string) string {
+	if backup_util.IsS3Location(cmd.path) {
+		return strings.TrimSuffix(cmd.path, "/") + "/" + name
+	}
+	return filepath.Join(cmd.path, name)
+}
+
 // downloadAndVerify will download either the metastore or shard to a temp file and then
-// rename it to a good backup file name after complete
-func (cmd *options) downloadAndVerify(req *snapshotter.Request, path string, validator func(string) error) error {
-	tmppath := path + backup_util.Suffix
+// write it to the named file of the target after complete. It returns the size and the
+// checksum of the file, the size being zero if nothing was downloaded.
+func (cmd *options) downloadAndVerify(req *snapshotter.Request, name string, validator func(string) error) (int64, string, error) {
+	tmppath := filepath.Join(cmd.staging, name+backup_util.Suffix)
+	defer os.Remove(tmppath)
 	if err := cmd.download(req, tmppath); err != nil {
-		return err
+		return 0, "", err
 	}
 
 	if validator != nil {
 		if err := validator(tmppath); err != nil {
-			if rmErr := os.Remove(tmppath); rmErr != nil {
-				cmd.StderrLogger.Printf("Error cleaning up temporary file: %v", rmErr)
-			}
-			return err
+			return 0, "", err
 		}
 	}
 
-	f, err := os.Stat(tmppath)
+	f, err := os.Open(tmppath)
 	if err != nil {
-		return err
+		return 0, "", err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, "", err
 	}
 
 	// There was nothing downloaded, don't create an empty backup file.
-	if f.Size() == 0 {
-		return os.Remove(tmppath)
+	if fi.Size() == 0 {
+		return 0, "", nil
 	}
 
-	// Rename temporary file to final path.
-	if err := os.Rename(tmppath, path); err != nil {
-		return fmt.Errorf("rename: %s", err)
+	// Write the temporary file to the target.
+	w, err := cmd.target.Create(name)
+	if err != nil {
+		return 0, "", err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), f)
+	if err == nil {
+		err = w.Close()
+	} else {
+		w.Close()
+		cmd.target.Remove(name)
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("write %s: %s", name, err)
 	}
 
-	return nil
+	return n, hex.EncodeToString(h.Sum(nil)), nil
 }
 
 // download downloads a snapshot of either the metastore or a shard from a host to a given path.
@@ -481,6 +955,14 @@ func (cmd *options) download(req *snapshotter.Request, path string) error {
 	min := 2 * time.Second
 	for i := 0; i < 10; i++ {
 		if err = func() error {
+			// Discard what a failed attempt downloaded.
+			if err := f.Truncate(0); err != nil {
+				return err
+			}
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+
 			// Connect to snapshotter service.
 			conn, err := network.Dial("tcp", cmd.host, snapshotter.MuxHeader)
 			if err != nil {
@@ -6,11 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"sort"
 	"strings"
 
-	"io/ioutil"
 	"path/filepath"
 
 	internal "github.com/cnosdatabase/cnosdb/cmd/cnosdb/backup_util/internal"
@@ -55,14 +53,9 @@ func (ep *PortablePacker) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
-func GetMetaBytes(fname string) ([]byte, error) {
-	f, err := os.Open(fname)
-	if err != nil {
-		return []byte{}, err
-	}
-
+func GetMetaBytes(r io.Reader) ([]byte, error) {
 	var buf bytes.Buffer
-	if _, err := io.Copy(&buf, f); err != nil {
+	if _, err := io.Copy(&buf, r); err != nil {
 		return []byte{}, fmt.Errorf("copy: %s", err)
 	}
 
@@ -92,6 +85,13 @@ type Manifest struct {
 	Limited bool      `json:"limited"`
 	Files   []Entry   `json:"files"`
 
+	// Parent is the manifest of the previous backup of an incremental
+	// backup, it is empty for a full backup.
+	Parent string `json:"parent,omitempty"`
+
+	// Shards lists the files of the backed up shards.
+	Shards []ShardEntry `json:"shards,omitempty"`
+
 	// If limited is true, then one (or all) of the following fields will be set
 
 	Database string `json:"database,omitempty"`
@@ -120,6 +120,7 @@ func (e *Entry) SizeOrZero() int64 {
 type MetaEntry struct {
 	FileName string `json:"fileName"`
 	Size     int64  `json:"size"`
+	Checksum string `json:"checksum,omitempty"`
 }
 
 // Size returns the size of the manifest.
@@ -136,18 +137,18 @@ func (m *Manifest) Size() int64 {
 	return size
 }
 
-func (manifest *Manifest) Save(filename string) error {
+func (manifest *Manifest) Save(t Target, name string) error {
 	b, err := json.MarshalIndent(manifest, "", "  ")
 	if err != nil {
 		return fmt.Errorf("create manifest: %v", err)
 	}
 
-	return ioutil.WriteFile(filename, b, 0600)
+	return WriteFile(t, name, b)
 }
 
-// LoadIncremental loads multiple manifest files from a given directory.
-func LoadIncremental(dir string) (*MetaEntry, map[uint64]*Entry, error) {
-	manifests, err := filepath.Glob(filepath.Join(dir, "*.manifest"))
+// LoadIncremental loads multiple manifest files from a given target.
+func LoadIncremental(t Target) (*MetaEntry, map[uint64]*Entry, error) {
+	manifests, err := Glob(t, "*.manifest")
 	if err != nil {
 		return nil, nil, err
 	}
@@ -157,28 +158,26 @@ func LoadIncremental(dir string) (*MetaEntry, map[uint64]*Entry, error) {
 		return nil, shards, nil
 	}
 
+	names, err := t.List("")
+	if err != nil {
+		return nil, nil, err
+	}
+	exists := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		exists[name] = struct{}{}
+	}
+
 	sort.Sort(sort.Reverse(sort.StringSlice(manifests)))
 	var metaEntry MetaEntry
 
 	for _, fileName := range manifests {
-		fi, err := os.Stat(fileName)
-		if err != nil {
-			return nil, nil, err
-		}
-
-		if fi.IsDir() {
-			continue
-		}
-
-		f, err := os.Open(fileName)
+		b, err := ReadFile(t, fileName)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		var manifest Manifest
-		err = json.NewDecoder(f).Decode(&manifest)
-		f.Close()
-		if err != nil {
+		if err := json.Unmarshal(b, &manifest); err != nil {
 			return nil, nil, fmt.Errorf("read manifest: %v", err)
 		}
 
@@ -189,7 +188,7 @@ func LoadIncremental(dir string) (*MetaEntry, map[uint64]*Entry, error) {
 
 		for i := range manifest.Files {
 			sh := manifest.Files[i]
-			if _, err := os.Stat(filepath.Join(dir, sh.FileName)); err != nil {
+			if _, ok := exists[sh.FileName]; !ok {
 				continue
 			}
 
@@ -0,0 +1,405 @@
+package backup_util
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// ShardEntry lists the files of a shard as of a backup. The files which
+// didn't change since the previous backup of the chain stay in the archives
+// of the earlier backups.
+type ShardEntry struct {
+	Database   string      `json:"database"`
+	Policy     string      `json:"policy"`
+	ShardID    uint64      `json:"shardID"`
+	Generation int         `json:"generation"`
+	Files      []FileEntry `json:"files"`
+}
+
+// FileEntry describes a file of a backed up shard.
+type FileEntry struct {
+	// Path of the file in the shard archive, e.g. db/ttl/1/000000001-000000001.tsm.
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+
+	// Hex-encoded SHA-256 of the file.
+	Checksum string `json:"checksum"`
+
+	// Backup file holding the file.
+	Archive string `json:"archive"`
+}
+
+// Chain is a full backup followed by the incremental backups based on it,
+// oldest first.
+type Chain struct {
+	Target    Target
+	Names     []string
+	Manifests []*Manifest
+}
+
+// LoadChain loads the chain of the latest backup in t by following the
+// parents of its manifest. The chain is empty if t holds no manifest.
+func LoadChain(t Target) (*Chain, error) {
+	names, err := Glob(t, "*.manifest")
+	if err != nil {
+		return nil, err
+	} else if len(names) == 0 {
+		return &Chain{Target: t}, nil
+	}
+
+	// Manifests are named after the time of the backup.
+	return loadChain(t, names[len(names)-1])
+}
+
+// loadChain loads the chain ending with the named manifest.
+func loadChain(t Target, name string) (*Chain, error) {
+	c := &Chain{Target: t}
+
+	seen := make(map[string]struct{})
+	for name != "" {
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("backup chain loops at manifest %s", name)
+		}
+		seen[name] = struct{}{}
+
+		m, err := loadManifest(t, name)
+		if os.IsNotExist(err) && len(c.Names) > 0 {
+			return nil, fmt.Errorf("backup chain broken: manifest %s is missing the parent %s", c.Names[0], name)
+		} else if err != nil {
+			return nil, err
+		}
+
+		c.Names = append([]string{name}, c.Names...)
+		c.Manifests = append([]*Manifest{m}, c.Manifests...)
+		name = m.Parent
+	}
+	return c, nil
+}
+
+// loadManifest reads the named manifest of the target.
+func loadManifest(t Target, name string) (*Manifest, error) {
+	b, err := ReadFile(t, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("read manifest %s: %v", name, err)
+	}
+	return &m, nil
+}
+
+// Latest returns the file name of the manifest of the latest backup, or an
+// empty string if the chain is empty.
+func (c *Chain) Latest() string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+	return c.Names[len(c.Names)-1]
+}
+
+// Shard returns the files of a shard as of its latest backup in the chain,
+// or nil if the shard isn't backed up.
+func (c *Chain) Shard(id uint64) *ShardEntry {
+	for i := len(c.Manifests) - 1; i >= 0; i-- {
+		for j := range c.Manifests[i].Shards {
+			if sh := &c.Manifests[i].Shards[j]; sh.ShardID == id {
+				return sh
+			}
+		}
+	}
+	return nil
+}
+
+// Verify checks the meta store backups and that every file of the shards
+// is in its archive with the recorded size and checksum. It returns the
+// problems found.
+func (c *Chain) Verify() []error {
+	var errs []error
+
+	var archives []string
+	files := make(map[string]map[string]FileEntry)
+	for _, m := range c.Manifests {
+		if m.Meta.FileName != "" && m.Meta.Checksum != "" {
+			if sum, err := Checksum(c.Target, m.Meta.FileName); err != nil {
+				errs = append(errs, err)
+			} else if sum != m.Meta.Checksum {
+				errs = append(errs, fmt.Errorf("%s: checksum mismatch", m.Meta.FileName))
+			}
+		}
+
+		for _, sh := range m.Shards {
+			for _, f := range sh.Files {
+				if files[f.Archive] == nil {
+					files[f.Archive] = make(map[string]FileEntry)
+					archives = append(archives, f.Archive)
+				}
+				files[f.Archive][f.Name] = f
+			}
+		}
+	}
+
+	for _, name := range archives {
+		errs = append(errs, verifyArchive(c.Target, name, files[name])...)
+	}
+	return errs
+}
+
+// verifyArchive checks that the named tar archive holds the given files.
+func verifyArchive(t Target, name string, files map[string]FileEntry) []error {
+	f, err := t.Open(name)
+	if err != nil {
+		return []error{err}
+	}
+	defer f.Close()
+
+	var errs []error
+	found := make(map[string]struct{}, len(files))
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return append(errs, fmt.Errorf("%s: %v", name, err))
+		}
+
+		fe, ok := files[hdr.Name]
+		if !ok {
+			continue
+		}
+		found[hdr.Name] = struct{}{}
+
+		h := sha256.New()
+		n, err := io.Copy(h, tr)
+		if err != nil {
+			return append(errs, fmt.Errorf("%s: %v", name, err))
+		}
+		if n != fe.Size || hex.EncodeToString(h.Sum(nil)) != fe.Checksum {
+			errs = append(errs, fmt.Errorf("%s: checksum mismatch for %s", name, hdr.Name))
+		}
+	}
+
+	for fname := range files {
+		if _, ok := found[fname]; !ok {
+			errs = append(errs, fmt.Errorf("%s: missing %s", name, fname))
+		}
+	}
+	return errs
+}
+
+// ShardReader returns a tar archive of the files of a shard, read from the
+// archives of the chain. A file which doesn't match its checksum fails the
+// read.
+func (c *Chain) ShardReader(sh *ShardEntry) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(c.writeShard(pw, sh))
+	}()
+	return pr
+}
+
+// writeShard writes a tar archive of the files of a shard into w.
+func (c *Chain) writeShard(w io.Writer, sh *ShardEntry) error {
+	var archives []string
+	files := make(map[string]map[string]FileEntry)
+	for _, f := range sh.Files {
+		if files[f.Archive] == nil {
+			files[f.Archive] = make(map[string]FileEntry)
+			archives = append(archives, f.Archive)
+		}
+		files[f.Archive][f.Name] = f
+	}
+
+	tw := tar.NewWriter(w)
+	for _, name := range archives {
+		if err := copyArchiveFiles(tw, c.Target, name, files[name]); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// copyArchiveFiles copies the given files of the named tar archive into tw.
+func copyArchiveFiles(tw *tar.Writer, t Target, name string, files map[string]FileEntry) error {
+	f, err := t.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	n := 0
+	tr := tar.NewReader(f)
+	for n < len(files) {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		fe, ok := files[hdr.Name]
+		if !ok {
+			continue
+		}
+		n++
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		h := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(tw, h), tr); err != nil {
+			return err
+		}
+		if hex.EncodeToString(h.Sum(nil)) != fe.Checksum {
+			return fmt.Errorf("%s: checksum mismatch for %s", name, hdr.Name)
+		}
+	}
+
+	if n < len(files) {
+		return fmt.Errorf("%s: %d files missing", name, len(files)-n)
+	}
+	return nil
+}
+
+// Checksum returns the hex-encoded SHA-256 of the named file of the target.
+func Checksum(t Target, name string) (string, error) {
+	f, err := t.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Prune removes the backup sets of the target but the latest keep ones. A
+// backup set is a full backup with the incremental backups based on it. The
+// files of a removed set which are still referenced by a kept set are left
+// in place. The archived WAL segments closed before the oldest kept set are
+// removed as well. It returns the names of the removed files.
+func Prune(t Target, keep int) ([]string, error) {
+	if keep < 1 {
+		return nil, fmt.Errorf("at least one backup set must be kept")
+	}
+
+	names, err := Glob(t, "*.manifest")
+	if err != nil {
+		return nil, err
+	}
+	manifests := make(map[string]*Manifest, len(names))
+	for _, name := range names {
+		if manifests[name], err = loadManifest(t, name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Group the manifests by the full backup at the root of their chain. A
+	// manifest whose parent is missing starts a set of its own.
+	var roots []string
+	sets := make(map[string][]string)
+	for _, name := range names {
+		root := name
+		for i := 0; i < len(names); i++ {
+			parent := manifests[root].Parent
+			if _, ok := manifests[parent]; !ok {
+				break
+			}
+			root = parent
+		}
+		if _, ok := sets[root]; !ok {
+			roots = append(roots, root)
+		}
+		sets[root] = append(sets[root], name)
+	}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+
+	// Manifests are named after the time of the backup.
+	sort.Strings(roots)
+	if len(roots) <= keep {
+		return pruneWAL(t, roots[0], nil)
+	}
+	skip := make(map[string]struct{})
+	for _, root := range roots[len(roots)-keep:] {
+		for _, name := range sets[root] {
+			for _, file := range manifestFiles(name, manifests[name]) {
+				skip[file] = struct{}{}
+			}
+		}
+	}
+
+	var removed []string
+	for _, root := range roots[:len(roots)-keep] {
+		for _, name := range sets[root] {
+			for _, file := range manifestFiles(name, manifests[name]) {
+				if _, ok := skip[file]; ok {
+					continue
+				}
+				if err := t.Remove(file); err != nil && !os.IsNotExist(err) {
+					return removed, err
+				}
+				skip[file] = struct{}{}
+				removed = append(removed, file)
+			}
+		}
+	}
+	return pruneWAL(t, roots[len(roots)-keep], removed)
+}
+
+// pruneWAL removes the archived WAL segments closed before the backup of
+// the named manifest, which can't be replayed anymore. It returns removed
+// with the names of the removed segments.
+func pruneWAL(t Target, name string, removed []string) ([]string, error) {
+	since, err := ManifestTime(name)
+	if err != nil {
+		// The manifest isn't named after the time of its backup.
+		return removed, nil
+	}
+
+	names, err := t.List("wal.")
+	if err != nil {
+		return removed, err
+	}
+	for _, name := range names {
+		_, _, _, _, closed, err := ParseWALFileName(name)
+		if err != nil || !closed.Before(since) {
+			continue
+		}
+		if err := t.Remove(name); err != nil && !os.IsNotExist(err) {
+			return removed, err
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+// manifestFiles returns the names of the files of a backup, the manifest
+// being the last one so that a partially pruned backup is pruned again.
+func manifestFiles(name string, m *Manifest) []string {
+	var files []string
+	if m.Meta.FileName != "" {
+		files = append(files, m.Meta.FileName)
+	}
+	for _, f := range m.Files {
+		files = append(files, f.FileName)
+	}
+	for _, sh := range m.Shards {
+		for _, f := range sh.Files {
+			files = append(files, f.Archive)
+		}
+	}
+	return append(files, name)
+}
@@ -0,0 +1,233 @@
+package backup_util
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Files encrypted by the backup are a header followed by chunks of at most
+// encryptChunkSize bytes sealed with AES-256-GCM. Every chunk is prefixed by
+// its sealed length. The nonce of a chunk is the random prefix of the header
+// followed by the index of the chunk, and the last chunk is authenticated as
+// such so that a truncated file fails to decrypt.
+const (
+	encryptChunkSize   = 64 * 1024
+	encryptNoncePrefix = 8
+)
+
+var encryptMagic = []byte("CNOSENC1")
+
+var (
+	// ErrTruncated is returned when reading an encrypted file which was cut short.
+	ErrTruncated = errors.New("encrypted file is truncated")
+)
+
+// ReadKeyFile reads an encryption key from a file holding either 32 raw bytes
+// or 64 hexadecimal digits.
+func ReadKeyFile(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if text := bytes.TrimSpace(data); len(text) == 2*32 {
+		if key, err := hex.DecodeString(string(text)); err == nil {
+			return key, nil
+		}
+	}
+	if len(data) != 32 {
+		return nil, fmt.Errorf("key file %s must hold 32 bytes or 64 hex digits", path)
+	}
+	return data, nil
+}
+
+// newGCM returns the AEAD sealing the chunks with key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// EncryptWriter encrypts the data written into an underlying writer.
+type EncryptWriter struct {
+	w     io.Writer
+	aead  cipher.AEAD
+	nonce []byte
+	index uint32
+	buf   []byte
+	err   error
+}
+
+// NewEncryptWriter writes the header of an encrypted file into w, and returns
+// a writer encrypting into w with key. The writer must be closed to write the
+// last chunk.
+func NewEncryptWriter(w io.Writer, key []byte) (*EncryptWriter, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce[:encryptNoncePrefix]); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(append(append([]byte{}, encryptMagic...), nonce[:encryptNoncePrefix]...)); err != nil {
+		return nil, err
+	}
+
+	return &EncryptWriter{
+		w:     w,
+		aead:  aead,
+		nonce: nonce,
+		buf:   make([]byte, 0, encryptChunkSize),
+	}, nil
+}
+
+// Write encrypts p into the underlying writer.
+func (w *EncryptWriter) Write(p []byte) (int, error) {
+	var n int
+	for len(p) > 0 {
+		if w.err != nil {
+			return n, w.err
+		}
+
+		// A full chunk is only written once more data comes, since the
+		// last chunk is sealed differently.
+		if len(w.buf) == encryptChunkSize {
+			w.err = w.seal(false)
+			continue
+		}
+
+		m := copy(w.buf[len(w.buf):encryptChunkSize], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		n += m
+		p = p[m:]
+	}
+	return n, w.err
+}
+
+// Close writes the last chunk. It doesn't close the underlying writer.
+func (w *EncryptWriter) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.err = w.seal(true); w.err != nil {
+		return w.err
+	}
+	w.err = errors.New("write to closed encrypted file")
+	return nil
+}
+
+// seal encrypts the buffered chunk into the underlying writer.
+func (w *EncryptWriter) seal(last bool) error {
+	binary.BigEndian.PutUint32(w.nonce[encryptNoncePrefix:], w.index)
+	w.index++
+
+	sealed := w.aead.Seal(nil, w.nonce, w.buf, chunkAD(last))
+	w.buf = w.buf[:0]
+
+	var hdr [4]byte
+	binary.BigEndian.PutUint32(hdr[:], uint32(len(sealed)))
+	if _, err := w.w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := w.w.Write(sealed)
+	return err
+}
+
+// chunkAD returns the additional data authenticating whether a chunk is the
+// last one.
+func chunkAD(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// DecryptReader decrypts the data read from an underlying reader.
+type DecryptReader struct {
+	r     io.Reader
+	aead  cipher.AEAD
+	nonce []byte
+	index uint32
+	buf   []byte
+	done  bool
+}
+
+// NewDecryptReader reads the header of an encrypted file from r, and returns
+// a reader decrypting r with key.
+func NewDecryptReader(r io.Reader, key []byte) (*DecryptReader, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	hdr := make([]byte, len(encryptMagic)+encryptNoncePrefix)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, ErrTruncated
+	} else if !bytes.Equal(hdr[:len(encryptMagic)], encryptMagic) {
+		return nil, errors.New("not an encrypted file")
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	copy(nonce, hdr[len(encryptMagic):])
+	return &DecryptReader{r: r, aead: aead, nonce: nonce}, nil
+}
+
+// Read reads decrypted data into p.
+func (r *DecryptReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// open reads and decrypts the next chunk.
+func (r *DecryptReader) open() error {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r.r, hdr[:]); err != nil {
+		return ErrTruncated
+	}
+	size := binary.BigEndian.Uint32(hdr[:])
+	if size > encryptChunkSize+uint32(r.aead.Overhead()) {
+		return errors.New("corrupt encrypted file")
+	}
+
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(r.r, sealed); err != nil {
+		return ErrTruncated
+	}
+
+	binary.BigEndian.PutUint32(r.nonce[encryptNoncePrefix:], r.index)
+	r.index++
+
+	// A chunk is tried as an inner chunk first, and as the last one if
+	// that fails.
+	buf, err := r.aead.Open(nil, r.nonce, sealed, chunkAD(false))
+	if err != nil {
+		if buf, err = r.aead.Open(sealed[:0], r.nonce, sealed, chunkAD(true)); err != nil {
+			return errors.New("decrypt failed: wrong key or corrupt file")
+		}
+		r.done = true
+	}
+	r.buf = buf
+	return nil
+}
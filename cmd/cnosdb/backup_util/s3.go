@@ -0,0 +1,427 @@
+package backup_util
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultS3Region is the region used to sign the requests if none is set.
+	DefaultS3Region = "us-east-1"
+
+	// DefaultS3PartSize is the size of the parts of the multipart uploads.
+	DefaultS3PartSize = 8 * 1024 * 1024
+
+	// minS3PartSize is the smallest part size accepted by S3.
+	minS3PartSize = 5 * 1024 * 1024
+)
+
+// S3Config holds the settings of an S3-compatible object store.
+type S3Config struct {
+	// Endpoint is the URL of the store, e.g. http://localhost:9000.
+	// It defaults to the AWS endpoint of the region.
+	Endpoint string
+
+	Region string
+
+	// Credentials, read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY if
+	// not set.
+	AccessKey string
+	SecretKey string
+
+	// PartSize is the size of the parts of the multipart uploads.
+	PartSize int
+}
+
+// S3Target stores the files of a backup as the objects of a bucket, under a
+// prefix. Files larger than a part are sent as multipart uploads while they
+// are written, so that no more than a part is held in memory.
+type S3Target struct {
+	Endpoint  *url.URL
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+	PartSize  int
+
+	Client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Target returns a target storing the files under prefix in bucket.
+func NewS3Target(c S3Config, bucket, prefix string) (*S3Target, error) {
+	if c.Region == "" {
+		c.Region = DefaultS3Region
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", c.Region)
+	}
+	if c.AccessKey == "" {
+		c.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if c.SecretKey == "" {
+		c.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if c.PartSize == 0 {
+		c.PartSize = DefaultS3PartSize
+	} else if c.PartSize < minS3PartSize {
+		return nil, fmt.Errorf("s3 part size must be at least %d bytes", minS3PartSize)
+	}
+
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %v", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid s3 endpoint %s", c.Endpoint)
+	}
+
+	return &S3Target{
+		Endpoint:  u,
+		Region:    c.Region,
+		Bucket:    bucket,
+		Prefix:    prefix,
+		AccessKey: c.AccessKey,
+		SecretKey: c.SecretKey,
+		PartSize:  c.PartSize,
+		Client:    http.DefaultClient,
+		now:       time.Now,
+	}, nil
+}
+
+// S3Error is an error returned by the object store.
+type S3Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+// Error returns the string representation of the error.
+func (e *S3Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: %s", http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("s3: %s: %s", e.Code, e.Message)
+}
+
+// key returns the key of the object holding the named file.
+func (t *S3Target) key(name string) string {
+	if t.Prefix == "" {
+		return name
+	}
+	return t.Prefix + "/" + name
+}
+
+// Create returns a writer uploading the named file.
+func (t *S3Target) Create(name string) (io.WriteCloser, error) {
+	return &s3Writer{t: t, key: t.key(name), buf: make([]byte, 0, t.PartSize)}, nil
+}
+
+// Open returns a reader downloading the named file.
+func (t *S3Target) Open(name string) (io.ReadCloser, error) {
+	resp, err := t.do("GET", t.key(name), nil, nil)
+	if err != nil {
+		if e, ok := err.(*S3Error); ok && e.StatusCode == http.StatusNotFound {
+			return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// List returns the sorted names of the files starting with prefix.
+func (t *S3Target) List(prefix string) ([]string, error) {
+	var base string
+	if t.Prefix != "" {
+		base = t.Prefix + "/"
+	}
+
+	var names []string
+	var token string
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {base + prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		var result struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		if err := t.doXML("GET", "", query, nil, &result); err != nil {
+			return nil, err
+		}
+
+		for _, c := range result.Contents {
+			name := strings.TrimPrefix(c.Key, base)
+			if !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Remove deletes the named file.
+func (t *S3Target) Remove(name string) error {
+	resp, err := t.do("DELETE", t.key(name), nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// doXML sends a request and decodes the XML body of the response into v.
+func (t *S3Target) doXML(method, key string, query url.Values, body []byte, v interface{}) error {
+	resp, err := t.do(method, key, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if v == nil {
+		_, err := io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+	return xml.NewDecoder(resp.Body).Decode(v)
+}
+
+// do sends a signed request on the object with key, or on the bucket if key
+// is empty. A response with an error status is returned as an S3Error.
+func (t *S3Target) do(method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u := *t.Endpoint
+	u.Path = path.Join("/", u.Path, t.Bucket, key)
+	if key == "" {
+		u.Path += "/"
+	}
+	u.RawQuery = strings.Replace(query.Encode(), "+", "%20", -1)
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = int64(len(body))
+	t.sign(req, body)
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		e := &S3Error{StatusCode: resp.StatusCode}
+		data, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		xml.Unmarshal(data, e)
+		return nil, e
+	}
+	return resp, nil
+}
+
+// sign signs a request with AWS Signature Version 4.
+func (t *S3Target) sign(req *http.Request, body []byte) {
+	now := t.now().UTC()
+	date := now.Format("20060102")
+	stamp := now.Format("20060102T150405Z")
+
+	sum := sha256.Sum256(body)
+	payload := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+	if t.AccessKey == "" {
+		return
+	}
+
+	headers := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	values := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payload,
+		"x-amz-date":           stamp,
+	}
+	var canonicalHeaders strings.Builder
+	for _, h := range headers {
+		canonicalHeaders.WriteString(h + ":" + values[h] + "\n")
+	}
+	signed := strings.Join(headers, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signed,
+		payload,
+	}, "\n")
+	canonicalSum := sha256.Sum256([]byte(canonical))
+
+	scope := date + "/" + t.Region + "/s3/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(canonicalSum[:])
+
+	key := hmacSHA256([]byte("AWS4"+t.SecretKey), date)
+	key = hmacSHA256(key, t.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		t.AccessKey, scope, signed, hex.EncodeToString(hmacSHA256(key, toSign))))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data with key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Writer uploads an object. The data is buffered up to a part, and sent
+// in a single request if it fits, or as a multipart upload otherwise.
+type s3Writer struct {
+	t        *S3Target
+	key      string
+	buf      []byte
+	uploadID string
+	etags    []string
+	err      error
+}
+
+// Write buffers p, and uploads every full part.
+func (w *s3Writer) Write(p []byte) (int, error) {
+	var n int
+	for len(p) > 0 {
+		if w.err != nil {
+			return n, w.err
+		}
+
+		if len(w.buf) == cap(w.buf) {
+			w.err = w.uploadPart()
+			continue
+		}
+
+		m := copy(w.buf[len(w.buf):cap(w.buf)], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		n += m
+		p = p[m:]
+	}
+	return n, w.err
+}
+
+// Close uploads the buffered data and completes the upload. The upload is
+// aborted if it failed.
+func (w *s3Writer) Close() error {
+	if w.err != nil {
+		w.abort()
+		return w.err
+	}
+
+	if w.uploadID == "" {
+		w.err = w.t.doXML("PUT", w.key, nil, w.buf, nil)
+	} else if w.err = w.uploadPart(); w.err == nil {
+		w.err = w.complete()
+	}
+	if w.err != nil {
+		w.abort()
+		return w.err
+	}
+
+	w.err = errors.New("write to closed s3 object")
+	return nil
+}
+
+// uploadPart uploads the buffered data as the next part, starting the
+// multipart upload if needed.
+func (w *s3Writer) uploadPart() error {
+	if w.uploadID == "" {
+		var result struct {
+			UploadID string `xml:"UploadId"`
+		}
+		if err := w.t.doXML("POST", w.key, url.Values{"uploads": {""}}, nil, &result); err != nil {
+			return err
+		}
+		w.uploadID = result.UploadID
+	}
+
+	query := url.Values{
+		"partNumber": {strconv.Itoa(len(w.etags) + 1)},
+		"uploadId":   {w.uploadID},
+	}
+	resp, err := w.t.do("PUT", w.key, query, w.buf)
+	if err != nil {
+		return err
+	}
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	w.etags = append(w.etags, resp.Header.Get("ETag"))
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// complete completes the multipart upload with the uploaded parts. The
+// store may fail the upload after it sent the 200 status, so the response
+// is an error if its root element is Error.
+func (w *s3Writer) complete() error {
+	type part struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	}
+	var body struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []part   `xml:"Part"`
+	}
+	for i, etag := range w.etags {
+		body.Parts = append(body.Parts, part{PartNumber: i + 1, ETag: etag})
+	}
+
+	data, err := xml.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if err := w.t.doXML("POST", w.key, url.Values{"uploadId": {w.uploadID}}, data, &result); err != nil {
+		return err
+	} else if result.XMLName.Local == "Error" {
+		return &S3Error{StatusCode: http.StatusOK, Code: result.Code, Message: result.Message}
+	} else if result.XMLName.Local != "CompleteMultipartUploadResult" {
+		return fmt.Errorf("s3: unexpected complete multipart upload response %q", result.XMLName.Local)
+	}
+	return nil
+}
+
+// abort aborts the multipart upload, if any.
+func (w *s3Writer) abort() {
+	if w.uploadID == "" {
+		return
+	}
+	if resp, err := w.t.do("DELETE", w.key, url.Values{"uploadId": {w.uploadID}}, nil); err == nil {
+		resp.Body.Close()
+	}
+	w.uploadID = ""
+}
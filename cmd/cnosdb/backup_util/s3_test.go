@@ -0,0 +1,327 @@
+package backup_util
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// s3Server is an S3 stand-in holding the objects of a single bucket.
+type s3Server struct {
+	*httptest.Server
+	bucket string
+
+	// pageSize is the number of keys of a page of a listing.
+	pageSize int
+
+	// completeError is returned with a 200 status by the requests completing
+	// the multipart uploads, if set.
+	completeError string
+
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+	nextID  int
+	puts    int
+}
+
+func newS3Server() *s3Server {
+	s := &s3Server{
+		bucket:   "backups",
+		pageSize: 1000,
+		objects:  make(map[string][]byte),
+		uploads:  make(map[string]map[int][]byte),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *s3Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		s.error(w, http.StatusForbidden, "AccessDenied")
+		return
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if parts[0] != s.bucket || len(parts) != 2 {
+		s.error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	key, query := parts[1], r.URL.Query()
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case key == "" && r.Method == "GET":
+		s.list(w, query)
+	case r.Method == "POST" && query.Get("uploads") == "" && query["uploads"] != nil:
+		s.nextID++
+		id := strconv.Itoa(s.nextID)
+		s.uploads[id] = make(map[int][]byte)
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+	case r.Method == "PUT" && query.Get("uploadId") != "":
+		upload, ok := s.uploads[query.Get("uploadId")]
+		if !ok {
+			s.error(w, http.StatusNotFound, "NoSuchUpload")
+			return
+		}
+		n, _ := strconv.Atoi(query.Get("partNumber"))
+		upload[n] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"etag%d"`, n))
+	case r.Method == "POST" && query.Get("uploadId") != "":
+		s.complete(w, key, query.Get("uploadId"), body)
+	case r.Method == "DELETE" && query.Get("uploadId") != "":
+		delete(s.uploads, query.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == "PUT":
+		s.puts++
+		s.objects[key] = body
+	case r.Method == "GET":
+		data, ok := s.objects[key]
+		if !ok {
+			s.error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Write(data)
+	case r.Method == "DELETE":
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		s.error(w, http.StatusBadRequest, "InvalidRequest")
+	}
+}
+
+// list writes a page of the keys starting with the prefix of the query.
+func (s *s3Server) list(w http.ResponseWriter, query map[string][]string) {
+	prefix := ""
+	if v := query["prefix"]; len(v) > 0 {
+		prefix = v[0]
+	}
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	start := 0
+	if v := query["continuation-token"]; len(v) > 0 {
+		start, _ = strconv.Atoi(v[0])
+	}
+	end := start + s.pageSize
+	if end > len(keys) {
+		end = len(keys)
+	}
+
+	fmt.Fprint(w, "<ListBucketResult>")
+	for _, key := range keys[start:end] {
+		fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+	}
+	if end < len(keys) {
+		fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%d</NextContinuationToken>", end)
+	}
+	fmt.Fprint(w, "</ListBucketResult>")
+}
+
+// complete assembles the parts of a multipart upload into the object.
+func (s *s3Server) complete(w http.ResponseWriter, key, id string, body []byte) {
+	upload, ok := s.uploads[id]
+	if !ok {
+		s.error(w, http.StatusNotFound, "NoSuchUpload")
+		return
+	}
+	if s.completeError != "" {
+		fmt.Fprintf(w, "<Error><Code>%s</Code><Message>failed</Message></Error>", s.completeError)
+		return
+	}
+
+	var req struct {
+		Parts []struct {
+			PartNumber int    `xml:"PartNumber"`
+			ETag       string `xml:"ETag"`
+		} `xml:"Part"`
+	}
+	if err := xml.Unmarshal(body, &req); err != nil {
+		s.error(w, http.StatusBadRequest, "MalformedXML")
+		return
+	}
+	var data []byte
+	for i, p := range req.Parts {
+		if p.PartNumber != i+1 || p.ETag != fmt.Sprintf(`"etag%d"`, p.PartNumber) {
+			s.error(w, http.StatusBadRequest, "InvalidPart")
+			return
+		}
+		data = append(data, upload[p.PartNumber]...)
+	}
+	s.objects[key] = data
+	delete(s.uploads, id)
+	fmt.Fprintf(w, "<CompleteMultipartUploadResult><Key>%s</Key></CompleteMultipartUploadResult>", key)
+}
+
+func (s *s3Server) error(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, http.StatusText(status))
+}
+
+// newS3Target returns a target storing the files under prefix on the server.
+func newS3Target(t *testing.T, s *s3Server, prefix string) *S3Target {
+	t.Helper()
+	target, err := NewS3Target(S3Config{Endpoint: s.URL, AccessKey: "key", SecretKey: "secret"}, s.bucket, prefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return target
+}
+
+// writeFile writes data to the named file of the target.
+func writeFile(target *S3Target, name string, data []byte) error {
+	w, err := target.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func TestS3Target_Put(t *testing.T) {
+	s := newS3Server()
+	defer s.Close()
+	target := newS3Target(t, s, "cnosdb")
+
+	if err := writeFile(target, "meta.00", []byte("meta")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s.puts != 1 || len(s.uploads) != 0 {
+		t.Fatalf("got %d puts and %d uploads, exp a single put", s.puts, len(s.uploads))
+	} else if data := string(s.objects["cnosdb/meta.00"]); data != "meta" {
+		t.Fatalf("got object %q, exp %q", data, "meta")
+	}
+
+	r, err := target.Open("meta.00")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer r.Close()
+	if data, err := ioutil.ReadAll(r); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != "meta" {
+		t.Fatalf("got file %q, exp %q", data, "meta")
+	}
+
+	if _, err := target.Open("meta.01"); !os.IsNotExist(err) {
+		t.Fatalf("got error %v, exp not exist", err)
+	}
+}
+
+func TestS3Target_Multipart(t *testing.T) {
+	s := newS3Server()
+	defer s.Close()
+	target := newS3Target(t, s, "")
+	target.PartSize = 4
+
+	data := []byte("0123456789")
+	if err := writeFile(target, "db.ttl.00001.00", data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if s.puts != 0 || len(s.uploads) != 0 {
+		t.Fatalf("got %d puts and %d open uploads, exp a completed upload", s.puts, len(s.uploads))
+	} else if got := string(s.objects["db.ttl.00001.00"]); got != string(data) {
+		t.Fatalf("got object %q, exp %q", got, data)
+	}
+}
+
+func TestS3Target_Multipart_CompleteError(t *testing.T) {
+	s := newS3Server()
+	defer s.Close()
+	s.completeError = "InternalError"
+	target := newS3Target(t, s, "")
+	target.PartSize = 4
+
+	// The upload fails although the complete request returned 200, and it
+	// is aborted.
+	err := writeFile(target, "db.ttl.00001.00", []byte("0123456789"))
+	if e, ok := err.(*S3Error); !ok || e.Code != "InternalError" {
+		t.Fatalf("got error %v, exp InternalError", err)
+	}
+	if len(s.uploads) != 0 {
+		t.Fatalf("got %d open uploads, exp 0", len(s.uploads))
+	} else if _, ok := s.objects["db.ttl.00001.00"]; ok {
+		t.Fatal("unexpected object of the failed upload")
+	}
+}
+
+func TestS3Target_List(t *testing.T) {
+	s := newS3Server()
+	defer s.Close()
+	s.pageSize = 2
+	target := newS3Target(t, s, "cnosdb")
+
+	for _, key := range []string{
+		"cnosdb/meta.01",
+		"cnosdb/meta.00",
+		"cnosdb/db.ttl.00001.00",
+		"cnosdb/meta.02",
+		"cnosdb/wal/meta.03",
+		"other/meta.04",
+	} {
+		s.objects[key] = nil
+	}
+
+	// The files are listed over several pages, without the nested ones.
+	names, err := target.List("meta.")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if exp := []string{"meta.00", "meta.01", "meta.02"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("got files %v, exp %v", names, exp)
+	}
+
+	if names, err := target.List("none"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(names) != 0 {
+		t.Fatalf("got files %v, exp none", names)
+	}
+}
+
+func TestS3Target_Remove(t *testing.T) {
+	s := newS3Server()
+	defer s.Close()
+	target := newS3Target(t, s, "cnosdb")
+	s.objects["cnosdb/meta.00"] = []byte("meta")
+	s.objects["cnosdb/meta.01"] = []byte("meta")
+
+	if err := target.Remove("meta.00"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if names, err := target.List(""); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if exp := []string{"meta.01"}; !reflect.DeepEqual(names, exp) {
+		t.Fatalf("got files %v, exp %v", names, exp)
+	}
+}
+
+func TestS3Target_Error(t *testing.T) {
+	s := newS3Server()
+	defer s.Close()
+	target := newS3Target(t, s, "")
+	target.Bucket = "missing"
+
+	_, err := target.List("")
+	if e, ok := err.(*S3Error); !ok || e.StatusCode != http.StatusNotFound || e.Code != "NoSuchBucket" {
+		t.Fatalf("got error %v, exp NoSuchBucket", err)
+	}
+}
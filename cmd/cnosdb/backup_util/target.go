@@ -0,0 +1,320 @@
+package backup_util
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	gzip "github.com/klauspost/pgzip"
+)
+
+// Target is a destination of backups, holding a flat set of named files.
+type Target interface {
+	// Create returns a writer storing the named file. The file only
+	// exists once the writer is closed.
+	Create(name string) (io.WriteCloser, error)
+
+	// Open returns a reader of the named file. The error satisfies
+	// os.IsNotExist if there is no such file.
+	Open(name string) (io.ReadCloser, error)
+
+	// List returns the sorted names of the files starting with prefix.
+	List(prefix string) ([]string, error)
+
+	// Remove deletes the named file.
+	Remove(name string) error
+}
+
+// compressComment marks the gzip streams written by the backup, telling them
+// apart from files which are gzipped archives themselves.
+const compressComment = "cnosdb-backup"
+
+// IsS3Location returns true if location is an s3://bucket/prefix URL.
+func IsS3Location(location string) bool {
+	return strings.HasPrefix(location, "s3://")
+}
+
+// TargetOptions holds the settings of the target of a backup.
+type TargetOptions struct {
+	// Compress gzips the files written to the target.
+	Compress bool
+
+	// KeyFile holds the key encrypting the files of the target.
+	KeyFile string
+
+	S3 S3Config
+}
+
+// NewTarget returns the target at location, which is either a local
+// directory or an s3://bucket/prefix URL. The files are compressed and
+// encrypted as set by the options.
+func NewTarget(location string, opt TargetOptions) (Target, error) {
+	var t Target
+	if IsS3Location(location) {
+		bucket := strings.TrimPrefix(location, "s3://")
+		var prefix string
+		if i := strings.Index(bucket, "/"); i >= 0 {
+			bucket, prefix = bucket[:i], strings.Trim(bucket[i+1:], "/")
+		}
+		if bucket == "" {
+			return nil, fmt.Errorf("bucket required in %s", location)
+		}
+
+		s3, err := NewS3Target(opt.S3, bucket, prefix)
+		if err != nil {
+			return nil, err
+		}
+		t = s3
+	} else {
+		t = &LocalTarget{Dir: location}
+	}
+
+	var key []byte
+	if opt.KeyFile != "" {
+		var err error
+		if key, err = ReadKeyFile(opt.KeyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return &codecTarget{Target: t, compress: opt.Compress, key: key}, nil
+}
+
+// LocalTarget stores the files of a backup in a local directory.
+type LocalTarget struct {
+	Dir string
+}
+
+// Create returns a writer of a temporary file, renamed to name on close.
+func (t *LocalTarget) Create(name string) (io.WriteCloser, error) {
+	path := filepath.Join(t.Dir, name)
+	f, err := os.OpenFile(path+Suffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &localFile{File: f, path: path}, nil
+}
+
+// Open opens the named file.
+func (t *LocalTarget) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(t.Dir, name))
+}
+
+// List returns the names of the files of the directory starting with prefix,
+// leaving out the files being written.
+func (t *LocalTarget) List(prefix string) ([]string, error) {
+	fis, err := ioutil.ReadDir(t.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, fi := range fis {
+		name := fi.Name()
+		if !fi.IsDir() && strings.HasPrefix(name, prefix) && !strings.HasSuffix(name, Suffix) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// Remove removes the named file.
+func (t *LocalTarget) Remove(name string) error {
+	return os.Remove(filepath.Join(t.Dir, name))
+}
+
+// localFile is a file renamed to its final path once it's written.
+type localFile struct {
+	*os.File
+	path string
+}
+
+// Close syncs and closes the file, and renames it.
+func (f *localFile) Close() error {
+	if err := f.File.Sync(); err != nil {
+		f.File.Close()
+		return err
+	}
+	if err := f.File.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.File.Name(), f.path)
+}
+
+// codecTarget compresses and encrypts the files written to a target, and
+// decodes the files read from it. The encoding of a file is detected from
+// its first bytes, so that a target may hold files written with different
+// options.
+type codecTarget struct {
+	Target
+	compress bool
+	key      []byte
+}
+
+// Create returns a writer encoding the file.
+func (t *codecTarget) Create(name string) (io.WriteCloser, error) {
+	w, err := t.Target.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	closers := []io.Closer{w}
+	var out io.Writer = w
+	if t.key != nil {
+		ew, err := NewEncryptWriter(out, t.key)
+		if err != nil {
+			w.Close()
+			return nil, err
+		}
+		out = ew
+		closers = append([]io.Closer{ew}, closers...)
+	}
+	if t.compress {
+		zw := gzip.NewWriter(out)
+		zw.Comment = compressComment
+		out = zw
+		closers = append([]io.Closer{zw}, closers...)
+	}
+	return &chainWriter{Writer: out, closers: closers}, nil
+}
+
+// Open returns a reader decoding the file.
+func (t *codecTarget) Open(name string) (io.ReadCloser, error) {
+	rc, err := t.Target.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(rc)
+	var r io.Reader = br
+	if magic, _ := br.Peek(len(encryptMagic)); bytes.Equal(magic, encryptMagic) {
+		if t.key == nil {
+			rc.Close()
+			return nil, fmt.Errorf("%s is encrypted, a key file is required", name)
+		}
+		dr, err := NewDecryptReader(br, t.key)
+		if err != nil {
+			rc.Close()
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		br = bufio.NewReader(dr)
+		r = br
+	}
+	if isCompressed(br) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			rc.Close()
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		r = zr
+	}
+	return &chainReader{Reader: r, Closer: rc}, nil
+}
+
+// isCompressed returns true if the reader starts with a gzip header written
+// by the backup: the gzip magic, the comment flag alone and the comment.
+func isCompressed(br *bufio.Reader) bool {
+	hdr, _ := br.Peek(10 + len(compressComment) + 1)
+	if len(hdr) < 10+len(compressComment)+1 {
+		return false
+	}
+	return hdr[0] == 0x1f && hdr[1] == 0x8b && hdr[3] == 0x10 &&
+		string(hdr[10:]) == compressComment+"\x00"
+}
+
+// chainWriter closes a stack of writers, innermost first.
+type chainWriter struct {
+	io.Writer
+	closers []io.Closer
+}
+
+// Close closes every writer, and returns the first error.
+func (w *chainWriter) Close() error {
+	var err error
+	for _, c := range w.closers {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// chainReader reads through decoders and closes the underlying file.
+type chainReader struct {
+	io.Reader
+	io.Closer
+}
+
+// Glob returns the sorted names of the files of the target matching pattern,
+// using the syntax of path.Match.
+func Glob(t Target, pattern string) ([]string, error) {
+	prefix := pattern
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		prefix = pattern[:i]
+	}
+
+	names, err := t.List(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, name := range names {
+		if ok, err := path.Match(pattern, name); err != nil {
+			return nil, err
+		} else if ok {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// ReadFile returns the content of the named file of the target.
+func ReadFile(t Target, name string) ([]byte, error) {
+	r, err := t.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// WriteFile writes data to the named file of the target.
+func WriteFile(t Target, name string, data []byte) error {
+	w, err := t.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// CopyFile copies a local file to the named file of the target.
+func CopyFile(t Target, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := t.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,85 @@
+package backup_util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cnosdatabase/db/tsdb/engine/tsm1"
+)
+
+// WALFilePattern is the pattern of the archived WAL segments shipped to a
+// backup. They follow the scheme wal.<database>.<ttl>.<shardID>.<segment>,
+// the segment being named after the time it was closed.
+const WALFilePattern = "wal.%s.%s.%05d.%s"
+
+// WALFileName returns the name of an archived WAL segment of a shard.
+func WALFileName(db, ttl string, id uint64, segment string) string {
+	return fmt.Sprintf(WALFilePattern, db, ttl, id, segment)
+}
+
+// ParseWALFileName returns the shard and the archived segment of the named
+// WAL file, and the time the segment was closed.
+func ParseWALFileName(name string) (db, ttl string, id uint64, segment string, closed time.Time, err error) {
+	// The segment holds the only dot of the name which isn't a separator,
+	// while the database may hold more of them.
+	parts := strings.Split(name, ".")
+	if len(parts) < 6 || parts[0] != "wal" {
+		return "", "", 0, "", time.Time{}, fmt.Errorf("invalid WAL file name: %s", name)
+	}
+	n := len(parts)
+	segment = parts[n-2] + "." + parts[n-1]
+	if closed, err = tsm1.ParseArchivedSegmentName(segment); err != nil {
+		return "", "", 0, "", time.Time{}, err
+	}
+	if id, err = strconv.ParseUint(parts[n-3], 10, 64); err != nil {
+		return "", "", 0, "", time.Time{}, fmt.Errorf("invalid WAL file name: %s", name)
+	}
+	return strings.Join(parts[1:n-4], "."), parts[n-4], id, segment, closed, nil
+}
+
+// WALFiles returns the names of the WAL segments of a shard closed after
+// since and no later than until, in the order they were closed.
+func WALFiles(t Target, db, ttl string, id uint64, since, until time.Time) ([]string, error) {
+	names, err := t.List(fmt.Sprintf("wal.%s.%s.%05d.", db, ttl, id))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, name := range names {
+		fdb, fttl, fid, _, closed, err := ParseWALFileName(name)
+		if err != nil || fdb != db || fttl != ttl || fid != id {
+			continue
+		}
+		if closed.After(since) && !closed.After(until) {
+			files = append(files, name)
+		}
+	}
+
+	// Segments are named after the time they were closed.
+	return files, nil
+}
+
+// ManifestTime returns the time of the backup of the named manifest.
+func ManifestTime(name string) (time.Time, error) {
+	return time.Parse(PortableFileNamePattern, strings.TrimSuffix(name, ".manifest"))
+}
+
+// LoadChainUntil loads the chain of the latest backup in t made no later
+// than until. The chain is empty if t holds no such backup.
+func LoadChainUntil(t Target, until time.Time) (*Chain, error) {
+	names, err := Glob(t, "*.manifest")
+	if err != nil {
+		return nil, err
+	}
+
+	// Manifests are named after the time of the backup.
+	for i := len(names) - 1; i >= 0; i-- {
+		if tm, err := ManifestTime(names[i]); err == nil && !tm.After(until) {
+			return loadChain(t, names[i])
+		}
+	}
+	return &Chain{Target: t}, nil
+}
@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cnosdatabase/cnosdb/cmd/cnosdb/backup_util"
 	"github.com/cnosdatabase/cnosdb/meta"
@@ -21,9 +22,13 @@ import (
 	"github.com/spf13/cobra"
 
 	tarstream "github.com/cnosdatabase/db/pkg/tar"
+	"github.com/cnosdatabase/db/tsdb/engine/tsm1"
 )
 
-var restore_examples = `  cnosdb restore`
+var restore_examples = `  cnosdb restore
+  cnosdb restore --verify /var/backups/cnosdb
+  cnosdb restore --online --s3-endpoint http://localhost:9000 s3://backups/cnosdb
+  cnosdb restore --online --until 2021-10-10T12:12:00Z /var/backups/cnosdb`
 
 // options represents the program execution for "cnosdb restore".
 type options struct {
@@ -51,13 +56,30 @@ type options struct {
 	manifestMeta        *backup_util.MetaEntry
 	manifestFiles       map[uint64]*backup_util.Entry
 
+	// verify only checks the backup chain instead of restoring it.
+	verify bool
+
+	// chain holds the files of the shards as of the latest backup, it is
+	// empty for backups without a manifest.
+	chain *backup_util.Chain
+
+	// until restores the latest backup made no later than until, and
+	// replays the archived WAL segments closed since then up to until.
+	untilArg string
+	until    time.Time
+	waldir   string
+
+	// target holds the backup files.
+	target        backup_util.Target
+	targetOptions backup_util.TargetOptions
+
 	// TODO: when the new meta stuff is done this should not be exported or be gone
 	MetaConfig *meta.Config
 
 	shardIDMap map[uint64]uint64
 }
 
-var env = options{}
+var env = options{Stdout: os.Stdout, Stderr: os.Stderr}
 
 func GetCommand() *cobra.Command {
 	c := &cobra.Command{
@@ -84,9 +106,37 @@ func GetCommand() *cobra.Command {
 				return fmt.Errorf("path with backup files required")
 			}
 
-			fi, err := os.Stat(env.backupFilesPath)
-			if err != nil || !fi.IsDir() {
-				return fmt.Errorf("backup path should be a valid directory: %s", env.backupFilesPath)
+			if !backup_util.IsS3Location(env.backupFilesPath) {
+				fi, err := os.Stat(env.backupFilesPath)
+				if err != nil || !fi.IsDir() {
+					return fmt.Errorf("backup path should be a valid directory: %s", env.backupFilesPath)
+				}
+			}
+
+			var err error
+			if env.target, err = backup_util.NewTarget(env.backupFilesPath, env.targetOptions); err != nil {
+				return err
+			}
+			if env.untilArg != "" {
+				if env.until, err = time.Parse(time.RFC3339, env.untilArg); err != nil {
+					return err
+				}
+				if env.portable {
+					return errors.New("-until isn't supported with -portable")
+				}
+				if env.chain, err = backup_util.LoadChainUntil(env.target, env.until); err != nil {
+					return err
+				} else if len(env.chain.Manifests) == 0 {
+					return fmt.Errorf("no backup made before %s in: %s", env.untilArg, env.backupFilesPath)
+				}
+			} else if env.chain, err = backup_util.LoadChain(env.target); err != nil {
+				return err
+			}
+			if env.verify {
+				if len(env.chain.Manifests) == 0 {
+					return fmt.Errorf("no manifest files found in: %s", env.backupFilesPath)
+				}
+				return nil
 			}
 
 			if env.portable || env.online {
@@ -106,7 +156,7 @@ func GetCommand() *cobra.Command {
 
 				if env.portable {
 					var err error
-					env.manifestMeta, env.manifestFiles, err = backup_util.LoadIncremental(env.backupFilesPath)
+					env.manifestMeta, env.manifestFiles, err = backup_util.LoadIncremental(env.target)
 					if err != nil {
 						return fmt.Errorf("restore failed while processing manifest files: %s", err.Error())
 					} else if env.manifestMeta == nil {
@@ -125,6 +175,10 @@ func GetCommand() *cobra.Command {
 					return fmt.Errorf("-datadir is required to restore")
 				}
 
+				if env.untilArg != "" && env.datadir != "" && env.waldir == "" {
+					return fmt.Errorf("-waldir is required to restore to a point in time")
+				}
+
 				if env.shard != 0 {
 					if env.destinationDatabase == "" {
 						return fmt.Errorf("-destinationDatabase is required to restore shard")
@@ -144,7 +198,9 @@ func GetCommand() *cobra.Command {
 			env.StdoutLogger = log.New(env.Stdout, "", log.LstdFlags)
 			env.StderrLogger = log.New(env.Stderr, "", log.LstdFlags)
 
-			if env.portable {
+			if env.verify {
+				return env.runVerify()
+			} else if env.portable {
 				return env.runOnlinePortable()
 			} else if env.online {
 				return env.runOnlineLegacy()
@@ -157,6 +213,7 @@ func GetCommand() *cobra.Command {
 	c.Flags().StringVar(&env.host, "host", "localhost:8088", "CnosDB host to where the data will be restored. Optional. Defaults to 127.0.0.1:8088.")
 	c.Flags().StringVar(&env.metadir, "metadir", "", "")
 	c.Flags().StringVar(&env.datadir, "datadir", "", "")
+	c.Flags().StringVar(&env.waldir, "waldir", "", "WAL directory the archived WAL segments are restored into with '--until'. Required for offline point-in-time restores.")
 	c.Flags().StringVar(&env.sourceDatabase, "db", "", "CnosDB database name to be restored from the backup. Optional. If not specified, all databases are backed up.")
 	c.Flags().StringVar(&env.destinationDatabase, "newdb", "", "Name of the CnosDB OSS database into which the archived data will be imported on the target system. Optional."+
 		" If not given, then the value of '--db <db_name>' is used.  The new database name must be unique to the target system.")
@@ -165,6 +222,11 @@ func GetCommand() *cobra.Command {
 		" Requires that '-ttl <ttl_name>' is set. If not given, the '--ttl <ttl_name>' value is used.")
 	c.Flags().Uint64Var(&env.shard, "shard", 0, "Identifier of the shard to be restored. Optional. If specified, then '-db <db_name>' and '-0ttl <ttl_name>' are required.")
 	c.Flags().BoolVar(&env.online, "online", false, "")
+	c.Flags().BoolVar(&env.verify, "verify", false, "Check the checksums of the files of the latest backup chain in PATH without restoring it.")
+	c.Flags().StringVar(&env.untilArg, "until", "", "Restore the data as of this timestamp (RFC3339 format): the latest backup made before it, followed by the WAL segments shipped with 'backup --wal' and closed up to it. Optional.")
+	c.Flags().StringVar(&env.targetOptions.KeyFile, "encryption-key-file", "", "File holding the AES-256 key the backup files were encrypted with. Required for encrypted backups.")
+	c.Flags().StringVar(&env.targetOptions.S3.Endpoint, "s3-endpoint", "", "URL of the S3-compatible store when PATH is s3://bucket/prefix. Optional. Defaults to AWS. Credentials are read from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.")
+	c.Flags().StringVar(&env.targetOptions.S3.Region, "s3-region", backup_util.DefaultS3Region, "Region of the S3-compatible store.")
 
 	// Continue on flag errors.
 	c.SetFlagErrorFunc(func(command *cobra.Command, err error) error {
@@ -191,6 +253,22 @@ func (cmd *options) runOffline() error {
 	return nil
 }
 
+// runVerify checks that the files of every backup of the chain are intact.
+func (cmd *options) runVerify() error {
+	cmd.StdoutLogger.Printf("Verifying backup chain %s", strings.Join(cmd.chain.Names, " -> "))
+
+	errs := cmd.chain.Verify()
+	for _, err := range errs {
+		cmd.StderrLogger.Printf("verify: %v", err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("backup chain is corrupt: %d errors", len(errs))
+	}
+
+	cmd.StdoutLogger.Printf("Backup chain of %d backups verified", len(cmd.chain.Names))
+	return nil
+}
+
 func (cmd *options) runOnlinePortable() error {
 	err := cmd.updateMetaPortable()
 	if err != nil {
@@ -223,23 +301,18 @@ func (cmd *options) runOnlineLegacy() error {
 // cluster and replaces the root metadata.
 func (cmd *options) unpackMeta() error {
 	// find the meta file
-	metaFiles, err := filepath.Glob(filepath.Join(cmd.backupFilesPath, backup_util.Metafile+".*"))
+	latest, err := cmd.metaFile()
 	if err != nil {
 		return err
 	}
 
-	if len(metaFiles) == 0 {
-		return fmt.Errorf("no metastore backups in %s", cmd.backupFilesPath)
-	}
-
-	latest := metaFiles[len(metaFiles)-1]
-
 	fmt.Fprintf(cmd.Stdout, "Using metastore snapshot: %v\n", latest)
 	// Read the metastore backup
-	f, err := os.Open(latest)
+	f, err := cmd.target.Open(latest)
 	if err != nil {
 		return err
 	}
+	defer f.Close()
 
 	var buf bytes.Buffer
 	if _, err := io.Copy(&buf, f); err != nil {
@@ -319,11 +392,31 @@ func (cmd *options) unpackMeta() error {
 	return nil
 }
 
+// metaFile returns the name of the metastore backup to restore, the one of
+// the backup restored to a point in time or the latest one.
+func (cmd *options) metaFile() (string, error) {
+	if !cmd.until.IsZero() {
+		m := cmd.chain.Manifests[len(cmd.chain.Manifests)-1]
+		if m.Meta.FileName == "" {
+			return "", fmt.Errorf("no metastore backup in %s", cmd.chain.Latest())
+		}
+		return m.Meta.FileName, nil
+	}
+
+	metaFiles, err := backup_util.Glob(cmd.target, backup_util.Metafile+".*")
+	if err != nil {
+		return "", err
+	}
+
+	if len(metaFiles) == 0 {
+		return "", fmt.Errorf("no metastore backups in %s", cmd.backupFilesPath)
+	}
+	return metaFiles[len(metaFiles)-1], nil
+}
+
 func (cmd *options) updateMetaPortable() error {
 	var metaBytes []byte
-	fileName := filepath.Join(cmd.backupFilesPath, cmd.manifestMeta.FileName)
-
-	fileBytes, err := ioutil.ReadFile(fileName)
+	fileBytes, err := backup_util.ReadFile(cmd.target, cmd.manifestMeta.FileName)
 	if err != nil {
 		return err
 	}
@@ -355,18 +448,17 @@ func (cmd *options) updateMetaLegacy() error {
 	var metaBytes []byte
 
 	// find the meta file
-	metaFiles, err := filepath.Glob(filepath.Join(cmd.backupFilesPath, backup_util.Metafile+".*"))
+	fileName, err := cmd.metaFile()
 	if err != nil {
 		return err
 	}
-
-	if len(metaFiles) == 0 {
-		return fmt.Errorf("no metastore backups in %s", cmd.backupFilesPath)
-	}
-
-	fileName := metaFiles[len(metaFiles)-1]
 	cmd.StdoutLogger.Printf("Using metastore snapshot: %v\n", fileName)
-	metaBytes, err = backup_util.GetMetaBytes(fileName)
+	f, err := cmd.target.Open(fileName)
+	if err != nil {
+		return err
+	}
+	metaBytes, err = backup_util.GetMetaBytes(f)
+	f.Close()
 	if err != nil {
 		return err
 	}
@@ -399,9 +491,8 @@ func (cmd *options) uploadShardsPortable() error {
 						continue
 					}
 					cmd.StdoutLogger.Printf("Restoring shard %d live from backup %s\n", file.ShardID, file.FileName)
-					f, err := os.Open(filepath.Join(cmd.backupFilesPath, file.FileName))
+					f, err := cmd.target.Open(file.FileName)
 					if err != nil {
-						f.Close()
 						return err
 					}
 					gr, err := gzip.NewReader(f)
@@ -430,9 +521,9 @@ func (cmd *options) uploadShardsPortable() error {
 // unpackFiles will look for backup files matching the pattern and restore them to the data dir
 func (cmd *options) uploadShardsLegacy() error {
 	// find the destinationDatabase backup files
-	pat := fmt.Sprintf("%s.*", filepath.Join(cmd.backupFilesPath, cmd.sourceDatabase))
+	pat := fmt.Sprintf("%s.*", cmd.sourceDatabase)
 	cmd.StdoutLogger.Printf("Restoring live from backup %s\n", pat)
-	backupFiles, err := filepath.Glob(pat)
+	backupFiles, err := backup_util.Glob(cmd.target, pat)
 	if err != nil {
 		return err
 	}
@@ -440,8 +531,9 @@ func (cmd *options) uploadShardsLegacy() error {
 		return fmt.Errorf("no backup files in %s", cmd.backupFilesPath)
 	}
 
+	restored := make(map[uint64]struct{})
 	for _, fn := range backupFiles {
-		parts := strings.Split(filepath.Base(fn), ".")
+		parts := strings.Split(fn, ".")
 
 		if len(parts) != 4 {
 			cmd.StderrLogger.Printf("Skipping mis-named backup file: %s", fn)
@@ -458,7 +550,30 @@ func (cmd *options) uploadShardsLegacy() error {
 			cmd.StdoutLogger.Printf("Meta info not found for shard %d. Skipping shard file %s", shardID, fn)
 			continue
 		}
-		f, err := os.Open(fn)
+
+		// Shards of a backup chain are restored at once from all of its backups.
+		if sh := cmd.chain.Shard(shardID); sh != nil {
+			if _, ok := restored[shardID]; ok {
+				continue
+			}
+			restored[shardID] = struct{}{}
+
+			r := cmd.chain.ShardReader(sh)
+			err := cmd.client.UploadShard(shardID, newID, cmd.destinationDatabase, cmd.restoreTimeToLive, tar.NewReader(r))
+			r.Close()
+			if err != nil {
+				return err
+			}
+			if err := cmd.replayWAL(sh, newID); err != nil {
+				return err
+			}
+			continue
+		} else if !cmd.until.IsZero() {
+			// Only the backups of the chain are restored to a point in time.
+			continue
+		}
+
+		f, err := cmd.target.Open(fn)
 		if err != nil {
 			return err
 		}
@@ -483,8 +598,7 @@ func (cmd *options) unpackDatabase() error {
 	}
 
 	// find the database backup files
-	pat := filepath.Join(cmd.backupFilesPath, cmd.sourceDatabase)
-	return cmd.unpackFiles(pat + ".*")
+	return cmd.unpackFiles(cmd.sourceDatabase + ".*")
 }
 
 // unpackTimeToLive will look for all backup files in the path matching this time to live
@@ -497,8 +611,7 @@ func (cmd *options) unpackTimeToLive() error {
 	}
 
 	// find the time to live backup files
-	pat := filepath.Join(cmd.backupFilesPath, cmd.sourceDatabase)
-	return cmd.unpackFiles(fmt.Sprintf("%s.%s.*", pat, cmd.backupTimeToLive))
+	return cmd.unpackFiles(fmt.Sprintf("%s.%s.*", cmd.sourceDatabase, cmd.backupTimeToLive))
 }
 
 // unpackShard will look for all backup files in the path matching this shard ID
@@ -517,7 +630,7 @@ func (cmd *options) unpackShard(shard uint64) error {
 	}
 
 	// find the shard backup files
-	pat := filepath.Join(cmd.backupFilesPath, fmt.Sprintf(backup_util.BackupFilePattern, cmd.sourceDatabase, cmd.backupTimeToLive, id))
+	pat := fmt.Sprintf(backup_util.BackupFilePattern, cmd.sourceDatabase, cmd.backupTimeToLive, id)
 	return cmd.unpackFiles(pat + ".*")
 }
 
@@ -525,7 +638,7 @@ func (cmd *options) unpackShard(shard uint64) error {
 func (cmd *options) unpackFiles(pat string) error {
 	cmd.StdoutLogger.Printf("Restoring offline from backup %s\n", pat)
 
-	backupFiles, err := filepath.Glob(pat)
+	backupFiles, err := backup_util.Glob(cmd.target, pat)
 	if err != nil {
 		return err
 	}
@@ -534,7 +647,27 @@ func (cmd *options) unpackFiles(pat string) error {
 		return fmt.Errorf("no backup files for %s in %s", pat, cmd.backupFilesPath)
 	}
 
+	restored := make(map[uint64]struct{})
 	for _, fn := range backupFiles {
+		// Shards of a backup chain are restored at once from all of its backups.
+		if sh := cmd.chainShard(fn); sh != nil {
+			if _, ok := restored[sh.ShardID]; ok {
+				continue
+			}
+			restored[sh.ShardID] = struct{}{}
+
+			if err := cmd.unpackChainShard(sh); err != nil {
+				return err
+			}
+			if err := cmd.unpackWAL(sh); err != nil {
+				return err
+			}
+			continue
+		} else if !cmd.until.IsZero() {
+			// Only the backups of the chain are restored to a point in time.
+			continue
+		}
+
 		if err := cmd.unpackTar(fn); err != nil {
 			return err
 		}
@@ -543,16 +676,127 @@ func (cmd *options) unpackFiles(pat string) error {
 	return nil
 }
 
+// chainShard returns the files of the shard of a backup file as of the
+// latest backup of the chain, or nil if the shard isn't in the chain.
+func (cmd *options) chainShard(tarFile string) *backup_util.ShardEntry {
+	pathParts := strings.Split(tarFile, ".")
+	if len(pathParts) != 4 {
+		return nil
+	}
+
+	id, err := strconv.ParseUint(pathParts[2], 10, 64)
+	if err != nil {
+		return nil
+	}
+	return cmd.chain.Shard(id)
+}
+
+// unpackChainShard restores the files of a shard from the backups of the
+// chain to the data dir.
+func (cmd *options) unpackChainShard(sh *backup_util.ShardEntry) error {
+	shardPath := filepath.Join(cmd.datadir, sh.Database, sh.Policy, strconv.FormatUint(sh.ShardID, 10))
+	if err := os.MkdirAll(shardPath, 0755); err != nil {
+		return err
+	}
+
+	r := cmd.chain.ShardReader(sh)
+	defer r.Close()
+	return tarstream.Restore(r, shardPath)
+}
+
+// walFiles returns the archived WAL segments of a shard to replay after
+// restoring it from the backup chain, none unless restoring to a point in
+// time.
+func (cmd *options) walFiles(sh *backup_util.ShardEntry) ([]string, error) {
+	if cmd.until.IsZero() {
+		return nil, nil
+	}
+
+	// The segments closed before the backup are in its shard files.
+	since, err := backup_util.ManifestTime(cmd.chain.Latest())
+	if err != nil {
+		return nil, err
+	}
+	return backup_util.WALFiles(cmd.target, sh.Database, sh.Policy, sh.ShardID, since, cmd.until)
+}
+
+// unpackWAL restores the archived WAL segments of a shard to its WAL dir,
+// numbered after the segments already there, so that they are replayed when
+// the shard is opened.
+func (cmd *options) unpackWAL(sh *backup_util.ShardEntry) error {
+	files, err := cmd.walFiles(sh)
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	walPath := filepath.Join(cmd.waldir, sh.Database, sh.Policy, strconv.FormatUint(sh.ShardID, 10))
+	if err := os.MkdirAll(walPath, 0700); err != nil {
+		return err
+	}
+
+	existing, err := filepath.Glob(filepath.Join(walPath, fmt.Sprintf("%s*.%s", tsm1.WALFilePrefix, tsm1.WALFileExtension)))
+	if err != nil {
+		return err
+	}
+	var id int
+	for _, fn := range existing {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(fn), tsm1.WALFilePrefix), "."+tsm1.WALFileExtension)
+		if n, err := strconv.Atoi(name); err == nil && n > id {
+			id = n
+		}
+	}
+
+	for _, name := range files {
+		id++
+		path := filepath.Join(walPath, fmt.Sprintf("%s%05d.%s", tsm1.WALFilePrefix, id, tsm1.WALFileExtension))
+		cmd.StdoutLogger.Printf("Restoring WAL segment %s to %s", name, path)
+
+		b, err := backup_util.ReadFile(cmd.target, name)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, b, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replayWAL replays the archived WAL segments of a shard into the shard
+// restored on the server.
+func (cmd *options) replayWAL(sh *backup_util.ShardEntry, newID uint64) error {
+	files, err := cmd.walFiles(sh)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		b, err := backup_util.ReadFile(cmd.target, name)
+		if err != nil {
+			return err
+		} else if len(b) == 0 {
+			continue
+		}
+
+		n, err := cmd.client.ReplayWAL(newID, b)
+		if err != nil {
+			return fmt.Errorf("replay %s: %s", name, err)
+		}
+		cmd.StdoutLogger.Printf("Replayed %d points from WAL segment %s", n, name)
+	}
+	return nil
+}
+
 // unpackTar will restore a single tar archive to the data dir
 func (cmd *options) unpackTar(tarFile string) error {
-	f, err := os.Open(tarFile)
+	f, err := cmd.target.Open(tarFile)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
 	// should get us ["db","ttl", "00001", "00"]
-	pathParts := strings.Split(filepath.Base(tarFile), ".")
+	pathParts := strings.Split(tarFile, ".")
 	if len(pathParts) != 4 {
 		return fmt.Errorf("backup tarfile name incorrect format")
 	}
@@ -595,7 +839,7 @@ Options:
            Identifier of the shard to be restored. Optional. If specified, then '-db <db_name>' and '-ttl <ttl_name>' are
            required.
    PATH
-           Path to directory containing the backup files.
+           Path to directory containing the backup files, or s3://bucket/prefix URL.
 
 `)
 }
@@ -9,6 +9,7 @@ require (
 	github.com/cnosdatabase/db v0.0.0
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/gogo/protobuf v1.3.2
+	github.com/golang/snappy v0.0.0-20180518054509-2e65f85255db
 	github.com/gorilla/mux v1.8.0
 	github.com/hashicorp/go-hclog v0.9.1
 	github.com/hashicorp/raft v1.3.1
@@ -22,6 +23,7 @@ require (
 	github.com/tinylib/msgp v1.1.6
 	go.uber.org/zap v1.19.0
 	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
+	golang.org/x/net v0.0.0-20210405180319-a5a99cb37ef4
 	golang.org/x/text v0.3.7
 	gopkg.in/fatih/pool.v2 v2.0.0
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
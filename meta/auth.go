@@ -0,0 +1,293 @@
+package meta
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// tokenDuration is how long the tokens signed with a shared secret are valid.
+const tokenDuration = time.Minute
+
+var (
+	// ErrAuthenticationRequired is returned when a request to the meta API
+	// carries no token.
+	ErrAuthenticationRequired = errors.New("authentication required")
+
+	// ErrInvalidToken is returned when a request to the meta API carries a
+	// token that is not signed with the shared secret, or that has expired.
+	ErrInvalidToken = errors.New("invalid token")
+)
+
+// NewToken returns a token for the meta API signed with the shared secret,
+// which expires after d.
+func NewToken(sharedSecret string, d time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": time.Now().Add(d).Unix(),
+	})
+	return token.SignedString([]byte(sharedSecret))
+}
+
+// validateToken returns an error if the token isn't signed with the shared
+// secret, or has no expiration.
+func validateToken(tokenString, sharedSecret string) error {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Check for expected signing method.
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(sharedSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return ErrInvalidToken
+	}
+
+	// Make sure an expiration was set on the token.
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ErrInvalidToken
+	} else if exp, ok := claims["exp"].(float64); !ok || exp <= 0.0 {
+		return errors.New("token expiration required")
+	}
+	return nil
+}
+
+// authenticate returns an error if the request doesn't carry a bearer token
+// signed with the shared secret.
+func authenticate(r *http.Request, sharedSecret string) error {
+	auth := r.Header.Get("Authorization")
+	if auth == "" {
+		return ErrAuthenticationRequired
+	}
+
+	const prefix = "Bearer "
+	if !strings.HasPrefix(auth, prefix) {
+		return errors.New("unsupported authentication")
+	}
+	return validateToken(strings.TrimPrefix(auth, prefix), sharedSecret)
+}
+
+// WrapWithAuthentication wraps a Handler and ensures that the requests carry
+// a valid token.
+func WrapWithAuthentication(inner http.Handler, sharedSecret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authenticate(r, sharedSecret); err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cnosdb-meta"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		inner.ServeHTTP(w, r)
+	})
+}
+
+// NewHTTPClient returns an HTTP client for the meta API, which uses tlsConfig
+// for HTTPS. The client sends the token with every request, or if the token
+// is empty and the shared secret isn't, a token signed with the secret.
+func NewHTTPClient(tlsConfig *tls.Config, sharedSecret, token string) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	if token != "" {
+		sharedSecret = ""
+	} else if sharedSecret == "" {
+		return &http.Client{Transport: transport}
+	}
+	return &http.Client{Transport: &authTransport{
+		base:         transport,
+		sharedSecret: sharedSecret,
+		token:        token,
+	}}
+}
+
+// authTransport adds a bearer token to the requests to the meta API. Since
+// the transport adds it, the token is also sent with redirected requests,
+// such as the requests redirected to the leader.
+type authTransport struct {
+	base         *http.Transport
+	sharedSecret string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func (t *authTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := t.bearerToken()
+	if err != nil {
+		return nil, err
+	}
+
+	// A transport must not modify the request.
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(r)
+}
+
+// CloseIdleConnections closes the idle connections of the transport.
+func (t *authTransport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
+
+// bearerToken returns the token to send. A token signed with the shared
+// secret is renewed once half of its duration has passed.
+func (t *authTransport) bearerToken() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.sharedSecret == "" || time.Now().Before(t.expires) {
+		return t.token, nil
+	}
+
+	token, err := NewToken(t.sharedSecret, tokenDuration)
+	if err != nil {
+		return "", err
+	}
+	t.token, t.expires = token, time.Now().Add(tokenDuration/2)
+	return t.token, nil
+}
+
+// ClientTLSConfig returns the TLS config of a client of the meta API. The
+// server certificates are verified with the CA certificate if it is set, and
+// the client certificate is sent to the servers that require one.
+func ClientTLSConfig(caCert, certFile, keyFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	c := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+
+	if caCert != "" {
+		pool, err := loadCertPool(caCert)
+		if err != nil {
+			return nil, err
+		}
+		c.RootCAs = pool
+	}
+
+	if certFile != "" {
+		if keyFile == "" {
+			keyFile = certFile
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %s", err)
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+
+	return c, nil
+}
+
+// loadCertPool returns a pool of the PEM encoded certificates in a file.
+func loadCertPool(path string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// serverTLSConfig returns the TLS config serving the HTTPS API. Client
+// certificates are required if the client CA is set.
+func (c *ServerConfig) serverTLSConfig() (*tls.Config, error) {
+	config, err := c.baseTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	keyFile := c.HTTPSPrivateKey
+	if keyFile == "" {
+		keyFile = c.HTTPSCertificate
+	}
+	cert, err := tls.LoadX509KeyPair(c.HTTPSCertificate, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load https certificate: %s", err)
+	}
+	config.Certificates = []tls.Certificate{cert}
+
+	if c.HTTPSClientCA != "" {
+		pool, err := loadCertPool(c.HTTPSClientCA)
+		if err != nil {
+			return nil, err
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// clientTLSConfig returns the TLS config of the requests from this meta node
+// to the other meta nodes, or nil if HTTPS is disabled. The meta node presents
+// its own certificate as client certificate, and verifies the other meta nodes
+// with the client CA, since the meta nodes share their configuration.
+func (c *ServerConfig) clientTLSConfig() (*tls.Config, error) {
+	if !c.HTTPSEnabled {
+		return nil, nil
+	}
+
+	base, err := c.baseTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	config, err := ClientTLSConfig(c.HTTPSClientCA, c.HTTPSCertificate, c.HTTPSPrivateKey, c.HTTPSInsecureTLS)
+	if err != nil {
+		return nil, err
+	}
+	config.CipherSuites, config.MinVersion, config.MaxVersion = base.CipherSuites, base.MinVersion, base.MaxVersion
+	return config, nil
+}
+
+// baseTLSConfig returns the TLS config with the configured ciphers and versions.
+func (c *ServerConfig) baseTLSConfig() (*tls.Config, error) {
+	if c.TLS == nil {
+		return new(tls.Config), nil
+	}
+
+	config, err := c.TLS.Parse()
+	if err != nil {
+		return nil, err
+	} else if config == nil {
+		config = new(tls.Config)
+	}
+	return config, nil
+}
+
+// httpClient returns the HTTP client of the requests from this meta node to
+// the other meta nodes.
+func (c *ServerConfig) httpClient() (*http.Client, error) {
+	tlsConfig, err := c.clientTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedSecret string
+	if c.AuthEnabled {
+		sharedSecret = c.InternalSharedSecret
+	}
+	return NewHTTPClient(tlsConfig, sharedSecret, ""), nil
+}
+
+// configureClient sets the credentials of this meta node on a client of the
+// other meta nodes.
+func (c *ServerConfig) configureClient(client *RemoteClient) error {
+	tlsConfig, err := c.clientTLSConfig()
+	if err != nil {
+		return err
+	} else if tlsConfig != nil {
+		client.SetTLSConfig(tlsConfig)
+	}
+
+	if c.AuthEnabled {
+		client.SetSharedSecret(c.InternalSharedSecret)
+	}
+	return nil
+}
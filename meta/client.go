@@ -82,6 +82,7 @@ type MetaClient interface {
 	Users() []UserInfo
 	UserCount() int
 	User(name string) (User, error)
+	UserWithRoles(name string, roles []string) (User, error)
 	CreateUser(name, password string, admin bool) (User, error)
 	UpdateUser(name, password string) error
 	DropUser(name string) error
@@ -90,13 +91,27 @@ type MetaClient interface {
 	SetAdminPrivilege(username string, admin bool) error
 	UserPrivileges(username string) (map[string]cnosql.Privilege, error)
 	UserPrivilege(username, database string) (*cnosql.Privilege, error)
+	SetSeriesPrivilege(username, database, metric, condition string, p cnosql.Privilege) error
+	UserSeriesPrivileges(username string) ([]SeriesPrivilege, error)
+	UserSeriesPrivilege(username, database, metric, condition string) (*cnosql.Privilege, error)
 	AdminUserExists() bool
 	Authenticate(username, password string) (User, error)
 
+	Roles() []RoleInfo
+	CreateRole(name string) error
+	DropRole(name string) error
+	SetRolePrivilege(name, database string, p cnosql.Privilege) error
+	SetUserQuota(name string, q Quota) error
+	SetDatabaseQuota(name string, q Quota) error
+	RolePrivilege(name, database string) (*cnosql.Privilege, error)
+	SetUserRole(username, role string, granted bool) error
+	SetRoleRole(name, role string, granted bool) error
+
 	ShardIDs() []uint64
 	RegionsByTimeRange(database, ttl string, min, max time.Time) (a []RegionInfo, err error)
 	ShardsByTimeRange(sources cnosql.Sources, tmin, tmax time.Time) (a []ShardInfo, err error)
 	DropShard(id uint64) error
+	UpdateShardOwners(id uint64, addOwners, delOwners []uint64) error
 	TruncateRegions(t time.Time) error
 	PruneRegions() error
 	CreateRegion(database, ttl string, timestamp time.Time) (*RegionInfo, error)
@@ -106,8 +121,9 @@ type MetaClient interface {
 
 	CreateContinuousQuery(database, name, query string) error
 	DropContinuousQuery(database, name string) error
+	SetContinuousQueryLastRun(database, name string, t time.Time, d time.Duration, lastErr string) error
 
-	CreateSubscription(database, ttl, name, mode string, destinations []string) error
+	CreateSubscription(database, ttl, name, mode string, destinations []string, durable bool) error
 	DropSubscription(database, ttl, name string) error
 
 	SetData(data *Data) error
@@ -498,6 +514,19 @@ func (c *Client) User(name string) (User, error) {
 	return nil, ErrUserNotFound
 }
 
+// UserWithRoles returns the user with the given name as a member of the
+// additional roles, or ErrUserNotFound.
+func (c *Client) UserWithRoles(name string, roles []string) (User, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ui, err := c.cacheData.UserWithRoles(name, roles)
+	if err != nil {
+		return nil, err
+	}
+	return ui, nil
+}
+
 // bcryptCost is the cost associated with generating password with bcrypt.
 // This setting is lowered during testing to improve test suite performance.
 var bcryptCost = bcrypt.DefaultCost
@@ -654,6 +683,161 @@ func (c *Client) UserPrivilege(username, database string) (*cnosql.Privilege, er
 	return p, nil
 }
 
+// SetSeriesPrivilege sets a privilege for the given user on the series of a metric of the given database
+// matching the condition. A privilege of NoPrivileges removes it.
+func (c *Client) SetSeriesPrivilege(username, database, metric, condition string, p cnosql.Privilege) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetSeriesPrivilege(username, database, metric, condition, p); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UserSeriesPrivileges returns the privileges of a user on series.
+func (c *Client) UserSeriesPrivileges(username string) ([]SeriesPrivilege, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.UserSeriesPrivileges(username)
+}
+
+// UserSeriesPrivilege returns the privilege for the given user on the series of a metric of the given database
+// matching the condition.
+func (c *Client) UserSeriesPrivilege(username, database, metric, condition string) (*cnosql.Privilege, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.UserSeriesPrivilege(username, database, metric, condition)
+}
+
+// Roles returns a slice of RoleInfo representing the currently known roles.
+func (c *Client) Roles() []RoleInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	roles := c.cacheData.Roles
+
+	if roles == nil {
+		return []RoleInfo{}
+	}
+	return roles
+}
+
+// CreateRole adds a role with the given name.
+func (c *Client) CreateRole(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.CreateRole(name); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// DropRole removes the role with the given name, and revokes it from the users and the roles it was granted to.
+func (c *Client) DropRole(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.DropRole(name); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// SetUserQuota sets the quota of the given user.
+func (c *Client) SetUserQuota(name string, q Quota) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetUserQuota(name, q); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// SetDatabaseQuota sets the quota of the given database.
+func (c *Client) SetDatabaseQuota(name string, q Quota) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetDatabaseQuota(name, q); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// SetRolePrivilege sets a privilege for the given role on the given database.
+func (c *Client) SetRolePrivilege(name, database string, p cnosql.Privilege) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetRolePrivilege(name, database, p); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// RolePrivilege returns the privilege for the given role on the given database.
+func (c *Client) RolePrivilege(name, database string) (*cnosql.Privilege, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.RolePrivilege(name, database)
+}
+
+// SetUserRole grants the role to the given user, or revokes it.
+func (c *Client) SetUserRole(username, role string, granted bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetUserRole(username, role, granted); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// SetRoleRole grants the role to the role with the given name, which inherits its privileges, or revokes it.
+func (c *Client) SetRoleRole(name, role string, granted bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetRoleRole(name, role, granted); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
 // AdminUserExists returns true if any user has admin privilege.
 func (c *Client) AdminUserExists() bool {
 	c.mu.RLock()
@@ -783,6 +967,18 @@ func (c *Client) DropShard(id uint64) error {
 	return c.commit(data)
 }
 
+// UpdateShardOwners adds and removes owners of a shard by ID.
+func (c *Client) UpdateShardOwners(id uint64, addOwners, delOwners []uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+	if err := data.UpdateShardOwners(id, addOwners, delOwners); err != nil {
+		return err
+	}
+	return c.commit(data)
+}
+
 // TruncateRegions truncates any region that could contain timestamps beyond t.
 func (c *Client) TruncateRegions(t time.Time) error {
 	c.mu.Lock()
@@ -1007,14 +1203,33 @@ func (c *Client) DropContinuousQuery(database, name string) error {
 	return nil
 }
 
+// SetContinuousQueryLastRun records the time the continuous query with the given name on the given database last ran,
+// with the duration and the error of the run.
+func (c *Client) SetContinuousQueryLastRun(database, name string, t time.Time, d time.Duration, lastErr string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetContinuousQueryLastRun(database, name, t, d, lastErr); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // CreateSubscription creates a subscription against the given database and time-to-live.
-func (c *Client) CreateSubscription(database, ttl, name, mode string, destinations []string) error {
+func (c *Client) CreateSubscription(database, ttl, name, mode string, destinations []string, durable bool) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	data := c.cacheData.Clone()
 
-	if err := data.CreateSubscription(database, ttl, name, mode, destinations); err != nil {
+	if err := data.CreateSubscription(database, ttl, name, mode, destinations, durable); err != nil {
 		return err
 	}
 
@@ -27,6 +27,15 @@ type Config struct {
 	Dir                  string `toml:"dir"`
 	TimeToLiveAutoCreate bool   `toml:"time-to-live-autocreate"`
 
+	// The credentials of the data nodes for the meta nodes. The client
+	// certificate is sent to meta nodes requiring mutual TLS.
+	MetaTLSEnabled           bool   `toml:"meta-tls-enabled"`
+	MetaInsecureTLS          bool   `toml:"meta-insecure-tls"`
+	MetaCACert               string `toml:"meta-ca-cert"`
+	MetaClientCertificate    string `toml:"meta-client-certificate"`
+	MetaClientPrivateKey     string `toml:"meta-client-private-key"`
+	MetaInternalSharedSecret string `toml:"meta-internal-shared-secret"`
+
 	HTTPD *ServerConfig
 	Log   *logger.Config
 }
@@ -105,9 +114,28 @@ func (c *Config) FromToml(input string) error {
 	return err
 }
 
+// ConfigureClient sets the credentials of a data node on a client of the meta
+// nodes.
+func (c *Config) ConfigureClient(client *RemoteClient) error {
+	if c.MetaTLSEnabled {
+		tlsConfig, err := ClientTLSConfig(c.MetaCACert, c.MetaClientCertificate, c.MetaClientPrivateKey, c.MetaInsecureTLS)
+		if err != nil {
+			return err
+		}
+		client.SetTLSConfig(tlsConfig)
+	}
+	if c.MetaInternalSharedSecret != "" {
+		client.SetSharedSecret(c.MetaInternalSharedSecret)
+	}
+	return nil
+}
+
 func (c *Config) Validate() error {
 	if c.Dir == "" {
 		return errors.New("Meta.Dir must be specified")
 	}
+	if c.HTTPD != nil {
+		return c.HTTPD.Validate()
+	}
 	return nil
 }
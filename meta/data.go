@@ -48,6 +48,7 @@ type Data struct {
 	DataNodes []NodeInfo
 	Databases []DatabaseInfo
 	Users     []UserInfo
+	Roles     []RoleInfo
 
 	// adminUserExists provides a constant time mechanism for determining
 	// if there is at least one admin user.
@@ -362,10 +363,22 @@ func (data *Data) DropDatabase(name string) error {
 		if data.Databases[i].Name == name {
 			data.Databases = append(data.Databases[:i], data.Databases[i+1:]...)
 
-			// Remove all user privileges associated with this database.
+			// Remove all user and role privileges associated with this database.
+			for i := range data.Roles {
+				delete(data.Roles[i].Privileges, name)
+			}
 			for i := range data.Users {
 				delete(data.Users[i].Privileges, name)
+
+				var sps []SeriesPrivilege
+				for _, sp := range data.Users[i].SeriesPrivileges {
+					if sp.Database != name {
+						sps = append(sps, sp)
+					}
+				}
+				data.Users[i].SeriesPrivileges = sps
 			}
+			data.updateRolePrivileges()
 			break
 		}
 	}
@@ -579,6 +592,60 @@ func (data *Data) DropShard(id uint64) {
 	}
 }
 
+// UpdateShardOwners adds and removes owners of a shard by ID.
+//
+// Owners that are already present are not added twice, and removing a
+// node that doesn't own the shard is a no-op, so the command can be re-run
+// if the data nodes fail after it succeeded.
+func (data *Data) UpdateShardOwners(id uint64, addOwners, delOwners []uint64) error {
+	for _, nodeID := range addOwners {
+		if data.DataNode(nodeID) == nil {
+			return ErrNodeNotFound
+		}
+	}
+
+	for dbidx, dbi := range data.Databases {
+		for ttlidx, ttli := range dbi.TimeToLives {
+			for rgidx, rg := range ttli.Regions {
+				for sidx, sh := range rg.Shards {
+					if sh.ID != id {
+						continue
+					}
+
+					var owners []ShardOwner
+					for _, owner := range sh.Owners {
+						if !containsNodeID(delOwners, owner.NodeID) {
+							owners = append(owners, owner)
+						}
+					}
+					for _, nodeID := range addOwners {
+						if !containsNodeID(delOwners, nodeID) && !sh.OwnedBy(nodeID) {
+							owners = append(owners, ShardOwner{NodeID: nodeID})
+						}
+					}
+
+					if len(owners) == 0 {
+						return ErrShardOwnersRequired
+					}
+					data.Databases[dbidx].TimeToLives[ttlidx].Regions[rgidx].Shards[sidx].Owners = owners
+					return nil
+				}
+			}
+		}
+	}
+	return ErrShardNotFound
+}
+
+// containsNodeID returns true if ids contains id.
+func containsNodeID(ids []uint64, id uint64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
 // Regions returns a list of all regions on a database and time-to-live.
 func (data *Data) Regions(database, ttl string) ([]RegionInfo, error) {
 	// Find time-to-live.
@@ -825,14 +892,40 @@ func (data *Data) DropContinuousQuery(database, name string) error {
 	return nil
 }
 
-// validateURL returns an error if the URL does not have a port or uses a scheme other than UDP or HTTP.
+// SetContinuousQueryLastRun sets the time a continuous query last ran, with
+// the duration and the error of the run. The last run time only moves
+// forward, so that a node that lost the lease of the query while running it
+// can't make the next node run intervals again.
+func (data *Data) SetContinuousQueryLastRun(database, name string, t time.Time, d time.Duration, lastErr string) error {
+	di := data.Database(database)
+	if di == nil {
+		return cnosdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.ContinuousQueries {
+		if di.ContinuousQueries[i].Name == name {
+			cqi := &di.ContinuousQueries[i]
+			if !t.Before(cqi.LastRun) {
+				cqi.LastRun = t
+				cqi.LastDuration = d
+				cqi.LastError = lastErr
+			}
+			return nil
+		}
+	}
+	return ErrContinuousQueryNotFound
+}
+
+// validateURL returns an error if the URL does not have a scheme or a port.
+// Subscription sinks are registered by scheme on the data nodes, which fail
+// to create the subscription if the scheme is unknown.
 func validateURL(input string) error {
 	u, err := url.Parse(input)
 	if err != nil {
 		return ErrInvalidSubscriptionURL(input)
 	}
 
-	if u.Scheme != "udp" && u.Scheme != "http" && u.Scheme != "https" {
+	if u.Scheme == "" {
 		return ErrInvalidSubscriptionURL(input)
 	}
 
@@ -845,7 +938,8 @@ func validateURL(input string) error {
 }
 
 // CreateSubscription adds a named subscription to a database and time-to-live.
-func (data *Data) CreateSubscription(database, ttl, name, mode string, destinations []string) error {
+// The writes of a durable subscription are queued on disk until they are delivered.
+func (data *Data) CreateSubscription(database, ttl, name, mode string, destinations []string, durable bool) error {
 	for _, d := range destinations {
 		if err := validateURL(d); err != nil {
 			return err
@@ -871,6 +965,7 @@ func (data *Data) CreateSubscription(database, ttl, name, mode string, destinati
 		Name:         name,
 		Mode:         mode,
 		Destinations: destinations,
+		Durable:      durable,
 	})
 
 	return nil
@@ -985,6 +1080,42 @@ func (data *Data) SetPrivilege(name, database string, p cnosql.Privilege) error
 	return nil
 }
 
+// SetSeriesPrivilege sets a privilege for a user on the series of a metric of
+// a database matching a condition. A privilege of NoPrivileges removes it.
+func (data *Data) SetSeriesPrivilege(name, database, metric, condition string, p cnosql.Privilege) error {
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	if data.Database(database) == nil {
+		return cnosdb.ErrDatabaseNotFound(database)
+	}
+
+	sp, err := NewSeriesPrivilege(database, metric, condition, p)
+	if err != nil {
+		return err
+	}
+
+	for i := range ui.SeriesPrivileges {
+		other := &ui.SeriesPrivileges[i]
+		if other.Database != database || other.Metric != metric || other.Condition != condition {
+			continue
+		}
+		if p == cnosql.NoPrivileges {
+			ui.SeriesPrivileges = append(ui.SeriesPrivileges[:i], ui.SeriesPrivileges[i+1:]...)
+		} else {
+			*other = sp
+		}
+		return nil
+	}
+
+	if p != cnosql.NoPrivileges {
+		ui.SeriesPrivileges = append(ui.SeriesPrivileges, sp)
+	}
+	return nil
+}
+
 // SetAdminPrivilege sets the admin privilege for a user.
 func (data *Data) SetAdminPrivilege(name string, admin bool) error {
 	ui := data.user(name)
@@ -1000,6 +1131,28 @@ func (data *Data) SetAdminPrivilege(name string, admin bool) error {
 	return nil
 }
 
+// SetUserQuota sets the quota of a user.
+func (data *Data) SetUserQuota(name string, q Quota) error {
+	ui := data.user(name)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	ui.Quota = q
+	return nil
+}
+
+// SetDatabaseQuota sets the quota of a database.
+func (data *Data) SetDatabaseQuota(name string, q Quota) error {
+	di := data.Database(name)
+	if di == nil {
+		return cnosdb.ErrDatabaseNotFound(name)
+	}
+
+	di.Quota = q
+	return nil
+}
+
 // AdminUserExists returns true if an admin user exists.
 func (data Data) AdminUserExists() bool {
 	return data.adminUserExists
@@ -1031,6 +1184,243 @@ func (data *Data) UserPrivilege(name, database string) (*cnosql.Privilege, error
 	return cnosql.NewPrivilege(cnosql.NoPrivileges), nil
 }
 
+// UserSeriesPrivileges gets the privileges for a user on series.
+func (data *Data) UserSeriesPrivileges(name string) ([]SeriesPrivilege, error) {
+	ui := data.user(name)
+	if ui == nil {
+		return nil, ErrUserNotFound
+	}
+
+	return ui.SeriesPrivileges, nil
+}
+
+// UserSeriesPrivilege gets the privilege for a user on the series of a metric
+// of a database matching a condition.
+func (data *Data) UserSeriesPrivilege(name, database, metric, condition string) (*cnosql.Privilege, error) {
+	ui := data.user(name)
+	if ui == nil {
+		return nil, ErrUserNotFound
+	}
+
+	for _, sp := range ui.SeriesPrivileges {
+		if sp.Database == database && sp.Metric == metric && sp.Condition == condition {
+			return cnosql.NewPrivilege(sp.Privilege), nil
+		}
+	}
+
+	return cnosql.NewPrivilege(cnosql.NoPrivileges), nil
+}
+
+func (data *Data) role(name string) *RoleInfo {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			return &data.Roles[i]
+		}
+	}
+	return nil
+}
+
+// Role returns a role by name.
+func (data *Data) Role(name string) *RoleInfo {
+	return data.role(name)
+}
+
+// CreateRole creates a new role without privileges.
+func (data *Data) CreateRole(name string) error {
+	if name == "" {
+		return ErrRoleNameRequired
+	} else if data.role(name) != nil {
+		return ErrRoleExists
+	}
+
+	data.Roles = append(data.Roles, RoleInfo{Name: name})
+	return nil
+}
+
+// DropRole removes an existing role by name, and revokes it from the users
+// and the roles it was granted to.
+func (data *Data) DropRole(name string) error {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			data.Roles = append(data.Roles[:i], data.Roles[i+1:]...)
+
+			for i := range data.Users {
+				data.Users[i].Roles = removeName(data.Users[i].Roles, name)
+			}
+			for i := range data.Roles {
+				data.Roles[i].Roles = removeName(data.Roles[i].Roles, name)
+			}
+			data.updateRolePrivileges()
+			return nil
+		}
+	}
+	return ErrRoleNotFound
+}
+
+// SetRolePrivilege sets a privilege for a role on a database.
+func (data *Data) SetRolePrivilege(name, database string, p cnosql.Privilege) error {
+	ri := data.role(name)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+
+	if data.Database(database) == nil {
+		return cnosdb.ErrDatabaseNotFound(database)
+	}
+
+	if ri.Privileges == nil {
+		ri.Privileges = make(map[string]cnosql.Privilege)
+	}
+	ri.Privileges[database] = p
+
+	data.updateRolePrivileges()
+	return nil
+}
+
+// RolePrivilege gets the privilege for a role on a database, excluding the
+// privileges of the roles it inherits.
+func (data *Data) RolePrivilege(name, database string) (*cnosql.Privilege, error) {
+	ri := data.role(name)
+	if ri == nil {
+		return nil, ErrRoleNotFound
+	}
+
+	if p, ok := ri.Privileges[database]; ok {
+		return &p, nil
+	}
+	return cnosql.NewPrivilege(cnosql.NoPrivileges), nil
+}
+
+// SetUserRole grants a role to a user, or revokes it.
+func (data *Data) SetUserRole(username, role string, granted bool) error {
+	ui := data.user(username)
+	if ui == nil {
+		return ErrUserNotFound
+	} else if data.role(role) == nil {
+		return ErrRoleNotFound
+	}
+
+	ui.Roles = removeName(ui.Roles, role)
+	if granted {
+		ui.Roles = append(ui.Roles, role)
+	}
+
+	data.updateRolePrivileges()
+	return nil
+}
+
+// SetRoleRole grants a role to a role inheriting its privileges, or revokes it.
+func (data *Data) SetRoleRole(name, role string, granted bool) error {
+	ri := data.role(name)
+	if ri == nil || data.role(role) == nil {
+		return ErrRoleNotFound
+	}
+
+	if granted && data.inheritsRole(role, name) {
+		return ErrRoleCycle
+	}
+
+	ri.Roles = removeName(ri.Roles, role)
+	if granted {
+		ri.Roles = append(ri.Roles, role)
+	}
+
+	data.updateRolePrivileges()
+	return nil
+}
+
+// inheritsRole returns true if the role with the given name is, or inherits,
+// the other role.
+func (data *Data) inheritsRole(name, other string) bool {
+	seen := make(map[string]bool)
+	var inherits func(name string) bool
+	inherits = func(name string) bool {
+		if name == other {
+			return true
+		} else if seen[name] {
+			return false
+		}
+		seen[name] = true
+
+		if ri := data.role(name); ri != nil {
+			for _, parent := range ri.Roles {
+				if inherits(parent) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return inherits(name)
+}
+
+// updateRolePrivileges computes the privileges the users have through their
+// roles. It must be called whenever users, roles or their privileges change.
+func (data *Data) updateRolePrivileges() {
+	for i := range data.Users {
+		ui := &data.Users[i]
+		ui.rolePrivileges = data.rolePrivileges(ui.Roles)
+	}
+}
+
+// rolePrivileges returns the union of the privileges of roles and the roles
+// they inherit from.
+func (data *Data) rolePrivileges(roles []string) map[string]cnosql.Privilege {
+	var privileges map[string]cnosql.Privilege
+	seen := make(map[string]bool)
+	var add func(name string)
+	add = func(name string) {
+		ri := data.role(name)
+		if ri == nil || seen[name] {
+			return
+		}
+		seen[name] = true
+
+		for database, p := range ri.Privileges {
+			if privileges == nil {
+				privileges = make(map[string]cnosql.Privilege)
+			}
+			privileges[database] |= p
+		}
+		for _, parent := range ri.Roles {
+			add(parent)
+		}
+	}
+	for _, name := range roles {
+		add(name)
+	}
+	return privileges
+}
+
+// UserWithRoles returns a copy of a user that is also a member of roles for
+// as long as the copy is used. Roles that do not exist are ignored.
+func (data *Data) UserWithRoles(username string, roles []string) (*UserInfo, error) {
+	u := data.user(username)
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+
+	ui := u.clone()
+	for _, name := range roles {
+		if data.role(name) != nil {
+			ui.Roles = append(removeName(ui.Roles, name), name)
+		}
+	}
+	ui.rolePrivileges = data.rolePrivileges(ui.Roles)
+	return &ui, nil
+}
+
+// removeName returns names without name.
+func removeName(names []string, name string) []string {
+	var other []string
+	for _, n := range names {
+		if n != name {
+			other = append(other, n)
+		}
+	}
+	return other
+}
+
 // Clone returns a copy of data with a new version.
 func (data *Data) Clone() *Data {
 	other := *data
@@ -1064,6 +1454,13 @@ func (data *Data) Clone() *Data {
 		}
 	}
 
+	if data.Roles != nil {
+		other.Roles = make([]RoleInfo, len(data.Roles))
+		for i := range data.Roles {
+			other.Roles[i] = data.Roles[i].clone()
+		}
+	}
+
 	return &other
 }
 
@@ -1099,6 +1496,11 @@ func (data *Data) marshal() *internal.Data {
 		pb.Users[i] = data.Users[i].marshal()
 	}
 
+	pb.Roles = make([]*internal.RoleInfo, len(data.Roles))
+	for i := range data.Roles {
+		pb.Roles[i] = data.Roles[i].marshal()
+	}
+
 	return pb
 }
 
@@ -1132,6 +1534,12 @@ func (data *Data) unmarshal(pb *internal.Data) {
 		data.Users[i].unmarshal(x)
 	}
 
+	data.Roles = make([]RoleInfo, len(pb.GetRoles()))
+	for i, x := range pb.GetRoles() {
+		data.Roles[i].unmarshal(x)
+	}
+	data.updateRolePrivileges()
+
 	// Exhaustively determine if there is an admin user. The marshalled cache
 	// value may not be correct.
 	data.adminUserExists = data.hasAdminUser()
@@ -1333,6 +1741,7 @@ type DatabaseInfo struct {
 	DefaultTimeToLive string
 	TimeToLives       []TimeToLiveInfo
 	ContinuousQueries []ContinuousQueryInfo
+	Quota             Quota
 }
 
 // TimeToLive returns a time-to-live by name.
@@ -1414,6 +1823,10 @@ func (di DatabaseInfo) marshal() *internal.DatabaseInfo {
 	for i := range di.ContinuousQueries {
 		pb.ContinuousQueries[i] = di.ContinuousQueries[i].marshal()
 	}
+
+	if di.Quota != (Quota{}) {
+		pb.Quota = di.Quota.marshal()
+	}
 	return pb
 }
 
@@ -1435,6 +1848,8 @@ func (di *DatabaseInfo) unmarshal(pb *internal.DatabaseInfo) {
 			di.ContinuousQueries[i].unmarshal(x)
 		}
 	}
+
+	di.Quota.unmarshal(pb.GetQuota())
 }
 
 // TimeToLiveSpec represents the specification for a new time-to-live.
@@ -1917,6 +2332,7 @@ type SubscriptionInfo struct {
 	Name         string
 	Mode         string
 	Destinations []string
+	Durable      bool
 }
 
 // marshal serializes to a protobuf representation.
@@ -1925,6 +2341,9 @@ func (si SubscriptionInfo) marshal() *internal.SubscriptionInfo {
 		Name: proto.String(si.Name),
 		Mode: proto.String(si.Mode),
 	}
+	if si.Durable {
+		pb.Durable = proto.Bool(true)
+	}
 
 	pb.Destinations = make([]string, len(si.Destinations))
 	for i := range si.Destinations {
@@ -1937,6 +2356,7 @@ func (si SubscriptionInfo) marshal() *internal.SubscriptionInfo {
 func (si *SubscriptionInfo) unmarshal(pb *internal.SubscriptionInfo) {
 	si.Name = pb.GetName()
 	si.Mode = pb.GetMode()
+	si.Durable = pb.GetDurable()
 
 	if len(pb.GetDestinations()) > 0 {
 		si.Destinations = make([]string, len(pb.GetDestinations()))
@@ -1970,6 +2390,15 @@ func (so *ShardOwner) unmarshal(pb *internal.ShardOwner) {
 type ContinuousQueryInfo struct {
 	Name  string
 	Query string
+
+	// LastRun is the time the query last ran, which is the end of the
+	// last interval it computed. It is zero if the query never ran.
+	LastRun time.Time
+
+	// LastDuration and LastError are the duration and the error of the
+	// last run. LastError is empty if the last run succeeded.
+	LastDuration time.Duration
+	LastError    string
 }
 
 // clone returns a deep copy of cqi.
@@ -1978,8 +2407,12 @@ func (cqi ContinuousQueryInfo) clone() ContinuousQueryInfo { return cqi }
 // marshal serializes to a protobuf representation.
 func (cqi ContinuousQueryInfo) marshal() *internal.ContinuousQueryInfo {
 	return &internal.ContinuousQueryInfo{
-		Name:  proto.String(cqi.Name),
-		Query: proto.String(cqi.Query),
+		Name:    proto.String(cqi.Name),
+		Query:   proto.String(cqi.Query),
+		LastRun: proto.Int64(MarshalTime(cqi.LastRun)),
+
+		LastDuration: proto.Int64(int64(cqi.LastDuration)),
+		LastError:    proto.String(cqi.LastError),
 	}
 }
 
@@ -1987,6 +2420,9 @@ func (cqi ContinuousQueryInfo) marshal() *internal.ContinuousQueryInfo {
 func (cqi *ContinuousQueryInfo) unmarshal(pb *internal.ContinuousQueryInfo) {
 	cqi.Name = pb.GetName()
 	cqi.Query = pb.GetQuery()
+	cqi.LastRun = UnmarshalTime(pb.GetLastRun())
+	cqi.LastDuration = time.Duration(pb.GetLastDuration())
+	cqi.LastError = pb.GetLastError()
 }
 
 var _ query.FineAuthorizer = (*UserInfo)(nil)
@@ -2004,6 +2440,19 @@ type UserInfo struct {
 
 	// Map of database name to granted privilege.
 	Privileges map[string]cnosql.Privilege
+
+	// Privileges granted on some series of a database. They are only
+	// checked on databases the user has no privilege on.
+	SeriesPrivileges []SeriesPrivilege
+
+	// Names of the roles granted to the user.
+	Roles []string
+
+	// Limits of the writes and the queries of the user.
+	Quota Quota
+
+	// Map of database name to the privilege granted through the roles.
+	rolePrivileges map[string]cnosql.Privilege
 }
 
 type User interface {
@@ -2017,27 +2466,65 @@ func (u *UserInfo) ID() string {
 }
 
 // AuthorizeDatabase returns true if the user is authorized for the given privilege on the given database.
+// A user granted reads on some series of the database may also query it, and only reads those series.
 func (ui *UserInfo) AuthorizeDatabase(privilege cnosql.Privilege, database string) bool {
 	if ui.Admin || privilege == cnosql.NoPrivileges {
 		return true
 	}
-	p, ok := ui.Privileges[database]
-	return ok && (p == privilege || p == cnosql.AllPrivileges)
+	if p := ui.privilege(database); p == privilege || p == cnosql.AllPrivileges {
+		return true
+	}
+	return privilege == cnosql.ReadPrivilege && ui.hasSeriesPrivilege(privilege, database)
 }
 
-// AuthorizeSeriesRead is used to limit access per-series (enterprise only)
-func (u *UserInfo) AuthorizeSeriesRead(database string, metric []byte, tags models.Tags) bool {
-	return true
+// privilege returns the union of the privileges granted to the user and to its roles on a database.
+func (ui *UserInfo) privilege(database string) cnosql.Privilege {
+	return ui.Privileges[database] | ui.rolePrivileges[database]
 }
 
-// AuthorizeSeriesWrite is used to limit access per-series (enterprise only)
-func (u *UserInfo) AuthorizeSeriesWrite(database string, metric []byte, tags models.Tags) bool {
-	return true
+// hasSeriesPrivilege returns true if the user is granted the privilege on some series of the database.
+func (ui *UserInfo) hasSeriesPrivilege(privilege cnosql.Privilege, database string) bool {
+	for i := range ui.SeriesPrivileges {
+		if sp := &ui.SeriesPrivileges[i]; sp.Database == database && sp.grants(privilege) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthorizeSeriesRead returns true if the user may read the series.
+func (ui *UserInfo) AuthorizeSeriesRead(database string, metric []byte, tags models.Tags) bool {
+	return ui.authorizeSeries(cnosql.ReadPrivilege, database, metric, tags)
+}
+
+// AuthorizeSeriesWrite returns true if the user may write the series.
+func (ui *UserInfo) AuthorizeSeriesWrite(database string, metric []byte, tags models.Tags) bool {
+	return ui.authorizeSeries(cnosql.WritePrivilege, database, metric, tags)
+}
+
+// authorizeSeries returns true if the user is granted the privilege on the
+// whole database, or on series privileges matching the series.
+func (ui *UserInfo) authorizeSeries(privilege cnosql.Privilege, database string, metric []byte, tags models.Tags) bool {
+	if ui.Admin {
+		return true
+	}
+	if p := ui.privilege(database); p == privilege || p == cnosql.AllPrivileges {
+		return true
+	}
+
+	for i := range ui.SeriesPrivileges {
+		sp := &ui.SeriesPrivileges[i]
+		if sp.Database == database && sp.grants(privilege) && sp.Matches(metric, tags) {
+			return true
+		}
+	}
+	return false
 }
 
 // IsOpen is a method on FineAuthorizer to indicate all fine auth is permitted and short circuit some checks.
-func (u *UserInfo) IsOpen() bool {
-	return true
+// Only users granted privileges on series need their series checked.
+func (ui *UserInfo) IsOpen() bool {
+	return ui.Admin || len(ui.SeriesPrivileges) == 0
 }
 
 // AuthorizeUnrestricted allows admins to shortcut access checks.
@@ -2056,6 +2543,16 @@ func (ui UserInfo) clone() UserInfo {
 		}
 	}
 
+	if ui.SeriesPrivileges != nil {
+		other.SeriesPrivileges = make([]SeriesPrivilege, len(ui.SeriesPrivileges))
+		copy(other.SeriesPrivileges, ui.SeriesPrivileges)
+	}
+
+	if ui.Roles != nil {
+		other.Roles = make([]string, len(ui.Roles))
+		copy(other.Roles, ui.Roles)
+	}
+
 	return other
 }
 
@@ -2074,6 +2571,16 @@ func (ui UserInfo) marshal() *internal.UserInfo {
 		})
 	}
 
+	for _, sp := range ui.SeriesPrivileges {
+		pb.SeriesPrivileges = append(pb.SeriesPrivileges, sp.marshal())
+	}
+
+	pb.Roles = ui.Roles
+
+	if ui.Quota != (Quota{}) {
+		pb.Quota = ui.Quota.marshal()
+	}
+
 	return pb
 }
 
@@ -2087,6 +2594,210 @@ func (ui *UserInfo) unmarshal(pb *internal.UserInfo) {
 	for _, p := range pb.GetPrivileges() {
 		ui.Privileges[p.GetDatabase()] = cnosql.Privilege(p.GetPrivilege())
 	}
+
+	ui.SeriesPrivileges = nil
+	if len(pb.GetSeriesPrivileges()) > 0 {
+		ui.SeriesPrivileges = make([]SeriesPrivilege, len(pb.GetSeriesPrivileges()))
+		for i, x := range pb.GetSeriesPrivileges() {
+			ui.SeriesPrivileges[i].unmarshal(x)
+		}
+	}
+
+	ui.Roles = pb.GetRoles()
+	ui.Quota.unmarshal(pb.GetQuota())
+}
+
+// Quota limits the writes and the queries of a database or a user. A zero
+// limit is unlimited.
+//
+// Each data node enforces the quotas on its own writes and queries, so a
+// database or a user writing and querying through N data nodes gets up to N
+// times the write rates and concurrent queries.
+type Quota struct {
+	// Maximum number of points written per second.
+	WritePoints int64
+
+	// Maximum number of bytes written per second.
+	WriteBytes int64
+
+	// Maximum number of queries running at the same time.
+	Queries int
+
+	// Maximum number of series a SELECT statement may read.
+	SelectSeries int
+}
+
+// marshal serializes to a protobuf representation.
+func (q Quota) marshal() *internal.Quota {
+	return &internal.Quota{
+		WritePoints: proto.Int64(q.WritePoints),
+		WriteBytes:  proto.Int64(q.WriteBytes),
+		Queries:     proto.Int64(int64(q.Queries)),
+		Series:      proto.Int64(int64(q.SelectSeries)),
+	}
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (q *Quota) unmarshal(pb *internal.Quota) {
+	q.WritePoints = pb.GetWritePoints()
+	q.WriteBytes = pb.GetWriteBytes()
+	q.Queries = int(pb.GetQueries())
+	q.SelectSeries = int(pb.GetSeries())
+}
+
+// RoleInfo represents a set of privileges granted to the users with the role.
+type RoleInfo struct {
+	// Role's name.
+	Name string
+
+	// Map of database name to granted privilege.
+	Privileges map[string]cnosql.Privilege
+
+	// Names of the roles whose privileges the role inherits.
+	Roles []string
+}
+
+// clone returns a deep copy of ri.
+func (ri RoleInfo) clone() RoleInfo {
+	other := ri
+
+	if ri.Privileges != nil {
+		other.Privileges = make(map[string]cnosql.Privilege)
+		for k, v := range ri.Privileges {
+			other.Privileges[k] = v
+		}
+	}
+
+	if ri.Roles != nil {
+		other.Roles = make([]string, len(ri.Roles))
+		copy(other.Roles, ri.Roles)
+	}
+
+	return other
+}
+
+// marshal serializes to a protobuf representation.
+func (ri RoleInfo) marshal() *internal.RoleInfo {
+	pb := &internal.RoleInfo{
+		Name:  proto.String(ri.Name),
+		Roles: ri.Roles,
+	}
+
+	for database, privilege := range ri.Privileges {
+		pb.Privileges = append(pb.Privileges, &internal.UserPrivilege{
+			Database:  proto.String(database),
+			Privilege: proto.Int32(int32(privilege)),
+		})
+	}
+
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (ri *RoleInfo) unmarshal(pb *internal.RoleInfo) {
+	ri.Name = pb.GetName()
+	ri.Roles = pb.GetRoles()
+
+	ri.Privileges = make(map[string]cnosql.Privilege)
+	for _, p := range pb.GetPrivileges() {
+		ri.Privileges[p.GetDatabase()] = cnosql.Privilege(p.GetPrivilege())
+	}
+}
+
+// SeriesPrivilege represents a privilege granted on the series of a metric
+// whose tags match a condition.
+type SeriesPrivilege struct {
+	Database string
+
+	// The metric of the series, or empty for all metrics of the database.
+	Metric string
+
+	// The condition on the tags of the series, or empty for all series.
+	Condition string
+
+	Privilege cnosql.Privilege
+
+	// The parsed condition.
+	cond cnosql.Expr
+}
+
+// NewSeriesPrivilege returns a series privilege, or an error if the
+// condition isn't a valid condition on the tags of a series.
+func NewSeriesPrivilege(database, metric, condition string, p cnosql.Privilege) (SeriesPrivilege, error) {
+	sp := SeriesPrivilege{
+		Database:  database,
+		Metric:    metric,
+		Condition: condition,
+		Privilege: p,
+	}
+	if condition == "" {
+		return sp, nil
+	}
+
+	cond, err := cnosql.ParseExpr(condition)
+	if err != nil {
+		return SeriesPrivilege{}, err
+	} else if err := cnosql.ValidateSeriesCondition(cond); err != nil {
+		return SeriesPrivilege{}, err
+	}
+	sp.cond = cond
+	return sp, nil
+}
+
+// grants returns true if the series privilege includes privilege.
+func (sp *SeriesPrivilege) grants(privilege cnosql.Privilege) bool {
+	return sp.Privilege == privilege || sp.Privilege == cnosql.AllPrivileges
+}
+
+// Matches returns true if the series of the metric with the tags is covered
+// by the privilege.
+func (sp *SeriesPrivilege) Matches(metric []byte, tags models.Tags) bool {
+	if sp.Metric != "" && sp.Metric != string(metric) {
+		return false
+	}
+	if sp.Condition == "" {
+		return true
+	} else if sp.cond == nil {
+		// A condition that failed to parse matches no series.
+		return false
+	}
+
+	eval := cnosql.ValuerEval{Valuer: tagsValuer(tags)}
+	return eval.EvalBool(sp.cond)
+}
+
+// marshal serializes to a protobuf representation.
+func (sp SeriesPrivilege) marshal() *internal.UserSeriesPrivilege {
+	return &internal.UserSeriesPrivilege{
+		Database:  proto.String(sp.Database),
+		Metric:    proto.String(sp.Metric),
+		Condition: proto.String(sp.Condition),
+		Privilege: proto.Int32(int32(sp.Privilege)),
+	}
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (sp *SeriesPrivilege) unmarshal(pb *internal.UserSeriesPrivilege) {
+	other, err := NewSeriesPrivilege(pb.GetDatabase(), pb.GetMetric(), pb.GetCondition(), cnosql.Privilege(pb.GetPrivilege()))
+	if err != nil {
+		// Keep the privilege without a parsed condition so that it can be revoked.
+		other = SeriesPrivilege{
+			Database:  pb.GetDatabase(),
+			Metric:    pb.GetMetric(),
+			Condition: pb.GetCondition(),
+			Privilege: cnosql.Privilege(pb.GetPrivilege()),
+		}
+	}
+	*sp = other
+}
+
+// tagsValuer returns the values of the tags of a series. Missing tags have
+// an empty value, as in the conditions of queries.
+type tagsValuer models.Tags
+
+// Value returns the value of the tag key.
+func (v tagsValuer) Value(key string) (interface{}, bool) {
+	return models.Tags(v).GetString(key), true
 }
 
 // Lease represents a lease held on a resource.
@@ -0,0 +1,228 @@
+package meta
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	internal "github.com/cnosdatabase/cnosdb/meta/internal"
+	"github.com/gogo/protobuf/proto"
+)
+
+// maxDataDeltas is the number of changes the meta store keeps for clients
+// catching up with the metadata. A client that is further behind fetches a
+// full snapshot instead.
+const maxDataDeltas = 1024
+
+// diffData returns the change from prev, the metadata at prevIndex, to next.
+// Unchanged databases are left out of the change, and so are unchanged regions
+// of changed databases.
+func diffData(prevIndex uint64, prev, next *Data) *internal.DataDelta {
+	pb := &internal.DataDelta{
+		PrevIndex: proto.Uint64(prevIndex),
+		Term:      proto.Uint64(next.Term),
+		Index:     proto.Uint64(next.Index),
+		ClusterID: proto.Uint64(next.ClusterID),
+
+		MaxNodeID:   proto.Uint64(next.MaxNodeID),
+		MaxRegionID: proto.Uint64(next.MaxRegionID),
+		MaxShardID:  proto.Uint64(next.MaxShardID),
+	}
+
+	if !reflect.DeepEqual(prev.DataNodes, next.DataNodes) || !reflect.DeepEqual(prev.MetaNodes, next.MetaNodes) {
+		pb.NodesChanged = proto.Bool(true)
+		pb.DataNodes = make([]*internal.NodeInfo, len(next.DataNodes))
+		for i := range next.DataNodes {
+			pb.DataNodes[i] = next.DataNodes[i].marshal()
+		}
+		pb.MetaNodes = make([]*internal.NodeInfo, len(next.MetaNodes))
+		for i := range next.MetaNodes {
+			pb.MetaNodes[i] = next.MetaNodes[i].marshal()
+		}
+	}
+
+	if !reflect.DeepEqual(prev.Users, next.Users) {
+		pb.UsersChanged = proto.Bool(true)
+		pb.Users = make([]*internal.UserInfo, len(next.Users))
+		for i := range next.Users {
+			pb.Users[i] = next.Users[i].marshal()
+		}
+	}
+
+	if !reflect.DeepEqual(prev.Roles, next.Roles) {
+		pb.RolesChanged = proto.Bool(true)
+		pb.Roles = make([]*internal.RoleInfo, len(next.Roles))
+		for i := range next.Roles {
+			pb.Roles[i] = next.Roles[i].marshal()
+		}
+	}
+
+	for i := range next.Databases {
+		if d := diffDatabase(prev.Database(next.Databases[i].Name), &next.Databases[i]); d != nil {
+			pb.Databases = append(pb.Databases, d)
+		}
+	}
+	for i := range prev.Databases {
+		if next.Database(prev.Databases[i].Name) == nil {
+			pb.DroppedDatabases = append(pb.DroppedDatabases, prev.Databases[i].Name)
+		}
+	}
+
+	return pb
+}
+
+// diffDatabase returns the change of a database, or nil if it is unchanged.
+// prev is nil if the database was created.
+func diffDatabase(prev, next *DatabaseInfo) *internal.DatabaseDelta {
+	if prev != nil && reflect.DeepEqual(prev, next) {
+		return nil
+	}
+
+	pb := &internal.DatabaseDelta{Info: next.marshal()}
+	if prev == nil {
+		return pb
+	}
+
+	// Keep only the regions that changed.
+	for i, ttli := range pb.Info.TimeToLives {
+		prevTTL := prev.TimeToLive(ttli.GetName())
+		if prevTTL == nil {
+			continue
+		}
+		prevRegions := regionsByID(prevTTL)
+
+		regions := ttli.Regions[:0]
+		for j := range next.TimeToLives[i].Regions {
+			rgi := &next.TimeToLives[i].Regions[j]
+			if prevRegion := prevRegions[rgi.ID]; prevRegion == nil || !reflect.DeepEqual(prevRegion, rgi) {
+				regions = append(regions, ttli.Regions[j])
+			}
+		}
+		ttli.Regions = regions
+	}
+
+	for i := range prev.TimeToLives {
+		nextTTL := next.TimeToLive(prev.TimeToLives[i].Name)
+		if nextTTL == nil {
+			continue
+		}
+		nextRegions := regionsByID(nextTTL)
+		for _, rgi := range prev.TimeToLives[i].Regions {
+			if nextRegions[rgi.ID] == nil {
+				pb.DroppedRegions = append(pb.DroppedRegions, rgi.ID)
+			}
+		}
+	}
+
+	return pb
+}
+
+// regionsByID returns the regions of a time-to-live by ID.
+func regionsByID(ttli *TimeToLiveInfo) map[uint64]*RegionInfo {
+	m := make(map[uint64]*RegionInfo, len(ttli.Regions))
+	for i := range ttli.Regions {
+		m[ttli.Regions[i].ID] = &ttli.Regions[i]
+	}
+	return m
+}
+
+// applyDelta applies a change to the metadata. The change must follow the
+// current index of the metadata.
+func (data *Data) applyDelta(pb *internal.DataDelta) error {
+	if pb.GetPrevIndex() != data.Index {
+		return fmt.Errorf("change from index %d does not apply to index %d", pb.GetPrevIndex(), data.Index)
+	}
+
+	data.Term = pb.GetTerm()
+	data.Index = pb.GetIndex()
+	data.ClusterID = pb.GetClusterID()
+
+	data.MaxNodeID = pb.GetMaxNodeID()
+	data.MaxRegionID = pb.GetMaxRegionID()
+	data.MaxShardID = pb.GetMaxShardID()
+
+	if pb.GetNodesChanged() {
+		data.DataNodes = make([]NodeInfo, len(pb.GetDataNodes()))
+		for i, x := range pb.GetDataNodes() {
+			data.DataNodes[i].unmarshal(x)
+		}
+		data.MetaNodes = make([]NodeInfo, len(pb.GetMetaNodes()))
+		for i, x := range pb.GetMetaNodes() {
+			data.MetaNodes[i].unmarshal(x)
+		}
+	}
+
+	if pb.GetUsersChanged() {
+		data.Users = make([]UserInfo, len(pb.GetUsers()))
+		for i, x := range pb.GetUsers() {
+			data.Users[i].unmarshal(x)
+		}
+		data.adminUserExists = data.hasAdminUser()
+	}
+
+	if pb.GetRolesChanged() {
+		data.Roles = make([]RoleInfo, len(pb.GetRoles()))
+		for i, x := range pb.GetRoles() {
+			data.Roles[i].unmarshal(x)
+		}
+	}
+
+	if pb.GetUsersChanged() || pb.GetRolesChanged() {
+		data.updateRolePrivileges()
+	}
+
+	for _, name := range pb.GetDroppedDatabases() {
+		for i := range data.Databases {
+			if data.Databases[i].Name == name {
+				data.Databases = append(data.Databases[:i], data.Databases[i+1:]...)
+				break
+			}
+		}
+	}
+
+	for _, d := range pb.GetDatabases() {
+		var di DatabaseInfo
+		di.unmarshal(d.GetInfo())
+
+		prev := data.Database(di.Name)
+		if prev == nil {
+			data.Databases = append(data.Databases, di)
+			continue
+		}
+		mergeRegions(&di, prev, d.GetDroppedRegions())
+		*prev = di
+	}
+
+	return nil
+}
+
+// mergeRegions adds the regions of prev that are unchanged to the
+// time-to-lives of di, which only hold the changed regions.
+func mergeRegions(di, prev *DatabaseInfo, dropped []uint64) {
+	isDropped := make(map[uint64]bool, len(dropped))
+	for _, id := range dropped {
+		isDropped[id] = true
+	}
+
+	for i := range di.TimeToLives {
+		ttli := &di.TimeToLives[i]
+		prevTTL := prev.TimeToLive(ttli.Name)
+		if prevTTL == nil {
+			continue
+		}
+
+		changed := make(map[uint64]bool, len(ttli.Regions))
+		for _, rgi := range ttli.Regions {
+			changed[rgi.ID] = true
+		}
+
+		regions := make([]RegionInfo, 0, len(prevTTL.Regions)+len(ttli.Regions))
+		for _, rgi := range prevTTL.Regions {
+			if !changed[rgi.ID] && !isDropped[rgi.ID] {
+				regions = append(regions, rgi)
+			}
+		}
+		ttli.Regions = append(regions, ttli.Regions...)
+		sort.Sort(RegionInfos(ttli.Regions))
+	}
+}
@@ -0,0 +1,209 @@
+package meta
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	internal "github.com/cnosdatabase/cnosdb/meta/internal"
+	"github.com/cnosdatabase/cnosql"
+	"github.com/gogo/protobuf/proto"
+)
+
+// deltaTime is the time of the regions created by the changes.
+var deltaTime = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// deltaChanges are the changes of the metadata, each on top of the previous
+// ones.
+var deltaChanges = []struct {
+	name   string
+	change func(data *Data) error
+}{
+	{"CreateDataNodes", func(data *Data) error {
+		if err := data.CreateDataNode("host0:8086", "host0:8088"); err != nil {
+			return err
+		}
+		return data.CreateDataNode("host1:8086", "host1:8088")
+	}},
+	{"CreateDatabases", func(data *Data) error {
+		for _, name := range []string{"db0", "db1"} {
+			if err := data.CreateDatabase(name); err != nil {
+				return err
+			}
+			ttli := &TimeToLiveInfo{Name: "ttl0", ReplicaN: 1, RegionDuration: time.Hour}
+			if err := data.CreateTimeToLive(name, ttli, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}},
+	{"CreateTimeToLive", func(data *Data) error {
+		return data.CreateTimeToLive("db0", &TimeToLiveInfo{Name: "ttl1", ReplicaN: 2, RegionDuration: time.Hour}, false)
+	}},
+	{"CreateRegions", func(data *Data) error {
+		for i := 0; i < 3; i++ {
+			if err := data.CreateRegion("db0", "ttl0", deltaTime.Add(time.Duration(i)*time.Hour)); err != nil {
+				return err
+			}
+		}
+		return data.CreateRegion("db1", "ttl0", deltaTime)
+	}},
+	{"UpdateShardOwners", func(data *Data) error {
+		rg, err := data.RegionByTimestamp("db0", "ttl0", deltaTime.Add(time.Hour))
+		if err != nil {
+			return err
+		}
+		return data.UpdateShardOwners(rg.Shards[0].ID, []uint64{1, 2}, nil)
+	}},
+	{"DeleteRegion", func(data *Data) error {
+		rg, err := data.RegionByTimestamp("db0", "ttl0", deltaTime)
+		if err != nil {
+			return err
+		}
+		return data.DeleteRegion("db0", "ttl0", rg.ID)
+	}},
+	{"DropRegion", func(data *Data) error {
+		ttli, err := data.TimeToLive("db0", "ttl0")
+		if err != nil {
+			return err
+		}
+		ttli.Regions = ttli.Regions[1:]
+		return nil
+	}},
+	{"CreateContinuousQuery", func(data *Data) error {
+		return data.CreateContinuousQuery("db1", "cq0", `CREATE CONTINUOUS QUERY cq0 ON db1 BEGIN SELECT count(v) INTO c FROM cpu GROUP BY time(1m) END`)
+	}},
+	{"CreateUsers", func(data *Data) error {
+		if err := data.CreateUser("admin", "hash", true); err != nil {
+			return err
+		}
+		return data.CreateUser("user0", "hash", false)
+	}},
+	{"SetPrivilege", func(data *Data) error {
+		return data.SetPrivilege("user0", "db0", cnosql.ReadPrivilege)
+	}},
+	{"CreateRole", func(data *Data) error {
+		if err := data.CreateRole("role0"); err != nil {
+			return err
+		} else if err := data.SetRolePrivilege("role0", "db1", cnosql.WritePrivilege); err != nil {
+			return err
+		}
+		return data.SetUserRole("user0", "role0", true)
+	}},
+	{"DropUser", func(data *Data) error {
+		return data.DropUser("admin")
+	}},
+	{"DropTimeToLive", func(data *Data) error {
+		return data.DropTimeToLive("db0", "ttl1")
+	}},
+	{"DropDatabase", func(data *Data) error {
+		return data.DropDatabase("db1")
+	}},
+	{"DeleteDataNode", func(data *Data) error {
+		return data.DeleteDataNode(2)
+	}},
+}
+
+// nextData returns the metadata changed by change, at the next index.
+func nextData(t *testing.T, prev *Data, change func(data *Data) error) *Data {
+	t.Helper()
+	next := prev.Clone()
+	if err := change(next); err != nil {
+		t.Fatal(err)
+	}
+	next.Index++
+	return next
+}
+
+// normalizeData returns the metadata as unmarshaled, so that it compares
+// equal to the metadata with empty slices and maps instead of nil ones.
+func normalizeData(t *testing.T, data *Data) *Data {
+	t.Helper()
+	b, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := &Data{}
+	if err := other.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	return other
+}
+
+// marshalDelta returns pb as received by the clients.
+func marshalDelta(t *testing.T, pb *internal.DataDelta) *internal.DataDelta {
+	t.Helper()
+	b, err := proto.Marshal(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other internal.DataDelta
+	if err := proto.Unmarshal(b, &other); err != nil {
+		t.Fatal(err)
+	}
+	return &other
+}
+
+func TestData_ApplyDelta(t *testing.T) {
+	prev := &Data{Index: 1}
+	for _, tt := range deltaChanges {
+		t.Run(tt.name, func(t *testing.T) {
+			next := nextData(t, prev, tt.change)
+
+			got := prev.Clone()
+			if err := got.applyDelta(marshalDelta(t, diffData(prev.Index, prev, next))); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if !reflect.DeepEqual(normalizeData(t, got), normalizeData(t, next)) {
+				t.Fatalf("unexpected metadata:\n got %+v\n exp %+v", got, next)
+			} else if got.adminUserExists != next.adminUserExists {
+				t.Fatalf("got admin user exists %v, exp %v", got.adminUserExists, next.adminUserExists)
+			}
+		})
+		prev = nextData(t, prev, tt.change)
+	}
+}
+
+func TestData_ApplyDelta_Index(t *testing.T) {
+	prev := &Data{Index: 1}
+	next := nextData(t, prev, deltaChanges[0].change)
+	pb := diffData(prev.Index, prev, next)
+
+	// A change applies to the metadata at its previous index only.
+	if err := next.Clone().applyDelta(pb); err == nil {
+		t.Fatal("expected the change not to apply to the next index")
+	}
+}
+
+func TestDiffData_Unchanged(t *testing.T) {
+	prev := &Data{Index: 1}
+	for _, tt := range deltaChanges[:5] {
+		prev = nextData(t, prev, tt.change)
+	}
+
+	// A change of a region leaves out the other databases and regions.
+	next := nextData(t, prev, func(data *Data) error {
+		rg, err := data.RegionByTimestamp("db0", "ttl0", deltaTime.Add(2*time.Hour))
+		if err != nil {
+			return err
+		}
+		return data.UpdateShardOwners(rg.Shards[0].ID, []uint64{2}, nil)
+	})
+	pb := diffData(prev.Index, prev, next)
+	if pb.GetNodesChanged() || pb.GetUsersChanged() || pb.GetRolesChanged() {
+		t.Fatal("unexpected change of the nodes, the users or the roles")
+	} else if len(pb.GetDatabases()) != 1 || pb.GetDatabases()[0].GetInfo().GetName() != "db0" {
+		t.Fatalf("got %d changed databases, exp db0 only", len(pb.GetDatabases()))
+	}
+
+	for _, ttli := range pb.GetDatabases()[0].GetInfo().GetTimeToLives() {
+		if n, exp := len(ttli.GetRegions()), map[string]int{"ttl0": 1, "ttl1": 0}[ttli.GetName()]; n != exp {
+			t.Fatalf("got %d changed regions of %s, exp %d", n, ttli.GetName(), exp)
+		}
+	}
+
+	// No change leaves out everything.
+	pb = diffData(next.Index, next, next)
+	if pb.GetNodesChanged() || pb.GetUsersChanged() || pb.GetRolesChanged() || len(pb.GetDatabases()) != 0 || len(pb.GetDroppedDatabases()) != 0 {
+		t.Fatalf("unexpected change: %s", pb)
+	}
+}
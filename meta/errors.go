@@ -100,6 +100,12 @@ var (
 	// ErrShardNotReplicated is returned if the node requested to be dropped has
 	// the last copy of a shard present and the force keyword was not used
 	ErrShardNotReplicated = errors.New("shard not replicated")
+
+	// ErrShardNotFound is returned when mutating a shard that doesn't exist.
+	ErrShardNotFound = errors.New("shard not found")
+
+	// ErrShardOwnersRequired is returned when removing the last owner of a shard.
+	ErrShardOwnersRequired = errors.New("shard must have at least one owner")
 )
 
 var (
@@ -136,3 +142,18 @@ var (
 	// ErrAuthenticate is returned when authentication fails.
 	ErrAuthenticate = errors.New("authentication failed")
 )
+
+var (
+	// ErrRoleExists is returned when creating an already existing role.
+	ErrRoleExists = errors.New("role already exists")
+
+	// ErrRoleNotFound is returned when mutating a role that doesn't exist.
+	ErrRoleNotFound = errors.New("role not found")
+
+	// ErrRoleNameRequired is returned when creating a role without a name.
+	ErrRoleNameRequired = errors.New("role name required")
+
+	// ErrRoleCycle is returned when granting a role to a role that it
+	// inherits already.
+	ErrRoleCycle = errors.New("role would inherit itself")
+)
@@ -16,6 +16,8 @@ import (
 
 	internal "github.com/cnosdatabase/cnosdb/meta/internal"
 	"github.com/cnosdatabase/cnosdb/pkg/uuid"
+	"github.com/cnosdatabase/cnosdb/prometheus"
+	"github.com/cnosdatabase/db/models"
 	"github.com/gogo/protobuf/proto"
 	"github.com/gorilla/mux"
 	"github.com/hashicorp/raft"
@@ -39,15 +41,20 @@ type Handler struct {
 
 	router *mux.Router
 
+	// client sends the requests to the other meta nodes.
+	client *http.Client
+
 	logger         *zap.Logger
 	loggingEnabled bool // Log every HTTP access.
 	pprofEnabled   bool
 	store          interface {
 		afterIndex(index uint64) <-chan struct{}
 		index() uint64
+		isLeader() bool
 		leader() string
 		leaderHTTP() string
 		snapshot() (*Data, error)
+		changesSince(index uint64) ([]*internal.DataDelta, bool)
 		apply(b []byte) error
 		joinCluster(peers []string) (*NodeInfo, error)
 		addMetaNode(n *NodeInfo) (*NodeInfo, error)
@@ -69,6 +76,7 @@ func NewHandler(conf *ServerConfig) *Handler {
 	h := &Handler{
 		config: conf,
 		router: mux.NewRouter(),
+		client: http.DefaultClient,
 	}
 
 	h.AddRoutes([]route{
@@ -76,6 +84,10 @@ func NewHandler(conf *ServerConfig) *Handler {
 			"snapshot", http.MethodGet, "/", true, true,
 			h.serveSnapshot,
 		},
+		{
+			"changes", http.MethodGet, "/changes", true, true,
+			h.serveChanges,
+		},
 		{
 			"ping", http.MethodGet, "/ping", true, true,
 			h.servePing,
@@ -114,6 +126,13 @@ func NewHandler(conf *ServerConfig) *Handler {
 		},
 	}...)
 
+	if conf.MetricsEnabled {
+		h.AddRoutes(route{
+			"metrics", http.MethodGet, "/metrics", true, false,
+			h.serveMetrics,
+		})
+	}
+
 	return h
 }
 
@@ -173,6 +192,46 @@ func (h *Handler) serveSnapshot(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// serveChanges is a long polling http connection to serve the changes of the
+// cache after the index the client has. It responds with 410 Gone if the
+// changes are no longer kept, so that the client fetches a snapshot instead.
+func (h *Handler) serveChanges(w http.ResponseWriter, r *http.Request) {
+	if h.isClosed() {
+		h.httpError(fmt.Errorf("server closed"), w, http.StatusInternalServerError)
+		return
+	}
+
+	// get the current index that client has
+	index, err := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64)
+	if err != nil {
+		http.Error(w, "error parsing index", http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case <-h.store.afterIndex(index):
+		changes, ok := h.store.changesSince(index)
+		if !ok {
+			http.Error(w, "changes no longer available", http.StatusGone)
+			return
+		}
+		b, err := proto.Marshal(&internal.DataDeltas{Deltas: changes})
+		if err != nil {
+			h.httpError(err, w, http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("Content-Type", "application/octet-stream")
+		w.Write(b)
+		return
+	case <-w.(http.CloseNotifier).CloseNotify():
+		// Client closed the connection so we're done.
+		return
+	case <-h.closing:
+		h.httpError(fmt.Errorf("server closed"), w, http.StatusInternalServerError)
+		return
+	}
+}
+
 // servePing will return if the server is up, or if specified will check the status
 // of the other meta-servers as well
 func (h *Handler) servePing(w http.ResponseWriter, r *http.Request) {
@@ -192,7 +251,7 @@ func (h *Handler) servePing(w http.ResponseWriter, r *http.Request) {
 		}
 		url := scheme + n + "/ping"
 
-		resp, err := http.Get(url)
+		resp, err := h.client.Get(url)
 		if err != nil {
 			healthy = false
 			break
@@ -314,6 +373,36 @@ func (h *Handler) serveMetaServers(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// serveMetrics returns the statistics of the meta node in the Prometheus text exposition format.
+func (h *Handler) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	data, err := h.store.snapshot()
+	if err != nil {
+		h.httpError(err, w, http.StatusInternalServerError)
+		return
+	}
+
+	isLeader := 0
+	if h.store.isLeader() {
+		isLeader = 1
+	}
+
+	stat := models.NewStatistic("meta")
+	stat.Tags["nodeID"] = strconv.FormatUint(h.store.getNode().ID, 10)
+	stat.Values = map[string]interface{}{
+		"raftIndex":    int64(data.Index),
+		"isLeader":     isLeader,
+		"numPeers":     len(h.store.peers()),
+		"numMetaNodes": len(data.MetaNodes),
+		"numDataNodes": len(data.DataNodes),
+		"numDatabases": len(data.Databases),
+	}
+
+	w.Header().Set("Content-Type", prometheus.ContentType)
+	if err := prometheus.WriteMetrics(w, []models.Statistic{stat}); err != nil {
+		h.logger.Info("Error writing metrics", zap.Error(err))
+	}
+}
+
 func (h *Handler) serveExecute(w http.ResponseWriter, r *http.Request) {
 	if h.isClosed() {
 		h.httpError(fmt.Errorf("server closed"), w, http.StatusServiceUnavailable)
@@ -525,6 +614,12 @@ func (h *Handler) AddRoutes(routes ...route) {
 			handler = WrapWithGzipResponseWriter(handler)
 		}
 
+		// Anyone may ping the meta node, for health checks, and scrape its
+		// metrics if metrics-auth-enabled is disabled.
+		if h.config.AuthEnabled && r.Name != "ping" && (r.Name != "metrics" || h.config.MetricsAuthEnabled) {
+			handler = WrapWithAuthentication(handler, h.config.InternalSharedSecret)
+		}
+
 		handler = WrapWithVersionHeader(handler, h.Version)
 		handler = WrapWithRequestID(handler)
 
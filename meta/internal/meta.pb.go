@@ -23,35 +23,44 @@ const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
 type Command_Type int32
 
 const (
-	Command_CreateNodeCommand            Command_Type = 1
-	Command_DeleteNodeCommand            Command_Type = 2
-	Command_CreateDatabaseCommand        Command_Type = 3
-	Command_DropDatabaseCommand          Command_Type = 4
-	Command_CreateTimeToLiveCommand      Command_Type = 5
-	Command_DropTimeToLiveCommand        Command_Type = 6
-	Command_SetDefaultTimeToLiveCommand  Command_Type = 7
-	Command_UpdateTimeToLiveCommand      Command_Type = 8
-	Command_CreateRegionCommand          Command_Type = 9
-	Command_DeleteRegionCommand          Command_Type = 10
-	Command_CreateContinuousQueryCommand Command_Type = 11
-	Command_DropContinuousQueryCommand   Command_Type = 12
-	Command_CreateUserCommand            Command_Type = 13
-	Command_DropUserCommand              Command_Type = 14
-	Command_UpdateUserCommand            Command_Type = 15
-	Command_SetPrivilegeCommand          Command_Type = 16
-	Command_SetDataCommand               Command_Type = 17
-	Command_SetAdminPrivilegeCommand     Command_Type = 18
-	Command_UpdateNodeCommand            Command_Type = 19
-	Command_CreateSubscriptionCommand    Command_Type = 21
-	Command_DropSubscriptionCommand      Command_Type = 22
-	Command_RemovePeerCommand            Command_Type = 23
-	Command_CreateMetaNodeCommand        Command_Type = 24
-	Command_CreateDataNodeCommand        Command_Type = 25
-	Command_UpdateDataNodeCommand        Command_Type = 26
-	Command_DeleteMetaNodeCommand        Command_Type = 27
-	Command_DeleteDataNodeCommand        Command_Type = 28
-	Command_SetMetaNodeCommand           Command_Type = 29
-	Command_DropShardCommand             Command_Type = 30
+	Command_CreateNodeCommand                Command_Type = 1
+	Command_DeleteNodeCommand                Command_Type = 2
+	Command_CreateDatabaseCommand            Command_Type = 3
+	Command_DropDatabaseCommand              Command_Type = 4
+	Command_CreateTimeToLiveCommand          Command_Type = 5
+	Command_DropTimeToLiveCommand            Command_Type = 6
+	Command_SetDefaultTimeToLiveCommand      Command_Type = 7
+	Command_UpdateTimeToLiveCommand          Command_Type = 8
+	Command_CreateRegionCommand              Command_Type = 9
+	Command_DeleteRegionCommand              Command_Type = 10
+	Command_CreateContinuousQueryCommand     Command_Type = 11
+	Command_DropContinuousQueryCommand       Command_Type = 12
+	Command_CreateUserCommand                Command_Type = 13
+	Command_DropUserCommand                  Command_Type = 14
+	Command_UpdateUserCommand                Command_Type = 15
+	Command_SetPrivilegeCommand              Command_Type = 16
+	Command_SetDataCommand                   Command_Type = 17
+	Command_SetAdminPrivilegeCommand         Command_Type = 18
+	Command_UpdateNodeCommand                Command_Type = 19
+	Command_CreateSubscriptionCommand        Command_Type = 21
+	Command_DropSubscriptionCommand          Command_Type = 22
+	Command_RemovePeerCommand                Command_Type = 23
+	Command_CreateMetaNodeCommand            Command_Type = 24
+	Command_CreateDataNodeCommand            Command_Type = 25
+	Command_UpdateDataNodeCommand            Command_Type = 26
+	Command_DeleteMetaNodeCommand            Command_Type = 27
+	Command_DeleteDataNodeCommand            Command_Type = 28
+	Command_SetMetaNodeCommand               Command_Type = 29
+	Command_DropShardCommand                 Command_Type = 30
+	Command_UpdateShardOwnersCommand         Command_Type = 31
+	Command_SetContinuousQueryLastRunCommand Command_Type = 32
+	Command_SetSeriesPrivilegeCommand        Command_Type = 33
+	Command_CreateRoleCommand                Command_Type = 34
+	Command_DropRoleCommand                  Command_Type = 35
+	Command_SetRolePrivilegeCommand          Command_Type = 36
+	Command_SetUserRoleCommand               Command_Type = 37
+	Command_SetRoleRoleCommand               Command_Type = 38
+	Command_SetQuotaCommand                  Command_Type = 39
 )
 
 var Command_Type_name = map[int32]string{
@@ -84,38 +93,56 @@ var Command_Type_name = map[int32]string{
 	28: "DeleteDataNodeCommand",
 	29: "SetMetaNodeCommand",
 	30: "DropShardCommand",
+	31: "UpdateShardOwnersCommand",
+	32: "SetContinuousQueryLastRunCommand",
+	33: "SetSeriesPrivilegeCommand",
+	34: "CreateRoleCommand",
+	35: "DropRoleCommand",
+	36: "SetRolePrivilegeCommand",
+	37: "SetUserRoleCommand",
+	38: "SetRoleRoleCommand",
+	39: "SetQuotaCommand",
 }
 
 var Command_Type_value = map[string]int32{
-	"CreateNodeCommand":            1,
-	"DeleteNodeCommand":            2,
-	"CreateDatabaseCommand":        3,
-	"DropDatabaseCommand":          4,
-	"CreateTimeToLiveCommand":      5,
-	"DropTimeToLiveCommand":        6,
-	"SetDefaultTimeToLiveCommand":  7,
-	"UpdateTimeToLiveCommand":      8,
-	"CreateRegionCommand":          9,
-	"DeleteRegionCommand":          10,
-	"CreateContinuousQueryCommand": 11,
-	"DropContinuousQueryCommand":   12,
-	"CreateUserCommand":            13,
-	"DropUserCommand":              14,
-	"UpdateUserCommand":            15,
-	"SetPrivilegeCommand":          16,
-	"SetDataCommand":               17,
-	"SetAdminPrivilegeCommand":     18,
-	"UpdateNodeCommand":            19,
-	"CreateSubscriptionCommand":    21,
-	"DropSubscriptionCommand":      22,
-	"RemovePeerCommand":            23,
-	"CreateMetaNodeCommand":        24,
-	"CreateDataNodeCommand":        25,
-	"UpdateDataNodeCommand":        26,
-	"DeleteMetaNodeCommand":        27,
-	"DeleteDataNodeCommand":        28,
-	"SetMetaNodeCommand":           29,
-	"DropShardCommand":             30,
+	"CreateNodeCommand":                1,
+	"DeleteNodeCommand":                2,
+	"CreateDatabaseCommand":            3,
+	"DropDatabaseCommand":              4,
+	"CreateTimeToLiveCommand":          5,
+	"DropTimeToLiveCommand":            6,
+	"SetDefaultTimeToLiveCommand":      7,
+	"UpdateTimeToLiveCommand":          8,
+	"CreateRegionCommand":              9,
+	"DeleteRegionCommand":              10,
+	"CreateContinuousQueryCommand":     11,
+	"DropContinuousQueryCommand":       12,
+	"CreateUserCommand":                13,
+	"DropUserCommand":                  14,
+	"UpdateUserCommand":                15,
+	"SetPrivilegeCommand":              16,
+	"SetDataCommand":                   17,
+	"SetAdminPrivilegeCommand":         18,
+	"UpdateNodeCommand":                19,
+	"CreateSubscriptionCommand":        21,
+	"DropSubscriptionCommand":          22,
+	"RemovePeerCommand":                23,
+	"CreateMetaNodeCommand":            24,
+	"CreateDataNodeCommand":            25,
+	"UpdateDataNodeCommand":            26,
+	"DeleteMetaNodeCommand":            27,
+	"DeleteDataNodeCommand":            28,
+	"SetMetaNodeCommand":               29,
+	"DropShardCommand":                 30,
+	"UpdateShardOwnersCommand":         31,
+	"SetContinuousQueryLastRunCommand": 32,
+	"SetSeriesPrivilegeCommand":        33,
+	"CreateRoleCommand":                34,
+	"DropRoleCommand":                  35,
+	"SetRolePrivilegeCommand":          36,
+	"SetUserRoleCommand":               37,
+	"SetRoleRoleCommand":               38,
+	"SetQuotaCommand":                  39,
 }
 
 func (x Command_Type) Enum() *Command_Type {
@@ -138,7 +165,7 @@ func (x *Command_Type) UnmarshalJSON(data []byte) error {
 }
 
 func (Command_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{12, 0}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{18, 0}
 }
 
 type Data struct {
@@ -154,6 +181,7 @@ type Data struct {
 	// added for 0.10.0
 	DataNodes            []*NodeInfo `protobuf:"bytes,10,rep,name=DataNodes" json:"DataNodes,omitempty"`
 	MetaNodes            []*NodeInfo `protobuf:"bytes,11,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
+	Roles                []*RoleInfo `protobuf:"bytes,12,rep,name=Roles" json:"Roles,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
 	XXX_unrecognized     []byte      `json:"-"`
 	XXX_sizecache        int32       `json:"-"`
@@ -260,6 +288,13 @@ func (m *Data) GetMetaNodes() []*NodeInfo {
 	return nil
 }
 
+func (m *Data) GetRoles() []*RoleInfo {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
 type NodeInfo struct {
 	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	Host                 *string  `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
@@ -319,6 +354,7 @@ type DatabaseInfo struct {
 	DefaultTimeToLive    *string                `protobuf:"bytes,2,req,name=DefaultTimeToLive" json:"DefaultTimeToLive,omitempty"`
 	TimeToLives          []*TimeToLiveInfo      `protobuf:"bytes,3,rep,name=TimeToLives" json:"TimeToLives,omitempty"`
 	ContinuousQueries    []*ContinuousQueryInfo `protobuf:"bytes,4,rep,name=ContinuousQueries" json:"ContinuousQueries,omitempty"`
+	Quota                *Quota                 `protobuf:"bytes,5,opt,name=Quota" json:"Quota,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
 	XXX_unrecognized     []byte                 `json:"-"`
 	XXX_sizecache        int32                  `json:"-"`
@@ -376,6 +412,77 @@ func (m *DatabaseInfo) GetContinuousQueries() []*ContinuousQueryInfo {
 	return nil
 }
 
+func (m *DatabaseInfo) GetQuota() *Quota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+// Quota limits the writes and the queries of a database or a user. A zero
+// limit is unlimited.
+type Quota struct {
+	WritePoints          *int64   `protobuf:"varint,1,opt,name=WritePoints" json:"WritePoints,omitempty"`
+	WriteBytes           *int64   `protobuf:"varint,2,opt,name=WriteBytes" json:"WriteBytes,omitempty"`
+	Queries              *int64   `protobuf:"varint,3,opt,name=Queries" json:"Queries,omitempty"`
+	Series               *int64   `protobuf:"varint,4,opt,name=Series" json:"Series,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Quota) Reset()         { *m = Quota{} }
+func (m *Quota) String() string { return proto.CompactTextString(m) }
+func (*Quota) ProtoMessage()    {}
+func (*Quota) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{3}
+}
+func (m *Quota) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Quota.Unmarshal(m, b)
+}
+func (m *Quota) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Quota.Marshal(b, m, deterministic)
+}
+func (m *Quota) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Quota.Merge(m, src)
+}
+func (m *Quota) XXX_Size() int {
+	return xxx_messageInfo_Quota.Size(m)
+}
+func (m *Quota) XXX_DiscardUnknown() {
+	xxx_messageInfo_Quota.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Quota proto.InternalMessageInfo
+
+func (m *Quota) GetWritePoints() int64 {
+	if m != nil && m.WritePoints != nil {
+		return *m.WritePoints
+	}
+	return 0
+}
+
+func (m *Quota) GetWriteBytes() int64 {
+	if m != nil && m.WriteBytes != nil {
+		return *m.WriteBytes
+	}
+	return 0
+}
+
+func (m *Quota) GetQueries() int64 {
+	if m != nil && m.Queries != nil {
+		return *m.Queries
+	}
+	return 0
+}
+
+func (m *Quota) GetSeries() int64 {
+	if m != nil && m.Series != nil {
+		return *m.Series
+	}
+	return 0
+}
+
 type TimeToLiveSpec struct {
 	Name                 *string  `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
 	Duration             *int64   `protobuf:"varint,2,opt,name=Duration" json:"Duration,omitempty"`
@@ -390,7 +497,7 @@ func (m *TimeToLiveSpec) Reset()         { *m = TimeToLiveSpec{} }
 func (m *TimeToLiveSpec) String() string { return proto.CompactTextString(m) }
 func (*TimeToLiveSpec) ProtoMessage()    {}
 func (*TimeToLiveSpec) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{3}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{4}
 }
 func (m *TimeToLiveSpec) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TimeToLiveSpec.Unmarshal(m, b)
@@ -454,7 +561,7 @@ func (m *TimeToLiveInfo) Reset()         { *m = TimeToLiveInfo{} }
 func (m *TimeToLiveInfo) String() string { return proto.CompactTextString(m) }
 func (*TimeToLiveInfo) ProtoMessage()    {}
 func (*TimeToLiveInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{4}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{5}
 }
 func (m *TimeToLiveInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TimeToLiveInfo.Unmarshal(m, b)
@@ -532,7 +639,7 @@ func (m *RegionInfo) Reset()         { *m = RegionInfo{} }
 func (m *RegionInfo) String() string { return proto.CompactTextString(m) }
 func (*RegionInfo) ProtoMessage()    {}
 func (*RegionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{5}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{6}
 }
 func (m *RegionInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RegionInfo.Unmarshal(m, b)
@@ -607,7 +714,7 @@ func (m *ShardInfo) Reset()         { *m = ShardInfo{} }
 func (m *ShardInfo) String() string { return proto.CompactTextString(m) }
 func (*ShardInfo) ProtoMessage()    {}
 func (*ShardInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{6}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{7}
 }
 func (m *ShardInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShardInfo.Unmarshal(m, b)
@@ -653,6 +760,7 @@ type SubscriptionInfo struct {
 	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Mode                 *string  `protobuf:"bytes,2,req,name=Mode" json:"Mode,omitempty"`
 	Destinations         []string `protobuf:"bytes,3,rep,name=Destinations" json:"Destinations,omitempty"`
+	Durable              *bool    `protobuf:"varint,4,opt,name=Durable" json:"Durable,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -662,7 +770,7 @@ func (m *SubscriptionInfo) Reset()         { *m = SubscriptionInfo{} }
 func (m *SubscriptionInfo) String() string { return proto.CompactTextString(m) }
 func (*SubscriptionInfo) ProtoMessage()    {}
 func (*SubscriptionInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{7}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{8}
 }
 func (m *SubscriptionInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SubscriptionInfo.Unmarshal(m, b)
@@ -703,6 +811,13 @@ func (m *SubscriptionInfo) GetDestinations() []string {
 	return nil
 }
 
+func (m *SubscriptionInfo) GetDurable() bool {
+	if m != nil && m.Durable != nil {
+		return *m.Durable
+	}
+	return false
+}
+
 type ShardOwner struct {
 	NodeID               *uint64  `protobuf:"varint,1,req,name=NodeID" json:"NodeID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -714,7 +829,7 @@ func (m *ShardOwner) Reset()         { *m = ShardOwner{} }
 func (m *ShardOwner) String() string { return proto.CompactTextString(m) }
 func (*ShardOwner) ProtoMessage()    {}
 func (*ShardOwner) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{8}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{9}
 }
 func (m *ShardOwner) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ShardOwner.Unmarshal(m, b)
@@ -744,6 +859,9 @@ func (m *ShardOwner) GetNodeID() uint64 {
 type ContinuousQueryInfo struct {
 	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Query                *string  `protobuf:"bytes,2,req,name=Query" json:"Query,omitempty"`
+	LastRun              *int64   `protobuf:"varint,3,opt,name=LastRun" json:"LastRun,omitempty"`
+	LastError            *string  `protobuf:"bytes,4,opt,name=LastError" json:"LastError,omitempty"`
+	LastDuration         *int64   `protobuf:"varint,5,opt,name=LastDuration" json:"LastDuration,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -753,7 +871,7 @@ func (m *ContinuousQueryInfo) Reset()         { *m = ContinuousQueryInfo{} }
 func (m *ContinuousQueryInfo) String() string { return proto.CompactTextString(m) }
 func (*ContinuousQueryInfo) ProtoMessage()    {}
 func (*ContinuousQueryInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{9}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{10}
 }
 func (m *ContinuousQueryInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ContinuousQueryInfo.Unmarshal(m, b)
@@ -787,21 +905,45 @@ func (m *ContinuousQueryInfo) GetQuery() string {
 	return ""
 }
 
+func (m *ContinuousQueryInfo) GetLastRun() int64 {
+	if m != nil && m.LastRun != nil {
+		return *m.LastRun
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastError() string {
+	if m != nil && m.LastError != nil {
+		return *m.LastError
+	}
+	return ""
+}
+
+func (m *ContinuousQueryInfo) GetLastDuration() int64 {
+	if m != nil && m.LastDuration != nil {
+		return *m.LastDuration
+	}
+	return 0
+}
+
 type UserInfo struct {
-	Name                 *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Hash                 *string          `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
-	Admin                *bool            `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
-	Privileges           []*UserPrivilege `protobuf:"bytes,4,rep,name=Privileges" json:"Privileges,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Name                 *string                `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Hash                 *string                `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
+	Admin                *bool                  `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
+	Privileges           []*UserPrivilege       `protobuf:"bytes,4,rep,name=Privileges" json:"Privileges,omitempty"`
+	SeriesPrivileges     []*UserSeriesPrivilege `protobuf:"bytes,5,rep,name=SeriesPrivileges" json:"SeriesPrivileges,omitempty"`
+	Roles                []string               `protobuf:"bytes,6,rep,name=Roles" json:"Roles,omitempty"`
+	Quota                *Quota                 `protobuf:"bytes,7,opt,name=Quota" json:"Quota,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
 }
 
 func (m *UserInfo) Reset()         { *m = UserInfo{} }
 func (m *UserInfo) String() string { return proto.CompactTextString(m) }
 func (*UserInfo) ProtoMessage()    {}
 func (*UserInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{10}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{11}
 }
 func (m *UserInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserInfo.Unmarshal(m, b)
@@ -849,6 +991,27 @@ func (m *UserInfo) GetPrivileges() []*UserPrivilege {
 	return nil
 }
 
+func (m *UserInfo) GetSeriesPrivileges() []*UserSeriesPrivilege {
+	if m != nil {
+		return m.SeriesPrivileges
+	}
+	return nil
+}
+
+func (m *UserInfo) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *UserInfo) GetQuota() *Quota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
 type UserPrivilege struct {
 	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
 	Privilege            *int32   `protobuf:"varint,2,req,name=Privilege" json:"Privilege,omitempty"`
@@ -861,7 +1024,7 @@ func (m *UserPrivilege) Reset()         { *m = UserPrivilege{} }
 func (m *UserPrivilege) String() string { return proto.CompactTextString(m) }
 func (*UserPrivilege) ProtoMessage()    {}
 func (*UserPrivilege) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{11}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{12}
 }
 func (m *UserPrivilege) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_UserPrivilege.Unmarshal(m, b)
@@ -895,1792 +1058,2746 @@ func (m *UserPrivilege) GetPrivilege() int32 {
 	return 0
 }
 
-type Command struct {
-	Type                         *Command_Type `protobuf:"varint,1,req,name=type,enum=meta.Command_Type" json:"type,omitempty"`
-	XXX_NoUnkeyedLiteral         struct{}      `json:"-"`
-	proto.XXX_InternalExtensions `json:"-"`
-	XXX_unrecognized             []byte `json:"-"`
-	XXX_sizecache                int32  `json:"-"`
-}
-
-func (m *Command) Reset()         { *m = Command{} }
-func (m *Command) String() string { return proto.CompactTextString(m) }
-func (*Command) ProtoMessage()    {}
-func (*Command) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{12}
+// RoleInfo is a set of privileges granted to the users with the role. A role
+// also has the privileges of the roles it inherits.
+type RoleInfo struct {
+	Name                 *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Privileges           []*UserPrivilege `protobuf:"bytes,2,rep,name=Privileges" json:"Privileges,omitempty"`
+	Roles                []string         `protobuf:"bytes,3,rep,name=Roles" json:"Roles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
-var extRange_Command = []proto.ExtensionRange{
-	{Start: 100, End: 536870911},
+func (m *RoleInfo) Reset()         { *m = RoleInfo{} }
+func (m *RoleInfo) String() string { return proto.CompactTextString(m) }
+func (*RoleInfo) ProtoMessage()    {}
+func (*RoleInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{13}
 }
-
-func (*Command) ExtensionRangeArray() []proto.ExtensionRange {
-	return extRange_Command
+func (m *RoleInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleInfo.Unmarshal(m, b)
 }
-
-func (m *Command) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Command.Unmarshal(m, b)
+func (m *RoleInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleInfo.Marshal(b, m, deterministic)
 }
-func (m *Command) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Command.Marshal(b, m, deterministic)
+func (m *RoleInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleInfo.Merge(m, src)
 }
-func (m *Command) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Command.Merge(m, src)
+func (m *RoleInfo) XXX_Size() int {
+	return xxx_messageInfo_RoleInfo.Size(m)
 }
-func (m *Command) XXX_Size() int {
-	return xxx_messageInfo_Command.Size(m)
+func (m *RoleInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleInfo.DiscardUnknown(m)
 }
-func (m *Command) XXX_DiscardUnknown() {
-	xxx_messageInfo_Command.DiscardUnknown(m)
+
+var xxx_messageInfo_RoleInfo proto.InternalMessageInfo
+
+func (m *RoleInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
 }
 
-var xxx_messageInfo_Command proto.InternalMessageInfo
+func (m *RoleInfo) GetPrivileges() []*UserPrivilege {
+	if m != nil {
+		return m.Privileges
+	}
+	return nil
+}
 
-func (m *Command) GetType() Command_Type {
-	if m != nil && m.Type != nil {
-		return *m.Type
+func (m *RoleInfo) GetRoles() []string {
+	if m != nil {
+		return m.Roles
 	}
-	return Command_CreateNodeCommand
+	return nil
 }
 
-// This isn't used in >= 0.10.0. Kept around for upgrade purposes. Instead
-// look at CreateDataNodeCommand and CreateMetaNodeCommand
-type CreateNodeCommand struct {
-	Host                 *string  `protobuf:"bytes,1,req,name=Host" json:"Host,omitempty"`
-	Rand                 *uint64  `protobuf:"varint,2,req,name=Rand" json:"Rand,omitempty"`
+// UserSeriesPrivilege is a privilege on the series of a metric, or of all
+// metrics if Metric is empty, whose tags match the condition.
+type UserSeriesPrivilege struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Metric               *string  `protobuf:"bytes,2,opt,name=Metric" json:"Metric,omitempty"`
+	Condition            *string  `protobuf:"bytes,3,opt,name=Condition" json:"Condition,omitempty"`
+	Privilege            *int32   `protobuf:"varint,4,req,name=Privilege" json:"Privilege,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CreateNodeCommand) Reset()         { *m = CreateNodeCommand{} }
-func (m *CreateNodeCommand) String() string { return proto.CompactTextString(m) }
-func (*CreateNodeCommand) ProtoMessage()    {}
-func (*CreateNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{13}
+func (m *UserSeriesPrivilege) Reset()         { *m = UserSeriesPrivilege{} }
+func (m *UserSeriesPrivilege) String() string { return proto.CompactTextString(m) }
+func (*UserSeriesPrivilege) ProtoMessage()    {}
+func (*UserSeriesPrivilege) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{14}
 }
-func (m *CreateNodeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateNodeCommand.Unmarshal(m, b)
+func (m *UserSeriesPrivilege) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserSeriesPrivilege.Unmarshal(m, b)
 }
-func (m *CreateNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateNodeCommand.Marshal(b, m, deterministic)
+func (m *UserSeriesPrivilege) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserSeriesPrivilege.Marshal(b, m, deterministic)
 }
-func (m *CreateNodeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateNodeCommand.Merge(m, src)
+func (m *UserSeriesPrivilege) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserSeriesPrivilege.Merge(m, src)
 }
-func (m *CreateNodeCommand) XXX_Size() int {
-	return xxx_messageInfo_CreateNodeCommand.Size(m)
+func (m *UserSeriesPrivilege) XXX_Size() int {
+	return xxx_messageInfo_UserSeriesPrivilege.Size(m)
 }
-func (m *CreateNodeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateNodeCommand.DiscardUnknown(m)
+func (m *UserSeriesPrivilege) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserSeriesPrivilege.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CreateNodeCommand proto.InternalMessageInfo
+var xxx_messageInfo_UserSeriesPrivilege proto.InternalMessageInfo
 
-func (m *CreateNodeCommand) GetHost() string {
-	if m != nil && m.Host != nil {
-		return *m.Host
+func (m *UserSeriesPrivilege) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
 	}
 	return ""
 }
 
-func (m *CreateNodeCommand) GetRand() uint64 {
-	if m != nil && m.Rand != nil {
-		return *m.Rand
+func (m *UserSeriesPrivilege) GetMetric() string {
+	if m != nil && m.Metric != nil {
+		return *m.Metric
 	}
-	return 0
+	return ""
 }
 
-var E_CreateNodeCommand_Command = &proto.ExtensionDesc{
-	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*CreateNodeCommand)(nil),
-	Field:         101,
-	Name:          "meta.CreateNodeCommand.command",
-	Tag:           "bytes,101,opt,name=command",
-	Filename:      "meta.proto",
+func (m *UserSeriesPrivilege) GetCondition() string {
+	if m != nil && m.Condition != nil {
+		return *m.Condition
+	}
+	return ""
 }
 
-type DeleteNodeCommand struct {
-	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
-	Force                *bool    `protobuf:"varint,2,req,name=Force" json:"Force,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *UserSeriesPrivilege) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
+	}
+	return 0
 }
 
-func (m *DeleteNodeCommand) Reset()         { *m = DeleteNodeCommand{} }
-func (m *DeleteNodeCommand) String() string { return proto.CompactTextString(m) }
-func (*DeleteNodeCommand) ProtoMessage()    {}
-func (*DeleteNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{14}
+// DataDelta is the change of the metadata by the command applied at Index.
+// It applies to the metadata at PrevIndex, the index of the command applied
+// before, which is not always Index-1 since not every raft log entry is a
+// command.
+type DataDelta struct {
+	PrevIndex   *uint64 `protobuf:"varint,1,req,name=PrevIndex" json:"PrevIndex,omitempty"`
+	Term        *uint64 `protobuf:"varint,2,req,name=Term" json:"Term,omitempty"`
+	Index       *uint64 `protobuf:"varint,3,req,name=Index" json:"Index,omitempty"`
+	ClusterID   *uint64 `protobuf:"varint,4,req,name=ClusterID" json:"ClusterID,omitempty"`
+	MaxNodeID   *uint64 `protobuf:"varint,5,req,name=MaxNodeID" json:"MaxNodeID,omitempty"`
+	MaxRegionID *uint64 `protobuf:"varint,6,req,name=MaxRegionID" json:"MaxRegionID,omitempty"`
+	MaxShardID  *uint64 `protobuf:"varint,7,req,name=MaxShardID" json:"MaxShardID,omitempty"`
+	// The nodes and the users are small, so they are sent whole if changed.
+	NodesChanged         *bool            `protobuf:"varint,8,opt,name=NodesChanged" json:"NodesChanged,omitempty"`
+	DataNodes            []*NodeInfo      `protobuf:"bytes,9,rep,name=DataNodes" json:"DataNodes,omitempty"`
+	MetaNodes            []*NodeInfo      `protobuf:"bytes,10,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
+	UsersChanged         *bool            `protobuf:"varint,11,opt,name=UsersChanged" json:"UsersChanged,omitempty"`
+	Users                []*UserInfo      `protobuf:"bytes,12,rep,name=Users" json:"Users,omitempty"`
+	Databases            []*DatabaseDelta `protobuf:"bytes,13,rep,name=Databases" json:"Databases,omitempty"`
+	DroppedDatabases     []string         `protobuf:"bytes,14,rep,name=DroppedDatabases" json:"DroppedDatabases,omitempty"`
+	RolesChanged         *bool            `protobuf:"varint,15,opt,name=RolesChanged" json:"RolesChanged,omitempty"`
+	Roles                []*RoleInfo      `protobuf:"bytes,16,rep,name=Roles" json:"Roles,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
-func (m *DeleteNodeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DeleteNodeCommand.Unmarshal(m, b)
+
+func (m *DataDelta) Reset()         { *m = DataDelta{} }
+func (m *DataDelta) String() string { return proto.CompactTextString(m) }
+func (*DataDelta) ProtoMessage()    {}
+func (*DataDelta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{15}
 }
-func (m *DeleteNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DeleteNodeCommand.Marshal(b, m, deterministic)
+func (m *DataDelta) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DataDelta.Unmarshal(m, b)
 }
-func (m *DeleteNodeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteNodeCommand.Merge(m, src)
+func (m *DataDelta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DataDelta.Marshal(b, m, deterministic)
 }
-func (m *DeleteNodeCommand) XXX_Size() int {
-	return xxx_messageInfo_DeleteNodeCommand.Size(m)
+func (m *DataDelta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DataDelta.Merge(m, src)
 }
-func (m *DeleteNodeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteNodeCommand.DiscardUnknown(m)
+func (m *DataDelta) XXX_Size() int {
+	return xxx_messageInfo_DataDelta.Size(m)
+}
+func (m *DataDelta) XXX_DiscardUnknown() {
+	xxx_messageInfo_DataDelta.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DeleteNodeCommand proto.InternalMessageInfo
+var xxx_messageInfo_DataDelta proto.InternalMessageInfo
 
-func (m *DeleteNodeCommand) GetID() uint64 {
-	if m != nil && m.ID != nil {
-		return *m.ID
+func (m *DataDelta) GetPrevIndex() uint64 {
+	if m != nil && m.PrevIndex != nil {
+		return *m.PrevIndex
 	}
 	return 0
 }
 
-func (m *DeleteNodeCommand) GetForce() bool {
-	if m != nil && m.Force != nil {
-		return *m.Force
+func (m *DataDelta) GetTerm() uint64 {
+	if m != nil && m.Term != nil {
+		return *m.Term
 	}
-	return false
+	return 0
 }
 
-var E_DeleteNodeCommand_Command = &proto.ExtensionDesc{
-	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DeleteNodeCommand)(nil),
-	Field:         102,
-	Name:          "meta.DeleteNodeCommand.command",
-	Tag:           "bytes,102,opt,name=command",
-	Filename:      "meta.proto",
+func (m *DataDelta) GetIndex() uint64 {
+	if m != nil && m.Index != nil {
+		return *m.Index
+	}
+	return 0
 }
 
-type CreateDatabaseCommand struct {
-	Name                 *string         `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	TimeToLive           *TimeToLiveInfo `protobuf:"bytes,2,opt,name=TimeToLive" json:"TimeToLive,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+func (m *DataDelta) GetClusterID() uint64 {
+	if m != nil && m.ClusterID != nil {
+		return *m.ClusterID
+	}
+	return 0
 }
 
-func (m *CreateDatabaseCommand) Reset()         { *m = CreateDatabaseCommand{} }
-func (m *CreateDatabaseCommand) String() string { return proto.CompactTextString(m) }
+func (m *DataDelta) GetMaxNodeID() uint64 {
+	if m != nil && m.MaxNodeID != nil {
+		return *m.MaxNodeID
+	}
+	return 0
+}
+
+func (m *DataDelta) GetMaxRegionID() uint64 {
+	if m != nil && m.MaxRegionID != nil {
+		return *m.MaxRegionID
+	}
+	return 0
+}
+
+func (m *DataDelta) GetMaxShardID() uint64 {
+	if m != nil && m.MaxShardID != nil {
+		return *m.MaxShardID
+	}
+	return 0
+}
+
+func (m *DataDelta) GetNodesChanged() bool {
+	if m != nil && m.NodesChanged != nil {
+		return *m.NodesChanged
+	}
+	return false
+}
+
+func (m *DataDelta) GetDataNodes() []*NodeInfo {
+	if m != nil {
+		return m.DataNodes
+	}
+	return nil
+}
+
+func (m *DataDelta) GetMetaNodes() []*NodeInfo {
+	if m != nil {
+		return m.MetaNodes
+	}
+	return nil
+}
+
+func (m *DataDelta) GetUsersChanged() bool {
+	if m != nil && m.UsersChanged != nil {
+		return *m.UsersChanged
+	}
+	return false
+}
+
+func (m *DataDelta) GetUsers() []*UserInfo {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *DataDelta) GetDatabases() []*DatabaseDelta {
+	if m != nil {
+		return m.Databases
+	}
+	return nil
+}
+
+func (m *DataDelta) GetDroppedDatabases() []string {
+	if m != nil {
+		return m.DroppedDatabases
+	}
+	return nil
+}
+
+func (m *DataDelta) GetRolesChanged() bool {
+	if m != nil && m.RolesChanged != nil {
+		return *m.RolesChanged
+	}
+	return false
+}
+
+func (m *DataDelta) GetRoles() []*RoleInfo {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+// DatabaseDelta is a database that was created or changed. Its time-to-lives
+// only hold the regions that were created or changed.
+type DatabaseDelta struct {
+	Info                 *DatabaseInfo `protobuf:"bytes,1,req,name=Info" json:"Info,omitempty"`
+	DroppedRegions       []uint64      `protobuf:"varint,2,rep,name=DroppedRegions" json:"DroppedRegions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *DatabaseDelta) Reset()         { *m = DatabaseDelta{} }
+func (m *DatabaseDelta) String() string { return proto.CompactTextString(m) }
+func (*DatabaseDelta) ProtoMessage()    {}
+func (*DatabaseDelta) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{16}
+}
+func (m *DatabaseDelta) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DatabaseDelta.Unmarshal(m, b)
+}
+func (m *DatabaseDelta) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DatabaseDelta.Marshal(b, m, deterministic)
+}
+func (m *DatabaseDelta) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DatabaseDelta.Merge(m, src)
+}
+func (m *DatabaseDelta) XXX_Size() int {
+	return xxx_messageInfo_DatabaseDelta.Size(m)
+}
+func (m *DatabaseDelta) XXX_DiscardUnknown() {
+	xxx_messageInfo_DatabaseDelta.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DatabaseDelta proto.InternalMessageInfo
+
+func (m *DatabaseDelta) GetInfo() *DatabaseInfo {
+	if m != nil {
+		return m.Info
+	}
+	return nil
+}
+
+func (m *DatabaseDelta) GetDroppedRegions() []uint64 {
+	if m != nil {
+		return m.DroppedRegions
+	}
+	return nil
+}
+
+type DataDeltas struct {
+	Deltas               []*DataDelta `protobuf:"bytes,1,rep,name=Deltas" json:"Deltas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *DataDeltas) Reset()         { *m = DataDeltas{} }
+func (m *DataDeltas) String() string { return proto.CompactTextString(m) }
+func (*DataDeltas) ProtoMessage()    {}
+func (*DataDeltas) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{17}
+}
+func (m *DataDeltas) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DataDeltas.Unmarshal(m, b)
+}
+func (m *DataDeltas) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DataDeltas.Marshal(b, m, deterministic)
+}
+func (m *DataDeltas) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DataDeltas.Merge(m, src)
+}
+func (m *DataDeltas) XXX_Size() int {
+	return xxx_messageInfo_DataDeltas.Size(m)
+}
+func (m *DataDeltas) XXX_DiscardUnknown() {
+	xxx_messageInfo_DataDeltas.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DataDeltas proto.InternalMessageInfo
+
+func (m *DataDeltas) GetDeltas() []*DataDelta {
+	if m != nil {
+		return m.Deltas
+	}
+	return nil
+}
+
+type Command struct {
+	Type                         *Command_Type `protobuf:"varint,1,req,name=type,enum=meta.Command_Type" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral         struct{}      `json:"-"`
+	proto.XXX_InternalExtensions `json:"-"`
+	XXX_unrecognized             []byte `json:"-"`
+	XXX_sizecache                int32  `json:"-"`
+}
+
+func (m *Command) Reset()         { *m = Command{} }
+func (m *Command) String() string { return proto.CompactTextString(m) }
+func (*Command) ProtoMessage()    {}
+func (*Command) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{18}
+}
+
+var extRange_Command = []proto.ExtensionRange{
+	{Start: 100, End: 536870911},
+}
+
+func (*Command) ExtensionRangeArray() []proto.ExtensionRange {
+	return extRange_Command
+}
+
+func (m *Command) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Command.Unmarshal(m, b)
+}
+func (m *Command) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Command.Marshal(b, m, deterministic)
+}
+func (m *Command) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Command.Merge(m, src)
+}
+func (m *Command) XXX_Size() int {
+	return xxx_messageInfo_Command.Size(m)
+}
+func (m *Command) XXX_DiscardUnknown() {
+	xxx_messageInfo_Command.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Command proto.InternalMessageInfo
+
+func (m *Command) GetType() Command_Type {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return Command_CreateNodeCommand
+}
+
+// This isn't used in >= 0.10.0. Kept around for upgrade purposes. Instead
+// look at CreateDataNodeCommand and CreateMetaNodeCommand
+type CreateNodeCommand struct {
+	Host                 *string  `protobuf:"bytes,1,req,name=Host" json:"Host,omitempty"`
+	Rand                 *uint64  `protobuf:"varint,2,req,name=Rand" json:"Rand,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateNodeCommand) Reset()         { *m = CreateNodeCommand{} }
+func (m *CreateNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateNodeCommand) ProtoMessage()    {}
+func (*CreateNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{19}
+}
+func (m *CreateNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateNodeCommand.Unmarshal(m, b)
+}
+func (m *CreateNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateNodeCommand.Marshal(b, m, deterministic)
+}
+func (m *CreateNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateNodeCommand.Merge(m, src)
+}
+func (m *CreateNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateNodeCommand.Size(m)
+}
+func (m *CreateNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateNodeCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateNodeCommand proto.InternalMessageInfo
+
+func (m *CreateNodeCommand) GetHost() string {
+	if m != nil && m.Host != nil {
+		return *m.Host
+	}
+	return ""
+}
+
+func (m *CreateNodeCommand) GetRand() uint64 {
+	if m != nil && m.Rand != nil {
+		return *m.Rand
+	}
+	return 0
+}
+
+var E_CreateNodeCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*CreateNodeCommand)(nil),
+	Field:         101,
+	Name:          "meta.CreateNodeCommand.command",
+	Tag:           "bytes,101,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+type DeleteNodeCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Force                *bool    `protobuf:"varint,2,req,name=Force" json:"Force,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteNodeCommand) Reset()         { *m = DeleteNodeCommand{} }
+func (m *DeleteNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*DeleteNodeCommand) ProtoMessage()    {}
+func (*DeleteNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{20}
+}
+func (m *DeleteNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteNodeCommand.Unmarshal(m, b)
+}
+func (m *DeleteNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteNodeCommand.Marshal(b, m, deterministic)
+}
+func (m *DeleteNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteNodeCommand.Merge(m, src)
+}
+func (m *DeleteNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_DeleteNodeCommand.Size(m)
+}
+func (m *DeleteNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteNodeCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteNodeCommand proto.InternalMessageInfo
+
+func (m *DeleteNodeCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *DeleteNodeCommand) GetForce() bool {
+	if m != nil && m.Force != nil {
+		return *m.Force
+	}
+	return false
+}
+
+var E_DeleteNodeCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*DeleteNodeCommand)(nil),
+	Field:         102,
+	Name:          "meta.DeleteNodeCommand.command",
+	Tag:           "bytes,102,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+type CreateDatabaseCommand struct {
+	Name                 *string         `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	TimeToLive           *TimeToLiveInfo `protobuf:"bytes,2,opt,name=TimeToLive" json:"TimeToLive,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CreateDatabaseCommand) Reset()         { *m = CreateDatabaseCommand{} }
+func (m *CreateDatabaseCommand) String() string { return proto.CompactTextString(m) }
 func (*CreateDatabaseCommand) ProtoMessage()    {}
 func (*CreateDatabaseCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{15}
+	return fileDescriptor_3b5ea8fe65782bcc, []int{21}
+}
+func (m *CreateDatabaseCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateDatabaseCommand.Unmarshal(m, b)
+}
+func (m *CreateDatabaseCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateDatabaseCommand.Marshal(b, m, deterministic)
+}
+func (m *CreateDatabaseCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateDatabaseCommand.Merge(m, src)
+}
+func (m *CreateDatabaseCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateDatabaseCommand.Size(m)
+}
+func (m *CreateDatabaseCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateDatabaseCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateDatabaseCommand proto.InternalMessageInfo
+
+func (m *CreateDatabaseCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *CreateDatabaseCommand) GetTimeToLive() *TimeToLiveInfo {
+	if m != nil {
+		return m.TimeToLive
+	}
+	return nil
+}
+
+var E_CreateDatabaseCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*CreateDatabaseCommand)(nil),
+	Field:         103,
+	Name:          "meta.CreateDatabaseCommand.command",
+	Tag:           "bytes,103,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+type DropDatabaseCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DropDatabaseCommand) Reset()         { *m = DropDatabaseCommand{} }
+func (m *DropDatabaseCommand) String() string { return proto.CompactTextString(m) }
+func (*DropDatabaseCommand) ProtoMessage()    {}
+func (*DropDatabaseCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{22}
+}
+func (m *DropDatabaseCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropDatabaseCommand.Unmarshal(m, b)
+}
+func (m *DropDatabaseCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropDatabaseCommand.Marshal(b, m, deterministic)
+}
+func (m *DropDatabaseCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropDatabaseCommand.Merge(m, src)
+}
+func (m *DropDatabaseCommand) XXX_Size() int {
+	return xxx_messageInfo_DropDatabaseCommand.Size(m)
+}
+func (m *DropDatabaseCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropDatabaseCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropDatabaseCommand proto.InternalMessageInfo
+
+func (m *DropDatabaseCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_DropDatabaseCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*DropDatabaseCommand)(nil),
+	Field:         104,
+	Name:          "meta.DropDatabaseCommand.command",
+	Tag:           "bytes,104,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+type CreateTimeToLiveCommand struct {
+	Database             *string         `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	TimeToLive           *TimeToLiveInfo `protobuf:"bytes,2,req,name=TimeToLive" json:"TimeToLive,omitempty"`
+	Default              *bool           `protobuf:"varint,3,req,name=Default" json:"Default,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CreateTimeToLiveCommand) Reset()         { *m = CreateTimeToLiveCommand{} }
+func (m *CreateTimeToLiveCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateTimeToLiveCommand) ProtoMessage()    {}
+func (*CreateTimeToLiveCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{23}
+}
+func (m *CreateTimeToLiveCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateTimeToLiveCommand.Unmarshal(m, b)
+}
+func (m *CreateTimeToLiveCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateTimeToLiveCommand.Marshal(b, m, deterministic)
+}
+func (m *CreateTimeToLiveCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateTimeToLiveCommand.Merge(m, src)
+}
+func (m *CreateTimeToLiveCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateTimeToLiveCommand.Size(m)
+}
+func (m *CreateTimeToLiveCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateTimeToLiveCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateTimeToLiveCommand proto.InternalMessageInfo
+
+func (m *CreateTimeToLiveCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *CreateTimeToLiveCommand) GetTimeToLive() *TimeToLiveInfo {
+	if m != nil {
+		return m.TimeToLive
+	}
+	return nil
+}
+
+func (m *CreateTimeToLiveCommand) GetDefault() bool {
+	if m != nil && m.Default != nil {
+		return *m.Default
+	}
+	return false
+}
+
+var E_CreateTimeToLiveCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*CreateTimeToLiveCommand)(nil),
+	Field:         105,
+	Name:          "meta.CreateTimeToLiveCommand.command",
+	Tag:           "bytes,105,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+type DropTimeToLiveCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DropTimeToLiveCommand) Reset()         { *m = DropTimeToLiveCommand{} }
+func (m *DropTimeToLiveCommand) String() string { return proto.CompactTextString(m) }
+func (*DropTimeToLiveCommand) ProtoMessage()    {}
+func (*DropTimeToLiveCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{24}
+}
+func (m *DropTimeToLiveCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropTimeToLiveCommand.Unmarshal(m, b)
+}
+func (m *DropTimeToLiveCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropTimeToLiveCommand.Marshal(b, m, deterministic)
+}
+func (m *DropTimeToLiveCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropTimeToLiveCommand.Merge(m, src)
+}
+func (m *DropTimeToLiveCommand) XXX_Size() int {
+	return xxx_messageInfo_DropTimeToLiveCommand.Size(m)
+}
+func (m *DropTimeToLiveCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropTimeToLiveCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DropTimeToLiveCommand proto.InternalMessageInfo
+
+func (m *DropTimeToLiveCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *DropTimeToLiveCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_DropTimeToLiveCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*DropTimeToLiveCommand)(nil),
+	Field:         106,
+	Name:          "meta.DropTimeToLiveCommand.command",
+	Tag:           "bytes,106,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+type SetDefaultTimeToLiveCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetDefaultTimeToLiveCommand) Reset()         { *m = SetDefaultTimeToLiveCommand{} }
+func (m *SetDefaultTimeToLiveCommand) String() string { return proto.CompactTextString(m) }
+func (*SetDefaultTimeToLiveCommand) ProtoMessage()    {}
+func (*SetDefaultTimeToLiveCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{25}
+}
+func (m *SetDefaultTimeToLiveCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetDefaultTimeToLiveCommand.Unmarshal(m, b)
+}
+func (m *SetDefaultTimeToLiveCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetDefaultTimeToLiveCommand.Marshal(b, m, deterministic)
+}
+func (m *SetDefaultTimeToLiveCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetDefaultTimeToLiveCommand.Merge(m, src)
+}
+func (m *SetDefaultTimeToLiveCommand) XXX_Size() int {
+	return xxx_messageInfo_SetDefaultTimeToLiveCommand.Size(m)
+}
+func (m *SetDefaultTimeToLiveCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetDefaultTimeToLiveCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetDefaultTimeToLiveCommand proto.InternalMessageInfo
+
+func (m *SetDefaultTimeToLiveCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetDefaultTimeToLiveCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_SetDefaultTimeToLiveCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetDefaultTimeToLiveCommand)(nil),
+	Field:         107,
+	Name:          "meta.SetDefaultTimeToLiveCommand.command",
+	Tag:           "bytes,107,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+type UpdateTimeToLiveCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	NewName              *string  `protobuf:"bytes,3,opt,name=NewName" json:"NewName,omitempty"`
+	Duration             *int64   `protobuf:"varint,4,opt,name=Duration" json:"Duration,omitempty"`
+	ReplicaN             *uint32  `protobuf:"varint,5,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
+	Default              *bool    `protobuf:"varint,6,req,name=Default" json:"Default,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateTimeToLiveCommand) Reset()         { *m = UpdateTimeToLiveCommand{} }
+func (m *UpdateTimeToLiveCommand) String() string { return proto.CompactTextString(m) }
+func (*UpdateTimeToLiveCommand) ProtoMessage()    {}
+func (*UpdateTimeToLiveCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{26}
+}
+func (m *UpdateTimeToLiveCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateTimeToLiveCommand.Unmarshal(m, b)
+}
+func (m *UpdateTimeToLiveCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateTimeToLiveCommand.Marshal(b, m, deterministic)
+}
+func (m *UpdateTimeToLiveCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateTimeToLiveCommand.Merge(m, src)
+}
+func (m *UpdateTimeToLiveCommand) XXX_Size() int {
+	return xxx_messageInfo_UpdateTimeToLiveCommand.Size(m)
+}
+func (m *UpdateTimeToLiveCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateTimeToLiveCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateTimeToLiveCommand proto.InternalMessageInfo
+
+func (m *UpdateTimeToLiveCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *UpdateTimeToLiveCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *UpdateTimeToLiveCommand) GetNewName() string {
+	if m != nil && m.NewName != nil {
+		return *m.NewName
+	}
+	return ""
+}
+
+func (m *UpdateTimeToLiveCommand) GetDuration() int64 {
+	if m != nil && m.Duration != nil {
+		return *m.Duration
+	}
+	return 0
+}
+
+func (m *UpdateTimeToLiveCommand) GetReplicaN() uint32 {
+	if m != nil && m.ReplicaN != nil {
+		return *m.ReplicaN
+	}
+	return 0
+}
+
+func (m *UpdateTimeToLiveCommand) GetDefault() bool {
+	if m != nil && m.Default != nil {
+		return *m.Default
+	}
+	return false
+}
+
+var E_UpdateTimeToLiveCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*UpdateTimeToLiveCommand)(nil),
+	Field:         108,
+	Name:          "meta.UpdateTimeToLiveCommand.command",
+	Tag:           "bytes,108,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+type CreateRegionCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	TimeToLive           *string  `protobuf:"bytes,2,req,name=TimeToLive" json:"TimeToLive,omitempty"`
+	Timestamp            *int64   `protobuf:"varint,3,req,name=Timestamp" json:"Timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateRegionCommand) Reset()         { *m = CreateRegionCommand{} }
+func (m *CreateRegionCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateRegionCommand) ProtoMessage()    {}
+func (*CreateRegionCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{27}
+}
+func (m *CreateRegionCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateRegionCommand.Unmarshal(m, b)
+}
+func (m *CreateRegionCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateRegionCommand.Marshal(b, m, deterministic)
+}
+func (m *CreateRegionCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateRegionCommand.Merge(m, src)
+}
+func (m *CreateRegionCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateRegionCommand.Size(m)
+}
+func (m *CreateRegionCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateRegionCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateRegionCommand proto.InternalMessageInfo
+
+func (m *CreateRegionCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *CreateRegionCommand) GetTimeToLive() string {
+	if m != nil && m.TimeToLive != nil {
+		return *m.TimeToLive
+	}
+	return ""
 }
-func (m *CreateDatabaseCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateDatabaseCommand.Unmarshal(m, b)
+
+func (m *CreateRegionCommand) GetTimestamp() int64 {
+	if m != nil && m.Timestamp != nil {
+		return *m.Timestamp
+	}
+	return 0
 }
-func (m *CreateDatabaseCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateDatabaseCommand.Marshal(b, m, deterministic)
+
+var E_CreateRegionCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*CreateRegionCommand)(nil),
+	Field:         109,
+	Name:          "meta.CreateRegionCommand.command",
+	Tag:           "bytes,109,opt,name=command",
+	Filename:      "meta.proto",
 }
-func (m *CreateDatabaseCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateDatabaseCommand.Merge(m, src)
+
+type DeleteRegionCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	TimeToLive           *string  `protobuf:"bytes,2,req,name=TimeToLive" json:"TimeToLive,omitempty"`
+	RegionID             *uint64  `protobuf:"varint,3,req,name=RegionID" json:"RegionID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (m *CreateDatabaseCommand) XXX_Size() int {
-	return xxx_messageInfo_CreateDatabaseCommand.Size(m)
+
+func (m *DeleteRegionCommand) Reset()         { *m = DeleteRegionCommand{} }
+func (m *DeleteRegionCommand) String() string { return proto.CompactTextString(m) }
+func (*DeleteRegionCommand) ProtoMessage()    {}
+func (*DeleteRegionCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{28}
 }
-func (m *CreateDatabaseCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateDatabaseCommand.DiscardUnknown(m)
+func (m *DeleteRegionCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteRegionCommand.Unmarshal(m, b)
+}
+func (m *DeleteRegionCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteRegionCommand.Marshal(b, m, deterministic)
+}
+func (m *DeleteRegionCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteRegionCommand.Merge(m, src)
+}
+func (m *DeleteRegionCommand) XXX_Size() int {
+	return xxx_messageInfo_DeleteRegionCommand.Size(m)
+}
+func (m *DeleteRegionCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteRegionCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CreateDatabaseCommand proto.InternalMessageInfo
+var xxx_messageInfo_DeleteRegionCommand proto.InternalMessageInfo
 
-func (m *CreateDatabaseCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
+func (m *DeleteRegionCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
 	}
 	return ""
 }
 
-func (m *CreateDatabaseCommand) GetTimeToLive() *TimeToLiveInfo {
-	if m != nil {
-		return m.TimeToLive
+func (m *DeleteRegionCommand) GetTimeToLive() string {
+	if m != nil && m.TimeToLive != nil {
+		return *m.TimeToLive
 	}
-	return nil
+	return ""
 }
 
-var E_CreateDatabaseCommand_Command = &proto.ExtensionDesc{
+func (m *DeleteRegionCommand) GetRegionID() uint64 {
+	if m != nil && m.RegionID != nil {
+		return *m.RegionID
+	}
+	return 0
+}
+
+var E_DeleteRegionCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*CreateDatabaseCommand)(nil),
-	Field:         103,
-	Name:          "meta.CreateDatabaseCommand.command",
-	Tag:           "bytes,103,opt,name=command",
+	ExtensionType: (*DeleteRegionCommand)(nil),
+	Field:         110,
+	Name:          "meta.DeleteRegionCommand.command",
+	Tag:           "bytes,110,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type DropDatabaseCommand struct {
-	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+type CreateContinuousQueryCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	Query                *string  `protobuf:"bytes,3,req,name=Query" json:"Query,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DropDatabaseCommand) Reset()         { *m = DropDatabaseCommand{} }
-func (m *DropDatabaseCommand) String() string { return proto.CompactTextString(m) }
-func (*DropDatabaseCommand) ProtoMessage()    {}
-func (*DropDatabaseCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{16}
+func (m *CreateContinuousQueryCommand) Reset()         { *m = CreateContinuousQueryCommand{} }
+func (m *CreateContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateContinuousQueryCommand) ProtoMessage()    {}
+func (*CreateContinuousQueryCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{29}
 }
-func (m *DropDatabaseCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DropDatabaseCommand.Unmarshal(m, b)
+func (m *CreateContinuousQueryCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateContinuousQueryCommand.Unmarshal(m, b)
 }
-func (m *DropDatabaseCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DropDatabaseCommand.Marshal(b, m, deterministic)
+func (m *CreateContinuousQueryCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateContinuousQueryCommand.Marshal(b, m, deterministic)
 }
-func (m *DropDatabaseCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DropDatabaseCommand.Merge(m, src)
+func (m *CreateContinuousQueryCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateContinuousQueryCommand.Merge(m, src)
 }
-func (m *DropDatabaseCommand) XXX_Size() int {
-	return xxx_messageInfo_DropDatabaseCommand.Size(m)
+func (m *CreateContinuousQueryCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateContinuousQueryCommand.Size(m)
 }
-func (m *DropDatabaseCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DropDatabaseCommand.DiscardUnknown(m)
+func (m *CreateContinuousQueryCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateContinuousQueryCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DropDatabaseCommand proto.InternalMessageInfo
+var xxx_messageInfo_CreateContinuousQueryCommand proto.InternalMessageInfo
 
-func (m *DropDatabaseCommand) GetName() string {
+func (m *CreateContinuousQueryCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *CreateContinuousQueryCommand) GetName() string {
 	if m != nil && m.Name != nil {
 		return *m.Name
 	}
 	return ""
 }
 
-var E_DropDatabaseCommand_Command = &proto.ExtensionDesc{
+func (m *CreateContinuousQueryCommand) GetQuery() string {
+	if m != nil && m.Query != nil {
+		return *m.Query
+	}
+	return ""
+}
+
+var E_CreateContinuousQueryCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DropDatabaseCommand)(nil),
-	Field:         104,
-	Name:          "meta.DropDatabaseCommand.command",
-	Tag:           "bytes,104,opt,name=command",
+	ExtensionType: (*CreateContinuousQueryCommand)(nil),
+	Field:         111,
+	Name:          "meta.CreateContinuousQueryCommand.command",
+	Tag:           "bytes,111,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type CreateTimeToLiveCommand struct {
-	Database             *string         `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	TimeToLive           *TimeToLiveInfo `protobuf:"bytes,2,req,name=TimeToLive" json:"TimeToLive,omitempty"`
-	Default              *bool           `protobuf:"varint,3,req,name=Default" json:"Default,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+type DropContinuousQueryCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CreateTimeToLiveCommand) Reset()         { *m = CreateTimeToLiveCommand{} }
-func (m *CreateTimeToLiveCommand) String() string { return proto.CompactTextString(m) }
-func (*CreateTimeToLiveCommand) ProtoMessage()    {}
-func (*CreateTimeToLiveCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{17}
+func (m *DropContinuousQueryCommand) Reset()         { *m = DropContinuousQueryCommand{} }
+func (m *DropContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
+func (*DropContinuousQueryCommand) ProtoMessage()    {}
+func (*DropContinuousQueryCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{30}
 }
-func (m *CreateTimeToLiveCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateTimeToLiveCommand.Unmarshal(m, b)
+func (m *DropContinuousQueryCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropContinuousQueryCommand.Unmarshal(m, b)
 }
-func (m *CreateTimeToLiveCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateTimeToLiveCommand.Marshal(b, m, deterministic)
+func (m *DropContinuousQueryCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropContinuousQueryCommand.Marshal(b, m, deterministic)
 }
-func (m *CreateTimeToLiveCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateTimeToLiveCommand.Merge(m, src)
+func (m *DropContinuousQueryCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropContinuousQueryCommand.Merge(m, src)
 }
-func (m *CreateTimeToLiveCommand) XXX_Size() int {
-	return xxx_messageInfo_CreateTimeToLiveCommand.Size(m)
+func (m *DropContinuousQueryCommand) XXX_Size() int {
+	return xxx_messageInfo_DropContinuousQueryCommand.Size(m)
 }
-func (m *CreateTimeToLiveCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateTimeToLiveCommand.DiscardUnknown(m)
+func (m *DropContinuousQueryCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropContinuousQueryCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CreateTimeToLiveCommand proto.InternalMessageInfo
+var xxx_messageInfo_DropContinuousQueryCommand proto.InternalMessageInfo
 
-func (m *CreateTimeToLiveCommand) GetDatabase() string {
+func (m *DropContinuousQueryCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
 		return *m.Database
 	}
 	return ""
 }
 
-func (m *CreateTimeToLiveCommand) GetTimeToLive() *TimeToLiveInfo {
-	if m != nil {
-		return m.TimeToLive
+func (m *DropContinuousQueryCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+var E_DropContinuousQueryCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*DropContinuousQueryCommand)(nil),
+	Field:         112,
+	Name:          "meta.DropContinuousQueryCommand.command",
+	Tag:           "bytes,112,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+type CreateUserCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Hash                 *string  `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
+	Admin                *bool    `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateUserCommand) Reset()         { *m = CreateUserCommand{} }
+func (m *CreateUserCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateUserCommand) ProtoMessage()    {}
+func (*CreateUserCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{31}
+}
+func (m *CreateUserCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateUserCommand.Unmarshal(m, b)
+}
+func (m *CreateUserCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateUserCommand.Marshal(b, m, deterministic)
+}
+func (m *CreateUserCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateUserCommand.Merge(m, src)
+}
+func (m *CreateUserCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateUserCommand.Size(m)
+}
+func (m *CreateUserCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateUserCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateUserCommand proto.InternalMessageInfo
+
+func (m *CreateUserCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *CreateUserCommand) GetHash() string {
+	if m != nil && m.Hash != nil {
+		return *m.Hash
 	}
-	return nil
+	return ""
 }
 
-func (m *CreateTimeToLiveCommand) GetDefault() bool {
-	if m != nil && m.Default != nil {
-		return *m.Default
+func (m *CreateUserCommand) GetAdmin() bool {
+	if m != nil && m.Admin != nil {
+		return *m.Admin
 	}
 	return false
 }
 
-var E_CreateTimeToLiveCommand_Command = &proto.ExtensionDesc{
+var E_CreateUserCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*CreateTimeToLiveCommand)(nil),
-	Field:         105,
-	Name:          "meta.CreateTimeToLiveCommand.command",
-	Tag:           "bytes,105,opt,name=command",
+	ExtensionType: (*CreateUserCommand)(nil),
+	Field:         113,
+	Name:          "meta.CreateUserCommand.command",
+	Tag:           "bytes,113,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type DropTimeToLiveCommand struct {
-	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+type DropUserCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DropTimeToLiveCommand) Reset()         { *m = DropTimeToLiveCommand{} }
-func (m *DropTimeToLiveCommand) String() string { return proto.CompactTextString(m) }
-func (*DropTimeToLiveCommand) ProtoMessage()    {}
-func (*DropTimeToLiveCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{18}
+func (m *DropUserCommand) Reset()         { *m = DropUserCommand{} }
+func (m *DropUserCommand) String() string { return proto.CompactTextString(m) }
+func (*DropUserCommand) ProtoMessage()    {}
+func (*DropUserCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{32}
 }
-func (m *DropTimeToLiveCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DropTimeToLiveCommand.Unmarshal(m, b)
+func (m *DropUserCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropUserCommand.Unmarshal(m, b)
 }
-func (m *DropTimeToLiveCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DropTimeToLiveCommand.Marshal(b, m, deterministic)
+func (m *DropUserCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropUserCommand.Marshal(b, m, deterministic)
 }
-func (m *DropTimeToLiveCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DropTimeToLiveCommand.Merge(m, src)
+func (m *DropUserCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropUserCommand.Merge(m, src)
 }
-func (m *DropTimeToLiveCommand) XXX_Size() int {
-	return xxx_messageInfo_DropTimeToLiveCommand.Size(m)
+func (m *DropUserCommand) XXX_Size() int {
+	return xxx_messageInfo_DropUserCommand.Size(m)
 }
-func (m *DropTimeToLiveCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DropTimeToLiveCommand.DiscardUnknown(m)
+func (m *DropUserCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropUserCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DropTimeToLiveCommand proto.InternalMessageInfo
-
-func (m *DropTimeToLiveCommand) GetDatabase() string {
-	if m != nil && m.Database != nil {
-		return *m.Database
-	}
-	return ""
-}
+var xxx_messageInfo_DropUserCommand proto.InternalMessageInfo
 
-func (m *DropTimeToLiveCommand) GetName() string {
+func (m *DropUserCommand) GetName() string {
 	if m != nil && m.Name != nil {
 		return *m.Name
 	}
 	return ""
 }
 
-var E_DropTimeToLiveCommand_Command = &proto.ExtensionDesc{
+var E_DropUserCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DropTimeToLiveCommand)(nil),
-	Field:         106,
-	Name:          "meta.DropTimeToLiveCommand.command",
-	Tag:           "bytes,106,opt,name=command",
+	ExtensionType: (*DropUserCommand)(nil),
+	Field:         114,
+	Name:          "meta.DropUserCommand.command",
+	Tag:           "bytes,114,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type SetDefaultTimeToLiveCommand struct {
-	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+type UpdateUserCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Hash                 *string  `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SetDefaultTimeToLiveCommand) Reset()         { *m = SetDefaultTimeToLiveCommand{} }
-func (m *SetDefaultTimeToLiveCommand) String() string { return proto.CompactTextString(m) }
-func (*SetDefaultTimeToLiveCommand) ProtoMessage()    {}
-func (*SetDefaultTimeToLiveCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{19}
+func (m *UpdateUserCommand) Reset()         { *m = UpdateUserCommand{} }
+func (m *UpdateUserCommand) String() string { return proto.CompactTextString(m) }
+func (*UpdateUserCommand) ProtoMessage()    {}
+func (*UpdateUserCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{33}
 }
-func (m *SetDefaultTimeToLiveCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetDefaultTimeToLiveCommand.Unmarshal(m, b)
+func (m *UpdateUserCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateUserCommand.Unmarshal(m, b)
 }
-func (m *SetDefaultTimeToLiveCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetDefaultTimeToLiveCommand.Marshal(b, m, deterministic)
+func (m *UpdateUserCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateUserCommand.Marshal(b, m, deterministic)
 }
-func (m *SetDefaultTimeToLiveCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetDefaultTimeToLiveCommand.Merge(m, src)
+func (m *UpdateUserCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateUserCommand.Merge(m, src)
 }
-func (m *SetDefaultTimeToLiveCommand) XXX_Size() int {
-	return xxx_messageInfo_SetDefaultTimeToLiveCommand.Size(m)
+func (m *UpdateUserCommand) XXX_Size() int {
+	return xxx_messageInfo_UpdateUserCommand.Size(m)
 }
-func (m *SetDefaultTimeToLiveCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetDefaultTimeToLiveCommand.DiscardUnknown(m)
+func (m *UpdateUserCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateUserCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetDefaultTimeToLiveCommand proto.InternalMessageInfo
+var xxx_messageInfo_UpdateUserCommand proto.InternalMessageInfo
 
-func (m *SetDefaultTimeToLiveCommand) GetDatabase() string {
-	if m != nil && m.Database != nil {
-		return *m.Database
+func (m *UpdateUserCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
 	}
 	return ""
 }
 
-func (m *SetDefaultTimeToLiveCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
+func (m *UpdateUserCommand) GetHash() string {
+	if m != nil && m.Hash != nil {
+		return *m.Hash
 	}
 	return ""
 }
 
-var E_SetDefaultTimeToLiveCommand_Command = &proto.ExtensionDesc{
+var E_UpdateUserCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetDefaultTimeToLiveCommand)(nil),
-	Field:         107,
-	Name:          "meta.SetDefaultTimeToLiveCommand.command",
-	Tag:           "bytes,107,opt,name=command",
+	ExtensionType: (*UpdateUserCommand)(nil),
+	Field:         115,
+	Name:          "meta.UpdateUserCommand.command",
+	Tag:           "bytes,115,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type UpdateTimeToLiveCommand struct {
-	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
-	NewName              *string  `protobuf:"bytes,3,opt,name=NewName" json:"NewName,omitempty"`
-	Duration             *int64   `protobuf:"varint,4,opt,name=Duration" json:"Duration,omitempty"`
-	ReplicaN             *uint32  `protobuf:"varint,5,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
-	Default              *bool    `protobuf:"varint,6,req,name=Default" json:"Default,omitempty"`
+type SetPrivilegeCommand struct {
+	Username             *string  `protobuf:"bytes,1,req,name=Username" json:"Username,omitempty"`
+	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
+	Privilege            *int32   `protobuf:"varint,3,req,name=Privilege" json:"Privilege,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateTimeToLiveCommand) Reset()         { *m = UpdateTimeToLiveCommand{} }
-func (m *UpdateTimeToLiveCommand) String() string { return proto.CompactTextString(m) }
-func (*UpdateTimeToLiveCommand) ProtoMessage()    {}
-func (*UpdateTimeToLiveCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{20}
+func (m *SetPrivilegeCommand) Reset()         { *m = SetPrivilegeCommand{} }
+func (m *SetPrivilegeCommand) String() string { return proto.CompactTextString(m) }
+func (*SetPrivilegeCommand) ProtoMessage()    {}
+func (*SetPrivilegeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{34}
 }
-func (m *UpdateTimeToLiveCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateTimeToLiveCommand.Unmarshal(m, b)
+func (m *SetPrivilegeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetPrivilegeCommand.Unmarshal(m, b)
 }
-func (m *UpdateTimeToLiveCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateTimeToLiveCommand.Marshal(b, m, deterministic)
+func (m *SetPrivilegeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetPrivilegeCommand.Marshal(b, m, deterministic)
 }
-func (m *UpdateTimeToLiveCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateTimeToLiveCommand.Merge(m, src)
+func (m *SetPrivilegeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetPrivilegeCommand.Merge(m, src)
 }
-func (m *UpdateTimeToLiveCommand) XXX_Size() int {
-	return xxx_messageInfo_UpdateTimeToLiveCommand.Size(m)
+func (m *SetPrivilegeCommand) XXX_Size() int {
+	return xxx_messageInfo_SetPrivilegeCommand.Size(m)
 }
-func (m *UpdateTimeToLiveCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateTimeToLiveCommand.DiscardUnknown(m)
+func (m *SetPrivilegeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetPrivilegeCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UpdateTimeToLiveCommand proto.InternalMessageInfo
+var xxx_messageInfo_SetPrivilegeCommand proto.InternalMessageInfo
 
-func (m *UpdateTimeToLiveCommand) GetDatabase() string {
-	if m != nil && m.Database != nil {
-		return *m.Database
+func (m *SetPrivilegeCommand) GetUsername() string {
+	if m != nil && m.Username != nil {
+		return *m.Username
 	}
 	return ""
 }
 
-func (m *UpdateTimeToLiveCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
+func (m *SetPrivilegeCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
 	}
 	return ""
 }
 
-func (m *UpdateTimeToLiveCommand) GetNewName() string {
-	if m != nil && m.NewName != nil {
-		return *m.NewName
+func (m *SetPrivilegeCommand) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
 	}
-	return ""
+	return 0
 }
 
-func (m *UpdateTimeToLiveCommand) GetDuration() int64 {
-	if m != nil && m.Duration != nil {
-		return *m.Duration
-	}
-	return 0
+var E_SetPrivilegeCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetPrivilegeCommand)(nil),
+	Field:         116,
+	Name:          "meta.SetPrivilegeCommand.command",
+	Tag:           "bytes,116,opt,name=command",
+	Filename:      "meta.proto",
 }
 
-func (m *UpdateTimeToLiveCommand) GetReplicaN() uint32 {
-	if m != nil && m.ReplicaN != nil {
-		return *m.ReplicaN
-	}
-	return 0
+type SetDataCommand struct {
+	Data                 *Data    `protobuf:"bytes,1,req,name=Data" json:"Data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateTimeToLiveCommand) GetDefault() bool {
-	if m != nil && m.Default != nil {
-		return *m.Default
+func (m *SetDataCommand) Reset()         { *m = SetDataCommand{} }
+func (m *SetDataCommand) String() string { return proto.CompactTextString(m) }
+func (*SetDataCommand) ProtoMessage()    {}
+func (*SetDataCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{35}
+}
+func (m *SetDataCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetDataCommand.Unmarshal(m, b)
+}
+func (m *SetDataCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetDataCommand.Marshal(b, m, deterministic)
+}
+func (m *SetDataCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetDataCommand.Merge(m, src)
+}
+func (m *SetDataCommand) XXX_Size() int {
+	return xxx_messageInfo_SetDataCommand.Size(m)
+}
+func (m *SetDataCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetDataCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetDataCommand proto.InternalMessageInfo
+
+func (m *SetDataCommand) GetData() *Data {
+	if m != nil {
+		return m.Data
 	}
-	return false
+	return nil
 }
 
-var E_UpdateTimeToLiveCommand_Command = &proto.ExtensionDesc{
+var E_SetDataCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*UpdateTimeToLiveCommand)(nil),
-	Field:         108,
-	Name:          "meta.UpdateTimeToLiveCommand.command",
-	Tag:           "bytes,108,opt,name=command",
+	ExtensionType: (*SetDataCommand)(nil),
+	Field:         117,
+	Name:          "meta.SetDataCommand.command",
+	Tag:           "bytes,117,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type CreateRegionCommand struct {
-	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	TimeToLive           *string  `protobuf:"bytes,2,req,name=TimeToLive" json:"TimeToLive,omitempty"`
-	Timestamp            *int64   `protobuf:"varint,3,req,name=Timestamp" json:"Timestamp,omitempty"`
+type SetAdminPrivilegeCommand struct {
+	Username             *string  `protobuf:"bytes,1,req,name=Username" json:"Username,omitempty"`
+	Admin                *bool    `protobuf:"varint,2,req,name=Admin" json:"Admin,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CreateRegionCommand) Reset()         { *m = CreateRegionCommand{} }
-func (m *CreateRegionCommand) String() string { return proto.CompactTextString(m) }
-func (*CreateRegionCommand) ProtoMessage()    {}
-func (*CreateRegionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{21}
+func (m *SetAdminPrivilegeCommand) Reset()         { *m = SetAdminPrivilegeCommand{} }
+func (m *SetAdminPrivilegeCommand) String() string { return proto.CompactTextString(m) }
+func (*SetAdminPrivilegeCommand) ProtoMessage()    {}
+func (*SetAdminPrivilegeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{36}
 }
-func (m *CreateRegionCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateRegionCommand.Unmarshal(m, b)
+func (m *SetAdminPrivilegeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetAdminPrivilegeCommand.Unmarshal(m, b)
 }
-func (m *CreateRegionCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateRegionCommand.Marshal(b, m, deterministic)
+func (m *SetAdminPrivilegeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetAdminPrivilegeCommand.Marshal(b, m, deterministic)
 }
-func (m *CreateRegionCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateRegionCommand.Merge(m, src)
+func (m *SetAdminPrivilegeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetAdminPrivilegeCommand.Merge(m, src)
 }
-func (m *CreateRegionCommand) XXX_Size() int {
-	return xxx_messageInfo_CreateRegionCommand.Size(m)
+func (m *SetAdminPrivilegeCommand) XXX_Size() int {
+	return xxx_messageInfo_SetAdminPrivilegeCommand.Size(m)
 }
-func (m *CreateRegionCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateRegionCommand.DiscardUnknown(m)
+func (m *SetAdminPrivilegeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetAdminPrivilegeCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CreateRegionCommand proto.InternalMessageInfo
-
-func (m *CreateRegionCommand) GetDatabase() string {
-	if m != nil && m.Database != nil {
-		return *m.Database
-	}
-	return ""
-}
+var xxx_messageInfo_SetAdminPrivilegeCommand proto.InternalMessageInfo
 
-func (m *CreateRegionCommand) GetTimeToLive() string {
-	if m != nil && m.TimeToLive != nil {
-		return *m.TimeToLive
+func (m *SetAdminPrivilegeCommand) GetUsername() string {
+	if m != nil && m.Username != nil {
+		return *m.Username
 	}
 	return ""
 }
 
-func (m *CreateRegionCommand) GetTimestamp() int64 {
-	if m != nil && m.Timestamp != nil {
-		return *m.Timestamp
+func (m *SetAdminPrivilegeCommand) GetAdmin() bool {
+	if m != nil && m.Admin != nil {
+		return *m.Admin
 	}
-	return 0
+	return false
 }
 
-var E_CreateRegionCommand_Command = &proto.ExtensionDesc{
+var E_SetAdminPrivilegeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*CreateRegionCommand)(nil),
-	Field:         109,
-	Name:          "meta.CreateRegionCommand.command",
-	Tag:           "bytes,109,opt,name=command",
+	ExtensionType: (*SetAdminPrivilegeCommand)(nil),
+	Field:         118,
+	Name:          "meta.SetAdminPrivilegeCommand.command",
+	Tag:           "bytes,118,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type DeleteRegionCommand struct {
-	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	TimeToLive           *string  `protobuf:"bytes,2,req,name=TimeToLive" json:"TimeToLive,omitempty"`
-	RegionID             *uint64  `protobuf:"varint,3,req,name=RegionID" json:"RegionID,omitempty"`
+type UpdateNodeCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Host                 *string  `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DeleteRegionCommand) Reset()         { *m = DeleteRegionCommand{} }
-func (m *DeleteRegionCommand) String() string { return proto.CompactTextString(m) }
-func (*DeleteRegionCommand) ProtoMessage()    {}
-func (*DeleteRegionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{22}
+func (m *UpdateNodeCommand) Reset()         { *m = UpdateNodeCommand{} }
+func (m *UpdateNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*UpdateNodeCommand) ProtoMessage()    {}
+func (*UpdateNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{37}
 }
-func (m *DeleteRegionCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DeleteRegionCommand.Unmarshal(m, b)
+func (m *UpdateNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateNodeCommand.Unmarshal(m, b)
 }
-func (m *DeleteRegionCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DeleteRegionCommand.Marshal(b, m, deterministic)
+func (m *UpdateNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateNodeCommand.Marshal(b, m, deterministic)
 }
-func (m *DeleteRegionCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteRegionCommand.Merge(m, src)
+func (m *UpdateNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateNodeCommand.Merge(m, src)
 }
-func (m *DeleteRegionCommand) XXX_Size() int {
-	return xxx_messageInfo_DeleteRegionCommand.Size(m)
+func (m *UpdateNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_UpdateNodeCommand.Size(m)
 }
-func (m *DeleteRegionCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteRegionCommand.DiscardUnknown(m)
+func (m *UpdateNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateNodeCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DeleteRegionCommand proto.InternalMessageInfo
+var xxx_messageInfo_UpdateNodeCommand proto.InternalMessageInfo
 
-func (m *DeleteRegionCommand) GetDatabase() string {
-	if m != nil && m.Database != nil {
-		return *m.Database
+func (m *UpdateNodeCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
 	}
-	return ""
+	return 0
 }
 
-func (m *DeleteRegionCommand) GetTimeToLive() string {
-	if m != nil && m.TimeToLive != nil {
-		return *m.TimeToLive
+func (m *UpdateNodeCommand) GetHost() string {
+	if m != nil && m.Host != nil {
+		return *m.Host
 	}
 	return ""
 }
 
-func (m *DeleteRegionCommand) GetRegionID() uint64 {
-	if m != nil && m.RegionID != nil {
-		return *m.RegionID
-	}
-	return 0
-}
-
-var E_DeleteRegionCommand_Command = &proto.ExtensionDesc{
+var E_UpdateNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DeleteRegionCommand)(nil),
-	Field:         110,
-	Name:          "meta.DeleteRegionCommand.command",
-	Tag:           "bytes,110,opt,name=command",
+	ExtensionType: (*UpdateNodeCommand)(nil),
+	Field:         119,
+	Name:          "meta.UpdateNodeCommand.command",
+	Tag:           "bytes,119,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type CreateContinuousQueryCommand struct {
-	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
-	Query                *string  `protobuf:"bytes,3,req,name=Query" json:"Query,omitempty"`
+type CreateSubscriptionCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
+	TimeToLive           *string  `protobuf:"bytes,3,req,name=TimeToLive" json:"TimeToLive,omitempty"`
+	Mode                 *string  `protobuf:"bytes,4,req,name=Mode" json:"Mode,omitempty"`
+	Destinations         []string `protobuf:"bytes,5,rep,name=Destinations" json:"Destinations,omitempty"`
+	Durable              *bool    `protobuf:"varint,6,opt,name=Durable" json:"Durable,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CreateContinuousQueryCommand) Reset()         { *m = CreateContinuousQueryCommand{} }
-func (m *CreateContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
-func (*CreateContinuousQueryCommand) ProtoMessage()    {}
-func (*CreateContinuousQueryCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{23}
+func (m *CreateSubscriptionCommand) Reset()         { *m = CreateSubscriptionCommand{} }
+func (m *CreateSubscriptionCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateSubscriptionCommand) ProtoMessage()    {}
+func (*CreateSubscriptionCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{38}
 }
-func (m *CreateContinuousQueryCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateContinuousQueryCommand.Unmarshal(m, b)
+func (m *CreateSubscriptionCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSubscriptionCommand.Unmarshal(m, b)
 }
-func (m *CreateContinuousQueryCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateContinuousQueryCommand.Marshal(b, m, deterministic)
+func (m *CreateSubscriptionCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSubscriptionCommand.Marshal(b, m, deterministic)
 }
-func (m *CreateContinuousQueryCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateContinuousQueryCommand.Merge(m, src)
+func (m *CreateSubscriptionCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSubscriptionCommand.Merge(m, src)
 }
-func (m *CreateContinuousQueryCommand) XXX_Size() int {
-	return xxx_messageInfo_CreateContinuousQueryCommand.Size(m)
+func (m *CreateSubscriptionCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateSubscriptionCommand.Size(m)
 }
-func (m *CreateContinuousQueryCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateContinuousQueryCommand.DiscardUnknown(m)
+func (m *CreateSubscriptionCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSubscriptionCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CreateContinuousQueryCommand proto.InternalMessageInfo
+var xxx_messageInfo_CreateSubscriptionCommand proto.InternalMessageInfo
 
-func (m *CreateContinuousQueryCommand) GetDatabase() string {
+func (m *CreateSubscriptionCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *CreateSubscriptionCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
 		return *m.Database
 	}
 	return ""
 }
 
-func (m *CreateContinuousQueryCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
+func (m *CreateSubscriptionCommand) GetTimeToLive() string {
+	if m != nil && m.TimeToLive != nil {
+		return *m.TimeToLive
 	}
 	return ""
 }
 
-func (m *CreateContinuousQueryCommand) GetQuery() string {
-	if m != nil && m.Query != nil {
-		return *m.Query
+func (m *CreateSubscriptionCommand) GetMode() string {
+	if m != nil && m.Mode != nil {
+		return *m.Mode
 	}
 	return ""
 }
 
-var E_CreateContinuousQueryCommand_Command = &proto.ExtensionDesc{
+func (m *CreateSubscriptionCommand) GetDestinations() []string {
+	if m != nil {
+		return m.Destinations
+	}
+	return nil
+}
+
+func (m *CreateSubscriptionCommand) GetDurable() bool {
+	if m != nil && m.Durable != nil {
+		return *m.Durable
+	}
+	return false
+}
+
+var E_CreateSubscriptionCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*CreateContinuousQueryCommand)(nil),
-	Field:         111,
-	Name:          "meta.CreateContinuousQueryCommand.command",
-	Tag:           "bytes,111,opt,name=command",
+	ExtensionType: (*CreateSubscriptionCommand)(nil),
+	Field:         121,
+	Name:          "meta.CreateSubscriptionCommand.command",
+	Tag:           "bytes,121,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type DropContinuousQueryCommand struct {
-	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
-	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+type DropSubscriptionCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
+	TimeToLive           *string  `protobuf:"bytes,3,req,name=TimeToLive" json:"TimeToLive,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DropContinuousQueryCommand) Reset()         { *m = DropContinuousQueryCommand{} }
-func (m *DropContinuousQueryCommand) String() string { return proto.CompactTextString(m) }
-func (*DropContinuousQueryCommand) ProtoMessage()    {}
-func (*DropContinuousQueryCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{24}
+func (m *DropSubscriptionCommand) Reset()         { *m = DropSubscriptionCommand{} }
+func (m *DropSubscriptionCommand) String() string { return proto.CompactTextString(m) }
+func (*DropSubscriptionCommand) ProtoMessage()    {}
+func (*DropSubscriptionCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{39}
 }
-func (m *DropContinuousQueryCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DropContinuousQueryCommand.Unmarshal(m, b)
+func (m *DropSubscriptionCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropSubscriptionCommand.Unmarshal(m, b)
 }
-func (m *DropContinuousQueryCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DropContinuousQueryCommand.Marshal(b, m, deterministic)
+func (m *DropSubscriptionCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropSubscriptionCommand.Marshal(b, m, deterministic)
 }
-func (m *DropContinuousQueryCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DropContinuousQueryCommand.Merge(m, src)
+func (m *DropSubscriptionCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropSubscriptionCommand.Merge(m, src)
 }
-func (m *DropContinuousQueryCommand) XXX_Size() int {
-	return xxx_messageInfo_DropContinuousQueryCommand.Size(m)
+func (m *DropSubscriptionCommand) XXX_Size() int {
+	return xxx_messageInfo_DropSubscriptionCommand.Size(m)
 }
-func (m *DropContinuousQueryCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DropContinuousQueryCommand.DiscardUnknown(m)
+func (m *DropSubscriptionCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropSubscriptionCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DropContinuousQueryCommand proto.InternalMessageInfo
+var xxx_messageInfo_DropSubscriptionCommand proto.InternalMessageInfo
 
-func (m *DropContinuousQueryCommand) GetDatabase() string {
+func (m *DropSubscriptionCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *DropSubscriptionCommand) GetDatabase() string {
 	if m != nil && m.Database != nil {
 		return *m.Database
 	}
 	return ""
 }
 
-func (m *DropContinuousQueryCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
+func (m *DropSubscriptionCommand) GetTimeToLive() string {
+	if m != nil && m.TimeToLive != nil {
+		return *m.TimeToLive
 	}
 	return ""
 }
 
-var E_DropContinuousQueryCommand_Command = &proto.ExtensionDesc{
+var E_DropSubscriptionCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DropContinuousQueryCommand)(nil),
-	Field:         112,
-	Name:          "meta.DropContinuousQueryCommand.command",
-	Tag:           "bytes,112,opt,name=command",
+	ExtensionType: (*DropSubscriptionCommand)(nil),
+	Field:         122,
+	Name:          "meta.DropSubscriptionCommand.command",
+	Tag:           "bytes,122,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type CreateUserCommand struct {
-	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Hash                 *string  `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
-	Admin                *bool    `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
+type RemovePeerCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,opt,name=ID" json:"ID,omitempty"`
+	Addr                 *string  `protobuf:"bytes,2,req,name=Addr" json:"Addr,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CreateUserCommand) Reset()         { *m = CreateUserCommand{} }
-func (m *CreateUserCommand) String() string { return proto.CompactTextString(m) }
-func (*CreateUserCommand) ProtoMessage()    {}
-func (*CreateUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{25}
+func (m *RemovePeerCommand) Reset()         { *m = RemovePeerCommand{} }
+func (m *RemovePeerCommand) String() string { return proto.CompactTextString(m) }
+func (*RemovePeerCommand) ProtoMessage()    {}
+func (*RemovePeerCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{40}
 }
-func (m *CreateUserCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateUserCommand.Unmarshal(m, b)
+func (m *RemovePeerCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemovePeerCommand.Unmarshal(m, b)
 }
-func (m *CreateUserCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateUserCommand.Marshal(b, m, deterministic)
+func (m *RemovePeerCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemovePeerCommand.Marshal(b, m, deterministic)
 }
-func (m *CreateUserCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateUserCommand.Merge(m, src)
+func (m *RemovePeerCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemovePeerCommand.Merge(m, src)
 }
-func (m *CreateUserCommand) XXX_Size() int {
-	return xxx_messageInfo_CreateUserCommand.Size(m)
+func (m *RemovePeerCommand) XXX_Size() int {
+	return xxx_messageInfo_RemovePeerCommand.Size(m)
 }
-func (m *CreateUserCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateUserCommand.DiscardUnknown(m)
+func (m *RemovePeerCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemovePeerCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CreateUserCommand proto.InternalMessageInfo
+var xxx_messageInfo_RemovePeerCommand proto.InternalMessageInfo
 
-func (m *CreateUserCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
+func (m *RemovePeerCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
 	}
-	return ""
+	return 0
 }
 
-func (m *CreateUserCommand) GetHash() string {
-	if m != nil && m.Hash != nil {
-		return *m.Hash
+func (m *RemovePeerCommand) GetAddr() string {
+	if m != nil && m.Addr != nil {
+		return *m.Addr
 	}
 	return ""
 }
 
-func (m *CreateUserCommand) GetAdmin() bool {
-	if m != nil && m.Admin != nil {
-		return *m.Admin
-	}
-	return false
-}
-
-var E_CreateUserCommand_Command = &proto.ExtensionDesc{
+var E_RemovePeerCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*CreateUserCommand)(nil),
-	Field:         113,
-	Name:          "meta.CreateUserCommand.command",
-	Tag:           "bytes,113,opt,name=command",
+	ExtensionType: (*RemovePeerCommand)(nil),
+	Field:         123,
+	Name:          "meta.RemovePeerCommand.command",
+	Tag:           "bytes,123,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type DropUserCommand struct {
-	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+type CreateMetaNodeCommand struct {
+	HTTPAddr             *string  `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
+	TCPAddr              *string  `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
+	Rand                 *uint64  `protobuf:"varint,3,req,name=Rand" json:"Rand,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DropUserCommand) Reset()         { *m = DropUserCommand{} }
-func (m *DropUserCommand) String() string { return proto.CompactTextString(m) }
-func (*DropUserCommand) ProtoMessage()    {}
-func (*DropUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{26}
+func (m *CreateMetaNodeCommand) Reset()         { *m = CreateMetaNodeCommand{} }
+func (m *CreateMetaNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateMetaNodeCommand) ProtoMessage()    {}
+func (*CreateMetaNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{41}
 }
-func (m *DropUserCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DropUserCommand.Unmarshal(m, b)
+func (m *CreateMetaNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateMetaNodeCommand.Unmarshal(m, b)
 }
-func (m *DropUserCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DropUserCommand.Marshal(b, m, deterministic)
+func (m *CreateMetaNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateMetaNodeCommand.Marshal(b, m, deterministic)
 }
-func (m *DropUserCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DropUserCommand.Merge(m, src)
+func (m *CreateMetaNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateMetaNodeCommand.Merge(m, src)
 }
-func (m *DropUserCommand) XXX_Size() int {
-	return xxx_messageInfo_DropUserCommand.Size(m)
+func (m *CreateMetaNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateMetaNodeCommand.Size(m)
 }
-func (m *DropUserCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DropUserCommand.DiscardUnknown(m)
+func (m *CreateMetaNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateMetaNodeCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DropUserCommand proto.InternalMessageInfo
+var xxx_messageInfo_CreateMetaNodeCommand proto.InternalMessageInfo
 
-func (m *DropUserCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
+func (m *CreateMetaNodeCommand) GetHTTPAddr() string {
+	if m != nil && m.HTTPAddr != nil {
+		return *m.HTTPAddr
 	}
 	return ""
 }
 
-var E_DropUserCommand_Command = &proto.ExtensionDesc{
+func (m *CreateMetaNodeCommand) GetTCPAddr() string {
+	if m != nil && m.TCPAddr != nil {
+		return *m.TCPAddr
+	}
+	return ""
+}
+
+func (m *CreateMetaNodeCommand) GetRand() uint64 {
+	if m != nil && m.Rand != nil {
+		return *m.Rand
+	}
+	return 0
+}
+
+var E_CreateMetaNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DropUserCommand)(nil),
-	Field:         114,
-	Name:          "meta.DropUserCommand.command",
-	Tag:           "bytes,114,opt,name=command",
+	ExtensionType: (*CreateMetaNodeCommand)(nil),
+	Field:         124,
+	Name:          "meta.CreateMetaNodeCommand.command",
+	Tag:           "bytes,124,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type UpdateUserCommand struct {
-	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Hash                 *string  `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
+type CreateDataNodeCommand struct {
+	HTTPAddr             *string  `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
+	TCPAddr              *string  `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateUserCommand) Reset()         { *m = UpdateUserCommand{} }
-func (m *UpdateUserCommand) String() string { return proto.CompactTextString(m) }
-func (*UpdateUserCommand) ProtoMessage()    {}
-func (*UpdateUserCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{27}
+func (m *CreateDataNodeCommand) Reset()         { *m = CreateDataNodeCommand{} }
+func (m *CreateDataNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateDataNodeCommand) ProtoMessage()    {}
+func (*CreateDataNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{42}
 }
-func (m *UpdateUserCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateUserCommand.Unmarshal(m, b)
+func (m *CreateDataNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateDataNodeCommand.Unmarshal(m, b)
 }
-func (m *UpdateUserCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateUserCommand.Marshal(b, m, deterministic)
+func (m *CreateDataNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateDataNodeCommand.Marshal(b, m, deterministic)
 }
-func (m *UpdateUserCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateUserCommand.Merge(m, src)
+func (m *CreateDataNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateDataNodeCommand.Merge(m, src)
 }
-func (m *UpdateUserCommand) XXX_Size() int {
-	return xxx_messageInfo_UpdateUserCommand.Size(m)
+func (m *CreateDataNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateDataNodeCommand.Size(m)
 }
-func (m *UpdateUserCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateUserCommand.DiscardUnknown(m)
+func (m *CreateDataNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateDataNodeCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UpdateUserCommand proto.InternalMessageInfo
+var xxx_messageInfo_CreateDataNodeCommand proto.InternalMessageInfo
 
-func (m *UpdateUserCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
+func (m *CreateDataNodeCommand) GetHTTPAddr() string {
+	if m != nil && m.HTTPAddr != nil {
+		return *m.HTTPAddr
 	}
 	return ""
 }
 
-func (m *UpdateUserCommand) GetHash() string {
-	if m != nil && m.Hash != nil {
-		return *m.Hash
+func (m *CreateDataNodeCommand) GetTCPAddr() string {
+	if m != nil && m.TCPAddr != nil {
+		return *m.TCPAddr
 	}
 	return ""
 }
 
-var E_UpdateUserCommand_Command = &proto.ExtensionDesc{
+var E_CreateDataNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*UpdateUserCommand)(nil),
-	Field:         115,
-	Name:          "meta.UpdateUserCommand.command",
-	Tag:           "bytes,115,opt,name=command",
+	ExtensionType: (*CreateDataNodeCommand)(nil),
+	Field:         125,
+	Name:          "meta.CreateDataNodeCommand.command",
+	Tag:           "bytes,125,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type SetPrivilegeCommand struct {
-	Username             *string  `protobuf:"bytes,1,req,name=Username" json:"Username,omitempty"`
-	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
-	Privilege            *int32   `protobuf:"varint,3,req,name=Privilege" json:"Privilege,omitempty"`
+type UpdateDataNodeCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Host                 *string  `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
+	TCPHost              *string  `protobuf:"bytes,3,req,name=TCPHost" json:"TCPHost,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
-
-func (m *SetPrivilegeCommand) Reset()         { *m = SetPrivilegeCommand{} }
-func (m *SetPrivilegeCommand) String() string { return proto.CompactTextString(m) }
-func (*SetPrivilegeCommand) ProtoMessage()    {}
-func (*SetPrivilegeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{28}
+
+func (m *UpdateDataNodeCommand) Reset()         { *m = UpdateDataNodeCommand{} }
+func (m *UpdateDataNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*UpdateDataNodeCommand) ProtoMessage()    {}
+func (*UpdateDataNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{43}
 }
-func (m *SetPrivilegeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetPrivilegeCommand.Unmarshal(m, b)
+func (m *UpdateDataNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateDataNodeCommand.Unmarshal(m, b)
 }
-func (m *SetPrivilegeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetPrivilegeCommand.Marshal(b, m, deterministic)
+func (m *UpdateDataNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateDataNodeCommand.Marshal(b, m, deterministic)
 }
-func (m *SetPrivilegeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetPrivilegeCommand.Merge(m, src)
+func (m *UpdateDataNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateDataNodeCommand.Merge(m, src)
 }
-func (m *SetPrivilegeCommand) XXX_Size() int {
-	return xxx_messageInfo_SetPrivilegeCommand.Size(m)
+func (m *UpdateDataNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_UpdateDataNodeCommand.Size(m)
 }
-func (m *SetPrivilegeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetPrivilegeCommand.DiscardUnknown(m)
+func (m *UpdateDataNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateDataNodeCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetPrivilegeCommand proto.InternalMessageInfo
+var xxx_messageInfo_UpdateDataNodeCommand proto.InternalMessageInfo
 
-func (m *SetPrivilegeCommand) GetUsername() string {
-	if m != nil && m.Username != nil {
-		return *m.Username
+func (m *UpdateDataNodeCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
 	}
-	return ""
+	return 0
 }
 
-func (m *SetPrivilegeCommand) GetDatabase() string {
-	if m != nil && m.Database != nil {
-		return *m.Database
+func (m *UpdateDataNodeCommand) GetHost() string {
+	if m != nil && m.Host != nil {
+		return *m.Host
 	}
 	return ""
 }
 
-func (m *SetPrivilegeCommand) GetPrivilege() int32 {
-	if m != nil && m.Privilege != nil {
-		return *m.Privilege
+func (m *UpdateDataNodeCommand) GetTCPHost() string {
+	if m != nil && m.TCPHost != nil {
+		return *m.TCPHost
 	}
-	return 0
+	return ""
 }
 
-var E_SetPrivilegeCommand_Command = &proto.ExtensionDesc{
+var E_UpdateDataNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetPrivilegeCommand)(nil),
-	Field:         116,
-	Name:          "meta.SetPrivilegeCommand.command",
-	Tag:           "bytes,116,opt,name=command",
+	ExtensionType: (*UpdateDataNodeCommand)(nil),
+	Field:         126,
+	Name:          "meta.UpdateDataNodeCommand.command",
+	Tag:           "bytes,126,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type SetDataCommand struct {
-	Data                 *Data    `protobuf:"bytes,1,req,name=Data" json:"Data,omitempty"`
+type DeleteMetaNodeCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SetDataCommand) Reset()         { *m = SetDataCommand{} }
-func (m *SetDataCommand) String() string { return proto.CompactTextString(m) }
-func (*SetDataCommand) ProtoMessage()    {}
-func (*SetDataCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{29}
+func (m *DeleteMetaNodeCommand) Reset()         { *m = DeleteMetaNodeCommand{} }
+func (m *DeleteMetaNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*DeleteMetaNodeCommand) ProtoMessage()    {}
+func (*DeleteMetaNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{44}
 }
-func (m *SetDataCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetDataCommand.Unmarshal(m, b)
+func (m *DeleteMetaNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteMetaNodeCommand.Unmarshal(m, b)
 }
-func (m *SetDataCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetDataCommand.Marshal(b, m, deterministic)
+func (m *DeleteMetaNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteMetaNodeCommand.Marshal(b, m, deterministic)
 }
-func (m *SetDataCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetDataCommand.Merge(m, src)
+func (m *DeleteMetaNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteMetaNodeCommand.Merge(m, src)
 }
-func (m *SetDataCommand) XXX_Size() int {
-	return xxx_messageInfo_SetDataCommand.Size(m)
+func (m *DeleteMetaNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_DeleteMetaNodeCommand.Size(m)
 }
-func (m *SetDataCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetDataCommand.DiscardUnknown(m)
+func (m *DeleteMetaNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteMetaNodeCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetDataCommand proto.InternalMessageInfo
+var xxx_messageInfo_DeleteMetaNodeCommand proto.InternalMessageInfo
 
-func (m *SetDataCommand) GetData() *Data {
-	if m != nil {
-		return m.Data
+func (m *DeleteMetaNodeCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
 	}
-	return nil
+	return 0
 }
 
-var E_SetDataCommand_Command = &proto.ExtensionDesc{
+var E_DeleteMetaNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetDataCommand)(nil),
-	Field:         117,
-	Name:          "meta.SetDataCommand.command",
-	Tag:           "bytes,117,opt,name=command",
+	ExtensionType: (*DeleteMetaNodeCommand)(nil),
+	Field:         127,
+	Name:          "meta.DeleteMetaNodeCommand.command",
+	Tag:           "bytes,127,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type SetAdminPrivilegeCommand struct {
-	Username             *string  `protobuf:"bytes,1,req,name=Username" json:"Username,omitempty"`
-	Admin                *bool    `protobuf:"varint,2,req,name=Admin" json:"Admin,omitempty"`
+type DeleteDataNodeCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SetAdminPrivilegeCommand) Reset()         { *m = SetAdminPrivilegeCommand{} }
-func (m *SetAdminPrivilegeCommand) String() string { return proto.CompactTextString(m) }
-func (*SetAdminPrivilegeCommand) ProtoMessage()    {}
-func (*SetAdminPrivilegeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{30}
+func (m *DeleteDataNodeCommand) Reset()         { *m = DeleteDataNodeCommand{} }
+func (m *DeleteDataNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*DeleteDataNodeCommand) ProtoMessage()    {}
+func (*DeleteDataNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{45}
 }
-func (m *SetAdminPrivilegeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetAdminPrivilegeCommand.Unmarshal(m, b)
+func (m *DeleteDataNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteDataNodeCommand.Unmarshal(m, b)
 }
-func (m *SetAdminPrivilegeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetAdminPrivilegeCommand.Marshal(b, m, deterministic)
+func (m *DeleteDataNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteDataNodeCommand.Marshal(b, m, deterministic)
 }
-func (m *SetAdminPrivilegeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetAdminPrivilegeCommand.Merge(m, src)
+func (m *DeleteDataNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteDataNodeCommand.Merge(m, src)
 }
-func (m *SetAdminPrivilegeCommand) XXX_Size() int {
-	return xxx_messageInfo_SetAdminPrivilegeCommand.Size(m)
+func (m *DeleteDataNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_DeleteDataNodeCommand.Size(m)
 }
-func (m *SetAdminPrivilegeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetAdminPrivilegeCommand.DiscardUnknown(m)
+func (m *DeleteDataNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteDataNodeCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetAdminPrivilegeCommand proto.InternalMessageInfo
-
-func (m *SetAdminPrivilegeCommand) GetUsername() string {
-	if m != nil && m.Username != nil {
-		return *m.Username
-	}
-	return ""
-}
+var xxx_messageInfo_DeleteDataNodeCommand proto.InternalMessageInfo
 
-func (m *SetAdminPrivilegeCommand) GetAdmin() bool {
-	if m != nil && m.Admin != nil {
-		return *m.Admin
+func (m *DeleteDataNodeCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
 	}
-	return false
+	return 0
 }
 
-var E_SetAdminPrivilegeCommand_Command = &proto.ExtensionDesc{
+var E_DeleteDataNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetAdminPrivilegeCommand)(nil),
-	Field:         118,
-	Name:          "meta.SetAdminPrivilegeCommand.command",
-	Tag:           "bytes,118,opt,name=command",
+	ExtensionType: (*DeleteDataNodeCommand)(nil),
+	Field:         128,
+	Name:          "meta.DeleteDataNodeCommand.command",
+	Tag:           "bytes,128,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type UpdateNodeCommand struct {
-	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
-	Host                 *string  `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
+type Response struct {
+	OK                   *bool    `protobuf:"varint,1,req,name=OK" json:"OK,omitempty"`
+	Error                *string  `protobuf:"bytes,2,opt,name=Error" json:"Error,omitempty"`
+	Index                *uint64  `protobuf:"varint,3,opt,name=Index" json:"Index,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateNodeCommand) Reset()         { *m = UpdateNodeCommand{} }
-func (m *UpdateNodeCommand) String() string { return proto.CompactTextString(m) }
-func (*UpdateNodeCommand) ProtoMessage()    {}
-func (*UpdateNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{31}
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+func (*Response) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{46}
 }
-func (m *UpdateNodeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateNodeCommand.Unmarshal(m, b)
+func (m *Response) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Response.Unmarshal(m, b)
 }
-func (m *UpdateNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateNodeCommand.Marshal(b, m, deterministic)
+func (m *Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Response.Marshal(b, m, deterministic)
 }
-func (m *UpdateNodeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateNodeCommand.Merge(m, src)
+func (m *Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Response.Merge(m, src)
 }
-func (m *UpdateNodeCommand) XXX_Size() int {
-	return xxx_messageInfo_UpdateNodeCommand.Size(m)
+func (m *Response) XXX_Size() int {
+	return xxx_messageInfo_Response.Size(m)
 }
-func (m *UpdateNodeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateNodeCommand.DiscardUnknown(m)
+func (m *Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_Response.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_UpdateNodeCommand proto.InternalMessageInfo
+var xxx_messageInfo_Response proto.InternalMessageInfo
 
-func (m *UpdateNodeCommand) GetID() uint64 {
-	if m != nil && m.ID != nil {
-		return *m.ID
+func (m *Response) GetOK() bool {
+	if m != nil && m.OK != nil {
+		return *m.OK
 	}
-	return 0
+	return false
 }
 
-func (m *UpdateNodeCommand) GetHost() string {
-	if m != nil && m.Host != nil {
-		return *m.Host
+func (m *Response) GetError() string {
+	if m != nil && m.Error != nil {
+		return *m.Error
 	}
 	return ""
 }
 
-var E_UpdateNodeCommand_Command = &proto.ExtensionDesc{
-	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*UpdateNodeCommand)(nil),
-	Field:         119,
-	Name:          "meta.UpdateNodeCommand.command",
-	Tag:           "bytes,119,opt,name=command",
-	Filename:      "meta.proto",
+func (m *Response) GetIndex() uint64 {
+	if m != nil && m.Index != nil {
+		return *m.Index
+	}
+	return 0
 }
 
-type CreateSubscriptionCommand struct {
-	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
-	TimeToLive           *string  `protobuf:"bytes,3,req,name=TimeToLive" json:"TimeToLive,omitempty"`
-	Mode                 *string  `protobuf:"bytes,4,req,name=Mode" json:"Mode,omitempty"`
-	Destinations         []string `protobuf:"bytes,5,rep,name=Destinations" json:"Destinations,omitempty"`
+// SetMetaNodeCommand is for the initial metanode in a cluster or
+// if the single host restarts and its hostname changes, this will update it
+type SetMetaNodeCommand struct {
+	HTTPAddr             *string  `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
+	TCPAddr              *string  `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
+	Rand                 *uint64  `protobuf:"varint,3,req,name=Rand" json:"Rand,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
-
-func (m *CreateSubscriptionCommand) Reset()         { *m = CreateSubscriptionCommand{} }
-func (m *CreateSubscriptionCommand) String() string { return proto.CompactTextString(m) }
-func (*CreateSubscriptionCommand) ProtoMessage()    {}
-func (*CreateSubscriptionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{32}
-}
-func (m *CreateSubscriptionCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateSubscriptionCommand.Unmarshal(m, b)
+
+func (m *SetMetaNodeCommand) Reset()         { *m = SetMetaNodeCommand{} }
+func (m *SetMetaNodeCommand) String() string { return proto.CompactTextString(m) }
+func (*SetMetaNodeCommand) ProtoMessage()    {}
+func (*SetMetaNodeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{47}
 }
-func (m *CreateSubscriptionCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateSubscriptionCommand.Marshal(b, m, deterministic)
+func (m *SetMetaNodeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMetaNodeCommand.Unmarshal(m, b)
 }
-func (m *CreateSubscriptionCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateSubscriptionCommand.Merge(m, src)
+func (m *SetMetaNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMetaNodeCommand.Marshal(b, m, deterministic)
 }
-func (m *CreateSubscriptionCommand) XXX_Size() int {
-	return xxx_messageInfo_CreateSubscriptionCommand.Size(m)
+func (m *SetMetaNodeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMetaNodeCommand.Merge(m, src)
 }
-func (m *CreateSubscriptionCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateSubscriptionCommand.DiscardUnknown(m)
+func (m *SetMetaNodeCommand) XXX_Size() int {
+	return xxx_messageInfo_SetMetaNodeCommand.Size(m)
 }
-
-var xxx_messageInfo_CreateSubscriptionCommand proto.InternalMessageInfo
-
-func (m *CreateSubscriptionCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
-	}
-	return ""
+func (m *SetMetaNodeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMetaNodeCommand.DiscardUnknown(m)
 }
 
-func (m *CreateSubscriptionCommand) GetDatabase() string {
-	if m != nil && m.Database != nil {
-		return *m.Database
-	}
-	return ""
-}
+var xxx_messageInfo_SetMetaNodeCommand proto.InternalMessageInfo
 
-func (m *CreateSubscriptionCommand) GetTimeToLive() string {
-	if m != nil && m.TimeToLive != nil {
-		return *m.TimeToLive
+func (m *SetMetaNodeCommand) GetHTTPAddr() string {
+	if m != nil && m.HTTPAddr != nil {
+		return *m.HTTPAddr
 	}
 	return ""
 }
 
-func (m *CreateSubscriptionCommand) GetMode() string {
-	if m != nil && m.Mode != nil {
-		return *m.Mode
+func (m *SetMetaNodeCommand) GetTCPAddr() string {
+	if m != nil && m.TCPAddr != nil {
+		return *m.TCPAddr
 	}
 	return ""
 }
 
-func (m *CreateSubscriptionCommand) GetDestinations() []string {
-	if m != nil {
-		return m.Destinations
+func (m *SetMetaNodeCommand) GetRand() uint64 {
+	if m != nil && m.Rand != nil {
+		return *m.Rand
 	}
-	return nil
+	return 0
 }
 
-var E_CreateSubscriptionCommand_Command = &proto.ExtensionDesc{
+var E_SetMetaNodeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*CreateSubscriptionCommand)(nil),
-	Field:         121,
-	Name:          "meta.CreateSubscriptionCommand.command",
-	Tag:           "bytes,121,opt,name=command",
+	ExtensionType: (*SetMetaNodeCommand)(nil),
+	Field:         129,
+	Name:          "meta.SetMetaNodeCommand.command",
+	Tag:           "bytes,129,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type DropSubscriptionCommand struct {
-	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
-	TimeToLive           *string  `protobuf:"bytes,3,req,name=TimeToLive" json:"TimeToLive,omitempty"`
+type DropShardCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DropSubscriptionCommand) Reset()         { *m = DropSubscriptionCommand{} }
-func (m *DropSubscriptionCommand) String() string { return proto.CompactTextString(m) }
-func (*DropSubscriptionCommand) ProtoMessage()    {}
-func (*DropSubscriptionCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{33}
-}
-func (m *DropSubscriptionCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DropSubscriptionCommand.Unmarshal(m, b)
+func (m *DropShardCommand) Reset()         { *m = DropShardCommand{} }
+func (m *DropShardCommand) String() string { return proto.CompactTextString(m) }
+func (*DropShardCommand) ProtoMessage()    {}
+func (*DropShardCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{48}
 }
-func (m *DropSubscriptionCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DropSubscriptionCommand.Marshal(b, m, deterministic)
+func (m *DropShardCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropShardCommand.Unmarshal(m, b)
 }
-func (m *DropSubscriptionCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DropSubscriptionCommand.Merge(m, src)
+func (m *DropShardCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropShardCommand.Marshal(b, m, deterministic)
 }
-func (m *DropSubscriptionCommand) XXX_Size() int {
-	return xxx_messageInfo_DropSubscriptionCommand.Size(m)
+func (m *DropShardCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropShardCommand.Merge(m, src)
 }
-func (m *DropSubscriptionCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DropSubscriptionCommand.DiscardUnknown(m)
+func (m *DropShardCommand) XXX_Size() int {
+	return xxx_messageInfo_DropShardCommand.Size(m)
 }
-
-var xxx_messageInfo_DropSubscriptionCommand proto.InternalMessageInfo
-
-func (m *DropSubscriptionCommand) GetName() string {
-	if m != nil && m.Name != nil {
-		return *m.Name
-	}
-	return ""
+func (m *DropShardCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropShardCommand.DiscardUnknown(m)
 }
 
-func (m *DropSubscriptionCommand) GetDatabase() string {
-	if m != nil && m.Database != nil {
-		return *m.Database
-	}
-	return ""
-}
+var xxx_messageInfo_DropShardCommand proto.InternalMessageInfo
 
-func (m *DropSubscriptionCommand) GetTimeToLive() string {
-	if m != nil && m.TimeToLive != nil {
-		return *m.TimeToLive
+func (m *DropShardCommand) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
 	}
-	return ""
+	return 0
 }
 
-var E_DropSubscriptionCommand_Command = &proto.ExtensionDesc{
+var E_DropShardCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DropSubscriptionCommand)(nil),
-	Field:         122,
-	Name:          "meta.DropSubscriptionCommand.command",
-	Tag:           "bytes,122,opt,name=command",
+	ExtensionType: (*DropShardCommand)(nil),
+	Field:         130,
+	Name:          "meta.DropShardCommand.command",
+	Tag:           "bytes,130,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type RemovePeerCommand struct {
-	ID                   *uint64  `protobuf:"varint,1,opt,name=ID" json:"ID,omitempty"`
-	Addr                 *string  `protobuf:"bytes,2,req,name=Addr" json:"Addr,omitempty"`
+// UpdateShardOwnersCommand adds and removes owners of a shard once its
+// data has been copied to or removed from the data nodes.
+type UpdateShardOwnersCommand struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	AddOwners            []uint64 `protobuf:"varint,2,rep,name=AddOwners" json:"AddOwners,omitempty"`
+	DelOwners            []uint64 `protobuf:"varint,3,rep,name=DelOwners" json:"DelOwners,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *RemovePeerCommand) Reset()         { *m = RemovePeerCommand{} }
-func (m *RemovePeerCommand) String() string { return proto.CompactTextString(m) }
-func (*RemovePeerCommand) ProtoMessage()    {}
-func (*RemovePeerCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{34}
+func (m *UpdateShardOwnersCommand) Reset()         { *m = UpdateShardOwnersCommand{} }
+func (m *UpdateShardOwnersCommand) String() string { return proto.CompactTextString(m) }
+func (*UpdateShardOwnersCommand) ProtoMessage()    {}
+func (*UpdateShardOwnersCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{49}
 }
-func (m *RemovePeerCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_RemovePeerCommand.Unmarshal(m, b)
+func (m *UpdateShardOwnersCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateShardOwnersCommand.Unmarshal(m, b)
 }
-func (m *RemovePeerCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_RemovePeerCommand.Marshal(b, m, deterministic)
+func (m *UpdateShardOwnersCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateShardOwnersCommand.Marshal(b, m, deterministic)
 }
-func (m *RemovePeerCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_RemovePeerCommand.Merge(m, src)
+func (m *UpdateShardOwnersCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateShardOwnersCommand.Merge(m, src)
 }
-func (m *RemovePeerCommand) XXX_Size() int {
-	return xxx_messageInfo_RemovePeerCommand.Size(m)
+func (m *UpdateShardOwnersCommand) XXX_Size() int {
+	return xxx_messageInfo_UpdateShardOwnersCommand.Size(m)
 }
-func (m *RemovePeerCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_RemovePeerCommand.DiscardUnknown(m)
+func (m *UpdateShardOwnersCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateShardOwnersCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_RemovePeerCommand proto.InternalMessageInfo
+var xxx_messageInfo_UpdateShardOwnersCommand proto.InternalMessageInfo
 
-func (m *RemovePeerCommand) GetID() uint64 {
+func (m *UpdateShardOwnersCommand) GetID() uint64 {
 	if m != nil && m.ID != nil {
 		return *m.ID
 	}
 	return 0
 }
 
-func (m *RemovePeerCommand) GetAddr() string {
-	if m != nil && m.Addr != nil {
-		return *m.Addr
+func (m *UpdateShardOwnersCommand) GetAddOwners() []uint64 {
+	if m != nil {
+		return m.AddOwners
 	}
-	return ""
+	return nil
 }
 
-var E_RemovePeerCommand_Command = &proto.ExtensionDesc{
+func (m *UpdateShardOwnersCommand) GetDelOwners() []uint64 {
+	if m != nil {
+		return m.DelOwners
+	}
+	return nil
+}
+
+var E_UpdateShardOwnersCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*RemovePeerCommand)(nil),
-	Field:         123,
-	Name:          "meta.RemovePeerCommand.command",
-	Tag:           "bytes,123,opt,name=command",
+	ExtensionType: (*UpdateShardOwnersCommand)(nil),
+	Field:         131,
+	Name:          "meta.UpdateShardOwnersCommand.command",
+	Tag:           "bytes,131,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type CreateMetaNodeCommand struct {
-	HTTPAddr             *string  `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
-	TCPAddr              *string  `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
-	Rand                 *uint64  `protobuf:"varint,3,req,name=Rand" json:"Rand,omitempty"`
+// SetContinuousQueryLastRunCommand records the time a continuous query
+// last ran, so that the node running it next resumes from there, and the
+// outcome of the run.
+type SetContinuousQueryLastRunCommand struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Name                 *string  `protobuf:"bytes,2,req,name=Name" json:"Name,omitempty"`
+	LastRun              *int64   `protobuf:"varint,3,req,name=LastRun" json:"LastRun,omitempty"`
+	LastError            *string  `protobuf:"bytes,4,opt,name=LastError" json:"LastError,omitempty"`
+	LastDuration         *int64   `protobuf:"varint,5,opt,name=LastDuration" json:"LastDuration,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CreateMetaNodeCommand) Reset()         { *m = CreateMetaNodeCommand{} }
-func (m *CreateMetaNodeCommand) String() string { return proto.CompactTextString(m) }
-func (*CreateMetaNodeCommand) ProtoMessage()    {}
-func (*CreateMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{35}
+func (m *SetContinuousQueryLastRunCommand) Reset()         { *m = SetContinuousQueryLastRunCommand{} }
+func (m *SetContinuousQueryLastRunCommand) String() string { return proto.CompactTextString(m) }
+func (*SetContinuousQueryLastRunCommand) ProtoMessage()    {}
+func (*SetContinuousQueryLastRunCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{50}
 }
-func (m *CreateMetaNodeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateMetaNodeCommand.Unmarshal(m, b)
+func (m *SetContinuousQueryLastRunCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetContinuousQueryLastRunCommand.Unmarshal(m, b)
 }
-func (m *CreateMetaNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateMetaNodeCommand.Marshal(b, m, deterministic)
+func (m *SetContinuousQueryLastRunCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetContinuousQueryLastRunCommand.Marshal(b, m, deterministic)
 }
-func (m *CreateMetaNodeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateMetaNodeCommand.Merge(m, src)
+func (m *SetContinuousQueryLastRunCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetContinuousQueryLastRunCommand.Merge(m, src)
 }
-func (m *CreateMetaNodeCommand) XXX_Size() int {
-	return xxx_messageInfo_CreateMetaNodeCommand.Size(m)
+func (m *SetContinuousQueryLastRunCommand) XXX_Size() int {
+	return xxx_messageInfo_SetContinuousQueryLastRunCommand.Size(m)
 }
-func (m *CreateMetaNodeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateMetaNodeCommand.DiscardUnknown(m)
+func (m *SetContinuousQueryLastRunCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetContinuousQueryLastRunCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_CreateMetaNodeCommand proto.InternalMessageInfo
+var xxx_messageInfo_SetContinuousQueryLastRunCommand proto.InternalMessageInfo
 
-func (m *CreateMetaNodeCommand) GetHTTPAddr() string {
-	if m != nil && m.HTTPAddr != nil {
-		return *m.HTTPAddr
+func (m *SetContinuousQueryLastRunCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
 	}
 	return ""
 }
 
-func (m *CreateMetaNodeCommand) GetTCPAddr() string {
-	if m != nil && m.TCPAddr != nil {
-		return *m.TCPAddr
+func (m *SetContinuousQueryLastRunCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
 	}
 	return ""
 }
 
-func (m *CreateMetaNodeCommand) GetRand() uint64 {
-	if m != nil && m.Rand != nil {
-		return *m.Rand
+func (m *SetContinuousQueryLastRunCommand) GetLastRun() int64 {
+	if m != nil && m.LastRun != nil {
+		return *m.LastRun
 	}
 	return 0
 }
 
-var E_CreateMetaNodeCommand_Command = &proto.ExtensionDesc{
+func (m *SetContinuousQueryLastRunCommand) GetLastError() string {
+	if m != nil && m.LastError != nil {
+		return *m.LastError
+	}
+	return ""
+}
+
+func (m *SetContinuousQueryLastRunCommand) GetLastDuration() int64 {
+	if m != nil && m.LastDuration != nil {
+		return *m.LastDuration
+	}
+	return 0
+}
+
+var E_SetContinuousQueryLastRunCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*CreateMetaNodeCommand)(nil),
-	Field:         124,
-	Name:          "meta.CreateMetaNodeCommand.command",
-	Tag:           "bytes,124,opt,name=command",
+	ExtensionType: (*SetContinuousQueryLastRunCommand)(nil),
+	Field:         132,
+	Name:          "meta.SetContinuousQueryLastRunCommand.command",
+	Tag:           "bytes,132,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type CreateDataNodeCommand struct {
-	HTTPAddr             *string  `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
-	TCPAddr              *string  `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
+// SetSeriesPrivilegeCommand sets the privilege of a user on the series of
+// a metric matching a condition. A privilege of zero removes it.
+type SetSeriesPrivilegeCommand struct {
+	Username             *string  `protobuf:"bytes,1,req,name=Username" json:"Username,omitempty"`
+	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
+	Metric               *string  `protobuf:"bytes,3,opt,name=Metric" json:"Metric,omitempty"`
+	Condition            *string  `protobuf:"bytes,4,opt,name=Condition" json:"Condition,omitempty"`
+	Privilege            *int32   `protobuf:"varint,5,req,name=Privilege" json:"Privilege,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *CreateDataNodeCommand) Reset()         { *m = CreateDataNodeCommand{} }
-func (m *CreateDataNodeCommand) String() string { return proto.CompactTextString(m) }
-func (*CreateDataNodeCommand) ProtoMessage()    {}
-func (*CreateDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{36}
+func (m *SetSeriesPrivilegeCommand) Reset()         { *m = SetSeriesPrivilegeCommand{} }
+func (m *SetSeriesPrivilegeCommand) String() string { return proto.CompactTextString(m) }
+func (*SetSeriesPrivilegeCommand) ProtoMessage()    {}
+func (*SetSeriesPrivilegeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{51}
 }
-func (m *CreateDataNodeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_CreateDataNodeCommand.Unmarshal(m, b)
+func (m *SetSeriesPrivilegeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetSeriesPrivilegeCommand.Unmarshal(m, b)
 }
-func (m *CreateDataNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_CreateDataNodeCommand.Marshal(b, m, deterministic)
+func (m *SetSeriesPrivilegeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetSeriesPrivilegeCommand.Marshal(b, m, deterministic)
 }
-func (m *CreateDataNodeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_CreateDataNodeCommand.Merge(m, src)
+func (m *SetSeriesPrivilegeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetSeriesPrivilegeCommand.Merge(m, src)
 }
-func (m *CreateDataNodeCommand) XXX_Size() int {
-	return xxx_messageInfo_CreateDataNodeCommand.Size(m)
+func (m *SetSeriesPrivilegeCommand) XXX_Size() int {
+	return xxx_messageInfo_SetSeriesPrivilegeCommand.Size(m)
 }
-func (m *CreateDataNodeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_CreateDataNodeCommand.DiscardUnknown(m)
+func (m *SetSeriesPrivilegeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetSeriesPrivilegeCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetSeriesPrivilegeCommand proto.InternalMessageInfo
+
+func (m *SetSeriesPrivilegeCommand) GetUsername() string {
+	if m != nil && m.Username != nil {
+		return *m.Username
+	}
+	return ""
+}
+
+func (m *SetSeriesPrivilegeCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
 }
 
-var xxx_messageInfo_CreateDataNodeCommand proto.InternalMessageInfo
-
-func (m *CreateDataNodeCommand) GetHTTPAddr() string {
-	if m != nil && m.HTTPAddr != nil {
-		return *m.HTTPAddr
+func (m *SetSeriesPrivilegeCommand) GetMetric() string {
+	if m != nil && m.Metric != nil {
+		return *m.Metric
 	}
 	return ""
 }
 
-func (m *CreateDataNodeCommand) GetTCPAddr() string {
-	if m != nil && m.TCPAddr != nil {
-		return *m.TCPAddr
+func (m *SetSeriesPrivilegeCommand) GetCondition() string {
+	if m != nil && m.Condition != nil {
+		return *m.Condition
 	}
 	return ""
 }
 
-var E_CreateDataNodeCommand_Command = &proto.ExtensionDesc{
+func (m *SetSeriesPrivilegeCommand) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
+	}
+	return 0
+}
+
+var E_SetSeriesPrivilegeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*CreateDataNodeCommand)(nil),
-	Field:         125,
-	Name:          "meta.CreateDataNodeCommand.command",
-	Tag:           "bytes,125,opt,name=command",
+	ExtensionType: (*SetSeriesPrivilegeCommand)(nil),
+	Field:         133,
+	Name:          "meta.SetSeriesPrivilegeCommand.command",
+	Tag:           "bytes,133,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type UpdateDataNodeCommand struct {
-	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
-	Host                 *string  `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
-	TCPHost              *string  `protobuf:"bytes,3,req,name=TCPHost" json:"TCPHost,omitempty"`
+type CreateRoleCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *UpdateDataNodeCommand) Reset()         { *m = UpdateDataNodeCommand{} }
-func (m *UpdateDataNodeCommand) String() string { return proto.CompactTextString(m) }
-func (*UpdateDataNodeCommand) ProtoMessage()    {}
-func (*UpdateDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{37}
-}
-func (m *UpdateDataNodeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_UpdateDataNodeCommand.Unmarshal(m, b)
+func (m *CreateRoleCommand) Reset()         { *m = CreateRoleCommand{} }
+func (m *CreateRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*CreateRoleCommand) ProtoMessage()    {}
+func (*CreateRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{52}
 }
-func (m *UpdateDataNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_UpdateDataNodeCommand.Marshal(b, m, deterministic)
+func (m *CreateRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateRoleCommand.Unmarshal(m, b)
 }
-func (m *UpdateDataNodeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_UpdateDataNodeCommand.Merge(m, src)
+func (m *CreateRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateRoleCommand.Marshal(b, m, deterministic)
 }
-func (m *UpdateDataNodeCommand) XXX_Size() int {
-	return xxx_messageInfo_UpdateDataNodeCommand.Size(m)
+func (m *CreateRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateRoleCommand.Merge(m, src)
 }
-func (m *UpdateDataNodeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_UpdateDataNodeCommand.DiscardUnknown(m)
+func (m *CreateRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_CreateRoleCommand.Size(m)
 }
-
-var xxx_messageInfo_UpdateDataNodeCommand proto.InternalMessageInfo
-
-func (m *UpdateDataNodeCommand) GetID() uint64 {
-	if m != nil && m.ID != nil {
-		return *m.ID
-	}
-	return 0
+func (m *CreateRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateRoleCommand.DiscardUnknown(m)
 }
 
-func (m *UpdateDataNodeCommand) GetHost() string {
-	if m != nil && m.Host != nil {
-		return *m.Host
-	}
-	return ""
-}
+var xxx_messageInfo_CreateRoleCommand proto.InternalMessageInfo
 
-func (m *UpdateDataNodeCommand) GetTCPHost() string {
-	if m != nil && m.TCPHost != nil {
-		return *m.TCPHost
+func (m *CreateRoleCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
 	}
 	return ""
 }
 
-var E_UpdateDataNodeCommand_Command = &proto.ExtensionDesc{
+var E_CreateRoleCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*UpdateDataNodeCommand)(nil),
-	Field:         126,
-	Name:          "meta.UpdateDataNodeCommand.command",
-	Tag:           "bytes,126,opt,name=command",
+	ExtensionType: (*CreateRoleCommand)(nil),
+	Field:         134,
+	Name:          "meta.CreateRoleCommand.command",
+	Tag:           "bytes,134,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type DeleteMetaNodeCommand struct {
-	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+type DropRoleCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DeleteMetaNodeCommand) Reset()         { *m = DeleteMetaNodeCommand{} }
-func (m *DeleteMetaNodeCommand) String() string { return proto.CompactTextString(m) }
-func (*DeleteMetaNodeCommand) ProtoMessage()    {}
-func (*DeleteMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{38}
+func (m *DropRoleCommand) Reset()         { *m = DropRoleCommand{} }
+func (m *DropRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*DropRoleCommand) ProtoMessage()    {}
+func (*DropRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{53}
 }
-func (m *DeleteMetaNodeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DeleteMetaNodeCommand.Unmarshal(m, b)
+func (m *DropRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DropRoleCommand.Unmarshal(m, b)
 }
-func (m *DeleteMetaNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DeleteMetaNodeCommand.Marshal(b, m, deterministic)
+func (m *DropRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DropRoleCommand.Marshal(b, m, deterministic)
 }
-func (m *DeleteMetaNodeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteMetaNodeCommand.Merge(m, src)
+func (m *DropRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DropRoleCommand.Merge(m, src)
 }
-func (m *DeleteMetaNodeCommand) XXX_Size() int {
-	return xxx_messageInfo_DeleteMetaNodeCommand.Size(m)
+func (m *DropRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_DropRoleCommand.Size(m)
 }
-func (m *DeleteMetaNodeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteMetaNodeCommand.DiscardUnknown(m)
+func (m *DropRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_DropRoleCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DeleteMetaNodeCommand proto.InternalMessageInfo
+var xxx_messageInfo_DropRoleCommand proto.InternalMessageInfo
 
-func (m *DeleteMetaNodeCommand) GetID() uint64 {
-	if m != nil && m.ID != nil {
-		return *m.ID
+func (m *DropRoleCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
 	}
-	return 0
+	return ""
 }
 
-var E_DeleteMetaNodeCommand_Command = &proto.ExtensionDesc{
+var E_DropRoleCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DeleteMetaNodeCommand)(nil),
-	Field:         127,
-	Name:          "meta.DeleteMetaNodeCommand.command",
-	Tag:           "bytes,127,opt,name=command",
+	ExtensionType: (*DropRoleCommand)(nil),
+	Field:         135,
+	Name:          "meta.DropRoleCommand.command",
+	Tag:           "bytes,135,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type DeleteDataNodeCommand struct {
-	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+type SetRolePrivilegeCommand struct {
+	Role                 *string  `protobuf:"bytes,1,req,name=Role" json:"Role,omitempty"`
+	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
+	Privilege            *int32   `protobuf:"varint,3,req,name=Privilege" json:"Privilege,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DeleteDataNodeCommand) Reset()         { *m = DeleteDataNodeCommand{} }
-func (m *DeleteDataNodeCommand) String() string { return proto.CompactTextString(m) }
-func (*DeleteDataNodeCommand) ProtoMessage()    {}
-func (*DeleteDataNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{39}
+func (m *SetRolePrivilegeCommand) Reset()         { *m = SetRolePrivilegeCommand{} }
+func (m *SetRolePrivilegeCommand) String() string { return proto.CompactTextString(m) }
+func (*SetRolePrivilegeCommand) ProtoMessage()    {}
+func (*SetRolePrivilegeCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{54}
 }
-func (m *DeleteDataNodeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DeleteDataNodeCommand.Unmarshal(m, b)
+func (m *SetRolePrivilegeCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRolePrivilegeCommand.Unmarshal(m, b)
 }
-func (m *DeleteDataNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DeleteDataNodeCommand.Marshal(b, m, deterministic)
+func (m *SetRolePrivilegeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRolePrivilegeCommand.Marshal(b, m, deterministic)
 }
-func (m *DeleteDataNodeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DeleteDataNodeCommand.Merge(m, src)
+func (m *SetRolePrivilegeCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRolePrivilegeCommand.Merge(m, src)
 }
-func (m *DeleteDataNodeCommand) XXX_Size() int {
-	return xxx_messageInfo_DeleteDataNodeCommand.Size(m)
+func (m *SetRolePrivilegeCommand) XXX_Size() int {
+	return xxx_messageInfo_SetRolePrivilegeCommand.Size(m)
 }
-func (m *DeleteDataNodeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DeleteDataNodeCommand.DiscardUnknown(m)
+func (m *SetRolePrivilegeCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRolePrivilegeCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DeleteDataNodeCommand proto.InternalMessageInfo
+var xxx_messageInfo_SetRolePrivilegeCommand proto.InternalMessageInfo
 
-func (m *DeleteDataNodeCommand) GetID() uint64 {
-	if m != nil && m.ID != nil {
-		return *m.ID
+func (m *SetRolePrivilegeCommand) GetRole() string {
+	if m != nil && m.Role != nil {
+		return *m.Role
+	}
+	return ""
+}
+
+func (m *SetRolePrivilegeCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SetRolePrivilegeCommand) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
 	}
 	return 0
 }
 
-var E_DeleteDataNodeCommand_Command = &proto.ExtensionDesc{
+var E_SetRolePrivilegeCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DeleteDataNodeCommand)(nil),
-	Field:         128,
-	Name:          "meta.DeleteDataNodeCommand.command",
-	Tag:           "bytes,128,opt,name=command",
+	ExtensionType: (*SetRolePrivilegeCommand)(nil),
+	Field:         136,
+	Name:          "meta.SetRolePrivilegeCommand.command",
+	Tag:           "bytes,136,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type Response struct {
-	OK                   *bool    `protobuf:"varint,1,req,name=OK" json:"OK,omitempty"`
-	Error                *string  `protobuf:"bytes,2,opt,name=Error" json:"Error,omitempty"`
-	Index                *uint64  `protobuf:"varint,3,opt,name=Index" json:"Index,omitempty"`
+// SetUserRoleCommand grants a role to a user, or revokes it.
+type SetUserRoleCommand struct {
+	Username             *string  `protobuf:"bytes,1,req,name=Username" json:"Username,omitempty"`
+	Role                 *string  `protobuf:"bytes,2,req,name=Role" json:"Role,omitempty"`
+	Granted              *bool    `protobuf:"varint,3,req,name=Granted" json:"Granted,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Response) Reset()         { *m = Response{} }
-func (m *Response) String() string { return proto.CompactTextString(m) }
-func (*Response) ProtoMessage()    {}
-func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{40}
+func (m *SetUserRoleCommand) Reset()         { *m = SetUserRoleCommand{} }
+func (m *SetUserRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*SetUserRoleCommand) ProtoMessage()    {}
+func (*SetUserRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{55}
 }
-func (m *Response) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Response.Unmarshal(m, b)
+func (m *SetUserRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetUserRoleCommand.Unmarshal(m, b)
 }
-func (m *Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Response.Marshal(b, m, deterministic)
+func (m *SetUserRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetUserRoleCommand.Marshal(b, m, deterministic)
 }
-func (m *Response) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Response.Merge(m, src)
+func (m *SetUserRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetUserRoleCommand.Merge(m, src)
 }
-func (m *Response) XXX_Size() int {
-	return xxx_messageInfo_Response.Size(m)
+func (m *SetUserRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_SetUserRoleCommand.Size(m)
 }
-func (m *Response) XXX_DiscardUnknown() {
-	xxx_messageInfo_Response.DiscardUnknown(m)
+func (m *SetUserRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetUserRoleCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Response proto.InternalMessageInfo
+var xxx_messageInfo_SetUserRoleCommand proto.InternalMessageInfo
 
-func (m *Response) GetOK() bool {
-	if m != nil && m.OK != nil {
-		return *m.OK
+func (m *SetUserRoleCommand) GetUsername() string {
+	if m != nil && m.Username != nil {
+		return *m.Username
 	}
-	return false
+	return ""
 }
 
-func (m *Response) GetError() string {
-	if m != nil && m.Error != nil {
-		return *m.Error
+func (m *SetUserRoleCommand) GetRole() string {
+	if m != nil && m.Role != nil {
+		return *m.Role
 	}
 	return ""
 }
 
-func (m *Response) GetIndex() uint64 {
-	if m != nil && m.Index != nil {
-		return *m.Index
+func (m *SetUserRoleCommand) GetGranted() bool {
+	if m != nil && m.Granted != nil {
+		return *m.Granted
 	}
-	return 0
+	return false
 }
 
-// SetMetaNodeCommand is for the initial metanode in a cluster or
-// if the single host restarts and its hostname changes, this will update it
-type SetMetaNodeCommand struct {
-	HTTPAddr             *string  `protobuf:"bytes,1,req,name=HTTPAddr" json:"HTTPAddr,omitempty"`
-	TCPAddr              *string  `protobuf:"bytes,2,req,name=TCPAddr" json:"TCPAddr,omitempty"`
-	Rand                 *uint64  `protobuf:"varint,3,req,name=Rand" json:"Rand,omitempty"`
+var E_SetUserRoleCommand_Command = &proto.ExtensionDesc{
+	ExtendedType:  (*Command)(nil),
+	ExtensionType: (*SetUserRoleCommand)(nil),
+	Field:         137,
+	Name:          "meta.SetUserRoleCommand.command",
+	Tag:           "bytes,137,opt,name=command",
+	Filename:      "meta.proto",
+}
+
+// SetRoleRoleCommand grants a role to a role inheriting it, or revokes it.
+type SetRoleRoleCommand struct {
+	Name                 *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Role                 *string  `protobuf:"bytes,2,req,name=Role" json:"Role,omitempty"`
+	Granted              *bool    `protobuf:"varint,3,req,name=Granted" json:"Granted,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *SetMetaNodeCommand) Reset()         { *m = SetMetaNodeCommand{} }
-func (m *SetMetaNodeCommand) String() string { return proto.CompactTextString(m) }
-func (*SetMetaNodeCommand) ProtoMessage()    {}
-func (*SetMetaNodeCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{41}
+func (m *SetRoleRoleCommand) Reset()         { *m = SetRoleRoleCommand{} }
+func (m *SetRoleRoleCommand) String() string { return proto.CompactTextString(m) }
+func (*SetRoleRoleCommand) ProtoMessage()    {}
+func (*SetRoleRoleCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{56}
 }
-func (m *SetMetaNodeCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SetMetaNodeCommand.Unmarshal(m, b)
+func (m *SetRoleRoleCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetRoleRoleCommand.Unmarshal(m, b)
 }
-func (m *SetMetaNodeCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SetMetaNodeCommand.Marshal(b, m, deterministic)
+func (m *SetRoleRoleCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetRoleRoleCommand.Marshal(b, m, deterministic)
 }
-func (m *SetMetaNodeCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetMetaNodeCommand.Merge(m, src)
+func (m *SetRoleRoleCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetRoleRoleCommand.Merge(m, src)
 }
-func (m *SetMetaNodeCommand) XXX_Size() int {
-	return xxx_messageInfo_SetMetaNodeCommand.Size(m)
+func (m *SetRoleRoleCommand) XXX_Size() int {
+	return xxx_messageInfo_SetRoleRoleCommand.Size(m)
 }
-func (m *SetMetaNodeCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_SetMetaNodeCommand.DiscardUnknown(m)
+func (m *SetRoleRoleCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetRoleRoleCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_SetMetaNodeCommand proto.InternalMessageInfo
+var xxx_messageInfo_SetRoleRoleCommand proto.InternalMessageInfo
 
-func (m *SetMetaNodeCommand) GetHTTPAddr() string {
-	if m != nil && m.HTTPAddr != nil {
-		return *m.HTTPAddr
+func (m *SetRoleRoleCommand) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
 	}
 	return ""
 }
 
-func (m *SetMetaNodeCommand) GetTCPAddr() string {
-	if m != nil && m.TCPAddr != nil {
-		return *m.TCPAddr
+func (m *SetRoleRoleCommand) GetRole() string {
+	if m != nil && m.Role != nil {
+		return *m.Role
 	}
 	return ""
 }
 
-func (m *SetMetaNodeCommand) GetRand() uint64 {
-	if m != nil && m.Rand != nil {
-		return *m.Rand
+func (m *SetRoleRoleCommand) GetGranted() bool {
+	if m != nil && m.Granted != nil {
+		return *m.Granted
 	}
-	return 0
+	return false
 }
 
-var E_SetMetaNodeCommand_Command = &proto.ExtensionDesc{
+var E_SetRoleRoleCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*SetMetaNodeCommand)(nil),
-	Field:         129,
-	Name:          "meta.SetMetaNodeCommand.command",
-	Tag:           "bytes,129,opt,name=command",
+	ExtensionType: (*SetRoleRoleCommand)(nil),
+	Field:         138,
+	Name:          "meta.SetRoleRoleCommand.command",
+	Tag:           "bytes,138,opt,name=command",
 	Filename:      "meta.proto",
 }
 
-type DropShardCommand struct {
-	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+// SetQuotaCommand sets the quota of either a database or a user.
+type SetQuotaCommand struct {
+	Database             *string  `protobuf:"bytes,1,opt,name=Database" json:"Database,omitempty"`
+	User                 *string  `protobuf:"bytes,2,opt,name=User" json:"User,omitempty"`
+	Quota                *Quota   `protobuf:"bytes,3,req,name=Quota" json:"Quota,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *DropShardCommand) Reset()         { *m = DropShardCommand{} }
-func (m *DropShardCommand) String() string { return proto.CompactTextString(m) }
-func (*DropShardCommand) ProtoMessage()    {}
-func (*DropShardCommand) Descriptor() ([]byte, []int) {
-	return fileDescriptor_3b5ea8fe65782bcc, []int{42}
+func (m *SetQuotaCommand) Reset()         { *m = SetQuotaCommand{} }
+func (m *SetQuotaCommand) String() string { return proto.CompactTextString(m) }
+func (*SetQuotaCommand) ProtoMessage()    {}
+func (*SetQuotaCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3b5ea8fe65782bcc, []int{57}
 }
-func (m *DropShardCommand) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_DropShardCommand.Unmarshal(m, b)
+func (m *SetQuotaCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetQuotaCommand.Unmarshal(m, b)
 }
-func (m *DropShardCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_DropShardCommand.Marshal(b, m, deterministic)
+func (m *SetQuotaCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetQuotaCommand.Marshal(b, m, deterministic)
 }
-func (m *DropShardCommand) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_DropShardCommand.Merge(m, src)
+func (m *SetQuotaCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetQuotaCommand.Merge(m, src)
 }
-func (m *DropShardCommand) XXX_Size() int {
-	return xxx_messageInfo_DropShardCommand.Size(m)
+func (m *SetQuotaCommand) XXX_Size() int {
+	return xxx_messageInfo_SetQuotaCommand.Size(m)
 }
-func (m *DropShardCommand) XXX_DiscardUnknown() {
-	xxx_messageInfo_DropShardCommand.DiscardUnknown(m)
+func (m *SetQuotaCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetQuotaCommand.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_DropShardCommand proto.InternalMessageInfo
+var xxx_messageInfo_SetQuotaCommand proto.InternalMessageInfo
 
-func (m *DropShardCommand) GetID() uint64 {
-	if m != nil && m.ID != nil {
-		return *m.ID
+func (m *SetQuotaCommand) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
 	}
-	return 0
+	return ""
 }
 
-var E_DropShardCommand_Command = &proto.ExtensionDesc{
+func (m *SetQuotaCommand) GetUser() string {
+	if m != nil && m.User != nil {
+		return *m.User
+	}
+	return ""
+}
+
+func (m *SetQuotaCommand) GetQuota() *Quota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+var E_SetQuotaCommand_Command = &proto.ExtensionDesc{
 	ExtendedType:  (*Command)(nil),
-	ExtensionType: (*DropShardCommand)(nil),
-	Field:         130,
-	Name:          "meta.DropShardCommand.command",
-	Tag:           "bytes,130,opt,name=command",
+	ExtensionType: (*SetQuotaCommand)(nil),
+	Field:         139,
+	Name:          "meta.SetQuotaCommand.command",
+	Tag:           "bytes,139,opt,name=command",
 	Filename:      "meta.proto",
 }
 
@@ -2689,6 +3806,7 @@ func init() {
 	proto.RegisterType((*Data)(nil), "meta.Data")
 	proto.RegisterType((*NodeInfo)(nil), "meta.NodeInfo")
 	proto.RegisterType((*DatabaseInfo)(nil), "meta.DatabaseInfo")
+	proto.RegisterType((*Quota)(nil), "meta.Quota")
 	proto.RegisterType((*TimeToLiveSpec)(nil), "meta.TimeToLiveSpec")
 	proto.RegisterType((*TimeToLiveInfo)(nil), "meta.TimeToLiveInfo")
 	proto.RegisterType((*RegionInfo)(nil), "meta.RegionInfo")
@@ -2698,6 +3816,11 @@ func init() {
 	proto.RegisterType((*ContinuousQueryInfo)(nil), "meta.ContinuousQueryInfo")
 	proto.RegisterType((*UserInfo)(nil), "meta.UserInfo")
 	proto.RegisterType((*UserPrivilege)(nil), "meta.UserPrivilege")
+	proto.RegisterType((*RoleInfo)(nil), "meta.RoleInfo")
+	proto.RegisterType((*UserSeriesPrivilege)(nil), "meta.UserSeriesPrivilege")
+	proto.RegisterType((*DataDelta)(nil), "meta.DataDelta")
+	proto.RegisterType((*DatabaseDelta)(nil), "meta.DatabaseDelta")
+	proto.RegisterType((*DataDeltas)(nil), "meta.DataDeltas")
 	proto.RegisterType((*Command)(nil), "meta.Command")
 	proto.RegisterExtension(E_CreateNodeCommand_Command)
 	proto.RegisterType((*CreateNodeCommand)(nil), "meta.CreateNodeCommand")
@@ -2758,123 +3881,186 @@ func init() {
 	proto.RegisterType((*SetMetaNodeCommand)(nil), "meta.SetMetaNodeCommand")
 	proto.RegisterExtension(E_DropShardCommand_Command)
 	proto.RegisterType((*DropShardCommand)(nil), "meta.DropShardCommand")
+	proto.RegisterExtension(E_UpdateShardOwnersCommand_Command)
+	proto.RegisterType((*UpdateShardOwnersCommand)(nil), "meta.UpdateShardOwnersCommand")
+	proto.RegisterExtension(E_SetContinuousQueryLastRunCommand_Command)
+	proto.RegisterType((*SetContinuousQueryLastRunCommand)(nil), "meta.SetContinuousQueryLastRunCommand")
+	proto.RegisterExtension(E_SetSeriesPrivilegeCommand_Command)
+	proto.RegisterType((*SetSeriesPrivilegeCommand)(nil), "meta.SetSeriesPrivilegeCommand")
+	proto.RegisterExtension(E_CreateRoleCommand_Command)
+	proto.RegisterType((*CreateRoleCommand)(nil), "meta.CreateRoleCommand")
+	proto.RegisterExtension(E_DropRoleCommand_Command)
+	proto.RegisterType((*DropRoleCommand)(nil), "meta.DropRoleCommand")
+	proto.RegisterExtension(E_SetRolePrivilegeCommand_Command)
+	proto.RegisterType((*SetRolePrivilegeCommand)(nil), "meta.SetRolePrivilegeCommand")
+	proto.RegisterExtension(E_SetUserRoleCommand_Command)
+	proto.RegisterType((*SetUserRoleCommand)(nil), "meta.SetUserRoleCommand")
+	proto.RegisterExtension(E_SetRoleRoleCommand_Command)
+	proto.RegisterType((*SetRoleRoleCommand)(nil), "meta.SetRoleRoleCommand")
+	proto.RegisterExtension(E_SetQuotaCommand_Command)
+	proto.RegisterType((*SetQuotaCommand)(nil), "meta.SetQuotaCommand")
 }
 
 func init() { proto.RegisterFile("meta.proto", fileDescriptor_3b5ea8fe65782bcc) }
 
 var fileDescriptor_3b5ea8fe65782bcc = []byte{
-	// 1807 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0x4f, 0x6f, 0xdb, 0xc6,
-	0x12, 0x07, 0x49, 0x49, 0x96, 0xc6, 0xb1, 0x63, 0xaf, 0xff, 0xd1, 0xb1, 0xe3, 0xe8, 0x11, 0x41,
-	0x9e, 0x10, 0x04, 0xc1, 0x83, 0xde, 0xc3, 0x3b, 0xf5, 0x5f, 0x62, 0x25, 0xb1, 0x9a, 0xda, 0x71,
-	0x29, 0xe5, 0x5a, 0x80, 0xb1, 0x36, 0xb1, 0x5a, 0x8b, 0x54, 0x49, 0xca, 0xb1, 0x9b, 0xba, 0x75,
-	0x8b, 0x1e, 0x7a, 0xe9, 0xa9, 0x28, 0x8a, 0xa2, 0xb7, 0x5e, 0x7a, 0x2c, 0x8a, 0x9e, 0xf3, 0x11,
-	0x7a, 0xe8, 0x07, 0x69, 0xbf, 0x41, 0xd1, 0x62, 0x77, 0xb9, 0xdc, 0x25, 0xb9, 0xcb, 0xd8, 0x4d,
-	0x6e, 0xda, 0x99, 0xd9, 0x99, 0xdf, 0xec, 0xce, 0x0c, 0x67, 0x56, 0x00, 0x23, 0x1c, 0x7b, 0x37,
-	0xc7, 0x61, 0x10, 0x07, 0xa8, 0x42, 0x7e, 0x3b, 0x5f, 0x5a, 0x50, 0xe9, 0x78, 0xb1, 0x87, 0x10,
-	0x54, 0xfa, 0x38, 0x1c, 0xd9, 0x46, 0xd3, 0x6c, 0x55, 0x5c, 0xfa, 0x1b, 0x2d, 0x42, 0xb5, 0xeb,
-	0x0f, 0xf0, 0x91, 0x6d, 0x52, 0x22, 0x5b, 0xa0, 0x75, 0x68, 0x6c, 0x1e, 0x4c, 0xa2, 0x18, 0x87,
-	0xdd, 0x8e, 0x6d, 0x51, 0x8e, 0x20, 0xa0, 0xab, 0x50, 0xdd, 0x09, 0x06, 0x38, 0xb2, 0x2b, 0x4d,
-	0xab, 0x35, 0xdd, 0x9e, 0xbd, 0x49, 0x4d, 0x12, 0x52, 0xd7, 0x7f, 0x1c, 0xb8, 0x8c, 0x89, 0xfe,
-	0x03, 0x0d, 0x62, 0xf5, 0x91, 0x17, 0xe1, 0xc8, 0xae, 0x52, 0x49, 0xc4, 0x24, 0x39, 0x99, 0x4a,
-	0x0b, 0x21, 0xa2, 0xf7, 0x61, 0x84, 0xc3, 0xc8, 0xae, 0xc9, 0x7a, 0x09, 0x89, 0xe9, 0xa5, 0x4c,
-	0x82, 0x6d, 0xdb, 0x3b, 0xa2, 0xd6, 0x3a, 0xf6, 0x14, 0xc3, 0x96, 0x12, 0x50, 0x13, 0xa6, 0xb7,
-	0xbd, 0x23, 0x17, 0x3f, 0x19, 0x06, 0x7e, 0xb7, 0x63, 0xd7, 0x29, 0x5f, 0x26, 0xa1, 0x0d, 0x80,
-	0x6d, 0xef, 0xa8, 0xb7, 0xef, 0x85, 0x83, 0x6e, 0xc7, 0x6e, 0x50, 0x01, 0x89, 0x82, 0x6e, 0x30,
-	0xdc, 0xcc, 0x43, 0x50, 0x7a, 0x28, 0x04, 0x88, 0xf4, 0x36, 0xe6, 0xd2, 0xd3, 0x6a, 0xe9, 0x54,
-	0xc0, 0xd9, 0x82, 0x3a, 0x27, 0xa3, 0x59, 0x30, 0xbb, 0x9d, 0xe4, 0x2e, 0xcc, 0x6e, 0x87, 0xdc,
-	0xce, 0x56, 0x10, 0xc5, 0xf4, 0x22, 0x1a, 0x2e, 0xfd, 0x8d, 0x6c, 0x98, 0xea, 0x6f, 0xee, 0x52,
-	0xb2, 0xd5, 0x34, 0x5a, 0x0d, 0x97, 0x2f, 0x9d, 0xdf, 0x0c, 0xb8, 0x20, 0x9f, 0x23, 0xd9, 0xbe,
-	0xe3, 0x8d, 0x30, 0x55, 0xd8, 0x70, 0xe9, 0x6f, 0x74, 0x03, 0xe6, 0x3b, 0xf8, 0xb1, 0x37, 0x39,
-	0x88, 0xfb, 0xc3, 0x11, 0xee, 0x07, 0xef, 0x0c, 0x0f, 0x71, 0xa2, 0xbf, 0xc8, 0x40, 0xff, 0x87,
-	0x69, 0xb1, 0x8a, 0x6c, 0x8b, 0x3a, 0xb3, 0xc8, 0x9c, 0x11, 0x0c, 0xea, 0x92, 0x2c, 0x88, 0xee,
-	0xc1, 0xfc, 0x66, 0xe0, 0xc7, 0x43, 0x7f, 0x12, 0x4c, 0xa2, 0x77, 0x27, 0x38, 0x1c, 0xa6, 0xa1,
-	0xb1, 0xca, 0x76, 0x67, 0xd9, 0xc7, 0x54, 0x45, 0x71, 0x8f, 0xf3, 0x85, 0x01, 0xb3, 0x42, 0x71,
-	0x6f, 0x8c, 0xf7, 0x24, 0xaf, 0x8c, 0xd4, 0xab, 0x4b, 0x50, 0xef, 0x4c, 0x42, 0x2f, 0x1e, 0x06,
-	0xbe, 0x6d, 0x36, 0x8d, 0x96, 0xe5, 0xa6, 0x6b, 0x74, 0x0d, 0x66, 0xd9, 0x45, 0xa7, 0x12, 0x16,
-	0x95, 0xc8, 0x51, 0x89, 0x0e, 0x17, 0x8f, 0x0f, 0x86, 0x7b, 0xde, 0x8e, 0x5d, 0x69, 0x1a, 0xad,
-	0x19, 0x37, 0x5d, 0x3b, 0x7f, 0x64, 0x60, 0x68, 0x0f, 0x37, 0x0b, 0xc3, 0x7c, 0x21, 0x0c, 0xf3,
-	0x85, 0x30, 0x4c, 0x19, 0x06, 0xba, 0x0e, 0x53, 0x4c, 0x9a, 0x67, 0xcf, 0x1c, 0x3b, 0xcc, 0x24,
-	0x90, 0xc9, 0x19, 0x72, 0x01, 0xf4, 0x1a, 0xcc, 0xf4, 0x26, 0x8f, 0xa2, 0xbd, 0x70, 0x38, 0x8e,
-	0xe9, 0x0e, 0x96, 0x41, 0xcb, 0x6c, 0x87, 0xcc, 0xa2, 0xfb, 0xb2, 0xc2, 0xce, 0x73, 0x03, 0x40,
-	0x68, 0x2d, 0x04, 0xe6, 0x3a, 0x34, 0x7a, 0xb1, 0x17, 0xd2, 0x50, 0x49, 0x3c, 0x15, 0x04, 0x12,
-	0xa2, 0x77, 0xfc, 0x01, 0xe5, 0x31, 0x1f, 0xf9, 0x92, 0xec, 0xeb, 0xe0, 0x03, 0x1c, 0xe3, 0xc1,
-	0xad, 0x98, 0x7a, 0x67, 0xb9, 0x82, 0x80, 0xfe, 0x0d, 0x35, 0x9a, 0x71, 0xdc, 0xbb, 0x8b, 0x09,
-	0x56, 0x9a, 0x85, 0x04, 0x64, 0xc2, 0x26, 0x19, 0xdd, 0x0f, 0x27, 0xfe, 0x9e, 0xc7, 0x14, 0xd5,
-	0xe8, 0x7d, 0xca, 0x24, 0x07, 0x43, 0x23, 0xdd, 0x56, 0x40, 0xbf, 0x01, 0xf5, 0x07, 0x4f, 0x7d,
-	0x52, 0xb7, 0x22, 0xdb, 0x6c, 0x5a, 0xad, 0xca, 0x6d, 0xd3, 0x36, 0xdc, 0x94, 0x86, 0x5a, 0x50,
-	0xa3, 0xbf, 0x79, 0xc0, 0xcf, 0x49, 0x38, 0x28, 0xc3, 0x4d, 0xf8, 0xce, 0x7b, 0x30, 0x97, 0x3f,
-	0x49, 0x65, 0x60, 0x20, 0xa8, 0x6c, 0x07, 0x03, 0x9e, 0x68, 0xf4, 0x37, 0x72, 0xe0, 0x42, 0x07,
-	0x47, 0xf1, 0xd0, 0xf7, 0xd8, 0xfd, 0x10, 0x5b, 0x0d, 0x37, 0x43, 0x73, 0xae, 0x02, 0x08, 0xab,
-	0x68, 0x19, 0x6a, 0x49, 0x8d, 0x63, 0xbe, 0x24, 0x2b, 0xe7, 0x4d, 0x58, 0x50, 0xa4, 0x93, 0x12,
-	0xc8, 0x22, 0x54, 0xa9, 0x40, 0x82, 0x84, 0x2d, 0x9c, 0x13, 0xa8, 0xf3, 0x92, 0xaa, 0x83, 0xbf,
-	0xe5, 0x45, 0xfb, 0x69, 0x1d, 0xf2, 0xa2, 0x7d, 0xa2, 0xe9, 0xd6, 0x60, 0x34, 0x64, 0x61, 0x5c,
-	0x77, 0xd9, 0x02, 0xfd, 0x17, 0x60, 0x37, 0x1c, 0x1e, 0x0e, 0x0f, 0xf0, 0x93, 0x34, 0xe3, 0x17,
-	0x44, 0xd1, 0x4e, 0x79, 0xae, 0x24, 0xe6, 0x74, 0x61, 0x26, 0xc3, 0xa4, 0x79, 0x94, 0x14, 0xb2,
-	0x04, 0x47, 0xba, 0x26, 0x21, 0x94, 0x0a, 0x52, 0x40, 0x55, 0x57, 0x10, 0x9c, 0xe7, 0x35, 0x98,
-	0xda, 0x0c, 0x46, 0x23, 0xcf, 0x1f, 0xa0, 0x6b, 0x50, 0x89, 0x8f, 0xc7, 0x4c, 0xc3, 0x2c, 0xff,
-	0xd0, 0x24, 0xcc, 0x9b, 0xfd, 0xe3, 0x31, 0x76, 0x29, 0xdf, 0xf9, 0xab, 0x0a, 0x15, 0xb2, 0x44,
-	0x4b, 0x30, 0xbf, 0x19, 0x62, 0x2f, 0xc6, 0xe4, 0x5c, 0x13, 0xc1, 0x39, 0x83, 0x90, 0x59, 0x8c,
-	0xca, 0x64, 0x13, 0xad, 0xc2, 0x12, 0x93, 0xe6, 0xd0, 0x38, 0xcb, 0x42, 0x2b, 0xb0, 0xd0, 0x09,
-	0x83, 0x71, 0x9e, 0x51, 0x41, 0x6b, 0xb0, 0xc2, 0xf6, 0x88, 0x62, 0xc2, 0x99, 0x55, 0xa2, 0x90,
-	0xec, 0x2a, 0xb2, 0x6a, 0xe8, 0x0a, 0xac, 0xf5, 0x70, 0x5c, 0xa8, 0xcf, 0x5c, 0x60, 0x8a, 0x28,
-	0x7e, 0x38, 0x1e, 0x28, 0x15, 0xd7, 0x09, 0x1c, 0x66, 0x95, 0x65, 0x34, 0x67, 0x34, 0x28, 0x4e,
-	0xea, 0x59, 0x96, 0x01, 0xa8, 0x09, 0xeb, 0x6c, 0x47, 0x2e, 0xae, 0xb8, 0xc4, 0x34, 0xda, 0x80,
-	0x4b, 0x04, 0xac, 0x86, 0x7f, 0x41, 0x9c, 0x25, 0xb9, 0x59, 0x4e, 0x9e, 0x41, 0x0b, 0x70, 0x91,
-	0x6c, 0x93, 0x89, 0xb3, 0x44, 0x96, 0x81, 0x97, 0xc9, 0x17, 0x09, 0xba, 0x1e, 0x8e, 0xd3, 0xbb,
-	0xe5, 0x8c, 0x39, 0x84, 0x60, 0x96, 0x9c, 0x86, 0x17, 0x7b, 0x9c, 0x36, 0x8f, 0xd6, 0xc1, 0xee,
-	0xe1, 0x98, 0x06, 0x61, 0x61, 0x07, 0x12, 0x16, 0xe4, 0x2b, 0x5c, 0x40, 0x97, 0x61, 0x95, 0x81,
-	0x94, 0x93, 0x98, 0xb3, 0x97, 0xc8, 0xa1, 0x12, 0xb0, 0x2a, 0xe6, 0x32, 0x51, 0xe9, 0xe2, 0x51,
-	0x70, 0x88, 0x77, 0xb1, 0x00, 0xbd, 0x22, 0xa2, 0x82, 0x7f, 0xe1, 0x39, 0xcb, 0xce, 0x06, 0x8c,
-	0xcc, 0x5a, 0x25, 0x2c, 0x86, 0x2f, 0xcf, 0xba, 0x44, 0xa3, 0x82, 0xde, 0x51, 0x5e, 0xe1, 0x9a,
-	0x60, 0xe5, 0x77, 0xad, 0xa3, 0x65, 0x40, 0x3d, 0x1c, 0xe7, 0xb7, 0x5c, 0x46, 0x8b, 0x30, 0x47,
-	0x5d, 0x22, 0x45, 0x85, 0x53, 0x37, 0xae, 0xd7, 0xeb, 0x83, 0xb9, 0xd3, 0xd3, 0xd3, 0x53, 0xd3,
-	0x39, 0x51, 0xa4, 0x40, 0xda, 0x86, 0x18, 0x52, 0x1b, 0x82, 0xa0, 0xe2, 0x7a, 0xfe, 0x20, 0xe9,
-	0x11, 0xe9, 0xef, 0xf6, 0x5b, 0x30, 0xb5, 0x97, 0x6c, 0x99, 0xc9, 0x64, 0x9b, 0x8d, 0x9b, 0x46,
-	0x6b, 0xba, 0xbd, 0x92, 0x10, 0xf3, 0x06, 0x5c, 0xbe, 0xcd, 0x79, 0xa6, 0x48, 0xb5, 0x42, 0xf9,
-	0x5e, 0x84, 0xea, 0xdd, 0x20, 0xdc, 0x63, 0xd9, 0x5f, 0x77, 0xd9, 0xa2, 0xc4, 0xf8, 0x63, 0xd9,
-	0x78, 0x41, 0xbd, 0x30, 0xfe, 0xa3, 0xa1, 0xc9, 0x68, 0x65, 0x4d, 0xfc, 0x1f, 0x40, 0xa6, 0x83,
-	0x32, 0xb4, 0x9d, 0x91, 0x24, 0xd7, 0xee, 0x68, 0x51, 0x3e, 0xa1, 0x1a, 0xd6, 0xe4, 0x23, 0xca,
-	0xc1, 0x10, 0x48, 0x47, 0xca, 0xfa, 0xa2, 0x82, 0xd9, 0xbe, 0xad, 0x35, 0xb8, 0x4f, 0x0d, 0x26,
-	0xed, 0x98, 0x42, 0x9d, 0x30, 0xf7, 0xab, 0xa1, 0x2d, 0x5b, 0xa5, 0xa5, 0x3a, 0x7f, 0x44, 0xe6,
-	0x59, 0x8e, 0x88, 0x74, 0x0f, 0x49, 0xa1, 0x4b, 0x3e, 0x2d, 0x7c, 0xd9, 0xbe, 0xab, 0xf5, 0x65,
-	0x48, 0x7d, 0xb9, 0x2c, 0x1f, 0x5e, 0x01, 0xaa, 0xf0, 0xe7, 0x2b, 0x43, 0x53, 0x69, 0x4b, 0xbd,
-	0xe1, 0xa7, 0x6b, 0x4a, 0xa7, 0xab, 0xbf, 0xce, 0xf7, 0xe5, 0xeb, 0x54, 0x1a, 0x13, 0x78, 0xbe,
-	0x33, 0x4a, 0xcb, 0xfb, 0xb9, 0x51, 0xbd, 0xad, 0x45, 0xf5, 0x01, 0x45, 0xf5, 0xaf, 0xa4, 0x9f,
-	0xd1, 0x9b, 0x14, 0xd8, 0xfe, 0x34, 0xb4, 0x5f, 0x96, 0xf3, 0xe2, 0x22, 0x37, 0xbb, 0x83, 0x9f,
-	0x52, 0x72, 0x32, 0xba, 0x24, 0xcb, 0x4c, 0xe3, 0x5c, 0xc9, 0xf5, 0xef, 0x72, 0x43, 0x5c, 0xcd,
-	0xf6, 0xe5, 0x72, 0xac, 0xd4, 0xce, 0x1a, 0x2b, 0x07, 0x72, 0xac, 0x68, 0x5c, 0x13, 0xfe, 0xff,
-	0x62, 0x28, 0x3f, 0x9e, 0xa5, 0xbe, 0x6f, 0x14, 0xe2, 0xbe, 0x91, 0x89, 0xf0, 0x75, 0x68, 0x90,
-	0x55, 0x14, 0x7b, 0xa3, 0x71, 0xd2, 0x21, 0x0b, 0x42, 0x49, 0xc6, 0x8e, 0xe4, 0x8c, 0x55, 0x80,
-	0x12, 0xa8, 0x7f, 0x36, 0x94, 0x5f, 0xf6, 0x97, 0x42, 0x4d, 0xef, 0x21, 0x99, 0xa1, 0xd9, 0xfc,
-	0x9f, 0xae, 0x4b, 0x30, 0xfb, 0x99, 0x2a, 0x53, 0x84, 0x94, 0xc1, 0x5c, 0xda, 0x74, 0x9c, 0x3b,
-	0xdc, 0xd2, 0x5e, 0xd7, 0x92, 0x7a, 0xdd, 0xf6, 0x7d, 0x2d, 0xd4, 0x80, 0x42, 0x75, 0xe4, 0xe3,
-	0x55, 0x23, 0x11, 0x98, 0xbf, 0x35, 0xca, 0xda, 0xa0, 0x73, 0x27, 0x6e, 0x57, 0x8b, 0x6d, 0x4c,
-	0xb1, 0x35, 0x45, 0x39, 0x79, 0x11, 0xb2, 0xaf, 0x0d, 0x45, 0x03, 0xf6, 0x72, 0xcd, 0x7d, 0xc9,
-	0x27, 0xf6, 0xc3, 0xe2, 0xf7, 0x5d, 0x32, 0x2b, 0x50, 0xe1, 0x42, 0xfb, 0xa7, 0xfc, 0x68, 0xbd,
-	0xa1, 0x35, 0x14, 0x52, 0x43, 0x4b, 0xe2, 0x1c, 0x94, 0x66, 0x4e, 0x14, 0x0d, 0xe5, 0x59, 0x7d,
-	0x2f, 0xf1, 0x32, 0x92, 0xbd, 0x2c, 0x18, 0x10, 0xe6, 0x7f, 0x32, 0x94, 0x9d, 0x2b, 0x09, 0x07,
-	0x22, 0xef, 0x0b, 0x14, 0xe9, 0x3a, 0x13, 0x2a, 0x66, 0xd9, 0xc8, 0x63, 0xe5, 0x46, 0x9e, 0x92,
-	0xdc, 0x8b, 0xe5, 0xdc, 0x53, 0x00, 0x12, 0x88, 0x83, 0x7c, 0x47, 0x8d, 0x36, 0xd8, 0x03, 0x21,
-	0xc5, 0x39, 0xdd, 0x06, 0xf1, 0x4a, 0xe7, 0x52, 0x7a, 0xfb, 0x75, 0xad, 0xd5, 0x89, 0xdc, 0x0a,
-	0x65, 0xb5, 0x0a, 0x83, 0xdf, 0x18, 0xfa, 0x7e, 0xbd, 0xf4, 0x9c, 0xd2, 0xc8, 0x34, 0xe5, 0xc8,
-	0xbc, 0xa7, 0x45, 0x73, 0x48, 0xd1, 0x6c, 0xa4, 0x68, 0x94, 0x16, 0x05, 0xae, 0x63, 0xc5, 0xa0,
-	0x70, 0x96, 0x67, 0xb9, 0x92, 0xa8, 0x79, 0x5a, 0x8c, 0x1a, 0x65, 0xfb, 0xf9, 0xbb, 0x51, 0x32,
-	0x8d, 0x68, 0x9f, 0x9b, 0x74, 0x31, 0x93, 0xad, 0xe6, 0x56, 0xa1, 0x9a, 0xf3, 0x17, 0x89, 0x4a,
-	0xc9, 0x8b, 0x44, 0xb5, 0xf8, 0x22, 0xd1, 0xde, 0xd2, 0xfa, 0x79, 0x4c, 0xfd, 0xbc, 0x22, 0xd7,
-	0x00, 0x85, 0x23, 0x99, 0x7a, 0xaf, 0x1b, 0xaf, 0x5e, 0xb5, 0xb7, 0x25, 0xdd, 0xc0, 0x47, 0x72,
-	0x37, 0xa0, 0x81, 0x93, 0x09, 0x8f, 0xc2, 0xd0, 0x97, 0x86, 0x87, 0x21, 0xc2, 0xe3, 0xd6, 0x60,
-	0x10, 0xf2, 0xf0, 0x20, 0xbf, 0x4b, 0xc2, 0xe3, 0x99, 0x1c, 0x1e, 0x05, 0xe5, 0xaa, 0xe9, 0x24,
-	0x37, 0xd5, 0x91, 0x83, 0xd9, 0xea, 0xf7, 0x77, 0xa9, 0xcd, 0x24, 0x5d, 0xf8, 0x3a, 0x79, 0x2d,
-	0x96, 0xe0, 0xf0, 0x65, 0x3a, 0xc0, 0x59, 0xd2, 0x00, 0xa7, 0x6f, 0x67, 0x3f, 0x2e, 0x4e, 0x27,
-	0x39, 0x18, 0x99, 0x4f, 0x8f, 0x7a, 0xd0, 0xfd, 0x67, 0x48, 0x4b, 0x50, 0x9d, 0xa8, 0x67, 0x26,
-	0x25, 0xaa, 0xef, 0x0d, 0xcd, 0x8c, 0x7d, 0xfe, 0x57, 0x77, 0x53, 0x7a, 0x75, 0x2f, 0x41, 0xf7,
-	0x89, 0x8c, 0x4e, 0x69, 0x5a, 0x9e, 0xe8, 0xd4, 0x53, 0x7e, 0x1e, 0x5c, 0x89, 0xb9, 0x4f, 0x33,
-	0x13, 0x87, 0x4a, 0x99, 0x30, 0xe7, 0x6b, 0x5e, 0x0e, 0x0a, 0xe6, 0xee, 0x68, 0xcd, 0x9d, 0x1a,
-	0x45, 0x7b, 0x5a, 0xf7, 0xee, 0x92, 0xde, 0x31, 0x1a, 0x07, 0x7e, 0x84, 0x89, 0x89, 0x07, 0xf7,
-	0xa9, 0x89, 0xba, 0x6b, 0x3e, 0xb8, 0x4f, 0x2a, 0xfa, 0x9d, 0x30, 0x0c, 0x42, 0x3a, 0x43, 0x37,
-	0x5c, 0xb6, 0x10, 0x7f, 0x42, 0x59, 0x34, 0xaf, 0xd8, 0xc2, 0xf9, 0xc1, 0x50, 0xbd, 0x6b, 0xbc,
-	0xc2, 0x0c, 0xd0, 0x7f, 0x4c, 0x3f, 0x63, 0xfe, 0xda, 0xe9, 0x97, 0x44, 0x7b, 0xb8, 0x83, 0xe2,
-	0x1b, 0x4b, 0xe1, 0x5c, 0xf5, 0xf5, 0xe0, 0x73, 0x66, 0x67, 0x59, 0xaa, 0x48, 0x92, 0xa2, 0xd4,
-	0xca, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x06, 0x33, 0x5c, 0xeb, 0xd5, 0x1b, 0x00, 0x00,
+	// 2519 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x59, 0xdd, 0x6f, 0x1c, 0x49,
+	0x11, 0x57, 0xcf, 0x7e, 0x78, 0xb7, 0xfc, 0x11, 0xa7, 0x9d, 0x38, 0x93, 0xc4, 0x71, 0x36, 0x43,
+	0xc8, 0x59, 0xd1, 0x29, 0x02, 0xf3, 0xf1, 0xc4, 0x57, 0xe2, 0xcd, 0x87, 0xc9, 0x39, 0xf1, 0xcd,
+	0xfa, 0xc4, 0x23, 0x9a, 0x78, 0x3b, 0xc9, 0x1e, 0xbb, 0x33, 0xcb, 0xcc, 0x6c, 0x12, 0x13, 0x02,
+	0x06, 0xee, 0x38, 0x3e, 0xc4, 0x13, 0x42, 0x08, 0xee, 0x0d, 0x09, 0xf1, 0x76, 0x1c, 0xe2, 0x99,
+	0x37, 0x5e, 0xf9, 0x57, 0x10, 0x42, 0xbc, 0x9e, 0x84, 0xaa, 0x7b, 0x7a, 0xba, 0x67, 0xa6, 0x7b,
+	0x1c, 0x27, 0x79, 0x9b, 0xae, 0xaa, 0xee, 0xfa, 0x55, 0x75, 0x75, 0x75, 0x75, 0x0d, 0xc0, 0x84,
+	0xa5, 0xc1, 0xb5, 0x69, 0x1c, 0xa5, 0x11, 0x6d, 0xe2, 0xb7, 0xf7, 0x49, 0x03, 0x9a, 0xfd, 0x20,
+	0x0d, 0x28, 0x85, 0xe6, 0x1e, 0x8b, 0x27, 0x2e, 0xe9, 0x39, 0x1b, 0x4d, 0x9f, 0x7f, 0xd3, 0x53,
+	0xd0, 0xda, 0x0e, 0x87, 0xec, 0x99, 0xeb, 0x70, 0xa2, 0x18, 0xd0, 0x35, 0xe8, 0x6e, 0x8d, 0x67,
+	0x49, 0xca, 0xe2, 0xed, 0xbe, 0xdb, 0xe0, 0x1c, 0x45, 0xa0, 0x97, 0xa1, 0x75, 0x2f, 0x1a, 0xb2,
+	0xc4, 0x6d, 0xf6, 0x1a, 0x1b, 0xf3, 0x9b, 0x4b, 0xd7, 0xb8, 0x4a, 0x24, 0x6d, 0x87, 0x0f, 0x23,
+	0x5f, 0x30, 0xe9, 0x17, 0xa0, 0x8b, 0x5a, 0x1f, 0x04, 0x09, 0x4b, 0xdc, 0x16, 0x97, 0xa4, 0x42,
+	0x52, 0x92, 0xb9, 0xb4, 0x12, 0xc2, 0x75, 0xdf, 0x4b, 0x58, 0x9c, 0xb8, 0x6d, 0x7d, 0x5d, 0x24,
+	0x89, 0x75, 0x39, 0x13, 0xb1, 0xed, 0x04, 0xcf, 0xb8, 0xb6, 0xbe, 0x3b, 0x27, 0xb0, 0xe5, 0x04,
+	0xda, 0x83, 0xf9, 0x9d, 0xe0, 0x99, 0xcf, 0x1e, 0x8d, 0xa2, 0x70, 0xbb, 0xef, 0x76, 0x38, 0x5f,
+	0x27, 0xd1, 0x75, 0x80, 0x9d, 0xe0, 0xd9, 0xe0, 0x71, 0x10, 0x0f, 0xb7, 0xfb, 0x6e, 0x97, 0x0b,
+	0x68, 0x14, 0xfa, 0xb6, 0xc0, 0x2d, 0x2c, 0x04, 0xa3, 0x85, 0x4a, 0x00, 0xa5, 0x77, 0x98, 0x94,
+	0x9e, 0x37, 0x4b, 0xe7, 0x02, 0x68, 0xa1, 0x1f, 0x8d, 0x59, 0xe2, 0x2e, 0xe8, 0x92, 0x48, 0x12,
+	0x16, 0x72, 0xa6, 0x77, 0x07, 0x3a, 0x72, 0x32, 0x5d, 0x02, 0x67, 0xbb, 0x9f, 0xed, 0x98, 0xb3,
+	0xdd, 0xc7, 0x3d, 0xbc, 0x13, 0x25, 0x29, 0xdf, 0xae, 0xae, 0xcf, 0xbf, 0xa9, 0x0b, 0x73, 0x7b,
+	0x5b, 0xbb, 0x9c, 0xdc, 0xe8, 0x91, 0x8d, 0xae, 0x2f, 0x87, 0xde, 0xff, 0x08, 0x2c, 0xe8, 0xde,
+	0xc6, 0xe9, 0xf7, 0x82, 0x09, 0xe3, 0x0b, 0x76, 0x7d, 0xfe, 0x4d, 0xdf, 0x86, 0x93, 0x7d, 0xf6,
+	0x30, 0x98, 0x8d, 0xd3, 0xbd, 0xd1, 0x84, 0xed, 0x45, 0xef, 0x8c, 0x9e, 0xb0, 0x6c, 0xfd, 0x2a,
+	0x83, 0x7e, 0x15, 0xe6, 0xd5, 0x28, 0x71, 0x1b, 0xdc, 0x90, 0x53, 0xc2, 0x10, 0xc5, 0xe0, 0xe6,
+	0xe8, 0x82, 0xf4, 0x36, 0x9c, 0xdc, 0x8a, 0xc2, 0x74, 0x14, 0xce, 0xa2, 0x59, 0xf2, 0xee, 0x8c,
+	0xc5, 0xa3, 0x3c, 0x80, 0xce, 0x8a, 0xd9, 0x45, 0xf6, 0x01, 0x5f, 0xa2, 0x3a, 0x87, 0x5e, 0x82,
+	0xd6, 0xbb, 0xb3, 0x28, 0x0d, 0xdc, 0x56, 0x8f, 0x6c, 0xcc, 0x6f, 0xce, 0x8b, 0xc9, 0x9c, 0xe4,
+	0x0b, 0x8e, 0xf7, 0x3c, 0x13, 0xc1, 0x68, 0xf8, 0x4e, 0x3c, 0x4a, 0xd9, 0x6e, 0x34, 0x0a, 0xd3,
+	0xc4, 0x25, 0x3d, 0xb2, 0xd1, 0xf0, 0x75, 0x12, 0x46, 0x03, 0x1f, 0xde, 0x38, 0x48, 0x59, 0xe2,
+	0x3a, 0x5c, 0x40, 0xa3, 0xa0, 0x6f, 0x25, 0xd8, 0x06, 0x67, 0xca, 0x21, 0x5d, 0x85, 0xf6, 0x40,
+	0x5a, 0x81, 0x8c, 0x6c, 0xe4, 0x7d, 0x40, 0x60, 0x49, 0x19, 0x3e, 0x98, 0xb2, 0x7d, 0xcd, 0xeb,
+	0x24, 0xf7, 0xfa, 0x39, 0xe8, 0xf4, 0x67, 0x71, 0x90, 0x8e, 0xa2, 0x30, 0x53, 0x9b, 0x8f, 0xe9,
+	0x15, 0x58, 0x12, 0xe1, 0x9a, 0x4b, 0x08, 0xdd, 0x25, 0x2a, 0xae, 0xe1, 0xb3, 0xe9, 0x78, 0xb4,
+	0x1f, 0xdc, 0xe3, 0x20, 0x16, 0xfd, 0x7c, 0xec, 0xfd, 0xbb, 0x00, 0xc3, 0xba, 0xf9, 0x45, 0x18,
+	0xce, 0x91, 0x30, 0x9c, 0x23, 0x61, 0x38, 0x3a, 0x0c, 0x7a, 0x15, 0xe6, 0x84, 0xb4, 0xcc, 0x01,
+	0xcb, 0x59, 0xcc, 0x8b, 0xe3, 0x88, 0x7b, 0x2c, 0x05, 0xe8, 0xd7, 0x60, 0x71, 0x30, 0x7b, 0x90,
+	0xec, 0xc7, 0xa3, 0x69, 0xca, 0x67, 0x88, 0x3c, 0xb0, 0x2a, 0x66, 0xe8, 0x2c, 0x3e, 0xaf, 0x28,
+	0xec, 0xfd, 0x83, 0x00, 0xa8, 0x55, 0x2b, 0x07, 0x67, 0x0d, 0xba, 0x83, 0x34, 0x88, 0x79, 0x28,
+	0x67, 0x96, 0x2a, 0x02, 0x6e, 0xf3, 0xcd, 0x70, 0xc8, 0x79, 0xc2, 0x46, 0x39, 0xc4, 0x79, 0x7d,
+	0x36, 0x66, 0x29, 0x1b, 0x5e, 0x4f, 0xb9, 0x75, 0x0d, 0x5f, 0x11, 0xe8, 0x5b, 0xd0, 0xe6, 0x79,
+	0x43, 0x5a, 0x77, 0x22, 0xc3, 0xca, 0x73, 0x09, 0x82, 0xcc, 0xd8, 0x18, 0x89, 0x7b, 0xf1, 0x2c,
+	0xdc, 0x0f, 0xc4, 0x42, 0x6d, 0x11, 0x89, 0x1a, 0xc9, 0x63, 0xd0, 0xcd, 0xa7, 0x55, 0xd0, 0xaf,
+	0x43, 0xe7, 0xfe, 0xd3, 0x10, 0xb3, 0x2f, 0x06, 0x69, 0x63, 0xa3, 0x79, 0xc3, 0x71, 0x89, 0x9f,
+	0xd3, 0xe8, 0x06, 0xb4, 0xf9, 0xb7, 0x3c, 0x90, 0xcb, 0x1a, 0x0e, 0xce, 0xf0, 0x33, 0xbe, 0xf7,
+	0x0c, 0x96, 0xcb, 0x9e, 0x34, 0x06, 0x06, 0x85, 0xe6, 0x4e, 0x34, 0x94, 0x89, 0x80, 0x7f, 0x53,
+	0x0f, 0x16, 0xfa, 0x2c, 0x49, 0x47, 0x61, 0x20, 0xf6, 0x07, 0x75, 0x75, 0xfd, 0x02, 0x0d, 0x3d,
+	0x89, 0x81, 0xf1, 0x60, 0xcc, 0x78, 0x48, 0x76, 0x7c, 0x39, 0xf4, 0x2e, 0x03, 0x28, 0x3c, 0x78,
+	0x7c, 0xb2, 0x1c, 0x2e, 0xac, 0xcc, 0x46, 0xde, 0xc7, 0x04, 0x56, 0x0c, 0x99, 0xc0, 0x88, 0xf1,
+	0x14, 0x9e, 0x73, 0x16, 0x1f, 0x64, 0x20, 0xc5, 0x00, 0x11, 0xbc, 0x13, 0x24, 0xa9, 0x3f, 0x93,
+	0xc7, 0x46, 0x0e, 0x71, 0x2f, 0xf1, 0xf3, 0x66, 0x1c, 0x47, 0x31, 0x47, 0xd7, 0xf5, 0x15, 0x01,
+	0xad, 0xc3, 0x41, 0x1e, 0xec, 0x2d, 0x3e, 0xb9, 0x40, 0xf3, 0x3e, 0x23, 0xd0, 0x91, 0x17, 0x92,
+	0xcd, 0x6d, 0x77, 0x82, 0xe4, 0x71, 0x9e, 0x9f, 0x83, 0xe4, 0x31, 0xc2, 0xbc, 0x3e, 0x9c, 0x8c,
+	0xc4, 0xf1, 0xe9, 0xf8, 0x62, 0x40, 0xbf, 0x04, 0xb0, 0x1b, 0x8f, 0x9e, 0x8c, 0xc6, 0xec, 0x51,
+	0x9e, 0x09, 0x57, 0xd4, 0x95, 0x97, 0xf3, 0x7c, 0x4d, 0x8c, 0xde, 0x84, 0x65, 0x91, 0x66, 0xb4,
+	0xa9, 0x2d, 0x3d, 0x89, 0xe2, 0xd4, 0x92, 0x84, 0x5f, 0x99, 0x82, 0x88, 0xc4, 0x3d, 0xd4, 0xe6,
+	0x3b, 0x28, 0x06, 0x2a, 0xb3, 0xce, 0x59, 0x33, 0xeb, 0x36, 0x2c, 0x16, 0xc0, 0xf1, 0xfc, 0x91,
+	0x5d, 0x30, 0x99, 0x1f, 0xf2, 0x31, 0xba, 0x3b, 0x17, 0xe4, 0x0e, 0x69, 0xf9, 0x8a, 0xe0, 0x8d,
+	0xa0, 0x23, 0x2f, 0x3e, 0xa3, 0x27, 0x8b, 0xfe, 0x71, 0x5e, 0xce, 0x3f, 0xb9, 0x61, 0x0d, 0xcd,
+	0x30, 0xef, 0x43, 0x02, 0x2b, 0x06, 0xc7, 0xd4, 0x82, 0x5f, 0x85, 0xf6, 0x0e, 0x4b, 0xe3, 0xd1,
+	0x3e, 0xcf, 0xce, 0x5d, 0x3f, 0x1b, 0xf1, 0xd2, 0x28, 0x0a, 0x87, 0xa3, 0x3c, 0x2d, 0x77, 0x7d,
+	0x45, 0x28, 0x9a, 0xdc, 0x2c, 0x9b, 0xfc, 0xcf, 0xa6, 0xa8, 0x2d, 0xfa, 0x6c, 0x9c, 0x06, 0x42,
+	0x96, 0x3d, 0x11, 0xe5, 0x97, 0x38, 0x04, 0x8a, 0x90, 0x17, 0x6b, 0x8e, 0xa9, 0x58, 0x6b, 0x58,
+	0x8b, 0xb5, 0x66, 0xb9, 0x58, 0x2b, 0x94, 0x4b, 0xad, 0x23, 0xca, 0xa5, 0xf6, 0x51, 0xe5, 0xd2,
+	0x5c, 0xa5, 0x5c, 0xf2, 0x60, 0x01, 0xd7, 0x4a, 0xb6, 0x1e, 0x07, 0xe1, 0x23, 0x36, 0x74, 0x3b,
+	0xfc, 0xd0, 0x17, 0x68, 0xc5, 0x92, 0xaa, 0x7b, 0xac, 0x92, 0x0a, 0x8e, 0x2a, 0xa9, 0x3c, 0x58,
+	0xe0, 0x75, 0xa1, 0xd4, 0x3f, 0x2f, 0xf4, 0xeb, 0x34, 0x55, 0x58, 0x2e, 0xd4, 0x15, 0x96, 0x5f,
+	0xd4, 0x0b, 0xd6, 0x45, 0x3d, 0xde, 0x24, 0x99, 0xef, 0x9b, 0x5e, 0xb1, 0x5e, 0x85, 0xe5, 0x7e,
+	0x1c, 0x4d, 0xa7, 0x6c, 0xa8, 0x66, 0x2e, 0xf1, 0xc8, 0xab, 0xd0, 0x11, 0x28, 0x8f, 0x46, 0x09,
+	0xf4, 0x84, 0x00, 0xaa, 0xd3, 0x54, 0x7d, 0xb8, 0x5c, 0x57, 0x1f, 0x7e, 0x17, 0x16, 0x0b, 0x88,
+	0xe8, 0x15, 0x68, 0x22, 0x9f, 0x07, 0x91, 0xb9, 0xca, 0xe6, 0x7c, 0xbc, 0xd0, 0x33, 0x58, 0xf2,
+	0x4e, 0xe6, 0x77, 0x89, 0x5f, 0xa2, 0x7a, 0x5f, 0x01, 0xc8, 0xc3, 0x34, 0xc1, 0x3b, 0x4e, 0x7c,
+	0xb9, 0x44, 0xbf, 0xe3, 0x72, 0x09, 0x3f, 0x63, 0x7b, 0x9f, 0x74, 0x60, 0x6e, 0x2b, 0x9a, 0x4c,
+	0x82, 0x70, 0x88, 0x90, 0xd2, 0x83, 0xa9, 0x38, 0x56, 0x4b, 0x12, 0x52, 0xc6, 0xbc, 0xb6, 0x77,
+	0x30, 0x65, 0x3e, 0xe7, 0x7b, 0xff, 0x99, 0x83, 0x26, 0x0e, 0xe9, 0x69, 0x38, 0xb9, 0x15, 0xb3,
+	0x20, 0x65, 0xb8, 0xad, 0x99, 0xe0, 0x32, 0x41, 0xb2, 0xb8, 0x6d, 0x75, 0xb2, 0x43, 0xcf, 0xc2,
+	0x69, 0x21, 0x2d, 0xad, 0x94, 0xac, 0x06, 0x3d, 0x03, 0x2b, 0x68, 0x4e, 0x99, 0xd1, 0xa4, 0xe7,
+	0xe1, 0x8c, 0x98, 0xa3, 0xca, 0x22, 0xc9, 0x6c, 0xe1, 0x82, 0x38, 0xab, 0xca, 0x6a, 0xd3, 0x8b,
+	0x70, 0x7e, 0xc0, 0xd2, 0x4a, 0x25, 0x2c, 0x05, 0xe6, 0x70, 0xe1, 0xf7, 0xa6, 0x43, 0xe3, 0xc2,
+	0x1d, 0x84, 0x23, 0xb4, 0x0a, 0xe7, 0x4a, 0x46, 0x97, 0xe3, 0xe4, 0x96, 0x15, 0x19, 0x40, 0x7b,
+	0xb0, 0x26, 0x66, 0x94, 0xae, 0x41, 0x29, 0x31, 0x4f, 0xd7, 0xe1, 0x1c, 0x82, 0xb5, 0xf0, 0x17,
+	0x94, 0x2f, 0x31, 0xb0, 0x25, 0x79, 0x91, 0xae, 0xc0, 0x09, 0x9c, 0xa6, 0x13, 0x97, 0x50, 0x56,
+	0x80, 0xd7, 0xc9, 0x27, 0x10, 0xdd, 0x80, 0xa5, 0x79, 0xea, 0x92, 0x8c, 0x65, 0x4a, 0x61, 0x09,
+	0xbd, 0x11, 0xa4, 0x81, 0xa4, 0x9d, 0xa4, 0x6b, 0xe0, 0x0e, 0x58, 0xca, 0xaf, 0xb5, 0xca, 0x0c,
+	0xaa, 0x34, 0xe8, 0x5b, 0xb8, 0x42, 0x2f, 0xc0, 0x59, 0x01, 0x52, 0x2f, 0x47, 0x24, 0xfb, 0x34,
+	0x3a, 0x15, 0xc1, 0x9a, 0x98, 0xab, 0xb8, 0xa4, 0xcf, 0x26, 0xd1, 0x13, 0xb6, 0xcb, 0x14, 0xe8,
+	0x33, 0x2a, 0x2a, 0x64, 0x7a, 0x90, 0x2c, 0xb7, 0x18, 0x30, 0x3a, 0xeb, 0x2c, 0xb2, 0x04, 0xbe,
+	0x32, 0xeb, 0x1c, 0x8f, 0x0a, 0xbe, 0x47, 0xe5, 0x05, 0xcf, 0x2b, 0x56, 0x79, 0xd6, 0x1a, 0x5d,
+	0x05, 0x3a, 0x60, 0x69, 0x79, 0xca, 0x05, 0x7a, 0x4a, 0x64, 0x0b, 0x9e, 0x39, 0x25, 0x75, 0x1d,
+	0x9d, 0x27, 0xd4, 0xab, 0xe2, 0x28, 0x91, 0xdc, 0x8b, 0xf4, 0x32, 0xf4, 0x06, 0x2c, 0x2d, 0xed,
+	0x74, 0x56, 0xcf, 0x48, 0xa9, 0x1e, 0xfa, 0x72, 0xc0, 0xd2, 0xd2, 0xf5, 0x26, 0xd9, 0x97, 0x54,
+	0x3c, 0x60, 0xfe, 0x90, 0x64, 0x4f, 0xc6, 0x83, 0x4e, 0xfc, 0x1c, 0xfa, 0x7d, 0xc0, 0x52, 0xa4,
+	0x55, 0x16, 0xba, 0x9c, 0x59, 0x86, 0x91, 0xa2, 0x4f, 0xfa, 0x7c, 0x46, 0x47, 0x9a, 0x4e, 0xbf,
+	0x82, 0x1a, 0x06, 0x2c, 0xe5, 0xa5, 0x83, 0x24, 0xbe, 0x75, 0xb5, 0xd3, 0x19, 0x2e, 0x1f, 0x1e,
+	0x1e, 0x1e, 0x3a, 0xde, 0x0b, 0xc3, 0x99, 0xcf, 0x5f, 0xb8, 0x44, 0x7b, 0xe1, 0x52, 0x68, 0xfa,
+	0x41, 0x38, 0x94, 0x97, 0x21, 0x7e, 0x6f, 0x7e, 0x0b, 0xe6, 0xf6, 0xb3, 0x29, 0x8b, 0x85, 0xf4,
+	0xe2, 0x32, 0x5e, 0xbe, 0x9c, 0xc9, 0x88, 0x65, 0x05, 0xbe, 0x9c, 0xe6, 0x3d, 0x37, 0xe4, 0x96,
+	0x4a, 0xe5, 0x7d, 0x0a, 0x5a, 0xb7, 0xa2, 0x78, 0x5f, 0x14, 0x30, 0x1d, 0x5f, 0x0c, 0x6a, 0x94,
+	0x3f, 0xd4, 0x95, 0x57, 0x96, 0x57, 0xca, 0xff, 0x42, 0x2c, 0x29, 0xcc, 0x58, 0x0c, 0x7d, 0x19,
+	0xa0, 0xf0, 0x38, 0x27, 0xd6, 0x47, 0xb7, 0x26, 0xb7, 0xd9, 0xb7, 0xa2, 0x7c, 0xc4, 0x57, 0x38,
+	0xaf, 0xbb, 0xa8, 0x04, 0x43, 0x21, 0x9d, 0x18, 0x13, 0xaa, 0x09, 0xe6, 0xe6, 0x0d, 0xab, 0xc2,
+	0xc7, 0x3d, 0xa2, 0x8a, 0x54, 0xc3, 0x72, 0x4a, 0xdd, 0xbf, 0x88, 0x35, 0x4f, 0xd7, 0x16, 0x6c,
+	0x65, 0x17, 0x39, 0x2f, 0xe3, 0x22, 0xfe, 0x5c, 0x11, 0x99, 0x3d, 0xab, 0xce, 0xe5, 0x70, 0xf3,
+	0x96, 0xd5, 0x96, 0x11, 0xb7, 0xe5, 0x82, 0xee, 0xbc, 0x0a, 0x54, 0x65, 0xcf, 0x6f, 0x88, 0xe5,
+	0x6a, 0xa9, 0xb5, 0x46, 0x7a, 0xd7, 0xd1, 0xbc, 0x6b, 0xdf, 0xce, 0xf7, 0xf5, 0xed, 0x34, 0x2a,
+	0x53, 0x78, 0xfe, 0x40, 0x6a, 0xef, 0xb3, 0x63, 0xa3, 0xfa, 0xb6, 0x15, 0xd5, 0xf7, 0x38, 0xaa,
+	0x4b, 0x82, 0x58, 0xa3, 0x52, 0x61, 0xfb, 0x8c, 0x58, 0xaf, 0xd2, 0xe3, 0xe2, 0xc2, 0x9d, 0xbd,
+	0xc7, 0x9e, 0x72, 0x72, 0xd6, 0x15, 0xcb, 0x86, 0x85, 0x9e, 0x47, 0xb3, 0xd4, 0x7a, 0xd1, 0x7b,
+	0x19, 0xad, 0x62, 0x4b, 0x45, 0x8f, 0x95, 0xf6, 0xcb, 0xc6, 0xca, 0x58, 0x8f, 0x15, 0x8b, 0x69,
+	0xca, 0xfe, 0xbf, 0x13, 0x63, 0xb5, 0x50, 0x6b, 0xfb, 0x7a, 0x25, 0xee, 0xbb, 0x85, 0x08, 0x5f,
+	0x83, 0x2e, 0x8e, 0x92, 0x34, 0x98, 0x4c, 0xb3, 0xe6, 0x86, 0x22, 0xd4, 0x9c, 0xd8, 0x89, 0x7e,
+	0x62, 0x0d, 0xa0, 0x14, 0xea, 0xbf, 0x11, 0x63, 0x29, 0xf3, 0x5a, 0xa8, 0xf9, 0x3e, 0x64, 0xaf,
+	0x12, 0xf1, 0xda, 0xc9, 0xc7, 0x35, 0x98, 0xc3, 0x42, 0x96, 0xa9, 0x42, 0x2a, 0x60, 0xae, 0xad,
+	0xb2, 0x8e, 0x1d, 0x6e, 0x79, 0x2f, 0xa2, 0xa1, 0xf5, 0x22, 0x36, 0xef, 0x5a, 0xa1, 0x46, 0x1c,
+	0xaa, 0xa7, 0xbb, 0xd7, 0x8c, 0x44, 0x61, 0xfe, 0x3d, 0xa9, 0xab, 0xfb, 0x8e, 0x7d, 0x70, 0xb7,
+	0xad, 0xd8, 0xa6, 0x1c, 0x5b, 0x4f, 0xa5, 0x93, 0xa3, 0x90, 0xfd, 0x96, 0x18, 0x2a, 0xce, 0xd7,
+	0xeb, 0x8f, 0xd4, 0x5c, 0xb1, 0xdf, 0xaf, 0xde, 0xef, 0x9a, 0x5a, 0x85, 0x8a, 0x55, 0xea, 0x5d,
+	0xe3, 0xa5, 0xf5, 0x0d, 0xab, 0xa2, 0x98, 0x2b, 0x3a, 0xad, 0xfc, 0x60, 0x54, 0xf3, 0xc2, 0x50,
+	0x41, 0xbf, 0xac, 0xed, 0x35, 0x56, 0x26, 0xba, 0x95, 0x15, 0x05, 0x4a, 0xfd, 0x5f, 0x89, 0xb1,
+	0x54, 0xc7, 0x70, 0x40, 0xf9, 0x50, 0xa1, 0xc8, 0xc7, 0x85, 0x50, 0x71, 0xea, 0xba, 0x36, 0x8d,
+	0x52, 0x0b, 0xa3, 0xe6, 0xec, 0xa5, 0xfa, 0xd9, 0x33, 0x00, 0x52, 0x88, 0xa3, 0xf2, 0x13, 0x82,
+	0xae, 0x8b, 0x3f, 0x54, 0xd9, 0x03, 0x16, 0xd4, 0x03, 0xd3, 0xe7, 0xf4, 0xcd, 0xaf, 0x5b, 0xb5,
+	0xce, 0xf4, 0x52, 0xa8, 0xb8, 0xaa, 0x52, 0xf8, 0x3b, 0x62, 0x7f, 0xa0, 0xd4, 0xfa, 0x29, 0x8f,
+	0x4c, 0x47, 0x8f, 0xcc, 0xdb, 0x56, 0x34, 0x4f, 0x38, 0x9a, 0xf5, 0x1c, 0x8d, 0x51, 0xa3, 0xc2,
+	0x75, 0x60, 0x78, 0x19, 0xbd, 0xcc, 0x1f, 0x9f, 0x9a, 0xa8, 0x79, 0x5a, 0x8d, 0x1a, 0x63, 0xf9,
+	0xf9, 0x91, 0x53, 0xf3, 0xfc, 0xb2, 0xfe, 0x29, 0xb0, 0xc5, 0x4c, 0x31, 0x9b, 0x37, 0x2a, 0xd9,
+	0x5c, 0x36, 0x93, 0x9b, 0x35, 0xcd, 0xe4, 0x56, 0x7d, 0x33, 0xb9, 0x5d, 0x68, 0x26, 0x6f, 0xde,
+	0xb1, 0x7a, 0xe0, 0x80, 0x7b, 0xe0, 0xa2, 0x9e, 0x1d, 0x0c, 0x26, 0x16, 0x6e, 0x02, 0xdb, 0x4b,
+	0xf3, 0x4d, 0xfb, 0xa1, 0xa6, 0x4e, 0xf8, 0x81, 0x5e, 0x27, 0x58, 0xe0, 0x14, 0x02, 0xa7, 0xf2,
+	0xfe, 0xcd, 0x03, 0x87, 0xa8, 0xc0, 0xb9, 0x3e, 0x1c, 0xc6, 0x32, 0x70, 0xf0, 0xbb, 0x26, 0x70,
+	0x9e, 0xeb, 0x81, 0x53, 0x59, 0xdc, 0xf4, 0x6e, 0x29, 0x3d, 0x70, 0xd1, 0x31, 0x77, 0xf6, 0xf6,
+	0x76, 0xb9, 0xce, 0xec, 0x20, 0xc9, 0x71, 0xf6, 0x8b, 0x52, 0x83, 0x23, 0x87, 0xf9, 0xd3, 0xae,
+	0xa1, 0x3d, 0xed, 0xec, 0x85, 0xee, 0x0f, 0xab, 0xef, 0x96, 0x12, 0x8c, 0xc2, 0xa5, 0x64, 0x7e,
+	0xf3, 0xbf, 0x1a, 0xd2, 0x1a, 0x54, 0x2f, 0xcc, 0xaf, 0x29, 0x23, 0xaa, 0x8f, 0x89, 0xa5, 0xdd,
+	0x70, 0xfc, 0x5f, 0xbd, 0x8e, 0xf6, 0xab, 0xb7, 0x06, 0xdd, 0x8f, 0x74, 0x74, 0x46, 0xd5, 0xfa,
+	0x5b, 0xcf, 0xdc, 0xf0, 0x28, 0x83, 0xab, 0x51, 0xf7, 0xe3, 0xc2, 0x5b, 0xc4, 0xb4, 0x98, 0x52,
+	0x17, 0x5a, 0x9a, 0x28, 0x15, 0x75, 0x37, 0xad, 0xea, 0x0e, 0x49, 0x55, 0x9f, 0xd5, 0xbc, 0x5b,
+	0x58, 0x55, 0x26, 0xd3, 0x28, 0x4c, 0x18, 0xaa, 0xb8, 0x7f, 0x97, 0xab, 0xe8, 0xf8, 0xce, 0xfd,
+	0xbb, 0x98, 0xeb, 0xc5, 0x8f, 0x21, 0xd1, 0xef, 0x17, 0x03, 0xbd, 0xe5, 0x4e, 0xf2, 0x96, 0xbb,
+	0xf7, 0x27, 0x62, 0x6a, 0xf1, 0xbc, 0xc1, 0x13, 0x60, 0xbf, 0x66, 0x7f, 0x22, 0xec, 0x75, 0xf3,
+	0x3b, 0xc6, 0xea, 0xdc, 0x61, 0xb5, 0xdd, 0x54, 0xf1, 0xab, 0x3d, 0x1f, 0xfc, 0x54, 0xe8, 0x59,
+	0xd5, 0x32, 0x92, 0xb6, 0x90, 0xd2, 0xf2, 0x29, 0xb1, 0xf7, 0xaf, 0x4c, 0x3f, 0x61, 0xaf, 0x0f,
+	0x33, 0x99, 0xac, 0xf7, 0xac, 0x08, 0xd9, 0xaf, 0x56, 0xed, 0x3f, 0x66, 0xd3, 0x57, 0x84, 0x9a,
+	0x8c, 0xff, 0x33, 0xa2, 0x5f, 0xbb, 0x36, 0x30, 0x0a, 0xf2, 0xa1, 0x73, 0x74, 0x53, 0xed, 0x55,
+	0x9e, 0x9b, 0xea, 0xaf, 0xa3, 0xf3, 0x46, 0xff, 0x3a, 0x6e, 0xee, 0x5a, 0x4d, 0xff, 0x40, 0x98,
+	0x7e, 0x25, 0x8f, 0x86, 0x5a, 0xa3, 0x94, 0x0b, 0xfe, 0x4b, 0x6a, 0x3a, 0x86, 0xaf, 0x5c, 0x3a,
+	0xaa, 0x7f, 0x66, 0x0d, 0xfb, 0x3f, 0xb3, 0x66, 0xed, 0x3f, 0xb3, 0x56, 0xb9, 0xe0, 0xb4, 0xbf,
+	0x52, 0x3e, 0x24, 0xfa, 0x4d, 0x6f, 0xb5, 0x46, 0x19, 0xfd, 0xbe, 0xa1, 0x0d, 0x6a, 0x7c, 0x11,
+	0x5c, 0xb7, 0xea, 0xfc, 0x39, 0xa9, 0xbe, 0x3d, 0xb4, 0xd5, 0x94, 0xae, 0x87, 0x95, 0xde, 0xaa,
+	0x51, 0xd3, 0x37, 0xad, 0x9a, 0x3e, 0x22, 0xe5, 0xc7, 0x87, 0x51, 0xcf, 0xa7, 0xc4, 0xda, 0xaf,
+	0xe5, 0x89, 0x25, 0x1a, 0xe7, 0x0a, 0xf1, 0xfb, 0x35, 0x2a, 0x7f, 0x7b, 0xd5, 0xfb, 0x0b, 0xa2,
+	0x17, 0x2f, 0x16, 0x34, 0x0a, 0x72, 0x96, 0x3c, 0x4b, 0x5d, 0xe4, 0xda, 0xa0, 0x93, 0x96, 0x38,
+	0x9a, 0x25, 0x2e, 0xcc, 0xdd, 0x8e, 0x83, 0x30, 0x65, 0x43, 0xd9, 0xb9, 0xcb, 0x86, 0x35, 0xc9,
+	0xf3, 0x97, 0xe5, 0xe4, 0x59, 0x02, 0xa1, 0x40, 0xfe, 0x91, 0x98, 0x5a, 0xda, 0xb6, 0x67, 0xdd,
+	0x1b, 0x01, 0xf7, 0xab, 0x32, 0xb8, 0x92, 0x72, 0x05, 0xee, 0xcf, 0xa4, 0xd2, 0x57, 0x2f, 0xe5,
+	0x2b, 0x52, 0xce, 0x57, 0x68, 0x68, 0x76, 0xb3, 0xf1, 0x6f, 0xf5, 0xb3, 0xbf, 0xd1, 0x73, 0xcc,
+	0x3f, 0xfb, 0x6b, 0x82, 0xf3, 0xd7, 0x85, 0xe0, 0x2c, 0x41, 0xc9, 0x71, 0xfe, 0x7f, 0x00, 0x45,
+	0xb0, 0xc1, 0xfa, 0x8c, 0x28, 0x00, 0x00,
 }
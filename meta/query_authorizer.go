@@ -98,6 +98,11 @@ func (a *QueryAuthorizer) AuthorizeQuery(u User, q *cnosql.Query, database strin
 				}
 			}
 		}
+
+		// Limit the query to the series the user is granted.
+		if !user.IsOpen() {
+			return user, nil
+		}
 		return query.OpenAuthorizer, nil
 	default:
 	}
@@ -143,6 +148,9 @@ type ErrAuthorize struct {
 	Message  string
 }
 
+// AuthorizationFailed indicates that the error is due to an authorization failure.
+func (e ErrAuthorize) AuthorizationFailed() bool { return true }
+
 // Error returns the text of the error.
 func (e ErrAuthorize) Error() string {
 	if e.User == "" {
@@ -1,7 +1,10 @@
 package meta
 
 import (
+	"crypto/tls"
+	"encoding/binary"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
@@ -71,7 +74,12 @@ func (r *raftState) open(s *store, ln net.Listener) error {
 	config.ShutdownOnRemove = false
 
 	// Build raft layer to multiplex listener.
-	r.raftLayer = newRaftLayer(r.addr, r.ln)
+	layer, err := newRaftLayer(r.addr, r.ln, r.config)
+	if err != nil {
+		return err
+	}
+	layer.logger = r.logger
+	r.raftLayer = layer
 
 	// Create a transport layer
 	r.transport = raft.NewNetworkTransport(r.raftLayer, 3, 10*time.Second, config.LogOutput)
@@ -293,11 +301,23 @@ func (r *raftState) isLeader() bool {
 }
 
 // raftLayer wraps the connection so it can be re-used for forwarding.
+//
+// The raft connections are served over TLS with the certificates of the
+// HTTPS API if it is enabled, and the dialing meta node presents its own
+// certificate as the client certificate. If authentication is enabled, the
+// dialing meta node then sends a token signed with the internal shared
+// secret, and the connections without a valid token are closed.
 type raftLayer struct {
 	addr   *raftLayerAddr
 	ln     net.Listener
 	conn   chan net.Conn
 	closed chan struct{}
+
+	serverTLS    *tls.Config
+	clientTLS    *tls.Config
+	sharedSecret string
+
+	logger hclog.Logger
 }
 
 type raftLayerAddr struct {
@@ -312,14 +332,30 @@ func (r *raftLayerAddr) String() string {
 	return r.addr
 }
 
-// newRaftLayer returns a new instance of raftLayer.
-func newRaftLayer(addr string, ln net.Listener) *raftLayer {
-	return &raftLayer{
+// newRaftLayer returns a new instance of raftLayer, securing the raft
+// connections as the HTTP API of c.
+func newRaftLayer(addr string, ln net.Listener, c *ServerConfig) (*raftLayer, error) {
+	l := &raftLayer{
 		addr:   &raftLayerAddr{addr},
 		ln:     ln,
 		conn:   make(chan net.Conn),
 		closed: make(chan struct{}),
+		logger: hclog.NewNullLogger(),
+	}
+
+	if c.HTTPSEnabled {
+		var err error
+		if l.serverTLS, err = c.serverTLSConfig(); err != nil {
+			return nil, err
+		}
+		if l.clientTLS, err = c.clientTLSConfig(); err != nil {
+			return nil, err
+		}
+	}
+	if c.AuthEnabled {
+		l.sharedSecret = c.InternalSharedSecret
 	}
+	return l, nil
 }
 
 // Addr returns the local address for the layer.
@@ -330,12 +366,95 @@ func (l *raftLayer) Addr() net.Addr {
 // Dial creates a new network connection.
 func (l *raftLayer) Dial(addr raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
 	//return net.DialTimeout("tcp", string(addr), timeout)
-	return network.DialTimeout("tcp", string(addr), RaftMuxHeader, timeout)
+	conn, err := network.DialTimeout("tcp", string(addr), RaftMuxHeader, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if conn, err = l.clientHandshake(conn, string(addr)); err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, nil
 }
 
-// Accept waits for the next connection.
+// clientHandshake secures a connection dialed to a meta node.
+func (l *raftLayer) clientHandshake(conn net.Conn, addr string) (net.Conn, error) {
+	if l.clientTLS != nil {
+		config := l.clientTLS.Clone()
+		if host, _, err := net.SplitHostPort(addr); err == nil && config.ServerName == "" {
+			config.ServerName = host
+		}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("raft tls handshake: %s", err)
+		}
+		conn = tlsConn
+	}
+
+	if l.sharedSecret != "" {
+		token, err := NewToken(l.sharedSecret, tokenDuration)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		b := make([]byte, 2, 2+len(token))
+		binary.BigEndian.PutUint16(b, uint16(len(token)))
+		if _, err := conn.Write(append(b, token...)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("write raft token: %s", err)
+		}
+	}
+	return conn, nil
+}
+
+// Accept waits for the next connection. The connections failing the TLS
+// handshake or carrying no valid token are closed.
 func (l *raftLayer) Accept() (net.Conn, error) {
-	return l.ln.Accept()
+	for {
+		conn, err := l.ln.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		conn.SetDeadline(time.Now().Add(raftTransportTimeout))
+		secured, err := l.serverHandshake(conn)
+		if err != nil {
+			l.logger.Warn("rejected raft connection", "remote", conn.RemoteAddr().String(), "error", err)
+			conn.Close()
+			continue
+		}
+		secured.SetDeadline(time.Time{})
+		return secured, nil
+	}
+}
+
+// serverHandshake secures a connection accepted from a meta node.
+func (l *raftLayer) serverHandshake(conn net.Conn) (net.Conn, error) {
+	if l.serverTLS != nil {
+		tlsConn := tls.Server(conn, l.serverTLS)
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, fmt.Errorf("tls handshake: %s", err)
+		}
+		conn = tlsConn
+	}
+
+	if l.sharedSecret != "" {
+		var n [2]byte
+		if _, err := io.ReadFull(conn, n[:]); err != nil {
+			return nil, fmt.Errorf("read token: %s", err)
+		}
+		token := make([]byte, binary.BigEndian.Uint16(n[:]))
+		if _, err := io.ReadFull(conn, token); err != nil {
+			return nil, fmt.Errorf("read token: %s", err)
+		}
+		if err := validateToken(string(token), l.sharedSecret); err != nil {
+			return nil, err
+		}
+	}
+	return conn, nil
 }
 
 // Close closes the layer.
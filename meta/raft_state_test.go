@@ -0,0 +1,157 @@
+package meta
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/pkg/network"
+	"github.com/hashicorp/raft"
+	"github.com/soheilhy/cmux"
+)
+
+// listenRaft returns a raft layer serving the raft connections to a local
+// address as configured by c, and the connections it accepts.
+func listenRaft(t *testing.T, c *ServerConfig) (*raftLayer, <-chan net.Conn) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := cmux.New(ln)
+	raftLn := network.ListenString(mux, RaftMuxHeader)
+	go mux.Serve()
+	t.Cleanup(func() { ln.Close() })
+
+	l, err := newRaftLayer(ln.Addr().String(), raftLn, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accepted := make(chan net.Conn)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+	return l, accepted
+}
+
+// dialRaft dials the raft layer l as configured by c, and checks whether
+// the connection is accepted by sending a message over it.
+func dialRaft(t *testing.T, l *raftLayer, accepted <-chan net.Conn, c *ServerConfig) bool {
+	t.Helper()
+	dialer, err := newRaftLayer("", nil, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := dialer.Dial(raft.ServerAddress(l.Addr().String()), time.Second)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		return false
+	}
+
+	select {
+	case conn := <-accepted:
+		defer conn.Close()
+		b := make([]byte, 4)
+		if _, err := io.ReadFull(conn, b); err != nil || string(b) != "ping" {
+			t.Fatalf("got message %q (%v), exp ping", b, err)
+		}
+		return true
+	case <-time.After(500 * time.Millisecond):
+		return false
+	}
+}
+
+// writeCertificate writes a self-signed certificate of 127.0.0.1 and its key
+// to dir, and returns the path of the file holding both.
+func writeCertificate(t *testing.T, dir string) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "meta"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "meta.pem")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	pem.Encode(f, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return path
+}
+
+func TestRaftLayer_Auth(t *testing.T) {
+	c := &ServerConfig{AuthEnabled: true, InternalSharedSecret: "secret"}
+	l, accepted := listenRaft(t, c)
+
+	if !dialRaft(t, l, accepted, c) {
+		t.Fatal("expected the connection with a valid token to be accepted")
+	}
+	if dialRaft(t, l, accepted, &ServerConfig{AuthEnabled: true, InternalSharedSecret: "other"}) {
+		t.Fatal("expected the connection with an invalid token to be rejected")
+	}
+	if dialRaft(t, l, accepted, &ServerConfig{}) {
+		t.Fatal("expected the connection without a token to be rejected")
+	}
+}
+
+func TestRaftLayer_TLS(t *testing.T) {
+	cert := writeCertificate(t, t.TempDir())
+	c := &ServerConfig{HTTPSEnabled: true, HTTPSCertificate: cert, HTTPSClientCA: cert}
+	l, accepted := listenRaft(t, c)
+
+	if !dialRaft(t, l, accepted, c) {
+		t.Fatal("expected the connection with a client certificate to be accepted")
+	}
+	if dialRaft(t, l, accepted, &ServerConfig{}) {
+		t.Fatal("expected the plaintext connection to be rejected")
+	}
+
+	// The connections without a client certificate are rejected.
+	dir := t.TempDir()
+	client := &ServerConfig{HTTPSEnabled: true, HTTPSCertificate: writeCertificate(t, dir), HTTPSInsecureTLS: true}
+	if dialRaft(t, l, accepted, client) {
+		t.Fatal("expected the connection with an unknown client certificate to be rejected")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"math"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"sort"
 	"sync"
 	"time"
@@ -37,11 +39,21 @@ const (
 
 var _ MetaClient = &RemoteClient{}
 
+// errSnapshotRequired is returned when a meta server no longer has the changes
+// since the index of the cache, or doesn't serve changes.
+var errSnapshotRequired = errors.New("snapshot required")
+
 type RemoteClient struct {
 	tls    bool
 	logger *zap.Logger
 	nodeID uint64
 
+	// The credentials sent to the meta servers.
+	tlsConfig    *tls.Config
+	sharedSecret string
+	token        string
+	httpClient   *http.Client
+
 	mu          sync.RWMutex
 	metaServers []string
 	changed     chan struct{}
@@ -55,9 +67,10 @@ type RemoteClient struct {
 // NewRemoteClient returns a new *Remote
 func NewRemoteClient() *RemoteClient {
 	return &RemoteClient{
-		cacheData: &Data{},
-		logger:    zap.NewNop(),
-		authCache: make(map[string]authUser, 0),
+		cacheData:  &Data{},
+		logger:     zap.NewNop(),
+		authCache:  make(map[string]authUser, 0),
+		httpClient: NewHTTPClient(nil, "", ""),
 	}
 }
 
@@ -77,9 +90,7 @@ func (c *RemoteClient) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if t, ok := http.DefaultTransport.(*http.Transport); ok {
-		t.CloseIdleConnections()
-	}
+	c.httpClient.CloseIdleConnections()
 
 	select {
 	case <-c.closing:
@@ -115,7 +126,7 @@ func (c *RemoteClient) Ping(checkAllMetaServers bool) error {
 		url = url + "?all=true"
 	}
 
-	resp, err := http.Get(url)
+	resp, err := c.httpClient.Get(url)
 	if err != nil {
 		return err
 	}
@@ -153,9 +164,9 @@ func (c *RemoteClient) acquireLease(name string) (*Lease, error) {
 	c.mu.RLock()
 	server := c.metaServers[0]
 	c.mu.RUnlock()
-	url := fmt.Sprintf("%s/lease?name=%s&nodeid=%d", c.url(server), name, c.nodeID)
+	u := fmt.Sprintf("%s/lease?name=%s&nodeid=%d", c.url(server), url.QueryEscape(name), c.nodeID)
 
-	resp, err := http.Get(url)
+	resp, err := c.httpClient.Get(u)
 	if err != nil {
 		return nil, err
 	}
@@ -204,6 +215,29 @@ func (c *RemoteClient) SetMetaServers(a []string) {
 // This function is not safe for concurrent use.
 func (c *RemoteClient) SetTLS(v bool) { c.tls = v }
 
+// SetTLSConfig sets the TLS config of the connections, which holds the client
+// certificate for meta servers requiring mutual TLS. It also enables TLS.
+// This function is not safe for concurrent use.
+func (c *RemoteClient) SetTLSConfig(config *tls.Config) {
+	c.tls = true
+	c.tlsConfig = config
+	c.httpClient = NewHTTPClient(c.tlsConfig, c.sharedSecret, c.token)
+}
+
+// SetSharedSecret sets the internal shared secret of the meta servers, which
+// signs the tokens the client sends. This function is not safe for concurrent use.
+func (c *RemoteClient) SetSharedSecret(secret string) {
+	c.sharedSecret = secret
+	c.httpClient = NewHTTPClient(c.tlsConfig, c.sharedSecret, c.token)
+}
+
+// SetToken sets the token the client sends to the meta servers, instead of
+// tokens signed with the shared secret. This function is not safe for concurrent use.
+func (c *RemoteClient) SetToken(token string) {
+	c.token = token
+	c.httpClient = NewHTTPClient(c.tlsConfig, c.sharedSecret, c.token)
+}
+
 // joinMetaServer will add the passed in tcpAddr to the raft peers and add a MetaNode to
 // the metastore
 func (c *RemoteClient) joinMetaServer(httpAddr, tcpAddr string) (*NodeInfo, error) {
@@ -239,7 +273,7 @@ func (c *RemoteClient) joinMetaServer(httpAddr, tcpAddr string) (*NodeInfo, erro
 			url = c.url(server) + "/add-meta"
 		}
 
-		resp, err := http.Post(url, "application/json", bytes.NewBuffer(b))
+		resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(b))
 		if err != nil {
 			//  TODO print error
 			currentServer++
@@ -587,6 +621,14 @@ func (c *RemoteClient) User(name string) (User, error) {
 	return nil, ErrUserNotFound
 }
 
+func (c *RemoteClient) UserWithRoles(name string, roles []string) (User, error) {
+	ui, err := c.data().UserWithRoles(name, roles)
+	if err != nil {
+		return nil, err
+	}
+	return ui, nil
+}
+
 // hashWithSalt returns a salted hash of password using salt
 func (c *RemoteClient) hashWithSalt(salt []byte, password string) []byte {
 	hasher := sha256.New()
@@ -692,6 +734,103 @@ func (c *RemoteClient) UserPrivilege(username, database string) (*cnosql.Privile
 	return p, nil
 }
 
+func (c *RemoteClient) SetSeriesPrivilege(username, database, metric, condition string, p cnosql.Privilege) error {
+	return c.retryUntilExec(internal.Command_SetSeriesPrivilegeCommand, internal.E_SetSeriesPrivilegeCommand_Command,
+		&internal.SetSeriesPrivilegeCommand{
+			Username:  proto.String(username),
+			Database:  proto.String(database),
+			Metric:    proto.String(metric),
+			Condition: proto.String(condition),
+			Privilege: proto.Int32(int32(p)),
+		},
+	)
+}
+
+func (c *RemoteClient) UserSeriesPrivileges(username string) ([]SeriesPrivilege, error) {
+	return c.data().UserSeriesPrivileges(username)
+}
+
+func (c *RemoteClient) UserSeriesPrivilege(username, database, metric, condition string) (*cnosql.Privilege, error) {
+	return c.data().UserSeriesPrivilege(username, database, metric, condition)
+}
+
+func (c *RemoteClient) Roles() []RoleInfo {
+	roles := c.data().Roles
+
+	if roles == nil {
+		return []RoleInfo{}
+	}
+	return roles
+}
+
+func (c *RemoteClient) CreateRole(name string) error {
+	return c.retryUntilExec(internal.Command_CreateRoleCommand, internal.E_CreateRoleCommand_Command,
+		&internal.CreateRoleCommand{
+			Name: proto.String(name),
+		},
+	)
+}
+
+func (c *RemoteClient) DropRole(name string) error {
+	return c.retryUntilExec(internal.Command_DropRoleCommand, internal.E_DropRoleCommand_Command,
+		&internal.DropRoleCommand{
+			Name: proto.String(name),
+		},
+	)
+}
+
+func (c *RemoteClient) SetUserQuota(name string, q Quota) error {
+	return c.retryUntilExec(internal.Command_SetQuotaCommand, internal.E_SetQuotaCommand_Command,
+		&internal.SetQuotaCommand{
+			User:  proto.String(name),
+			Quota: q.marshal(),
+		},
+	)
+}
+
+func (c *RemoteClient) SetDatabaseQuota(name string, q Quota) error {
+	return c.retryUntilExec(internal.Command_SetQuotaCommand, internal.E_SetQuotaCommand_Command,
+		&internal.SetQuotaCommand{
+			Database: proto.String(name),
+			Quota:    q.marshal(),
+		},
+	)
+}
+
+func (c *RemoteClient) SetRolePrivilege(name, database string, p cnosql.Privilege) error {
+	return c.retryUntilExec(internal.Command_SetRolePrivilegeCommand, internal.E_SetRolePrivilegeCommand_Command,
+		&internal.SetRolePrivilegeCommand{
+			Role:      proto.String(name),
+			Database:  proto.String(database),
+			Privilege: proto.Int32(int32(p)),
+		},
+	)
+}
+
+func (c *RemoteClient) RolePrivilege(name, database string) (*cnosql.Privilege, error) {
+	return c.data().RolePrivilege(name, database)
+}
+
+func (c *RemoteClient) SetUserRole(username, role string, granted bool) error {
+	return c.retryUntilExec(internal.Command_SetUserRoleCommand, internal.E_SetUserRoleCommand_Command,
+		&internal.SetUserRoleCommand{
+			Username: proto.String(username),
+			Role:     proto.String(role),
+			Granted:  proto.Bool(granted),
+		},
+	)
+}
+
+func (c *RemoteClient) SetRoleRole(name, role string, granted bool) error {
+	return c.retryUntilExec(internal.Command_SetRoleRoleCommand, internal.E_SetRoleRoleCommand_Command,
+		&internal.SetRoleRoleCommand{
+			Name:    proto.String(name),
+			Role:    proto.String(role),
+			Granted: proto.Bool(granted),
+		},
+	)
+}
+
 func (c *RemoteClient) AdminUserExists() bool {
 	for _, u := range c.data().Users {
 		if u.Admin {
@@ -809,6 +948,17 @@ func (c *RemoteClient) DropShard(id uint64) error {
 	return c.retryUntilExec(internal.Command_DropShardCommand, internal.E_DropShardCommand_Command, cmd)
 }
 
+// UpdateShardOwners adds and removes owners of a shard by ID.
+func (c *RemoteClient) UpdateShardOwners(id uint64, addOwners, delOwners []uint64) error {
+	cmd := &internal.UpdateShardOwnersCommand{
+		ID:        proto.Uint64(id),
+		AddOwners: addOwners,
+		DelOwners: delOwners,
+	}
+
+	return c.retryUntilExec(internal.Command_UpdateShardOwnersCommand, internal.E_UpdateShardOwnersCommand_Command, cmd)
+}
+
 func (c *RemoteClient) TruncateRegions(t time.Time) error {
 
 	return nil
@@ -934,7 +1084,20 @@ func (c *RemoteClient) DropContinuousQuery(database, name string) error {
 	)
 }
 
-func (c *RemoteClient) CreateSubscription(database, ttl, name, mode string, destinations []string) error {
+func (c *RemoteClient) SetContinuousQueryLastRun(database, name string, t time.Time, d time.Duration, lastErr string) error {
+	return c.retryUntilExec(internal.Command_SetContinuousQueryLastRunCommand, internal.E_SetContinuousQueryLastRunCommand_Command,
+		&internal.SetContinuousQueryLastRunCommand{
+			Database: proto.String(database),
+			Name:     proto.String(name),
+			LastRun:  proto.Int64(MarshalTime(t)),
+
+			LastDuration: proto.Int64(int64(d)),
+			LastError:    proto.String(lastErr),
+		},
+	)
+}
+
+func (c *RemoteClient) CreateSubscription(database, ttl, name, mode string, destinations []string, durable bool) error {
 	return c.retryUntilExec(internal.Command_CreateSubscriptionCommand, internal.E_CreateSubscriptionCommand_Command,
 		&internal.CreateSubscriptionCommand{
 			Database:     proto.String(database),
@@ -942,6 +1105,7 @@ func (c *RemoteClient) CreateSubscription(database, ttl, name, mode string, dest
 			Name:         proto.String(name),
 			Mode:         proto.String(mode),
 			Destinations: destinations,
+			Durable:      proto.Bool(durable),
 		},
 	)
 }
@@ -1101,7 +1265,7 @@ func (c *RemoteClient) exec(url string, typ internal.Command_Type, desc *proto.E
 		return 0, err
 	}
 
-	resp, err := http.Post(url, "application/octet-stream", bytes.NewBuffer(b))
+	resp, err := c.httpClient.Post(url, "application/octet-stream", bytes.NewBuffer(b))
 	if err != nil {
 		return 0, err
 	}
@@ -1162,7 +1326,7 @@ func (c *RemoteClient) updateAuthCache() {
 
 func (c *RemoteClient) pollForUpdates() {
 	for {
-		data := c.retryUntilSnapshot(c.index())
+		data := c.retryUntilUpdate(c.index())
 		if data == nil {
 			// this will only be nil if the client has been closed,
 			// so we can exit out
@@ -1195,7 +1359,7 @@ func (c *RemoteClient) url(server string) string {
 }
 
 func (c *RemoteClient) getSnapshot(server string, index uint64) (*Data, error) {
-	resp, err := http.Get(c.url(server) + fmt.Sprintf("?index=%d", index))
+	resp, err := c.httpClient.Get(c.url(server) + fmt.Sprintf("?index=%d", index))
 	if err != nil {
 		return nil, err
 	}
@@ -1252,3 +1416,101 @@ func (c *RemoteClient) retryUntilSnapshot(idx uint64) *Data {
 		currentServer++
 	}
 }
+
+func (c *RemoteClient) getChanges(server string, index uint64) ([]*internal.DataDelta, error) {
+	resp, err := c.httpClient.Get(c.url(server) + fmt.Sprintf("/changes?index=%d", index))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusGone, http.StatusNotFound:
+		return nil, errSnapshotRequired
+	default:
+		return nil, fmt.Errorf("meta server returned non-200: %s", resp.Status)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var pb internal.DataDeltas
+	if err := proto.Unmarshal(b, &pb); err != nil {
+		return nil, err
+	}
+
+	return pb.GetDeltas(), nil
+}
+
+// applyChanges returns a copy of the cache at idx with the changes applied.
+func (c *RemoteClient) applyChanges(idx uint64, changes []*internal.DataDelta) (*Data, error) {
+	c.mu.RLock()
+	data := c.cacheData.Clone()
+	c.mu.RUnlock()
+
+	if data.Index != idx {
+		return nil, fmt.Errorf("cache moved from index %d to %d", idx, data.Index)
+	}
+	for _, pb := range changes {
+		if err := data.applyDelta(pb); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// retryUntilUpdate returns the cache updated after idx. It fetches the changes
+// after idx, and falls back to a full snapshot if the meta server no longer has
+// them, or if they don't apply to the cache.
+func (c *RemoteClient) retryUntilUpdate(idx uint64) *Data {
+	currentServer := 0
+	for {
+		// get the server to poll
+		c.mu.RLock()
+
+		// exit if we're closed
+		select {
+		case <-c.closing:
+			c.mu.RUnlock()
+			return nil
+		default:
+			// we're still open, continue on
+		}
+
+		if currentServer >= len(c.metaServers) {
+			currentServer = 0
+		}
+		server := c.metaServers[currentServer]
+		c.mu.RUnlock()
+
+		changes, err := c.getChanges(server, idx)
+		if err == nil {
+			data, err := c.applyChanges(idx, changes)
+			if err == nil {
+				return data
+			}
+			c.logger.Info("Failed to apply metadata changes, fetching snapshot",
+				zap.String("server", server),
+				zap.Error(err))
+		}
+
+		if err == nil || err == errSnapshotRequired {
+			data, err := c.getSnapshot(server, idx)
+			if err == nil {
+				return data
+			}
+			c.logger.Error("failure getting snapshot,",
+				zap.String("server", server),
+				zap.Error(err))
+		} else {
+			c.logger.Error("failure getting changes,",
+				zap.String("server", server),
+				zap.Error(err))
+		}
+		time.Sleep(errSleep)
+
+		currentServer++
+	}
+}
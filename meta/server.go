@@ -1,6 +1,7 @@
 package meta
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
@@ -112,13 +113,29 @@ func (s *Server) initNetwork(ln net.Listener) error {
 	s.listener = ln
 
 	s.mux = cmux.New(s.listener)
-	s.httpMux = s.mux.Match(cmux.HTTP1Fast())
+	if s.Config.HTTPD.HTTPSEnabled {
+		// Serve the HTTP API over TLS only. The raft connections are told
+		// apart by their header, and are then secured by the raft layer.
+		tlsConfig, err := s.Config.HTTPD.serverTLSConfig()
+		if err != nil {
+			return err
+		}
+		s.httpMux = tls.NewListener(s.mux.Match(cmux.TLS()), tlsConfig)
+	} else {
+		s.httpMux = s.mux.Match(cmux.HTTP1Fast())
+	}
 	s.raftMux = network.ListenString(s.mux, RaftMuxHeader)
 
+	client, err := s.Config.HTTPD.httpClient()
+	if err != nil {
+		return err
+	}
+
 	h := NewHandler(s.Config.HTTPD)
 	h.Version = "0.0.0"
 	h.logger = logger.BgLogger()
 	h.store = s.store
+	h.client = client
 	h.Open()
 
 	s.httpHandler = h
@@ -1,6 +1,7 @@
 package meta
 
 import (
+	"errors"
 	"net"
 	"time"
 
@@ -44,9 +45,27 @@ type ServerConfig struct {
 	RemoteHostname string `toml:"-"`
 
 	// HTTPBindAddress is the bind address for the metaservice HTTP API
-	HTTPBindAddress  string `toml:"http-bind-address"`
+	// and the raft connections between the meta nodes.
+	HTTPBindAddress string `toml:"http-bind-address"`
+
+	// HTTPSEnabled serves the HTTP API and the raft connections over TLS.
 	HTTPSEnabled     bool   `toml:"https-enabled"`
 	HTTPSCertificate string `toml:"https-certificate"`
+	HTTPSPrivateKey  string `toml:"https-private-key"`
+
+	// HTTPSClientCA is the CA certificate verifying the client certificates.
+	// If set, clients of the HTTPS API and the other meta nodes connecting
+	// over raft must present a certificate.
+	HTTPSClientCA string `toml:"https-client-ca"`
+
+	// HTTPSInsecureTLS skips the verification of the certificates of the
+	// other meta nodes.
+	HTTPSInsecureTLS bool `toml:"https-insecure-tls"`
+
+	// AuthEnabled requires the requests to the HTTP API and the raft
+	// connections to carry a token signed with the internal shared secret.
+	AuthEnabled          bool   `toml:"auth-enabled"`
+	InternalSharedSecret string `toml:"internal-shared-secret"`
 
 	ElectionTimeout    toml.Duration `toml:"election-timeout"`
 	HeartbeatTimeout   toml.Duration `toml:"heartbeat-timeout"`
@@ -54,6 +73,12 @@ type ServerConfig struct {
 	CommitTimeout      toml.Duration `toml:"commit-timeout"`
 	ClusterTracing     bool          `toml:"cluster-tracing"`
 	LeaseDuration      toml.Duration `toml:"lease-duration"`
+	MetricsEnabled     bool          `toml:"metrics-enabled"`
+
+	// MetricsAuthEnabled requires the token of auth-enabled to scrape the
+	// metrics, if auth-enabled is set. Disable it to let the scrapers that
+	// can't sign tokens scrape the metrics.
+	MetricsAuthEnabled bool `toml:"metrics-auth-enabled"`
 
 	TLS *tls.Config `toml:"-"`
 }
@@ -68,11 +93,27 @@ func NewServerConfig() *ServerConfig {
 		LeaderLeaseTimeout: toml.Duration(DefaultLeaderLeaseTimeout),
 		CommitTimeout:      toml.Duration(DefaultCommitTimeout),
 		LeaseDuration:      toml.Duration(DefaultLeaseDuration),
+		MetricsEnabled:     true,
+		MetricsAuthEnabled: true,
 	}
 
 	return sc
 }
 
+// Validate returns an error if the config is invalid.
+func (c *ServerConfig) Validate() error {
+	if c.AuthEnabled && c.InternalSharedSecret == "" {
+		return errors.New("internal-shared-secret must be set if auth-enabled is true")
+	}
+	if c.HTTPSEnabled && c.HTTPSCertificate == "" {
+		return errors.New("https-certificate must be set if https-enabled is true")
+	}
+	if c.TLS != nil {
+		return c.TLS.Validate()
+	}
+	return nil
+}
+
 func (c *ServerConfig) defaultHost(addr string) string {
 	address, err := DefaultHost(DefaultHostname, addr)
 	if nil != err {
@@ -101,10 +142,14 @@ func (c ServerConfig) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"http-bind-address":    c.HTTPBindAddress,
 		"https-enabled":        c.HTTPSEnabled,
 		"https-certificate":    c.HTTPSCertificate,
+		"https-client-ca":      c.HTTPSClientCA,
+		"auth-enabled":         c.AuthEnabled,
 		"election-timeout":     c.ElectionTimeout,
 		"heartbeat-timeout":    c.HeartbeatTimeout,
 		"leader-lease-timeout": c.LeaderLeaseTimeout,
 		"commit-timeout":       c.CommitTimeout,
 		"cluster-tracing":      c.ClusterTracing,
+		"metrics-enabled":      c.MetricsEnabled,
+		"metrics-auth-enabled": c.MetricsAuthEnabled,
 	}), nil
 }
@@ -40,6 +40,7 @@ type store struct {
 	data        *Data
 	raftState   *raftState
 	dataChanged chan struct{}
+	changes     []*internal.DataDelta
 	path        string
 	opened      bool
 	logger      *log.Logger
@@ -224,6 +225,35 @@ func (s *store) afterIndex(index uint64) <-chan struct{} {
 	return s.dataChanged
 }
 
+// addChange records the change of the metadata by the last applied command.
+// The oldest change is dropped once there are too many.
+func (s *store) addChange(pb *internal.DataDelta) {
+	if len(s.changes) >= maxDataDeltas {
+		copy(s.changes, s.changes[1:])
+		s.changes = s.changes[:len(s.changes)-1]
+	}
+	s.changes = append(s.changes, pb)
+}
+
+// changesSince returns the changes of the metadata after index. It returns
+// false if the changes since index are no longer kept, in which case the
+// client needs a full snapshot.
+func (s *store) changesSince(index uint64) ([]*internal.DataDelta, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if index == s.data.Index {
+		return nil, true
+	}
+
+	for i := len(s.changes) - 1; i >= 0; i-- {
+		if s.changes[i].GetPrevIndex() == index {
+			return append([]*internal.DataDelta(nil), s.changes[i:]...), true
+		}
+	}
+	return nil, false
+}
+
 // WaitForLeader sleeps until a leader is found or a timeout occurs.
 // timeout == 0 means to wait forever.
 func (s *store) waitForLeader(timeout time.Duration) error {
@@ -349,6 +379,9 @@ func (s *store) joinCluster(peers []string) (*NodeInfo, error) {
 		c := NewRemoteClient()
 		c.SetMetaServers(peers)
 		c.SetTLS(s.config.HTTPD.HTTPSEnabled)
+		if err := s.config.HTTPD.configureClient(c); err != nil {
+			return nil, err
+		}
 		if err := c.Open(); err != nil {
 			return nil, err
 		}
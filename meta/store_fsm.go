@@ -27,6 +27,10 @@ func (fsm *storeFSM) Apply(l *raft.Log) interface{} {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Commands copy the metadata before changing it, so prev is unchanged
+	// except for its term and index.
+	prev, prevIndex := fsm.data, fsm.data.Index
+
 	err := func() interface{} {
 		switch cmd.GetType() {
 		case internal.Command_RemovePeerCommand:
@@ -75,6 +79,20 @@ func (fsm *storeFSM) Apply(l *raft.Log) interface{} {
 			return fsm.applySetPrivilegeCommand(&cmd)
 		case internal.Command_SetAdminPrivilegeCommand:
 			return fsm.applySetAdminPrivilegeCommand(&cmd)
+		case internal.Command_SetSeriesPrivilegeCommand:
+			return fsm.applySetSeriesPrivilegeCommand(&cmd)
+		case internal.Command_CreateRoleCommand:
+			return fsm.applyCreateRoleCommand(&cmd)
+		case internal.Command_DropRoleCommand:
+			return fsm.applyDropRoleCommand(&cmd)
+		case internal.Command_SetRolePrivilegeCommand:
+			return fsm.applySetRolePrivilegeCommand(&cmd)
+		case internal.Command_SetUserRoleCommand:
+			return fsm.applySetUserRoleCommand(&cmd)
+		case internal.Command_SetRoleRoleCommand:
+			return fsm.applySetRoleRoleCommand(&cmd)
+		case internal.Command_SetQuotaCommand:
+			return fsm.applySetQuotaCommand(&cmd)
 		case internal.Command_SetDataCommand:
 			return fsm.applySetDataCommand(&cmd)
 		case internal.Command_UpdateNodeCommand:
@@ -89,6 +107,10 @@ func (fsm *storeFSM) Apply(l *raft.Log) interface{} {
 			return fsm.applyCreateDataNodeCommand(&cmd)
 		case internal.Command_DeleteDataNodeCommand:
 			return fsm.applyDeleteDataNodeCommand(&cmd)
+		case internal.Command_UpdateShardOwnersCommand:
+			return fsm.applyUpdateShardOwnersCommand(&cmd)
+		case internal.Command_SetContinuousQueryLastRunCommand:
+			return fsm.applySetContinuousQueryLastRunCommand(&cmd)
 		default:
 			panic(fmt.Errorf("cannot apply command: %x", l.Data))
 		}
@@ -98,6 +120,9 @@ func (fsm *storeFSM) Apply(l *raft.Log) interface{} {
 	fsm.data.Term = l.Term
 	fsm.data.Index = l.Index
 
+	// Record the change for the clients polling for changes.
+	s.addChange(diffData(prevIndex, prev, fsm.data))
+
 	// signal that the data changed
 	close(s.dataChanged)
 	s.dataChanged = make(chan struct{})
@@ -389,13 +414,27 @@ func (fsm *storeFSM) applyDropContinuousQueryCommand(cmd *internal.Command) inte
 	return nil
 }
 
+func (fsm *storeFSM) applySetContinuousQueryLastRunCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetContinuousQueryLastRunCommand_Command)
+	v := ext.(*internal.SetContinuousQueryLastRunCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetContinuousQueryLastRun(v.GetDatabase(), v.GetName(), UnmarshalTime(v.GetLastRun()), time.Duration(v.GetLastDuration()), v.GetLastError()); err != nil {
+		return err
+	}
+	fsm.data = other
+
+	return nil
+}
+
 func (fsm *storeFSM) applyCreateSubscriptionCommand(cmd *internal.Command) interface{} {
 	ext, _ := proto.GetExtension(cmd, internal.E_CreateSubscriptionCommand_Command)
 	v := ext.(*internal.CreateSubscriptionCommand)
 
 	// Copy data and update.
 	other := fsm.data.Clone()
-	if err := other.CreateSubscription(v.GetDatabase(), v.GetTimeToLive(), v.GetName(), v.GetMode(), v.GetDestinations()); err != nil {
+	if err := other.CreateSubscription(v.GetDatabase(), v.GetTimeToLive(), v.GetName(), v.GetMode(), v.GetDestinations(), v.GetDurable()); err != nil {
 		return err
 	}
 	fsm.data = other
@@ -470,6 +509,106 @@ func (fsm *storeFSM) applySetPrivilegeCommand(cmd *internal.Command) interface{}
 	return nil
 }
 
+func (fsm *storeFSM) applySetSeriesPrivilegeCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetSeriesPrivilegeCommand_Command)
+	v := ext.(*internal.SetSeriesPrivilegeCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetSeriesPrivilege(v.GetUsername(), v.GetDatabase(), v.GetMetric(), v.GetCondition(), cnosql.Privilege(v.GetPrivilege())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyCreateRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_CreateRoleCommand_Command)
+	v := ext.(*internal.CreateRoleCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.CreateRole(v.GetName()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applyDropRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_DropRoleCommand_Command)
+	v := ext.(*internal.DropRoleCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.DropRole(v.GetName()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetRolePrivilegeCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetRolePrivilegeCommand_Command)
+	v := ext.(*internal.SetRolePrivilegeCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetRolePrivilege(v.GetRole(), v.GetDatabase(), cnosql.Privilege(v.GetPrivilege())); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetQuotaCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetQuotaCommand_Command)
+	v := ext.(*internal.SetQuotaCommand)
+
+	var q Quota
+	q.unmarshal(v.GetQuota())
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if v.User != nil {
+		if err := other.SetUserQuota(v.GetUser(), q); err != nil {
+			return err
+		}
+	} else {
+		if err := other.SetDatabaseQuota(v.GetDatabase(), q); err != nil {
+			return err
+		}
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetUserRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetUserRoleCommand_Command)
+	v := ext.(*internal.SetUserRoleCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetUserRole(v.GetUsername(), v.GetRole(), v.GetGranted()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
+func (fsm *storeFSM) applySetRoleRoleCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_SetRoleRoleCommand_Command)
+	v := ext.(*internal.SetRoleRoleCommand)
+
+	// Copy data and update.
+	other := fsm.data.Clone()
+	if err := other.SetRoleRole(v.GetName(), v.GetRole(), v.GetGranted()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
 func (fsm *storeFSM) applySetAdminPrivilegeCommand(cmd *internal.Command) interface{} {
 	ext, _ := proto.GetExtension(cmd, internal.E_SetAdminPrivilegeCommand_Command)
 	v := ext.(*internal.SetAdminPrivilegeCommand)
@@ -579,6 +718,18 @@ func (fsm *storeFSM) applyDeleteDataNodeCommand(cmd *internal.Command) interface
 	return nil
 }
 
+func (fsm *storeFSM) applyUpdateShardOwnersCommand(cmd *internal.Command) interface{} {
+	ext, _ := proto.GetExtension(cmd, internal.E_UpdateShardOwnersCommand_Command)
+	v := ext.(*internal.UpdateShardOwnersCommand)
+
+	other := fsm.data.Clone()
+	if err := other.UpdateShardOwners(v.GetID(), v.GetAddOwners(), v.GetDelOwners()); err != nil {
+		return err
+	}
+	fsm.data = other
+	return nil
+}
+
 func (fsm *storeFSM) Snapshot() (raft.FSMSnapshot, error) {
 	s := (*store)(fsm)
 	s.mu.Lock()
@@ -600,10 +751,19 @@ func (fsm *storeFSM) Restore(r io.ReadCloser) error {
 		return err
 	}
 
-	// Set metadata on store.
-	// NOTE: No lock because Hashicorp Raft doesn't call Restore concurrently
-	// with any other function.
-	fsm.data = data
+	// Set metadata on store. Hashicorp Raft doesn't call Restore concurrently
+	// with any other function, but the HTTP API reads the metadata and the
+	// changes.
+	s := (*store)(fsm)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = data
+
+	// The changes before the snapshot don't apply to it, so the clients
+	// polling for changes fetch the snapshot.
+	s.changes = nil
+	close(s.dataChanged)
+	s.dataChanged = make(chan struct{})
 
 	return nil
 }
@@ -0,0 +1,159 @@
+package meta
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newChangesStore returns a store holding the metadata built by the changes.
+func newChangesStore(t *testing.T) *store {
+	t.Helper()
+	s := &store{data: &Data{Index: 1}, dataChanged: make(chan struct{})}
+	for _, tt := range deltaChanges {
+		applyChange(t, s, tt.change)
+	}
+	return s
+}
+
+// applyChange changes the metadata of the store, as the commands applied by
+// raft do.
+func applyChange(t *testing.T, s *store, change func(data *Data) error) {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev := s.data
+	s.data = nextData(t, prev, change)
+	s.addChange(diffData(prev.Index, prev, s.data))
+	close(s.dataChanged)
+	s.dataChanged = make(chan struct{})
+}
+
+func noChange(data *Data) error { return nil }
+
+func TestStore_ChangesSince(t *testing.T) {
+	s := newChangesStore(t)
+
+	if changes, ok := s.changesSince(1); !ok || len(changes) != len(deltaChanges) {
+		t.Fatalf("got %d changes (%v), exp %d", len(changes), ok, len(deltaChanges))
+	}
+	if changes, ok := s.changesSince(s.data.Index - 1); !ok || len(changes) != 1 || changes[0].GetIndex() != s.data.Index {
+		t.Fatalf("got %d changes (%v), exp the last change", len(changes), ok)
+	}
+	if changes, ok := s.changesSince(s.data.Index); !ok || len(changes) != 0 {
+		t.Fatalf("got %d changes (%v), exp none", len(changes), ok)
+	}
+
+	// The oldest changes are dropped from the log.
+	for i := 0; i < maxDataDeltas; i++ {
+		applyChange(t, s, noChange)
+	}
+	if _, ok := s.changesSince(1); ok {
+		t.Fatal("expected the changes to be dropped")
+	}
+	if changes, ok := s.changesSince(s.data.Index - maxDataDeltas); !ok || len(changes) != maxDataDeltas {
+		t.Fatalf("got %d changes (%v), exp %d", len(changes), ok, maxDataDeltas)
+	}
+}
+
+func TestStore_Restore(t *testing.T) {
+	s := newChangesStore(t)
+	index := s.data.Index
+	changed := s.afterIndex(index)
+
+	b, err := s.data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (*storeFSM)(s).Restore(ioutil.NopCloser(bytes.NewReader(b))); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The clients polling for changes are woken up, and fetch a snapshot.
+	select {
+	case <-changed:
+	default:
+		t.Fatal("expected the clients polling for changes to be woken up")
+	}
+	if _, ok := s.changesSince(index - 1); ok {
+		t.Fatal("expected the changes before the snapshot to be dropped")
+	}
+}
+
+// newChangesServer returns a meta server serving the metadata of the store,
+// and the number of snapshots it served.
+func newChangesServer(s *store) (*httptest.Server, *int64) {
+	h := NewHandler(&ServerConfig{})
+	h.store = s
+
+	var snapshots int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/" {
+			atomic.AddInt64(&snapshots, 1)
+		}
+		h.ServeHTTP(w, r)
+	}))
+	return srv, &snapshots
+}
+
+// newChangesClient returns a client of the server caching data.
+func newChangesClient(srv *httptest.Server, data *Data) *RemoteClient {
+	c := NewRemoteClient()
+	c.SetMetaServers([]string{strings.TrimPrefix(srv.URL, "http://")})
+	c.cacheData = data
+	c.closing = make(chan struct{})
+	return c
+}
+
+func TestRemoteClient_Changes(t *testing.T) {
+	s := &store{data: &Data{Index: 1}, dataChanged: make(chan struct{})}
+	srv, snapshots := newChangesServer(s)
+	defer srv.Close()
+
+	// The client catches up with each change.
+	c := newChangesClient(srv, s.data.Clone())
+	for _, tt := range deltaChanges {
+		idx := s.data.Index
+		applyChange(t, s, tt.change)
+
+		data := c.retryUntilUpdate(idx)
+		if !reflect.DeepEqual(normalizeData(t, data), normalizeData(t, s.data)) {
+			t.Fatalf("%s: unexpected metadata:\n got %+v\n exp %+v", tt.name, data, s.data)
+		}
+		c.cacheData = data
+	}
+
+	// The client catches up with several changes at once.
+	c = newChangesClient(srv, &Data{Index: 1})
+	if data := c.retryUntilUpdate(1); !reflect.DeepEqual(normalizeData(t, data), normalizeData(t, s.data)) {
+		t.Fatalf("unexpected metadata:\n got %+v\n exp %+v", data, s.data)
+	}
+
+	if n := atomic.LoadInt64(snapshots); n != 0 {
+		t.Fatalf("got %d snapshots, exp 0", n)
+	}
+}
+
+func TestRemoteClient_Changes_Truncated(t *testing.T) {
+	s := newChangesStore(t)
+	for i := 0; i < maxDataDeltas; i++ {
+		applyChange(t, s, noChange)
+	}
+	srv, snapshots := newChangesServer(s)
+	defer srv.Close()
+
+	// The client too far behind falls back to a snapshot.
+	c := newChangesClient(srv, &Data{Index: 1})
+	if data := c.retryUntilUpdate(1); !reflect.DeepEqual(normalizeData(t, data), normalizeData(t, s.data)) {
+		t.Fatalf("unexpected metadata:\n got %+v\n exp %+v", data, s.data)
+	}
+	if n := atomic.LoadInt64(snapshots); n != 1 {
+		t.Fatalf("got %d snapshots, exp 1", n)
+	}
+}
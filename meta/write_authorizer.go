@@ -16,7 +16,9 @@ func NewWriteAuthorizer(c MetaClient) *WriteAuthorizer {
 	return &WriteAuthorizer{Client: c}
 }
 
-// AuthorizeWrite returns nil if the user has permission to write to the database.
+// AuthorizeWrite returns nil if the user has permission to write to the database,
+// or to some series of the database. The series of the written points are
+// checked by the points writer.
 func (a WriteAuthorizer) AuthorizeWrite(username, database string) error {
 	u, err := a.Client.User(username)
 	if err != nil || u == nil {
@@ -30,7 +32,7 @@ func (a WriteAuthorizer) AuthorizeWrite(username, database string) error {
 	// Enterprise UserInfo in closed-source code.
 	switch user := u.(type) {
 	case *UserInfo:
-		if !user.AuthorizeDatabase(cnosql.WritePrivilege, database) {
+		if !user.AuthorizeDatabase(cnosql.WritePrivilege, database) && !user.hasSeriesPrivilege(cnosql.WritePrivilege, database) {
 			return &ErrAuthorize{
 				Database: database,
 				Message:  fmt.Sprintf("%s not authorized to write to %s", username, database),
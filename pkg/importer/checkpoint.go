@@ -0,0 +1,68 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// checkpoint records how far an import went, so that a failed import can
+// resume. The offset is the one of the first line of the file which may
+// not be written yet, in the uncompressed data, and the database and the
+// time to live are the context of the line protocol at this offset.
+type checkpoint struct {
+	Path       string `json:"path"`
+	Offset     int64  `json:"offset"`
+	Database   string `json:"database,omitempty"`
+	TimeToLive string `json:"ttl,omitempty"`
+}
+
+// loadCheckpoint loads the checkpoint of the import of a file. It returns
+// nil if the file has no checkpoint.
+func loadCheckpoint(path, file string) (*checkpoint, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var c checkpoint
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	if c.Path != file {
+		return nil, fmt.Errorf("checkpoint %s is the one of the import of %s", path, c.Path)
+	}
+	return &c, nil
+}
+
+// save atomically replaces the checkpoint file at path.
+func (c *checkpoint) save(path string) error {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,272 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cnosdatabase/db/models"
+)
+
+// Formats of the imported files.
+const (
+	FormatLineProtocol = "lp"
+	FormatCSV          = "csv"
+	FormatJSON         = "json"
+)
+
+// Roles of the columns of a CSV file.
+const (
+	roleMetric   = "metric"
+	roleTag      = "tag"
+	roleTime     = "time"
+	roleRFC3339  = "rfc3339"
+	roleFloat    = "float"
+	roleInteger  = "integer"
+	roleUnsigned = "unsigned"
+	roleString   = "string"
+	roleBoolean  = "boolean"
+	roleIgnore   = "ignore"
+)
+
+// decoder converts the lines of a CSV or JSON lines file to line protocol.
+type decoder interface {
+	decode(line []byte) ([]byte, error)
+}
+
+// csvColumn is a column of a CSV file mapped to a part of the points.
+type csvColumn struct {
+	index int
+	name  string
+	role  string
+}
+
+// csvDecoder converts the rows of a CSV file to points. The first row of
+// the file names the columns, and the mapping spec gives the role of the
+// named columns as a comma separated list of <column>:<role>. The roles are
+// metric, tag, time (a timestamp in the precision of the import), rfc3339,
+// the type of a field (float, integer, unsigned, string or boolean) and
+// ignore. The columns missing from the spec are ignored.
+type csvDecoder struct {
+	columns   []csvColumn
+	n         int
+	metric    string
+	precision time.Duration
+}
+
+// newCSVDecoder returns a decoder of the rows following the header. The
+// metric of the rows is the default one if no column is mapped to it.
+func newCSVDecoder(header []byte, spec, metric, precision string) (*csvDecoder, error) {
+	names, err := csv.NewReader(bytes.NewReader(header)).Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %s", err)
+	}
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		index[strings.TrimSpace(name)] = i
+	}
+
+	d := &csvDecoder{n: len(names), metric: metric}
+	if d.precision, err = precisionDuration(precision); err != nil {
+		return nil, err
+	}
+
+	var hasMetric, hasTime, hasField bool
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		// The role follows the last colon, column names may hold colons.
+		i := strings.LastIndex(entry, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid CSV mapping %q: expected <column>:<role>", entry)
+		}
+		name, role := entry[:i], entry[i+1:]
+		col, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid CSV mapping %q: no column %s in the header", entry, name)
+		}
+
+		switch role {
+		case roleMetric:
+			if hasMetric {
+				return nil, fmt.Errorf("invalid CSV mapping: more than one metric column")
+			}
+			hasMetric = true
+		case roleTime, roleRFC3339:
+			if hasTime {
+				return nil, fmt.Errorf("invalid CSV mapping: more than one time column")
+			}
+			hasTime = true
+		case roleFloat, roleInteger, roleUnsigned, roleString, roleBoolean:
+			hasField = true
+		case roleTag:
+		case roleIgnore:
+			continue
+		default:
+			return nil, fmt.Errorf("invalid CSV mapping %q: unknown role %s", entry, role)
+		}
+		d.columns = append(d.columns, csvColumn{index: col, name: name, role: role})
+	}
+
+	if !hasField {
+		return nil, fmt.Errorf("invalid CSV mapping: no field column")
+	}
+	if !hasMetric && metric == "" {
+		return nil, fmt.Errorf("invalid CSV mapping: no metric column nor default metric")
+	}
+	return d, nil
+}
+
+func (d *csvDecoder) decode(line []byte) ([]byte, error) {
+	r := csv.NewReader(bytes.NewReader(line))
+	r.FieldsPerRecord = d.n
+	record, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV row: %s", err)
+	}
+
+	name := d.metric
+	tags := make(map[string]string)
+	fields := make(models.Fields)
+	var t time.Time
+	for _, c := range d.columns {
+		v := record[c.index]
+		if v == "" {
+			continue
+		}
+
+		switch c.role {
+		case roleMetric:
+			name = v
+		case roleTag:
+			tags[c.name] = v
+		case roleTime:
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time %q: %s", v, err)
+			}
+			t = time.Unix(0, n*int64(d.precision))
+		case roleRFC3339:
+			if t, err = time.Parse(time.RFC3339Nano, v); err != nil {
+				return nil, fmt.Errorf("invalid time %q: %s", v, err)
+			}
+		default:
+			if fields[c.name], err = parseFieldValue(v, c.role); err != nil {
+				return nil, fmt.Errorf("invalid value of field %s: %s", c.name, err)
+			}
+		}
+	}
+	return encodePoint(name, tags, fields, t)
+}
+
+// parseFieldValue parses the value of a field of the named type.
+func parseFieldValue(v, typ string) (interface{}, error) {
+	switch typ {
+	case roleFloat:
+		return strconv.ParseFloat(v, 64)
+	case roleInteger:
+		return strconv.ParseInt(v, 10, 64)
+	case roleUnsigned:
+		return strconv.ParseUint(v, 10, 64)
+	case roleBoolean:
+		return strconv.ParseBool(v)
+	default:
+		return v, nil
+	}
+}
+
+// jsonPoint is a point of a JSON lines file. The time is either a
+// timestamp in the precision of the import or a RFC3339 string, and the
+// numbers of the fields are floats.
+type jsonPoint struct {
+	Metric string                 `json:"metric"`
+	Tags   map[string]string      `json:"tags"`
+	Fields map[string]interface{} `json:"fields"`
+	Time   json.RawMessage        `json:"time"`
+}
+
+// jsonDecoder converts the lines of a JSON lines file to points.
+type jsonDecoder struct {
+	precision time.Duration
+}
+
+func newJSONDecoder(precision string) (*jsonDecoder, error) {
+	d, err := precisionDuration(precision)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonDecoder{precision: d}, nil
+}
+
+func (d *jsonDecoder) decode(line []byte) ([]byte, error) {
+	var p jsonPoint
+	if err := json.Unmarshal(line, &p); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %s", err)
+	}
+
+	var t time.Time
+	if len(p.Time) > 0 && string(p.Time) != "null" {
+		var s string
+		var n int64
+		if err := json.Unmarshal(p.Time, &s); err == nil {
+			if t, err = time.Parse(time.RFC3339Nano, s); err != nil {
+				return nil, fmt.Errorf("invalid time %q: %s", s, err)
+			}
+		} else if err := json.Unmarshal(p.Time, &n); err == nil {
+			t = time.Unix(0, n*int64(d.precision))
+		} else {
+			return nil, fmt.Errorf("invalid time %s", p.Time)
+		}
+	}
+
+	fields := make(models.Fields, len(p.Fields))
+	for k, v := range p.Fields {
+		switch v.(type) {
+		case float64, string, bool:
+			fields[k] = v
+		case nil:
+		default:
+			return nil, fmt.Errorf("invalid value of field %s", k)
+		}
+	}
+	return encodePoint(p.Metric, p.Tags, fields, t)
+}
+
+// encodePoint returns the line protocol of a point with a nanosecond
+// timestamp. The point has no timestamp if t is zero.
+func encodePoint(name string, tags map[string]string, fields models.Fields, t time.Time) ([]byte, error) {
+	if name == "" {
+		return nil, fmt.Errorf("missing metric")
+	}
+	pt, err := models.NewPoint(name, models.NewTags(tags), fields, t)
+	if err != nil {
+		return nil, err
+	}
+	return pt.AppendString(nil), nil
+}
+
+// precisionDuration returns the duration of the unit of a precision.
+func precisionDuration(precision string) (time.Duration, error) {
+	switch precision {
+	case "", "n", "ns":
+		return time.Nanosecond, nil
+	case "u":
+		return time.Microsecond, nil
+	case "ms":
+		return time.Millisecond, nil
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+	return 0, fmt.Errorf("invalid precision: %s", precision)
+}
@@ -2,7 +2,10 @@ package importer
 
 import (
 	"bufio"
+	"bytes"
 	"compress/gzip"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,20 +15,55 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cnosdatabase/cnosdb/client"
 )
 
-const batchSize = 5000
+const (
+	// DefaultBatchSize is the default number of lines written by a request.
+	DefaultBatchSize = 5000
+
+	// DefaultWorkers is the default number of concurrent writes.
+	DefaultWorkers = 4
+
+	// maxRetries is the number of times a batch is retried when a write
+	// fails for a reason other than its points.
+	maxRetries = 5
+)
 
 type Config struct {
 	URL        url.URL
 	Path       string
-	Version    string
 	Compressed bool // Whether import data is gzipped.
 	PPS        int  // points per second importer imports with.
 
+	// Format is the format of the file: line protocol, CSV or JSON lines.
+	Format string
+
+	// CSVMapping gives the role of the columns of a CSV file, see
+	// csvDecoder. Metric is the metric of the rows without a metric column.
+	CSVMapping string
+	Metric     string
+
+	// Database and TimeToLive are the ones the points are written to, until
+	// the CONTEXT comments of a line protocol file change them.
+	Database   string
+	TimeToLive string
+
+	// Workers is the number of batches written concurrently, and BatchSize
+	// the number of lines of a batch.
+	Workers   int
+	BatchSize int
+
+	// Checkpoint is the file recording the offset of the import, which is
+	// resumed from the offset if the file exists. DeadLetter is the file
+	// the lines rejected by the server are appended to, with the error.
+	Checkpoint string
+	DeadLetter string
+
 	ClientConfig     *client.HTTPConfig
 	Precision        string
 	WriteConsistency string
@@ -33,24 +71,52 @@ type Config struct {
 
 func NewConfig() *Config {
 	return &Config{
+		Format:       FormatLineProtocol,
+		Workers:      DefaultWorkers,
+		BatchSize:    DefaultBatchSize,
 		ClientConfig: &client.HTTPConfig{},
 	}
 }
 
+// batch is a batch of lines of the file, written by a single request. It
+// ends at offset in the file.
+type batch struct {
+	seq        int64
+	database   string
+	timeToLive string
+	lines      [][]byte // lines of the file
+	points     [][]byte // line protocol of the lines
+	offset     int64
+}
+
 type Importer struct {
 	config *Config
 
-	client                client.Client
-	database              string
-	timeToLive            string
-	batch                 []string
-	totalInserts          int
-	failedInserts         int
-	totalCommands         int
-	throttlePointsWritten int
-	startTime             time.Time
-	lastWrite             time.Time
-	throttle              *time.Ticker
+	client     client.Client
+	httpClient *http.Client
+	decoder    decoder
+	throttle   *throttle
+
+	// State of the reader of the file.
+	database   string
+	timeToLive string
+	offset     int64
+	ddl        bool
+	batch      *batch
+	seq        int64
+
+	totalInserts  int64
+	failedInserts int64
+	totalCommands int
+	startTime     time.Time
+
+	batches chan *batch
+	done    chan *batch
+	closing chan struct{}
+
+	mu         sync.Mutex
+	err        error
+	deadLetter *os.File
 
 	stderrLogger *log.Logger
 	stdoutLogger *log.Logger
@@ -59,27 +125,62 @@ type Importer struct {
 func NewImporter(c Config) *Importer {
 	return &Importer{
 		config:       &c,
-		batch:        make([]string, 0, batchSize),
 		stdoutLogger: log.New(os.Stdout, "", log.LstdFlags),
 		stderrLogger: log.New(os.Stderr, "", log.LstdFlags),
 	}
 }
 
-// Import processes the specified file in the Config and writes the data to the databases in chunks specified by batchSize
+// Import processes the specified file in the Config and writes the data to
+// the databases in batches of BatchSize lines, with Workers concurrent
+// writes. The offset of the lines written is saved in the checkpoint file,
+// and a failed import started again resumes from it.
 func (i *Importer) Import() error {
+	// Validate args
+	if i.config.Path == "" {
+		return fmt.Errorf("file argument required")
+	}
+	if i.config.Workers <= 0 || i.config.BatchSize <= 0 {
+		return fmt.Errorf("workers and batch size must be positive")
+	}
+	switch i.config.Format {
+	case FormatLineProtocol, FormatCSV:
+	case FormatJSON:
+		d, err := newJSONDecoder(i.config.Precision)
+		if err != nil {
+			return err
+		}
+		i.decoder = d
+	default:
+		return fmt.Errorf("unknown format: %s", i.config.Format)
+	}
+
 	// Create a client and try to connect.
 	cl, err := client.NewHTTPClient(*i.config.ClientConfig)
 	if err != nil {
 		return fmt.Errorf("could not create client: %s", err)
 	}
 	i.client = cl
+	defer i.client.Close()
 	if _, _, e := i.client.Ping(client.DEFAULT_TIMEOUT); e != nil {
 		return fmt.Errorf("failed to connect to %s\n", i.config.ClientConfig.Addr)
 	}
+	i.httpClient = newHTTPClient(i.config.ClientConfig, i.config.Workers)
+	i.throttle = &throttle{pps: i.config.PPS}
 
-	// Validate args
-	if i.config.Path == "" {
-		return fmt.Errorf("file argument required")
+	var cp *checkpoint
+	if i.config.Checkpoint != "" {
+		if cp, err = loadCheckpoint(i.config.Checkpoint, i.config.Path); err != nil {
+			return fmt.Errorf("loading checkpoint: %s", err)
+		}
+	}
+
+	if i.config.DeadLetter != "" {
+		f, err := os.OpenFile(i.config.DeadLetter, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		i.deadLetter = f
 	}
 
 	defer func() {
@@ -114,24 +215,75 @@ func (i *Importer) Import() error {
 	}
 
 	// Get our reader
-	scanner := bufio.NewReader(r)
+	scanner := bufio.NewReaderSize(r, 1024*1024)
 
-	// Process the DDL
-	if err := i.processDDL(scanner); err != nil {
-		return fmt.Errorf("reading standard input: %s", err)
+	// The header of a CSV file is read even when resuming.
+	if i.config.Format == FormatCSV {
+		header, err := scanner.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		i.offset = int64(len(header))
+		if i.decoder, err = newCSVDecoder(trimLine(header), i.config.CSVMapping, i.config.Metric, i.config.Precision); err != nil {
+			return err
+		}
 	}
 
-	// Set up our throttle channel.  Since there is effectively no other activity at this point
-	// the smaller resolution gets us much closer to the requested PPS
-	i.throttle = time.NewTicker(time.Microsecond)
-	defer i.throttle.Stop()
+	i.database, i.timeToLive = i.config.Database, i.config.TimeToLive
+	if cp != nil && cp.Offset > i.offset {
+		i.stdoutLogger.Printf("Resuming import of %s from offset %d\n", i.config.Path, cp.Offset)
+		if i.config.Compressed {
+			if _, err := io.CopyN(ioutil.Discard, scanner, cp.Offset-i.offset); err != nil {
+				return err
+			}
+		} else {
+			if _, err := f.Seek(cp.Offset, io.SeekStart); err != nil {
+				return err
+			}
+			scanner.Reset(f)
+		}
+		i.offset = cp.Offset
+		i.database, i.timeToLive = cp.Database, cp.TimeToLive
+	}
 
-	// Prime the last write
-	i.lastWrite = time.Now()
+	// Start the workers writing the batches, and the committer saving the
+	// checkpoint once they're written.
+	i.batches = make(chan *batch, i.config.Workers)
+	i.done = make(chan *batch, i.config.Workers)
+	i.closing = make(chan struct{})
+	var wg sync.WaitGroup
+	for n := 0; n < i.config.Workers; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			i.writeBatches()
+		}()
+	}
+	committed := make(chan struct{})
+	go func() {
+		defer close(committed)
+		i.commit()
+	}()
+
+	i.startTime = time.Now()
+	err = i.process(scanner)
+	close(i.batches)
+	wg.Wait()
+	close(i.done)
+	<-committed
+
+	if err != nil {
+		i.fail(fmt.Errorf("reading %s: %s", i.config.Path, err))
+	}
+	if err := i.error(); err != nil {
+		return err
+	}
 
-	// Process the DML
-	if err := i.processDML(scanner); err != nil {
-		return fmt.Errorf("reading standard input: %s", err)
+	// The import is complete, a next one starts over.
+	if i.config.Checkpoint != "" {
+		if err := os.Remove(i.config.Checkpoint); err != nil && !os.IsNotExist(err) {
+			return err
+		}
 	}
 
 	// If there were any failed inserts then return an error so that a non-zero
@@ -148,56 +300,101 @@ func (i *Importer) Import() error {
 	return nil
 }
 
-func (i *Importer) processDDL(scanner *bufio.Reader) error {
+// process reads the lines of the file and sends them to the workers in
+// batches. A line protocol file may start with a DDL section, from a
+// "# DDL" to a "# DML" comment, holding queries executed in order before
+// the points are written.
+func (i *Importer) process(scanner *bufio.Reader) error {
+	i.batch = &batch{database: i.database, timeToLive: i.timeToLive}
 	for {
-		line, err := scanner.ReadString(byte('\n'))
-		if err != nil && err != io.EOF {
-			return err
-		} else if err == io.EOF {
-			return nil
-		}
-		// If we find the DML token, we are done with DDL
-		if strings.HasPrefix(line, "# DML") {
+		select {
+		case <-i.closing:
 			return nil
+		default:
 		}
-		if strings.HasPrefix(line, "#") {
-			continue
-		}
-		// Skip blank lines
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		i.queryExecutor(line)
-	}
-}
 
-func (i *Importer) processDML(scanner *bufio.Reader) error {
-	i.startTime = time.Now()
-	for {
-		line, err := scanner.ReadString(byte('\n'))
+		line, err := scanner.ReadBytes('\n')
 		if err != nil && err != io.EOF {
 			return err
-		} else if err == io.EOF {
-			// Call batchWrite one last time to flush anything out in the batch
-			i.batchWrite()
-			return nil
 		}
-		if strings.HasPrefix(line, "# CONTEXT-DATABASE:") {
-			i.batchWrite()
-			i.database = strings.TrimSpace(strings.Split(line, ":")[1])
+		if len(line) > 0 {
+			i.processLine(line)
+			i.offset += int64(len(line))
+			if len(i.batch.points) >= i.config.BatchSize {
+				i.flush()
+			}
 		}
-		if strings.HasPrefix(line, "# CONTEXT-TTL:") {
-			i.batchWrite()
-			i.timeToLive = strings.TrimSpace(strings.Split(line, ":")[1])
+		if err == io.EOF {
+			// Flush anything out in the batch
+			i.flush()
+			return nil
 		}
-		if strings.HasPrefix(line, "#") {
-			continue
+	}
+}
+
+func (i *Importer) processLine(line []byte) {
+	line = trimLine(line)
+
+	// Skip blank lines
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+
+	if i.decoder != nil {
+		p, err := i.decoder.decode(line)
+		if err != nil {
+			i.reject(line, err.Error())
+			return
 		}
-		// Skip blank lines
-		if strings.TrimSpace(line) == "" {
-			continue
+		i.batch.lines = append(i.batch.lines, line)
+		i.batch.points = append(i.batch.points, p)
+		return
+	}
+
+	s := string(line)
+	if strings.HasPrefix(s, "# DDL") && i.seq == 0 && len(i.batch.points) == 0 {
+		i.ddl = true
+	} else if strings.HasPrefix(s, "# DML") {
+		i.ddl = false
+	}
+	if strings.HasPrefix(s, "# CONTEXT-DATABASE:") {
+		i.flush()
+		i.database = strings.TrimSpace(strings.Split(s, ":")[1])
+		i.batch.database = i.database
+	}
+	if strings.HasPrefix(s, "# CONTEXT-TTL:") {
+		i.flush()
+		i.timeToLive = strings.TrimSpace(strings.Split(s, ":")[1])
+		i.batch.timeToLive = i.timeToLive
+	}
+	if strings.HasPrefix(s, "#") {
+		return
+	}
+	if i.ddl {
+		i.queryExecutor(s)
+		return
+	}
+	i.batch.lines = append(i.batch.lines, line)
+	i.batch.points = append(i.batch.points, line)
+}
+
+// flush sends the current batch to the workers. The batch ends at the
+// current offset.
+func (i *Importer) flush() {
+	if len(i.batch.points) > 0 {
+		i.batch.seq = i.seq
+		i.batch.offset = i.offset
+		select {
+		case i.batches <- i.batch:
+			i.seq++
+		case <-i.closing:
 		}
-		i.batchAccumulator(line)
+	}
+	i.batch = &batch{
+		database:   i.database,
+		timeToLive: i.timeToLive,
+		lines:      make([][]byte, 0, i.config.BatchSize),
+		points:     make([][]byte, 0, i.config.BatchSize),
 	}
 }
 
@@ -217,82 +414,94 @@ func (i *Importer) queryExecutor(command string) {
 	i.execute(command)
 }
 
-func (i *Importer) batchAccumulator(line string) {
-	i.batch = append(i.batch, line)
-	if len(i.batch) == batchSize {
-		i.batchWrite()
+// writeBatches writes the batches sent by the reader until it's done. The
+// batches are skipped once the import failed.
+func (i *Importer) writeBatches() {
+	for b := range i.batches {
+		select {
+		case <-i.closing:
+			continue
+		default:
+		}
+
+		if err := i.writeLines(b, 0, len(b.points)); err != nil {
+			i.fail(fmt.Errorf("error writing batch: %s", err))
+			continue
+		}
+		i.done <- b
 	}
 }
 
-func (i *Importer) batchWrite() {
-	// Exit early if there are no points in the batch.
-	if len(i.batch) == 0 {
-		return
+// writeLines writes the lines lo to hi of a batch. The lines are split in
+// halves when the server rejects some of them, until the rejected lines
+// are isolated and sent to the dead-letter file. The lines accepted by a
+// partial write are thus written again, which overwrites the same points.
+func (i *Importer) writeLines(b *batch, lo, hi int) error {
+	err := i.writeWithRetries(b.database, b.timeToLive, b.points[lo:hi])
+	if err == nil {
+		atomic.AddInt64(&i.totalInserts, int64(hi-lo))
+		return nil
 	}
 
-	// Accumulate the batch size to see how many points we have written this second
-	i.throttlePointsWritten += len(i.batch)
-
-	// Find out when we last wrote data
-	since := time.Since(i.lastWrite)
+	werr, ok := err.(*writeError)
+	if !ok || werr.status != http.StatusBadRequest {
+		return err
+	}
+	if hi-lo == 1 {
+		i.reject(b.lines[lo], werr.msg)
+		return nil
+	}
 
-	// Check to see if we've exceeded our points per second for the current timeframe
-	var currentPPS int
-	if since.Seconds() > 0 {
-		currentPPS = int(float64(i.throttlePointsWritten) / since.Seconds())
-	} else {
-		currentPPS = i.throttlePointsWritten
+	mid := (lo + hi) / 2
+	if err := i.writeLines(b, lo, mid); err != nil {
+		return err
 	}
+	return i.writeLines(b, mid, hi)
+}
 
-	// If our currentPPS is greater than the PPS specified, then we wait and retry
-	if int(currentPPS) > i.config.PPS && i.config.PPS != 0 {
-		// Wait for the next tick
-		<-i.throttle.C
+// writeWithRetries writes points, retrying with a backoff when the server
+// is unavailable or throttles the writes.
+func (i *Importer) writeWithRetries(database, timeToLive string, points [][]byte) error {
+	i.throttle.wait(len(points))
 
-		// Decrement the batch size back out as it is going to get called again
-		i.throttlePointsWritten -= len(i.batch)
-		i.batchWrite()
-		return
-	}
+	backoff := time.Second
+	for n := 0; ; n++ {
+		err := i.write(database, timeToLive, points)
+		if err == nil || n == maxRetries {
+			return err
+		}
+		if werr, ok := err.(*writeError); ok && werr.status < http.StatusInternalServerError && werr.status != http.StatusTooManyRequests {
+			return err
+		}
 
-	_, e := i.WriteLineProtocol(strings.Join(i.batch, "\n"), i.database, i.timeToLive, i.config.Precision, i.config.WriteConsistency)
-	if e != nil {
-		i.stderrLogger.Println("error writing batch: ", e)
-		i.stderrLogger.Println(strings.Join(i.batch, "\n"))
-		i.failedInserts += len(i.batch)
-	} else {
-		i.totalInserts += len(i.batch)
+		i.stderrLogger.Printf("error writing batch, retrying in %s: %s\n", backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-i.closing:
+			return err
+		}
+		backoff *= 2
 	}
-	i.throttlePointsWritten = 0
-	i.lastWrite = time.Now()
+}
 
-	// Clear the batch and record the number of processed points.
-	i.batch = i.batch[:0]
-	// Give some status feedback every 100000 lines processed
-	processed := i.totalInserts + i.failedInserts
-	if processed%100000 == 0 {
-		since := time.Since(i.startTime)
-		pps := float64(processed) / since.Seconds()
-		i.stdoutLogger.Printf("Processed %d lines.  Time elapsed: %s.  Points per second (PPS): %d", processed, since.String(), int64(pps))
-	}
+// writeError is an error returned by the server to a write.
+type writeError struct {
+	status int
+	msg    string
 }
 
-type Response struct {
-	Err error
+func (e *writeError) Error() string {
+	return e.msg
 }
 
-// WriteLineProtocol takes a string with line returns to delimit each write
-// If successful, error is nil and Response is nil
-// If an error occurs, Response may contain additional information if populated.
-func (i *Importer) WriteLineProtocol(data, database, timeToLive, precision, writeConsistency string) (*Response, error) {
+// write writes points in line protocol to a database.
+func (i *Importer) write(database, timeToLive string, points [][]byte) error {
 	u := i.config.URL
 	u.Path = path.Join(u.Path, "write")
 
-	r := strings.NewReader(data)
-
-	req, err := http.NewRequest("POST", u.String(), r)
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(bytes.Join(points, []byte("\n"))))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	req.Header.Set("Content-Type", "")
 	req.Header.Set("User-Agent", i.config.ClientConfig.UserAgent)
@@ -302,32 +511,160 @@ func (i *Importer) WriteLineProtocol(data, database, timeToLive, precision, writ
 	params := req.URL.Query()
 	params.Set("db", database)
 	params.Set("ttl", timeToLive)
-	params.Set("precision", precision)
-	params.Set("consistency", writeConsistency)
+	// The points of the CSV and JSON files have nanosecond timestamps.
+	if i.decoder == nil {
+		params.Set("precision", i.config.Precision)
+	}
+	params.Set("consistency", i.config.WriteConsistency)
 	req.URL.RawQuery = params.Encode()
 
-	tr := &http.Transport{
-		Proxy:           i.config.ClientConfig.Proxy,
-		TLSClientConfig: i.config.ClientConfig.TLSConfig,
-	}
-	httpClient := &http.Client{Timeout: i.config.ClientConfig.Timeout, Transport: tr}
-	resp, err := httpClient.Do(req)
+	resp, err := i.httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer resp.Body.Close()
 
-	var response Response
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		err := fmt.Errorf(string(body))
-		response.Err = err
-		return &response, err
+		var response struct {
+			Err string `json:"error"`
+		}
+		if json.Unmarshal(body, &response) != nil || response.Err == "" {
+			response.Err = strings.TrimSpace(string(body))
+		}
+		return &writeError{status: resp.StatusCode, msg: response.Err}
+	}
+	return nil
+}
+
+// reject records a line which can't be written. The line is appended to
+// the dead-letter file after a comment holding the error.
+func (i *Importer) reject(line []byte, msg string) {
+	atomic.AddInt64(&i.failedInserts, 1)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.deadLetter == nil {
+		i.stderrLogger.Printf("error writing line: %s\n%s\n", msg, line)
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# ")
+	buf.WriteString(strings.Replace(msg, "\n", " ", -1))
+	buf.WriteByte('\n')
+	buf.Write(line)
+	buf.WriteByte('\n')
+	if _, err := i.deadLetter.Write(buf.Bytes()); err != nil {
+		i.stderrLogger.Printf("error writing dead letter: %s\n", err)
+	}
+}
+
+// commit saves the checkpoint of the batches written, which is the end of
+// the last one of the batches all written, and gives some status feedback.
+func (i *Importer) commit() {
+	pending := make(map[int64]*batch)
+	var next int64
+	var lines int64
+	for b := range i.done {
+		pending[b.seq] = b
+
+		var last *batch
+		for {
+			b, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			last = b
+
+			// Give some status feedback every 100000 lines processed
+			before := lines
+			lines += int64(len(b.lines))
+			if lines/100000 > before/100000 {
+				since := time.Since(i.startTime)
+				pps := float64(lines) / since.Seconds()
+				i.stdoutLogger.Printf("Processed %d lines.  Time elapsed: %s.  Points per second (PPS): %d", lines, since.String(), int64(pps))
+			}
+		}
+		if last == nil || i.config.Checkpoint == "" {
+			continue
+		}
+
+		cp := &checkpoint{
+			Path:       i.config.Path,
+			Offset:     last.offset,
+			Database:   last.database,
+			TimeToLive: last.timeToLive,
+		}
+		if err := cp.save(i.config.Checkpoint); err != nil {
+			i.fail(fmt.Errorf("saving checkpoint: %s", err))
+		}
 	}
+}
+
+// fail stops the import with an error.
+func (i *Importer) fail(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.err == nil {
+		i.err = err
+		close(i.closing)
+	}
+}
+
+func (i *Importer) error() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.err
+}
+
+// throttle limits the points per second written by the workers.
+type throttle struct {
+	mu   sync.Mutex
+	pps  int
+	next time.Time
+}
+
+// wait waits until n points can be written.
+func (t *throttle) wait(n int) {
+	if t.pps == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	if t.next.Before(now) {
+		t.next = now
+	}
+	d := t.next.Sub(now)
+	t.next = t.next.Add(time.Duration(n) * time.Second / time.Duration(t.pps))
+	t.mu.Unlock()
+	time.Sleep(d)
+}
+
+// newHTTPClient returns the client of the writes, keeping a connection for
+// every worker.
+func newHTTPClient(c *client.HTTPConfig, workers int) *http.Client {
+	tr := &http.Transport{
+		Proxy:               c.Proxy,
+		TLSClientConfig:     c.TLSConfig,
+		MaxIdleConnsPerHost: workers,
+	}
+	if tr.TLSClientConfig == nil {
+		tr.TLSClientConfig = &tls.Config{}
+	}
+	tr.TLSClientConfig.InsecureSkipVerify = c.InsecureSkipVerify
+	return &http.Client{Timeout: c.Timeout, Transport: tr}
+}
 
-	return nil, nil
+// trimLine removes the end of line of a line.
+func trimLine(line []byte) []byte {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r"))
 }
@@ -0,0 +1,108 @@
+package parquet
+
+import (
+	"encoding/binary"
+)
+
+// Types of the Thrift compact protocol.
+const (
+	thriftTrue   = 1
+	thriftFalse  = 2
+	thriftByte   = 3
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes the Thrift structures of the Parquet metadata with
+// the compact protocol.
+type thriftWriter struct {
+	buf  []byte
+	last []int16
+}
+
+// beginStruct starts a struct, which is either the top level one or the
+// value of the field or list element just written.
+func (w *thriftWriter) beginStruct() {
+	w.last = append(w.last, 0)
+}
+
+// endStruct writes the stop field of the current struct.
+func (w *thriftWriter) endStruct() {
+	w.buf = append(w.buf, 0)
+	w.last = w.last[:len(w.last)-1]
+}
+
+// field writes the header of a field of the current struct.
+func (w *thriftWriter) field(id int16, typ byte) {
+	last := &w.last[len(w.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.varint(int64(id))
+	}
+	*last = id
+}
+
+// list writes the header of a list of n elements of type typ.
+func (w *thriftWriter) list(n int, typ byte) {
+	if n < 15 {
+		w.buf = append(w.buf, byte(n)<<4|typ)
+		return
+	}
+	w.buf = append(w.buf, 0xf0|typ)
+	w.uvarint(uint64(n))
+}
+
+func (w *thriftWriter) uvarint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	w.buf = append(w.buf, b[:n]...)
+}
+
+// varint writes a zigzag encoded integer.
+func (w *thriftWriter) varint(v int64) {
+	w.uvarint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (w *thriftWriter) binary(b []byte) {
+	w.uvarint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *thriftWriter) fieldBool(id int16, v bool) {
+	if v {
+		w.field(id, thriftTrue)
+	} else {
+		w.field(id, thriftFalse)
+	}
+}
+
+func (w *thriftWriter) fieldByte(id int16, v int8) {
+	w.field(id, thriftByte)
+	w.buf = append(w.buf, byte(v))
+}
+
+func (w *thriftWriter) fieldI32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(int64(v))
+}
+
+func (w *thriftWriter) fieldI64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(v)
+}
+
+func (w *thriftWriter) fieldString(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.binary([]byte(v))
+}
+
+// fieldEmpty writes a field holding an empty struct.
+func (w *thriftWriter) fieldEmpty(id int16) {
+	w.field(id, thriftStruct)
+	w.buf = append(w.buf, 0)
+}
@@ -0,0 +1,472 @@
+// Package parquet writes Apache Parquet files with a flat schema, streaming
+// the rows as row groups of a bounded size.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Type is the type of the values of a column.
+type Type int
+
+const (
+	// Boolean columns hold bool values.
+	Boolean Type = iota
+	// Int64 columns hold int64 values.
+	Int64
+	// Uint64 columns hold uint64 values.
+	Uint64
+	// Double columns hold float64 values.
+	Double
+	// String columns hold UTF-8 string values.
+	String
+	// Timestamp columns hold time.Time values or int64 nanoseconds, stored
+	// as nanoseconds since the Unix epoch in UTC.
+	Timestamp
+)
+
+// Codec is the compression codec of the pages of a file.
+type Codec int32
+
+const (
+	// Uncompressed pages are stored as is.
+	Uncompressed Codec = 0
+	// Gzip pages are compressed with gzip.
+	Gzip Codec = 2
+)
+
+// DefaultRowGroupSize is the default number of rows of a row group.
+const DefaultRowGroupSize = 64 * 1024
+
+// Physical types, converted types and encodings of the Parquet format.
+const (
+	typeBoolean   = 0
+	typeInt64     = 2
+	typeDouble    = 5
+	typeByteArray = 6
+
+	convertedUTF8   = 0
+	convertedUint64 = 14
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageData = 0
+)
+
+var magic = []byte("PAR1")
+
+// ErrClosed is returned when writing to a closed writer.
+var ErrClosed = errors.New("parquet writer closed")
+
+// Column describes a column of a file.
+type Column struct {
+	Name string
+	Type Type
+
+	// Optional columns may hold null values.
+	Optional bool
+}
+
+// Writer writes rows into a Parquet file. The rows are buffered and written
+// as a row group every RowGroupSize rows, the file metadata being written
+// when the writer is closed.
+type Writer struct {
+	// Codec compresses the pages.
+	Codec Codec
+
+	// RowGroupSize is the number of rows of a row group.
+	RowGroupSize int
+
+	w       io.Writer
+	offset  int64
+	columns []Column
+	buffers []*columnBuffer
+	rows    int
+
+	rowGroups []rowGroup
+	numRows   int64
+	closed    bool
+	err       error
+}
+
+// columnBuffer holds the values of a column of the current row group.
+type columnBuffer struct {
+	defined []bool
+	bools   []bool
+	data    bytes.Buffer
+}
+
+type columnChunk struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+	compressedSize   int64
+}
+
+type rowGroup struct {
+	columns []columnChunk
+	numRows int64
+	size    int64
+}
+
+// NewWriter returns a writer of a file with the given columns into w.
+func NewWriter(w io.Writer, columns []Column) *Writer {
+	buffers := make([]*columnBuffer, len(columns))
+	for i := range buffers {
+		buffers[i] = &columnBuffer{}
+	}
+	return &Writer{
+		Codec:        Gzip,
+		RowGroupSize: DefaultRowGroupSize,
+		w:            w,
+		columns:      columns,
+		buffers:      buffers,
+	}
+}
+
+// Write adds a row holding a value for every column, nil for null values.
+func (w *Writer) Write(row []interface{}) error {
+	if w.err != nil {
+		return w.err
+	} else if w.closed {
+		return ErrClosed
+	} else if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values, want %d", len(row), len(w.columns))
+	}
+
+	for i, v := range row {
+		if err := w.buffers[i].add(w.columns[i], v); err != nil {
+			return err
+		}
+	}
+	w.rows++
+
+	if w.rows >= w.RowGroupSize {
+		return w.Flush()
+	}
+	return nil
+}
+
+// add appends a value of the column to the buffer.
+func (b *columnBuffer) add(c Column, v interface{}) error {
+	if c.Optional {
+		b.defined = append(b.defined, v != nil)
+		if v == nil {
+			return nil
+		}
+	} else if v == nil {
+		return fmt.Errorf("parquet: null value in required column %s", c.Name)
+	}
+
+	var buf [8]byte
+	switch c.Type {
+	case Boolean:
+		if v, ok := v.(bool); ok {
+			b.bools = append(b.bools, v)
+			return nil
+		}
+	case Int64:
+		if v, ok := v.(int64); ok {
+			binary.LittleEndian.PutUint64(buf[:], uint64(v))
+			b.data.Write(buf[:])
+			return nil
+		}
+	case Uint64:
+		if v, ok := v.(uint64); ok {
+			binary.LittleEndian.PutUint64(buf[:], v)
+			b.data.Write(buf[:])
+			return nil
+		}
+	case Double:
+		if v, ok := v.(float64); ok {
+			binary.LittleEndian.PutUint64(buf[:], math.Float64bits(v))
+			b.data.Write(buf[:])
+			return nil
+		}
+	case String:
+		if v, ok := v.(string); ok {
+			binary.LittleEndian.PutUint32(buf[:4], uint32(len(v)))
+			b.data.Write(buf[:4])
+			b.data.WriteString(v)
+			return nil
+		}
+	case Timestamp:
+		switch v := v.(type) {
+		case time.Time:
+			binary.LittleEndian.PutUint64(buf[:], uint64(v.UnixNano()))
+			b.data.Write(buf[:])
+			return nil
+		case int64:
+			binary.LittleEndian.PutUint64(buf[:], uint64(v))
+			b.data.Write(buf[:])
+			return nil
+		}
+	}
+	return fmt.Errorf("parquet: invalid value %T for column %s", v, c.Name)
+}
+
+// Flush writes the buffered rows as a row group.
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	} else if w.rows == 0 {
+		return nil
+	}
+
+	if w.offset == 0 {
+		if w.err = w.write(magic); w.err != nil {
+			return w.err
+		}
+	}
+
+	rg := rowGroup{numRows: int64(w.rows)}
+	for i, b := range w.buffers {
+		cc, err := w.writePage(w.columns[i], b, w.rows)
+		if err != nil {
+			w.err = err
+			return err
+		}
+		rg.columns = append(rg.columns, cc)
+		rg.size += cc.uncompressedSize
+		w.buffers[i] = &columnBuffer{}
+	}
+	w.rowGroups = append(w.rowGroups, rg)
+	w.numRows += rg.numRows
+	w.rows = 0
+	return nil
+}
+
+// writePage writes the values of a column of the row group as a single
+// data page.
+func (w *Writer) writePage(c Column, b *columnBuffer, n int) (columnChunk, error) {
+	var page bytes.Buffer
+	if c.Optional {
+		levels := encodeBits(b.defined)
+		var size [4]byte
+		binary.LittleEndian.PutUint32(size[:], uint32(len(levels)))
+		page.Write(size[:])
+		page.Write(levels)
+	}
+	if c.Type == Boolean {
+		page.Write(packBits(b.bools))
+	} else {
+		page.Write(b.data.Bytes())
+	}
+
+	data := page.Bytes()
+	if w.Codec == Gzip {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(data); err != nil {
+			return columnChunk{}, err
+		} else if err := zw.Close(); err != nil {
+			return columnChunk{}, err
+		}
+		data = buf.Bytes()
+	}
+
+	var t thriftWriter
+	t.beginStruct()
+	t.fieldI32(1, pageData)
+	t.fieldI32(2, int32(page.Len()))
+	t.fieldI32(3, int32(len(data)))
+	t.field(5, thriftStruct)
+	t.beginStruct()
+	t.fieldI32(1, int32(n))
+	t.fieldI32(2, encodingPlain)
+	t.fieldI32(3, encodingRLE)
+	t.fieldI32(4, encodingRLE)
+	t.endStruct()
+	t.endStruct()
+	header := len(t.buf)
+
+	cc := columnChunk{
+		offset:           w.offset,
+		numValues:        int64(n),
+		uncompressedSize: int64(header + page.Len()),
+		compressedSize:   int64(header + len(data)),
+	}
+	if err := w.write(t.buf); err != nil {
+		return cc, err
+	}
+	return cc, w.write(data)
+}
+
+// encodeBits encodes definition levels of a bit width of one with the RLE
+// and bit-packing hybrid encoding, as a single bit-packed run.
+func encodeBits(v []bool) []byte {
+	var header [binary.MaxVarintLen64]byte
+	groups := (len(v) + 7) / 8
+	n := binary.PutUvarint(header[:], uint64(groups)<<1|1)
+	return append(header[:n], packBits(v)...)
+}
+
+// packBits packs booleans into bits, least significant bit first.
+func packBits(v []bool) []byte {
+	b := make([]byte, (len(v)+7)/8)
+	for i, set := range v {
+		if set {
+			b[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return b
+}
+
+// write writes p into the file.
+func (w *Writer) write(p []byte) error {
+	n, err := w.w.Write(p)
+	w.offset += int64(n)
+	return err
+}
+
+// Close writes the buffered rows and the file metadata. It doesn't close
+// the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	} else if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	if w.offset == 0 {
+		if err := w.write(magic); err != nil {
+			return err
+		}
+	}
+
+	var t thriftWriter
+	t.beginStruct()
+	t.fieldI32(1, 1)
+
+	t.field(2, thriftList)
+	t.list(len(w.columns)+1, thriftStruct)
+	t.beginStruct()
+	t.fieldString(4, "schema")
+	t.fieldI32(5, int32(len(w.columns)))
+	t.endStruct()
+	for _, c := range w.columns {
+		writeSchemaElement(&t, c)
+	}
+
+	t.fieldI64(3, w.numRows)
+
+	t.field(4, thriftList)
+	t.list(len(w.rowGroups), thriftStruct)
+	for _, rg := range w.rowGroups {
+		t.beginStruct()
+		t.field(1, thriftList)
+		t.list(len(rg.columns), thriftStruct)
+		for i, cc := range rg.columns {
+			writeColumnChunk(&t, w.columns[i], cc, w.Codec)
+		}
+		t.fieldI64(2, rg.size)
+		t.fieldI64(3, rg.numRows)
+		t.endStruct()
+	}
+
+	t.fieldString(6, "cnosdb")
+	t.endStruct()
+
+	footer := t.buf
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(footer)))
+	for _, p := range [][]byte{footer, size[:], magic} {
+		if err := w.write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSchemaElement writes the schema element of a column.
+func writeSchemaElement(t *thriftWriter, c Column) {
+	t.beginStruct()
+	t.fieldI32(1, physicalType(c.Type))
+	if c.Optional {
+		t.fieldI32(3, repetitionOptional)
+	} else {
+		t.fieldI32(3, repetitionRequired)
+	}
+	t.fieldString(4, c.Name)
+
+	switch c.Type {
+	case String:
+		t.fieldI32(6, convertedUTF8)
+		t.field(10, thriftStruct)
+		t.beginStruct()
+		t.fieldEmpty(1)
+		t.endStruct()
+	case Uint64:
+		t.fieldI32(6, convertedUint64)
+		t.field(10, thriftStruct)
+		t.beginStruct()
+		t.field(10, thriftStruct)
+		t.beginStruct()
+		t.fieldByte(1, 64)
+		t.fieldBool(2, false)
+		t.endStruct()
+		t.endStruct()
+	case Timestamp:
+		t.field(10, thriftStruct)
+		t.beginStruct()
+		t.field(8, thriftStruct)
+		t.beginStruct()
+		t.fieldBool(1, true)
+		t.field(2, thriftStruct)
+		t.beginStruct()
+		t.fieldEmpty(3)
+		t.endStruct()
+		t.endStruct()
+		t.endStruct()
+	}
+	t.endStruct()
+}
+
+// writeColumnChunk writes the metadata of a column chunk.
+func writeColumnChunk(t *thriftWriter, c Column, cc columnChunk, codec Codec) {
+	t.beginStruct()
+	t.fieldI64(2, cc.offset)
+	t.field(3, thriftStruct)
+	t.beginStruct()
+	t.fieldI32(1, physicalType(c.Type))
+	t.field(2, thriftList)
+	t.list(2, thriftI32)
+	t.varint(encodingPlain)
+	t.varint(encodingRLE)
+	t.field(3, thriftList)
+	t.list(1, thriftBinary)
+	t.binary([]byte(c.Name))
+	t.fieldI32(4, int32(codec))
+	t.fieldI64(5, cc.numValues)
+	t.fieldI64(6, cc.uncompressedSize)
+	t.fieldI64(7, cc.compressedSize)
+	t.fieldI64(9, cc.offset)
+	t.endStruct()
+	t.endStruct()
+}
+
+// physicalType returns the physical type storing the values of a type.
+func physicalType(typ Type) int32 {
+	switch typ {
+	case Boolean:
+		return typeBoolean
+	case Double:
+		return typeDouble
+	case String:
+		return typeByteArray
+	default:
+		return typeInt64
+	}
+}
@@ -0,0 +1,375 @@
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// thriftReader decodes Thrift structures encoded with the compact protocol
+// into maps of the field IDs to the values.
+type thriftReader struct {
+	buf []byte
+	pos int
+	err bool
+}
+
+func (r *thriftReader) byte() byte {
+	if r.pos >= len(r.buf) {
+		r.err = true
+		return 0
+	}
+	b := r.buf[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	if r.pos >= len(r.buf) {
+		r.err = true
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf[r.pos:])
+	if n <= 0 {
+		r.err = true
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) readStruct() map[int16]interface{} {
+	fields := make(map[int16]interface{})
+	var last int16
+	for !r.err {
+		h := r.byte()
+		if h == 0 {
+			break
+		}
+		id := last + int16(h>>4)
+		if h>>4 == 0 {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(h & 0x0f)
+		last = id
+	}
+	return fields
+}
+
+func (r *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case thriftTrue:
+		return true
+	case thriftFalse:
+		return false
+	case thriftByte:
+		return int64(int8(r.byte()))
+	case thriftI32, thriftI64:
+		return r.varint()
+	case thriftBinary:
+		n := int(r.uvarint())
+		if r.pos+n > len(r.buf) {
+			r.err = true
+			return nil
+		}
+		r.pos += n
+		return string(r.buf[r.pos-n : r.pos])
+	case thriftList:
+		h := r.byte()
+		n := int(h >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = r.value(h & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return r.readStruct()
+	}
+	r.err = true
+	return nil
+}
+
+// decodeStruct decodes the Thrift structure starting at the beginning of b,
+// and returns it with its encoded size.
+func decodeStruct(t *testing.T, b []byte) (map[int16]interface{}, int) {
+	t.Helper()
+	r := &thriftReader{buf: b}
+	v := r.readStruct()
+	if r.err {
+		t.Fatal("invalid thrift structure")
+	}
+	return v, r.pos
+}
+
+// field returns the value of the field at the path of field IDs and list
+// indexes of a decoded structure.
+func field(v interface{}, path ...int) interface{} {
+	for _, i := range path {
+		switch x := v.(type) {
+		case map[int16]interface{}:
+			v = x[int16(i)]
+		case []interface{}:
+			if i >= len(x) {
+				return nil
+			}
+			v = x[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// readFooter checks the magic numbers of a file, and returns its decoded
+// metadata.
+func readFooter(t *testing.T, b []byte) map[int16]interface{} {
+	t.Helper()
+	if len(b) < 12 || !bytes.HasPrefix(b, magic) || !bytes.HasSuffix(b, magic) {
+		t.Fatalf("invalid magic numbers: %q", b)
+	}
+	size := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	footer := b[len(b)-8-size : len(b)-8]
+	meta, n := decodeStruct(t, footer)
+	if n != size {
+		t.Fatalf("got a footer of %d bytes, exp %d", n, size)
+	}
+	return meta
+}
+
+// readColumnChunk reads the data page of a column chunk, described by its
+// metadata, and returns the decoded values.
+func readColumnChunk(t *testing.T, b []byte, c Column, chunk interface{}) []interface{} {
+	t.Helper()
+	offset := field(chunk, 3, 9).(int64)
+	compressed := field(chunk, 3, 7).(int64)
+	uncompressed := field(chunk, 3, 6).(int64)
+	if field(chunk, 2).(int64) != offset {
+		t.Fatalf("got file offset %v, exp the data page offset %d", field(chunk, 2), offset)
+	}
+
+	chunkData := b[offset : offset+compressed]
+	header, n := decodeStruct(t, chunkData)
+	if typ := field(header, 1); typ != int64(pageData) {
+		t.Fatalf("got page type %v, exp a data page", typ)
+	} else if size := field(header, 3); size != int64(len(chunkData)-n) {
+		t.Fatalf("got compressed page size %v, exp %d", size, len(chunkData)-n)
+	} else if size := field(header, 2); size != uncompressed-int64(n) {
+		t.Fatalf("got uncompressed page size %v, exp %d", size, uncompressed-int64(n))
+	}
+
+	page := chunkData[n:]
+	if field(chunk, 3, 4) == int64(Gzip) {
+		zr, err := gzip.NewReader(bytes.NewReader(page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page, err = ioutil.ReadAll(zr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if int64(len(page)) != uncompressed-int64(n) {
+		t.Fatalf("got %d uncompressed bytes, exp %d", len(page), uncompressed-int64(n))
+	}
+
+	numValues := int(field(header, 5, 1).(int64))
+	defined := make([]bool, numValues)
+	for i := range defined {
+		defined[i] = true
+	}
+	if c.Optional {
+		size := int(binary.LittleEndian.Uint32(page))
+		levels := page[4 : 4+size]
+		page = page[4+size:]
+
+		runHeader, m := binary.Uvarint(levels)
+		if runHeader&1 != 1 || int(runHeader>>1) != (numValues+7)/8 {
+			t.Fatalf("got run header %d, exp a bit-packed run of %d values", runHeader, numValues)
+		}
+		for i := range defined {
+			defined[i] = levels[m+i/8]&(1<<uint(i%8)) != 0
+		}
+	}
+
+	values := make([]interface{}, numValues)
+	var j int
+	for i := range values {
+		if !defined[i] {
+			continue
+		}
+		switch c.Type {
+		case Boolean:
+			values[i] = page[j/8]&(1<<uint(j%8)) != 0
+			j++
+		case Int64:
+			values[i] = int64(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+		case Uint64:
+			values[i] = binary.LittleEndian.Uint64(page)
+			page = page[8:]
+		case Double:
+			values[i] = math.Float64frombits(binary.LittleEndian.Uint64(page))
+			page = page[8:]
+		case String:
+			size := int(binary.LittleEndian.Uint32(page))
+			values[i] = string(page[4 : 4+size])
+			page = page[4+size:]
+		case Timestamp:
+			values[i] = time.Unix(0, int64(binary.LittleEndian.Uint64(page))).UTC()
+			page = page[8:]
+		}
+	}
+	return values
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	columns := []Column{
+		{Name: "metric", Type: String},
+		{Name: "time", Type: Timestamp},
+		{Name: "value", Type: Double, Optional: true},
+		{Name: "count", Type: Int64},
+		{Name: "total", Type: Uint64, Optional: true},
+		{Name: "ok", Type: Boolean},
+	}
+	ts := time.Unix(0, 1000).UTC()
+	rows := [][]interface{}{
+		{"cpu", ts, 1.5, int64(-1), uint64(10), true},
+		{"cpu", ts.Add(time.Second), nil, int64(2), nil, false},
+		{"mem", int64(3000), 2.5, int64(3), uint64(30), true},
+	}
+
+	for _, codec := range []Codec{Gzip, Uncompressed} {
+		var buf bytes.Buffer
+		w := NewWriter(&buf, columns)
+		w.Codec = codec
+		w.RowGroupSize = 2
+		for _, row := range rows {
+			if err := w.Write(row); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		b := buf.Bytes()
+		meta := readFooter(t, b)
+		if v := field(meta, 1); v != int64(1) {
+			t.Fatalf("got version %v, exp 1", v)
+		} else if v := field(meta, 3); v != int64(len(rows)) {
+			t.Fatalf("got %v rows, exp %d", v, len(rows))
+		} else if v := field(meta, 6); v != "cnosdb" {
+			t.Fatalf("got created by %v", v)
+		}
+
+		// The root of the schema is followed by an element for every column.
+		schema := field(meta, 2).([]interface{})
+		if len(schema) != len(columns)+1 {
+			t.Fatalf("got %d schema elements, exp %d", len(schema), len(columns)+1)
+		} else if field(schema, 0, 4) != "schema" || field(schema, 0, 5) != int64(len(columns)) {
+			t.Fatalf("invalid schema root: %v", schema[0])
+		}
+		for i, c := range columns {
+			el := schema[i+1]
+			repetition := int64(repetitionRequired)
+			if c.Optional {
+				repetition = repetitionOptional
+			}
+			if field(el, 4) != c.Name || field(el, 1) != int64(physicalType(c.Type)) || field(el, 3) != repetition {
+				t.Fatalf("invalid schema element of column %s: %v", c.Name, el)
+			}
+		}
+		if v := field(schema, 1, 6); v != int64(convertedUTF8) {
+			t.Fatalf("got converted type %v of the string column", v)
+		} else if v := field(schema, 2, 10, 8, 1); v != true {
+			t.Fatalf("got timestamp adjusted to UTC %v", v)
+		} else if v := field(schema, 5, 10, 10, 1); v != int64(64) {
+			t.Fatalf("got integer bit width %v of the uint64 column", v)
+		}
+
+		// The rows are written as row groups of 2 rows.
+		rowGroups := field(meta, 4).([]interface{})
+		if len(rowGroups) != 2 {
+			t.Fatalf("got %d row groups, exp 2", len(rowGroups))
+		}
+		var got [][]interface{}
+		for _, rg := range rowGroups {
+			chunks := field(rg, 1).([]interface{})
+			if len(chunks) != len(columns) {
+				t.Fatalf("got %d column chunks, exp %d", len(chunks), len(columns))
+			}
+
+			n := int(field(rg, 3).(int64))
+			groupRows := make([][]interface{}, n)
+			for i := range groupRows {
+				groupRows[i] = make([]interface{}, len(columns))
+			}
+			for i, chunk := range chunks {
+				if field(chunk, 3, 4) != int64(codec) {
+					t.Fatalf("got codec %v, exp %d", field(chunk, 3, 4), codec)
+				} else if field(chunk, 3, 3, 0) != columns[i].Name {
+					t.Fatalf("got path %v, exp %s", field(chunk, 3, 3), columns[i].Name)
+				} else if field(chunk, 3, 5) != int64(n) {
+					t.Fatalf("got %v values, exp %d", field(chunk, 3, 5), n)
+				}
+				for j, v := range readColumnChunk(t, b, columns[i], chunk) {
+					groupRows[j][i] = v
+				}
+			}
+			got = append(got, groupRows...)
+		}
+
+		exp := [][]interface{}{
+			{"cpu", ts, 1.5, int64(-1), uint64(10), true},
+			{"cpu", ts.Add(time.Second), nil, int64(2), nil, false},
+			{"mem", time.Unix(0, 3000).UTC(), 2.5, int64(3), uint64(30), true},
+		}
+		if !reflect.DeepEqual(got, exp) {
+			t.Fatalf("codec %d: got rows %v, exp %v", codec, got, exp)
+		}
+	}
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, []Column{{Name: "metric", Type: String}})
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	meta := readFooter(t, buf.Bytes())
+	if v := field(meta, 3); v != int64(0) {
+		t.Fatalf("got %v rows, exp 0", v)
+	} else if v := field(meta, 4).([]interface{}); len(v) != 0 {
+		t.Fatalf("got %d row groups, exp 0", len(v))
+	}
+}
+
+func TestWriter_InvalidRow(t *testing.T) {
+	w := NewWriter(ioutil.Discard, []Column{{Name: "metric", Type: String}, {Name: "value", Type: Double}})
+	if err := w.Write([]interface{}{"cpu"}); err == nil {
+		t.Fatal("expected an error writing a row missing values")
+	} else if err := w.Write([]interface{}{"cpu", nil}); err == nil {
+		t.Fatal("expected an error writing a null value in a required column")
+	} else if err := w.Write([]interface{}{"cpu", int64(1)}); err == nil {
+		t.Fatal("expected an error writing a value of the wrong type")
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if err := w.Write([]interface{}{"cpu", 1.0}); err != ErrClosed {
+		t.Fatalf("got error %v, exp %v", err, ErrClosed)
+	}
+}
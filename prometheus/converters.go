@@ -0,0 +1,246 @@
+// Package prometheus converts between the Prometheus remote storage protocol
+// and CnosDB points and queries, and renders statistics as Prometheus metrics.
+package prometheus
+
+//go:generate protoc -I remote --gogo_out=remote remote/remote.proto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/prometheus/remote"
+	"github.com/cnosdatabase/cnosql"
+	"github.com/cnosdatabase/db/models"
+)
+
+const (
+	// metricLabel is the label holding the name of a Prometheus metric.
+	metricLabel = "__name__"
+
+	// fieldName is the field that holds the value of a Prometheus sample.
+	fieldName = "value"
+)
+
+var (
+	// ErrNaNDropped is returned when samples with NaN or infinite values
+	// were dropped, as they can't be stored.
+	ErrNaNDropped = errors.New("dropped NaN or infinite values from the Prometheus write request")
+
+	// ErrMultipleQueries is returned when a read request has more than one query.
+	ErrMultipleQueries = errors.New("the Prometheus read endpoint only supports one query at a time")
+)
+
+// WriteRequestToPoints converts a Prometheus remote write request to points.
+// The metric name becomes the name of the points, the other labels become
+// tags, and the sample values are written to the "value" field.
+//
+// Samples with NaN or infinite values are dropped, in which case the points
+// are returned with ErrNaNDropped.
+func WriteRequestToPoints(req *remote.WriteRequest) ([]models.Point, error) {
+	var maxPoints int
+	for _, ts := range req.Timeseries {
+		maxPoints += len(ts.Samples)
+	}
+	points := make([]models.Point, 0, maxPoints)
+
+	var dropped bool
+	for _, ts := range req.Timeseries {
+		name := ""
+		tags := make(map[string]string, len(ts.Labels))
+		for _, l := range ts.Labels {
+			if l.Name == metricLabel {
+				name = l.Value
+				continue
+			}
+			tags[l.Name] = l.Value
+		}
+		if name == "" {
+			return nil, fmt.Errorf("time series has no %s label", metricLabel)
+		}
+
+		for _, s := range ts.Samples {
+			if math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+				dropped = true
+				continue
+			}
+
+			p, err := models.NewPoint(name, models.NewTags(tags), models.Fields{fieldName: s.Value}, time.Unix(0, s.Timestamp*int64(time.Millisecond)))
+			if err != nil {
+				return nil, err
+			}
+			points = append(points, p)
+		}
+	}
+
+	if dropped {
+		return points, ErrNaNDropped
+	}
+	return points, nil
+}
+
+// ReadRequestToCnosQLStatement converts a Prometheus remote read request to
+// a statement selecting the "value" field of the matching series, grouped by
+// series. The metric name matcher selects the metrics and the other matchers
+// become conditions on the tags.
+func ReadRequestToCnosQLStatement(req *remote.ReadRequest, database, ttl string) (*cnosql.SelectStatement, error) {
+	if len(req.Queries) != 1 {
+		return nil, ErrMultipleQueries
+	}
+	q := req.Queries[0]
+
+	src := &cnosql.Metric{
+		Database:   database,
+		TimeToLive: ttl,
+		Regex:      &cnosql.RegexLiteral{Val: regexp.MustCompile(".+")},
+	}
+
+	cond := timeCondition(q.StartTimestampMs, q.EndTimestampMs)
+	for _, m := range q.Matchers {
+		if m.Name == metricLabel {
+			switch m.Type {
+			case remote.LabelMatcher_EQ:
+				src.Name, src.Regex = m.Value, nil
+				continue
+			case remote.LabelMatcher_RE:
+				re, err := anchoredRegex(m.Value)
+				if err != nil {
+					return nil, err
+				}
+				src.Regex = &cnosql.RegexLiteral{Val: re}
+				continue
+			}
+		}
+
+		expr, err := matcherCondition(m)
+		if err != nil {
+			return nil, err
+		}
+		cond = &cnosql.BinaryExpr{Op: cnosql.AND, LHS: cond, RHS: expr}
+	}
+
+	return &cnosql.SelectStatement{
+		Fields:     []*cnosql.Field{{Expr: &cnosql.VarRef{Val: fieldName}}},
+		Sources:    []cnosql.Source{src},
+		Condition:  cond,
+		Dimensions: []*cnosql.Dimension{{Expr: &cnosql.Wildcard{}}},
+	}, nil
+}
+
+// timeCondition returns the condition selecting the time range of a query.
+func timeCondition(start, end int64) cnosql.Expr {
+	return &cnosql.BinaryExpr{
+		Op: cnosql.AND,
+		LHS: &cnosql.BinaryExpr{
+			Op:  cnosql.GTE,
+			LHS: &cnosql.VarRef{Val: "time"},
+			RHS: &cnosql.TimeLiteral{Val: time.Unix(0, start*int64(time.Millisecond)).UTC()},
+		},
+		RHS: &cnosql.BinaryExpr{
+			Op:  cnosql.LTE,
+			LHS: &cnosql.VarRef{Val: "time"},
+			RHS: &cnosql.TimeLiteral{Val: time.Unix(0, end*int64(time.Millisecond)).UTC()},
+		},
+	}
+}
+
+// matcherCondition converts a label matcher to a condition on a tag. The
+// metric name is matched with the "_name" tag.
+func matcherCondition(m *remote.LabelMatcher) (cnosql.Expr, error) {
+	key := &cnosql.VarRef{Val: m.Name, Type: cnosql.Tag}
+	if m.Name == metricLabel {
+		key = &cnosql.VarRef{Val: "_name"}
+	}
+
+	switch m.Type {
+	case remote.LabelMatcher_EQ:
+		return &cnosql.BinaryExpr{Op: cnosql.EQ, LHS: key, RHS: &cnosql.StringLiteral{Val: m.Value}}, nil
+	case remote.LabelMatcher_NEQ:
+		return &cnosql.BinaryExpr{Op: cnosql.NEQ, LHS: key, RHS: &cnosql.StringLiteral{Val: m.Value}}, nil
+	case remote.LabelMatcher_RE, remote.LabelMatcher_NRE:
+		re, err := anchoredRegex(m.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		op := cnosql.EQREGEX
+		if m.Type == remote.LabelMatcher_NRE {
+			op = cnosql.NEQREGEX
+		}
+		return &cnosql.BinaryExpr{Op: op, LHS: key, RHS: &cnosql.RegexLiteral{Val: re}}, nil
+	default:
+		return nil, fmt.Errorf("unknown label matcher type: %v", m.Type)
+	}
+}
+
+// anchoredRegex compiles a Prometheus regular expression, which always
+// matches the whole label value.
+func anchoredRegex(expr string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %q: %s", expr, err)
+	}
+	return re, nil
+}
+
+// RowsToQueryResult converts the rows selected by a statement returned by
+// ReadRequestToCnosQLStatement to a Prometheus query result.
+func RowsToQueryResult(rows []*models.Row) (*remote.QueryResult, error) {
+	result := &remote.QueryResult{}
+	for _, row := range rows {
+		ts := &remote.TimeSeries{
+			Labels:  tagsToLabels(row.Name, row.Tags),
+			Samples: make([]*remote.Sample, 0, len(row.Values)),
+		}
+
+		for _, v := range row.Values {
+			if len(v) != 2 {
+				return nil, fmt.Errorf("unexpected number of columns: %d", len(v))
+			}
+
+			t, ok := v[0].(time.Time)
+			if !ok {
+				return nil, fmt.Errorf("unexpected time type: %T", v[0])
+			}
+
+			var value float64
+			switch val := v[1].(type) {
+			case nil:
+				continue
+			case float64:
+				value = val
+			case int64:
+				value = float64(val)
+			default:
+				return nil, fmt.Errorf("unexpected value type: %T", v[1])
+			}
+
+			ts.Samples = append(ts.Samples, &remote.Sample{
+				Value:     value,
+				Timestamp: t.UnixNano() / int64(time.Millisecond),
+			})
+		}
+
+		if len(ts.Samples) > 0 {
+			result.Timeseries = append(result.Timeseries, ts)
+		}
+	}
+	return result, nil
+}
+
+// tagsToLabels returns the labels of a series sorted by name, with the
+// metric name as the "__name__" label. Empty tags are not labels.
+func tagsToLabels(name string, tags map[string]string) []*remote.Label {
+	labels := make([]*remote.Label, 0, len(tags)+1)
+	labels = append(labels, &remote.Label{Name: metricLabel, Value: name})
+	for k, v := range tags {
+		if v != "" {
+			labels = append(labels, &remote.Label{Name: k, Value: v})
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return labels[i].Name < labels[j].Name })
+	return labels
+}
@@ -0,0 +1,293 @@
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cnosdatabase/db/models"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const (
+	counterType = "counter"
+	gaugeType   = "gauge"
+
+	// namespace prefixes the name of every metric rendered from a statistic.
+	namespace = "cnosdb"
+)
+
+// startTime is the time the process started, as reported by the
+// process_start_time_seconds metric.
+var startTime = time.Now()
+
+// gaugeKeys are the statistic values that can go down, which are not
+// recognized by their name.
+var gaugeKeys = map[string]bool{
+	"cacheAgeMs":              true,
+	"cachedBytes":             true,
+	"currentSegmentDiskBytes": true,
+	"diskBytes":               true,
+	"healthy":                 true,
+	"lagMs":                   true,
+	"memBytes":                true,
+	"oldSegmentsDiskBytes":    true,
+	"queueBytes":              true,
+
+	// Go memstats of the runtime statistic.
+	"Alloc":        true,
+	"Sys":          true,
+	"HeapAlloc":    true,
+	"HeapSys":      true,
+	"HeapIdle":     true,
+	"HeapInUse":    true,
+	"HeapReleased": true,
+	"HeapObjects":  true,
+}
+
+// metricType returns the type of the metric of a statistic value. Values
+// counting things that are currently active, queued or present are gauges,
+// all other values are cumulative counters.
+func metricType(key string) string {
+	switch {
+	case gaugeKeys[key],
+		strings.HasPrefix(key, "num"), strings.HasPrefix(key, "Num") && key != "NumGC",
+		strings.HasPrefix(key, "is"),
+		strings.HasSuffix(key, "Active"), strings.HasSuffix(key, "Queue"):
+		return gaugeType
+	default:
+		return counterType
+	}
+}
+
+// sample is a single sample of a metric.
+type sample struct {
+	labels string
+	value  float64
+}
+
+// family is a metric and all of its samples.
+type family struct {
+	name    string
+	typ     string
+	help    string
+	samples []sample
+}
+
+// metricSet groups samples by metric so that each metric is written once.
+type metricSet map[string]*family
+
+func (m metricSet) add(name, typ, help, labels string, value float64) {
+	f := m[name]
+	if f == nil {
+		f = &family{name: name, typ: typ, help: help}
+		m[name] = f
+	}
+	f.samples = append(f.samples, sample{labels: labels, value: value})
+}
+
+// WriteMetrics writes statistics in the Prometheus text exposition format,
+// followed by metrics of the Go runtime and of the process.
+//
+// Each statistic value becomes a metric named after the statistic and the
+// value, such as cnosdb_httpd_write_req_total, and the statistic tags become
+// its labels. Values that aren't numbers are skipped.
+func WriteMetrics(w io.Writer, stats []models.Statistic) error {
+	set := make(metricSet)
+	for _, s := range stats {
+		labels := formatLabels(s.Tags)
+		for k, v := range s.Values {
+			value, ok := toFloat(v)
+			if !ok {
+				continue
+			}
+
+			typ := metricType(k)
+			name := namespace + "_" + snakeCase(s.Name) + "_" + snakeCase(k)
+			if typ == counterType {
+				name += "_total"
+			}
+			set.add(name, typ, fmt.Sprintf("Value %s of the %s statistic.", k, s.Name), labels, value)
+		}
+	}
+	addRuntimeMetrics(set)
+	addProcessMetrics(set)
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		f := set[name]
+		sort.SliceStable(f.samples, func(i, j int) bool { return f.samples[i].labels < f.samples[j].labels })
+
+		fmt.Fprintf(bw, "# HELP %s %s\n", f.name, f.help)
+		fmt.Fprintf(bw, "# TYPE %s %s\n", f.name, f.typ)
+		for _, s := range f.samples {
+			bw.WriteString(f.name)
+			bw.WriteString(s.labels)
+			bw.WriteByte(' ')
+			bw.WriteString(formatValue(s.value))
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// addRuntimeMetrics adds the metrics of the Go runtime.
+func addRuntimeMetrics(set metricSet) {
+	var rt runtime.MemStats
+	runtime.ReadMemStats(&rt)
+
+	set.add("go_info", gaugeType, "Information about the Go environment.", formatLabels(map[string]string{"version": runtime.Version()}), 1)
+	set.add("go_goroutines", gaugeType, "Number of goroutines that currently exist.", "", float64(runtime.NumGoroutine()))
+	set.add("go_memstats_alloc_bytes", gaugeType, "Number of bytes allocated and still in use.", "", float64(rt.Alloc))
+	set.add("go_memstats_alloc_bytes_total", counterType, "Total number of bytes allocated, even if freed.", "", float64(rt.TotalAlloc))
+	set.add("go_memstats_sys_bytes", gaugeType, "Number of bytes obtained from system.", "", float64(rt.Sys))
+	set.add("go_memstats_mallocs_total", counterType, "Total number of mallocs.", "", float64(rt.Mallocs))
+	set.add("go_memstats_frees_total", counterType, "Total number of frees.", "", float64(rt.Frees))
+	set.add("go_memstats_heap_alloc_bytes", gaugeType, "Number of heap bytes allocated and still in use.", "", float64(rt.HeapAlloc))
+	set.add("go_memstats_heap_idle_bytes", gaugeType, "Number of heap bytes waiting to be used.", "", float64(rt.HeapIdle))
+	set.add("go_memstats_heap_inuse_bytes", gaugeType, "Number of heap bytes that are in use.", "", float64(rt.HeapInuse))
+	set.add("go_memstats_heap_objects", gaugeType, "Number of allocated objects.", "", float64(rt.HeapObjects))
+	set.add("go_memstats_heap_released_bytes", gaugeType, "Number of heap bytes released to OS.", "", float64(rt.HeapReleased))
+	set.add("go_memstats_next_gc_bytes", gaugeType, "Number of heap bytes when next garbage collection will take place.", "", float64(rt.NextGC))
+	set.add("go_gc_cycles_total", counterType, "Number of completed GC cycles.", "", float64(rt.NumGC))
+	set.add("go_gc_pause_seconds_total", counterType, "Total time spent in GC stop-the-world pauses.", "", float64(rt.PauseTotalNs)/1e9)
+}
+
+// addProcessMetrics adds the metrics of the process. Metrics that can't be
+// read on this platform are left out.
+func addProcessMetrics(set metricSet) {
+	set.add("process_start_time_seconds", gaugeType, "Start time of the process since unix epoch in seconds.", "", float64(startTime.UnixNano())/1e9)
+	if cpu, ok := processCPUTime(); ok {
+		set.add("process_cpu_seconds_total", counterType, "Total user and system CPU time spent in seconds.", "", cpu.Seconds())
+	}
+	if fds, err := ioutil.ReadDir("/proc/self/fd"); err == nil {
+		set.add("process_open_fds", gaugeType, "Number of open file descriptors.", "", float64(len(fds)))
+	}
+}
+
+// formatLabels returns tags as a label set, or an empty string if there
+// are no tags. Empty tags are not labels.
+func formatLabels(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k, v := range tags {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(labelName(k))
+		b.WriteString(`="`)
+		b.WriteString(labelValueReplacer.Replace(tags[k]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelName replaces the characters that aren't allowed in a label name.
+func labelName(s string) string {
+	name := []rune(s)
+	for i, r := range name {
+		if !isNameRune(r, i) {
+			name[i] = '_'
+		}
+	}
+	return string(name)
+}
+
+// snakeCase converts a statistic name or key, such as queryExecutor or
+// WALCompactionTimeMs, to a metric name part, such as query_executor or
+// wal_compaction_time_ms.
+func snakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+
+		if !isNameRune(r, 1) || r == ':' {
+			r = '_'
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// isNameRune returns true if r is allowed at position i of a metric name.
+func isNameRune(r rune, i int) bool {
+	return r == '_' || r == ':' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9' && i > 0)
+}
+
+// toFloat returns a statistic value as a float.
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// formatValue formats a sample value.
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
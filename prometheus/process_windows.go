@@ -0,0 +1,8 @@
+package prometheus
+
+import "time"
+
+// processCPUTime returns false as the CPU time of the process isn't read on Windows.
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,59 @@
+package ae
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cnosdatabase/cnosdb/pkg/network"
+)
+
+// Client provides an API for the anti-entropy service.
+type Client struct {
+	host string
+}
+
+// NewClient returns a new *Client.
+func NewClient(host string) *Client {
+	return &Client{host: host}
+}
+
+// Status returns the status of the shard replicas on the node.
+func (c *Client) Status() ([]ShardStatus, error) {
+	resp, err := c.doRequest(&Request{Type: RequestStatus})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Shards, nil
+}
+
+// Repair checks a shard replica on the node and queues its repair if it diverged.
+func (c *Client) Repair(shardID uint64) error {
+	_, err := c.doRequest(&Request{Type: RequestRepair, ShardID: shardID})
+	return err
+}
+
+// doRequest sends a request to the anti-entropy service and returns the response.
+func (c *Client) doRequest(req *Request) (*Response, error) {
+	// Connect to anti-entropy service.
+	conn, err := network.Dial("tcp", c.host, MuxHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Write the request
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("encode anti-entropy request: %s", err)
+	}
+
+	// Read the response
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("decode anti-entropy response: %s", err)
+	}
+	if resp.Err != "" {
+		return nil, errors.New(resp.Err)
+	}
+	return &resp, nil
+}
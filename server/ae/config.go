@@ -0,0 +1,69 @@
+package ae
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cnosdatabase/common/monitor/diagnostics"
+	"github.com/cnosdatabase/common/pkg/toml"
+)
+
+const (
+	// DefaultCheckInterval is the default interval between two checks of the
+	// shard replicas.
+	DefaultCheckInterval = 5 * time.Minute
+
+	// DefaultMaxConcurrentRepairs is the default number of shards repaired at
+	// the same time.
+	DefaultMaxConcurrentRepairs = 1
+)
+
+// Config represents the configuration for the anti-entropy service.
+type Config struct {
+	Enabled              bool          `toml:"enabled"`
+	CheckInterval        toml.Duration `toml:"check-interval"`
+	AutoRepair           bool          `toml:"auto-repair"`
+	MaxConcurrentRepairs int           `toml:"max-concurrent-repairs"`
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		Enabled:              false,
+		CheckInterval:        toml.Duration(DefaultCheckInterval),
+		AutoRepair:           true,
+		MaxConcurrentRepairs: DefaultMaxConcurrentRepairs,
+	}
+}
+
+// Validate returns an error if the Config is invalid.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.CheckInterval <= 0 {
+		return errors.New("check-interval must be positive")
+	}
+	if c.MaxConcurrentRepairs <= 0 {
+		return errors.New("max-concurrent-repairs must be positive")
+	}
+
+	return nil
+}
+
+// Diagnostics returns a diagnostics representation of a subset of the Config.
+func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
+	if !c.Enabled {
+		return diagnostics.RowFromMap(map[string]interface{}{
+			"enabled": false,
+		}), nil
+	}
+
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"enabled":                true,
+		"check-interval":         c.CheckInterval,
+		"auto-repair":            c.AutoRepair,
+		"max-concurrent-repairs": c.MaxConcurrentRepairs,
+	}), nil
+}
@@ -0,0 +1,108 @@
+package ae
+
+import (
+	"io"
+	"math"
+	"sort"
+
+	"github.com/cnosdatabase/db/tsdb/engine/tsm1"
+)
+
+// keySummary summarizes the blocks of a single key in a shard digest.
+//
+// Replicas compact their TSM files independently, so the blocks of two
+// consistent replicas don't have to line up. Only the time bounds and the
+// number of points of each key are compared.
+type keySummary struct {
+	min, max int64
+	n        int
+}
+
+// digest holds the summary of each key of a shard.
+type digest map[string]keySummary
+
+// readDigest reads a shard digest written by tsm1.DigestWriter and closes r.
+func readDigest(r io.ReadCloser) (digest, error) {
+	dr, err := tsm1.NewDigestReader(r)
+	if err != nil {
+		r.Close()
+		return nil, err
+	}
+	defer dr.Close()
+
+	d := make(digest)
+	for {
+		key, ts, err := dr.ReadTimeSpan()
+		if err == io.EOF {
+			return d, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		s := keySummary{min: math.MaxInt64, max: math.MinInt64}
+		for _, tr := range ts.Ranges {
+			if tr.Min < s.min {
+				s.min = tr.Min
+			}
+			if tr.Max > s.max {
+				s.max = tr.Max
+			}
+			s.n += tr.N
+		}
+		d[key] = s
+	}
+}
+
+// timeRange is an inclusive range of timestamps.
+type timeRange struct {
+	min, max int64
+}
+
+// Missing returns the number of keys that have data in remote that is
+// missing from d, and the sorted, disjoint time ranges of those keys. The
+// closest ranges are merged so that at most maxRanges ranges are returned.
+func (d digest) Missing(remote digest, maxRanges int) (keys int, ranges []timeRange) {
+	for key, rs := range remote {
+		ls, ok := d[key]
+		if ok && ls.n >= rs.n && ls.min <= rs.min && ls.max >= rs.max {
+			continue
+		}
+
+		keys++
+		ranges = append(ranges, timeRange{min: rs.min, max: rs.max})
+	}
+	return keys, mergeRanges(ranges, maxRanges)
+}
+
+// mergeRanges sorts ranges and merges the overlapping ones. The ranges with
+// the smallest gap between them are merged until at most max remain.
+func mergeRanges(ranges []timeRange, max int) []timeRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].min < ranges[j].min })
+
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.min <= last.max {
+			if r.max > last.max {
+				last.max = r.max
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	for len(merged) > max {
+		i := 0
+		for j := 1; j < len(merged)-1; j++ {
+			if merged[j+1].min-merged[j].max < merged[i+1].min-merged[i].max {
+				i = j
+			}
+		}
+		merged[i].max = merged[i+1].max
+		merged = append(merged[:i+1], merged[i+2:]...)
+	}
+	return merged
+}
@@ -0,0 +1,582 @@
+// Package ae provides the anti-entropy service that repairs divergent shard replicas.
+package ae
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb"
+	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/cnosdb/server/snapshotter"
+	"github.com/cnosdatabase/db/models"
+	"github.com/cnosdatabase/db/tsdb"
+	"go.uber.org/zap"
+)
+
+// MuxHeader is the header byte used for the TCP muxer.
+const MuxHeader = "ae"
+
+// ErrAntiEntropyDisabled is returned when requesting a repair from a
+// disabled anti-entropy service.
+var ErrAntiEntropyDisabled = errors.New("anti-entropy disabled")
+
+// maxRepairRanges is the maximum number of time ranges exported from the
+// source replica to repair a shard. Each range is a separate shard export.
+const maxRepairRanges = 16
+
+// Statistics maintained by the anti-entropy service.
+const (
+	statChecks         = "checks"
+	statShardsChecked  = "shardsChecked"
+	statShardsDiverged = "shardsDiverged"
+	statRepairs        = "repairs"
+	statRepairFailures = "repairFailures"
+	statRepairBytes    = "repairBytes"
+)
+
+// The states of a shard replica.
+const (
+	StateConsistent = "consistent"
+	StateDiverged   = "diverged"
+	StateRepairing  = "repairing"
+	StateRepaired   = "repaired"
+	StateFailed     = "failed"
+)
+
+// ShardStatus is the anti-entropy status of a shard replica on the local node.
+type ShardStatus struct {
+	ShardID     uint64    `json:"shardID"`
+	Database    string    `json:"database"`
+	TimeToLive  string    `json:"ttl"`
+	State       string    `json:"state"`
+	Source      uint64    `json:"source,omitempty"`
+	MissingKeys int       `json:"missingKeys"`
+	LastCheck   time.Time `json:"lastCheck"`
+	LastRepair  time.Time `json:"lastRepair,omitempty"`
+	Err         string    `json:"error,omitempty"`
+
+	// ranges are the time ranges of the keys missing data in the local replica.
+	ranges []timeRange
+}
+
+// Service compares the digests of the replicas of the shards owned by the
+// local node and pulls the data missing from the local replicas from the
+// other owners.
+type Service struct {
+	mu      sync.RWMutex
+	wg      sync.WaitGroup
+	closing chan struct{}
+
+	config  Config
+	shards  map[uint64]*ShardStatus
+	repairs chan uint64
+	stats   *Statistics
+
+	// repairing holds the shards queued for repair or being repaired. They
+	// aren't checked until their repair is done.
+	repairing map[uint64]bool
+
+	Node *cnosdb.Node
+
+	MetaClient interface {
+		Databases() []meta.DatabaseInfo
+		DataNode(id uint64) (*meta.NodeInfo, error)
+	}
+
+	TSDBStore interface {
+		ShardDigest(id uint64) (io.ReadCloser, int64, error)
+		ImportShard(id uint64, r io.Reader) error
+	}
+
+	Listener net.Listener
+	Logger   *zap.Logger
+}
+
+// NewService returns a new instance of Service.
+func NewService(c Config) *Service {
+	return &Service{
+		config:    c,
+		shards:    make(map[uint64]*ShardStatus),
+		stats:     &Statistics{},
+		repairing: make(map[uint64]bool),
+		Logger:    zap.NewNop(),
+	}
+}
+
+// WithLogger sets the logger on the service.
+func (s *Service) WithLogger(log *zap.Logger) {
+	s.Logger = log.With(zap.String("service", "ae"))
+}
+
+// Open starts the service.
+func (s *Service) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closing != nil {
+		return nil
+	}
+	s.closing = make(chan struct{})
+
+	if s.Listener != nil {
+		s.wg.Add(1)
+		go s.serve()
+	}
+
+	if !s.config.Enabled {
+		return nil
+	}
+	s.Logger.Info("Starting anti-entropy service",
+		zap.Duration("check_interval", time.Duration(s.config.CheckInterval)))
+
+	s.repairs = make(chan uint64, 1024)
+	s.repairing = make(map[uint64]bool)
+	for i := 0; i < s.config.MaxConcurrentRepairs; i++ {
+		s.wg.Add(1)
+		go s.processRepairs()
+	}
+
+	s.wg.Add(1)
+	go s.run()
+	return nil
+}
+
+// Close stops the service, and waits for the repairs and the requests in
+// progress.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	if s.closing == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	close(s.closing)
+	s.mu.Unlock()
+
+	var err error
+	if s.Listener != nil {
+		err = s.Listener.Close()
+	}
+	s.wg.Wait()
+
+	s.mu.Lock()
+	s.closing = nil
+	s.mu.Unlock()
+	return err
+}
+
+// run checks the shards at every check interval.
+func (s *Service) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(time.Duration(s.config.CheckInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			s.Check()
+		}
+	}
+}
+
+// Check compares the replicas of every shard owned by the local node and
+// queues the repair of the divergent ones if auto-repair is enabled.
+func (s *Service) Check() {
+	atomic.AddInt64(&s.stats.Checks, 1)
+
+	for _, di := range s.MetaClient.Databases() {
+		for _, ttli := range di.TimeToLives {
+			for _, sgi := range ttli.Regions {
+				if sgi.Deleted() {
+					continue
+				}
+				for _, si := range sgi.Shards {
+					if !s.replicated(si) || s.isRepairing(si.ID) {
+						continue
+					}
+
+					select {
+					case <-s.closing:
+						return
+					default:
+					}
+
+					status := s.checkShard(di.Name, ttli.Name, si)
+					if status != nil && status.State == StateDiverged && s.config.AutoRepair {
+						s.queueRepair(si.ID)
+					}
+				}
+			}
+		}
+	}
+}
+
+// Repair checks a shard and queues its repair if the local replica diverged.
+func (s *Service) Repair(shardID uint64) error {
+	if !s.config.Enabled {
+		return ErrAntiEntropyDisabled
+	}
+
+	for _, di := range s.MetaClient.Databases() {
+		for _, ttli := range di.TimeToLives {
+			for _, sgi := range ttli.Regions {
+				for _, si := range sgi.Shards {
+					if si.ID != shardID {
+						continue
+					}
+					if !s.replicated(si) {
+						return fmt.Errorf("shard %d has no other replica of the local node", shardID)
+					} else if s.isRepairing(shardID) {
+						return fmt.Errorf("shard %d is already being repaired", shardID)
+					}
+
+					status := s.checkShard(di.Name, ttli.Name, si)
+					if status == nil {
+						return fmt.Errorf("shard %d is not idle", shardID)
+					} else if status.State == StateDiverged {
+						s.queueRepair(shardID)
+					} else if status.Err != "" {
+						return errors.New(status.Err)
+					}
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("shard %d not found", shardID)
+}
+
+// Status returns the status of the shard replicas on the local node.
+func (s *Service) Status() []ShardStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a := make([]ShardStatus, 0, len(s.shards))
+	for _, status := range s.shards {
+		a = append(a, *status)
+	}
+	return a
+}
+
+// replicated returns true if the shard is owned by the local node and at
+// least one other node.
+func (s *Service) replicated(si meta.ShardInfo) bool {
+	return len(si.Owners) > 1 && si.OwnedBy(s.Node.ID)
+}
+
+// checkShard compares the digest of the local replica of a shard with the
+// digests of the other replicas. It returns nil if the local replica is not
+// idle, as digests are only taken from shards that are not being written to.
+func (s *Service) checkShard(database, ttl string, si meta.ShardInfo) *ShardStatus {
+	rc, _, err := s.TSDBStore.ShardDigest(si.ID)
+	if err == tsdb.ErrShardNotIdle || err == tsdb.ErrShardNotFound {
+		return nil
+	}
+
+	status := &ShardStatus{
+		ShardID:    si.ID,
+		Database:   database,
+		TimeToLive: ttl,
+		State:      StateConsistent,
+		LastCheck:  time.Now().UTC(),
+	}
+	defer s.setStatus(status)
+	atomic.AddInt64(&s.stats.ShardsChecked, 1)
+
+	if err != nil {
+		status.State, status.Err = StateFailed, err.Error()
+		return status
+	}
+
+	local, err := readDigest(rc)
+	if err != nil {
+		status.State, status.Err = StateFailed, err.Error()
+		return status
+	}
+
+	for _, owner := range si.Owners {
+		if owner.NodeID == s.Node.ID {
+			continue
+		}
+
+		remote, err := s.remoteDigest(owner.NodeID, si.ID)
+		if err != nil {
+			s.Logger.Info("Unable to read shard digest",
+				zap.Uint64("shard_id", si.ID), zap.Uint64("node_id", owner.NodeID), zap.Error(err))
+			status.Err = fmt.Sprintf("node %d: %s", owner.NodeID, err)
+			continue
+		}
+
+		if n, ranges := local.Missing(remote, maxRepairRanges); n > 0 {
+			status.State = StateDiverged
+			status.Source = owner.NodeID
+			status.MissingKeys = n
+			status.ranges = ranges
+			atomic.AddInt64(&s.stats.ShardsDiverged, 1)
+			return status
+		}
+	}
+	return status
+}
+
+// remoteDigest returns the digest of a shard replica on another node.
+func (s *Service) remoteDigest(nodeID, shardID uint64) (digest, error) {
+	client, err := s.snapshotterClient(nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := client.ShardDigest(shardID)
+	if err != nil {
+		return nil, err
+	}
+	return readDigest(rc)
+}
+
+// snapshotterClient returns a client of the snapshotter service of a node.
+func (s *Service) snapshotterClient(nodeID uint64) (*snapshotter.Client, error) {
+	ni, err := s.MetaClient.DataNode(nodeID)
+	if err != nil {
+		return nil, err
+	} else if ni == nil {
+		return nil, fmt.Errorf("node %d does not exist", nodeID)
+	}
+	return snapshotter.NewClient(ni.TCPHost), nil
+}
+
+// isRepairing returns true if the shard is queued for repair or being
+// repaired.
+func (s *Service) isRepairing(shardID uint64) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.repairing[shardID]
+}
+
+// setStatus records the status of a shard, keeping the time of its last
+// repair. The status of a shard being repaired is kept until the repair is
+// done, as a check may run concurrently with the repair.
+func (s *Service) setStatus(status *ShardStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.repairing[status.ShardID] {
+		return
+	}
+	if prev := s.shards[status.ShardID]; prev != nil {
+		status.LastRepair = prev.LastRepair
+	}
+	s.shards[status.ShardID] = status
+}
+
+// queueRepair queues the repair of a shard unless one is already queued or
+// in progress.
+func (s *Service) queueRepair(shardID uint64) {
+	s.mu.Lock()
+	status := s.shards[shardID]
+	if status == nil || status.State != StateDiverged || s.repairing[shardID] {
+		s.mu.Unlock()
+		return
+	}
+	status.State = StateRepairing
+	s.repairing[shardID] = true
+	s.mu.Unlock()
+
+	select {
+	case s.repairs <- shardID:
+	default:
+		s.Logger.Info("Repair queue full", zap.Uint64("shard_id", shardID))
+		s.mu.Lock()
+		status.State = StateDiverged
+		delete(s.repairing, shardID)
+		s.mu.Unlock()
+	}
+}
+
+// processRepairs repairs the queued shards.
+func (s *Service) processRepairs() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.closing:
+			return
+		case shardID := <-s.repairs:
+			s.repairShard(shardID)
+		}
+	}
+}
+
+// repairShard imports the data missing from the local replica of a shard
+// from the replica it diverged from.
+func (s *Service) repairShard(shardID uint64) {
+	s.mu.RLock()
+	status := *s.shards[shardID]
+	s.mu.RUnlock()
+
+	s.Logger.Info("Repairing shard",
+		zap.Uint64("shard_id", shardID), zap.Uint64("source", status.Source), zap.Int("missing_keys", status.MissingKeys))
+
+	n, err := s.importShard(status)
+	atomic.AddInt64(&s.stats.RepairBytes, n)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.repairing, shardID)
+	current := s.shards[shardID]
+	if err != nil {
+		s.Logger.Info("Failed to repair shard", zap.Uint64("shard_id", shardID), zap.Error(err))
+		atomic.AddInt64(&s.stats.RepairFailures, 1)
+		current.State, current.Err = StateFailed, err.Error()
+		return
+	}
+	atomic.AddInt64(&s.stats.Repairs, 1)
+	current.State, current.Err = StateRepaired, ""
+	current.LastRepair = time.Now().UTC()
+}
+
+// importShard streams the blocks overlapping the time ranges of the missing
+// data from the source replica into the local replica, and returns the number
+// of bytes imported.
+func (s *Service) importShard(status ShardStatus) (int64, error) {
+	client, err := s.snapshotterClient(status.Source)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	for _, tr := range status.ranges {
+		rc, err := client.ExportShard(status.ShardID, time.Unix(0, tr.min), time.Unix(0, tr.max))
+		if err != nil {
+			return n, err
+		}
+
+		r := &countingReader{r: rc}
+		err = s.TSDBStore.ImportShard(status.ShardID, r)
+		rc.Close()
+		n += r.n
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// serve serves the anti-entropy requests from the listener.
+func (s *Service) serve() {
+	defer s.wg.Done()
+
+	for {
+		// Wait for next connection.
+		conn, err := s.Listener.Accept()
+		if err != nil && strings.Contains(err.Error(), "connection closed") {
+			s.Logger.Info("Listener closed")
+			return
+		} else if err != nil {
+			s.Logger.Info("Error accepting anti-entropy request", zap.Error(err))
+			continue
+		}
+
+		// Handle connection in separate goroutine.
+		s.wg.Add(1)
+		go func(conn net.Conn) {
+			defer s.wg.Done()
+			defer conn.Close()
+			if err := s.handleConn(conn); err != nil {
+				s.Logger.Info(err.Error())
+			}
+		}(conn)
+	}
+}
+
+// handleConn processes conn. This is run in a separate goroutine.
+func (s *Service) handleConn(conn net.Conn) error {
+	var r Request
+	if err := json.NewDecoder(conn).Decode(&r); err != nil {
+		return fmt.Errorf("read request: %s", err)
+	}
+
+	var resp Response
+	switch r.Type {
+	case RequestStatus:
+		resp.Shards = s.Status()
+	case RequestRepair:
+		if err := s.Repair(r.ShardID); err != nil {
+			resp.Err = err.Error()
+		}
+	default:
+		resp.Err = fmt.Sprintf("request type unknown: %v", r.Type)
+	}
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		return fmt.Errorf("encode response: %s", err)
+	}
+	return nil
+}
+
+// RequestType indicates the type of an anti-entropy request.
+type RequestType uint8
+
+const (
+	// RequestStatus represents a request for the status of the shard replicas.
+	RequestStatus RequestType = iota
+
+	// RequestRepair represents a request to repair a shard replica.
+	RequestRepair
+)
+
+// Request represents a request to the anti-entropy service.
+type Request struct {
+	Type    RequestType
+	ShardID uint64
+}
+
+// Response represents the response of the anti-entropy service.
+type Response struct {
+	Shards []ShardStatus
+	Err    string
+}
+
+// Statistics keeps statistics related to the anti-entropy service.
+type Statistics struct {
+	Checks         int64
+	ShardsChecked  int64
+	ShardsDiverged int64
+	Repairs        int64
+	RepairFailures int64
+	RepairBytes    int64
+}
+
+// Statistics returns statistics for periodic monitoring.
+func (s *Service) Statistics(tags map[string]string) []models.Statistic {
+	return []models.Statistic{{
+		Name: "ae",
+		Tags: tags,
+		Values: map[string]interface{}{
+			statChecks:         atomic.LoadInt64(&s.stats.Checks),
+			statShardsChecked:  atomic.LoadInt64(&s.stats.ShardsChecked),
+			statShardsDiverged: atomic.LoadInt64(&s.stats.ShardsDiverged),
+			statRepairs:        atomic.LoadInt64(&s.stats.Repairs),
+			statRepairFailures: atomic.LoadInt64(&s.stats.RepairFailures),
+			statRepairBytes:    atomic.LoadInt64(&s.stats.RepairBytes),
+		},
+	}}
+}
@@ -0,0 +1,309 @@
+package ae
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb"
+	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/cnosdb/server/snapshotter"
+	"github.com/cnosdatabase/common/pkg/toml"
+	"github.com/cnosdatabase/db/tsdb/engine/tsm1"
+)
+
+// writeDigest returns a shard digest holding a single range of each key.
+func writeDigest(t *testing.T, ranges map[string]tsm1.DigestTimeRange) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := tsm1.NewDigestWriter(nopWriteCloser{&buf})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteManifest(&tsm1.DigestManifest{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"cpu,host=a#!~#v", "cpu,host=b#!~#v"} {
+		if tr, ok := ranges[key]; ok {
+			if err := w.WriteTimeSpan(key, &tsm1.DigestTimeSpan{Ranges: []tsm1.DigestTimeRange{tr}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// snapshotterServer serves the digest of the remote replica of the shards,
+// and exports them.
+type snapshotterServer struct {
+	ln     net.Listener
+	digest []byte
+}
+
+func newSnapshotterServer(t *testing.T, digest []byte) *snapshotterServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &snapshotterServer{ln: ln, digest: digest}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+	return s
+}
+
+func (s *snapshotterServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			header := make([]byte, len(snapshotter.MuxHeader)+1)
+			if _, err := io.ReadFull(conn, header); err != nil {
+				return
+			}
+			var r snapshotter.Request
+			if err := json.NewDecoder(conn).Decode(&r); err != nil {
+				return
+			}
+			switch r.Type {
+			case snapshotter.RequestShardDigest:
+				conn.Write(s.digest)
+			case snapshotter.RequestShardExport:
+				conn.Write([]byte("export"))
+			}
+		}(conn)
+	}
+}
+
+// aeMetaClient holds a shard owned by the local node 1 and the remote node 2.
+type aeMetaClient struct {
+	host string
+}
+
+func (c *aeMetaClient) Databases() []meta.DatabaseInfo {
+	return []meta.DatabaseInfo{{
+		Name: "db0",
+		TimeToLives: []meta.TimeToLiveInfo{{
+			Name: "autogen",
+			Regions: []meta.RegionInfo{{
+				ID:     1,
+				Shards: []meta.ShardInfo{{ID: 1, Owners: []meta.ShardOwner{{NodeID: 1}, {NodeID: 2}}}},
+			}},
+		}},
+	}}
+}
+
+func (c *aeMetaClient) DataNode(id uint64) (*meta.NodeInfo, error) {
+	if id != 2 {
+		return nil, nil
+	}
+	return &meta.NodeInfo{ID: 2, TCPHost: c.host}, nil
+}
+
+// aeTSDBStore holds the local replica of the shards. The imports wait for
+// unblock to be released, if it is set.
+type aeTSDBStore struct {
+	digest  []byte
+	unblock chan struct{}
+	once    sync.Once
+
+	mu       sync.Mutex
+	digests  int
+	imported []string
+	started  chan struct{}
+}
+
+func (s *aeTSDBStore) ShardDigest(id uint64) (io.ReadCloser, int64, error) {
+	s.mu.Lock()
+	s.digests++
+	s.mu.Unlock()
+	return ioutil.NopCloser(bytes.NewReader(s.digest)), int64(len(s.digest)), nil
+}
+
+func (s *aeTSDBStore) ImportShard(id uint64, r io.Reader) error {
+	select {
+	case s.started <- struct{}{}:
+	default:
+	}
+	if s.unblock != nil {
+		<-s.unblock
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.imported = append(s.imported, string(b))
+	s.mu.Unlock()
+	return nil
+}
+
+// release unblocks the imports.
+func (s *aeTSDBStore) release() {
+	s.once.Do(func() { close(s.unblock) })
+}
+
+func (s *aeTSDBStore) counts() (digests, imports int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.digests, len(s.imported)
+}
+
+// openService opens a service repairing the local replica of the shard,
+// which misses a key of the remote replica.
+func openService(t *testing.T, store *aeTSDBStore, ln net.Listener) *Service {
+	t.Helper()
+	remote := newSnapshotterServer(t, writeDigest(t, map[string]tsm1.DigestTimeRange{
+		"cpu,host=a#!~#v": {Min: 0, Max: 10, N: 2},
+		"cpu,host=b#!~#v": {Min: 5, Max: 20, N: 3},
+	}))
+	store.digest = writeDigest(t, map[string]tsm1.DigestTimeRange{
+		"cpu,host=a#!~#v": {Min: 0, Max: 10, N: 2},
+	})
+
+	c := NewConfig()
+	c.Enabled = true
+	c.CheckInterval = toml.Duration(time.Hour)
+
+	s := NewService(c)
+	s.Node = &cnosdb.Node{ID: 1}
+	s.MetaClient = &aeMetaClient{host: remote.ln.Addr().String()}
+	s.TSDBStore = store
+	s.Listener = ln
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// shardState returns the state of the local replica of the shard.
+func shardState(s *Service) string {
+	for _, status := range s.Status() {
+		if status.ShardID == 1 {
+			return status.State
+		}
+	}
+	return ""
+}
+
+// waitForState waits for the local replica of the shard to be in state.
+func waitForState(t *testing.T, s *Service, state string) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); shardState(s) != state; {
+		if time.Now().After(deadline) {
+			t.Fatalf("got state %q, exp %q", shardState(s), state)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestService_Check(t *testing.T) {
+	store := &aeTSDBStore{}
+	s := openService(t, store, nil)
+	defer s.Close()
+
+	// The missing key is imported from the remote replica.
+	s.Check()
+	waitForState(t, s, StateRepaired)
+	if _, imports := store.counts(); imports != 1 || store.imported[0] != "export" {
+		t.Fatalf("got imports %q, exp a single export", store.imported)
+	}
+
+	status := s.Status()[0]
+	if status.Source != 2 || status.MissingKeys != 1 || status.LastRepair.IsZero() {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestService_Check_Repairing(t *testing.T) {
+	store := &aeTSDBStore{started: make(chan struct{}, 1), unblock: make(chan struct{})}
+	s := openService(t, store, nil)
+	defer s.Close()
+	defer store.release()
+
+	s.Check()
+	<-store.started
+
+	// The shard being repaired isn't checked, nor queued for another repair.
+	digests, _ := store.counts()
+	s.Check()
+	if err := s.Repair(1); err == nil || !strings.Contains(err.Error(), "already being repaired") {
+		t.Fatalf("got error %v, exp the repair to be in progress", err)
+	}
+	if n, _ := store.counts(); n != digests {
+		t.Fatalf("got %d digests, exp %d", n, digests)
+	} else if state := shardState(s); state != StateRepairing {
+		t.Fatalf("got state %q, exp %q", state, StateRepairing)
+	}
+
+	store.release()
+	waitForState(t, s, StateRepaired)
+	if _, imports := store.counts(); imports != 1 {
+		t.Fatalf("got %d imports, exp 1", imports)
+	}
+
+	// The repaired shard is checked again, and repaired once more since the
+	// remote replica still has more data.
+	s.Check()
+	waitForState(t, s, StateRepaired)
+	if _, imports := store.counts(); imports != 2 {
+		t.Fatalf("got %d imports, exp 2", imports)
+	}
+}
+
+// closeErrListener is a listener failing to close.
+type closeErrListener struct {
+	net.Listener
+	closed chan struct{}
+}
+
+func (ln *closeErrListener) Accept() (net.Conn, error) {
+	<-ln.closed
+	return nil, errors.New("connection closed")
+}
+
+func (ln *closeErrListener) Close() error {
+	close(ln.closed)
+	return errors.New("close failed")
+}
+
+func TestService_Close(t *testing.T) {
+	store := &aeTSDBStore{started: make(chan struct{}, 1), unblock: make(chan struct{})}
+	s := openService(t, store, &closeErrListener{closed: make(chan struct{})})
+	defer store.release()
+
+	s.Check()
+	<-store.started
+
+	// Close waits for the repair in progress, and returns the error of the
+	// listener.
+	closed := make(chan error, 1)
+	go func() { closed <- s.Close() }()
+	select {
+	case err := <-closed:
+		t.Fatalf("closed with the repair in progress: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	store.release()
+	if err := <-closed; err == nil || err.Error() != "close failed" {
+		t.Fatalf("got error %v, exp close failed", err)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/cnosdatabase/cnosdb/monitor"
 	"github.com/cnosdatabase/cnosdb/pkg/logger"
 	"github.com/cnosdatabase/cnosdb/pkg/tlsconfig"
+	"github.com/cnosdatabase/cnosdb/server/ae"
 	"github.com/cnosdatabase/cnosdb/server/continuous_querier"
 	"github.com/cnosdatabase/cnosdb/server/coordinator"
 	"github.com/cnosdatabase/cnosdb/server/hh"
@@ -48,6 +49,7 @@ type Config struct {
 	Log             *logger.Config
 	ContinuousQuery continuous_querier.Config
 	HintedHandoff   hh.Config
+	AntiEntropy     ae.Config
 	TLS             tlsconfig.Config
 }
 
@@ -61,11 +63,13 @@ func NewConfig() *Config {
 	c.Precreator = region.NewConfig()
 
 	c.Monitor = monitor.NewConfig()
+	c.Subscriber = subscriber.NewConfig()
 	c.HTTPD = NewHTTPConfig()
 	c.Log = logger.NewDefaultLogConfig()
 
 	c.ContinuousQuery = continuous_querier.NewConfig()
 	c.TimeToLive = ttl.NewConfig()
+	c.AntiEntropy = ae.NewConfig()
 
 	return c
 }
@@ -88,6 +92,7 @@ func NewDemoConfig() (*Config, error) {
 	c.Meta.Dir = filepath.Join(homeDir, ".cnosdb/meta")
 	c.Data.Dir = filepath.Join(homeDir, ".cnosdb/data")
 	c.Data.WALDir = filepath.Join(homeDir, ".cnosdb/wal")
+	c.Subscriber.Dir = filepath.Join(homeDir, ".cnosdb/subscriber")
 
 	return c, nil
 }
@@ -143,6 +148,10 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.AntiEntropy.Validate(); err != nil {
+		return err
+	}
+
 	if err := c.TimeToLive.Validate(); err != nil {
 		return err
 	}
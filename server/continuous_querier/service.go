@@ -4,6 +4,8 @@ package continuous_querier
 import (
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -26,6 +28,13 @@ const (
 	// idDelimiter is used as a delimiter when creating a unique name for a
 	// Continuous Query.
 	idDelimiter = string(rune(31)) // unit separator
+
+	// leasePrefix prefixes the name of the lease of each Continuous Query.
+	leasePrefix = "continuous_querier" + idDelimiter
+
+	// backfillChunkIntervals is the number of GROUP BY intervals computed by
+	// each query of a backfill.
+	backfillChunkIntervals = 100
 )
 
 // Statistics for the CQ service.
@@ -42,9 +51,12 @@ type ContinuousQuerier interface {
 
 // metaClient is an internal interface to make testing easier.
 type metaClient interface {
+	NodeID() uint64
+	DataNodes() ([]meta.NodeInfo, error)
 	AcquireLease(name string) (l *meta.Lease, err error)
 	Databases() []meta.DatabaseInfo
 	Database(name string) *meta.DatabaseInfo
+	SetContinuousQueryLastRun(database, name string, t time.Time, d time.Duration, lastErr string) error
 }
 
 // RunRequest is a request to run one or more CQs.
@@ -54,6 +66,11 @@ type RunRequest struct {
 	// CQs tells the CQ service which queries to run.
 	// If nil, all queries will be run.
 	CQs []string
+
+	// forced holds the CQs that run regardless of their last run time.
+	forced map[string]bool
+	// err receives the error of running the forced CQs, if set.
+	err chan error
 }
 
 // matches returns true if the CQ matches one of the requested CQs.
@@ -86,15 +103,22 @@ type Service struct {
 	Monitor       Monitor
 	Config        *Config
 	RunInterval   time.Duration
+	// TakeoverDelay is how long a CQ must be due before a node other than
+	// its preferred node takes over its lease.
+	TakeoverDelay time.Duration
 	// RunCh can be used by clients to signal service to run CQs.
 	RunCh             chan *RunRequest
 	Logger            *zap.Logger
 	loggingEnabled    bool
 	queryStatsEnabled bool
 	stats             *Statistics
-	// lastRuns maps CQ name to last time it was run.
+	// lastRuns maps CQ name to last time it was run by this node. The last
+	// run times are persisted in the meta store, which may lag behind.
 	mu       sync.RWMutex
 	lastRuns map[string]time.Time
+	// dueSince maps CQ name to the time this node saw it due, for the CQs
+	// that other nodes are preferred to run.
+	dueSince map[string]time.Time
 	stop     chan struct{}
 	wg       *sync.WaitGroup
 }
@@ -110,7 +134,9 @@ func NewService(c Config) *Service {
 		queryStatsEnabled: c.QueryStatsEnabled,
 		Logger:            zap.NewNop(),
 		stats:             &Statistics{},
+		TakeoverDelay:     meta.DefaultLeaseDuration,
 		lastRuns:          map[string]time.Time{},
+		dueSince:          map[string]time.Time{},
 	}
 
 	return s
@@ -169,7 +195,9 @@ func (s *Service) Statistics(tags map[string]string) []models.Statistic {
 	}}
 }
 
-// Run runs the specified continuous query, or all CQs if none is specified.
+// Run runs the specified continuous query, or all CQs if none is specified,
+// and waits for them to run. It fails if another node holds the lease of one
+// of the CQs.
 func (s *Service) Run(database, name string, t time.Time) error {
 	var dbs []meta.DatabaseInfo
 
@@ -186,28 +214,24 @@ func (s *Service) Run(database, name string, t time.Time) error {
 	}
 
 	// Loop through databases.
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	req := &RunRequest{Now: t, forced: make(map[string]bool), err: make(chan error, 1)}
 	for _, db := range dbs {
 		// Loop through CQs in each DB executing the ones that match name.
 		for _, cq := range db.ContinuousQueries {
 			if name == "" || cq.Name == name {
-				// Remove the last run time for the CQ
-				id := fmt.Sprintf("%s%s%s", db.Name, idDelimiter, cq.Name)
-				delete(s.lastRuns, id)
+				// Ignore the last run time of the CQ
+				req.forced[cqID(db.Name, cq.Name)] = true
 			}
 		}
 	}
 
 	// Signal the background routine to run CQs.
-	s.RunCh <- &RunRequest{Now: t}
-
-	return nil
+	s.RunCh <- req
+	return <-req.err
 }
 
 // backgroundLoop runs on a go routine and periodically executes CQs.
 func (s *Service) backgroundLoop() {
-	leaseName := "continuous_querier"
 	t := time.NewTimer(s.RunInterval)
 	defer t.Stop()
 	defer s.wg.Done()
@@ -217,21 +241,20 @@ func (s *Service) backgroundLoop() {
 			s.Logger.Info("Terminating continuous query service")
 			return
 		case req := <-s.RunCh:
-			if !s.hasContinuousQueries() {
-				continue
-			}
-			if _, err := s.MetaClient.AcquireLease(leaseName); err == nil {
+			var err error
+			if s.hasContinuousQueries() {
 				s.Logger.Info("Running continuous queries by request", zap.Time("at", req.Now))
-				s.runContinuousQueries(req)
+				err = s.runContinuousQueries(req)
+			}
+			if req.err != nil {
+				req.err <- err
 			}
 		case <-t.C:
 			if !s.hasContinuousQueries() {
 				t.Reset(s.RunInterval)
 				continue
 			}
-			if _, err := s.MetaClient.AcquireLease(leaseName); err == nil {
-				s.runContinuousQueries(&RunRequest{Now: time.Now()})
-			}
+			s.runContinuousQueries(&RunRequest{Now: time.Now()})
 			t.Reset(s.RunInterval)
 		}
 	}
@@ -250,29 +273,43 @@ func (s *Service) hasContinuousQueries() bool {
 	return false
 }
 
-// runContinuousQueries gets CQs from the meta store and runs them.
-func (s *Service) runContinuousQueries(req *RunRequest) {
+// runContinuousQueries gets CQs from the meta store and runs the ones that
+// are due. The CQs are distributed across the data nodes, a node runs a CQ
+// only if it holds the lease of the CQ. It returns the first error of the
+// CQs forced by the request.
+func (s *Service) runContinuousQueries(req *RunRequest) error {
+	var forcedErr error
 	// Get list of all databases.
 	dbs := s.MetaClient.Databases()
 	// Loop through all databases executing CQs.
 	for _, db := range dbs {
-		// TODO: distribute across nodes
 		for _, cq := range db.ContinuousQueries {
 			if !req.matches(&cq) {
 				continue
 			}
-			if ok, err := s.ExecuteContinuousQuery(&db, &cq, req.Now); err != nil {
+			forced := req.forced[cqID(db.Name, cq.Name)]
+			if ok, err := s.executeContinuousQuery(&db, &cq, req.Now, forced); err != nil {
 				s.Logger.Info("Error executing query", zap.String("query", cq.Query), zap.Error(err))
 				atomic.AddInt64(&s.stats.QueryFail, 1)
+				if forced && forcedErr == nil {
+					forcedErr = fmt.Errorf("continuous query %s: %s", cq.Name, err)
+				}
 			} else if ok {
 				atomic.AddInt64(&s.stats.QueryOK, 1)
 			}
 		}
 	}
+	return forcedErr
 }
 
 // ExecuteContinuousQuery may execute a single CQ. This will return false if there were no errors and the CQ was not run.
-func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.ContinuousQueryInfo, now time.Time) (bool, error) {
+func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.ContinuousQueryInfo, now time.Time) (ran bool, err error) {
+	return s.executeContinuousQuery(dbi, cqi, now, false)
+}
+
+// executeContinuousQuery may execute a single CQ. A forced CQ runs regardless
+// of its last run time, and fails if another node holds its lease.
+func (s *Service) executeContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.ContinuousQueryInfo, now time.Time, forced bool) (ran bool, err error) {
 	// TODO: re-enable stats
 	//s.stats.Inc("continuousQueryExecuted")
 
@@ -288,11 +325,17 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 		now = now.In(cq.q.Location)
 	}
 
-	// Get the last time this CQ was run from the service's cache.
+	// Get the last time this CQ was run from the meta store, or from the
+	// service's cache if the meta store doesn't have the last run yet.
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	id := fmt.Sprintf("%s%s%s", dbi.Name, idDelimiter, cqi.Name)
-	cq.LastRun, cq.HasRun = s.lastRuns[id]
+	id := cqID(dbi.Name, cqi.Name)
+	if !forced {
+		cq.LastRun, cq.HasRun = cqi.LastRun, !cqi.LastRun.IsZero()
+		if lastRun, ok := s.lastRuns[id]; ok && lastRun.After(cq.LastRun) {
+			cq.LastRun, cq.HasRun = lastRun, true
+		}
+	}
 
 	// Set the time-to-live to default if it wasn't specified in the query.
 	if cq.intoRP() == "" {
@@ -318,9 +361,16 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	if err != nil {
 		return false, err
 	} else if !run {
+		delete(s.dueSince, id)
 		return false, nil
 	}
 
+	// Run the query only if this node holds its lease.
+	if ok, err := s.acquireLease(id, now, forced); !ok {
+		return false, err
+	}
+	delete(s.dueSince, id)
+
 	resampleEvery := interval
 	if cq.Resample.Every != 0 {
 		resampleEvery = cq.Resample.Every
@@ -331,6 +381,14 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	cq.LastRun = truncate(now.Add(-offset), resampleEvery).Add(offset)
 	s.lastRuns[id] = cq.LastRun
 
+	// Persist the last run time once the query ran, so that the node running
+	// the query next resumes from there. If this node fails before that, the
+	// next node runs the query again, which writes the same points again.
+	runStart := time.Now()
+	defer func() {
+		s.saveLastRun(dbi.Name, cqi.Name, cq.LastRun, time.Since(runStart), err)
+	}()
+
 	// Retrieve the oldest interval we should calculate based on the next time
 	// interval. We do this instead of using the current time just in case any
 	// time intervals were missed. The start time of the oldest interval is what
@@ -418,6 +476,229 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	return true, nil
 }
 
+// acquireLease returns true if this node holds the lease of a CQ that is due.
+//
+// Each CQ has a preferred node, which acquires the lease of the CQ whenever it
+// is due. Other nodes try to take over the lease once the CQ has been due for
+// the takeover delay, which means that the preferred node is down or can't
+// reach the meta store. A CQ that is run by request always tries the lease,
+// and an error naming the node holding the lease is returned if it fails.
+func (s *Service) acquireLease(id string, now time.Time, forced bool) (bool, error) {
+	if !forced && !s.isPreferredNode(id) {
+		since, ok := s.dueSince[id]
+		if !ok {
+			s.dueSince[id] = now
+			return false, nil
+		} else if now.Sub(since) < s.TakeoverDelay {
+			return false, nil
+		}
+	}
+
+	l, err := s.MetaClient.AcquireLease(leasePrefix + id)
+	if err == nil {
+		return true, nil
+	} else if !forced {
+		return false, nil
+	} else if l != nil && l.Owner != s.MetaClient.NodeID() {
+		return false, fmt.Errorf("node %d holds its lease", l.Owner)
+	}
+	return false, fmt.Errorf("acquire lease: %s", err)
+}
+
+// cqID returns the unique ID of a CQ of a database.
+func cqID(database, name string) string {
+	return database + idDelimiter + name
+}
+
+// isPreferredNode returns true if this node is the preferred node of a CQ. The
+// CQs are spread over the data nodes by the hash of their ID. A node that is
+// not a data node of a cluster is the preferred node of all CQs.
+func (s *Service) isPreferredNode(id string) bool {
+	nodes, err := s.MetaClient.DataNodes()
+	if err != nil || len(nodes) == 0 {
+		return true
+	}
+
+	nodeIDs := make([]uint64, 0, len(nodes))
+	found := false
+	for _, n := range nodes {
+		nodeIDs = append(nodeIDs, n.ID)
+		found = found || n.ID == s.MetaClient.NodeID()
+	}
+	if !found {
+		return true
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool { return nodeIDs[i] < nodeIDs[j] })
+
+	h := fnv.New64a()
+	h.Write([]byte(id))
+	return nodeIDs[h.Sum64()%uint64(len(nodeIDs))] == s.MetaClient.NodeID()
+}
+
+// saveLastRun persists the last run time of a CQ in the meta store, along
+// with the duration and the error of the run.
+func (s *Service) saveLastRun(database, name string, lastRun time.Time, d time.Duration, runErr error) {
+	var lastErr string
+	if runErr != nil {
+		lastErr = runErr.Error()
+	}
+
+	if err := s.MetaClient.SetContinuousQueryLastRun(database, name, lastRun, d, lastErr); err != nil {
+		s.Logger.Info("Failed to save the last run time of continuous query",
+			zap.String("name", name),
+			logger.Database(database),
+			zap.Error(err))
+	}
+}
+
+// NextRun returns the time the CQ runs next. It returns a zero time if the CQ
+// never ran, in which case it runs at the next check.
+func (s *Service) NextRun(database string, cqi *meta.ContinuousQueryInfo) (time.Time, error) {
+	if cqi.LastRun.IsZero() {
+		return time.Time{}, nil
+	}
+
+	cq, err := NewContinuousQuery(database, cqi)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	interval, err := cq.q.GroupByInterval()
+	if err != nil || interval == 0 {
+		return time.Time{}, err
+	}
+
+	lastRun := cqi.LastRun
+	if cq.q.Location != nil {
+		lastRun = lastRun.In(cq.q.Location)
+	}
+	return cq.nextRun(lastRun, interval), nil
+}
+
+// Backfill computes a CQ over the time range from start to end in the
+// background, and returns the query ID of the backfill. The backfill is a
+// task of the query executor, so it is listed by SHOW QUERIES and is stopped
+// by KILL QUERY.
+func (s *Service) Backfill(database, name string, start, end time.Time) (uint64, error) {
+	dbi := s.MetaClient.Database(database)
+	if dbi == nil {
+		return 0, query.ErrDatabaseNotFound(database)
+	}
+
+	var cqi *meta.ContinuousQueryInfo
+	for i := range dbi.ContinuousQueries {
+		if dbi.ContinuousQueries[i].Name == name {
+			cqi = &dbi.ContinuousQueries[i]
+			break
+		}
+	}
+	if cqi == nil {
+		return 0, meta.ErrContinuousQueryNotFound
+	}
+
+	cq, err := NewContinuousQuery(database, cqi)
+	if err != nil {
+		return 0, err
+	}
+
+	// Set the time-to-live to default if it wasn't specified in the query.
+	if cq.intoRP() == "" {
+		cq.setIntoRP(dbi.DefaultTimeToLive)
+	}
+
+	interval, err := cq.q.GroupByInterval()
+	if err != nil {
+		return 0, err
+	} else if interval == 0 {
+		return 0, errors.New("continuous query has no GROUP BY time interval")
+	}
+	offset, err := cq.q.GroupByOffset()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, detach, err := s.QueryExecutor.TaskManager.AttachQuery(&cnosql.Query{
+		Statements: cnosql.Statements{&cnosql.BackfillContinuousQueryStatement{
+			Name:      name,
+			Database:  database,
+			StartTime: start,
+			EndTime:   end,
+		}},
+	}, query.ExecutionOptions{Database: database}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	go func() {
+		defer detach()
+		s.backfill(ctx, cq, start, end, interval, offset)
+	}()
+	return ctx.QueryID, nil
+}
+
+// backfill runs the query of a CQ over whole GROUP BY intervals covering the
+// time range, a chunk of intervals at a time, until the range is done or the
+// backfill is killed.
+func (s *Service) backfill(ctx *query.ExecutionContext, cq *ContinuousQuery, start, end time.Time, interval, offset time.Duration) {
+	log, logEnd := logger.NewOperation(s.Logger, "Continuous query backfill", "continuous_querier_backfill")
+	defer logEnd()
+
+	log.Info("Backfilling continuous query",
+		zap.String("name", cq.Info.Name),
+		logger.Database(cq.Database),
+		zap.Uint64("qid", ctx.QueryID),
+		zap.Time("start", start),
+		zap.Time("end", end))
+
+	// Interrupt the running query when the backfill is killed.
+	closing := make(chan struct{})
+	go func() {
+		<-ctx.Done()
+		close(closing)
+	}()
+
+	if cq.q.Location != nil {
+		start, end = start.In(cq.q.Location), end.In(cq.q.Location)
+	}
+	t := truncate(start.Add(-offset), interval).Add(offset)
+	end = truncate(end.Add(interval-offset-1), interval).Add(offset)
+
+	for t.Before(end) {
+		select {
+		case <-closing:
+			log.Info("Continuous query backfill killed", zap.Time("at", t))
+			return
+		default:
+		}
+
+		chunkEnd := t.Add(backfillChunkIntervals * interval)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		q := cq.q.Clone()
+		if err := q.SetTimeRange(t, chunkEnd); err != nil {
+			log.Info("Continuous query backfill failed", zap.Error(err))
+			return
+		}
+
+		ch := s.QueryExecutor.ExecuteQuery(&cnosql.Query{
+			Statements: cnosql.Statements{q},
+		}, query.ExecutionOptions{Database: cq.Database}, closing)
+		for res := range ch {
+			if res.Err != nil {
+				log.Info("Continuous query backfill failed", zap.Time("at", t), zap.Error(res.Err))
+				return
+			}
+		}
+		t = chunkEnd
+	}
+
+	log.Info("Finished continuous query backfill",
+		zap.String("name", cq.Info.Name),
+		logger.Database(cq.Database))
+}
+
 // runContinuousQueryAndWriteResult will run the query against the cluster and write the results back in
 func (s *Service) runContinuousQueryAndWriteResult(cq *ContinuousQuery) *query.Result {
 	// Wrap the CQ's inner SELECT statement in a Query for the Executor.
@@ -503,25 +784,10 @@ func (cq *ContinuousQuery) shouldRunContinuousQuery(now time.Time, interval time
 		return false, cq.LastRun, errors.New("continuous queries must be aggregate queries")
 	}
 
-	// Override the query's default run interval with the resample options.
-	resampleEvery := interval
-	if cq.Resample.Every != 0 {
-		resampleEvery = cq.Resample.Every
-	}
-
 	// Determine if we should run the continuous query based on the last time it ran.
 	// If the query never ran, execute it using the current time.
 	if cq.HasRun {
-		// Retrieve the zone offset for the previous window.
-		_, startOffset := cq.LastRun.Add(-1).Zone()
-		nextRun := cq.LastRun.Add(resampleEvery)
-		// Retrieve the end zone offset for the end of the current interval.
-		if _, endOffset := nextRun.Add(-1).Zone(); startOffset != endOffset {
-			diff := int64(startOffset-endOffset) * int64(time.Second)
-			if abs(diff) < int64(resampleEvery) {
-				nextRun = nextRun.Add(time.Duration(diff))
-			}
-		}
+		nextRun := cq.nextRun(cq.LastRun, interval)
 		if nextRun.UnixNano() <= now.UnixNano() {
 			return true, nextRun, nil
 		}
@@ -537,6 +803,27 @@ func (cq *ContinuousQuery) shouldRunContinuousQuery(now time.Time, interval time
 	return false, cq.LastRun, nil
 }
 
+// nextRun returns the time the CQ runs next after it ran at lastRun.
+func (cq *ContinuousQuery) nextRun(lastRun time.Time, interval time.Duration) time.Time {
+	// Override the query's default run interval with the resample options.
+	resampleEvery := interval
+	if cq.Resample.Every != 0 {
+		resampleEvery = cq.Resample.Every
+	}
+
+	// Retrieve the zone offset for the previous window.
+	_, startOffset := lastRun.Add(-1).Zone()
+	nextRun := lastRun.Add(resampleEvery)
+	// Retrieve the end zone offset for the end of the current interval.
+	if _, endOffset := nextRun.Add(-1).Zone(); startOffset != endOffset {
+		diff := int64(startOffset-endOffset) * int64(time.Second)
+		if abs(diff) < int64(resampleEvery) {
+			nextRun = nextRun.Add(time.Duration(diff))
+		}
+	}
+	return nextRun
+}
+
 // assert will panic with a given formatted message if the given condition is false.
 func assert(condition bool, msg string, v ...interface{}) {
 	if !condition {
@@ -0,0 +1,166 @@
+package continuous_querier
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/cnosql"
+	"github.com/cnosdatabase/db/query"
+)
+
+// leaseMetaClient is a meta client whose CQ leases are held by leaseOwner,
+// if it is set.
+type leaseMetaClient struct {
+	mu         sync.Mutex
+	nodeID     uint64
+	leaseOwner uint64
+	db         meta.DatabaseInfo
+	lastRuns   map[string]time.Time
+}
+
+func newLeaseMetaClient(nodeID uint64) *leaseMetaClient {
+	return &leaseMetaClient{
+		nodeID: nodeID,
+		db: meta.DatabaseInfo{
+			Name:              "db0",
+			DefaultTimeToLive: "autogen",
+			ContinuousQueries: []meta.ContinuousQueryInfo{{
+				Name:  "cq0",
+				Query: `CREATE CONTINUOUS QUERY cq0 ON db0 BEGIN SELECT count(v) INTO cpu_count FROM cpu GROUP BY time(10s) END`,
+			}},
+		},
+		lastRuns: make(map[string]time.Time),
+	}
+}
+
+func (c *leaseMetaClient) NodeID() uint64                      { return c.nodeID }
+func (c *leaseMetaClient) DataNodes() ([]meta.NodeInfo, error) { return nil, nil }
+
+func (c *leaseMetaClient) AcquireLease(name string) (*meta.Lease, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.leaseOwner != 0 && c.leaseOwner != c.nodeID {
+		return &meta.Lease{Name: name, Owner: c.leaseOwner}, errors.New("another node owns the lease")
+	}
+	return &meta.Lease{Name: name, Owner: c.nodeID, Expiration: time.Now().Add(meta.DefaultLeaseDuration)}, nil
+}
+
+func (c *leaseMetaClient) Databases() []meta.DatabaseInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return []meta.DatabaseInfo{c.db}
+}
+
+func (c *leaseMetaClient) Database(name string) *meta.DatabaseInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if name != c.db.Name {
+		return nil
+	}
+	db := c.db
+	return &db
+}
+
+func (c *leaseMetaClient) SetContinuousQueryLastRun(database, name string, t time.Time, d time.Duration, lastErr string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastRuns[name] = t
+	for i := range c.db.ContinuousQueries {
+		if c.db.ContinuousQueries[i].Name == name {
+			c.db.ContinuousQueries[i].LastRun = t
+		}
+	}
+	return nil
+}
+
+func (c *leaseMetaClient) setLeaseOwner(id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.leaseOwner = id
+}
+
+// countingExecutor counts the statements it executes.
+type countingExecutor struct {
+	mu sync.Mutex
+	n  int
+}
+
+func (e *countingExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt cnosql.Statement) error {
+	e.mu.Lock()
+	e.n++
+	e.mu.Unlock()
+	return ctx.Send(&query.Result{})
+}
+
+func (e *countingExecutor) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.n
+}
+
+// openService opens a CQ service running the CQs of mc with se.
+func openService(t *testing.T, mc *leaseMetaClient, se *countingExecutor) *Service {
+	t.Helper()
+	s := NewService(NewConfig())
+	s.RunInterval = time.Hour
+	s.MetaClient = mc
+	s.QueryExecutor = query.NewExecutor()
+	s.QueryExecutor.StatementExecutor = se
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestService_Run(t *testing.T) {
+	mc, se := newLeaseMetaClient(1), &countingExecutor{}
+	s := openService(t, mc, se)
+	defer s.Close()
+
+	// A CQ that never ran computes the interval ending at the run time.
+	now := time.Now().Truncate(10 * time.Second)
+	if err := s.Run("db0", "cq0", now); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if n := se.count(); n != 1 {
+		t.Fatalf("got %d queries executed, exp 1", n)
+	} else if _, ok := mc.lastRuns["cq0"]; !ok {
+		t.Fatal("expected the last run time to be saved")
+	}
+
+	// The CQ runs again by request, regardless of its last run time.
+	if err := s.Run("db0", "cq0", now); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if n := se.count(); n != 2 {
+		t.Fatalf("got %d queries executed, exp 2", n)
+	}
+}
+
+func TestService_Run_LeaseHeld(t *testing.T) {
+	mc, se := newLeaseMetaClient(1), &countingExecutor{}
+	mc.setLeaseOwner(2)
+	s := openService(t, mc, se)
+	defer s.Close()
+
+	// The run fails, naming the node holding the lease.
+	now := time.Now().Truncate(10 * time.Second)
+	if err := s.Run("db0", "cq0", now); err == nil || !strings.Contains(err.Error(), "node 2") {
+		t.Fatalf("got error %v, exp the lease to be held by node 2", err)
+	} else if n := se.count(); n != 0 {
+		t.Fatalf("got %d queries executed, exp 0", n)
+	}
+
+	// The failed run isn't left pending: once the lease is free, the CQ only
+	// runs when it is due.
+	mc.setLeaseOwner(0)
+	mc.SetContinuousQueryLastRun("db0", "cq0", now, 0, "")
+	db := mc.Database("db0")
+	if ran, err := s.ExecuteContinuousQuery(db, &db.ContinuousQueries[0], now); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if ran || se.count() != 0 {
+		t.Fatal("expected the CQ not to run before it is due")
+	}
+}
@@ -20,6 +20,10 @@ const (
 	// DefaultShardMapperTimeout is the default timeout set on shard mappers.
 	DefaultShardMapperTimeout = 5 * time.Second
 
+	// DefaultNodeFailureBackoff is the default time a data node is avoided
+	// for reads after a failed request.
+	DefaultNodeFailureBackoff = 30 * time.Second
+
 	// DefaultMaxRemoteWriteConnections is the maximum number of open connections
 	// that will be available for remote writes to another host.
 	DefaultMaxRemoteWriteConnections = 3
@@ -44,6 +48,7 @@ type Config struct {
 	ShardWriterTimeout        toml.Duration `toml:"shard-writer-timeout"`
 	MaxRemoteWriteConnections int           `toml:"max-remote-write-connections"`
 	ShardMapperTimeout        toml.Duration `toml:"shard-mapper-timeout"`
+	NodeFailureBackoff        toml.Duration `toml:"node-failure-backoff"`
 
 	MaxConcurrentQueries int           `toml:"max-concurrent-queries"`
 	QueryTimeout         toml.Duration `toml:"query-timeout"`
@@ -59,6 +64,7 @@ func NewConfig() Config {
 		WriteTimeout:              toml.Duration(DefaultWriteTimeout),
 		ShardWriterTimeout:        toml.Duration(DefaultShardWriterTimeout),
 		ShardMapperTimeout:        toml.Duration(DefaultShardMapperTimeout),
+		NodeFailureBackoff:        toml.Duration(DefaultNodeFailureBackoff),
 		MaxRemoteWriteConnections: DefaultMaxRemoteWriteConnections,
 
 		QueryTimeout:         toml.Duration(query.DefaultQueryTimeout),
@@ -1,5 +1,5 @@
 // Code generated by protoc-gen-gogo. DO NOT EDIT.
-// source: internal/data.proto
+// source: data.proto
 
 package internal
 
@@ -34,7 +34,7 @@ func (m *WriteShardRequest) Reset()         { *m = WriteShardRequest{} }
 func (m *WriteShardRequest) String() string { return proto.CompactTextString(m) }
 func (*WriteShardRequest) ProtoMessage()    {}
 func (*WriteShardRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7438786364df21e1, []int{0}
+	return fileDescriptor_871986018790d2fd, []int{0}
 }
 func (m *WriteShardRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_WriteShardRequest.Unmarshal(m, b)
@@ -85,6 +85,8 @@ func (m *WriteShardRequest) GetTimeToLive() string {
 type WriteShardResponse struct {
 	Code                 *int32   `protobuf:"varint,1,req,name=Code" json:"Code,omitempty"`
 	Message              *string  `protobuf:"bytes,2,opt,name=Message" json:"Message,omitempty"`
+	PartialWrite         *bool    `protobuf:"varint,3,opt,name=PartialWrite" json:"PartialWrite,omitempty"`
+	Dropped              *int64   `protobuf:"varint,4,opt,name=Dropped" json:"Dropped,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -94,7 +96,7 @@ func (m *WriteShardResponse) Reset()         { *m = WriteShardResponse{} }
 func (m *WriteShardResponse) String() string { return proto.CompactTextString(m) }
 func (*WriteShardResponse) ProtoMessage()    {}
 func (*WriteShardResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7438786364df21e1, []int{1}
+	return fileDescriptor_871986018790d2fd, []int{1}
 }
 func (m *WriteShardResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_WriteShardResponse.Unmarshal(m, b)
@@ -128,6 +130,20 @@ func (m *WriteShardResponse) GetMessage() string {
 	return ""
 }
 
+func (m *WriteShardResponse) GetPartialWrite() bool {
+	if m != nil && m.PartialWrite != nil {
+		return *m.PartialWrite
+	}
+	return false
+}
+
+func (m *WriteShardResponse) GetDropped() int64 {
+	if m != nil && m.Dropped != nil {
+		return *m.Dropped
+	}
+	return 0
+}
+
 type ExecuteStatementRequest struct {
 	Statement            *string  `protobuf:"bytes,1,req,name=Statement" json:"Statement,omitempty"`
 	Database             *string  `protobuf:"bytes,2,req,name=Database" json:"Database,omitempty"`
@@ -140,7 +156,7 @@ func (m *ExecuteStatementRequest) Reset()         { *m = ExecuteStatementRequest
 func (m *ExecuteStatementRequest) String() string { return proto.CompactTextString(m) }
 func (*ExecuteStatementRequest) ProtoMessage()    {}
 func (*ExecuteStatementRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7438786364df21e1, []int{2}
+	return fileDescriptor_871986018790d2fd, []int{2}
 }
 func (m *ExecuteStatementRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecuteStatementRequest.Unmarshal(m, b)
@@ -186,7 +202,7 @@ func (m *ExecuteStatementResponse) Reset()         { *m = ExecuteStatementRespon
 func (m *ExecuteStatementResponse) String() string { return proto.CompactTextString(m) }
 func (*ExecuteStatementResponse) ProtoMessage()    {}
 func (*ExecuteStatementResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7438786364df21e1, []int{3}
+	return fileDescriptor_871986018790d2fd, []int{3}
 }
 func (m *ExecuteStatementResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ExecuteStatementResponse.Unmarshal(m, b)
@@ -226,6 +242,10 @@ type CreateIteratorRequest struct {
 	Database             []byte   `protobuf:"bytes,3,req,name=Database" json:"Database,omitempty"`
 	TimeToLive           []byte   `protobuf:"bytes,4,req,name=TimeToLive" json:"TimeToLive,omitempty"`
 	MetricName           []byte   `protobuf:"bytes,5,req,name=MetricName" json:"MetricName,omitempty"`
+	Regex                *string  `protobuf:"bytes,6,opt,name=Regex" json:"Regex,omitempty"`
+	SystemIterator       *string  `protobuf:"bytes,7,opt,name=SystemIterator" json:"SystemIterator,omitempty"`
+	User                 *string  `protobuf:"bytes,8,opt,name=User" json:"User,omitempty"`
+	Roles                []string `protobuf:"bytes,9,rep,name=Roles" json:"Roles,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -235,7 +255,7 @@ func (m *CreateIteratorRequest) Reset()         { *m = CreateIteratorRequest{} }
 func (m *CreateIteratorRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateIteratorRequest) ProtoMessage()    {}
 func (*CreateIteratorRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7438786364df21e1, []int{4}
+	return fileDescriptor_871986018790d2fd, []int{4}
 }
 func (m *CreateIteratorRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateIteratorRequest.Unmarshal(m, b)
@@ -290,6 +310,34 @@ func (m *CreateIteratorRequest) GetMetricName() []byte {
 	return nil
 }
 
+func (m *CreateIteratorRequest) GetRegex() string {
+	if m != nil && m.Regex != nil {
+		return *m.Regex
+	}
+	return ""
+}
+
+func (m *CreateIteratorRequest) GetSystemIterator() string {
+	if m != nil && m.SystemIterator != nil {
+		return *m.SystemIterator
+	}
+	return ""
+}
+
+func (m *CreateIteratorRequest) GetUser() string {
+	if m != nil && m.User != nil {
+		return *m.User
+	}
+	return ""
+}
+
+func (m *CreateIteratorRequest) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
 type CreateIteratorResponse struct {
 	Err                  *string  `protobuf:"bytes,1,opt,name=Err" json:"Err,omitempty"`
 	DataType             *int32   `protobuf:"varint,2,opt,name=DataType" json:"DataType,omitempty"`
@@ -304,7 +352,7 @@ func (m *CreateIteratorResponse) Reset()         { *m = CreateIteratorResponse{}
 func (m *CreateIteratorResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateIteratorResponse) ProtoMessage()    {}
 func (*CreateIteratorResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7438786364df21e1, []int{5}
+	return fileDescriptor_871986018790d2fd, []int{5}
 }
 func (m *CreateIteratorResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateIteratorResponse.Unmarshal(m, b)
@@ -364,7 +412,7 @@ func (m *FieldDimensionsRequest) Reset()         { *m = FieldDimensionsRequest{}
 func (m *FieldDimensionsRequest) String() string { return proto.CompactTextString(m) }
 func (*FieldDimensionsRequest) ProtoMessage()    {}
 func (*FieldDimensionsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7438786364df21e1, []int{6}
+	return fileDescriptor_871986018790d2fd, []int{6}
 }
 func (m *FieldDimensionsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FieldDimensionsRequest.Unmarshal(m, b)
@@ -411,7 +459,7 @@ func (m *FieldDimensionsResponse) Reset()         { *m = FieldDimensionsResponse
 func (m *FieldDimensionsResponse) String() string { return proto.CompactTextString(m) }
 func (*FieldDimensionsResponse) ProtoMessage()    {}
 func (*FieldDimensionsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7438786364df21e1, []int{7}
+	return fileDescriptor_871986018790d2fd, []int{7}
 }
 func (m *FieldDimensionsResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FieldDimensionsResponse.Unmarshal(m, b)
@@ -452,6 +500,428 @@ func (m *FieldDimensionsResponse) GetErr() string {
 	return ""
 }
 
+type SeriesKeysRequest struct {
+	ShardIDs             []uint64 `protobuf:"varint,1,rep,name=ShardIDs" json:"ShardIDs,omitempty"`
+	Condition            *string  `protobuf:"bytes,2,opt,name=Condition" json:"Condition,omitempty"`
+	Reduce               *int32   `protobuf:"varint,3,opt,name=Reduce" json:"Reduce,omitempty"`
+	TagKeyCondition      *string  `protobuf:"bytes,4,opt,name=TagKeyCondition" json:"TagKeyCondition,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SeriesKeysRequest) Reset()         { *m = SeriesKeysRequest{} }
+func (m *SeriesKeysRequest) String() string { return proto.CompactTextString(m) }
+func (*SeriesKeysRequest) ProtoMessage()    {}
+func (*SeriesKeysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_871986018790d2fd, []int{8}
+}
+func (m *SeriesKeysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SeriesKeysRequest.Unmarshal(m, b)
+}
+func (m *SeriesKeysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SeriesKeysRequest.Marshal(b, m, deterministic)
+}
+func (m *SeriesKeysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeriesKeysRequest.Merge(m, src)
+}
+func (m *SeriesKeysRequest) XXX_Size() int {
+	return xxx_messageInfo_SeriesKeysRequest.Size(m)
+}
+func (m *SeriesKeysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeriesKeysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeriesKeysRequest proto.InternalMessageInfo
+
+func (m *SeriesKeysRequest) GetShardIDs() []uint64 {
+	if m != nil {
+		return m.ShardIDs
+	}
+	return nil
+}
+
+func (m *SeriesKeysRequest) GetCondition() string {
+	if m != nil && m.Condition != nil {
+		return *m.Condition
+	}
+	return ""
+}
+
+func (m *SeriesKeysRequest) GetReduce() int32 {
+	if m != nil && m.Reduce != nil {
+		return *m.Reduce
+	}
+	return 0
+}
+
+func (m *SeriesKeysRequest) GetTagKeyCondition() string {
+	if m != nil && m.TagKeyCondition != nil {
+		return *m.TagKeyCondition
+	}
+	return ""
+}
+
+type SeriesKeysResponse struct {
+	Keys                 []string `protobuf:"bytes,1,rep,name=Keys" json:"Keys,omitempty"`
+	Err                  *string  `protobuf:"bytes,2,opt,name=Err" json:"Err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SeriesKeysResponse) Reset()         { *m = SeriesKeysResponse{} }
+func (m *SeriesKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*SeriesKeysResponse) ProtoMessage()    {}
+func (*SeriesKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_871986018790d2fd, []int{9}
+}
+func (m *SeriesKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SeriesKeysResponse.Unmarshal(m, b)
+}
+func (m *SeriesKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SeriesKeysResponse.Marshal(b, m, deterministic)
+}
+func (m *SeriesKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeriesKeysResponse.Merge(m, src)
+}
+func (m *SeriesKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_SeriesKeysResponse.Size(m)
+}
+func (m *SeriesKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeriesKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeriesKeysResponse proto.InternalMessageInfo
+
+func (m *SeriesKeysResponse) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *SeriesKeysResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type SeriesSketchesRequest struct {
+	ShardIDs             []uint64 `protobuf:"varint,1,rep,name=ShardIDs" json:"ShardIDs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SeriesSketchesRequest) Reset()         { *m = SeriesSketchesRequest{} }
+func (m *SeriesSketchesRequest) String() string { return proto.CompactTextString(m) }
+func (*SeriesSketchesRequest) ProtoMessage()    {}
+func (*SeriesSketchesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_871986018790d2fd, []int{10}
+}
+func (m *SeriesSketchesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SeriesSketchesRequest.Unmarshal(m, b)
+}
+func (m *SeriesSketchesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SeriesSketchesRequest.Marshal(b, m, deterministic)
+}
+func (m *SeriesSketchesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeriesSketchesRequest.Merge(m, src)
+}
+func (m *SeriesSketchesRequest) XXX_Size() int {
+	return xxx_messageInfo_SeriesSketchesRequest.Size(m)
+}
+func (m *SeriesSketchesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeriesSketchesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeriesSketchesRequest proto.InternalMessageInfo
+
+func (m *SeriesSketchesRequest) GetShardIDs() []uint64 {
+	if m != nil {
+		return m.ShardIDs
+	}
+	return nil
+}
+
+type SeriesSketchesResponse struct {
+	Series               []byte   `protobuf:"bytes,1,opt,name=Series" json:"Series,omitempty"`
+	Tombstones           []byte   `protobuf:"bytes,2,opt,name=Tombstones" json:"Tombstones,omitempty"`
+	Err                  *string  `protobuf:"bytes,3,opt,name=Err" json:"Err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SeriesSketchesResponse) Reset()         { *m = SeriesSketchesResponse{} }
+func (m *SeriesSketchesResponse) String() string { return proto.CompactTextString(m) }
+func (*SeriesSketchesResponse) ProtoMessage()    {}
+func (*SeriesSketchesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_871986018790d2fd, []int{11}
+}
+func (m *SeriesSketchesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SeriesSketchesResponse.Unmarshal(m, b)
+}
+func (m *SeriesSketchesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SeriesSketchesResponse.Marshal(b, m, deterministic)
+}
+func (m *SeriesSketchesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SeriesSketchesResponse.Merge(m, src)
+}
+func (m *SeriesSketchesResponse) XXX_Size() int {
+	return xxx_messageInfo_SeriesSketchesResponse.Size(m)
+}
+func (m *SeriesSketchesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SeriesSketchesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SeriesSketchesResponse proto.InternalMessageInfo
+
+func (m *SeriesSketchesResponse) GetSeries() []byte {
+	if m != nil {
+		return m.Series
+	}
+	return nil
+}
+
+func (m *SeriesSketchesResponse) GetTombstones() []byte {
+	if m != nil {
+		return m.Tombstones
+	}
+	return nil
+}
+
+func (m *SeriesSketchesResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type QueryInfo struct {
+	ID                   *uint64  `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
+	Query                *string  `protobuf:"bytes,2,req,name=Query" json:"Query,omitempty"`
+	Database             *string  `protobuf:"bytes,3,req,name=Database" json:"Database,omitempty"`
+	Duration             *int64   `protobuf:"varint,4,req,name=Duration" json:"Duration,omitempty"`
+	Status               *int32   `protobuf:"varint,5,req,name=Status" json:"Status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryInfo) Reset()         { *m = QueryInfo{} }
+func (m *QueryInfo) String() string { return proto.CompactTextString(m) }
+func (*QueryInfo) ProtoMessage()    {}
+func (*QueryInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_871986018790d2fd, []int{12}
+}
+func (m *QueryInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInfo.Unmarshal(m, b)
+}
+func (m *QueryInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInfo.Marshal(b, m, deterministic)
+}
+func (m *QueryInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInfo.Merge(m, src)
+}
+func (m *QueryInfo) XXX_Size() int {
+	return xxx_messageInfo_QueryInfo.Size(m)
+}
+func (m *QueryInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInfo proto.InternalMessageInfo
+
+func (m *QueryInfo) GetID() uint64 {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return 0
+}
+
+func (m *QueryInfo) GetQuery() string {
+	if m != nil && m.Query != nil {
+		return *m.Query
+	}
+	return ""
+}
+
+func (m *QueryInfo) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *QueryInfo) GetDuration() int64 {
+	if m != nil && m.Duration != nil {
+		return *m.Duration
+	}
+	return 0
+}
+
+func (m *QueryInfo) GetStatus() int32 {
+	if m != nil && m.Status != nil {
+		return *m.Status
+	}
+	return 0
+}
+
+type ShowQueriesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShowQueriesRequest) Reset()         { *m = ShowQueriesRequest{} }
+func (m *ShowQueriesRequest) String() string { return proto.CompactTextString(m) }
+func (*ShowQueriesRequest) ProtoMessage()    {}
+func (*ShowQueriesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_871986018790d2fd, []int{13}
+}
+func (m *ShowQueriesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShowQueriesRequest.Unmarshal(m, b)
+}
+func (m *ShowQueriesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShowQueriesRequest.Marshal(b, m, deterministic)
+}
+func (m *ShowQueriesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShowQueriesRequest.Merge(m, src)
+}
+func (m *ShowQueriesRequest) XXX_Size() int {
+	return xxx_messageInfo_ShowQueriesRequest.Size(m)
+}
+func (m *ShowQueriesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShowQueriesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShowQueriesRequest proto.InternalMessageInfo
+
+type ShowQueriesResponse struct {
+	Queries              []*QueryInfo `protobuf:"bytes,1,rep,name=Queries" json:"Queries,omitempty"`
+	Err                  *string      `protobuf:"bytes,2,opt,name=Err" json:"Err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ShowQueriesResponse) Reset()         { *m = ShowQueriesResponse{} }
+func (m *ShowQueriesResponse) String() string { return proto.CompactTextString(m) }
+func (*ShowQueriesResponse) ProtoMessage()    {}
+func (*ShowQueriesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_871986018790d2fd, []int{14}
+}
+func (m *ShowQueriesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShowQueriesResponse.Unmarshal(m, b)
+}
+func (m *ShowQueriesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShowQueriesResponse.Marshal(b, m, deterministic)
+}
+func (m *ShowQueriesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShowQueriesResponse.Merge(m, src)
+}
+func (m *ShowQueriesResponse) XXX_Size() int {
+	return xxx_messageInfo_ShowQueriesResponse.Size(m)
+}
+func (m *ShowQueriesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShowQueriesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShowQueriesResponse proto.InternalMessageInfo
+
+func (m *ShowQueriesResponse) GetQueries() []*QueryInfo {
+	if m != nil {
+		return m.Queries
+	}
+	return nil
+}
+
+func (m *ShowQueriesResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
+type KillQueryRequest struct {
+	QueryID              *uint64  `protobuf:"varint,1,req,name=QueryID" json:"QueryID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KillQueryRequest) Reset()         { *m = KillQueryRequest{} }
+func (m *KillQueryRequest) String() string { return proto.CompactTextString(m) }
+func (*KillQueryRequest) ProtoMessage()    {}
+func (*KillQueryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_871986018790d2fd, []int{15}
+}
+func (m *KillQueryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KillQueryRequest.Unmarshal(m, b)
+}
+func (m *KillQueryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KillQueryRequest.Marshal(b, m, deterministic)
+}
+func (m *KillQueryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KillQueryRequest.Merge(m, src)
+}
+func (m *KillQueryRequest) XXX_Size() int {
+	return xxx_messageInfo_KillQueryRequest.Size(m)
+}
+func (m *KillQueryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KillQueryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KillQueryRequest proto.InternalMessageInfo
+
+func (m *KillQueryRequest) GetQueryID() uint64 {
+	if m != nil && m.QueryID != nil {
+		return *m.QueryID
+	}
+	return 0
+}
+
+type KillQueryResponse struct {
+	Err                  *string  `protobuf:"bytes,1,opt,name=Err" json:"Err,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KillQueryResponse) Reset()         { *m = KillQueryResponse{} }
+func (m *KillQueryResponse) String() string { return proto.CompactTextString(m) }
+func (*KillQueryResponse) ProtoMessage()    {}
+func (*KillQueryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_871986018790d2fd, []int{16}
+}
+func (m *KillQueryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KillQueryResponse.Unmarshal(m, b)
+}
+func (m *KillQueryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KillQueryResponse.Marshal(b, m, deterministic)
+}
+func (m *KillQueryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KillQueryResponse.Merge(m, src)
+}
+func (m *KillQueryResponse) XXX_Size() int {
+	return xxx_messageInfo_KillQueryResponse.Size(m)
+}
+func (m *KillQueryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_KillQueryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KillQueryResponse proto.InternalMessageInfo
+
+func (m *KillQueryResponse) GetErr() string {
+	if m != nil && m.Err != nil {
+		return *m.Err
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*WriteShardRequest)(nil), "internal.WriteShardRequest")
 	proto.RegisterType((*WriteShardResponse)(nil), "internal.WriteShardResponse")
@@ -461,35 +931,63 @@ func init() {
 	proto.RegisterType((*CreateIteratorResponse)(nil), "internal.CreateIteratorResponse")
 	proto.RegisterType((*FieldDimensionsRequest)(nil), "internal.FieldDimensionsRequest")
 	proto.RegisterType((*FieldDimensionsResponse)(nil), "internal.FieldDimensionsResponse")
-}
-
-func init() { proto.RegisterFile("internal/data.proto", fileDescriptor_7438786364df21e1) }
-
-var fileDescriptor_7438786364df21e1 = []byte{
-	// 398 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0x5f, 0x8b, 0xd3, 0x40,
-	0x14, 0xc5, 0xc9, 0xbf, 0xda, 0x5c, 0xfa, 0xb0, 0x8e, 0x98, 0x1d, 0x16, 0x91, 0x90, 0xa7, 0x3c,
-	0xe9, 0x77, 0x70, 0xbb, 0xe2, 0xc2, 0x6e, 0x94, 0x69, 0xc1, 0xe7, 0xb1, 0xb9, 0xe8, 0xc0, 0x26,
-	0x13, 0x67, 0xee, 0x4a, 0x7d, 0xf4, 0xa3, 0xf8, 0x4d, 0x25, 0xd3, 0x99, 0x34, 0xb6, 0x08, 0xb2,
-	0x6f, 0xf3, 0x3b, 0x37, 0x5c, 0xce, 0x3d, 0x39, 0xf0, 0x42, 0xf5, 0x84, 0xa6, 0x97, 0x0f, 0x6f,
-	0x5b, 0x49, 0xf2, 0xcd, 0x60, 0x34, 0x69, 0xb6, 0x0c, 0x62, 0xf5, 0x2b, 0x82, 0xe7, 0x9f, 0x8d,
-	0x22, 0xdc, 0x7c, 0x93, 0xa6, 0x15, 0xf8, 0xfd, 0x11, 0x2d, 0x31, 0x0e, 0xcf, 0x1c, 0xdf, 0xae,
-	0x79, 0x54, 0xc6, 0x75, 0x2a, 0x02, 0xb2, 0x02, 0x16, 0x9f, 0xb4, 0xea, 0xc9, 0xf2, 0xb8, 0x4c,
-	0xea, 0x95, 0xf0, 0xc4, 0xae, 0x60, 0xb9, 0x96, 0x24, 0xbf, 0x48, 0x8b, 0x3c, 0x29, 0xa3, 0x3a,
-	0x17, 0x13, 0xb3, 0xd7, 0x00, 0x5b, 0xd5, 0xe1, 0x56, 0xdf, 0xa9, 0x1f, 0xc8, 0x53, 0x37, 0x9d,
-	0x29, 0xd5, 0x3b, 0x60, 0x73, 0x0b, 0x76, 0xd0, 0xbd, 0x45, 0xc6, 0x20, 0xbd, 0xd6, 0x2d, 0x3a,
-	0x03, 0x99, 0x70, 0xef, 0xd1, 0xd7, 0x3d, 0x5a, 0x2b, 0xbf, 0x22, 0x8f, 0xdd, 0x9a, 0x80, 0xd5,
-	0x06, 0x2e, 0x6f, 0xf6, 0xb8, 0x7b, 0x24, 0xdc, 0x90, 0x24, 0xec, 0xb0, 0xa7, 0x70, 0xcc, 0x2b,
-	0xc8, 0x27, 0xcd, 0x6d, 0xcb, 0xc5, 0x51, 0xf8, 0xcb, 0x78, 0xec, 0x86, 0x13, 0x57, 0x1f, 0x80,
-	0x9f, 0x2f, 0x7d, 0x92, 0xbd, 0xdf, 0x11, 0xbc, 0xbc, 0x36, 0x28, 0x09, 0x6f, 0x09, 0x8d, 0x24,
-	0x6d, 0x82, 0xbb, 0x2b, 0x58, 0xfa, 0x6c, 0x2d, 0x8f, 0xca, 0xa4, 0x4e, 0xc5, 0xc4, 0xec, 0x02,
-	0x92, 0x8f, 0x03, 0x39, 0x5b, 0x2b, 0x31, 0x3e, 0x4f, 0x62, 0x1e, 0xe5, 0x7f, 0xc7, 0x3c, 0x4e,
-	0x67, 0xca, 0x38, 0xbf, 0x47, 0x32, 0x6a, 0xd7, 0xc8, 0x0e, 0x79, 0x76, 0x98, 0x1f, 0x95, 0x6a,
-	0x0f, 0xc5, 0xa9, 0x45, 0x7f, 0xeb, 0x05, 0x24, 0x37, 0xc6, 0xf0, 0xc8, 0xdd, 0x34, 0x3e, 0x83,
-	0x8f, 0xed, 0xcf, 0xe1, 0x70, 0x6a, 0x26, 0x26, 0x76, 0xe5, 0x41, 0xa3, 0xd0, 0x36, 0xae, 0x09,
-	0x99, 0x08, 0x38, 0x95, 0xa7, 0x71, 0x25, 0xc8, 0x7c, 0x79, 0x9a, 0xea, 0x0e, 0x8a, 0xf7, 0x0a,
-	0x1f, 0xda, 0xb5, 0xea, 0xb0, 0xb7, 0x4a, 0xf7, 0xf6, 0x7f, 0xd2, 0x29, 0x60, 0x71, 0x70, 0xef,
-	0x03, 0xf2, 0x54, 0xed, 0xe0, 0xf2, 0x6c, 0x9b, 0x3f, 0xa4, 0x80, 0x85, 0x1b, 0x59, 0xf7, 0xdb,
-	0x56, 0xc2, 0xd3, 0x18, 0xcd, 0xf1, 0x6b, 0xd7, 0xec, 0x5c, 0xcc, 0x94, 0x10, 0x40, 0x32, 0x05,
-	0xf0, 0x27, 0x00, 0x00, 0xff, 0xff, 0xdd, 0x72, 0x8e, 0x71, 0x57, 0x03, 0x00, 0x00,
+	proto.RegisterType((*SeriesKeysRequest)(nil), "internal.SeriesKeysRequest")
+	proto.RegisterType((*SeriesKeysResponse)(nil), "internal.SeriesKeysResponse")
+	proto.RegisterType((*SeriesSketchesRequest)(nil), "internal.SeriesSketchesRequest")
+	proto.RegisterType((*SeriesSketchesResponse)(nil), "internal.SeriesSketchesResponse")
+	proto.RegisterType((*QueryInfo)(nil), "internal.QueryInfo")
+	proto.RegisterType((*ShowQueriesRequest)(nil), "internal.ShowQueriesRequest")
+	proto.RegisterType((*ShowQueriesResponse)(nil), "internal.ShowQueriesResponse")
+	proto.RegisterType((*KillQueryRequest)(nil), "internal.KillQueryRequest")
+	proto.RegisterType((*KillQueryResponse)(nil), "internal.KillQueryResponse")
+}
+
+func init() { proto.RegisterFile("data.proto", fileDescriptor_871986018790d2fd) }
+
+var fileDescriptor_871986018790d2fd = []byte{
+	// 700 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x4d, 0x4f, 0x1b, 0x49,
+	0x10, 0xd5, 0xcc, 0xd8, 0xc6, 0x53, 0x6b, 0xb1, 0xd0, 0x80, 0x19, 0x21, 0xb4, 0xb2, 0x46, 0xda,
+	0x95, 0x0f, 0xbb, 0x1c, 0x76, 0x6f, 0x7b, 0xc5, 0x44, 0xb1, 0x00, 0x87, 0xb4, 0x9d, 0xe4, 0xdc,
+	0xd8, 0x15, 0x68, 0xc5, 0x9e, 0x76, 0xba, 0xdb, 0x09, 0xbe, 0x85, 0x63, 0x4e, 0xf9, 0xcb, 0x51,
+	0xd7, 0xf4, 0x7c, 0x60, 0x40, 0x42, 0xb9, 0xf5, 0x7b, 0xd5, 0x5d, 0x7e, 0xf5, 0xaa, 0xca, 0x03,
+	0x30, 0x13, 0x56, 0x9c, 0x2c, 0xb5, 0xb2, 0x8a, 0xb5, 0x65, 0x66, 0x51, 0x67, 0x62, 0x9e, 0xde,
+	0x07, 0xb0, 0xfb, 0x41, 0x4b, 0x8b, 0xe3, 0x5b, 0xa1, 0x67, 0x1c, 0x3f, 0xaf, 0xd0, 0x58, 0x96,
+	0xc0, 0x16, 0xe1, 0xe1, 0x20, 0x09, 0x7a, 0x61, 0xbf, 0xc1, 0x0b, 0xc8, 0xba, 0xd0, 0xba, 0x52,
+	0x32, 0xb3, 0x26, 0x09, 0x7b, 0x51, 0xbf, 0xc3, 0x3d, 0x62, 0x47, 0xd0, 0x1e, 0x08, 0x2b, 0xae,
+	0x85, 0xc1, 0x24, 0xea, 0x05, 0xfd, 0x98, 0x97, 0x98, 0xfd, 0x01, 0x30, 0x91, 0x0b, 0x9c, 0xa8,
+	0x0b, 0xf9, 0x05, 0x93, 0x06, 0x45, 0x6b, 0x4c, 0xfa, 0x2d, 0x00, 0x56, 0xd7, 0x60, 0x96, 0x2a,
+	0x33, 0xc8, 0x18, 0x34, 0x4e, 0xd5, 0x0c, 0x49, 0x41, 0x93, 0xd3, 0xd9, 0x09, 0xbb, 0x44, 0x63,
+	0xc4, 0x0d, 0x26, 0x21, 0xe5, 0x29, 0x20, 0x4b, 0xa1, 0x73, 0x25, 0xb4, 0x95, 0x62, 0x4e, 0xa9,
+	0x48, 0x44, 0x9b, 0x3f, 0xe0, 0xdc, 0xeb, 0x81, 0x56, 0xcb, 0x25, 0xce, 0x48, 0x45, 0xc4, 0x0b,
+	0x98, 0x8e, 0xe1, 0xf0, 0xec, 0x0e, 0xa7, 0x2b, 0x8b, 0x63, 0x2b, 0x2c, 0x2e, 0x30, 0xb3, 0x85,
+	0x17, 0xc7, 0x10, 0x97, 0x1c, 0x69, 0x89, 0x79, 0x45, 0x3c, 0xa8, 0x3b, 0xa4, 0x60, 0x89, 0xd3,
+	0xd7, 0x90, 0x3c, 0x4e, 0xfa, 0x2b, 0xc5, 0xa5, 0xdf, 0x43, 0x38, 0x38, 0xd5, 0x28, 0x2c, 0x0e,
+	0x2d, 0x6a, 0x61, 0x95, 0x2e, 0xd4, 0x1d, 0x41, 0xdb, 0xb7, 0xc6, 0x24, 0x41, 0x2f, 0xea, 0x37,
+	0x78, 0x89, 0xd9, 0x0e, 0x44, 0x6f, 0x96, 0x96, 0x64, 0x75, 0xb8, 0x3b, 0x6e, 0x74, 0xc9, 0xd1,
+	0xcf, 0x77, 0xc9, 0x45, 0x6b, 0x8c, 0x8b, 0x5f, 0xa2, 0xd5, 0x72, 0x3a, 0x12, 0x0b, 0x4c, 0x9a,
+	0x79, 0xbc, 0x62, 0xd8, 0x3e, 0x34, 0x39, 0xde, 0xe0, 0x5d, 0xd2, 0x22, 0xed, 0x39, 0x60, 0x7f,
+	0xc1, 0xf6, 0x78, 0x6d, 0x2c, 0x2e, 0x0a, 0xe1, 0xc9, 0x16, 0x85, 0x37, 0x58, 0xe7, 0xc7, 0x3b,
+	0x83, 0x3a, 0x69, 0x53, 0x94, 0xce, 0x94, 0x51, 0xcd, 0xd1, 0x24, 0x71, 0x2f, 0xa2, 0x8c, 0x0e,
+	0xa4, 0x77, 0xd0, 0xdd, 0xb4, 0xc2, 0x7b, 0xba, 0x03, 0xd1, 0x99, 0xd6, 0x49, 0x40, 0x29, 0xdc,
+	0xb1, 0xa8, 0x77, 0xb2, 0x5e, 0xe6, 0x96, 0x36, 0x79, 0x89, 0x69, 0xc6, 0x51, 0x4b, 0x34, 0x23,
+	0x9a, 0x95, 0x26, 0x2f, 0x60, 0x39, 0xe3, 0x23, 0x9a, 0x92, 0xa6, 0x9f, 0xf1, 0x51, 0x7a, 0x01,
+	0xdd, 0x57, 0x12, 0xe7, 0xb3, 0x81, 0x5c, 0x60, 0x66, 0xa4, 0xca, 0xcc, 0x4b, 0xba, 0xd0, 0x85,
+	0x56, 0xee, 0x92, 0x6f, 0x84, 0x47, 0xe9, 0x14, 0x0e, 0x1f, 0x65, 0xf3, 0x85, 0x74, 0xa1, 0x45,
+	0x21, 0x43, 0xe3, 0xd1, 0xe1, 0x1e, 0xb9, 0x16, 0x54, 0xb7, 0x69, 0x01, 0x63, 0x5e, 0x63, 0x0a,
+	0x03, 0xa2, 0xd2, 0x80, 0xf4, 0x47, 0x00, 0xbb, 0x79, 0x59, 0xe7, 0xb8, 0x7e, 0x91, 0xdc, 0x63,
+	0x88, 0x4f, 0x55, 0x36, 0x93, 0x56, 0xaa, 0xcc, 0x8f, 0x61, 0x45, 0x38, 0x65, 0x1c, 0x67, 0xab,
+	0x29, 0x7a, 0xcf, 0x3c, 0x62, 0x7d, 0xf8, 0x7d, 0x22, 0x6e, 0xce, 0x71, 0x5d, 0xbd, 0xcd, 0xf7,
+	0x7c, 0x93, 0x4e, 0xff, 0x07, 0x56, 0x17, 0x54, 0xad, 0x83, 0xc3, 0xa4, 0x26, 0xe6, 0x74, 0x2e,
+	0xaa, 0x09, 0xab, 0x6a, 0xfe, 0x83, 0x83, 0xfc, 0xed, 0xf8, 0x13, 0xda, 0xe9, 0x2d, 0xbe, 0xa4,
+	0xa0, 0xf4, 0x1a, 0xba, 0x9b, 0x8f, 0x2a, 0x9b, 0xf3, 0x08, 0x8d, 0x4c, 0x87, 0x7b, 0x44, 0x9b,
+	0xa0, 0x16, 0xd7, 0xc6, 0xaa, 0x0c, 0x0d, 0xfd, 0x7e, 0x87, 0xd7, 0x98, 0x27, 0x6c, 0xbe, 0x0f,
+	0x20, 0x7e, 0xbb, 0x42, 0xbd, 0x1e, 0x66, 0x1f, 0x15, 0xdb, 0x86, 0xb0, 0xfc, 0xe3, 0x0c, 0x87,
+	0x03, 0x37, 0xc7, 0x14, 0xf4, 0x7f, 0x10, 0x39, 0x78, 0xb4, 0x8b, 0xf5, 0x7f, 0x4c, 0x17, 0x5b,
+	0x69, 0xe1, 0x7d, 0x0c, 0xfb, 0x11, 0x2f, 0x31, 0xa9, 0xb6, 0xc2, 0xae, 0x0c, 0xed, 0x60, 0x93,
+	0x7b, 0x94, 0xee, 0x03, 0x1b, 0xdf, 0xaa, 0xaf, 0x2e, 0xb9, 0x2c, 0x9d, 0x49, 0xdf, 0xc3, 0xde,
+	0x03, 0xd6, 0x97, 0xfe, 0x0f, 0x6c, 0x79, 0x8a, 0xfc, 0xfa, 0xed, 0xdf, 0xbd, 0x93, 0xe2, 0x93,
+	0x70, 0x52, 0x16, 0xc2, 0x8b, 0x3b, 0x4f, 0xb4, 0xe2, 0x6f, 0xd8, 0x39, 0x97, 0xf3, 0x39, 0xdd,
+	0xad, 0x7d, 0x35, 0xf2, 0xb7, 0xe5, 0x57, 0xc3, 0xc3, 0xf4, 0x4f, 0xd8, 0xad, 0xdd, 0x7e, 0x6e,
+	0x5d, 0x7f, 0x0e, 0x00, 0xc6, 0x4b, 0xd0, 0x1c, 0xa3, 0x06, 0x00, 0x00,
 }
@@ -13,8 +13,9 @@ type MetaClient interface {
 	CreateDatabase(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithTimeToLive(name string, spec *meta.TimeToLiveSpec) (*meta.DatabaseInfo, error)
 	CreateTimeToLive(database string, spec *meta.TimeToLiveSpec, makeDefault bool) (*meta.TimeToLiveInfo, error)
-	CreateSubscription(database, ttl, name, mode string, destinations []string) error
+	CreateSubscription(database, ttl, name, mode string, destinations []string, durable bool) error
 	CreateUser(name, password string, admin bool) (meta.User, error)
+	CreateRole(name string) error
 	Database(name string) *meta.DatabaseInfo
 	Databases() []meta.DatabaseInfo
 	DataNode(id uint64) (*meta.NodeInfo, error)
@@ -28,10 +29,17 @@ type MetaClient interface {
 	DropTimeToLive(database, name string) error
 	DropSubscription(database, ttl, name string) error
 	DropUser(name string) error
+	DropRole(name string) error
 	RegionsByTimeRange(database, ttl string, min, max time.Time) (a []meta.RegionInfo, err error)
 	SetAdminPrivilege(username string, admin bool) error
 	SetDefaultTimeToLive(database, name string) error
 	SetPrivilege(username, database string, p cnosql.Privilege) error
+	SetDatabaseQuota(name string, q meta.Quota) error
+	SetUserQuota(name string, q meta.Quota) error
+	SetRolePrivilege(name, database string, p cnosql.Privilege) error
+	SetRoleRole(name, role string, granted bool) error
+	SetUserRole(username, role string, granted bool) error
+	SetSeriesPrivilege(username, database, metric, condition string, p cnosql.Privilege) error
 	ShardsByTimeRange(sources cnosql.Sources, tmin, tmax time.Time) (a []meta.ShardInfo, err error)
 	TimeToLive(database, name string) (ttl *meta.TimeToLiveInfo, err error)
 	TruncateRegions(t time.Time) error
@@ -39,5 +47,9 @@ type MetaClient interface {
 	UpdateUser(name, password string) error
 	UserPrivilege(username, database string) (*cnosql.Privilege, error)
 	UserPrivileges(username string) (map[string]cnosql.Privilege, error)
+	UserSeriesPrivilege(username, database, metric, condition string) (*cnosql.Privilege, error)
+	UserSeriesPrivileges(username string) ([]meta.SeriesPrivilege, error)
 	Users() []meta.UserInfo
+	Roles() []meta.RoleInfo
+	RolePrivilege(name, database string) (*cnosql.Privilege, error)
 }
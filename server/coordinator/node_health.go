@@ -0,0 +1,124 @@
+package coordinator
+
+import (
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cnosdatabase/db/models"
+)
+
+// The keys for statistics generated by the "remoteRead" module.
+const (
+	statRemoteReadFailures = "failures"
+	statRemoteReadTimeouts = "timeouts"
+	statRemoteReadHealthy  = "healthy"
+)
+
+// NodeHealth tracks recent failures when reading from remote data nodes so
+// that healthy shard owners are preferred over ones that just failed.
+// A nil *NodeHealth considers every node healthy.
+type NodeHealth struct {
+	mu    sync.RWMutex
+	nodes map[uint64]*nodeStatus
+
+	// Backoff is how long a node is considered unhealthy after a failure.
+	Backoff time.Duration
+}
+
+// nodeStatus holds the recent failures of a single node.
+type nodeStatus struct {
+	failures    int
+	timeouts    int
+	lastFailure time.Time
+}
+
+// NewNodeHealth returns a new instance of NodeHealth.
+func NewNodeHealth(backoff time.Duration) *NodeHealth {
+	return &NodeHealth{
+		nodes:   make(map[uint64]*nodeStatus),
+		Backoff: backoff,
+	}
+}
+
+// Healthy returns true if the node has not failed within the backoff period.
+func (h *NodeHealth) Healthy(nodeID uint64) bool {
+	if h == nil {
+		return true
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	ns := h.nodes[nodeID]
+	return ns == nil || ns.failures == 0 || time.Since(ns.lastFailure) >= h.Backoff
+}
+
+// Success clears the recorded failures of the node.
+func (h *NodeHealth) Success(nodeID uint64) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	delete(h.nodes, nodeID)
+	h.mu.Unlock()
+}
+
+// Failure records a failed request to the node.
+func (h *NodeHealth) Failure(nodeID uint64, err error) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ns := h.nodes[nodeID]
+	if ns == nil {
+		ns = &nodeStatus{}
+		h.nodes[nodeID] = ns
+	}
+	ns.failures++
+	if err, ok := err.(net.Error); ok && err.Timeout() {
+		ns.timeouts++
+	}
+	ns.lastFailure = time.Now()
+}
+
+// Order returns the node IDs with the healthy nodes first. The relative
+// order of the nodes is otherwise preserved.
+func (h *NodeHealth) Order(nodeIDs []uint64) []uint64 {
+	a := make([]uint64, 0, len(nodeIDs))
+	var unhealthy []uint64
+	for _, id := range nodeIDs {
+		if h.Healthy(id) {
+			a = append(a, id)
+		} else {
+			unhealthy = append(unhealthy, id)
+		}
+	}
+	return append(a, unhealthy...)
+}
+
+// Statistics returns statistics for periodic monitoring.
+func (h *NodeHealth) Statistics(tags map[string]string) []models.Statistic {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	statistics := make([]models.Statistic, 0, len(h.nodes))
+	for id, ns := range h.nodes {
+		statistics = append(statistics, models.Statistic{
+			Name: "remoteRead",
+			Tags: models.StatisticTags{"nodeID": strconv.FormatUint(id, 10)}.Merge(tags),
+			Values: map[string]interface{}{
+				statRemoteReadFailures: ns.failures,
+				statRemoteReadTimeouts: ns.timeouts,
+				statRemoteReadHealthy:  time.Since(ns.lastFailure) >= h.Backoff,
+			},
+		})
+	}
+	return statistics
+}
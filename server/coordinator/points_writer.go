@@ -29,6 +29,7 @@ const (
 	statWritePointReqHH     = "pointReqHH"
 	statSubWriteOK          = "subWriteOk"
 	statSubWriteDrop        = "subWriteDrop"
+	statSubWriteDurableDrop = "subWriteDurableDrop"
 )
 
 var (
@@ -47,6 +48,10 @@ var (
 	ErrInvalidConsistencyLevel = errors.New("invalid consistency level")
 )
 
+// durableDropLogInterval is the minimum interval between the warnings about
+// the writes dropped for the durable subscriptions.
+const durableDropLogInterval = time.Minute
+
 // PointsWriter handles writes across multiple local and remote data nodes.
 type PointsWriter struct {
 	mu           sync.RWMutex
@@ -78,8 +83,26 @@ type PointsWriter struct {
 	Subscriber interface {
 		Points() chan<- *WritePointsRequest
 	}
+
+	// DurableSubscriber queues the writes of durable subscriptions on disk
+	// once a write is written to the shards, so that they aren't dropped
+	// like the writes sent to the subscriber channels while the destinations
+	// are unreachable. A write that can't be queued is dropped for the
+	// subscriptions, it never fails the write.
+	DurableSubscriber interface {
+		WriteDurable(p *WritePointsRequest) error
+	}
+
+	// Quotas limits the points written per second to a database or by a user.
+	Quotas interface {
+		WritePoints(database, user string, n int) error
+	}
+
 	subPoints []chan<- *WritePointsRequest
 
+	// Time a dropped durable subscription write was last logged, in nanoseconds.
+	lastDurableDropLog int64
+
 	stats *WriteStatistics
 }
 
@@ -166,6 +189,7 @@ type WriteStatistics struct {
 	WritePointReqHH     int64
 	SubWriteOK          int64
 	SubWriteDrop        int64
+	SubWriteDurableDrop int64
 }
 
 // Statistics returns statistics for periodic monitoring.
@@ -186,6 +210,7 @@ func (w *PointsWriter) Statistics(tags map[string]string) []models.Statistic {
 			statWritePointReqHH:     atomic.LoadInt64(&w.stats.WritePointReqHH),
 			statSubWriteOK:          atomic.LoadInt64(&w.stats.SubWriteOK),
 			statSubWriteDrop:        atomic.LoadInt64(&w.stats.SubWriteDrop),
+			statSubWriteDurableDrop: atomic.LoadInt64(&w.stats.SubWriteDurableDrop),
 		},
 	}}
 }
@@ -340,8 +365,29 @@ func (w *PointsWriter) WritePointsInto(p *IntoWriteRequest) error {
 	return w.WritePointsPrivileged(p.Database, p.TimeToLive, models.ConsistencyLevelOne, p.Points)
 }
 
-// WritePoints writes data to the underlying storage. consitencyLevel and user are only used for clustered scenarios.
+// WritePoints writes data to the underlying storage. consitencyLevel is only used for clustered scenarios.
+// The write fails if user is granted writes on some series only, and a point is in another series.
 func (w *PointsWriter) WritePoints(database, timeToLive string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error {
+	if user != nil && !user.IsOpen() {
+		for _, p := range points {
+			if !user.AuthorizeSeriesWrite(database, p.Name(), p.Tags()) {
+				return meta.ErrAuthorize{
+					User:     user.ID(),
+					Database: database,
+					Message:  fmt.Sprintf("write to series %q", p.Key()),
+				}
+			}
+		}
+	}
+	if w.Quotas != nil {
+		var username string
+		if user != nil {
+			username = user.ID()
+		}
+		if err := w.Quotas.WritePoints(database, username, len(points)); err != nil {
+			return err
+		}
+	}
 	return w.WritePointsPrivileged(database, timeToLive, consistencyLevel, points)
 }
 
@@ -359,7 +405,8 @@ func (w *PointsWriter) WritePointsPrivileged(database, timeToLive string, consis
 		timeToLive = db.DefaultTimeToLive
 	}
 
-	shardMappings, err := w.MapShards(&WritePointsRequest{Database: database, TimeToLive: timeToLive, Points: points})
+	pts := &WritePointsRequest{Database: database, TimeToLive: timeToLive, Points: points}
+	shardMappings, err := w.MapShards(pts)
 	if err != nil {
 		return err
 	}
@@ -378,7 +425,6 @@ func (w *PointsWriter) WritePointsPrivileged(database, timeToLive string, consis
 
 	// Send points to subscriptions if possible.
 	var ok, dropped int64
-	pts := &WritePointsRequest{Database: database, TimeToLive: timeToLive, Points: points}
 	// We need to lock just in case the channel is about to be nil'ed
 	w.mu.RLock()
 	for _, ch := range w.subPoints {
@@ -419,9 +465,32 @@ func (w *PointsWriter) WritePointsPrivileged(database, timeToLive string, consis
 			}
 		}
 	}
+
+	// Queue the write for the durable subscriptions once it is written, so
+	// that they only receive the writes acknowledged to the client.
+	if w.DurableSubscriber != nil {
+		if err := w.DurableSubscriber.WriteDurable(pts); err != nil {
+			w.durableDropped(err)
+		}
+	}
 	return err
 }
 
+// durableDropped counts a write dropped for the durable subscriptions, and
+// logs it at most once per durableDropLogInterval.
+func (w *PointsWriter) durableDropped(err error) {
+	atomic.AddInt64(&w.stats.SubWriteDurableDrop, 1)
+
+	now := time.Now().UnixNano()
+	last := atomic.LoadInt64(&w.lastDurableDropLog)
+	if now-last < int64(durableDropLogInterval) || !atomic.CompareAndSwapInt64(&w.lastDurableDropLog, last, now) {
+		return
+	}
+	w.Logger.Warn("Dropped write for durable subscriptions",
+		zap.Error(err),
+		zap.Int64("dropped_total", atomic.LoadInt64(&w.stats.SubWriteDurableDrop)))
+}
+
 // writeToShard writes points to a shard and ensures a write consistency level has been met.  If the write
 // partially succeeds, ErrPartialWrite is returned.
 func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, timeToLive string, consistency models.ConsistencyLevel, points []models.Point) error {
@@ -522,6 +591,12 @@ func (w *PointsWriter) writeToShard(shard *meta.ShardInfo, database, timeToLive
 	}
 
 	if writeError != nil {
+		// Points rejected by the shard, on this node or a remote one, are
+		// reported as such, so that the writer knows they can't be written
+		// again.
+		if werr, ok := writeError.(tsdb.PartialWriteError); ok {
+			return werr
+		}
 		return fmt.Errorf("write failed: %v", writeError)
 	}
 
@@ -0,0 +1,172 @@
+package coordinator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb"
+	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/db/query"
+)
+
+// NodeQueryInfo represents a query running on a data node.
+type NodeQueryInfo struct {
+	NodeID uint64
+	query.QueryInfo
+}
+
+// QueryManager lists and kills the queries running on all data nodes. The
+// queries of this node are managed by its TaskManager, the queries of the
+// other nodes over the coordinator service.
+type QueryManager struct {
+	Node *cnosdb.Node
+
+	TaskManager interface {
+		Queries() []query.QueryInfo
+		KillQuery(qid uint64) error
+	}
+
+	MetaClient interface {
+		DataNode(id uint64) (*meta.NodeInfo, error)
+		DataNodes() ([]meta.NodeInfo, error)
+	}
+
+	Timeout time.Duration
+}
+
+// NewQueryManager returns a new instance of QueryManager.
+func NewQueryManager() *QueryManager {
+	return &QueryManager{
+		Timeout: DefaultShardMapperTimeout,
+	}
+}
+
+// Queries returns the queries running on all data nodes, sorted by node and
+// query ID. The nodes which can't be reached are returned as errors.
+func (m *QueryManager) Queries() ([]NodeQueryInfo, []error) {
+	nodes, err := m.MetaClient.DataNodes()
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	// The queries of this node are listed even if it hasn't joined the cluster.
+	ids := []uint64{m.localID()}
+	for _, node := range nodes {
+		if !m.isLocal(node.ID) {
+			ids = append(ids, node.ID)
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		queries []NodeQueryInfo
+		errs    []error
+	)
+	for _, id := range ids {
+		wg.Add(1)
+		go func(nodeID uint64) {
+			defer wg.Done()
+
+			a, err := m.nodeQueries(nodeID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("node %d: %s", nodeID, err))
+				return
+			}
+			for _, qi := range a {
+				queries = append(queries, NodeQueryInfo{NodeID: nodeID, QueryInfo: qi})
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	sort.Slice(queries, func(i, j int) bool {
+		if queries[i].NodeID != queries[j].NodeID {
+			return queries[i].NodeID < queries[j].NodeID
+		}
+		return queries[i].ID < queries[j].ID
+	})
+	return queries, errs
+}
+
+// nodeQueries returns the queries running on a single node.
+func (m *QueryManager) nodeQueries(nodeID uint64) ([]query.QueryInfo, error) {
+	if m.isLocal(nodeID) {
+		return m.TaskManager.Queries(), nil
+	}
+
+	dialer := &NodeDialer{MetaClient: m.MetaClient, Timeout: m.Timeout}
+	conn, err := dialer.DialNode(nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := EncodeTLV(conn, showQueriesRequestMessage, &ShowQueriesRequest{}); err != nil {
+		return nil, err
+	}
+
+	var resp ShowQueriesResponse
+	if _, err := DecodeTLV(conn, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Queries, resp.Err
+}
+
+// KillQuery kills a query running on a node. A zero nodeID is this node.
+func (m *QueryManager) KillQuery(nodeID, qid uint64) error {
+	if nodeID == 0 || m.isLocal(nodeID) {
+		return m.TaskManager.KillQuery(qid)
+	}
+
+	dialer := &NodeDialer{MetaClient: m.MetaClient, Timeout: m.Timeout}
+	conn, err := dialer.DialNode(nodeID)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := EncodeTLV(conn, killQueryRequestMessage, &KillQueryRequest{QueryID: qid}); err != nil {
+		return err
+	}
+
+	var resp KillQueryResponse
+	if _, err := DecodeTLV(conn, &resp); err != nil {
+		return err
+	}
+	return resp.Err
+}
+
+// NodeID returns the ID of the data node with the given host, which is either
+// its HTTP or its TCP address.
+func (m *QueryManager) NodeID(host string) (uint64, error) {
+	nodes, err := m.MetaClient.DataNodes()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, n := range nodes {
+		if n.Host == host || n.TCPHost == host {
+			return n.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("no data node with host %q", host)
+}
+
+// localID returns the ID of this node.
+func (m *QueryManager) localID() uint64 {
+	if m.Node == nil {
+		return 0
+	}
+	return m.Node.ID
+}
+
+// isLocal returns true if nodeID is the ID of this node.
+func (m *QueryManager) isLocal(nodeID uint64) bool {
+	return nodeID == m.localID()
+}
@@ -0,0 +1,242 @@
+package coordinator
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/db/models"
+)
+
+// Statistics for the Quotas.
+const (
+	statWritePointsRejected = "writePointsRejected" // Number of writes rejected by a points quota
+	statWriteBytesRejected  = "writeBytesRejected"  // Number of writes rejected by a bytes quota
+	statQueriesRejected     = "queriesRejected"     // Number of queries rejected by a concurrency quota
+)
+
+// QuotaExceededError is returned when a write or a query exceeds the quota of
+// a database or a user.
+type QuotaExceededError struct {
+	// Database or user the quota belongs to, e.g. `database "db0"`.
+	Scope string
+
+	// The exceeded limit.
+	Limit string
+}
+
+// Error returns the string representation of the error.
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded: %s is limited to %s", e.Scope, e.Limit)
+}
+
+// Quotas enforces the quotas of the databases and the users stored in meta.
+// Write rates are limited by token buckets holding up to one second of
+// writes; a write larger than the available tokens is accepted but delays
+// the following ones until the bucket has refilled.
+//
+// The buckets and the running queries are kept by each data node, so the
+// quotas limit the writes and queries received by a node, not the cluster.
+type Quotas struct {
+	MetaClient interface {
+		Database(name string) *meta.DatabaseInfo
+		User(name string) (meta.User, error)
+	}
+
+	mu       sync.Mutex
+	buckets  map[string]*tokenBucket
+	running  map[string]int
+	stats    *QuotaStatistics
+	timeFunc func() time.Time
+}
+
+// NewQuotas returns a new instance of Quotas.
+func NewQuotas() *Quotas {
+	return &Quotas{
+		buckets:  make(map[string]*tokenBucket),
+		running:  make(map[string]int),
+		stats:    &QuotaStatistics{},
+		timeFunc: time.Now,
+	}
+}
+
+// QuotaStatistics keeps statistics related to the Quotas.
+type QuotaStatistics struct {
+	WritePointsRejected int64
+	WriteBytesRejected  int64
+	QueriesRejected     int64
+}
+
+// Statistics returns statistics for periodic monitoring.
+func (q *Quotas) Statistics(tags map[string]string) []models.Statistic {
+	return []models.Statistic{{
+		Name: "quota",
+		Tags: tags,
+		Values: map[string]interface{}{
+			statWritePointsRejected: atomic.LoadInt64(&q.stats.WritePointsRejected),
+			statWriteBytesRejected:  atomic.LoadInt64(&q.stats.WriteBytesRejected),
+			statQueriesRejected:     atomic.LoadInt64(&q.stats.QueriesRejected),
+		},
+	}}
+}
+
+// scopedQuota is the quota of a database or a user.
+type scopedQuota struct {
+	key   string
+	scope string
+	meta.Quota
+}
+
+// quotas returns the non-zero quotas of user and databases. The user is
+// empty if authentication is disabled.
+func (q *Quotas) quotas(user string, databases ...string) []scopedQuota {
+	var a []scopedQuota
+	for _, database := range databases {
+		if di := q.MetaClient.Database(database); di != nil && di.Quota != (meta.Quota{}) {
+			a = append(a, scopedQuota{
+				key:   "db:" + database,
+				scope: fmt.Sprintf("database %q", database),
+				Quota: di.Quota,
+			})
+		}
+	}
+	if user != "" {
+		u, _ := q.MetaClient.User(user)
+		if ui, ok := u.(*meta.UserInfo); ok && ui.Quota != (meta.Quota{}) {
+			a = append(a, scopedQuota{
+				key:   "user:" + user,
+				scope: fmt.Sprintf("user %q", user),
+				Quota: ui.Quota,
+			})
+		}
+	}
+	return a
+}
+
+// WritePoints takes n points from the write points quotas of database and
+// user, or returns a QuotaExceededError if one of them is exhausted.
+func (q *Quotas) WritePoints(database, user string, n int) error {
+	err := q.take(q.quotas(user, database), "points", int64(n), func(sq scopedQuota) int64 { return sq.WritePoints })
+	if err != nil {
+		atomic.AddInt64(&q.stats.WritePointsRejected, 1)
+	}
+	return err
+}
+
+// WriteBytes takes n bytes from the write bytes quotas of database and user,
+// or returns a QuotaExceededError if one of them is exhausted.
+func (q *Quotas) WriteBytes(database, user string, n int) error {
+	err := q.take(q.quotas(user, database), "bytes", int64(n), func(sq scopedQuota) int64 { return sq.WriteBytes })
+	if err != nil {
+		atomic.AddInt64(&q.stats.WriteBytesRejected, 1)
+	}
+	return err
+}
+
+// take takes n tokens from the buckets of the quotas limiting unit per
+// second. Nothing is taken unless every bucket has tokens left.
+func (q *Quotas) take(quotas []scopedQuota, unit string, n int64, rate func(scopedQuota) int64) error {
+	if len(quotas) == 0 {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := q.timeFunc()
+	var buckets []*tokenBucket
+	for _, sq := range quotas {
+		r := rate(sq)
+		if r <= 0 {
+			continue
+		}
+
+		key := sq.key + ":" + unit
+		b := q.buckets[key]
+		if b == nil {
+			b = &tokenBucket{tokens: float64(r), last: now}
+			q.buckets[key] = b
+		}
+		b.refill(r, now)
+		if b.tokens <= 0 {
+			return QuotaExceededError{Scope: sq.scope, Limit: fmt.Sprintf("%d written %s per second", r, unit)}
+		}
+		buckets = append(buckets, b)
+	}
+
+	for _, b := range buckets {
+		b.tokens -= float64(n)
+	}
+	return nil
+}
+
+// BeginQuery reserves a slot in the concurrent queries quotas of user and
+// databases. The returned function releases the slot and must be called
+// once the query has finished.
+func (q *Quotas) BeginQuery(user string, databases ...string) (func(), error) {
+	var limited []scopedQuota
+	for _, sq := range q.quotas(user, databases...) {
+		if sq.Queries > 0 {
+			limited = append(limited, sq)
+		}
+	}
+	if len(limited) == 0 {
+		return func() {}, nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, sq := range limited {
+		if q.running[sq.key] >= sq.Queries {
+			atomic.AddInt64(&q.stats.QueriesRejected, 1)
+			return nil, QuotaExceededError{Scope: sq.scope, Limit: fmt.Sprintf("%d concurrent queries", sq.Queries)}
+		}
+	}
+	for _, sq := range limited {
+		q.running[sq.key]++
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.mu.Lock()
+			defer q.mu.Unlock()
+			for _, sq := range limited {
+				if q.running[sq.key]--; q.running[sq.key] <= 0 {
+					delete(q.running, sq.key)
+				}
+			}
+		})
+	}, nil
+}
+
+// MaxSelectSeries returns the lowest select series quota of user and
+// databases, or zero if none of them is limited.
+func (q *Quotas) MaxSelectSeries(user string, databases ...string) int {
+	var n int
+	for _, sq := range q.quotas(user, databases...) {
+		if sq.SelectSeries > 0 && (n == 0 || sq.SelectSeries < n) {
+			n = sq.SelectSeries
+		}
+	}
+	return n
+}
+
+// tokenBucket holds the tokens left for a rate limited quota.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the last refill at rate tokens per
+// second, up to one second worth of tokens.
+func (b *tokenBucket) refill(rate int64, now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(rate), b.tokens+elapsed*float64(rate))
+	}
+	b.last = now
+}
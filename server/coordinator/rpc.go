@@ -4,12 +4,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/cnosdatabase/cnosdb/server/coordinator/internal"
 	"github.com/cnosdatabase/cnosql"
 	"github.com/cnosdatabase/db/models"
+	"github.com/cnosdatabase/db/pkg/estimator"
+	"github.com/cnosdatabase/db/pkg/estimator/hll"
 	"github.com/cnosdatabase/db/query"
+	"github.com/cnosdatabase/db/tsdb"
 	"github.com/gogo/protobuf/proto"
 )
 
@@ -127,6 +131,23 @@ func (w *WriteShardResponse) Code() int { return int(w.pb.GetCode()) }
 // Message returns the Message
 func (w *WriteShardResponse) Message() string { return w.pb.GetMessage() }
 
+// SetPartialWrite sets the reason and the number of dropped points of a
+// write that only wrote a portion of the points.
+func (w *WriteShardResponse) SetPartialWrite(e tsdb.PartialWriteError) {
+	w.pb.PartialWrite = proto.Bool(true)
+	w.pb.Message = proto.String(e.Reason)
+	w.pb.Dropped = proto.Int64(int64(e.Dropped))
+}
+
+// PartialWrite returns the error of a write that only wrote a portion of
+// the points, and false if the write wasn't partial.
+func (w *WriteShardResponse) PartialWrite() (tsdb.PartialWriteError, bool) {
+	if !w.pb.GetPartialWrite() {
+		return tsdb.PartialWriteError{}, false
+	}
+	return tsdb.PartialWriteError{Reason: w.pb.GetMessage(), Dropped: int(w.pb.GetDropped())}, true
+}
+
 // MarshalBinary encodes the object to a binary format.
 func (w *WriteShardResponse) MarshalBinary() ([]byte, error) {
 	return proto.Marshal(&w.pb)
@@ -209,6 +230,14 @@ type CreateIteratorRequest struct {
 	ShardIDs []uint64
 	Metric   cnosql.Metric
 	Opt      query.IteratorOptions
+
+	// User is the user running the query, if the user is granted reads
+	// on some series only. The remote node only reads those series.
+	User string
+
+	// Roles are the roles of the user, including the roles granted by the
+	// groups of its JWT token, which the remote node doesn't know about.
+	Roles []string
 }
 
 // MarshalBinary encodes r to a binary format.
@@ -217,13 +246,24 @@ func (r *CreateIteratorRequest) MarshalBinary() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	return proto.Marshal(&internal.CreateIteratorRequest{
+	pb := &internal.CreateIteratorRequest{
 		ShardIDs:   r.ShardIDs,
 		Database:   []byte(r.Metric.Database),
 		TimeToLive: []byte(r.Metric.TimeToLive),
 		MetricName: []byte(r.Metric.Name),
 		Opt:        buf,
-	})
+	}
+	if r.Metric.Regex != nil {
+		pb.Regex = proto.String(r.Metric.Regex.Val.String())
+	}
+	if r.Metric.SystemIterator != "" {
+		pb.SystemIterator = proto.String(r.Metric.SystemIterator)
+	}
+	if r.User != "" {
+		pb.User = proto.String(r.User)
+	}
+	pb.Roles = r.Roles
+	return proto.Marshal(pb)
 }
 
 // UnmarshalBinary decodes data into r.
@@ -237,6 +277,16 @@ func (r *CreateIteratorRequest) UnmarshalBinary(data []byte) error {
 	r.Metric.Database = string(pb.GetDatabase()[:])
 	r.Metric.TimeToLive = string(pb.GetTimeToLive()[:])
 	r.Metric.Name = string(pb.GetMetricName()[:])
+	r.Metric.SystemIterator = pb.GetSystemIterator()
+	r.User = pb.GetUser()
+	r.Roles = pb.GetRoles()
+	if pb.Regex != nil {
+		re, err := regexp.Compile(pb.GetRegex())
+		if err != nil {
+			return fmt.Errorf("invalid metric regex: value=%q, err=%s", pb.GetRegex(), err)
+		}
+		r.Metric.Regex = &cnosql.RegexLiteral{Val: re}
+	}
 	if err := r.Opt.UnmarshalBinary(pb.GetOpt()); err != nil {
 		return err
 	}
@@ -360,3 +410,257 @@ func (r *FieldDimensionsResponse) UnmarshalBinary(data []byte) error {
 	}
 	return nil
 }
+
+// SeriesKeysRequest represents a request to retrieve the keys of the series
+// in a set of shards.
+type SeriesKeysRequest struct {
+	ShardIDs []uint64
+	SeriesKeysOptions
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *SeriesKeysRequest) MarshalBinary() ([]byte, error) {
+	var pb internal.SeriesKeysRequest
+	pb.ShardIDs = r.ShardIDs
+	if r.Condition != nil {
+		pb.Condition = proto.String(r.Condition.String())
+	}
+	if r.Reduce != ReduceSeriesKeys {
+		pb.Reduce = proto.Int32(int32(r.Reduce))
+	}
+	if r.TagKeyCondition != nil {
+		pb.TagKeyCondition = proto.String(r.TagKeyCondition.String())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *SeriesKeysRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.SeriesKeysRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.ShardIDs = pb.GetShardIDs()
+	if pb.Condition != nil {
+		cond, err := cnosql.ParseExpr(pb.GetCondition())
+		if err != nil {
+			return err
+		}
+		r.Condition = cond
+	}
+	r.Reduce = SeriesReduce(pb.GetReduce())
+	if pb.TagKeyCondition != nil {
+		cond, err := cnosql.ParseExpr(pb.GetTagKeyCondition())
+		if err != nil {
+			return err
+		}
+		r.TagKeyCondition = cond
+	}
+	return nil
+}
+
+// SeriesKeysResponse represents a response from retrieving series keys.
+type SeriesKeysResponse struct {
+	Keys []string
+	Err  error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *SeriesKeysResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.SeriesKeysResponse
+	pb.Keys = r.Keys
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *SeriesKeysResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.SeriesKeysResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Keys = pb.GetKeys()
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// SeriesSketchesRequest represents a request to retrieve the sketches of the
+// series in a set of shards.
+type SeriesSketchesRequest struct {
+	ShardIDs []uint64
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *SeriesSketchesRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.SeriesSketchesRequest{ShardIDs: r.ShardIDs})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *SeriesSketchesRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.SeriesSketchesRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.ShardIDs = pb.GetShardIDs()
+	return nil
+}
+
+// SeriesSketchesResponse represents a response from retrieving the sketches
+// of the series and of the tombstoned series.
+type SeriesSketchesResponse struct {
+	Series     estimator.Sketch
+	Tombstones estimator.Sketch
+	Err        error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *SeriesSketchesResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.SeriesSketchesResponse
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+		return proto.Marshal(&pb)
+	}
+
+	var err error
+	if pb.Series, err = r.Series.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	if pb.Tombstones, err = r.Tombstones.MarshalBinary(); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *SeriesSketchesResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.SeriesSketchesResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+		return nil
+	}
+
+	r.Series, r.Tombstones = hll.NewDefaultPlus(), hll.NewDefaultPlus()
+	if err := r.Series.UnmarshalBinary(pb.GetSeries()); err != nil {
+		return err
+	}
+	return r.Tombstones.UnmarshalBinary(pb.GetTombstones())
+}
+
+// ShowQueriesRequest represents a request to list the queries running on a node.
+type ShowQueriesRequest struct{}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShowQueriesRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.ShowQueriesRequest{})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShowQueriesRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.ShowQueriesRequest
+	return proto.Unmarshal(data, &pb)
+}
+
+// ShowQueriesResponse represents a response from listing the queries.
+type ShowQueriesResponse struct {
+	Queries []query.QueryInfo
+	Err     error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *ShowQueriesResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.ShowQueriesResponse
+	for _, q := range r.Queries {
+		pb.Queries = append(pb.Queries, &internal.QueryInfo{
+			ID:       proto.Uint64(q.ID),
+			Query:    proto.String(q.Query),
+			Database: proto.String(q.Database),
+			Duration: proto.Int64(int64(q.Duration)),
+			Status:   proto.Int32(int32(q.Status)),
+		})
+	}
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *ShowQueriesResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.ShowQueriesResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+
+	r.Queries = make([]query.QueryInfo, 0, len(pb.GetQueries()))
+	for _, q := range pb.GetQueries() {
+		r.Queries = append(r.Queries, query.QueryInfo{
+			ID:       q.GetID(),
+			Query:    q.GetQuery(),
+			Database: q.GetDatabase(),
+			Duration: time.Duration(q.GetDuration()),
+			Status:   query.TaskStatus(q.GetStatus()),
+		})
+	}
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
+
+// KillQueryRequest represents a request to kill a query running on a node.
+type KillQueryRequest struct {
+	QueryID uint64
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *KillQueryRequest) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(&internal.KillQueryRequest{
+		QueryID: proto.Uint64(r.QueryID),
+	})
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *KillQueryRequest) UnmarshalBinary(data []byte) error {
+	var pb internal.KillQueryRequest
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	r.QueryID = pb.GetQueryID()
+	return nil
+}
+
+// KillQueryResponse represents a response from killing a query.
+type KillQueryResponse struct {
+	Err error
+}
+
+// MarshalBinary encodes r to a binary format.
+func (r *KillQueryResponse) MarshalBinary() ([]byte, error) {
+	var pb internal.KillQueryResponse
+	if r.Err != nil {
+		pb.Err = proto.String(r.Err.Error())
+	}
+	return proto.Marshal(&pb)
+}
+
+// UnmarshalBinary decodes data into r.
+func (r *KillQueryResponse) UnmarshalBinary(data []byte) error {
+	var pb internal.KillQueryResponse
+	if err := proto.Unmarshal(data, &pb); err != nil {
+		return err
+	}
+	if pb.Err != nil {
+		r.Err = errors.New(pb.GetErr())
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/cnosdatabase/cnosdb/meta"
 	"github.com/cnosdatabase/cnosql"
 	"github.com/cnosdatabase/common"
+	"github.com/cnosdatabase/db/pkg/estimator"
 	"github.com/cnosdatabase/db/query"
 	"github.com/cnosdatabase/db/tsdb"
 	"go.uber.org/zap"
@@ -40,6 +41,12 @@ const (
 
 	seriesKeysReq  = "seriesKeysReq"
 	seriesKeysResp = "seriesKeysResp"
+
+	seriesSketchesReq  = "seriesSketchesReq"
+	seriesSketchesResp = "seriesSketchesResp"
+
+	showQueriesReq = "showQueriesReq"
+	killQueryReq   = "killQueryReq"
 )
 
 // Service processes data received over raw TCP connections.
@@ -53,10 +60,17 @@ type Service struct {
 
 	MetaClient interface {
 		ShardOwner(shardID uint64) (string, string, *meta.RegionInfo)
+		UserWithRoles(name string, roles []string) (meta.User, error)
 	}
 
 	TSDBStore TSDBStore
 
+	// TaskManager lists and kills the queries running on this node.
+	TaskManager interface {
+		Queries() []query.QueryInfo
+		KillQuery(qid uint64) error
+	}
+
 	Logger  *zap.Logger
 	statMap *expvar.Map
 }
@@ -190,6 +204,22 @@ func (s *Service) handleConn(conn net.Conn) {
 			s.statMap.Add(fieldDimensionsReq, 1)
 			s.processFieldDimensionsRequest(conn)
 			return
+		case seriesKeysRequestMessage:
+			s.statMap.Add(seriesKeysReq, 1)
+			s.processSeriesKeysRequest(conn)
+			return
+		case seriesSketchesRequestMessage:
+			s.statMap.Add(seriesSketchesReq, 1)
+			s.processSeriesSketchesRequest(conn)
+			return
+		case showQueriesRequestMessage:
+			s.statMap.Add(showQueriesReq, 1)
+			s.processShowQueriesRequest(conn)
+			return
+		case killQueryRequestMessage:
+			s.statMap.Add(killQueryReq, 1)
+			s.processKillQueryRequest(conn)
+			return
 		default:
 			s.Logger.Info("coordinator service message type not found:", zap.Uint8("Type", uint8(typ)))
 		}
@@ -220,6 +250,8 @@ func (s *Service) executeStatement(stmt cnosql.Statement, database string) error
 		return s.TSDBStore.DeleteMetric(database, t.Name)
 	case *cnosql.DropSeriesStatement:
 		return s.TSDBStore.DeleteSeries(database, t.Sources, t.Condition)
+	case *cnosql.DeleteSeriesStatement:
+		return s.TSDBStore.DeleteSeries(database, t.Sources, t.Condition)
 	case *cnosql.DropTimeToLiveStatement:
 		return s.TSDBStore.DeleteTimeToLive(database, t.Name)
 	default:
@@ -257,14 +289,15 @@ func (s *Service) processWriteShardRequest(buf []byte) error {
 		}
 
 		err = s.TSDBStore.WriteToShard(req.ShardID(), points)
-		if err != nil {
-			s.statMap.Add(writeShardFail, 1)
-			return fmt.Errorf("write shard %d: %s", req.ShardID(), err)
-		}
 	}
 
 	if err != nil {
 		s.statMap.Add(writeShardFail, 1)
+		// Points rejected by the shard are reported as such, so that the
+		// writer knows the others were written.
+		if _, ok := err.(tsdb.PartialWriteError); ok {
+			return err
+		}
 		return fmt.Errorf("write shard %d: %s", req.ShardID(), err)
 	}
 
@@ -274,7 +307,10 @@ func (s *Service) processWriteShardRequest(buf []byte) error {
 func (s *Service) writeShardResponse(w io.Writer, e error) {
 	// Build response.
 	var resp WriteShardResponse
-	if e != nil {
+	if werr, ok := e.(tsdb.PartialWriteError); ok {
+		resp.SetCode(1)
+		resp.SetPartialWrite(werr)
+	} else if e != nil {
 		resp.SetCode(1)
 		resp.SetMessage(e.Error())
 	} else {
@@ -304,7 +340,32 @@ func (s *Service) processCreateIteratorRequest(conn net.Conn) {
 		if err := DecodeLV(conn, &req); err != nil {
 			return err
 		}
-		sg := s.TSDBStore.Region(req.ShardIDs)
+
+		// Only read the series the user running the query is granted,
+		// with the same roles as on the node running the query.
+		if req.User != "" {
+			u, err := s.MetaClient.UserWithRoles(req.User, req.Roles)
+			if err != nil {
+				return err
+			}
+			req.Opt.Authorizer = u
+		}
+
+		// Map the requested shards as a local region so that metric
+		// regexes are expanded the same way as for a local query.
+		source := Source{
+			Database:   req.Metric.Database,
+			TimeToLive: req.Metric.TimeToLive,
+		}
+		rg, err := s.region(req.ShardIDs)
+		if err != nil {
+			return err
+		}
+		sg := &LocalShardMapping{
+			ShardMap: map[Source]tsdb.Region{source: rg},
+		}
+		defer sg.Close()
+
 		ic, err := sg.CreateIterator(context.Background(), &req.Metric, req.Opt)
 		if err != nil {
 			return err
@@ -312,16 +373,20 @@ func (s *Service) processCreateIteratorRequest(conn net.Conn) {
 		itr = ic
 		return nil
 	}(); err != nil {
-		itr.Close()
-		//s.Logger.Printf("error reading CreateIterator request: %s", err)
+		if itr != nil {
+			itr.Close()
+		}
+		s.Logger.Info("error reading CreateIterator request", zap.Error(err))
 		EncodeTLV(conn, createIteratorResponseMessage, &CreateIteratorResponse{Err: err})
 		return
 	}
 
-	// Exit if no iterator was produced.
+	// Send an empty response if no iterator was produced.
 	if itr == nil {
+		EncodeTLV(conn, createIteratorResponseMessage, &CreateIteratorResponse{typ: cnosql.Unknown})
 		return
 	}
+	defer itr.Close()
 
 	var typ cnosql.DataType
 	switch itr.(type) {
@@ -355,6 +420,29 @@ func (s *Service) processCreateIteratorRequest(conn net.Conn) {
 	}
 }
 
+// region returns the local shards with the given IDs. It fails if some of
+// them are not on this node, e.g. because they were moved, so that the node
+// running the query reads them from their other owners.
+func (s *Service) region(ids []uint64) (tsdb.Region, error) {
+	rg := s.TSDBStore.Region(ids)
+	shards, ok := rg.(tsdb.Shards)
+	if !ok || len(shards) >= len(ids) {
+		return rg, nil
+	}
+
+	found := make(map[uint64]struct{}, len(shards))
+	for _, sh := range shards {
+		found[sh.ID()] = struct{}{}
+	}
+	var missing []uint64
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return nil, errShardsNotFound(missing)
+}
+
 func (s *Service) processFieldDimensionsRequest(conn net.Conn) {
 	var fields map[string]cnosql.DataType
 	var dimensions map[string]struct{}
@@ -366,7 +454,10 @@ func (s *Service) processFieldDimensionsRequest(conn net.Conn) {
 			return err
 		}
 
-		sg := s.TSDBStore.Region(req.ShardIDs)
+		sg, err := s.region(req.ShardIDs)
+		if err != nil {
+			return err
+		}
 		if sg != nil {
 			var metrics []string
 			if req.Metric.Regex != nil {
@@ -398,6 +489,101 @@ func (s *Service) processFieldDimensionsRequest(conn net.Conn) {
 	}
 }
 
+func (s *Service) processSeriesKeysRequest(conn net.Conn) {
+	set := make(map[string]struct{})
+
+	if err := func() error {
+		// Parse request.
+		var req SeriesKeysRequest
+		if err := DecodeLV(conn, &req); err != nil {
+			return err
+		}
+
+		rg, err := s.region(req.ShardIDs)
+		if err != nil {
+			return err
+		}
+		return seriesKeys(rg, req.SeriesKeysOptions, set)
+	}(); err != nil {
+		s.Logger.Info("error reading SeriesKeys request", zap.Error(err))
+		EncodeTLV(conn, seriesKeysResponseMessage, &SeriesKeysResponse{Err: err})
+		return
+	}
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+
+	// Encode success response.
+	if err := EncodeTLV(conn, seriesKeysResponseMessage, &SeriesKeysResponse{
+		Keys: keys,
+	}); err != nil {
+		s.Logger.Info("error writing SeriesKeys response", zap.Error(err))
+		return
+	}
+	s.statMap.Add(seriesKeysResp, 1)
+}
+
+func (s *Service) processSeriesSketchesRequest(conn net.Conn) {
+	var ss, ts estimator.Sketch
+	if err := func() error {
+		// Parse request.
+		var req SeriesSketchesRequest
+		if err := DecodeLV(conn, &req); err != nil {
+			return err
+		}
+
+		rg, err := s.region(req.ShardIDs)
+		if err != nil {
+			return err
+		}
+		ss, ts, err = seriesSketches(rg)
+		return err
+	}(); err != nil {
+		s.Logger.Info("error reading SeriesSketches request", zap.Error(err))
+		EncodeTLV(conn, seriesSketchesResponseMessage, &SeriesSketchesResponse{Err: err})
+		return
+	}
+
+	// Encode success response.
+	if err := EncodeTLV(conn, seriesSketchesResponseMessage, &SeriesSketchesResponse{
+		Series:     ss,
+		Tombstones: ts,
+	}); err != nil {
+		s.Logger.Info("error writing SeriesSketches response", zap.Error(err))
+		return
+	}
+	s.statMap.Add(seriesSketchesResp, 1)
+}
+
+func (s *Service) processShowQueriesRequest(conn net.Conn) {
+	var req ShowQueriesRequest
+	if err := DecodeLV(conn, &req); err != nil {
+		s.Logger.Info("error reading ShowQueries request", zap.Error(err))
+		EncodeTLV(conn, showQueriesResponseMessage, &ShowQueriesResponse{Err: err})
+		return
+	}
+
+	if err := EncodeTLV(conn, showQueriesResponseMessage, &ShowQueriesResponse{
+		Queries: s.TaskManager.Queries(),
+	}); err != nil {
+		s.Logger.Info("error writing ShowQueries response", zap.Error(err))
+	}
+}
+
+func (s *Service) processKillQueryRequest(conn net.Conn) {
+	var req KillQueryRequest
+	err := DecodeLV(conn, &req)
+	if err == nil {
+		err = s.TaskManager.KillQuery(req.QueryID)
+	}
+
+	if err := EncodeTLV(conn, killQueryResponseMessage, &KillQueryResponse{Err: err}); err != nil {
+		s.Logger.Info("error writing KillQuery response", zap.Error(err))
+	}
+}
+
 // ReadTLV reads a type-length-value record from r.
 func ReadTLV(r io.Reader) (byte, []byte, error) {
 	typ, err := ReadType(r)
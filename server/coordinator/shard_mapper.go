@@ -2,13 +2,21 @@ package coordinator
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/cnosdatabase/cnosdb"
 	"github.com/cnosdatabase/cnosdb/meta"
 	"github.com/cnosdatabase/cnosdb/pkg/network"
 	"github.com/cnosdatabase/cnosql"
+	"github.com/cnosdatabase/db/models"
+	"github.com/cnosdatabase/db/pkg/estimator"
+	"github.com/cnosdatabase/db/pkg/estimator/hll"
 	"github.com/cnosdatabase/db/query"
 	"github.com/cnosdatabase/db/tsdb"
 )
@@ -80,6 +88,10 @@ func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources cnosql.Source
 			if err := e.mapShards(a, s.Statement.Sources, tmin, tmax); err != nil {
 				return err
 			}
+		case *cnosql.Join:
+			if err := e.mapShards(a, cnosql.Sources{s.Left, s.Right}, tmin, tmax); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -250,6 +262,703 @@ func (a *LocalShardMapping) Close() error {
 	return nil
 }
 
+// ClusterShardMapper implements a ShardMapper for shards on every data node in
+// the cluster. Shards owned by the local node are read from the local store and
+// all other shards are read from one of their owners over the coordinator RPC.
+type ClusterShardMapper struct {
+	Node *cnosdb.Node
+
+	MetaClient interface {
+		DataNode(id uint64) (*meta.NodeInfo, error)
+		RegionsByTimeRange(database, ttl string, min, max time.Time) (a []meta.RegionInfo, err error)
+	}
+
+	TSDBStore interface {
+		Region(ids []uint64) tsdb.Region
+	}
+
+	// Health tracks recent failures of remote nodes so that reads
+	// prefer the owners that are known to be healthy.
+	Health *NodeHealth
+
+	// Timeout is the timeout used when dialing remote nodes.
+	Timeout time.Duration
+
+	// ForceRemoteMapping maps every shard through the remote path,
+	// even when it is owned by the local node.
+	ForceRemoteMapping bool
+}
+
+// MapShards maps the sources to the appropriate local and remote shards.
+func (e *ClusterShardMapper) MapShards(sources cnosql.Sources, t cnosql.TimeRange, opt query.SelectOptions) (query.Region, error) {
+	a := &ClusterShardMapping{
+		LocalShardMapping: &LocalShardMapping{
+			ShardMap: make(map[Source]tsdb.Region),
+		},
+		RemoteMap:    make(map[Source][]*remoteIteratorCreator),
+		ShardOwners:  make(map[uint64][]uint64),
+		Health:       e.Health,
+		AllowPartial: opt.AllowPartial,
+		dialer:       &NodeDialer{MetaClient: e.MetaClient, Timeout: e.Timeout},
+	}
+
+	tmin := time.Unix(0, t.MinTimeNano())
+	tmax := time.Unix(0, t.MaxTimeNano())
+	if err := e.mapShards(a, sources, tmin, tmax, opt.NodeID); err != nil {
+		return nil, err
+	}
+	a.MinTime, a.MaxTime = tmin, tmax
+	return a, nil
+}
+
+func (e *ClusterShardMapper) mapShards(a *ClusterShardMapping, sources cnosql.Sources, tmin, tmax time.Time, nodeID uint64) error {
+	for _, s := range sources {
+		switch s := s.(type) {
+		case *cnosql.Metric:
+			source := Source{
+				Database:   s.Database,
+				TimeToLive: s.TimeToLive,
+			}
+			// Retrieve the list of shards for this database. This list of
+			// shards is always the same regardless of which metric we are
+			// using.
+			if _, ok := a.ShardMap[source]; ok {
+				continue
+			}
+
+			groups, err := e.MetaClient.RegionsByTimeRange(s.Database, s.TimeToLive, tmin, tmax)
+			if err != nil {
+				return err
+			}
+
+			var shards []meta.ShardInfo
+			for _, g := range groups {
+				shards = append(shards, g.Shards...)
+			}
+			e.mapShardOwners(a, source, shards, nodeID)
+		case *cnosql.SubQuery:
+			if err := e.mapShards(a, s.Statement.Sources, tmin, tmax, nodeID); err != nil {
+				return err
+			}
+		case *cnosql.Join:
+			if err := e.mapShards(a, cnosql.Sources{s.Left, s.Right}, tmin, tmax, nodeID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mapShardOwners groups the shards by the preferred node to read them from
+// and maps them to source.
+func (e *ClusterShardMapper) mapShardOwners(a *ClusterShardMapping, source Source, shards []meta.ShardInfo, nodeID uint64) {
+	shardIDsByNodeID := make(map[uint64][]uint64)
+	for _, si := range shards {
+		owners := e.shardOwners(si, nodeID)
+		if len(owners) == 0 {
+			continue
+		}
+		a.ShardOwners[si.ID] = owners
+		shardIDsByNodeID[owners[0]] = append(shardIDsByNodeID[owners[0]], si.ID)
+	}
+
+	a.ShardMap[source] = nil
+	for ownerID, shardIDs := range shardIDsByNodeID {
+		if ownerID == e.localNodeID() && !e.ForceRemoteMapping {
+			a.ShardMap[source] = e.TSDBStore.Region(shardIDs)
+			continue
+		}
+		a.RemoteMap[source] = append(a.RemoteMap[source], newRemoteIteratorCreator(a.dialer, ownerID, shardIDs))
+	}
+}
+
+// SeriesKeys returns the sorted keys of the series matching opt in the
+// shards, reduced by opt. Each shard is read from one of its owners.
+func (e *ClusterShardMapper) SeriesKeys(shards []meta.ShardInfo, opt SeriesKeysOptions) ([]string, error) {
+	a := &ClusterShardMapping{
+		LocalShardMapping: &LocalShardMapping{
+			ShardMap: make(map[Source]tsdb.Region),
+		},
+		RemoteMap:   make(map[Source][]*remoteIteratorCreator),
+		ShardOwners: make(map[uint64][]uint64),
+		Health:      e.Health,
+		dialer:      &NodeDialer{MetaClient: e.MetaClient, Timeout: e.Timeout},
+	}
+	defer a.Close()
+
+	e.mapShardOwners(a, Source{}, shards, 0)
+	return a.SeriesKeys(Source{}, opt)
+}
+
+// SeriesSketches returns the merged sketches of the series and of the
+// tombstoned series in the shards. Each shard is read from one of its owners.
+func (e *ClusterShardMapper) SeriesSketches(shards []meta.ShardInfo) (estimator.Sketch, estimator.Sketch, error) {
+	a := &ClusterShardMapping{
+		LocalShardMapping: &LocalShardMapping{
+			ShardMap: make(map[Source]tsdb.Region),
+		},
+		RemoteMap:   make(map[Source][]*remoteIteratorCreator),
+		ShardOwners: make(map[uint64][]uint64),
+		Health:      e.Health,
+		dialer:      &NodeDialer{MetaClient: e.MetaClient, Timeout: e.Timeout},
+	}
+	defer a.Close()
+
+	e.mapShardOwners(a, Source{}, shards, 0)
+	return a.SeriesSketches(Source{})
+}
+
+// shardOwners returns the nodes that a shard can be read from in order of
+// preference: the local node first, then the healthy remote owners and
+// finally the owners that failed recently. If nodeID is non-zero, the shard
+// is only read when nodeID owns it.
+func (e *ClusterShardMapper) shardOwners(si meta.ShardInfo, nodeID uint64) []uint64 {
+	if nodeID != 0 {
+		if !si.OwnedBy(nodeID) {
+			return nil
+		}
+		return []uint64{nodeID}
+	}
+
+	localID := e.localNodeID()
+	if len(si.Owners) == 0 {
+		return []uint64{localID}
+	}
+
+	local := si.OwnedBy(localID) && !e.ForceRemoteMapping
+	remotes := make([]uint64, 0, len(si.Owners))
+	for _, owner := range si.Owners {
+		if local && owner.NodeID == localID {
+			continue
+		}
+		remotes = append(remotes, owner.NodeID)
+	}
+	remotes = e.Health.Order(remotes)
+
+	if local {
+		return append([]uint64{localID}, remotes...)
+	}
+	return remotes
+}
+
+func (e *ClusterShardMapper) localNodeID() uint64 {
+	if e.Node == nil {
+		return 0
+	}
+	return e.Node.ID
+}
+
+// ClusterShardMapping maps data sources to local shards and to the remote
+// nodes holding the rest of the shards.
+type ClusterShardMapping struct {
+	*LocalShardMapping
+
+	// RemoteMap holds an iterator creator for each remote node with shards of the source.
+	RemoteMap map[Source][]*remoteIteratorCreator
+
+	// ShardOwners holds the nodes each shard can be read from in order of preference.
+	ShardOwners map[uint64][]uint64
+
+	// Health tracks recent failures of remote nodes.
+	Health *NodeHealth
+
+	// AllowPartial skips shards that can't be read from any of their
+	// owners instead of failing the query.
+	AllowPartial bool
+
+	dialer *NodeDialer
+
+	// missing holds the shards skipped while looking up the fields and
+	// dimensions of the metrics. They are reported by CreateIterator as
+	// FieldDimensions and MapType have no context.
+	missing missingShards
+
+	// types caches the field and tag types of each metric on the remote nodes.
+	// typesErr holds the error looking them up, which is returned by
+	// CreateIterator as MapType can't return it.
+	mu       sync.Mutex
+	types    map[string]map[string]cnosql.DataType
+	typesErr map[string]error
+}
+
+func (a *ClusterShardMapping) FieldDimensions(m *cnosql.Metric) (fields map[string]cnosql.DataType, dimensions map[string]struct{}, err error) {
+	fields, dimensions, err = a.LocalShardMapping.FieldDimensions(m)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	remotes := a.RemoteMap[Source{Database: m.Database, TimeToLive: m.TimeToLive}]
+	if len(remotes) == 0 {
+		return fields, dimensions, nil
+	}
+
+	if fields == nil {
+		fields = make(map[string]cnosql.DataType)
+		dimensions = make(map[string]struct{})
+	}
+	for _, ic := range remotes {
+		if err := a.fieldDimensions(ic, m, fields, dimensions); err != nil {
+			return nil, nil, err
+		}
+	}
+	return fields, dimensions, nil
+}
+
+// fieldDimensions merges the fields and dimensions of the shards of ic into
+// fields and dimensions. The shards are read from their other owners if the
+// node of ic is unavailable.
+func (a *ClusterShardMapping) fieldDimensions(ic *remoteIteratorCreator, m *cnosql.Metric, fields map[string]cnosql.DataType, dimensions map[string]struct{}) error {
+	f, d, err := ic.FieldDimensions(m)
+	if err != nil {
+		if !a.failoverOn(ic, err) {
+			return err
+		}
+
+		next, missing := a.failover(ic)
+		if len(missing) > 0 {
+			if !a.AllowPartial {
+				return errShardsUnavailable(missing, err)
+			}
+			a.missing.Add(missing, err)
+		}
+		for _, ic := range next {
+			if err := a.fieldDimensions(ic, m, fields, dimensions); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	a.Health.Success(ic.nodeID)
+
+	for k, typ := range f {
+		if fields[k].LessThan(typ) {
+			fields[k] = typ
+		}
+	}
+	for k := range d {
+		dimensions[k] = struct{}{}
+	}
+	return nil
+}
+
+func (a *ClusterShardMapping) MapType(m *cnosql.Metric, field string) cnosql.DataType {
+	typ := a.LocalShardMapping.MapType(m, field)
+	if t := a.remoteTypes(m)[field]; typ.LessThan(t) {
+		typ = t
+	}
+	return typ
+}
+
+// remoteTypes returns the types of the fields and tags of a metric on the
+// remote nodes. Each metric is only looked up once per mapping, but the lock
+// isn't held while the nodes are queried, so a slow node doesn't block the
+// lookups of the other metrics.
+func (a *ClusterShardMapping) remoteTypes(m *cnosql.Metric) map[string]cnosql.DataType {
+	remotes := a.RemoteMap[Source{Database: m.Database, TimeToLive: m.TimeToLive}]
+	if len(remotes) == 0 {
+		return nil
+	}
+
+	key := m.String()
+	a.mu.Lock()
+	types, ok := a.types[key]
+	a.mu.Unlock()
+	if ok {
+		return types
+	}
+
+	var err error
+	fields := make(map[string]cnosql.DataType)
+	dimensions := make(map[string]struct{})
+	for _, ic := range remotes {
+		if err = a.fieldDimensions(ic, m, fields, dimensions); err != nil {
+			break
+		}
+	}
+
+	types = make(map[string]cnosql.DataType, len(fields)+len(dimensions))
+	for k := range dimensions {
+		types[k] = cnosql.Tag
+	}
+	for k, typ := range fields {
+		types[k] = typ
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Keep the types of a concurrent lookup of the metric, if any.
+	if cached, ok := a.types[key]; ok {
+		return cached
+	}
+	if err != nil {
+		if a.typesErr == nil {
+			a.typesErr = make(map[string]error)
+		}
+		a.typesErr[key] = err
+	}
+	if a.types == nil {
+		a.types = make(map[string]map[string]cnosql.DataType)
+	}
+	a.types[key] = types
+	return types
+}
+
+func (a *ClusterShardMapping) CreateIterator(ctx context.Context, m *cnosql.Metric, opt query.IteratorOptions) (query.Iterator, error) {
+	remotes := a.RemoteMap[Source{Database: m.Database, TimeToLive: m.TimeToLive}]
+	if len(remotes) == 0 {
+		return a.LocalShardMapping.CreateIterator(ctx, m, opt)
+	}
+
+	// Report the shards skipped while looking up the fields of the query.
+	if ms := missingShardsFromContext(ctx); ms != nil {
+		ms.AddFrom(&a.missing)
+	}
+
+	// Fail if the types of the query couldn't be looked up on all the nodes.
+	a.mu.Lock()
+	err := a.typesErr[m.String()]
+	a.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Override the time constraints if they don't match each other.
+	if !a.MinTime.IsZero() && opt.StartTime < a.MinTime.UnixNano() {
+		opt.StartTime = a.MinTime.UnixNano()
+	}
+	if !a.MaxTime.IsZero() && opt.EndTime > a.MaxTime.UnixNano() {
+		opt.EndTime = a.MaxTime.UnixNano()
+	}
+
+	inputs := make([]query.Iterator, 0, len(remotes)+1)
+	if err := func() error {
+		itr, err := a.LocalShardMapping.CreateIterator(ctx, m, opt)
+		if err != nil {
+			return err
+		} else if itr != nil {
+			inputs = append(inputs, itr)
+		}
+
+		for _, ic := range remotes {
+			itrs, err := a.createRemoteIterators(ctx, ic, m, opt)
+			inputs = append(inputs, itrs...)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		query.Iterators(inputs).Close()
+		return nil, err
+	}
+
+	return query.Iterators(inputs).Merge(opt)
+}
+
+// createRemoteIterators creates the iterators for the shards of ic. The shards
+// are read from their other owners if the node of ic is unavailable.
+func (a *ClusterShardMapping) createRemoteIterators(ctx context.Context, ic *remoteIteratorCreator, m *cnosql.Metric, opt query.IteratorOptions) ([]query.Iterator, error) {
+	itr, err := ic.CreateIterator(ctx, m, opt)
+	if err == nil {
+		a.Health.Success(ic.nodeID)
+		if itr == nil {
+			return nil, nil
+		}
+		return []query.Iterator{itr}, nil
+	}
+
+	if !a.failoverOn(ic, err) {
+		return nil, err
+	}
+
+	next, missing := a.failover(ic)
+	if len(missing) > 0 {
+		if !a.AllowPartial {
+			return nil, errShardsUnavailable(missing, err)
+		}
+		if ms := missingShardsFromContext(ctx); ms != nil {
+			ms.Add(missing, err)
+		}
+	}
+
+	var itrs []query.Iterator
+	for _, ic := range next {
+		inputs, err := a.createRemoteIterators(ctx, ic, m, opt)
+		itrs = append(itrs, inputs...)
+		if err != nil {
+			return itrs, err
+		}
+	}
+	return itrs, nil
+}
+
+// failoverOn returns true if the shards of ic must be read from their other
+// owners after err. This is the case when the node is unavailable, which is
+// then recorded as a failure of the node, or when the node no longer has
+// some of the shards, e.g. because they were moved.
+func (a *ClusterShardMapping) failoverOn(ic *remoteIteratorCreator, err error) bool {
+	if nerr, ok := err.(nodeUnavailableError); ok {
+		a.Health.Failure(ic.nodeID, nerr.err)
+		return true
+	}
+	return isShardsNotFound(err)
+}
+
+// failover returns iterator creators that read the shards of ic from the
+// next owner of each shard, and the shards that have no owner left.
+func (a *ClusterShardMapping) failover(ic *remoteIteratorCreator) ([]*remoteIteratorCreator, []uint64) {
+	var missing []uint64
+	shardIDsByNodeID := make(map[uint64][]uint64)
+	for _, shardID := range ic.shardIDs {
+		owners := a.ShardOwners[shardID]
+
+		next := len(owners)
+		for i, ownerID := range owners {
+			if ownerID == ic.nodeID {
+				next = i + 1
+				break
+			}
+		}
+
+		if next >= len(owners) {
+			missing = append(missing, shardID)
+			continue
+		}
+		shardIDsByNodeID[owners[next]] = append(shardIDsByNodeID[owners[next]], shardID)
+	}
+
+	ics := make([]*remoteIteratorCreator, 0, len(shardIDsByNodeID))
+	for nodeID, shardIDs := range shardIDsByNodeID {
+		ics = append(ics, newRemoteIteratorCreator(ic.dialer, nodeID, shardIDs))
+	}
+	return ics, missing
+}
+
+func (a *ClusterShardMapping) IteratorCost(m *cnosql.Metric, opt query.IteratorOptions) (query.IteratorCost, error) {
+	costs, err := a.LocalShardMapping.IteratorCost(m, opt)
+	if err != nil {
+		return query.IteratorCost{}, err
+	}
+
+	for _, ic := range a.RemoteMap[Source{Database: m.Database, TimeToLive: m.TimeToLive}] {
+		cost, err := ic.IteratorCost(m, opt)
+		if err != nil {
+			return query.IteratorCost{}, err
+		}
+		costs = costs.Combine(cost)
+	}
+	return costs, nil
+}
+
+// SeriesKeys returns the sorted, unique keys of the series matching opt in
+// the local and remote shards of source, reduced by opt.
+func (a *ClusterShardMapping) SeriesKeys(source Source, opt SeriesKeysOptions) ([]string, error) {
+	set := make(map[string]struct{})
+	if err := seriesKeys(a.ShardMap[source], opt, set); err != nil {
+		return nil, err
+	}
+
+	for _, ic := range a.RemoteMap[source] {
+		if err := a.seriesKeys(ic, opt, set); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// SeriesSketches returns the merged sketches of the series and of the
+// tombstoned series in the local and remote shards of source.
+func (a *ClusterShardMapping) SeriesSketches(source Source) (estimator.Sketch, estimator.Sketch, error) {
+	ss, ts, err := seriesSketches(a.ShardMap[source])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, ic := range a.RemoteMap[source] {
+		if err := a.seriesSketches(ic, ss, ts); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ss, ts, nil
+}
+
+// seriesSketches merges the sketches of the shards of ic into ss and ts. The
+// shards are read from their other owners if the node of ic is unavailable.
+func (a *ClusterShardMapping) seriesSketches(ic *remoteIteratorCreator, ss, ts estimator.Sketch) error {
+	s, t, err := ic.SeriesSketches()
+	if err != nil {
+		if !a.failoverOn(ic, err) {
+			return err
+		}
+
+		next, missing := a.failover(ic)
+		if len(missing) > 0 && !a.AllowPartial {
+			return errShardsUnavailable(missing, err)
+		}
+		for _, ic := range next {
+			if err := a.seriesSketches(ic, ss, ts); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	a.Health.Success(ic.nodeID)
+
+	if err := ss.Merge(s); err != nil {
+		return err
+	}
+	return ts.Merge(t)
+}
+
+// seriesKeys adds the keys of the series in the shards of ic to set. The
+// shards are read from their other owners if the node of ic is unavailable.
+func (a *ClusterShardMapping) seriesKeys(ic *remoteIteratorCreator, opt SeriesKeysOptions, set map[string]struct{}) error {
+	keys, err := ic.SeriesKeys(opt)
+	if err != nil {
+		if !a.failoverOn(ic, err) {
+			return err
+		}
+
+		next, missing := a.failover(ic)
+		if len(missing) > 0 && !a.AllowPartial {
+			return errShardsUnavailable(missing, err)
+		}
+		for _, ic := range next {
+			if err := a.seriesKeys(ic, opt, set); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	a.Health.Success(ic.nodeID)
+
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return nil
+}
+
+// Close clears out the list of mapped shards.
+func (a *ClusterShardMapping) Close() error {
+	a.RemoteMap = nil
+	a.ShardOwners = nil
+	return a.LocalShardMapping.Close()
+}
+
+// nodeUnavailableError is returned when a remote node can't be reached or
+// fails to answer a request.
+type nodeUnavailableError struct {
+	nodeID uint64
+	err    error
+}
+
+func (e nodeUnavailableError) Error() string {
+	return fmt.Sprintf("node %d unavailable: %s", e.nodeID, e.err)
+}
+
+// errShardsNotFound returns the error answered by a node which doesn't have
+// some of the requested shards.
+func errShardsNotFound(shardIDs []uint64) error {
+	return fmt.Errorf("%s: %s", tsdb.ErrShardNotFound, joinUint64(shardIDs))
+}
+
+// isShardsNotFound returns true if err was answered by a node which doesn't
+// have some of the requested shards. The error is only known by its message
+// as it is sent as a string.
+func isShardsNotFound(err error) bool {
+	return strings.HasPrefix(err.Error(), tsdb.ErrShardNotFound.Error()+": ")
+}
+
+// errShardsUnavailable returns an error for shards that can't be read from any owner.
+func errShardsUnavailable(shardIDs []uint64, err error) error {
+	return fmt.Errorf("shards %s unavailable on all owners: %s", joinUint64(shardIDs), err)
+}
+
+type missingShardsContextKey struct{}
+
+// missingShards collects the shards that were skipped by a query because
+// they couldn't be read from any of their owners.
+type missingShards struct {
+	mu      sync.Mutex
+	skipped []skippedShards
+	ids     map[uint64]struct{}
+}
+
+// skippedShards are shards skipped because of the same error.
+type skippedShards struct {
+	shardIDs []uint64
+	err      error
+}
+
+// newContextWithMissingShards returns a new context.Context with ms added.
+func newContextWithMissingShards(ctx context.Context, ms *missingShards) context.Context {
+	return context.WithValue(ctx, missingShardsContextKey{}, ms)
+}
+
+// missingShardsFromContext returns the missingShards added to ctx, if any.
+func missingShardsFromContext(ctx context.Context) *missingShards {
+	ms, _ := ctx.Value(missingShardsContextKey{}).(*missingShards)
+	return ms
+}
+
+// Add records shards that were skipped because of err. Shards that were
+// already recorded are ignored.
+func (ms *missingShards) Add(shardIDs []uint64, err error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.add(shardIDs, err)
+}
+
+func (ms *missingShards) add(shardIDs []uint64, err error) {
+	if ms.ids == nil {
+		ms.ids = make(map[uint64]struct{})
+	}
+	var ids []uint64
+	for _, id := range shardIDs {
+		if _, ok := ms.ids[id]; !ok {
+			ms.ids[id] = struct{}{}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 {
+		ms.skipped = append(ms.skipped, skippedShards{shardIDs: ids, err: err})
+	}
+}
+
+// AddFrom records the shards recorded by other.
+func (ms *missingShards) AddFrom(other *missingShards) {
+	other.mu.Lock()
+	skipped := append([]skippedShards(nil), other.skipped...)
+	other.mu.Unlock()
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, s := range skipped {
+		ms.add(s.shardIDs, s.err)
+	}
+}
+
+// Messages returns a warning for each group of skipped shards.
+func (ms *missingShards) Messages() []*query.Message {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	var messages []*query.Message
+	for _, s := range ms.skipped {
+		messages = append(messages, &query.Message{
+			Level: query.WarningLevel,
+			Text:  fmt.Sprintf("partial results, shards %s were skipped: %s", joinUint64(s.shardIDs), s.err),
+		})
+	}
+	return messages
+}
+
 // remoteIteratorCreator creates iterators for remote shards.
 type remoteIteratorCreator struct {
 	dialer   *NodeDialer
@@ -270,7 +979,18 @@ func newRemoteIteratorCreator(dialer *NodeDialer, nodeID uint64, shardIDs []uint
 func (ic *remoteIteratorCreator) CreateIterator(ctx context.Context, m *cnosql.Metric, opt query.IteratorOptions) (query.Iterator, error) {
 	conn, err := ic.dialer.DialNode(ic.nodeID)
 	if err != nil {
-		return nil, err
+		return nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
+	}
+
+	// The authorizer can't be sent, so send the user and its roles for the
+	// remote node to limit the query to the series the user is granted.
+	var user string
+	var roles []string
+	if u, ok := opt.Authorizer.(meta.User); ok && !u.IsOpen() {
+		user = u.ID()
+		if ui, ok := u.(*meta.UserInfo); ok {
+			roles = ui.Roles
+		}
 	}
 
 	var resp CreateIteratorResponse
@@ -278,16 +998,19 @@ func (ic *remoteIteratorCreator) CreateIterator(ctx context.Context, m *cnosql.M
 		// Write request.
 		if err := EncodeTLV(conn, createIteratorRequestMessage, &CreateIteratorRequest{
 			ShardIDs: ic.shardIDs,
+			Metric:   *m,
 			Opt:      opt,
+			User:     user,
+			Roles:    roles,
 		}); err != nil {
-			return err
+			return nodeUnavailableError{nodeID: ic.nodeID, err: err}
 		}
 
 		// Read the response.
 		if _, err := DecodeTLV(conn, &resp); err != nil {
-			return err
+			return nodeUnavailableError{nodeID: ic.nodeID, err: err}
 		} else if resp.Err != nil {
-			return err
+			return resp.Err
 		}
 
 		return nil
@@ -296,14 +1019,64 @@ func (ic *remoteIteratorCreator) CreateIterator(ctx context.Context, m *cnosql.M
 		return nil, err
 	}
 
-	return query.NewReaderIterator(ctx, conn, resp.typ, resp.stats), nil
+	// The remote node had no data for the metric.
+	if resp.typ == cnosql.Unknown {
+		conn.Close()
+		return nil, nil
+	}
+
+	// The dial deadline only covers the request. Clear it so that
+	// long-running queries can stream for as long as they need to.
+	conn.SetDeadline(time.Time{})
+
+	return query.NewReaderIterator(ctx, newQueryConn(ctx, conn), resp.typ, resp.stats), nil
+}
+
+// queryConn is the connection of a remote iterator. It is closed as soon as
+// the query is killed so that reading the stream doesn't block the query, and
+// the remote node stops streaming.
+type queryConn struct {
+	net.Conn
+	closing chan struct{}
+	once    sync.Once
+}
+
+// newQueryConn returns a connection closed once ctx is done.
+func newQueryConn(ctx context.Context, conn net.Conn) *queryConn {
+	c := &queryConn{Conn: conn, closing: make(chan struct{})}
+	if done := ctx.Done(); done != nil {
+		go func() {
+			select {
+			case <-done:
+				c.Close()
+			case <-c.closing:
+			}
+		}()
+	}
+	return c
+}
+
+// Close closes the connection.
+func (c *queryConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.closing)
+		err = c.Conn.Close()
+	})
+	return err
+}
+
+// IteratorCost returns an estimate of the cost of reading the remote shards.
+// Only the number of shards is known without a round trip to the remote node.
+func (ic *remoteIteratorCreator) IteratorCost(m *cnosql.Metric, opt query.IteratorOptions) (query.IteratorCost, error) {
+	return query.IteratorCost{NumShards: int64(len(ic.shardIDs))}, nil
 }
 
 // FieldDimensions returns the unique fields and dimensions across a list of sources.
 func (ic *remoteIteratorCreator) FieldDimensions(km *cnosql.Metric) (fields map[string]cnosql.DataType, dimensions map[string]struct{}, err error) {
 	conn, err := ic.dialer.DialNode(ic.nodeID)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
 	}
 	defer conn.Close()
 
@@ -312,21 +1085,188 @@ func (ic *remoteIteratorCreator) FieldDimensions(km *cnosql.Metric) (fields map[
 		ShardIDs: ic.shardIDs,
 		Metric:   *km,
 	}); err != nil {
-		return nil, nil, err
+		return nil, nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
 	}
 
 	// Read the response.
 	var resp FieldDimensionsResponse
 	if _, err := DecodeTLV(conn, &resp); err != nil {
-		return nil, nil, err
+		return nil, nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
 	}
 	return resp.Fields, resp.Dimensions, resp.Err
 }
 
+// SeriesKeys returns the keys of the series matching opt in the remote shards,
+// reduced by opt on the remote node.
+func (ic *remoteIteratorCreator) SeriesKeys(opt SeriesKeysOptions) ([]string, error) {
+	conn, err := ic.dialer.DialNode(ic.nodeID)
+	if err != nil {
+		return nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
+	}
+	defer conn.Close()
+
+	// Write request.
+	if err := EncodeTLV(conn, seriesKeysRequestMessage, &SeriesKeysRequest{
+		ShardIDs:          ic.shardIDs,
+		SeriesKeysOptions: opt,
+	}); err != nil {
+		return nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
+	}
+
+	// Read the response.
+	var resp SeriesKeysResponse
+	if _, err := DecodeTLV(conn, &resp); err != nil {
+		return nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
+	}
+	return resp.Keys, resp.Err
+}
+
+// SeriesSketches returns the sketches of the series and of the tombstoned
+// series in the remote shards.
+func (ic *remoteIteratorCreator) SeriesSketches() (estimator.Sketch, estimator.Sketch, error) {
+	conn, err := ic.dialer.DialNode(ic.nodeID)
+	if err != nil {
+		return nil, nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
+	}
+	defer conn.Close()
+
+	// Write request.
+	if err := EncodeTLV(conn, seriesSketchesRequestMessage, &SeriesSketchesRequest{
+		ShardIDs: ic.shardIDs,
+	}); err != nil {
+		return nil, nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
+	}
+
+	// Read the response.
+	var resp SeriesSketchesResponse
+	if _, err := DecodeTLV(conn, &resp); err != nil {
+		return nil, nil, nodeUnavailableError{nodeID: ic.nodeID, err: err}
+	}
+	return resp.Series, resp.Tombstones, resp.Err
+}
+
+// SeriesReduce selects what SeriesKeys returns of each series, so that data
+// nodes send the metadata needed by a statement instead of every series key.
+type SeriesReduce int
+
+const (
+	// ReduceSeriesKeys returns the key of each series.
+	ReduceSeriesKeys SeriesReduce = iota
+
+	// ReduceMetricNames returns the metric name of each series as a key
+	// without tags.
+	ReduceMetricNames
+
+	// ReduceTagKeys returns a key per tag key of each series. The key has a
+	// single "_tagKey" tag set to the tag key.
+	ReduceTagKeys
+
+	// ReduceTagValues returns a key per tag of each series. The key has the
+	// tag as its only tag.
+	ReduceTagValues
+)
+
+// SeriesKeysOptions represents the series read by SeriesKeys.
+type SeriesKeysOptions struct {
+	// Condition selects the series.
+	Condition cnosql.Expr
+
+	// Reduce selects what is returned of each series.
+	Reduce SeriesReduce
+
+	// TagKeyCondition selects the tags returned by ReduceTagKeys and
+	// ReduceTagValues by their "_tagKey".
+	TagKeyCondition cnosql.Expr
+}
+
+// reduce adds the keys returned of the series with key to set.
+func (opt SeriesKeysOptions) reduce(key string, set map[string]struct{}) {
+	if opt.Reduce == ReduceSeriesKeys {
+		set[key] = struct{}{}
+		return
+	}
+
+	name, tags := models.ParseKeyBytes([]byte(key))
+	switch opt.Reduce {
+	case ReduceMetricNames:
+		set[string(models.MakeKey(name, nil))] = struct{}{}
+	case ReduceTagKeys, ReduceTagValues:
+		for _, t := range tags {
+			if opt.TagKeyCondition != nil && !cnosql.EvalBool(opt.TagKeyCondition, map[string]interface{}{"_tagKey": string(t.Key)}) {
+				continue
+			}
+			if opt.Reduce == ReduceTagKeys {
+				t = models.NewTag([]byte("_tagKey"), t.Key)
+			}
+			set[string(models.MakeKey(name, models.Tags{t}))] = struct{}{}
+		}
+	}
+}
+
+// seriesKeys adds the keys of the series matching opt in rg to set, reduced
+// by opt.
+func seriesKeys(rg tsdb.Region, opt SeriesKeysOptions, set map[string]struct{}) error {
+	if rg == nil {
+		return nil
+	}
+
+	itr, err := rg.CreateIterator(context.Background(), &cnosql.Metric{SystemIterator: "_series"}, query.IteratorOptions{
+		Aux:       []cnosql.VarRef{{Val: "key", Type: cnosql.String}},
+		Condition: opt.Condition,
+		StartTime: cnosql.MinTime,
+		EndTime:   cnosql.MaxTime,
+		Ascending: true,
+	})
+	if err != nil {
+		return err
+	} else if itr == nil {
+		return nil
+	}
+	defer itr.Close()
+
+	fitr, ok := itr.(query.FloatIterator)
+	if !ok {
+		return fmt.Errorf("unexpected series iterator type: %T", itr)
+	}
+
+	for {
+		p, err := fitr.Next()
+		if err != nil {
+			return err
+		} else if p == nil {
+			return nil
+		}
+		if key, ok := p.Aux[0].(string); ok {
+			opt.reduce(key, set)
+		}
+	}
+}
+
+// seriesSketches returns the merged sketches of the series and of the
+// tombstoned series in the shards of rg.
+func seriesSketches(rg tsdb.Region) (estimator.Sketch, estimator.Sketch, error) {
+	ss, ts := hll.NewDefaultPlus(), hll.NewDefaultPlus()
+	shards, _ := rg.(tsdb.Shards)
+	for _, sh := range shards {
+		s, t, err := sh.SeriesSketches()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := ss.Merge(s); err != nil {
+			return nil, nil, err
+		} else if err := ts.Merge(t); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ss, ts, nil
+}
+
 // NodeDialer dials connections to a given node.
 type NodeDialer struct {
-	MetaClient MetaClient
-	Timeout    time.Duration
+	MetaClient interface {
+		DataNode(id uint64) (*meta.NodeInfo, error)
+	}
+	Timeout time.Duration
 }
 
 // DialNode returns a connection to a node.
@@ -334,6 +1274,8 @@ func (d *NodeDialer) DialNode(nodeID uint64) (net.Conn, error) {
 	ni, err := d.MetaClient.DataNode(nodeID)
 	if err != nil {
 		return nil, err
+	} else if ni == nil {
+		return nil, fmt.Errorf("node %d does not exist", nodeID)
 	}
 
 	conn, err := network.DialTimeout("tcp", ni.TCPHost, MuxHeader, d.Timeout)
@@ -22,6 +22,18 @@ const (
 
 	fieldDimensionsRequestMessage
 	fieldDimensionsResponseMessage
+
+	seriesKeysRequestMessage
+	seriesKeysResponseMessage
+
+	showQueriesRequestMessage
+	showQueriesResponseMessage
+
+	killQueryRequestMessage
+	killQueryResponseMessage
+
+	seriesSketchesRequestMessage
+	seriesSketchesResponseMessage
 )
 
 // ShardWriter writes a set of points to a shard.
@@ -103,7 +115,9 @@ func (w *ShardWriter) WriteShard(shardID, ownerID uint64, points []models.Point)
 		return err
 	}
 
-	if response.Code() != 0 {
+	if werr, ok := response.PartialWrite(); ok {
+		return werr
+	} else if response.Code() != 0 {
 		return fmt.Errorf("error code %d: %s", response.Code(), response.Message())
 	}
 
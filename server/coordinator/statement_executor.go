@@ -16,6 +16,7 @@ import (
 	"github.com/cnosdatabase/cnosdb/monitor"
 	"github.com/cnosdatabase/cnosql"
 	"github.com/cnosdatabase/db/models"
+	"github.com/cnosdatabase/db/pkg/estimator"
 	"github.com/cnosdatabase/db/pkg/tracing"
 	"github.com/cnosdatabase/db/pkg/tracing/fields"
 	"github.com/cnosdatabase/db/query"
@@ -26,10 +27,27 @@ import (
 // when a database has not been provided.
 var ErrDatabaseNameRequired = errors.New("database name required")
 
+// ErrContinuousQueriesDisabled is returned when running a continuous query by
+// request while the continuous query service is disabled.
+var ErrContinuousQueriesDisabled = errors.New("continuous queries are disabled")
+
 type pointsWriter interface {
 	WritePointsInto(*IntoWriteRequest) error
 }
 
+// ContinuousQuerier runs continuous queries by request.
+type ContinuousQuerier interface {
+	// Run runs the named CQ of the database at its next check.
+	Run(database, name string, t time.Time) error
+
+	// Backfill computes the named CQ of the database over a time range in the
+	// background, and returns the query ID of the backfill.
+	Backfill(database, name string, start, end time.Time) (uint64, error)
+
+	// NextRun returns the time the CQ runs next, or a zero time if it is unknown.
+	NextRun(database string, cqi *meta.ContinuousQueryInfo) (time.Time, error)
+}
+
 // StatementExecutor executes a statement in the query.
 type StatementExecutor struct {
 	MetaClient MetaClient
@@ -43,14 +61,46 @@ type StatementExecutor struct {
 	// ShardMapper for mapping shards when executing a SELECT statement.
 	ShardMapper query.ShardMapper
 
+	// SeriesKeysReader reads the series keys of shards on every data node
+	// for metadata queries such as SHOW TAG VALUES, and their sketches for
+	// SHOW SERIES CARDINALITY.
+	SeriesKeysReader interface {
+		SeriesKeys(shards []meta.ShardInfo, opt SeriesKeysOptions) ([]string, error)
+		SeriesSketches(shards []meta.ShardInfo) (estimator.Sketch, estimator.Sketch, error)
+	}
+
+	// MetaExecutor executes statements that delete data on the other data nodes.
+	MetaExecutor interface {
+		ExecuteStatement(stmt cnosql.Statement, database string) error
+	}
+
 	// Holds monitoring data for SHOW STATS and SHOW DIAGNOSTICS.
 	Monitor *monitor.Monitor
 
+	// QueryManager lists and kills the queries of all data nodes, for SHOW
+	// QUERIES and KILL QUERY. The TaskManager is used if it is nil.
+	QueryManager interface {
+		Queries() ([]NodeQueryInfo, []error)
+		KillQuery(nodeID, qid uint64) error
+		NodeID(host string) (uint64, error)
+	}
+
+	// ContinuousQuerier runs continuous queries by request, for RUN and
+	// BACKFILL CONTINUOUS QUERY. It is nil if the service is disabled.
+	ContinuousQuerier ContinuousQuerier
+
 	// Used for rewriting points back into system for SELECT INTO statements.
 	PointsWriter interface {
 		WritePointsInto(*IntoWriteRequest) error
 	}
 
+	// Quotas limits the concurrent queries and the series read by the
+	// queries of a user or against a database.
+	Quotas interface {
+		BeginQuery(user string, databases ...string) (func(), error)
+		MaxSelectSeries(user string, databases ...string) int
+	}
+
 	// Select statement limits
 	MaxSelectPointN   int
 	MaxSelectSeriesN  int
@@ -73,6 +123,11 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeAlterTimeToLiveStatement(stmt)
+	case *cnosql.BackfillContinuousQueryStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		rows, err = e.executeBackfillContinuousQueryStatement(stmt)
 	case *cnosql.CreateContinuousQueryStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -98,6 +153,11 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeCreateUserStatement(stmt)
+	case *cnosql.CreateRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.MetaClient.CreateRole(stmt.Name)
 	case *cnosql.DeleteSeriesStatement:
 		err = e.executeDeleteSeriesStatement(stmt, ctx.Database)
 	case *cnosql.DropContinuousQueryStatement:
@@ -115,6 +175,16 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeDropMetricStatement(stmt, ctx.Database)
+	case *cnosql.DropQuotaStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropQuotaStatement(stmt)
+	case *cnosql.DropRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.MetaClient.DropRole(stmt.Name)
 	case *cnosql.DropSeriesStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -156,6 +226,11 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeGrantAdminStatement(stmt)
+	case *cnosql.GrantRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeGrantRoleStatement(stmt)
 	case *cnosql.RevokeStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -166,12 +241,24 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeRevokeAdminStatement(stmt)
+	case *cnosql.RevokeRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeRevokeRoleStatement(stmt)
+	case *cnosql.RunContinuousQueryStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeRunContinuousQueryStatement(stmt)
 	case *cnosql.ShowContinuousQueriesStatement:
 		rows, err = e.executeShowContinuousQueriesStatement(stmt)
 	case *cnosql.ShowDatabasesStatement:
 		rows, err = e.executeShowDatabasesStatement(ctx, stmt)
 	case *cnosql.ShowDiagnosticsStatement:
 		rows, err = e.executeShowDiagnosticsStatement(stmt)
+	case *cnosql.ShowGrantsForRoleStatement:
+		rows, err = e.executeShowGrantsForRoleStatement(stmt)
 	case *cnosql.ShowGrantsForUserStatement:
 		rows, err = e.executeShowGrantsForUserStatement(stmt)
 	case *cnosql.ShowMetricsStatement:
@@ -186,6 +273,10 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 		rows, err = e.executeShowShardsStatement(stmt)
 	case *cnosql.ShowRegionsStatement:
 		rows, err = e.executeShowRegionsStatement(stmt)
+	case *cnosql.ShowQuotasStatement:
+		rows, err = e.executeShowQuotasStatement(stmt)
+	case *cnosql.ShowRolesStatement:
+		rows, err = e.executeShowRolesStatement(stmt)
 	case *cnosql.ShowStatsStatement:
 		rows, err = e.executeShowStatsStatement(stmt)
 	case *cnosql.ShowSubscriptionsStatement:
@@ -201,9 +292,25 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeSetPasswordUserStatement(stmt)
+	case *cnosql.SetQuotaStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeSetQuotaStatement(stmt)
 	case *cnosql.ShowQueriesStatement, *cnosql.KillQueryStatement:
-		// Send query related statements to the task manager.
-		return e.TaskManager.ExecuteStatement(ctx, stmt)
+		if e.QueryManager == nil {
+			// Send query related statements to the task manager.
+			return e.TaskManager.ExecuteStatement(ctx, stmt)
+		}
+
+		if stmt, ok := stmt.(*cnosql.KillQueryStatement); ok {
+			if ctx.ReadOnly {
+				messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+			}
+			err = e.executeKillQueryStatement(stmt)
+		} else {
+			rows, messages = e.executeShowQueriesStatement()
+		}
 	default:
 		return query.ErrInvalidQuery
 	}
@@ -218,6 +325,52 @@ func (e *StatementExecutor) ExecuteStatement(ctx *query.ExecutionContext, stmt c
 	})
 }
 
+func (e *StatementExecutor) executeKillQueryStatement(stmt *cnosql.KillQueryStatement) error {
+	nodeID := stmt.NodeID
+	if stmt.Host != "" {
+		id, err := e.QueryManager.NodeID(stmt.Host)
+		if err != nil {
+			return err
+		}
+		nodeID = id
+	}
+	return e.QueryManager.KillQuery(nodeID, stmt.QueryID)
+}
+
+// executeShowQueriesStatement lists the queries of all data nodes. The nodes
+// which can't be reached are reported as warnings.
+func (e *StatementExecutor) executeShowQueriesStatement() (models.Rows, []*query.Message) {
+	queries, errs := e.QueryManager.Queries()
+
+	values := make([][]interface{}, 0, len(queries))
+	for _, qi := range queries {
+		d := qi.Duration
+		switch {
+		case d >= time.Second:
+			d = d - (d % time.Second)
+		case d >= time.Millisecond:
+			d = d - (d % time.Millisecond)
+		case d >= time.Microsecond:
+			d = d - (d % time.Microsecond)
+		}
+
+		values = append(values, []interface{}{qi.ID, qi.NodeID, qi.Query, qi.Database, d.String(), qi.Status.String()})
+	}
+
+	var messages []*query.Message
+	for _, err := range errs {
+		messages = append(messages, &query.Message{
+			Level: query.WarningLevel,
+			Text:  fmt.Sprintf("partial results, could not list the queries of %s", err),
+		})
+	}
+
+	return []*models.Row{{
+		Columns: []string{"qid", "node_id", "query", "database", "duration", "status"},
+		Values:  values,
+	}}, messages
+}
+
 func (e *StatementExecutor) executeAlterTimeToLiveStatement(stmt *cnosql.AlterTimeToLiveStatement) error {
 	ttlu := &meta.TimeToLiveUpdate{
 		Duration:       stmt.Duration,
@@ -229,6 +382,21 @@ func (e *StatementExecutor) executeAlterTimeToLiveStatement(stmt *cnosql.AlterTi
 	return e.MetaClient.UpdateTimeToLive(stmt.Database, stmt.Name, ttlu, stmt.Default)
 }
 
+func (e *StatementExecutor) executeBackfillContinuousQueryStatement(q *cnosql.BackfillContinuousQueryStatement) (models.Rows, error) {
+	if e.ContinuousQuerier == nil {
+		return nil, ErrContinuousQueriesDisabled
+	}
+
+	qid, err := e.ContinuousQuerier.Backfill(q.Database, q.Name, q.StartTime, q.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	return []*models.Row{{
+		Columns: []string{"qid"},
+		Values:  [][]interface{}{{qid}},
+	}}, nil
+}
+
 func (e *StatementExecutor) executeCreateContinuousQueryStatement(q *cnosql.CreateContinuousQueryStatement) error {
 	// Verify that time-to-lives exist.
 	var err error
@@ -308,7 +476,7 @@ func (e *StatementExecutor) executeCreateTimeToLiveStatement(stmt *cnosql.Create
 }
 
 func (e *StatementExecutor) executeCreateSubscriptionStatement(q *cnosql.CreateSubscriptionStatement) error {
-	return e.MetaClient.CreateSubscription(q.Database, q.TimeToLive, q.Name, q.Mode, q.Destinations)
+	return e.MetaClient.CreateSubscription(q.Database, q.TimeToLive, q.Name, q.Mode, q.Destinations, q.Durable)
 }
 
 func (e *StatementExecutor) executeCreateUserStatement(q *cnosql.CreateUserStatement) error {
@@ -325,7 +493,31 @@ func (e *StatementExecutor) executeDeleteSeriesStatement(stmt *cnosql.DeleteSeri
 	stmt.Condition = cnosql.Reduce(stmt.Condition, &cnosql.NowValuer{Now: time.Now().UTC()})
 
 	// Locally delete the series.
-	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
+	if err := e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition); err != nil {
+		return err
+	}
+
+	// Delete the series on the other data nodes.
+	return e.MetaExecutor.ExecuteStatement(stmt, database)
+}
+
+func (e *StatementExecutor) executeRunContinuousQueryStatement(q *cnosql.RunContinuousQueryStatement) error {
+	if e.ContinuousQuerier == nil {
+		return ErrContinuousQueriesDisabled
+	}
+
+	dbi := e.MetaClient.Database(q.Database)
+	if dbi == nil {
+		return query.ErrDatabaseNotFound(q.Database)
+	}
+	found := false
+	for _, cqi := range dbi.ContinuousQueries {
+		found = found || cqi.Name == q.Name
+	}
+	if !found {
+		return meta.ErrContinuousQueryNotFound
+	}
+	return e.ContinuousQuerier.Run(q.Database, q.Name, time.Now())
 }
 
 func (e *StatementExecutor) executeDropContinuousQueryStatement(q *cnosql.DropContinuousQueryStatement) error {
@@ -369,7 +561,12 @@ func (e *StatementExecutor) executeDropSeriesStatement(stmt *cnosql.DropSeriesSt
 	}
 
 	// Locally drop the series.
-	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
+	if err := e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition); err != nil {
+		return err
+	}
+
+	// Drop the series on the other data nodes.
+	return e.MetaExecutor.ExecuteStatement(stmt, database)
 }
 
 func (e *StatementExecutor) executeDropShardStatement(stmt *cnosql.DropShardStatement) error {
@@ -410,10 +607,11 @@ func (e *StatementExecutor) executeDropUserStatement(q *cnosql.DropUserStatement
 
 func (e *StatementExecutor) executeExplainStatement(ctx *query.ExecutionContext, q *cnosql.ExplainStatement) (models.Rows, error) {
 	opt := query.SelectOptions{
-		NodeID:      ctx.ExecutionOptions.NodeID,
-		MaxSeriesN:  e.MaxSelectSeriesN,
-		MaxBucketsN: e.MaxSelectBucketsN,
-		Authorizer:  ctx.Authorizer,
+		NodeID:       ctx.ExecutionOptions.NodeID,
+		MaxSeriesN:   e.MaxSelectSeriesN,
+		MaxBucketsN:  e.MaxSelectBucketsN,
+		Authorizer:   ctx.Authorizer,
+		AllowPartial: ctx.ExecutionOptions.AllowPartial,
 	}
 
 	// Prepare the query for execution, but do not actually execute it.
@@ -448,6 +646,12 @@ func (e *StatementExecutor) executeExplainAnalyzeStatement(ectx *query.Execution
 	ctx = query.NewContextWithIterators(ctx, &aux)
 	start := time.Now()
 
+	endQuery, err := e.beginQuery(stmt, ectx.ExecutionOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer endQuery()
+
 	cur, err := e.createIterators(ctx, stmt, ectx.ExecutionOptions)
 	if err != nil {
 		return nil, err
@@ -507,14 +711,41 @@ CLEANUP:
 }
 
 func (e *StatementExecutor) executeGrantStatement(stmt *cnosql.GrantStatement) error {
+	if stmt.Role != "" {
+		return e.MetaClient.SetRolePrivilege(stmt.Role, stmt.On, stmt.Privilege)
+	} else if stmt.Metric != "" || stmt.Condition != nil {
+		return e.MetaClient.SetSeriesPrivilege(stmt.User, stmt.On, stmt.Metric, seriesPrivilegeCondition(stmt.Condition), stmt.Privilege)
+	}
 	return e.MetaClient.SetPrivilege(stmt.User, stmt.On, stmt.Privilege)
 }
 
+func (e *StatementExecutor) executeGrantRoleStatement(stmt *cnosql.GrantRoleStatement) error {
+	if stmt.Role != "" {
+		return e.MetaClient.SetRoleRole(stmt.Role, stmt.Name, true)
+	}
+	return e.MetaClient.SetUserRole(stmt.User, stmt.Name, true)
+}
+
+// seriesPrivilegeCondition returns the condition of a series privilege as
+// stored in the meta data.
+func seriesPrivilegeCondition(cond cnosql.Expr) string {
+	if cond == nil {
+		return ""
+	}
+	return cond.String()
+}
+
 func (e *StatementExecutor) executeGrantAdminStatement(stmt *cnosql.GrantAdminStatement) error {
 	return e.MetaClient.SetAdminPrivilege(stmt.User, true)
 }
 
 func (e *StatementExecutor) executeRevokeStatement(stmt *cnosql.RevokeStatement) error {
+	if stmt.Role != "" {
+		return e.executeRevokeRolePrivilegeStatement(stmt)
+	} else if stmt.Metric != "" || stmt.Condition != nil {
+		return e.executeRevokeSeriesStatement(stmt)
+	}
+
 	priv := cnosql.NoPrivileges
 
 	// Revoking all privileges means there's no need to look at existing user privileges.
@@ -530,6 +761,45 @@ func (e *StatementExecutor) executeRevokeStatement(stmt *cnosql.RevokeStatement)
 	return e.MetaClient.SetPrivilege(stmt.User, stmt.On, priv)
 }
 
+// executeRevokeRolePrivilegeStatement revokes a privilege on a database from a role.
+func (e *StatementExecutor) executeRevokeRolePrivilegeStatement(stmt *cnosql.RevokeStatement) error {
+	priv := cnosql.NoPrivileges
+	if stmt.Privilege != cnosql.AllPrivileges {
+		p, err := e.MetaClient.RolePrivilege(stmt.Role, stmt.On)
+		if err != nil {
+			return err
+		}
+		// Bit clear (AND NOT) the role's privilege with the revoked privilege.
+		priv = *p &^ stmt.Privilege
+	}
+
+	return e.MetaClient.SetRolePrivilege(stmt.Role, stmt.On, priv)
+}
+
+func (e *StatementExecutor) executeRevokeRoleStatement(stmt *cnosql.RevokeRoleStatement) error {
+	if stmt.Role != "" {
+		return e.MetaClient.SetRoleRole(stmt.Role, stmt.Name, false)
+	}
+	return e.MetaClient.SetUserRole(stmt.User, stmt.Name, false)
+}
+
+// executeRevokeSeriesStatement revokes a privilege on the series of a metric.
+func (e *StatementExecutor) executeRevokeSeriesStatement(stmt *cnosql.RevokeStatement) error {
+	condition := seriesPrivilegeCondition(stmt.Condition)
+
+	priv := cnosql.NoPrivileges
+	if stmt.Privilege != cnosql.AllPrivileges {
+		p, err := e.MetaClient.UserSeriesPrivilege(stmt.User, stmt.On, stmt.Metric, condition)
+		if err != nil {
+			return err
+		}
+		// Bit clear (AND NOT) the user's privilege with the revoked privilege.
+		priv = *p &^ stmt.Privilege
+	}
+
+	return e.MetaClient.SetSeriesPrivilege(stmt.User, stmt.On, stmt.Metric, condition, priv)
+}
+
 func (e *StatementExecutor) executeRevokeAdminStatement(stmt *cnosql.RevokeAdminStatement) error {
 	return e.MetaClient.SetAdminPrivilege(stmt.User, false)
 }
@@ -539,10 +809,19 @@ func (e *StatementExecutor) executeSetPasswordUserStatement(q *cnosql.SetPasswor
 }
 
 func (e *StatementExecutor) executeSelectStatement(ctx *query.ExecutionContext, stmt *cnosql.SelectStatement) error {
-	cur, err := e.createIterators(ctx, stmt, ctx.ExecutionOptions)
+	endQuery, err := e.beginQuery(stmt, ctx.ExecutionOptions)
+	if err != nil {
+		return err
+	}
+	defer endQuery()
+
+	// Collect the shards skipped when partial results are allowed.
+	var missing missingShards
+	cur, err := e.createIterators(newContextWithMissingShards(ctx, &missing), stmt, ctx.ExecutionOptions)
 	if err != nil {
 		return err
 	}
+	warnings := missing.Messages()
 
 	// Generate a row emitter from the iterator set.
 	em := query.NewEmitter(cur, ctx.ChunkSize)
@@ -582,9 +861,11 @@ func (e *StatementExecutor) executeSelectStatement(ctx *query.ExecutionContext,
 		}
 
 		result := &query.Result{
-			Series:  []*models.Row{row},
-			Partial: partial,
+			Series:   []*models.Row{row},
+			Messages: warnings,
+			Partial:  partial,
 		}
+		warnings = nil
 
 		// Send results or exit if closing.
 		if err := ctx.Send(result); err != nil {
@@ -600,7 +881,7 @@ func (e *StatementExecutor) executeSelectStatement(ctx *query.ExecutionContext,
 			return err
 		}
 
-		var messages []*query.Message
+		messages := warnings
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
@@ -618,7 +899,8 @@ func (e *StatementExecutor) executeSelectStatement(ctx *query.ExecutionContext,
 	// Always emit at least one result.
 	if !emitted {
 		return ctx.Send(&query.Result{
-			Series: make([]*models.Row, 0),
+			Series:   make([]*models.Row, 0),
+			Messages: warnings,
 		})
 	}
 
@@ -627,11 +909,20 @@ func (e *StatementExecutor) executeSelectStatement(ctx *query.ExecutionContext,
 
 func (e *StatementExecutor) createIterators(ctx context.Context, stmt *cnosql.SelectStatement, opt query.ExecutionOptions) (query.Cursor, error) {
 	sopt := query.SelectOptions{
-		NodeID:      opt.NodeID,
-		MaxSeriesN:  e.MaxSelectSeriesN,
-		MaxPointN:   e.MaxSelectPointN,
-		MaxBucketsN: e.MaxSelectBucketsN,
-		Authorizer:  opt.Authorizer,
+		NodeID:       opt.NodeID,
+		MaxSeriesN:   e.MaxSelectSeriesN,
+		MaxPointN:    e.MaxSelectPointN,
+		MaxBucketsN:  e.MaxSelectBucketsN,
+		Authorizer:   opt.Authorizer,
+		AllowPartial: opt.AllowPartial,
+	}
+
+	// The series quotas may be lower than the configured limit.
+	if e.Quotas != nil {
+		n := e.Quotas.MaxSelectSeries(opt.UserID, selectDatabases(stmt, opt.Database)...)
+		if n > 0 && (sopt.MaxSeriesN <= 0 || n < sopt.MaxSeriesN) {
+			sopt.MaxSeriesN = n
+		}
 	}
 
 	// Create a set of iterators from a selection.
@@ -642,14 +933,55 @@ func (e *StatementExecutor) createIterators(ctx context.Context, stmt *cnosql.Se
 	return cur, nil
 }
 
+// beginQuery reserves a slot in the concurrent queries quotas of the user
+// running stmt and of the databases it reads. The returned function releases
+// the slot.
+func (e *StatementExecutor) beginQuery(stmt *cnosql.SelectStatement, opt query.ExecutionOptions) (func(), error) {
+	if e.Quotas == nil {
+		return func() {}, nil
+	}
+	return e.Quotas.BeginQuery(opt.UserID, selectDatabases(stmt, opt.Database)...)
+}
+
+// selectDatabases returns the databases read by stmt, the sources without a
+// database being read from the default database.
+func selectDatabases(stmt *cnosql.SelectStatement, defaultDatabase string) []string {
+	var databases []string
+	seen := make(map[string]struct{})
+	for _, m := range stmt.Sources.Metrics() {
+		name := m.Database
+		if name == "" {
+			name = defaultDatabase
+		}
+		if _, ok := seen[name]; ok || name == "" {
+			continue
+		}
+		seen[name] = struct{}{}
+		databases = append(databases, name)
+	}
+	return databases
+}
+
 func (e *StatementExecutor) executeShowContinuousQueriesStatement(stmt *cnosql.ShowContinuousQueriesStatement) (models.Rows, error) {
 	dis := e.MetaClient.Databases()
 
 	rows := []*models.Row{}
 	for _, di := range dis {
-		row := &models.Row{Columns: []string{"name", "query"}, Name: di.Name}
-		for _, cqi := range di.ContinuousQueries {
-			row.Values = append(row.Values, []interface{}{cqi.Name, cqi.Query})
+		row := &models.Row{Columns: []string{"name", "query", "last_run", "next_run", "last_error", "last_duration"}, Name: di.Name}
+		for i := range di.ContinuousQueries {
+			cqi := &di.ContinuousQueries[i]
+
+			var lastRun, nextRun, lastDuration interface{}
+			if !cqi.LastRun.IsZero() {
+				lastRun = cqi.LastRun.UTC().Format(time.RFC3339Nano)
+				lastDuration = cqi.LastDuration.String()
+			}
+			if e.ContinuousQuerier != nil {
+				if t, err := e.ContinuousQuerier.NextRun(di.Name, cqi); err == nil && !t.IsZero() {
+					nextRun = t.UTC().Format(time.RFC3339Nano)
+				}
+			}
+			row.Values = append(row.Values, []interface{}{cqi.Name, cqi.Query, lastRun, nextRun, cqi.LastError, lastDuration})
 		}
 		rows = append(rows, row)
 	}
@@ -708,6 +1040,66 @@ func (e *StatementExecutor) executeShowGrantsForUserStatement(q *cnosql.ShowGran
 	for d, p := range priv {
 		row.Values = append(row.Values, []interface{}{d, p.String()})
 	}
+	rows := []*models.Row{row}
+
+	sps, err := e.MetaClient.UserSeriesPrivileges(q.Name)
+	if err != nil {
+		return nil, err
+	} else if len(sps) > 0 {
+		row := &models.Row{Name: "series", Columns: []string{"database", "metric", "condition", "privilege"}}
+		for _, sp := range sps {
+			row.Values = append(row.Values, []interface{}{sp.Database, sp.Metric, sp.Condition, sp.Privilege.String()})
+		}
+		rows = append(rows, row)
+	}
+
+	for _, u := range e.MetaClient.Users() {
+		if u.Name == q.Name && len(u.Roles) > 0 {
+			row := &models.Row{Name: "roles", Columns: []string{"role"}}
+			for _, r := range u.Roles {
+				row.Values = append(row.Values, []interface{}{r})
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (e *StatementExecutor) executeShowGrantsForRoleStatement(q *cnosql.ShowGrantsForRoleStatement) (models.Rows, error) {
+	for _, ri := range e.MetaClient.Roles() {
+		if ri.Name != q.Name {
+			continue
+		}
+
+		row := &models.Row{Columns: []string{"database", "privilege"}}
+		for d, p := range ri.Privileges {
+			row.Values = append(row.Values, []interface{}{d, p.String()})
+		}
+		return []*models.Row{row}, nil
+	}
+	return nil, meta.ErrRoleNotFound
+}
+
+func (e *StatementExecutor) executeShowRolesStatement(q *cnosql.ShowRolesStatement) (models.Rows, error) {
+	users := make(map[string][]string)
+	for _, u := range e.MetaClient.Users() {
+		for _, r := range u.Roles {
+			users[r] = append(users[r], u.Name)
+		}
+	}
+
+	row := &models.Row{Columns: []string{"role", "roles", "users"}}
+	for _, ri := range e.MetaClient.Roles() {
+		roles := ri.Roles
+		if roles == nil {
+			roles = []string{}
+		}
+		members := users[ri.Name]
+		if members == nil {
+			members = []string{}
+		}
+		row.Values = append(row.Values, []interface{}{ri.Name, roles, members})
+	}
 	return []*models.Row{row}, nil
 }
 
@@ -716,7 +1108,7 @@ func (e *StatementExecutor) executeShowMetricsStatement(ctx *query.ExecutionCont
 		return ErrDatabaseNameRequired
 	}
 
-	names, err := e.TSDBStore.MetricNames(ctx.Authorizer, q.Database, q.Condition)
+	names, err := e.metricNames(ctx.Authorizer, q.Database, q.Condition)
 	if err != nil || len(names) == 0 {
 		return ctx.Send(&query.Result{
 			Err: err,
@@ -831,7 +1223,7 @@ func (e *StatementExecutor) executeShowSeriesCardinalityStatement(ctx *query.Exe
 		return nil, ErrDatabaseNameRequired
 	}
 
-	n, err := e.TSDBStore.SeriesCardinality(stmt.Database)
+	n, err := e.seriesCardinality(ctx.Authorizer, stmt.Database)
 	if err != nil {
 		return nil, err
 	}
@@ -913,10 +1305,10 @@ func (e *StatementExecutor) executeShowSubscriptionsStatement(stmt *cnosql.ShowS
 
 	rows := []*models.Row{}
 	for _, di := range dis {
-		row := &models.Row{Columns: []string{"ttl", "name", "mode", "destinations"}, Name: di.Name}
+		row := &models.Row{Columns: []string{"ttl", "name", "mode", "destinations", "durable"}, Name: di.Name}
 		for _, ttli := range di.TimeToLives {
 			for _, si := range ttli.Subscriptions {
-				row.Values = append(row.Values, []interface{}{ttli.Name, si.Name, si.Mode, si.Destinations})
+				row.Values = append(row.Values, []interface{}{ttli.Name, si.Name, si.Mode, si.Destinations, si.Durable})
 			}
 		}
 		if len(row.Values) > 0 {
@@ -946,24 +1338,7 @@ func (e *StatementExecutor) executeShowTagKeys(ctx *query.ExecutionContext, q *c
 		return err
 	}
 
-	// Get all shards for all time-to-lives.
-	var allGroups []meta.RegionInfo
-	for _, ttli := range di.TimeToLives {
-		sgis, err := e.MetaClient.RegionsByTimeRange(q.Database, ttli.Name, timeRange.MinTime(), timeRange.MaxTime())
-		if err != nil {
-			return err
-		}
-		allGroups = append(allGroups, sgis...)
-	}
-
-	var shardIDs []uint64
-	for _, sgi := range allGroups {
-		for _, si := range sgi.Shards {
-			shardIDs = append(shardIDs, si.ID)
-		}
-	}
-
-	tagKeys, err := e.TSDBStore.TagKeys(ctx.Authorizer, shardIDs, cond)
+	tagKeys, err := e.tagKeys(ctx.Authorizer, q.Database, cond, timeRange)
 	if err != nil {
 		return ctx.Send(&query.Result{
 			Err: err,
@@ -1033,24 +1408,7 @@ func (e *StatementExecutor) executeShowTagValues(ctx *query.ExecutionContext, q
 		return err
 	}
 
-	// Get all shards for all time-to-lives.
-	var allGroups []meta.RegionInfo
-	for _, ttli := range di.TimeToLives {
-		sgis, err := e.MetaClient.RegionsByTimeRange(q.Database, ttli.Name, timeRange.MinTime(), timeRange.MaxTime())
-		if err != nil {
-			return err
-		}
-		allGroups = append(allGroups, sgis...)
-	}
-
-	var shardIDs []uint64
-	for _, sgi := range allGroups {
-		for _, si := range sgi.Shards {
-			shardIDs = append(shardIDs, si.ID)
-		}
-	}
-
-	tagValues, err := e.TSDBStore.TagValues(ctx.Authorizer, shardIDs, cond)
+	tagValues, err := e.tagValues(ctx.Authorizer, q.Database, cond, timeRange)
 	if err != nil {
 		return ctx.Send(&query.Result{Err: err})
 	}
@@ -1101,6 +1459,295 @@ func (e *StatementExecutor) executeShowTagValues(ctx *query.ExecutionContext, q
 	return nil
 }
 
+// shards returns the shards of all time-to-lives of a database that overlap
+// the time range.
+func (e *StatementExecutor) shards(database string, timeRange cnosql.TimeRange) ([]meta.ShardInfo, error) {
+	di := e.MetaClient.Database(database)
+	if di == nil {
+		return nil, cnosdb.ErrDatabaseNotFound(database)
+	}
+
+	var shards []meta.ShardInfo
+	for _, ttli := range di.TimeToLives {
+		sgis, err := e.MetaClient.RegionsByTimeRange(database, ttli.Name, timeRange.MinTime(), timeRange.MaxTime())
+		if err != nil {
+			return nil, err
+		}
+		for _, sgi := range sgis {
+			shards = append(shards, sgi.Shards...)
+		}
+	}
+	return shards, nil
+}
+
+// seriesKey is a parsed series key.
+type seriesKey struct {
+	name string
+	tags models.Tags
+}
+
+// seriesCardinality returns an estimation of the number of series of a
+// database, merged from the sketches of the shards on every data node. The
+// series are counted exactly if auth restricts them, as each series must
+// then be authorized.
+func (e *StatementExecutor) seriesCardinality(auth query.FineAuthorizer, database string) (int64, error) {
+	if auth != nil && !auth.IsOpen() {
+		keys, err := e.seriesKeys(auth, database, cnosql.TimeRange{}, SeriesKeysOptions{})
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(keys)), nil
+	}
+
+	shards, err := e.shards(database, cnosql.TimeRange{})
+	if err != nil {
+		return 0, err
+	}
+	ss, ts, err := e.SeriesKeysReader.SeriesSketches(shards)
+	if err != nil {
+		return 0, err
+	}
+
+	n, tn := ss.Count(), ts.Count()
+	if tn > n {
+		return 0, nil
+	}
+	return int64(n - tn), nil
+}
+
+// seriesKeys returns the keys of the series of a database matching opt on
+// every data node, reduced by opt and sorted by metric name. Series that auth
+// may not read are skipped. The data nodes reduce the keys unless auth
+// restricts the series, as every series key is then needed to authorize it.
+func (e *StatementExecutor) seriesKeys(auth query.FineAuthorizer, database string, timeRange cnosql.TimeRange, opt SeriesKeysOptions) ([]seriesKey, error) {
+	shards, err := e.shards(database, timeRange)
+	if err != nil {
+		return nil, err
+	}
+
+	restricted := auth != nil && !auth.IsOpen()
+	read := opt
+	if restricted {
+		read.Reduce = ReduceSeriesKeys
+	}
+	keys, err := e.SeriesKeysReader.SeriesKeys(shards, read)
+	if err != nil {
+		return nil, err
+	}
+
+	if restricted {
+		set := make(map[string]struct{})
+		for _, key := range keys {
+			name, tags := models.ParseKey([]byte(key))
+			if auth.AuthorizeSeriesRead(database, []byte(name), tags) {
+				opt.reduce(key, set)
+			}
+		}
+		keys = keys[:0]
+		for key := range set {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	series := make([]seriesKey, 0, len(keys))
+	for _, key := range keys {
+		name, tags := models.ParseKey([]byte(key))
+		series = append(series, seriesKey{name: name, tags: tags})
+	}
+
+	// Keep the series of each metric next to each other.
+	sort.SliceStable(series, func(i, j int) bool { return series[i].name < series[j].name })
+	return series, nil
+}
+
+// splitTagKeyCondition splits cond into the condition on the series and the
+// condition on the "_tagKey" of the tags of the series.
+func splitTagKeyCondition(cond cnosql.Expr) (seriesCond, tagKeyCond cnosql.Expr) {
+	rewrite := func(keep func(name string) bool) cnosql.Expr {
+		return cnosql.Reduce(cnosql.RewriteExpr(cnosql.CloneExpr(cond), func(e cnosql.Expr) cnosql.Expr {
+			switch e := e.(type) {
+			case *cnosql.BinaryExpr:
+				switch e.Op {
+				case cnosql.EQ, cnosql.NEQ, cnosql.EQREGEX, cnosql.NEQREGEX:
+					tag, ok := e.LHS.(*cnosql.VarRef)
+					if !ok || !keep(tag.Val) {
+						return nil
+					}
+				}
+			}
+			return e
+		}), nil)
+	}
+
+	seriesCond = rewrite(func(name string) bool {
+		return name == "_name" || !cnosql.IsSystemName(name)
+	})
+	tagKeyCond = rewrite(func(name string) bool {
+		return name == "_tagKey"
+	})
+	return seriesCond, tagKeyCond
+}
+
+// metricNames returns the sorted names of the metrics with series matching cond.
+func (e *StatementExecutor) metricNames(auth query.FineAuthorizer, database string, cond cnosql.Expr) ([][]byte, error) {
+	series, err := e.seriesKeys(auth, database, cnosql.TimeRange{}, SeriesKeysOptions{
+		Condition: cond,
+		Reduce:    ReduceMetricNames,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([][]byte, 0, len(series))
+	for _, s := range series {
+		names = append(names, []byte(s.name))
+	}
+	return names, nil
+}
+
+// tagKeys returns the tag keys of each metric with series matching cond.
+func (e *StatementExecutor) tagKeys(auth query.FineAuthorizer, database string, cond cnosql.Expr, timeRange cnosql.TimeRange) ([]tsdb.TagKeys, error) {
+	seriesCond, tagKeyCond := splitTagKeyCondition(cond)
+	series, err := e.seriesKeys(auth, database, timeRange, SeriesKeysOptions{
+		Condition:       seriesCond,
+		Reduce:          ReduceTagKeys,
+		TagKeyCondition: tagKeyCond,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []tsdb.TagKeys
+	set := make(map[string]struct{})
+	for i, s := range series {
+		for _, t := range s.tags {
+			set[string(t.Value)] = struct{}{}
+		}
+
+		// Emit the keys once all the series of the metric are read.
+		if i < len(series)-1 && series[i+1].name == s.name {
+			continue
+		}
+		if len(set) > 0 {
+			keys := make([]string, 0, len(set))
+			for k := range set {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			results = append(results, tsdb.TagKeys{Metric: s.name, Keys: keys})
+		}
+		set = make(map[string]struct{})
+	}
+	return results, nil
+}
+
+// tagValues returns the tag values of each metric with series matching cond.
+func (e *StatementExecutor) tagValues(auth query.FineAuthorizer, database string, cond cnosql.Expr, timeRange cnosql.TimeRange) ([]tsdb.TagValues, error) {
+	if cond == nil {
+		return nil, errors.New("a condition is required")
+	}
+
+	seriesCond, tagKeyCond := splitTagKeyCondition(cond)
+	series, err := e.seriesKeys(auth, database, timeRange, SeriesKeysOptions{
+		Condition:       seriesCond,
+		Reduce:          ReduceTagValues,
+		TagKeyCondition: tagKeyCond,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []tsdb.TagValues
+	set := make(map[tsdb.KeyValue]struct{})
+	for i, s := range series {
+		for _, t := range s.tags {
+			set[tsdb.KeyValue{Key: string(t.Key), Value: string(t.Value)}] = struct{}{}
+		}
+
+		// Emit the values once all the series of the metric are read.
+		if i < len(series)-1 && series[i+1].name == s.name {
+			continue
+		}
+		if len(set) > 0 {
+			values := make(tsdb.KeyValues, 0, len(set))
+			for kv := range set {
+				values = append(values, kv)
+			}
+			sort.Sort(values)
+			results = append(results, tsdb.TagValues{Metric: s.name, Values: values})
+		}
+		set = make(map[tsdb.KeyValue]struct{})
+	}
+	return results, nil
+}
+
+// executeSetQuotaStatement changes the limits set by the statement and keeps
+// the other limits of the quota.
+func (e *StatementExecutor) executeSetQuotaStatement(q *cnosql.SetQuotaStatement) error {
+	if q.User != "" {
+		var quota meta.Quota
+		for _, ui := range e.MetaClient.Users() {
+			if ui.Name == q.User {
+				quota = ui.Quota
+			}
+		}
+		return e.MetaClient.SetUserQuota(q.User, setQuotaLimits(quota, q))
+	}
+
+	di := e.MetaClient.Database(q.Database)
+	if di == nil {
+		return cnosdb.ErrDatabaseNotFound(q.Database)
+	}
+	return e.MetaClient.SetDatabaseQuota(q.Database, setQuotaLimits(di.Quota, q))
+}
+
+// setQuotaLimits returns quota with the limits set by the statement.
+func setQuotaLimits(quota meta.Quota, q *cnosql.SetQuotaStatement) meta.Quota {
+	if q.WritePoints != nil {
+		quota.WritePoints = *q.WritePoints
+	}
+	if q.WriteBytes != nil {
+		quota.WriteBytes = *q.WriteBytes
+	}
+	if q.Queries != nil {
+		quota.Queries = *q.Queries
+	}
+	if q.SelectSeries != nil {
+		quota.SelectSeries = *q.SelectSeries
+	}
+	return quota
+}
+
+func (e *StatementExecutor) executeDropQuotaStatement(q *cnosql.DropQuotaStatement) error {
+	if q.User != "" {
+		return e.MetaClient.SetUserQuota(q.User, meta.Quota{})
+	}
+	return e.MetaClient.SetDatabaseQuota(q.Database, meta.Quota{})
+}
+
+func (e *StatementExecutor) executeShowQuotasStatement(q *cnosql.ShowQuotasStatement) (models.Rows, error) {
+	columns := []string{"name", "write_points", "write_bytes", "queries", "select_series"}
+	quotaValues := func(name string, quota meta.Quota) []interface{} {
+		return []interface{}{name, quota.WritePoints, quota.WriteBytes, quota.Queries, quota.SelectSeries}
+	}
+
+	databases := &models.Row{Name: "databases", Columns: columns}
+	for _, di := range e.MetaClient.Databases() {
+		if di.Quota != (meta.Quota{}) {
+			databases.Values = append(databases.Values, quotaValues(di.Name, di.Quota))
+		}
+	}
+
+	users := &models.Row{Name: "users", Columns: columns}
+	for _, ui := range e.MetaClient.Users() {
+		if ui.Quota != (meta.Quota{}) {
+			users.Values = append(users.Values, quotaValues(ui.Name, ui.Quota))
+		}
+	}
+	return []*models.Row{databases, users}, nil
+}
+
 func (e *StatementExecutor) executeShowUsersStatement(q *cnosql.ShowUsersStatement) (models.Rows, error) {
 	row := &models.Row{Columns: []string{"user", "admin"}}
 	for _, ui := range e.MetaClient.Users() {
@@ -1368,11 +2015,6 @@ type TSDBStore interface {
 	DeleteSeries(database string, sources []cnosql.Source, condition cnosql.Expr) error
 	DeleteShard(id uint64) error
 
-	MetricNames(auth query.FineAuthorizer, database string, cond cnosql.Expr) ([][]byte, error)
-	TagKeys(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.TagKeys, error)
-	TagValues(auth query.FineAuthorizer, shardIDs []uint64, cond cnosql.Expr) ([]tsdb.TagValues, error)
-
-	SeriesCardinality(database string) (int64, error)
 	MetricsCardinality(database string) (int64, error)
 
 	Region(ids []uint64) tsdb.Region
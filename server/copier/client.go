@@ -0,0 +1,81 @@
+package copier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cnosdatabase/cnosdb/pkg/network"
+)
+
+// Client provides an API for the shard copier service.
+type Client struct {
+	host string
+}
+
+// NewClient returns a new *Client.
+func NewClient(host string) *Client {
+	return &Client{host: host}
+}
+
+// CopyShard starts copying a shard from the source node to the node. The
+// copy reads at most rateLimit bytes per second if rateLimit is positive.
+func (c *Client) CopyShard(shardID uint64, source string, rateLimit int64) error {
+	_, err := c.doRequest(&Request{Type: RequestCopyShard, ShardID: shardID, Source: source, RateLimit: rateLimit})
+	return err
+}
+
+// ShardSizes returns the disk size of each shard of the node.
+func (c *Client) ShardSizes() (map[uint64]int64, error) {
+	resp, err := c.doRequest(&Request{Type: RequestShardSizes})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Sizes, nil
+}
+
+// Status returns the status of the shard copies to the node.
+func (c *Client) Status() ([]CopyStatus, error) {
+	resp, err := c.doRequest(&Request{Type: RequestCopyShardStatus})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Copies, nil
+}
+
+// KillCopyShard aborts a running copy of a shard to the node.
+func (c *Client) KillCopyShard(shardID uint64) error {
+	_, err := c.doRequest(&Request{Type: RequestKillCopyShard, ShardID: shardID})
+	return err
+}
+
+// RemoveShard deletes the data of a shard that is no longer owned by the node.
+func (c *Client) RemoveShard(shardID uint64) error {
+	_, err := c.doRequest(&Request{Type: RequestRemoveShard, ShardID: shardID})
+	return err
+}
+
+// doRequest sends a request to the shard copier service and returns the response.
+func (c *Client) doRequest(req *Request) (*Response, error) {
+	// Connect to shard copier service.
+	conn, err := network.Dial("tcp", c.host, MuxHeader)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Write the request
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("encode shard copier request: %s", err)
+	}
+
+	// Read the response
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("decode shard copier response: %s", err)
+	}
+	if resp.Err != "" {
+		return nil, errors.New(resp.Err)
+	}
+	return &resp, nil
+}
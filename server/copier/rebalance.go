@@ -0,0 +1,168 @@
+package copier
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/meta"
+)
+
+// DefaultRebalanceRateLimit is the default number of bytes per second read
+// from a source data node by the shard copies of a rebalance.
+const DefaultRebalanceRateLimit = 32 * 1024 * 1024
+
+// Move is a move of a shard from a data node to another.
+type Move struct {
+	ShardID     uint64
+	Database    string
+	TimeToLive  string
+	Source      uint64
+	Destination uint64
+	Size        int64
+}
+
+// movableShard is a shard that can be moved by a rebalance.
+type movableShard struct {
+	database string
+	ttl      string
+	id       uint64
+	owners   []uint64
+	size     int64
+	moved    bool
+}
+
+func (m *movableShard) ownedBy(nodeID uint64) bool {
+	for _, id := range m.owners {
+		if id == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanRebalance returns the shard moves that even out the disk size of the
+// shards owned by each data node.
+//
+// sizes holds the disk size of each shard reported by each data node. A
+// shard is weighted by the largest size reported by its owners. Shards of
+// regions that are still written to at now are never moved, and each shard
+// is moved at most once. Planning stops once the difference between the
+// most and the least loaded data nodes is within tolerance of the mean load.
+func PlanRebalance(data *meta.Data, sizes map[uint64]map[uint64]int64, now time.Time, tolerance float64) []Move {
+	if len(data.DataNodes) < 2 {
+		return nil
+	}
+
+	loads := make(map[uint64]int64, len(data.DataNodes))
+	for _, n := range data.DataNodes {
+		loads[n.ID] = 0
+	}
+
+	var shards []*movableShard
+	var total int64
+	for _, dbi := range data.Databases {
+		for _, ttli := range dbi.TimeToLives {
+			for _, rg := range ttli.Regions {
+				if rg.Deleted() {
+					continue
+				}
+
+				for _, si := range rg.Shards {
+					m := &movableShard{database: dbi.Name, ttl: ttli.Name, id: si.ID}
+					for _, owner := range si.Owners {
+						m.owners = append(m.owners, owner.NodeID)
+						if size := sizes[owner.NodeID][si.ID]; size > m.size {
+							m.size = size
+						}
+					}
+
+					for _, nodeID := range m.owners {
+						if _, ok := loads[nodeID]; ok {
+							loads[nodeID] += m.size
+							total += m.size
+						}
+					}
+					if rg.EndTime.Before(now) && m.size > 0 {
+						shards = append(shards, m)
+					}
+				}
+			}
+		}
+	}
+	threshold := tolerance * float64(total) / float64(len(loads))
+
+	var moves []Move
+	for {
+		move := planMove(shards, loads, threshold)
+		if move == nil {
+			return moves
+		}
+		loads[move.Source] -= move.Size
+		loads[move.Destination] += move.Size
+		moves = append(moves, *move)
+	}
+}
+
+// planMove returns the move of a shard from the most loaded data node that
+// brings the loads of the two data nodes the closest, or nil if no move
+// reduces the difference between them.
+func planMove(shards []*movableShard, loads map[uint64]int64, threshold float64) *Move {
+	nodeIDs := make([]uint64, 0, len(loads))
+	for id := range loads {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		if loads[nodeIDs[i]] != loads[nodeIDs[j]] {
+			return loads[nodeIDs[i]] > loads[nodeIDs[j]]
+		}
+		return nodeIDs[i] < nodeIDs[j]
+	})
+
+	// Try the most loaded nodes as the source and the least loaded nodes
+	// as the destination first.
+	for i := 0; i < len(nodeIDs); i++ {
+		for j := len(nodeIDs) - 1; j > i; j-- {
+			src, dst := nodeIDs[i], nodeIDs[j]
+			diff := loads[src] - loads[dst]
+			if float64(diff) <= threshold {
+				break
+			}
+
+			var best *movableShard
+			for _, m := range shards {
+				if m.moved || m.size >= diff || !m.ownedBy(src) || m.ownedBy(dst) {
+					continue
+				}
+				if best == nil || abs(2*m.size-diff) < abs(2*best.size-diff) {
+					best = m
+				}
+			}
+			if best == nil {
+				continue
+			}
+
+			best.moved = true
+			for k, id := range best.owners {
+				if id == src {
+					best.owners[k] = dst
+				}
+			}
+			return &Move{
+				ShardID:     best.id,
+				Database:    best.database,
+				TimeToLive:  best.ttl,
+				Source:      src,
+				Destination: dst,
+				Size:        best.size,
+			}
+		}
+	}
+	return nil
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
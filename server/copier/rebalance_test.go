@@ -0,0 +1,134 @@
+package copier
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/meta"
+)
+
+// rebalanceShard is a shard of a cluster to rebalance.
+type rebalanceShard struct {
+	id     uint64
+	hot    bool // the region of the shard is still written to
+	owners []uint64
+	size   int64
+}
+
+// newRebalanceData returns the meta data of a cluster of nodeN data nodes
+// owning the shards, each in a region of its own, and the sizes of the
+// shards reported by their owners.
+func newRebalanceData(now time.Time, nodeN int, shards []rebalanceShard) (*meta.Data, map[uint64]map[uint64]int64) {
+	data := &meta.Data{}
+	sizes := make(map[uint64]map[uint64]int64)
+	for i := 1; i <= nodeN; i++ {
+		data.DataNodes = append(data.DataNodes, meta.NodeInfo{ID: uint64(i)})
+		sizes[uint64(i)] = make(map[uint64]int64)
+	}
+
+	ttli := meta.TimeToLiveInfo{Name: "autogen"}
+	for _, s := range shards {
+		rg := meta.RegionInfo{ID: s.id, StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)}
+		if s.hot {
+			rg.StartTime, rg.EndTime = now.Add(-time.Hour), now.Add(time.Hour)
+		}
+
+		si := meta.ShardInfo{ID: s.id}
+		for _, id := range s.owners {
+			si.Owners = append(si.Owners, meta.ShardOwner{NodeID: id})
+			sizes[id][s.id] = s.size
+		}
+		rg.Shards = []meta.ShardInfo{si}
+		ttli.Regions = append(ttli.Regions, rg)
+	}
+	data.Databases = []meta.DatabaseInfo{{Name: "db0", TimeToLives: []meta.TimeToLiveInfo{ttli}}}
+	return data, sizes
+}
+
+func TestPlanRebalance(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		nodeN  int
+		shards []rebalanceShard
+		moves  []Move
+	}{
+		{
+			name:  "SingleNode",
+			nodeN: 1,
+			shards: []rebalanceShard{
+				{id: 1, owners: []uint64{1}, size: 100},
+				{id: 2, owners: []uint64{1}, size: 100},
+			},
+		},
+		{
+			name:  "Balanced",
+			nodeN: 2,
+			shards: []rebalanceShard{
+				{id: 1, owners: []uint64{1}, size: 100},
+				{id: 2, owners: []uint64{2}, size: 95},
+			},
+		},
+		{
+			name:  "Skewed",
+			nodeN: 2,
+			shards: []rebalanceShard{
+				{id: 1, owners: []uint64{1}, size: 100},
+				{id: 2, owners: []uint64{1}, size: 100},
+			},
+			moves: []Move{
+				{ShardID: 1, Database: "db0", TimeToLive: "autogen", Source: 1, Destination: 2, Size: 100},
+			},
+		},
+		{
+			name:  "Skewed_ThreeNodes",
+			nodeN: 3,
+			shards: []rebalanceShard{
+				{id: 1, owners: []uint64{1}, size: 300},
+				{id: 2, owners: []uint64{1}, size: 200},
+				{id: 3, owners: []uint64{1}, size: 100},
+			},
+			moves: []Move{
+				{ShardID: 1, Database: "db0", TimeToLive: "autogen", Source: 1, Destination: 3, Size: 300},
+				{ShardID: 2, Database: "db0", TimeToLive: "autogen", Source: 1, Destination: 2, Size: 200},
+			},
+		},
+		{
+			name:  "Skewed_Replicated",
+			nodeN: 3,
+			shards: []rebalanceShard{
+				{id: 1, owners: []uint64{1, 2}, size: 100},
+				{id: 2, owners: []uint64{1, 2}, size: 100},
+			},
+			moves: []Move{
+				{ShardID: 1, Database: "db0", TimeToLive: "autogen", Source: 1, Destination: 3, Size: 100},
+			},
+		},
+		{
+			name:  "HotRegion",
+			nodeN: 2,
+			shards: []rebalanceShard{
+				{id: 1, owners: []uint64{1}, size: 100},
+				{id: 2, hot: true, owners: []uint64{1}, size: 300},
+			},
+			moves: []Move{
+				{ShardID: 1, Database: "db0", TimeToLive: "autogen", Source: 1, Destination: 2, Size: 100},
+			},
+		},
+		{
+			name:  "HotRegion_Only",
+			nodeN: 2,
+			shards: []rebalanceShard{
+				{id: 1, hot: true, owners: []uint64{1}, size: 100},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			data, sizes := newRebalanceData(now, tt.nodeN, tt.shards)
+			if moves := PlanRebalance(data, sizes, now, 0.1); !reflect.DeepEqual(moves, tt.moves) {
+				t.Fatalf("unexpected moves:\n got %+v\n exp %+v", moves, tt.moves)
+			}
+		})
+	}
+}
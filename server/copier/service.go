@@ -0,0 +1,519 @@
+// Package copier provides the service that copies shards between data nodes.
+package copier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb"
+	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/cnosdb/server/snapshotter"
+	"github.com/cnosdatabase/db/tsdb"
+	"go.uber.org/zap"
+)
+
+const (
+	// MuxHeader is the header byte used for the TCP muxer.
+	MuxHeader = "copier"
+
+	// removeShardTimeout is how long to wait for the metadata to show that
+	// a removed shard is no longer owned by the local node.
+	removeShardTimeout = 10 * time.Second
+)
+
+var (
+	// ErrCopyAborted is returned when a shard copy is killed.
+	ErrCopyAborted = errors.New("shard copy aborted")
+
+	// ErrCopyInProgress is returned when copying a shard that is already being copied.
+	ErrCopyInProgress = errors.New("shard copy already in progress")
+
+	// ErrCopyNotFound is returned when killing a shard copy that isn't running.
+	ErrCopyNotFound = errors.New("shard copy not found")
+)
+
+// The states of a shard copy.
+const (
+	StateCopying   = "copying"
+	StateCompleted = "completed"
+	StateFailed    = "failed"
+	StateAborted   = "aborted"
+)
+
+// CopyStatus is the status of a shard copy to the local node.
+type CopyStatus struct {
+	ShardID     uint64    `json:"shardID"`
+	Database    string    `json:"database"`
+	TimeToLive  string    `json:"ttl"`
+	Source      string    `json:"source"`
+	State       string    `json:"state"`
+	BytesCopied int64     `json:"bytesCopied"`
+	RateLimit   int64     `json:"rateLimit,omitempty"`
+	StartedAt   time.Time `json:"startedAt"`
+	EndedAt     time.Time `json:"endedAt,omitempty"`
+	Err         string    `json:"error,omitempty"`
+}
+
+// task is a shard copy to the local node.
+type task struct {
+	status CopyStatus
+	n      int64 // bytes copied, updated atomically
+
+	once  sync.Once
+	abort chan struct{}
+
+	mu    sync.Mutex
+	rc    io.ReadCloser
+	start time.Time
+}
+
+// Read reads the shard backup from the source node until the copy is aborted.
+func (t *task) Read(p []byte) (int, error) {
+	select {
+	case <-t.abort:
+		return 0, ErrCopyAborted
+	default:
+	}
+
+	t.mu.Lock()
+	rc := t.rc
+	t.mu.Unlock()
+
+	n, err := rc.Read(p)
+	copied := atomic.AddInt64(&t.n, int64(n))
+
+	// Throttle the copy to the rate limit.
+	var delay time.Duration
+	if t.status.RateLimit > 0 {
+		delay = time.Duration(copied*int64(time.Second)/t.status.RateLimit) - time.Since(t.start)
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-t.abort:
+			return n, ErrCopyAborted
+		case <-timer.C:
+		}
+	}
+
+	select {
+	case <-t.abort:
+		return n, ErrCopyAborted
+	default:
+	}
+	return n, err
+}
+
+// kill aborts the copy and closes the connection to the source node.
+func (t *task) kill() {
+	t.once.Do(func() {
+		close(t.abort)
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.rc != nil {
+			t.rc.Close()
+		}
+	})
+}
+
+// Service copies shards from the other data nodes to the local node and
+// removes the local shards that were moved to other nodes.
+type Service struct {
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	closing chan struct{}
+
+	tasks map[uint64]*task
+
+	Node *cnosdb.Node
+
+	MetaClient interface {
+		ShardOwner(shardID uint64) (database, ttl string, sgi *meta.RegionInfo)
+		DataNodeByTCPHost(tcpAddr string) (*meta.NodeInfo, error)
+		UpdateShardOwners(id uint64, addOwners, delOwners []uint64) error
+		WaitForDataChanged() chan struct{}
+	}
+
+	TSDBStore interface {
+		Shard(id uint64) *tsdb.Shard
+		CreateShard(database, ttl string, shardID uint64, enabled bool) error
+		RestoreShard(id uint64, r io.Reader) error
+		SetShardEnabled(shardID uint64, enabled bool) error
+		DeleteShard(shardID uint64) error
+		ShardIDs() []uint64
+	}
+
+	Listener net.Listener
+	Logger   *zap.Logger
+}
+
+// NewService returns a new instance of Service.
+func NewService() *Service {
+	return &Service{
+		tasks:  make(map[uint64]*task),
+		Logger: zap.NewNop(),
+	}
+}
+
+// WithLogger sets the logger on the service.
+func (s *Service) WithLogger(log *zap.Logger) {
+	s.Logger = log.With(zap.String("service", "copier"))
+}
+
+// Open starts the service.
+func (s *Service) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closing != nil {
+		return nil
+	}
+	s.closing = make(chan struct{})
+
+	s.Logger.Info("Starting shard copier service")
+
+	s.wg.Add(1)
+	go s.serve()
+	return nil
+}
+
+// Close aborts the running copies and stops the service.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	if s.closing == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	close(s.closing)
+	for _, t := range s.tasks {
+		t.kill()
+	}
+	s.mu.Unlock()
+
+	if s.Listener != nil {
+		if err := s.Listener.Close(); err != nil {
+			return err
+		}
+	}
+	s.wg.Wait()
+
+	s.mu.Lock()
+	s.closing = nil
+	s.mu.Unlock()
+	return nil
+}
+
+// CopyShard starts copying a shard from the source node to the local node,
+// reading at most rateLimit bytes per second if rateLimit is positive. The
+// local node is added to the owners of the shard once all of its data has
+// been copied.
+//
+// Points written to the shard after its backup started would never reach
+// the local node, so only the shards of regions that ended are copied.
+func (s *Service) CopyShard(shardID uint64, source string, rateLimit int64) error {
+	database, ttl, sgi := s.MetaClient.ShardOwner(shardID)
+	if sgi == nil {
+		return meta.ErrShardNotFound
+	} else if !sgi.EndTime.Before(time.Now()) {
+		return fmt.Errorf("shard %d may still be written to: its region ends at %s", shardID, sgi.EndTime.UTC().Format(time.RFC3339))
+	}
+
+	ni, err := s.MetaClient.DataNodeByTCPHost(source)
+	if err != nil {
+		return fmt.Errorf("source %s: %s", source, err)
+	}
+	for _, si := range sgi.Shards {
+		if si.ID != shardID {
+			continue
+		}
+		if !si.OwnedBy(ni.ID) {
+			return fmt.Errorf("shard %d is not owned by node %d", shardID, ni.ID)
+		} else if si.OwnedBy(s.Node.ID) {
+			return fmt.Errorf("shard %d is already owned by node %d", shardID, s.Node.ID)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t := s.tasks[shardID]; t != nil && t.status.State == StateCopying {
+		return ErrCopyInProgress
+	} else if s.TSDBStore.Shard(shardID) != nil {
+		return fmt.Errorf("shard %d already exists on node %d", shardID, s.Node.ID)
+	}
+
+	t := &task{
+		status: CopyStatus{
+			ShardID:    shardID,
+			Database:   database,
+			TimeToLive: ttl,
+			Source:     source,
+			State:      StateCopying,
+			RateLimit:  rateLimit,
+			StartedAt:  time.Now().UTC(),
+		},
+		abort: make(chan struct{}),
+	}
+	s.tasks[shardID] = t
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.copyShard(t)
+	}()
+	return nil
+}
+
+// copyShard restores the backup of the shard streamed from the source node
+// into a new local shard and takes ownership of it.
+func (s *Service) copyShard(t *task) {
+	log := s.Logger.With(zap.Uint64("shard_id", t.status.ShardID), zap.String("source", t.status.Source))
+	log.Info("Copying shard")
+
+	err := s.restoreShard(t)
+	if err == nil {
+		err = s.MetaClient.UpdateShardOwners(t.status.ShardID, []uint64{s.Node.ID}, nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.status.EndedAt = time.Now().UTC()
+	if err != nil {
+		log.Info("Failed to copy shard", zap.Error(err))
+		if err := s.TSDBStore.DeleteShard(t.status.ShardID); err != nil {
+			log.Info("Failed to delete partial shard", zap.Error(err))
+		}
+
+		t.status.State, t.status.Err = StateFailed, err.Error()
+		select {
+		case <-t.abort:
+			t.status.State = StateAborted
+		default:
+		}
+		return
+	}
+	log.Info("Copied shard", zap.Int64("bytes", atomic.LoadInt64(&t.n)))
+	t.status.State = StateCompleted
+}
+
+// restoreShard creates the local shard and restores the backup streamed
+// from the source node into it.
+func (s *Service) restoreShard(t *task) error {
+	if err := s.TSDBStore.CreateShard(t.status.Database, t.status.TimeToLive, t.status.ShardID, false); err != nil {
+		return err
+	}
+
+	rc, err := snapshotter.NewClient(t.status.Source).BackupShard(t.status.ShardID, time.Time{})
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	t.rc = rc
+	t.start = time.Now()
+	t.mu.Unlock()
+
+	select {
+	case <-t.abort:
+		rc.Close()
+		return ErrCopyAborted
+	default:
+	}
+	defer rc.Close()
+
+	if err := s.TSDBStore.RestoreShard(t.status.ShardID, t); err != nil {
+		return err
+	} else if atomic.LoadInt64(&t.n) == 0 {
+		// An empty shard still streams the trailer of the archive, so no
+		// data means the source failed to back up the shard.
+		return fmt.Errorf("no data received from %s", t.status.Source)
+	}
+	return s.TSDBStore.SetShardEnabled(t.status.ShardID, true)
+}
+
+// KillCopyShard aborts a running copy of a shard.
+func (s *Service) KillCopyShard(shardID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := s.tasks[shardID]
+	if t == nil || t.status.State != StateCopying {
+		return ErrCopyNotFound
+	}
+	t.kill()
+	return nil
+}
+
+// Status returns the status of the shard copies to the local node.
+func (s *Service) Status() []CopyStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := make([]CopyStatus, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		status := t.status
+		status.BytesCopied = atomic.LoadInt64(&t.n)
+		a = append(a, status)
+	}
+	sort.Slice(a, func(i, j int) bool { return a[i].ShardID < a[j].ShardID })
+	return a
+}
+
+// ShardSizes returns the disk size of each local shard.
+func (s *Service) ShardSizes() (map[uint64]int64, error) {
+	sizes := make(map[uint64]int64)
+	for _, id := range s.TSDBStore.ShardIDs() {
+		sh := s.TSDBStore.Shard(id)
+		if sh == nil {
+			continue
+		}
+
+		size, err := sh.DiskSize()
+		if err != nil {
+			return nil, err
+		}
+		sizes[id] = size
+	}
+	return sizes, nil
+}
+
+// RemoveShard deletes the local data of a shard that is no longer owned by
+// the local node. As the owners are usually updated right before, it waits
+// up to removeShardTimeout for the local metadata to catch up.
+func (s *Service) RemoveShard(shardID uint64) error {
+	timeout := time.After(removeShardTimeout)
+	for s.ownsShard(shardID) {
+		select {
+		case <-s.MetaClient.WaitForDataChanged():
+		case <-timeout:
+			return fmt.Errorf("shard %d is still owned by node %d", shardID, s.Node.ID)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t := s.tasks[shardID]; t != nil && t.status.State == StateCopying {
+		return ErrCopyInProgress
+	}
+
+	s.Logger.Info("Removing shard", zap.Uint64("shard_id", shardID))
+	return s.TSDBStore.DeleteShard(shardID)
+}
+
+// ownsShard returns true if the local node owns the shard.
+func (s *Service) ownsShard(shardID uint64) bool {
+	_, _, sgi := s.MetaClient.ShardOwner(shardID)
+	if sgi == nil {
+		return false
+	}
+	for _, si := range sgi.Shards {
+		if si.ID == shardID {
+			return si.OwnedBy(s.Node.ID)
+		}
+	}
+	return false
+}
+
+// serve serves the shard copier requests from the listener.
+func (s *Service) serve() {
+	defer s.wg.Done()
+
+	for {
+		// Wait for next connection.
+		conn, err := s.Listener.Accept()
+		if err != nil && strings.Contains(err.Error(), "connection closed") {
+			s.Logger.Info("Listener closed")
+			return
+		} else if err != nil {
+			s.Logger.Info("Error accepting shard copier request", zap.Error(err))
+			continue
+		}
+
+		// Handle connection in separate goroutine.
+		s.wg.Add(1)
+		go func(conn net.Conn) {
+			defer s.wg.Done()
+			defer conn.Close()
+			if err := s.handleConn(conn); err != nil {
+				s.Logger.Info(err.Error())
+			}
+		}(conn)
+	}
+}
+
+// handleConn processes conn. This is run in a separate goroutine.
+func (s *Service) handleConn(conn net.Conn) error {
+	var r Request
+	if err := json.NewDecoder(conn).Decode(&r); err != nil {
+		return fmt.Errorf("read request: %s", err)
+	}
+
+	var resp Response
+	var err error
+	switch r.Type {
+	case RequestCopyShard:
+		err = s.CopyShard(r.ShardID, r.Source, r.RateLimit)
+	case RequestCopyShardStatus:
+		resp.Copies = s.Status()
+	case RequestKillCopyShard:
+		err = s.KillCopyShard(r.ShardID)
+	case RequestRemoveShard:
+		err = s.RemoveShard(r.ShardID)
+	case RequestShardSizes:
+		resp.Sizes, err = s.ShardSizes()
+	default:
+		err = fmt.Errorf("request type unknown: %v", r.Type)
+	}
+	if err != nil {
+		resp.Err = err.Error()
+	}
+
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		return fmt.Errorf("encode response: %s", err)
+	}
+	return nil
+}
+
+// RequestType indicates the type of a shard copier request.
+type RequestType uint8
+
+const (
+	// RequestCopyShard represents a request to copy a shard to the node.
+	RequestCopyShard RequestType = iota
+
+	// RequestCopyShardStatus represents a request for the status of the shard copies.
+	RequestCopyShardStatus
+
+	// RequestKillCopyShard represents a request to abort a shard copy.
+	RequestKillCopyShard
+
+	// RequestRemoveShard represents a request to remove a shard moved off the node.
+	RequestRemoveShard
+
+	// RequestShardSizes represents a request for the disk size of the shards of the node.
+	RequestShardSizes
+)
+
+// Request represents a request to the shard copier service.
+type Request struct {
+	Type      RequestType
+	ShardID   uint64
+	Source    string
+	RateLimit int64
+}
+
+// Response represents the response of the shard copier service.
+type Response struct {
+	Copies []CopyStatus
+	Sizes  map[uint64]int64
+	Err    string
+}
@@ -0,0 +1,581 @@
+package server
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/cnosdb/pkg/escape"
+	"github.com/cnosdatabase/cnosdb/pkg/parquet"
+	"github.com/cnosdatabase/cnosql"
+	"github.com/cnosdatabase/db/models"
+	"github.com/cnosdatabase/db/query"
+	"go.uber.org/zap"
+)
+
+// Formats of an export.
+const (
+	exportFormatLineProtocol = "lp"
+	exportFormatCSV          = "csv"
+	exportFormatParquet      = "parquet"
+)
+
+// serveExport streams the points of a database in line protocol, CSV or
+// Parquet. The points are selected by a metric regex, a condition on the
+// tags and a time range, and read by a query so that the authorization and
+// the limits of the queries apply. Line protocol and CSV are gzipped if the
+// client accepts it, Parquet pages are always gzipped. An error occurring
+// once the response is started is sent in the X-CnosDB-Error trailer.
+func (h *Handler) serveExport(w http.ResponseWriter, r *http.Request, user meta.User) {
+	atomic.AddInt64(&h.stats.QueryRequests, 1)
+	atomic.AddInt64(&h.stats.ExportRequests, 1)
+	defer func(start time.Time) {
+		atomic.AddInt64(&h.stats.QueryRequestDuration, time.Since(start).Nanoseconds())
+	}(time.Now())
+	h.requestTracker.Add(r, user)
+
+	db := r.FormValue("db")
+	ttl := r.FormValue("ttl")
+	if db == "" {
+		writeError(w, "database is required")
+		return
+	}
+
+	format := r.FormValue("format")
+	if format == "" {
+		format = exportFormatLineProtocol
+	}
+	switch format {
+	case exportFormatLineProtocol, exportFormatCSV, exportFormatParquet:
+	default:
+		writeError(w, fmt.Sprintf("unknown export format: %q", format))
+		return
+	}
+
+	stmt, err := exportStatement(db, ttl, r.FormValue("metric"), r.FormValue("where"), r.FormValue("start"), r.FormValue("end"))
+	if err != nil {
+		writeError(w, err.Error())
+		return
+	}
+
+	// Parquet files need the tag and field keys of the metrics up front.
+	q := &cnosql.Query{}
+	if format == exportFormatParquet {
+		q.Statements = append(q.Statements,
+			&cnosql.ShowTagKeysStatement{Database: db, Sources: stmt.Sources},
+			&cnosql.ShowFieldKeysStatement{Database: db, Sources: stmt.Sources},
+		)
+	}
+	q.Statements = append(q.Statements, stmt)
+
+	// Check authorization.
+	var fineAuthorizer query.FineAuthorizer
+	if h.config.AuthEnabled {
+		if fineAuthorizer, err = h.QueryAuthorizer.AuthorizeQuery(user, q, db); err != nil {
+			writeErrorWithCode(w, "error authorizing query: "+err.Error(), http.StatusForbidden)
+			return
+		}
+	} else {
+		fineAuthorizer = query.OpenAuthorizer
+	}
+
+	opts := query.ExecutionOptions{
+		Database:   db,
+		TimeToLive: ttl,
+		ChunkSize:  DefaultChunkSize,
+		ReadOnly:   true,
+		Authorizer: fineAuthorizer,
+	}
+	if h.config.AuthEnabled {
+		opts.CoarseAuthorizer = &userQueryAuthorizer{
+			auth: h.QueryAuthorizer,
+			user: user,
+		}
+		if user != nil {
+			opts.UserID = user.ID()
+		}
+	} else {
+		opts.CoarseAuthorizer = query.OpenCoarseAuthorizer
+	}
+
+	// Abort the query if the request fails before all results were read.
+	closing := make(chan struct{})
+	defer close(closing)
+
+	var gw *gzip.Writer
+	var cw *countingWriter
+	var ew exportWriter
+	start := func() {
+		switch format {
+		case exportFormatLineProtocol:
+			w.Header().Set(headerContentType, "text/plain; charset=utf-8")
+		case exportFormatCSV:
+			w.Header().Set(headerContentType, "text/csv")
+		case exportFormatParquet:
+			w.Header().Set(headerContentType, "application/vnd.apache.parquet")
+		}
+		cw = &countingWriter{w: w}
+		if format != exportFormatParquet && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			w.Header().Set("Content-Encoding", "gzip")
+			gw = getGzipWriter(cw)
+		}
+		w.Header().Set("Trailer", headerErrorMsg)
+		writeHeader(w, http.StatusOK)
+
+		var out io.Writer = cw
+		if gw != nil {
+			out = gw
+		}
+		switch format {
+		case exportFormatLineProtocol:
+			ew = &lineProtocolExporter{w: out}
+		case exportFormatCSV:
+			ew = &csvExporter{w: csv.NewWriter(out)}
+		case exportFormatParquet:
+			ew = &parquetExporter{w: out, stmtID: len(q.Statements) - 1}
+		}
+	}
+	defer func() {
+		if gw != nil {
+			putGzipWriter(gw)
+		}
+		if cw != nil {
+			atomic.AddInt64(&h.stats.QueryRequestBytesTransmitted, cw.n)
+		}
+	}()
+
+	// The response starts with the first result, so that a query failing
+	// at once is answered with an error status.
+	for res := range h.QueryExecutor.ExecuteQuery(q, opts, closing) {
+		if res.Err != nil {
+			if ew == nil {
+				if isQuotaExceeded(res.Err) {
+					h.writeQuotaExceeded(w, res.Err)
+				} else {
+					writeError(w, res.Err.Error())
+				}
+				return
+			}
+			h.abortExport(w, res.Err)
+			return
+		}
+
+		if ew == nil {
+			start()
+		}
+		if err := ew.WriteResult(res); err != nil {
+			h.abortExport(w, err)
+			return
+		}
+	}
+
+	if ew == nil {
+		start()
+	}
+	if err := ew.Close(); err != nil {
+		h.abortExport(w, err)
+	}
+}
+
+// abortExport reports an error occurring once an export is started in the
+// trailer of the response.
+func (h *Handler) abortExport(w http.ResponseWriter, err error) {
+	h.logger.Info("Export failed", zap.Error(err))
+	w.Header().Set(headerErrorMsg, err.Error())
+}
+
+// exportStatement returns the query selecting the points of an export. All
+// metrics are selected if the regex is empty, and the condition is a CnosQL
+// expression on the tags. The start and end times are RFC3339 timestamps,
+// the end being excluded.
+func exportStatement(db, ttl, metric, where, start, end string) (*cnosql.SelectStatement, error) {
+	src := &cnosql.Metric{
+		Database:   db,
+		TimeToLive: ttl,
+		Regex:      &cnosql.RegexLiteral{Val: regexp.MustCompile(".+")},
+	}
+	if metric != "" {
+		re, err := regexp.Compile(metric)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric regex: %s", err)
+		}
+		src.Regex.Val = re
+	}
+
+	var cond cnosql.Expr
+	if where != "" {
+		expr, err := cnosql.ParseExpr(where)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition: %s", err)
+		}
+		cond = &cnosql.ParenExpr{Expr: expr}
+	}
+	for _, bound := range []struct {
+		value string
+		op    cnosql.Token
+	}{{start, cnosql.GTE}, {end, cnosql.LT}} {
+		if bound.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, bound.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time: %s", err)
+		}
+		expr := &cnosql.BinaryExpr{
+			Op:  bound.op,
+			LHS: &cnosql.VarRef{Val: "time"},
+			RHS: &cnosql.TimeLiteral{Val: t.UTC()},
+		}
+		if cond == nil {
+			cond = expr
+		} else {
+			cond = &cnosql.BinaryExpr{Op: cnosql.AND, LHS: cond, RHS: expr}
+		}
+	}
+
+	return &cnosql.SelectStatement{
+		Fields:     []*cnosql.Field{{Expr: &cnosql.Wildcard{}}},
+		Sources:    []cnosql.Source{src},
+		Condition:  cond,
+		Dimensions: []*cnosql.Dimension{{Expr: &cnosql.Wildcard{}}},
+	}, nil
+}
+
+// countingWriter counts the bytes written into an underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// exportWriter writes the results of the query of an export. The rows of a
+// series are ordered by time, the first column being the time and the other
+// ones the fields.
+type exportWriter interface {
+	WriteResult(res *query.Result) error
+	Close() error
+}
+
+// lineProtocolExporter writes the points in line protocol.
+type lineProtocolExporter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (e *lineProtocolExporter) WriteResult(res *query.Result) error {
+	for _, row := range res.Series {
+		key := models.MakeKey([]byte(row.Name), models.NewTags(row.Tags))
+		for _, values := range row.Values {
+			e.buf = append(e.buf[:0], key...)
+			e.buf = append(e.buf, ' ')
+
+			n := 0
+			for i := 1; i < len(values); i++ {
+				if values[i] == nil {
+					continue
+				}
+				if n > 0 {
+					e.buf = append(e.buf, ',')
+				}
+				e.buf = append(e.buf, escape.String(row.Columns[i])...)
+				e.buf = append(e.buf, '=')
+				e.buf = appendFieldValue(e.buf, values[i])
+				n++
+			}
+			if n == 0 {
+				continue
+			}
+
+			if t, ok := values[0].(time.Time); ok {
+				e.buf = append(e.buf, ' ')
+				e.buf = strconv.AppendInt(e.buf, t.UnixNano(), 10)
+			}
+			e.buf = append(e.buf, '\n')
+			if _, err := e.w.Write(e.buf); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *lineProtocolExporter) Close() error {
+	return nil
+}
+
+// appendFieldValue appends the line protocol representation of a field
+// value to buf.
+func appendFieldValue(buf []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case float64:
+		return strconv.AppendFloat(buf, v, 'g', -1, 64)
+	case int64:
+		return append(strconv.AppendInt(buf, v, 10), 'i')
+	case uint64:
+		return append(strconv.AppendUint(buf, v, 10), 'u')
+	case bool:
+		return strconv.AppendBool(buf, v)
+	case string:
+		buf = append(buf, '"')
+		buf = append(buf, models.EscapeStringField(v)...)
+		return append(buf, '"')
+	default:
+		return append(buf, fmt.Sprintf("%v", v)...)
+	}
+}
+
+// csvExporter writes the points in the CSV format of the query responses:
+// the name and the tags of the series, the time and the fields. The header
+// is repeated after a blank line when the columns change.
+type csvExporter struct {
+	w       *csv.Writer
+	columns []string
+	record  []string
+}
+
+func (e *csvExporter) WriteResult(res *query.Result) error {
+	for _, row := range res.Series {
+		if e.columns == nil || !stringsEqual(e.columns, row.Columns) {
+			if e.columns != nil {
+				e.w.Write(nil)
+			}
+			e.columns = row.Columns
+			e.record = append([]string{"name", "tags"}, row.Columns...)
+			if err := e.w.Write(e.record); err != nil {
+				return err
+			}
+		}
+
+		e.record[0] = row.Name
+		e.record[1] = ""
+		if len(row.Tags) > 0 {
+			e.record[1] = string(models.NewTags(row.Tags).HashKey()[1:])
+		}
+		for _, values := range row.Values {
+			for i, value := range values {
+				switch v := value.(type) {
+				case nil:
+					e.record[i+2] = ""
+				case time.Time:
+					e.record[i+2] = strconv.FormatInt(v.UnixNano(), 10)
+				case float64:
+					e.record[i+2] = strconv.FormatFloat(v, 'f', -1, 64)
+				default:
+					e.record[i+2] = fmt.Sprintf("%v", v)
+				}
+			}
+			if err := e.w.Write(e.record); err != nil {
+				return err
+			}
+		}
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// parquetExporter writes the points in a Parquet file. The columns are the
+// metric, the time, the tag keys and the field keys returned by the
+// statements preceding the one selecting the points. A field key with
+// different types, or named like a tag key, has a column for every type
+// named <key>_<type>.
+type parquetExporter struct {
+	w      io.Writer
+	stmtID int
+
+	tagKeys   map[string]struct{}
+	fieldKeys map[string]map[string]struct{}
+
+	pw      *parquet.Writer
+	tagCols map[string]int
+	fields  map[string]map[string]int
+	record  []interface{}
+}
+
+func (e *parquetExporter) WriteResult(res *query.Result) error {
+	if res.StatementID < e.stmtID {
+		e.addKeys(res)
+		return nil
+	}
+
+	if e.pw == nil {
+		e.start()
+	}
+	for _, row := range res.Series {
+		for i := range e.record {
+			e.record[i] = nil
+		}
+		for k, v := range row.Tags {
+			// Series missing a tag key grouped by have an empty value.
+			if v == "" {
+				continue
+			}
+			i, ok := e.tagCols[k]
+			if !ok {
+				return fmt.Errorf("tag key %s of metric %s created during the export", k, row.Name)
+			}
+			e.record[i] = v
+		}
+
+		for _, values := range row.Values {
+			e.record[0] = row.Name
+			e.record[1] = values[0]
+			cols := make([]int, 0, len(values))
+			for j := 1; j < len(values); j++ {
+				if values[j] == nil {
+					continue
+				}
+				i, ok := e.fields[row.Columns[j]][fieldType(values[j])]
+				if !ok {
+					return fmt.Errorf("field key %s of metric %s created during the export", row.Columns[j], row.Name)
+				}
+				e.record[i] = values[j]
+				cols = append(cols, i)
+			}
+			if err := e.pw.Write(e.record); err != nil {
+				return err
+			}
+			for _, i := range cols {
+				e.record[i] = nil
+			}
+		}
+	}
+	return nil
+}
+
+// addKeys adds the tag and field keys of the results of SHOW TAG KEYS and
+// SHOW FIELD KEYS.
+func (e *parquetExporter) addKeys(res *query.Result) {
+	if e.tagKeys == nil {
+		e.tagKeys = make(map[string]struct{})
+		e.fieldKeys = make(map[string]map[string]struct{})
+	}
+
+	for _, row := range res.Series {
+		for _, values := range row.Values {
+			key, _ := values[0].(string)
+			if len(values) == 1 {
+				e.tagKeys[key] = struct{}{}
+				continue
+			}
+			typ, _ := values[1].(string)
+			if e.fieldKeys[key] == nil {
+				e.fieldKeys[key] = make(map[string]struct{})
+			}
+			e.fieldKeys[key][typ] = struct{}{}
+		}
+	}
+}
+
+// start creates the Parquet writer with the columns of the keys.
+func (e *parquetExporter) start() {
+	columns := []parquet.Column{
+		{Name: "metric", Type: parquet.String},
+		{Name: "time", Type: parquet.Timestamp},
+	}
+	names := map[string]struct{}{"metric": {}, "time": {}}
+
+	e.tagCols = make(map[string]int)
+	for _, k := range sortedKeys(e.tagKeys) {
+		name := k
+		if _, ok := names[name]; ok {
+			name = k + "_tag"
+		}
+		names[name] = struct{}{}
+		e.tagCols[k] = len(columns)
+		columns = append(columns, parquet.Column{Name: name, Type: parquet.String, Optional: true})
+	}
+
+	e.fields = make(map[string]map[string]int)
+	for _, k := range sortedKeys(e.fieldKeys) {
+		e.fields[k] = make(map[string]int)
+		types := sortedKeys(e.fieldKeys[k])
+		for _, typ := range types {
+			name := k
+			if _, ok := names[name]; ok || len(types) > 1 {
+				name = k + "_" + typ
+			}
+			names[name] = struct{}{}
+			e.fields[k][typ] = len(columns)
+			columns = append(columns, parquet.Column{Name: name, Type: parquetType(typ), Optional: true})
+		}
+	}
+
+	e.pw = parquet.NewWriter(e.w, columns)
+	e.record = make([]interface{}, len(columns))
+}
+
+func (e *parquetExporter) Close() error {
+	if e.pw == nil {
+		e.start()
+	}
+	return e.pw.Close()
+}
+
+// fieldType returns the type of a field value, as named by SHOW FIELD KEYS.
+func fieldType(v interface{}) string {
+	switch v.(type) {
+	case float64:
+		return "float"
+	case int64:
+		return "integer"
+	case uint64:
+		return "unsigned"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	return ""
+}
+
+// parquetType returns the type of the column of a field type.
+func parquetType(typ string) parquet.Type {
+	switch typ {
+	case "integer":
+		return parquet.Int64
+	case "unsigned":
+		return parquet.Uint64
+	case "string":
+		return parquet.String
+	case "boolean":
+		return parquet.Boolean
+	default:
+		return parquet.Double
+	}
+}
+
+// sortedKeys returns the sorted keys of a set.
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]struct{}:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]map[string]struct{}:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
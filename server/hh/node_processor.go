@@ -31,7 +31,7 @@ type NodeProcessor struct {
 	wg   sync.WaitGroup
 	done chan struct{}
 
-	queue  *queue
+	queue  *Queue
 	meta   metaClient
 	writer shardWriter
 
@@ -79,7 +79,7 @@ func (n *NodeProcessor) Open() error {
 	}
 
 	// Create the queue of hinted-handoff data.
-	queue, err := newQueue(n.dir, n.MaxSize)
+	queue, err := NewQueue(n.dir, n.MaxSize)
 	if err != nil {
 		return err
 	}
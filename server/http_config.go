@@ -27,6 +27,12 @@ const (
 
 	// DefaultEnqueuedWriteTimeout is the maximum time a write request can wait to be processed.
 	DefaultEnqueuedWriteTimeout = 30 * time.Second
+
+	// DefaultJWTUsernameClaim is the default claim of a bearer token holding the username.
+	DefaultJWTUsernameClaim = "username"
+
+	// DefaultJWKSRefreshInterval is the default interval at which the JWKS is reloaded.
+	DefaultJWKSRefreshInterval = 15 * time.Minute
 )
 
 type HTTPConfig struct {
@@ -38,12 +44,21 @@ type HTTPConfig struct {
 	WriteTracing            bool           `toml:"write-tracing"`
 	PprofEnabled            bool           `toml:"pprof-enabled"`
 	DebugPprofEnabled       bool           `toml:"debug-pprof-enabled"`
+	MetricsEnabled          bool           `toml:"metrics-enabled"`
+	MetricsAuthEnabled      bool           `toml:"metrics-auth-enabled"`
 	HTTPSEnabled            bool           `toml:"https-enabled"`
 	HTTPSCertificate        string         `toml:"https-certificate"`
 	HTTPSPrivateKey         string         `toml:"https-private-key"`
 	MaxRowLimit             int            `toml:"max-row-limit"`
 	MaxConnectionLimit      int            `toml:"max-connection-limit"`
 	SharedSecret            string         `toml:"shared-secret"`
+	JWTPublicKeys           []string       `toml:"jwt-public-keys"`
+	JWKS                    string         `toml:"jwks"`
+	JWKSRefreshInterval     time.Duration  `toml:"jwks-refresh-interval"`
+	JWTAudience             string         `toml:"jwt-audience"`
+	JWTIssuer               string         `toml:"jwt-issuer"`
+	JWTUsernameClaim        string         `toml:"jwt-username-claim"`
+	JWTGroupsClaim          string         `toml:"jwt-groups-claim"`
 	Realm                   string         `toml:"realm"`
 	UnixSocketEnabled       bool           `toml:"unix-socket-enabled"`
 	UnixSocketGroup         *toml.Group    `toml:"unix-socket-group"`
@@ -65,6 +80,8 @@ func NewHTTPConfig() HTTPConfig {
 		LogEnabled:            true,
 		PprofEnabled:          true,
 		DebugPprofEnabled:     false,
+		MetricsEnabled:        true,
+		MetricsAuthEnabled:    true,
 		HTTPSEnabled:          false,
 		HTTPSCertificate:      "/etc/ssl/cnosdb.pem",
 		MaxRowLimit:           0,
@@ -74,6 +91,8 @@ func NewHTTPConfig() HTTPConfig {
 		BindSocket:            DefaultBindSocket,
 		MaxBodySize:           DefaultMaxBodySize,
 		EnqueuedWriteTimeout:  DefaultEnqueuedWriteTimeout,
+		JWKSRefreshInterval:   DefaultJWKSRefreshInterval,
+		JWTUsernameClaim:      DefaultJWTUsernameClaim,
 	}
 }
 
@@ -19,12 +19,12 @@ import (
 	"github.com/cnosdatabase/cnosdb/monitor"
 	"github.com/cnosdatabase/cnosdb/pkg/logger"
 	"github.com/cnosdatabase/cnosdb/pkg/uuid"
+	"github.com/cnosdatabase/cnosdb/server/coordinator"
 	"github.com/cnosdatabase/cnosql"
 	"github.com/cnosdatabase/common/monitor/diagnostics"
 	"github.com/cnosdatabase/db/models"
 	"github.com/cnosdatabase/db/query"
 	"github.com/cnosdatabase/db/tsdb"
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
@@ -85,6 +85,8 @@ const (
 	statRecoveredPanics              = "recoveredPanics"      // Number of panics recovered by HTTP Handler.
 	statPromWriteRequest             = "promWriteReq"         // Number of write requests to the prometheus endpoint.
 	statPromReadRequest              = "promReadReq"          // Number of read requests to the prometheus endpoint.
+	statQuotaRejected                = "quotaRejected"        // Number of requests rejected by a quota.
+	statExportRequest                = "exportReq"            // Number of export requests served.
 )
 
 // 如果环境变量 CNOSDB_PANIC_CRASH 值已设置，并且为 true
@@ -151,8 +153,13 @@ type Handler struct {
 		WritePoints(database, timeToLive string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error
 	}
 
+	Quotas interface {
+		WriteBytes(database, user string, n int) error
+	}
+
 	requestTracker *RequestTracker
 	writeThrottler *Throttler
+	tokens         *tokenVerifier
 
 	logger *zap.Logger
 }
@@ -164,6 +171,7 @@ func NewHandler(conf *HTTPConfig) *Handler {
 		router:         mux.NewRouter(),
 		stats:          &Statistics{},
 		requestTracker: NewRequestTracker(),
+		tokens:         newTokenVerifier(conf),
 	}
 
 	h.writeThrottler = NewThrottler(conf.MaxConcurrentWriteLimit, conf.MaxEnqueuedWriteLimit)
@@ -194,13 +202,44 @@ func NewHandler(conf *HTTPConfig) *Handler {
 			"write", http.MethodPost, "/write", true, true,
 			h.serveWrite,
 		},
+		{
+			"prometheus-write", http.MethodPost, "/api/v1/prom/write", false, true,
+			h.servePromWrite,
+		},
+		{
+			"prometheus-read", http.MethodPost, "/api/v1/prom/read", false, true,
+			h.servePromRead,
+		},
+		{
+			"export", http.MethodGet, "/export", false, true,
+			h.serveExport,
+		},
 	}...)
 
+	if conf.MetricsEnabled {
+		// Admin users may scrape the metrics if auth-enabled is set, unless
+		// metrics-auth-enabled is disabled for the scrapers without
+		// credentials.
+		var serveMetrics interface{} = h.serveMetrics
+		if conf.MetricsAuthEnabled {
+			serveMetrics = h.serveAuthenticatedMetrics
+		}
+		h.AddRoutes(route{
+			"metrics", http.MethodGet, "/metrics", true, false,
+			serveMetrics,
+		})
+	}
+
 	return h
 }
 
-func (h *Handler) Open() {
-
+func (h *Handler) Open() error {
+	if h.config.AuthEnabled {
+		if err := h.tokens.Open(); err != nil {
+			return fmt.Errorf("open token verifier: %s", err)
+		}
+	}
+	return nil
 }
 
 // 响应 HTTP 请求
@@ -331,12 +370,13 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 	async := r.FormValue("async") == "true"
 
 	opts := query.ExecutionOptions{
-		Database:   db,
-		TimeToLive: r.FormValue("ttl"),
-		ChunkSize:  chunkSize,
-		ReadOnly:   r.Method == "GET",
-		NodeID:     nodeID,
-		Authorizer: fineAuthorizer,
+		Database:     db,
+		TimeToLive:   r.FormValue("ttl"),
+		ChunkSize:    chunkSize,
+		ReadOnly:     r.Method == "GET",
+		NodeID:       nodeID,
+		AllowPartial: r.FormValue("allow_partial") == "true",
+		Authorizer:   fineAuthorizer,
 	}
 
 	if h.config.AuthEnabled {
@@ -345,6 +385,9 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 			auth: h.QueryAuthorizer,
 			user: user,
 		}
+		if user != nil {
+			opts.UserID = user.ID()
+		}
 	} else {
 		opts.CoarseAuthorizer = query.OpenCoarseAuthorizer
 	}
@@ -387,6 +430,16 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 		return
 	}
 
+	// A query rejected by a quota is answered with a 429 before the header is
+	// written, the other results are sent once it is.
+	first, more := <-results
+	if more && first != nil && isQuotaExceeded(first.Err) {
+		h.writeQuotaExceeded(rw, first.Err)
+		for range results {
+		}
+		return
+	}
+
 	// if we're not chunking, this will be the in memory buffer for all results before sending to client
 	resp := Response{Results: make([]*query.Result, 0)}
 
@@ -400,7 +453,7 @@ func (h *Handler) serveQuery(w http.ResponseWriter, r *http.Request, user meta.U
 
 	// pull all results from the channel
 	rows := 0
-	for r := range results {
+	for r := first; more; r, more = <-results {
 		// Ignore nil results.
 		if r == nil {
 			continue
@@ -564,28 +617,10 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 	database := r.URL.Query().Get("db")
 	timeToLive := r.URL.Query().Get("ttl")
 
-	if database == "" {
-		writeError(w, "database is required")
-		return
-	}
-
-	if di := h.metaClient.Database(database); di == nil {
-		writeErrorWithCode(w, fmt.Sprintf("database not found: %q", database), http.StatusNotFound)
+	if !h.authorizeWrite(w, database, user) {
 		return
 	}
 
-	if h.config.AuthEnabled {
-		if user == nil {
-			writeErrorWithCode(w, fmt.Sprintf("user is required to write to database %q", database), http.StatusForbidden)
-			return
-		}
-
-		if err := h.WriteAuthorizer.AuthorizeWrite(user.ID(), database); err != nil {
-			writeErrorWithCode(w, fmt.Sprintf("%q user is not authorized to write to database %q", user.ID(), database), http.StatusForbidden)
-			return
-		}
-	}
-
 	body := r.Body
 	if h.config.MaxBodySize > 0 {
 		body = truncateReader(body, int64(h.config.MaxBodySize))
@@ -630,6 +665,17 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 	}
 	atomic.AddInt64(&h.stats.WriteRequestBytesReceived, int64(buf.Len()))
 
+	if h.Quotas != nil {
+		var username string
+		if user != nil {
+			username = user.ID()
+		}
+		if err := h.Quotas.WriteBytes(database, username, buf.Len()); err != nil {
+			h.writeQuotaExceeded(w, err)
+			return
+		}
+	}
+
 	if h.config.WriteTracing {
 		h.logger.Info("Write body received by Handler", zap.ByteString("body", buf.Bytes()))
 	}
@@ -645,6 +691,51 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 		return
 	}
 
+	if !h.writePoints(w, r, database, timeToLive, user, points) {
+		return
+	} else if parseError != nil {
+		// We wrote some of the points
+		atomic.AddInt64(&h.stats.PointsWrittenOK, int64(len(points)))
+		// The other points failed to parse which means the client sent invalid line protocol.  We return a 400
+		// response code as well as the lines that failed to parse.
+		writeError(w, tsdb.PartialWriteError{Reason: parseError.Error()}.Error())
+		return
+	}
+
+	atomic.AddInt64(&h.stats.PointsWrittenOK, int64(len(points)))
+	writeHeader(w, http.StatusNoContent)
+}
+
+// authorizeWrite checks that the database exists and that the user may
+// write to it. It writes the error response and returns false otherwise.
+func (h *Handler) authorizeWrite(w http.ResponseWriter, database string, user meta.User) bool {
+	if database == "" {
+		writeError(w, "database is required")
+		return false
+	}
+
+	if di := h.metaClient.Database(database); di == nil {
+		writeErrorWithCode(w, fmt.Sprintf("database not found: %q", database), http.StatusNotFound)
+		return false
+	}
+
+	if h.config.AuthEnabled {
+		if user == nil {
+			writeErrorWithCode(w, fmt.Sprintf("user is required to write to database %q", database), http.StatusForbidden)
+			return false
+		}
+
+		if err := h.WriteAuthorizer.AuthorizeWrite(user.ID(), database); err != nil {
+			writeErrorWithCode(w, fmt.Sprintf("%q user is not authorized to write to database %q", user.ID(), database), http.StatusForbidden)
+			return false
+		}
+	}
+	return true
+}
+
+// writePoints writes the points with the consistency level of the request.
+// It writes the error response and returns false if the write failed.
+func (h *Handler) writePoints(w http.ResponseWriter, r *http.Request, database, timeToLive string, user meta.User, points []models.Point) bool {
 	// Determine required consistency level.
 	level := r.URL.Query().Get("consistency")
 	consistency := models.ConsistencyLevelOne
@@ -653,39 +744,48 @@ func (h *Handler) serveWrite(w http.ResponseWriter, r *http.Request, user meta.U
 		consistency, err = models.ParseConsistencyLevel(level)
 		if err != nil {
 			writeError(w, err.Error())
-			return
+			return false
 		}
 	}
 
 	// Write points.
-	if err := h.PointsWriter.WritePoints(database, timeToLive, consistency, user, points); cnosdb.IsClientError(err) {
+	if err := h.PointsWriter.WritePoints(database, timeToLive, consistency, user, points); isQuotaExceeded(err) {
+		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
+		h.writeQuotaExceeded(w, err)
+		return false
+	} else if cnosdb.IsClientError(err) {
 		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
 		writeError(w, err.Error())
-		return
+		return false
 	} else if cnosdb.IsAuthorizationError(err) {
 		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
 		writeErrorWithCode(w, err.Error(), http.StatusForbidden)
-		return
+		return false
 	} else if werr, ok := err.(tsdb.PartialWriteError); ok {
 		atomic.AddInt64(&h.stats.PointsWrittenOK, int64(len(points)-werr.Dropped))
 		atomic.AddInt64(&h.stats.PointsWrittenDropped, int64(werr.Dropped))
 		writeError(w, werr.Error())
-		return
+		return false
 	} else if err != nil {
 		atomic.AddInt64(&h.stats.PointsWrittenFail, int64(len(points)))
 		writeErrorWithCode(w, err.Error(), http.StatusInternalServerError)
-		return
-	} else if parseError != nil {
-		// We wrote some of the points
-		atomic.AddInt64(&h.stats.PointsWrittenOK, int64(len(points)))
-		// The other points failed to parse which means the client sent invalid line protocol.  We return a 400
-		// response code as well as the lines that failed to parse.
-		writeError(w, tsdb.PartialWriteError{Reason: parseError.Error()}.Error())
-		return
+		return false
 	}
+	return true
+}
 
-	atomic.AddInt64(&h.stats.PointsWrittenOK, int64(len(points)))
-	writeHeader(w, http.StatusNoContent)
+// isQuotaExceeded returns true if err is a rejection by a quota.
+func isQuotaExceeded(err error) bool {
+	_, ok := err.(coordinator.QuotaExceededError)
+	return ok
+}
+
+// writeQuotaExceeded responds to a request rejected by a quota with a 429,
+// asking the client to retry once the write rates have recovered.
+func (h *Handler) writeQuotaExceeded(w http.ResponseWriter, err error) {
+	atomic.AddInt64(&h.stats.QuotaRejections, 1)
+	w.Header().Set("Retry-After", "1")
+	writeErrorWithCode(w, err.Error(), http.StatusTooManyRequests)
 }
 
 // Statistics maintains statistics for the httpd service.
@@ -713,6 +813,8 @@ type Statistics struct {
 	RecoveredPanics              int64
 	PromWriteRequests            int64
 	PromReadRequests             int64
+	QuotaRejections              int64
+	ExportRequests               int64
 }
 
 // Statistics returns statistics for periodic monitoring.
@@ -743,6 +845,8 @@ func (h *Handler) Statistics(tags map[string]string) []models.Statistic {
 			statRecoveredPanics:              atomic.LoadInt64(&h.stats.RecoveredPanics),
 			statPromWriteRequest:             atomic.LoadInt64(&h.stats.PromWriteRequests),
 			statPromReadRequest:              atomic.LoadInt64(&h.stats.PromReadRequests),
+			statQuotaRejected:                atomic.LoadInt64(&h.stats.QuotaRejections),
+			statExportRequest:                atomic.LoadInt64(&h.stats.ExportRequests),
 		},
 	}}
 }
@@ -752,7 +856,10 @@ func (h *Handler) AddRoutes(routes ...route) {
 	for _, r := range routes {
 		var handler http.Handler
 		if hf, ok := r.HandlerFunc.(func(http.ResponseWriter, *http.Request, meta.User)); ok {
-			handler = WrapWithAuthenticate(hf, h.config, h.metaClient)
+			// The meta client is set after the routes are added.
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				WrapWithAuthenticate(hf, h.config, h.metaClient, h.tokens).ServeHTTP(w, r)
+			})
 		}
 		if hf, ok := r.HandlerFunc.(func(http.ResponseWriter, *http.Request)); ok {
 			handler = http.HandlerFunc(hf)
@@ -990,7 +1097,9 @@ type serveAuthenticateFunc func(http.ResponseWriter, *http.Request, meta.User)
 //
 // There is one exception: if there are no users in the system, authentication is not required. This
 // is to facilitate bootstrapping of a system with authentication enabled.
-func WrapWithAuthenticate(inner serveAuthenticateFunc, conf *HTTPConfig, metaCli meta.MetaClient) http.Handler {
+//
+// Bearer tokens are verified by tokens, with the shared secret or the configured public keys.
+func WrapWithAuthenticate(inner serveAuthenticateFunc, conf *HTTPConfig, metaCli meta.MetaClient, tokens *tokenVerifier) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Return early if we are not authenticating
 		if !conf.AuthEnabled {
@@ -1022,29 +1131,11 @@ func WrapWithAuthenticate(inner serveAuthenticateFunc, conf *HTTPConfig, metaCli
 					return
 				}
 			case BearerAuthentication:
-				keyLookupFn := func(token *jwt.Token) (interface{}, error) {
-					// Check for expected signing method.
-					if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-						return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-					}
-					return []byte(conf.SharedSecret), nil
-				}
-
 				// Parse and validate the token.
-				token, err := jwt.Parse(creds.Token, keyLookupFn)
+				claims, err := tokens.Parse(creds.Token)
 				if err != nil {
 					writeErrorUnauthorized(w, err.Error(), conf.Realm)
 					return
-				} else if !token.Valid {
-					writeErrorUnauthorized(w, "invalid token", conf.Realm)
-					return
-				}
-
-				claims, ok := token.Claims.(jwt.MapClaims)
-				if !ok {
-					writeErrorUnauthorized(w, "problem authenticating token", conf.Realm)
-					logger.BgLogger().Info("Could not assert JWT token claims as jwt.MapClaims")
-					return
 				}
 
 				// Make sure an expiration was set on the token.
@@ -1054,7 +1145,11 @@ func WrapWithAuthenticate(inner serveAuthenticateFunc, conf *HTTPConfig, metaCli
 				}
 
 				// Get the username from the token.
-				username, ok := claims["username"].(string)
+				usernameClaim := conf.JWTUsernameClaim
+				if usernameClaim == "" {
+					usernameClaim = DefaultJWTUsernameClaim
+				}
+				username, ok := claims[usernameClaim].(string)
 				if !ok {
 					writeErrorUnauthorized(w, "username in token must be a string", conf.Realm)
 					return
@@ -1063,8 +1158,14 @@ func WrapWithAuthenticate(inner serveAuthenticateFunc, conf *HTTPConfig, metaCli
 					return
 				}
 
-				// Lookup user in the metastore.
-				if user, err = metaCli.User(username); err != nil {
+				// Lookup user in the metastore, as a member of the roles
+				// named after the groups of the token.
+				if conf.JWTGroupsClaim != "" {
+					user, err = metaCli.UserWithRoles(username, claimStrings(claims[conf.JWTGroupsClaim]))
+				} else {
+					user, err = metaCli.User(username)
+				}
+				if err != nil {
 					writeErrorUnauthorized(w, err.Error(), conf.Realm)
 					return
 				} else if user == nil {
@@ -1073,6 +1174,7 @@ func WrapWithAuthenticate(inner serveAuthenticateFunc, conf *HTTPConfig, metaCli
 				}
 			default:
 				writeErrorUnauthorized(w, "unsupported authentication", conf.Realm)
+				return
 			}
 
 		}
@@ -0,0 +1,402 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/pkg/logger"
+	"github.com/dgrijalva/jwt-go"
+	"go.uber.org/zap"
+)
+
+const (
+	// jwksMinReloadInterval limits how often a token signed with an unknown
+	// key reloads the JWKS.
+	jwksMinReloadInterval = 30 * time.Second
+
+	// maxJWKSSize is the maximum size of a JWKS, in bytes.
+	maxJWKSSize = 1 << 20
+)
+
+// publicKey is a key verifying the signatures of bearer tokens.
+type publicKey struct {
+	id  string // empty if the key has no ID
+	alg string // empty if the key may be used with any algorithm
+	key interface{}
+}
+
+// tokenVerifier verifies the signatures and the claims of bearer tokens. The
+// tokens are signed either with the shared secret (HMAC), or with the private
+// key of one of the configured public keys (RSA or ECDSA).
+type tokenVerifier struct {
+	conf   *HTTPConfig
+	client *http.Client
+
+	mu        sync.RWMutex
+	keys      []publicKey // keys of the PEM files
+	jwks      []publicKey // keys of the JWKS
+	checkedAt time.Time   // last time the JWKS was loaded
+
+	reloadMu sync.Mutex
+}
+
+// newTokenVerifier returns a new instance of tokenVerifier. Open must be
+// called before using it.
+func newTokenVerifier(conf *HTTPConfig) *tokenVerifier {
+	return &tokenVerifier{
+		conf:   conf,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Open loads the public keys and the JWKS.
+func (v *tokenVerifier) Open() error {
+	var keys []publicKey
+	for _, path := range v.conf.JWTPublicKeys {
+		buf, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		key, err := parsePublicKeyPEM(buf)
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err)
+		}
+		keys = append(keys, publicKey{key: key})
+	}
+
+	v.mu.Lock()
+	v.keys = keys
+	v.mu.Unlock()
+
+	if v.conf.JWKS == "" {
+		return nil
+	}
+
+	if err := v.loadJWKS(); err != nil {
+		// An unreachable JWKS URL must not prevent the node from starting,
+		// the keys are loaded again once a token needs them.
+		if !isJWKSURL(v.conf.JWKS) {
+			return fmt.Errorf("%s: %s", v.conf.JWKS, err)
+		}
+		logger.BgLogger().Warn("Failed to load JWKS", zap.String("jwks", v.conf.JWKS), zap.Error(err))
+	}
+	return nil
+}
+
+// Parse parses a bearer token, verifies its signature, its registered claims,
+// and the audience and issuer if configured.
+func (v *tokenVerifier) Parse(tokenString string) (jwt.MapClaims, error) {
+	unverified, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return nil, err
+	}
+
+	var token *jwt.Token
+	switch unverified.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if v.conf.SharedSecret == "" {
+			return nil, errors.New("tokens signed with a shared secret are not accepted")
+		}
+		token, err = jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+			return []byte(v.conf.SharedSecret), nil
+		})
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		kid, _ := unverified.Header["kid"].(string)
+		token, err = v.parseWithKeys(tokenString, unverified.Method.Alg(), v.publicKeys(kid))
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %v", unverified.Header["alg"])
+	}
+
+	if err != nil {
+		return nil, err
+	} else if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		logger.BgLogger().Info("Could not assert JWT token claims as jwt.MapClaims")
+		return nil, errors.New("problem authenticating token")
+	}
+
+	if v.conf.JWTIssuer != "" && !claims.VerifyIssuer(v.conf.JWTIssuer, true) {
+		return nil, errors.New("invalid token issuer")
+	} else if v.conf.JWTAudience != "" && !verifyAudience(claims, v.conf.JWTAudience) {
+		return nil, errors.New("invalid token audience")
+	}
+	return claims, nil
+}
+
+// parseWithKeys parses a token signed with alg, trying each of keys until one
+// verifies its signature.
+func (v *tokenVerifier) parseWithKeys(tokenString, alg string, keys []publicKey) (*jwt.Token, error) {
+	for _, k := range keys {
+		if k.alg != "" && k.alg != alg {
+			continue
+		}
+
+		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+			switch token.Method.(type) {
+			case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+				if _, ok := k.key.(*rsa.PublicKey); !ok {
+					return nil, errKeyMismatch
+				}
+			case *jwt.SigningMethodECDSA:
+				if _, ok := k.key.(*ecdsa.PublicKey); !ok {
+					return nil, errKeyMismatch
+				}
+			}
+			return k.key, nil
+		})
+		if ve, ok := err.(*jwt.ValidationError); ok && (ve.Inner == errKeyMismatch || ve.Errors&jwt.ValidationErrorSignatureInvalid != 0) {
+			// The token is not signed with this key.
+			continue
+		}
+		return token, err
+	}
+	return nil, errors.New("token is not signed by a known key")
+}
+
+// errKeyMismatch is returned when the type of a key does not match the signing method.
+var errKeyMismatch = errors.New("key does not match the signing method")
+
+// publicKeys returns the public keys which may have signed a token with the
+// key ID kid. The JWKS is reloaded when it is due, or when it has no key with
+// the ID because the signing keys have been rotated.
+func (v *tokenVerifier) publicKeys(kid string) []publicKey {
+	if v.conf.JWKS != "" && v.conf.JWKSRefreshInterval > 0 {
+		v.reloadJWKS(v.conf.JWKSRefreshInterval)
+	}
+
+	keys, found := v.findKeys(kid)
+	if !found && v.conf.JWKS != "" {
+		v.reloadJWKS(jwksMinReloadInterval)
+		keys, _ = v.findKeys(kid)
+	}
+	return keys
+}
+
+// findKeys returns the keys of the PEM files, and the keys of the JWKS with
+// the ID kid. It returns false if kid is set but no key has this ID.
+func (v *tokenVerifier) findKeys(kid string) ([]publicKey, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	keys := append([]publicKey{}, v.keys...)
+	found := kid == ""
+	for _, k := range v.jwks {
+		if kid == "" || k.id == "" || k.id == kid {
+			keys = append(keys, k)
+			found = found || k.id == kid
+		}
+	}
+	return keys, found
+}
+
+// reloadJWKS loads the JWKS again if it has not been loaded for age.
+func (v *tokenVerifier) reloadJWKS(age time.Duration) {
+	v.reloadMu.Lock()
+	defer v.reloadMu.Unlock()
+
+	v.mu.RLock()
+	checkedAt := v.checkedAt
+	v.mu.RUnlock()
+	if time.Since(checkedAt) < age {
+		return
+	}
+
+	if err := v.loadJWKS(); err != nil {
+		logger.BgLogger().Warn("Failed to reload JWKS, keeping the previous keys", zap.String("jwks", v.conf.JWKS), zap.Error(err))
+	}
+}
+
+// loadJWKS reads the JWKS from its URL or file and replaces the keys of the
+// previous JWKS.
+func (v *tokenVerifier) loadJWKS() error {
+	v.mu.Lock()
+	v.checkedAt = time.Now()
+	v.mu.Unlock()
+
+	buf, err := v.readJWKS()
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(buf, &set); err != nil {
+		return err
+	}
+
+	var keys []publicKey
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			logger.BgLogger().Info("Skipping JWKS key", zap.String("kid", jwk.Kid), zap.Error(err))
+			continue
+		}
+		keys = append(keys, publicKey{id: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+
+	v.mu.Lock()
+	v.jwks = keys
+	v.mu.Unlock()
+	return nil
+}
+
+// readJWKS returns the content of the JWKS.
+func (v *tokenVerifier) readJWKS() ([]byte, error) {
+	if !isJWKSURL(v.conf.JWKS) {
+		return ioutil.ReadFile(v.conf.JWKS)
+	}
+
+	resp, err := v.client.Get(v.conf.JWKS)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return ioutil.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+// isJWKSURL returns true if the JWKS is fetched over HTTP rather than read
+// from a file.
+func isJWKSURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// jsonWebKey is a public key of a JWKS, as defined by RFC 7517.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// Elliptic curve keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey returns the RSA or ECDSA public key of jwk.
+func (jwk jsonWebKey) publicKey() (interface{}, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBase64URLInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBase64URLInt(jwk.E)
+		if err != nil {
+			return nil, err
+		} else if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := decodeBase64URLInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBase64URLInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		} else if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+// decodeBase64URLInt decodes a big-endian integer encoded with base64url.
+func decodeBase64URLInt(s string) (*big.Int, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	} else if len(buf) == 0 {
+		return nil, errors.New("missing key parameter")
+	}
+	return new(big.Int).SetBytes(buf), nil
+}
+
+// parsePublicKeyPEM parses a PEM encoded RSA or ECDSA public key or certificate.
+func parsePublicKeyPEM(buf []byte) (interface{}, error) {
+	if key, err := jwt.ParseRSAPublicKeyFromPEM(buf); err == nil {
+		return key, nil
+	}
+	if key, err := jwt.ParseECPublicKeyFromPEM(buf); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("not a PEM encoded RSA or ECDSA public key")
+}
+
+// verifyAudience returns true if the audience of claims, a string or an
+// array of strings, contains aud.
+func verifyAudience(claims jwt.MapClaims, aud string) bool {
+	switch v := claims["aud"].(type) {
+	case string:
+		return v == aud
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && s == aud {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// claimStrings returns the strings of a claim holding a string or an array
+// of strings.
+func claimStrings(claim interface{}) []string {
+	switch v := claim.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		a := make([]string, 0, len(v))
+		for _, s := range v {
+			if s, ok := s.(string); ok {
+				a = append(a, s)
+			}
+		}
+		return a
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/cnosdb/prometheus"
+	"github.com/cnosdatabase/cnosdb/prometheus/remote"
+	"github.com/cnosdatabase/cnosql"
+	"github.com/cnosdatabase/db/models"
+	"github.com/cnosdatabase/db/query"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"go.uber.org/zap"
+)
+
+// servePromWrite receives data in the Prometheus remote write protocol and writes it to the database.
+func (h *Handler) servePromWrite(w http.ResponseWriter, r *http.Request, user meta.User) {
+	atomic.AddInt64(&h.stats.WriteRequests, 1)
+	atomic.AddInt64(&h.stats.ActiveWriteRequests, 1)
+	atomic.AddInt64(&h.stats.PromWriteRequests, 1)
+	defer func(start time.Time) {
+		atomic.AddInt64(&h.stats.ActiveWriteRequests, -1)
+		atomic.AddInt64(&h.stats.WriteRequestDuration, time.Since(start).Nanoseconds())
+	}(time.Now())
+	h.requestTracker.Add(r, user)
+
+	database := r.URL.Query().Get("db")
+	timeToLive := r.URL.Query().Get("ttl")
+
+	if !h.authorizeWrite(w, database, user) {
+		return
+	}
+
+	buf, ok := h.readPromRequest(w, r)
+	if !ok {
+		return
+	}
+	atomic.AddInt64(&h.stats.WriteRequestBytesReceived, int64(len(buf)))
+
+	var req remote.WriteRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		writeError(w, err.Error())
+		return
+	}
+
+	points, err := prometheus.WriteRequestToPoints(&req)
+	if err != nil && err != prometheus.ErrNaNDropped {
+		writeError(w, err.Error())
+		return
+	} else if err != nil && h.config.WriteTracing {
+		h.logger.Info("Prometheus write handler", zap.Error(err))
+	}
+
+	if !h.writePoints(w, r, database, timeToLive, user, points) {
+		return
+	}
+
+	atomic.AddInt64(&h.stats.PointsWrittenOK, int64(len(points)))
+	writeHeader(w, http.StatusNoContent)
+}
+
+// servePromRead answers a Prometheus remote read request.
+func (h *Handler) servePromRead(w http.ResponseWriter, r *http.Request, user meta.User) {
+	atomic.AddInt64(&h.stats.QueryRequests, 1)
+	atomic.AddInt64(&h.stats.PromReadRequests, 1)
+	defer func(start time.Time) {
+		atomic.AddInt64(&h.stats.QueryRequestDuration, time.Since(start).Nanoseconds())
+	}(time.Now())
+	h.requestTracker.Add(r, user)
+
+	db := r.FormValue("db")
+	ttl := r.FormValue("ttl")
+
+	buf, ok := h.readPromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req remote.ReadRequest
+	if err := proto.Unmarshal(buf, &req); err != nil {
+		writeError(w, err.Error())
+		return
+	}
+
+	stmt, err := prometheus.ReadRequestToCnosQLStatement(&req, db, ttl)
+	if err != nil {
+		writeError(w, err.Error())
+		return
+	}
+	q := &cnosql.Query{Statements: cnosql.Statements{stmt}}
+
+	// Check authorization.
+	var fineAuthorizer query.FineAuthorizer
+	if h.config.AuthEnabled {
+		if fineAuthorizer, err = h.QueryAuthorizer.AuthorizeQuery(user, q, db); err != nil {
+			writeErrorWithCode(w, "error authorizing query: "+err.Error(), http.StatusForbidden)
+			return
+		}
+	} else {
+		fineAuthorizer = query.OpenAuthorizer
+	}
+
+	opts := query.ExecutionOptions{
+		Database:   db,
+		TimeToLive: ttl,
+		ChunkSize:  DefaultChunkSize,
+		ReadOnly:   true,
+		Authorizer: fineAuthorizer,
+	}
+	if h.config.AuthEnabled {
+		opts.CoarseAuthorizer = &userQueryAuthorizer{
+			auth: h.QueryAuthorizer,
+			user: user,
+		}
+	} else {
+		opts.CoarseAuthorizer = query.OpenCoarseAuthorizer
+	}
+
+	// Abort the query if the request fails before all results were read.
+	closing := make(chan struct{})
+	defer close(closing)
+
+	// Merge the chunks of each series. The whole response is buffered, so
+	// the number of points is limited like a non-chunked query. A truncated
+	// response would look complete to Prometheus, so the read fails instead.
+	var rows []*models.Row
+	var points int
+	for res := range h.QueryExecutor.ExecuteQuery(q, opts, closing) {
+		if res.Err != nil {
+			writeErrorWithCode(w, res.Err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		for _, row := range res.Series {
+			points += len(row.Values)
+			if h.config.MaxRowLimit > 0 && points > h.config.MaxRowLimit {
+				writeError(w, fmt.Sprintf("remote read exceeds the max-row-limit of %d points", h.config.MaxRowLimit))
+				return
+			}
+
+			if n := len(rows); n > 0 && rows[n-1].SameSeries(row) {
+				rows[n-1].Values = append(rows[n-1].Values, row.Values...)
+				continue
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	result, err := prometheus.RowsToQueryResult(rows)
+	if err != nil {
+		writeErrorWithCode(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := proto.Marshal(&remote.ReadResponse{
+		Results: []*remote.QueryResult{result},
+	})
+	if err != nil {
+		writeErrorWithCode(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Header().Set("Content-Encoding", "snappy")
+
+	compressed := snappy.Encode(nil, data)
+	n, err := w.Write(compressed)
+	if err != nil {
+		h.logger.Info("Error writing Prometheus read response", zap.Error(err))
+		return
+	}
+	atomic.AddInt64(&h.stats.QueryRequestBytesTransmitted, int64(n))
+}
+
+// readPromRequest reads and decompresses the snappy encoded body of a
+// Prometheus remote storage request. It writes the error response and
+// returns false if the body can't be read.
+func (h *Handler) readPromRequest(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body := io.Reader(r.Body)
+	if h.config.MaxBodySize > 0 {
+		if r.ContentLength > int64(h.config.MaxBodySize) {
+			writeErrorWithCode(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+		body = truncateReader(body, int64(h.config.MaxBodySize))
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(body); err == errTruncated {
+		writeErrorWithCode(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return nil, false
+	} else if err != nil {
+		writeError(w, err.Error())
+		return nil, false
+	}
+
+	b, err := snappy.Decode(nil, buf.Bytes())
+	if err != nil {
+		writeError(w, fmt.Sprintf("error decoding snappy body: %s", err))
+		return nil, false
+	}
+	return b, true
+}
+
+// serveAuthenticatedMetrics serves the metrics to the admin users.
+func (h *Handler) serveAuthenticatedMetrics(w http.ResponseWriter, r *http.Request, user meta.User) {
+	if h.config.AuthEnabled && user != nil && !user.AuthorizeUnrestricted() {
+		writeErrorWithCode(w, fmt.Sprintf("%q user is not authorized to read the metrics", user.ID()), http.StatusForbidden)
+		return
+	}
+	h.serveMetrics(w, r)
+}
+
+// serveMetrics returns the statistics of the node in the Prometheus text exposition format.
+func (h *Handler) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.Monitor.Statistics(nil)
+	if err != nil {
+		writeErrorWithCode(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	a := make([]models.Statistic, 0, len(stats))
+	for _, s := range stats {
+		a = append(a, s.Statistic)
+	}
+
+	w.Header().Set("Content-Type", prometheus.ContentType)
+	if err := prometheus.WriteMetrics(w, a); err != nil {
+		h.logger.Info("Error writing metrics", zap.Error(err))
+	}
+}
@@ -18,7 +18,10 @@ import (
 	"github.com/cnosdatabase/cnosdb/pkg/logger"
 	"github.com/cnosdatabase/cnosdb/pkg/network"
 	"github.com/cnosdatabase/cnosdb/pkg/utils"
+	"github.com/cnosdatabase/cnosdb/server/ae"
+	"github.com/cnosdatabase/cnosdb/server/continuous_querier"
 	"github.com/cnosdatabase/cnosdb/server/coordinator"
+	"github.com/cnosdatabase/cnosdb/server/copier"
 	"github.com/cnosdatabase/cnosdb/server/hh"
 	"github.com/cnosdatabase/cnosdb/server/snapshotter"
 	"github.com/cnosdatabase/cnosdb/server/subscriber"
@@ -61,11 +64,15 @@ type Server struct {
 	queryExecutor *query.Executor
 	pointsWriter  *coordinator.PointsWriter
 	shardWriter   *coordinator.ShardWriter
+	readHealth    *coordinator.NodeHealth
+	quotas        *coordinator.Quotas
 	hintedHandoff *hh.Service
 	subscriber    *subscriber.Service
 
 	coordinatorService *coordinator.Service
 	snapshotterService *snapshotter.Service
+	antiEntropy        *ae.Service
+	shardCopier        *copier.Service
 
 	services []interface {
 		WithLogger(log *zap.Logger)
@@ -136,10 +143,22 @@ func (s *Server) Close() {
 		_ = s.pointsWriter.Close()
 	}
 
+	if s.subscriber != nil {
+		_ = s.subscriber.Close()
+	}
+
 	if s.queryExecutor != nil {
 		_ = s.queryExecutor.Close()
 	}
 
+	if s.antiEntropy != nil {
+		_ = s.antiEntropy.Close()
+	}
+
+	if s.shardCopier != nil {
+		_ = s.shardCopier.Close()
+	}
+
 	// Close the TSDBStore, no more reads or writes at this point
 	if s.tsdbStore != nil {
 		_ = s.tsdbStore.Close()
@@ -192,6 +211,9 @@ func (s *Server) initTSDBStore() error {
 	s.hintedHandoff = hh.NewService(s.Config.HintedHandoff, s.shardWriter, s.metaClient)
 	s.hintedHandoff.Monitor = s.monitor
 
+	s.quotas = coordinator.NewQuotas()
+	s.quotas.MetaClient = s.metaClient
+
 	s.pointsWriter = coordinator.NewPointsWriter()
 	s.pointsWriter.WriteTimeout = time.Duration(s.Config.Coordinator.WriteTimeout)
 	s.pointsWriter.MetaClient = s.metaClient
@@ -199,27 +221,52 @@ func (s *Server) initTSDBStore() error {
 	s.pointsWriter.TSDBStore = s.tsdbStore
 	s.pointsWriter.ShardWriter = s.shardWriter
 	s.pointsWriter.Node = s.Node
+	s.pointsWriter.Quotas = s.quotas
 
 	s.subscriber = subscriber.NewService(s.Config.Subscriber)
 	s.subscriber.MetaClient = s.metaClient
 
-	s.queryExecutor = query.NewExecutor()
-	s.queryExecutor.StatementExecutor = &coordinator.StatementExecutor{
-		MetaClient:  s.metaClient,
-		TaskManager: s.queryExecutor.TaskManager,
-		TSDBStore:   s.tsdbStore,
-		ShardMapper: &coordinator.LocalShardMapper{
-			MetaClient: s.metaClient,
-			TSDBStore: coordinator.LocalTSDBStore{
-				Store: s.tsdbStore,
-			},
+	s.readHealth = coordinator.NewNodeHealth(time.Duration(s.Config.Coordinator.NodeFailureBackoff))
+
+	shardMapper := &coordinator.ClusterShardMapper{
+		Node:       s.Node,
+		MetaClient: s.metaClient,
+		TSDBStore: coordinator.LocalTSDBStore{
+			Store: s.tsdbStore,
 		},
+		Health:             s.readHealth,
+		Timeout:            time.Duration(s.Config.Coordinator.ShardMapperTimeout),
+		ForceRemoteMapping: s.Config.Coordinator.ForceRemoteShardMapping,
+	}
+
+	metaExecutor := coordinator.NewMetaExecutor()
+	metaExecutor.Node = s.Node
+	metaExecutor.MetaClient = s.metaClient
+
+	s.queryExecutor = query.NewExecutor()
+
+	queryManager := coordinator.NewQueryManager()
+	queryManager.Node = s.Node
+	queryManager.TaskManager = s.queryExecutor.TaskManager
+	queryManager.MetaClient = s.metaClient
+	queryManager.Timeout = time.Duration(s.Config.Coordinator.ShardMapperTimeout)
+
+	statementExecutor := &coordinator.StatementExecutor{
+		MetaClient:        s.metaClient,
+		TaskManager:       s.queryExecutor.TaskManager,
+		TSDBStore:         s.tsdbStore,
+		ShardMapper:       shardMapper,
+		SeriesKeysReader:  shardMapper,
+		MetaExecutor:      metaExecutor,
+		QueryManager:      queryManager,
 		Monitor:           s.monitor,
 		PointsWriter:      s.pointsWriter,
+		Quotas:            s.quotas,
 		MaxSelectPointN:   s.Config.Coordinator.MaxSelectPointN,
 		MaxSelectSeriesN:  s.Config.Coordinator.MaxSelectSeriesN,
 		MaxSelectBucketsN: s.Config.Coordinator.MaxSelectBucketsN,
 	}
+	s.queryExecutor.StatementExecutor = statementExecutor
 	s.queryExecutor.TaskManager.QueryTimeout = time.Duration(s.Config.Coordinator.QueryTimeout)
 	s.queryExecutor.TaskManager.LogQueriesAfter = time.Duration(s.Config.Coordinator.LogQueriesAfter)
 	s.queryExecutor.TaskManager.MaxConcurrentQueries = s.Config.Coordinator.MaxConcurrentQueries
@@ -227,10 +274,34 @@ func (s *Server) initTSDBStore() error {
 	s.coordinatorService = coordinator.NewService(s.Config.Coordinator)
 	s.coordinatorService.TSDBStore = s.tsdbStore
 	s.coordinatorService.MetaClient = s.metaClient
+	s.coordinatorService.TaskManager = s.queryExecutor.TaskManager
 
 	s.snapshotterService = snapshotter.NewService()
 	s.snapshotterService.TSDBStore = s.tsdbStore
 	s.snapshotterService.MetaClient = s.metaClient
+	s.snapshotterService.WALArchiveDir = s.Config.Data.WALArchiveDir
+
+	s.antiEntropy = ae.NewService(s.Config.AntiEntropy)
+	s.antiEntropy.Node = s.Node
+	s.antiEntropy.MetaClient = s.metaClient
+	s.antiEntropy.TSDBStore = s.tsdbStore
+	s.antiEntropy.WithLogger(s.logger)
+
+	if s.Config.ContinuousQuery.Enabled {
+		cq := continuous_querier.NewService(s.Config.ContinuousQuery)
+		cq.MetaClient = s.metaClient
+		cq.QueryExecutor = s.queryExecutor
+		cq.Monitor = s.monitor
+		cq.WithLogger(s.logger)
+		s.services = append(s.services, cq)
+		statementExecutor.ContinuousQuerier = cq
+	}
+
+	s.shardCopier = copier.NewService()
+	s.shardCopier.Node = s.Node
+	s.shardCopier.MetaClient = s.metaClient
+	s.shardCopier.TSDBStore = s.tsdbStore
+	s.shardCopier.WithLogger(s.logger)
 
 	// Open TSDB store.
 	if err := s.tsdbStore.Open(); err != nil {
@@ -251,6 +322,10 @@ func (s *Server) initTSDBStore() error {
 	if err := s.subscriber.Open(); err != nil {
 		return fmt.Errorf("open subscriber: %s", err)
 	}
+	if s.Config.Subscriber.Enabled {
+		s.pointsWriter.AddWriteSubscriber(s.subscriber.Points())
+		s.pointsWriter.DurableSubscriber = s.subscriber
+	}
 
 	for _, service := range s.services {
 		if err := service.Open(); err != nil {
@@ -279,8 +354,11 @@ func (s *Server) initHTTPServer() error {
 	h.QueryExecutor = s.queryExecutor
 	h.Monitor = s.monitor
 	h.PointsWriter = s.pointsWriter
+	h.Quotas = s.quotas
 	h.logger = logger.BgLogger()
-	h.Open()
+	if err := h.Open(); err != nil {
+		return err
+	}
 
 	s.httpHandler = h
 
@@ -310,6 +388,16 @@ func (s *Server) openServices() error {
 		return fmt.Errorf("open snapshotter service: %s", err)
 	}
 
+	s.antiEntropy.Listener = network.ListenString(s.tcpMux, ae.MuxHeader)
+	if err := s.antiEntropy.Open(); err != nil {
+		return fmt.Errorf("open anti-entropy service: %s", err)
+	}
+
+	s.shardCopier.Listener = network.ListenString(s.tcpMux, copier.MuxHeader)
+	if err := s.shardCopier.Open(); err != nil {
+		return fmt.Errorf("open shard copier service: %s", err)
+	}
+
 	return nil
 }
 
@@ -319,7 +407,11 @@ func (s *Server) initMetaClient() error {
 		metaCli = meta.NewClient(s.Config.Meta)
 	} else {
 		s.logger.Info("waiting to be added to cluster")
-		metaCli = meta.NewRemoteClient()
+		remoteCli := meta.NewRemoteClient()
+		if err := s.Config.Meta.ConfigureClient(remoteCli); err != nil {
+			return err
+		}
+		metaCli = remoteCli
 		for {
 			if len(s.Node.Peers) == 0 {
 				time.Sleep(time.Second)
@@ -422,6 +514,10 @@ func (s *Server) startNodeServer() {
 func (s *Server) joinCluster(conn net.Conn, peers []string) {
 	metaClient := meta.NewRemoteClient()
 	metaClient.SetMetaServers(peers)
+	if err := s.Config.Meta.ConfigureClient(metaClient); err != nil {
+		s.logger.Error("error configuring MetaClient", zap.Error(err))
+		return
+	}
 	if err := metaClient.Open(); err != nil {
 		s.logger.Error("error open MetaClient", zap.Error(err))
 		return
@@ -485,6 +581,10 @@ func (s *Server) Statistics(tags map[string]string) []models.Statistic {
 	statistics = append(statistics, s.queryExecutor.Statistics(tags)...)
 	statistics = append(statistics, s.tsdbStore.Statistics(tags)...)
 	statistics = append(statistics, s.pointsWriter.Statistics(tags)...)
+	statistics = append(statistics, s.subscriber.Statistics(tags)...)
+	statistics = append(statistics, s.readHealth.Statistics(tags)...)
+	statistics = append(statistics, s.quotas.Statistics(tags)...)
+	statistics = append(statistics, s.antiEntropy.Statistics(tags)...)
 	for _, srv := range s.services {
 		if m, ok := srv.(monitor.Reporter); ok {
 			statistics = append(statistics, m.Statistics(tags)...)
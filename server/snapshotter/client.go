@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cnosdatabase/cnosdb/meta"
 	"github.com/cnosdatabase/cnosdb/pkg/network"
@@ -188,6 +189,92 @@ func (c *Client) MetastoreBackup() (*meta.Data, error) {
 	return &data, nil
 }
 
+// BackupShard returns a reader of a tar archive of the TSM files of a shard
+// written since the given time. The caller must close the reader.
+func (c *Client) BackupShard(id uint64, since time.Time) (io.ReadCloser, error) {
+	return c.stream(&Request{
+		Type:    RequestShardBackup,
+		ShardID: id,
+		Since:   since,
+	})
+}
+
+// ShardDigest returns a reader of the digest of a shard. The caller must
+// close the reader.
+func (c *Client) ShardDigest(id uint64) (io.ReadCloser, error) {
+	return c.stream(&Request{
+		Type:    RequestShardDigest,
+		ShardID: id,
+	})
+}
+
+// ExportShard returns a reader of a tar archive of the blocks of a shard
+// that overlap the time range. The caller must close the reader.
+func (c *Client) ExportShard(id uint64, start, end time.Time) (io.ReadCloser, error) {
+	return c.stream(&Request{
+		Type:        RequestShardExport,
+		ShardID:     id,
+		ExportStart: start,
+		ExportEnd:   end,
+	})
+}
+
+// ReplayWAL writes the entries of a WAL segment into a shard, and returns
+// the number of points written.
+func (c *Client) ReplayWAL(id uint64, segment []byte) (int, error) {
+	req := &Request{
+		Type:       RequestShardWALReplay,
+		ShardID:    id,
+		UploadSize: int64(len(segment)),
+	}
+
+	conn, err := network.Dial("tcp", c.host, MuxHeader)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte{byte(req.Type)}); err != nil {
+		return 0, err
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return 0, fmt.Errorf("encode snapshot request: %s", err)
+	}
+	if _, err := conn.Write(segment); err != nil {
+		return 0, err
+	}
+
+	var resp WALReplayResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return 0, fmt.Errorf("read WAL replay response: %s", err)
+	} else if resp.Err != "" {
+		return resp.Points, errors.New(resp.Err)
+	}
+	return resp.Points, nil
+}
+
+// stream sends a request to the snapshotter service and returns the
+// connection to read the result from.
+func (c *Client) stream(req *Request) (io.ReadCloser, error) {
+	// Connect to snapshotter service.
+	conn, err := network.Dial("tcp", c.host, MuxHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	// Write the request
+	if _, err := conn.Write([]byte{byte(req.Type)}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("encode snapshot request: %s", err)
+	}
+
+	return conn, nil
+}
+
 // doRequest sends a request to the snapshotter service and returns the result.
 func (c *Client) doRequest(req *Request) ([]byte, error) {
 	// Connect to snapshotter service.
@@ -2,13 +2,22 @@
 package snapshotter
 
 import (
+	"archive/tar"
 	"bytes"
+	"crypto/sha256"
 	"encoding"
 	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math"
 	"net"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -17,7 +26,10 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/cnosdatabase/cnosdb/meta"
+	"github.com/cnosdatabase/cnosql"
+	"github.com/cnosdatabase/db/models"
 	"github.com/cnosdatabase/db/tsdb"
+	"github.com/cnosdatabase/db/tsdb/engine/tsm1"
 )
 
 const (
@@ -27,6 +39,10 @@ const (
 	// BackupMagicHeader is the first 8 bytes used to identify and validate
 	// a metastore backup file
 	BackupMagicHeader = 0x6b6d657461 //kmeta
+
+	// BackupFileList is the name of the last entry of an incremental shard
+	// backup, which lists every file of the shard.
+	BackupFileList = "files.json"
 )
 
 // Service manages the listener for the snapshot endpoint.
@@ -42,7 +58,9 @@ type Service struct {
 
 	TSDBStore interface {
 		BackupShard(id uint64, since time.Time, w io.Writer) error
+		BackupShardFiles(id uint64, include func(name string, size int64) bool, w io.Writer) error
 		ExportShard(id uint64, ExportStart time.Time, ExportEnd time.Time, w io.Writer) error
+		ShardDigest(id uint64) (io.ReadCloser, int64, error)
 		Shard(id uint64) *tsdb.Shard
 		ShardRelativePath(id uint64) (string, error)
 		SetShardEnabled(shardID uint64, enabled bool) error
@@ -50,6 +68,10 @@ type Service struct {
 		CreateShard(database, timeToLive string, shardID uint64, enabled bool) error
 	}
 
+	// WALArchiveDir is the directory the closed WAL segments are archived
+	// into, empty if WAL archiving is disabled.
+	WALArchiveDir string
+
 	Listener net.Listener
 	Logger   *zap.Logger
 }
@@ -140,6 +162,20 @@ func (s *Service) handleConn(conn net.Conn) error {
 		if err := s.TSDBStore.ExportShard(r.ShardID, r.ExportStart, r.ExportEnd, conn); err != nil {
 			return err
 		}
+	case RequestShardDigest:
+		if err := s.writeShardDigest(conn, r.ShardID); err != nil {
+			return err
+		}
+	case RequestShardIncrementalBackup:
+		if err := s.writeShardIncrementalBackup(conn, r.ShardID, r.KnownFiles); err != nil {
+			return err
+		}
+	case RequestWALArchive:
+		if err := s.writeWALArchive(conn, r.KnownFiles); err != nil {
+			return err
+		}
+	case RequestShardWALReplay:
+		return s.replayShardWAL(conn, r.ShardID, bytes)
 	case RequestMetastoreBackup:
 		if err := s.writeMetaStore(conn); err != nil {
 			return err
@@ -251,6 +287,316 @@ func (s *Service) respondIDMap(conn net.Conn, IDMap map[uint64]uint64) error {
 	return err
 }
 
+// writeShardDigest writes the digest of a shard into the connection.
+func (s *Service) writeShardDigest(conn net.Conn, id uint64) error {
+	r, _, err := s.TSDBStore.ShardDigest(id)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	_, err = io.Copy(conn, r)
+	return err
+}
+
+// writeShardIncrementalBackup writes a tar archive of the files of a shard
+// into the connection, leaving out the TSM files listed in known. TSM files
+// are never modified once written so that they only need to be sent once,
+// the other files of the shard are always sent. The known files are left out
+// of the backup of the shard, so they aren't read. The archive ends with a
+// BackupFileList entry holding the BackupFile of every file of the shard.
+func (s *Service) writeShardIncrementalBackup(conn net.Conn, id uint64, known []string) error {
+	skip := make(map[string]struct{}, len(known))
+	for _, name := range known {
+		if strings.HasSuffix(name, ".tsm") {
+			skip[name] = struct{}{}
+		}
+	}
+
+	// skipped is only appended to by the backup, before it closes the pipe.
+	var skipped []BackupFile
+	include := func(name string, size int64) bool {
+		if _, ok := skip[name]; ok {
+			skipped = append(skipped, BackupFile{Name: name, Size: size})
+			return false
+		}
+		return true
+	}
+
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(s.TSDBStore.BackupShardFiles(id, include, pw))
+	}()
+
+	tr := tar.NewReader(pr)
+	tw := tar.NewWriter(conn)
+	var files []BackupFile
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		h := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(tw, h), tr); err != nil {
+			return err
+		}
+		files = append(files, BackupFile{Name: hdr.Name, Size: hdr.Size, Checksum: hex.EncodeToString(h.Sum(nil))})
+	}
+	return writeFileList(tw, append(files, skipped...))
+}
+
+// writeFileList writes the BackupFileList entry ending an archive and closes
+// the archive.
+func writeFileList(tw *tar.Writer, files []BackupFile) error {
+	b, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:     BackupFileList,
+		Mode:     0600,
+		Size:     int64(len(b)),
+		ModTime:  time.Now(),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(b); err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// writeWALArchive removes the archived WAL segments listed in shipped, which
+// are shipped to a backup, and writes a tar archive of the other archived
+// segments into the connection. The segments are named after their path in
+// the archive directory, e.g. db/ttl/1/01634567890123456789-_00001.wal, and
+// the archive ends with a BackupFileList entry holding their BackupFile.
+func (s *Service) writeWALArchive(conn net.Conn, shipped []string) error {
+	if s.WALArchiveDir == "" {
+		// An archive without a file list tells the client that nothing
+		// can be shipped.
+		tar.NewWriter(conn).Close()
+		return errors.New("WAL archiving is disabled")
+	}
+	dir := filepath.Clean(s.WALArchiveDir)
+
+	for _, name := range shipped {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	// The file list is sent even if no segment is, to tell the client
+	// that archiving is enabled.
+	files := []BackupFile{}
+	tw := tar.NewWriter(conn)
+	if err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// Segments being copied into the archive are left out.
+		if fi.IsDir() {
+			return nil
+		} else if _, err := tsm1.ParseArchivedSegmentName(fi.Name()); err != nil {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		hdr := &tar.Header{
+			Name:     filepath.ToSlash(rel),
+			Mode:     0600,
+			Size:     fi.Size(),
+			ModTime:  fi.ModTime(),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		h := sha256.New()
+		if _, err := io.CopyN(io.MultiWriter(tw, h), f, fi.Size()); err != nil {
+			return err
+		}
+		files = append(files, BackupFile{Name: hdr.Name, Size: hdr.Size, Checksum: hex.EncodeToString(h.Sum(nil))})
+		return nil
+	}); err != nil {
+		return err
+	}
+	return writeFileList(tw, files)
+}
+
+// replayShardWAL writes the entries of a WAL segment into a shard, and
+// responds with a WALReplayResponse.
+func (s *Service) replayShardWAL(conn net.Conn, id uint64, segment []byte) error {
+	resp, err := s.replayWAL(id, segment)
+	if err != nil {
+		resp.Err = err.Error()
+	}
+	if err := json.NewEncoder(conn).Encode(resp); err != nil {
+		return err
+	}
+	return err
+}
+
+// replayWAL writes the entries of a WAL segment into a shard. The writes go
+// through the shard so that its index is updated, and the deletes remove
+// the series of their keys over their time range.
+func (s *Service) replayWAL(id uint64, segment []byte) (WALReplayResponse, error) {
+	var resp WALReplayResponse
+
+	sh := s.TSDBStore.Shard(id)
+	if sh == nil {
+		return resp, fmt.Errorf("shard %d not found", id)
+	}
+
+	r := tsm1.NewWALSegmentReader(ioutil.NopCloser(bytes.NewReader(segment)))
+	defer r.Close()
+	for r.Next() {
+		entry, err := r.Read()
+		if err != nil {
+			// A segment may end with an entry partially written before
+			// a crash, which the engine skips as well.
+			s.Logger.Info("Stopped replaying corrupt WAL segment", zap.Uint64("shard", id), zap.Int64("offset", r.Count()), zap.Error(err))
+			break
+		}
+
+		switch e := entry.(type) {
+		case *tsm1.WriteWALEntry:
+			points, err := walEntryPoints(e)
+			if err != nil {
+				return resp, err
+			}
+			if err := sh.WritePoints(points); err != nil {
+				return resp, err
+			}
+			resp.Points += len(points)
+		case *tsm1.DeleteWALEntry:
+			if err := sh.DeleteSeriesRange(newWALSeriesIterator(e.Keys), math.MinInt64, math.MaxInt64); err != nil {
+				return resp, err
+			}
+			resp.Deletes++
+		case *tsm1.DeleteRangeWALEntry:
+			if err := sh.DeleteSeriesRange(newWALSeriesIterator(e.Keys), e.Min, e.Max); err != nil {
+				return resp, err
+			}
+			resp.Deletes++
+		}
+	}
+	return resp, nil
+}
+
+// walEntryPoints returns the points of a WAL write entry, one per series
+// and time.
+func walEntryPoints(e *tsm1.WriteWALEntry) ([]models.Point, error) {
+	type seriesTime struct {
+		key string
+		t   int64
+	}
+
+	var order []seriesTime
+	fields := make(map[seriesTime]models.Fields)
+	for k, values := range e.Values {
+		seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey([]byte(k))
+		for _, v := range values {
+			st := seriesTime{key: string(seriesKey), t: v.UnixNano()}
+			f := fields[st]
+			if f == nil {
+				f = make(models.Fields)
+				fields[st] = f
+				order = append(order, st)
+			}
+			f[string(field)] = v.Value()
+		}
+	}
+
+	points := make([]models.Point, 0, len(order))
+	for _, st := range order {
+		name, tags := models.ParseKeyBytes([]byte(st.key))
+		p, err := models.NewPoint(string(name), tags, fields[st], time.Unix(0, st.t))
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// walSeriesIterator iterates over the series of the keys of a WAL delete
+// entry, in order.
+type walSeriesIterator struct {
+	keys []string
+}
+
+// newWALSeriesIterator returns an iterator over the series of keys, which
+// are composite series and field keys.
+func newWALSeriesIterator(keys [][]byte) *walSeriesIterator {
+	seen := make(map[string]struct{}, len(keys))
+	itr := &walSeriesIterator{}
+	for _, k := range keys {
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(k)
+		if _, ok := seen[string(seriesKey)]; !ok {
+			seen[string(seriesKey)] = struct{}{}
+			itr.keys = append(itr.keys, string(seriesKey))
+		}
+	}
+	sort.Strings(itr.keys)
+	return itr
+}
+
+// Next returns the next series, or nil once done.
+func (itr *walSeriesIterator) Next() (tsdb.SeriesElem, error) {
+	if len(itr.keys) == 0 {
+		return nil, nil
+	}
+	name, tags := models.ParseKeyBytes([]byte(itr.keys[0]))
+	itr.keys = itr.keys[1:]
+	return walSeriesElem{name: name, tags: tags}, nil
+}
+
+// Close closes the iterator.
+func (itr *walSeriesIterator) Close() error { return nil }
+
+// walSeriesElem is a series deleted by a WAL entry.
+type walSeriesElem struct {
+	name []byte
+	tags models.Tags
+}
+
+func (e walSeriesElem) Name() []byte      { return e.name }
+func (e walSeriesElem) Tags() models.Tags { return e.tags }
+func (e walSeriesElem) Deleted() bool     { return false }
+func (e walSeriesElem) Expr() cnosql.Expr { return nil }
+
 func (s *Service) writeMetaStore(conn net.Conn) error {
 	// Retrieve and serialize the current meta data.
 	metaBlob, err := s.MetaClient.MarshalBinary()
@@ -387,24 +733,12 @@ func (s *Service) readRequest(conn net.Conn) (Request, []byte, error) {
 	bits := make([]byte, r.UploadSize+1)
 
 	if r.UploadSize > 0 {
-
-		remainder := d.Buffered()
-
-		n, err := remainder.Read(bits)
-		if err != nil && err != io.EOF {
+		// The JSON decoder may have buffered a part of the upload.
+		if _, err := io.ReadFull(io.MultiReader(d.Buffered(), conn), bits); err != nil {
 			return r, bits, err
 		}
 
-		// it is a bit random but sometimes the Json decoder will consume all the bytes and sometimes
-		// it will leave a few behind.
-		if err != io.EOF && n < int(r.UploadSize+1) {
-			_, err = conn.Read(bits[n:])
-		}
-
-		if err != nil && err != io.EOF {
-			return r, bits, err
-		}
-		// the JSON encoder on the client side seems to write an extra byte, so trim that off the front.
+		// the JSON encoder on the client side writes a trailing newline, so trim that off the front.
 		return r, bits[1:], nil
 	}
 
@@ -441,6 +775,22 @@ const (
 	// RequestShardUpdate will initiate the upload of a shard data tar file
 	// and have the engine import the data.
 	RequestShardUpdate
+
+	// RequestShardDigest represents a request for the digest of a shard.
+	RequestShardDigest
+
+	// RequestShardIncrementalBackup represents a request for the files of a
+	// shard which aren't in a previous backup, with the checksums of all of
+	// its files.
+	RequestShardIncrementalBackup
+
+	// RequestWALArchive represents a request for the archived WAL segments,
+	// acknowledging the ones already shipped to a backup.
+	RequestWALArchive
+
+	// RequestShardWALReplay represents a request to write the entries of an
+	// uploaded WAL segment into a shard.
+	RequestShardWALReplay
 )
 
 // Request represents a request for a specific backup or for information
@@ -456,6 +806,27 @@ type Request struct {
 	ExportStart       time.Time
 	ExportEnd         time.Time
 	UploadSize        int64
+
+	// KnownFiles lists the files of the shard which are already backed up,
+	// for RequestShardIncrementalBackup, or the archived WAL segments which
+	// are shipped, for RequestWALArchive.
+	KnownFiles []string
+}
+
+// WALReplayResponse is the result of a RequestShardWALReplay.
+type WALReplayResponse struct {
+	Points  int
+	Deletes int
+	Err     string `json:",omitempty"`
+}
+
+// BackupFile describes a file of a shard backup. The checksum is the
+// hex-encoded SHA-256 of the file, it is empty for the files which weren't
+// sent because they are already backed up.
+type BackupFile struct {
+	Name     string
+	Size     int64
+	Checksum string
 }
 
 // Response contains the relative paths for all the shards on this server
@@ -21,8 +21,101 @@ const (
 
 	// DefaultWriteBufferSize is the default write buffer size for a Config.
 	DefaultWriteBufferSize = 1000
+
+	// DefaultDurableMaxSize is the default maximum size in bytes of the queue
+	// of a durable subscription destination.
+	DefaultDurableMaxSize = 1024 * 1024 * 1024
+
+	// DefaultDurableRetryInterval is the default amount of time to wait before
+	// retrying a failed write of a durable subscription. The interval doubles
+	// with each failed write until it reaches the maximum.
+	DefaultDurableRetryInterval = time.Second
+
+	// DefaultDurableRetryMaxInterval is the default maximum amount of time to
+	// wait before retrying a failed write of a durable subscription.
+	DefaultDurableRetryMaxInterval = time.Minute
+
+	// DefaultSinkBatchSize is the default maximum number of points a
+	// streaming sink sends in a batch.
+	DefaultSinkBatchSize = 5000
+
+	// DefaultSinkBatchTimeout is the default amount of time a streaming sink
+	// waits for more writes before sending a batch that isn't full.
+	DefaultSinkBatchTimeout = 10 * time.Millisecond
+
+	// DefaultSinkBufferSize is the default number of writes a streaming sink
+	// buffers while a batch is sent.
+	DefaultSinkBufferSize = 1000
+
+	// DefaultSinkWriteTimeout is the default amount of time a streaming sink
+	// waits for buffer space, and for a batch to be sent.
+	DefaultSinkWriteTimeout = 10 * time.Second
 )
 
+// SinkConfig represents the configuration of a streaming subscription sink,
+// such as the TCP or gRPC sink.
+type SinkConfig struct {
+	// The maximum number of points sent in a batch.
+	BatchSize int `toml:"batch-size"`
+
+	// The amount of time to wait for more writes before sending a batch
+	// that isn't full.
+	BatchTimeout toml.Duration `toml:"batch-timeout"`
+
+	// The number of writes buffered while a batch is sent. Writes wait for
+	// buffer space up to the write timeout, and fail after that.
+	BufferSize int `toml:"buffer-size"`
+
+	// The amount of time to wait for buffer space, and for a batch to be sent.
+	WriteTimeout toml.Duration `toml:"write-timeout"`
+
+	// Whether to connect to the destinations with TLS.
+	TLSEnabled bool `toml:"tls-enabled"`
+
+	// InsecureSkipVerify skips the verification of the certificates of the
+	// destinations. Defaults to false
+	InsecureSkipVerify bool `toml:"insecure-skip-verify"`
+
+	// configure the path to the PEM encoded CA certs file. If the
+	// empty string, the default system certs will be used
+	CaCerts string `toml:"ca-certs"`
+}
+
+// NewSinkConfig returns a new instance of a sink config with defaults.
+func NewSinkConfig() SinkConfig {
+	return SinkConfig{
+		BatchSize:    DefaultSinkBatchSize,
+		BatchTimeout: toml.Duration(DefaultSinkBatchTimeout),
+		BufferSize:   DefaultSinkBufferSize,
+		WriteTimeout: toml.Duration(DefaultSinkWriteTimeout),
+	}
+}
+
+// Validate returns an error if the config is invalid.
+func (c SinkConfig) Validate() error {
+	if c.BatchSize <= 0 {
+		return errors.New("batch-size must be greater than 0")
+	}
+
+	if c.BatchTimeout <= 0 {
+		return errors.New("batch-timeout must be greater than 0")
+	}
+
+	if c.BufferSize <= 0 {
+		return errors.New("buffer-size must be greater than 0")
+	}
+
+	if c.WriteTimeout <= 0 {
+		return errors.New("write-timeout must be greater than 0")
+	}
+
+	if c.CaCerts != "" && !fileExists(c.CaCerts) {
+		return fmt.Errorf("ca-certs file %s does not exist", c.CaCerts)
+	}
+
+	return nil
+}
+
 // Config represents a configuration of the subscriber service.
 type Config struct {
 	// Whether to enable to Subscriber service
@@ -44,6 +137,21 @@ type Config struct {
 	// The number of in-flight writes buffered in the write channel.
 	WriteBufferSize int `toml:"write-buffer-size"`
 
+	// The directory where the writes of durable subscriptions are queued.
+	Dir string `toml:"dir"`
+
+	// The maximum size in bytes of the queue of a durable subscription destination.
+	DurableMaxSize int64 `toml:"durable-max-size"`
+
+	// The interval between retries of failed writes of durable subscriptions,
+	// which doubles after each failure up to the maximum interval.
+	DurableRetryInterval    toml.Duration `toml:"durable-retry-interval"`
+	DurableRetryMaxInterval toml.Duration `toml:"durable-retry-max-interval"`
+
+	// The settings of the sinks of tcp:// and grpc:// destinations.
+	TCP  SinkConfig `toml:"tcp"`
+	GRPC SinkConfig `toml:"grpc"`
+
 	// TLS is a base tls config to use for https clients.
 	TLS *tls.Config `toml:"-"`
 }
@@ -57,6 +165,13 @@ func NewConfig() Config {
 		CaCerts:            "",
 		WriteConcurrency:   DefaultWriteConcurrency,
 		WriteBufferSize:    DefaultWriteBufferSize,
+
+		DurableMaxSize:          DefaultDurableMaxSize,
+		DurableRetryInterval:    toml.Duration(DefaultDurableRetryInterval),
+		DurableRetryMaxInterval: toml.Duration(DefaultDurableRetryMaxInterval),
+
+		TCP:  NewSinkConfig(),
+		GRPC: NewSinkConfig(),
 	}
 }
 
@@ -82,6 +197,26 @@ func (c Config) Validate() error {
 		return errors.New("write-concurrency must be greater than 0")
 	}
 
+	if c.DurableMaxSize <= 0 {
+		return errors.New("durable-max-size must be greater than 0")
+	}
+
+	if c.DurableRetryInterval <= 0 {
+		return errors.New("durable-retry-interval must be greater than 0")
+	}
+
+	if c.DurableRetryMaxInterval < c.DurableRetryInterval {
+		return errors.New("durable-retry-max-interval must not be less than durable-retry-interval")
+	}
+
+	if err := c.TCP.Validate(); err != nil {
+		return fmt.Errorf("tcp: %s", err)
+	}
+
+	if err := c.GRPC.Validate(); err != nil {
+		return fmt.Errorf("grpc: %s", err)
+	}
+
 	return nil
 }
 
@@ -103,5 +238,11 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"http-timeout":      c.HTTPTimeout,
 		"write-concurrency": c.WriteConcurrency,
 		"write-buffer-size": c.WriteBufferSize,
+		"dir":               c.Dir,
+		"durable-max-size":  c.DurableMaxSize,
+		"tcp-batch-size":    c.TCP.BatchSize,
+		"tcp-tls-enabled":   c.TCP.TLSEnabled,
+		"grpc-batch-size":   c.GRPC.BatchSize,
+		"grpc-tls-enabled":  c.GRPC.TLSEnabled,
 	}), nil
 }
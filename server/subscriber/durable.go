@@ -0,0 +1,378 @@
+package subscriber
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/server/coordinator"
+	"github.com/cnosdatabase/cnosdb/server/hh"
+	"github.com/cnosdatabase/db/models"
+	"go.uber.org/zap"
+)
+
+// Statistics for the queues of durable subscriptions.
+const (
+	statPointsQueued  = "pointsQueued"
+	statPointsDropped = "pointsDropped"
+	statQueueBytes    = "queueBytes"
+	statLagMs         = "lagMs"
+)
+
+// durableWriter queues the writes of a durable subscription on disk and
+// delivers them to the destinations in the background, retrying failed
+// writes until they succeed.
+//
+// A subscription in ALL mode has a queue for each destination, so that a
+// slow destination doesn't hold back the others. A subscription in ANY mode
+// has a single queue, whose writes go to any destination that accepts them.
+type durableWriter struct {
+	dir    string
+	queues []*destinationQueue
+}
+
+// newDurableWriter returns a durableWriter queueing writes in dir.
+func newDurableWriter(dir string, bw *balancewriter, c Config, logger *zap.Logger) *durableWriter {
+	d := &durableWriter{dir: dir}
+
+	var writers []*balancewriter
+	if bw.bm == ALL {
+		for i := range bw.writers {
+			writers = append(writers, &balancewriter{
+				bm:          ALL,
+				writers:     bw.writers[i : i+1],
+				stats:       bw.stats[i : i+1],
+				defaultTags: bw.defaultTags,
+			})
+		}
+	} else {
+		writers = append(writers, bw)
+	}
+
+	for _, w := range writers {
+		dests := make([]string, len(w.stats))
+		for i := range w.stats {
+			dests[i] = w.stats[i].dest
+		}
+		dest := strings.Join(dests, ",")
+
+		d.queues = append(d.queues, &destinationQueue{
+			dest:             dest,
+			dir:              filepath.Join(dir, url.QueryEscape(dest)),
+			pw:               w,
+			maxSize:          c.DurableMaxSize,
+			retryInterval:    time.Duration(c.DurableRetryInterval),
+			retryMaxInterval: time.Duration(c.DurableRetryMaxInterval),
+			defaultTags:      bw.defaultTags,
+			logger:           logger.With(zap.String("destination", dest)),
+		})
+	}
+	return d
+}
+
+// Open opens the queues and starts delivering the queued writes.
+func (d *durableWriter) Open() error {
+	for i, q := range d.queues {
+		if err := q.Open(); err != nil {
+			for _, q := range d.queues[:i] {
+				q.Close()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops delivering writes and closes the queues and the writers of
+// the destinations.
+func (d *durableWriter) Close() {
+	for _, q := range d.queues {
+		q.Close()
+		closeWriter(q.pw)
+	}
+}
+
+// Remove deletes the queued writes. The writer must be closed.
+func (d *durableWriter) Remove() error {
+	return os.RemoveAll(d.dir)
+}
+
+// WritePoints queues a write for each destination queue. The write is
+// dropped for the queues that are full or failing.
+func (d *durableWriter) WritePoints(p *coordinator.WritePointsRequest) error {
+	b := marshalWrite(time.Now(), p)
+
+	var lastErr error
+	for _, q := range d.queues {
+		if err := q.Append(b, len(p.Points)); err != nil {
+			lastErr = fmt.Errorf("queue write for %s: %s", q.dest, err)
+		}
+	}
+	return lastErr
+}
+
+// Statistics returns statistics for periodic monitoring.
+func (d *durableWriter) Statistics(tags map[string]string) []models.Statistic {
+	var statistics []models.Statistic
+	for _, q := range d.queues {
+		statistics = append(statistics, q.pw.Statistics(tags)...)
+		statistics = append(statistics, q.Statistics(tags))
+	}
+	return statistics
+}
+
+// destinationQueue is the on-disk queue of the writes of a durable
+// subscription for a destination.
+type destinationQueue struct {
+	dest             string
+	dir              string
+	pw               *balancewriter
+	maxSize          int64
+	retryInterval    time.Duration
+	retryMaxInterval time.Duration
+
+	queue  *hh.Queue
+	notify chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	pointsQueued  int64 // Points in the queue.
+	pointsDropped int64 // Points dropped because they couldn't be queued.
+	headTime      int64 // Time the write at the head of the queue was queued, in nanoseconds.
+
+	defaultTags models.StatisticTags
+	logger      *zap.Logger
+}
+
+// Open opens the queue and starts delivering the queued writes.
+func (q *destinationQueue) Open() error {
+	if err := os.MkdirAll(q.dir, 0700); err != nil {
+		return fmt.Errorf("mkdir all: %s", err)
+	}
+
+	queue, err := hh.NewQueue(q.dir, q.maxSize)
+	if err != nil {
+		return err
+	}
+	if err := queue.Open(); err != nil {
+		return err
+	}
+
+	// Count the points of the writes left in the queue.
+	var n int64
+	if err := queue.Walk(func(b []byte) error {
+		n += writePointN(b)
+		return nil
+	}); err != nil {
+		queue.Close()
+		return err
+	}
+	q.queue = queue
+	atomic.StoreInt64(&q.pointsQueued, n)
+
+	q.notify = make(chan struct{}, 1)
+	q.done = make(chan struct{})
+
+	q.wg.Add(1)
+	go q.run()
+	return nil
+}
+
+// Close stops delivering writes and closes the queue.
+func (q *destinationQueue) Close() {
+	close(q.done)
+	q.wg.Wait()
+	if err := q.queue.Close(); err != nil {
+		q.logger.Info("Error closing subscription queue", zap.Error(err))
+	}
+}
+
+// Append queues a write of n points. The points are dropped if the queue is
+// full or can't be written.
+func (q *destinationQueue) Append(b []byte, n int) error {
+	if err := q.queue.Append(b); err != nil {
+		atomic.AddInt64(&q.pointsDropped, int64(n))
+		return err
+	}
+	atomic.AddInt64(&q.pointsQueued, int64(n))
+
+	// Wake up the delivery of the writes.
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// run delivers the queued writes. After a failed write, it waits for the
+// retry interval, which doubles with each failure up to the maximum.
+func (q *destinationQueue) run() {
+	defer q.wg.Done()
+
+	interval := q.retryInterval
+	for {
+		err := q.sendWrite()
+		if err == nil {
+			interval = q.retryInterval
+			select {
+			case <-q.done:
+				return
+			default:
+			}
+			continue
+		}
+
+		if err == io.EOF {
+			// No more writes, wait for new ones.
+			interval = q.retryInterval
+			select {
+			case <-q.done:
+				return
+			case <-q.notify:
+			}
+			continue
+		}
+
+		q.logger.Info("Failed to write to subscription destination", zap.Error(err), zap.Duration("retry_interval", interval))
+		select {
+		case <-q.done:
+			return
+		case <-time.After(interval):
+		}
+
+		interval *= 2
+		if interval > q.retryMaxInterval {
+			interval = q.retryMaxInterval
+		}
+	}
+}
+
+// sendWrite sends the write at the head of the queue to the destination, and
+// advances the queue if it succeeds. It returns io.EOF if there are no writes.
+func (q *destinationQueue) sendWrite() error {
+	b, err := q.queue.Current()
+	if err == io.EOF {
+		atomic.StoreInt64(&q.headTime, 0)
+		return io.EOF
+	} else if err != nil {
+		return err
+	}
+
+	queuedAt, p, err := unmarshalWrite(b)
+	if err != nil {
+		// Skip the write, it can never be sent.
+		q.logger.Info("Dropping unreadable subscription write", zap.Error(err))
+		return q.advance(b)
+	}
+	atomic.StoreInt64(&q.headTime, queuedAt.UnixNano())
+
+	if err := q.pw.WritePoints(p); err != nil {
+		return err
+	}
+	return q.advance(b)
+}
+
+// advance removes the write b at the head of the queue.
+func (q *destinationQueue) advance(b []byte) error {
+	if err := q.queue.Advance(); err != nil {
+		return err
+	}
+	atomic.AddInt64(&q.pointsQueued, -writePointN(b))
+	return nil
+}
+
+// Statistics returns the size of the queue, and the lag of the destination,
+// which is the time the write at the head of the queue has been queued.
+func (q *destinationQueue) Statistics(tags map[string]string) models.Statistic {
+	var lag time.Duration
+	if t := atomic.LoadInt64(&q.headTime); t > 0 {
+		lag = time.Since(time.Unix(0, t))
+	}
+
+	subTags := q.defaultTags.Merge(tags)
+	subTags["destination"] = q.dest
+	return models.Statistic{
+		Name: "subscriber_queue",
+		Tags: subTags,
+		Values: map[string]interface{}{
+			statPointsQueued:  atomic.LoadInt64(&q.pointsQueued),
+			statPointsDropped: atomic.LoadInt64(&q.pointsDropped),
+			statQueueBytes:    q.queue.DiskUsage(),
+			statLagMs:         int64(lag / time.Millisecond),
+		},
+	}
+}
+
+// marshalWrite encodes a write with the time it was queued.
+func marshalWrite(queuedAt time.Time, p *coordinator.WritePointsRequest) []byte {
+	b := make([]byte, 8, 8+2*binary.MaxVarintLen64+len(p.Database)+len(p.TimeToLive))
+	binary.BigEndian.PutUint64(b, uint64(queuedAt.UnixNano()))
+	b = appendString(b, p.Database)
+	b = appendString(b, p.TimeToLive)
+	for _, pt := range p.Points {
+		b = append(b, pt.String()...)
+		b = append(b, '\n')
+	}
+	return b
+}
+
+// unmarshalWrite decodes a write encoded by marshalWrite.
+func unmarshalWrite(b []byte) (time.Time, *coordinator.WritePointsRequest, error) {
+	if len(b) < 8 {
+		return time.Time{}, nil, fmt.Errorf("too short: len = %d", len(b))
+	}
+	queuedAt := time.Unix(0, int64(binary.BigEndian.Uint64(b[:8])))
+	b = b[8:]
+
+	p := &coordinator.WritePointsRequest{}
+	var err error
+	if p.Database, b, err = readString(b); err != nil {
+		return time.Time{}, nil, err
+	}
+	if p.TimeToLive, b, err = readString(b); err != nil {
+		return time.Time{}, nil, err
+	}
+	if p.Points, err = models.ParsePoints(b); err != nil {
+		return time.Time{}, nil, err
+	}
+	return queuedAt, p, nil
+}
+
+// writePointN returns the number of points of a write encoded by
+// marshalWrite, which has a line for each point.
+func writePointN(b []byte) int64 {
+	if len(b) < 8 {
+		return 0
+	}
+	_, b, err := readString(b[8:])
+	if err != nil {
+		return 0
+	}
+	if _, b, err = readString(b); err != nil {
+		return 0
+	}
+	return int64(bytes.Count(b, []byte{'\n'}))
+}
+
+func appendString(b []byte, s string) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(s)))
+	b = append(b, buf[:n]...)
+	return append(b, s...)
+}
+
+func readString(b []byte) (string, []byte, error) {
+	sz, n := binary.Uvarint(b)
+	if n <= 0 || uint64(len(b)-n) < sz {
+		return "", nil, fmt.Errorf("invalid string length")
+	}
+	return string(b[n : n+int(sz)]), b[n+int(sz):], nil
+}
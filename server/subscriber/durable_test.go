@@ -0,0 +1,205 @@
+package subscriber
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/server/coordinator"
+	"github.com/cnosdatabase/cnosdb/server/hh"
+	"github.com/cnosdatabase/common/pkg/toml"
+	"go.uber.org/zap"
+)
+
+// durableDest is a destination recording the points written to it. Its
+// writes fail while it is unreachable.
+type durableDest struct {
+	mu          sync.Mutex
+	unreachable bool
+	lines       []string
+}
+
+func (d *durableDest) WritePoints(p *coordinator.WritePointsRequest) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.unreachable {
+		return errors.New("unreachable")
+	}
+	for _, pt := range p.Points {
+		d.lines = append(d.lines, p.Database+" "+pt.String())
+	}
+	return nil
+}
+
+func (d *durableDest) setUnreachable(unreachable bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.unreachable = unreachable
+}
+
+func (d *durableDest) received() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return strings.Join(d.lines, "\n")
+}
+
+// openDurableWriter opens a writer queueing writes in dir for the
+// destinations of a subscription in ALL mode.
+func openDurableWriter(t *testing.T, dir string, maxSize int64, dests ...*durableDest) *durableWriter {
+	t.Helper()
+	c := NewConfig()
+	c.DurableMaxSize = maxSize
+	c.DurableRetryInterval = toml.Duration(10 * time.Millisecond)
+	c.DurableRetryMaxInterval = toml.Duration(10 * time.Millisecond)
+
+	bw := &balancewriter{bm: ALL}
+	for i, d := range dests {
+		bw.writers = append(bw.writers, d)
+		bw.stats = append(bw.stats, writerStats{dest: string(rune('a' + i))})
+	}
+
+	dw := newDurableWriter(dir, bw, c, zap.NewNop())
+	if err := dw.Open(); err != nil {
+		t.Fatal(err)
+	}
+	return dw
+}
+
+// queueStat returns a statistic of the i-th queue of the writer.
+func queueStat(dw *durableWriter, i int, name string) int64 {
+	return dw.queues[i].Statistics(nil).Values[name].(int64)
+}
+
+// waitFor waits for cond to be true.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDurableWriter_Replay(t *testing.T) {
+	dest := &durableDest{unreachable: true}
+	dw := openDurableWriter(t, t.TempDir(), DefaultDurableMaxSize, dest)
+	defer dw.Close()
+
+	for _, p := range []*coordinator.WritePointsRequest{
+		newWrite(t, "db0", "ttl0", "cpu v=1 1"),
+		newWrite(t, "db0", "ttl0", "cpu v=2 2\ncpu v=3 3"),
+	} {
+		if err := dw.WritePoints(p); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if n := queueStat(dw, 0, statPointsQueued); n != 3 {
+		t.Fatalf("got %d points queued, exp 3", n)
+	}
+
+	// The queued writes are delivered in order once the destination is back.
+	dest.setUnreachable(false)
+	exp := "db0 cpu v=1 1\ndb0 cpu v=2 2\ndb0 cpu v=3 3"
+	waitFor(t, "the writes to be delivered", func() bool { return dest.received() == exp })
+	waitFor(t, "the queue to be empty", func() bool { return queueStat(dw, 0, statPointsQueued) == 0 })
+}
+
+func TestDurableWriter_Restart(t *testing.T) {
+	dir := t.TempDir()
+	dest := &durableDest{unreachable: true}
+	dw := openDurableWriter(t, dir, DefaultDurableMaxSize, dest)
+	if err := dw.WritePoints(newWrite(t, "db0", "ttl0", "cpu v=1 1\ncpu v=2 2")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	dw.Close()
+
+	// The writes left in the queue are counted and delivered after a restart.
+	dw = openDurableWriter(t, dir, DefaultDurableMaxSize, dest)
+	defer dw.Close()
+	if n := queueStat(dw, 0, statPointsQueued); n != 2 {
+		t.Fatalf("got %d points queued, exp 2", n)
+	}
+
+	dest.setUnreachable(false)
+	exp := "db0 cpu v=1 1\ndb0 cpu v=2 2"
+	waitFor(t, "the writes to be delivered", func() bool { return dest.received() == exp })
+	waitFor(t, "the queue to be empty", func() bool { return queueStat(dw, 0, statPointsQueued) == 0 })
+}
+
+func TestDurableWriter_MaxSize(t *testing.T) {
+	p := newWrite(t, "db0", "ttl0", "cpu v=1 1\ncpu v=2 2")
+	size := int64(len(marshalWrite(time.Now(), p)))
+
+	// The queue holds a single write, with its length and the footer of the
+	// segment.
+	dest := &durableDest{unreachable: true}
+	dw := openDurableWriter(t, t.TempDir(), size+16, dest)
+	defer dw.Close()
+
+	if err := dw.WritePoints(p); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := dw.WritePoints(p); err == nil || !strings.Contains(err.Error(), hh.ErrQueueFull.Error()) {
+		t.Fatalf("got error %v, exp the queue to be full", err)
+	}
+	if n := queueStat(dw, 0, statPointsQueued); n != 2 {
+		t.Fatalf("got %d points queued, exp 2", n)
+	} else if n := queueStat(dw, 0, statPointsDropped); n != 2 {
+		t.Fatalf("got %d points dropped, exp 2", n)
+	}
+
+	// Only the queued write is delivered.
+	dest.setUnreachable(false)
+	exp := "db0 cpu v=1 1\ndb0 cpu v=2 2"
+	waitFor(t, "the write to be delivered", func() bool { return dest.received() == exp })
+	waitFor(t, "the queue to be empty", func() bool { return queueStat(dw, 0, statPointsQueued) == 0 })
+	if got := dest.received(); got != exp {
+		t.Fatalf("got points %q, exp %q", got, exp)
+	}
+}
+
+func TestService_WriteDurable_Drop(t *testing.T) {
+	p := newWrite(t, "db0", "ttl0", "cpu v=1 1")
+	size := int64(len(marshalWrite(time.Now(), p)))
+
+	// The queues hold two writes, in segments of a single write so that the
+	// delivered writes are removed. The queue of the unreachable destination
+	// fills up, while the other destination keeps receiving the writes.
+	up, down := &durableDest{}, &durableDest{unreachable: true}
+	dw := openDurableWriter(t, t.TempDir(), 2*(size+16), up, down)
+	defer dw.Close()
+	for _, q := range dw.queues {
+		q.queue.SetMaxSegmentSize(size + 16)
+	}
+
+	s := NewService(NewConfig())
+	s.durable = map[subEntry]*durableWriter{{db: "db0", ttl: "ttl0", name: "s0"}: dw}
+
+	for _, p := range []*coordinator.WritePointsRequest{p, newWrite(t, "db0", "ttl0", "cpu v=2 2")} {
+		if err := s.WriteDurable(p); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	waitFor(t, "the writes to be delivered", func() bool { return up.received() == "db0 cpu v=1 1\ndb0 cpu v=2 2" })
+
+	if err := s.WriteDurable(newWrite(t, "db0", "ttl0", "cpu v=3 3")); err == nil {
+		t.Fatal("expected the write to be dropped")
+	}
+	if n := s.stats.WriteFailures; n != 1 {
+		t.Fatalf("got %d write failures, exp 1", n)
+	} else if n := queueStat(dw, 1, statPointsDropped); n != 1 {
+		t.Fatalf("got %d points dropped, exp 1", n)
+	} else if n := queueStat(dw, 0, statPointsDropped); n != 0 {
+		t.Fatalf("got %d points dropped by the reachable destination, exp 0", n)
+	}
+	exp := "db0 cpu v=1 1\ndb0 cpu v=2 2\ndb0 cpu v=3 3"
+	waitFor(t, "the writes to be delivered", func() bool { return up.received() == exp })
+
+	// Writes to other time-to-lives aren't queued.
+	if err := s.WriteDurable(newWrite(t, "db0", "ttl1", "cpu v=4 4")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,283 @@
+package subscriber
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/server/coordinator"
+	internal "github.com/cnosdatabase/cnosdb/server/subscriber/internal"
+	"github.com/gogo/protobuf/proto"
+	"golang.org/x/net/http2"
+)
+
+// grpcWritePointsMethod is the path of the WritePoints method of the
+// Subscriber service, which is defined in internal/subscriber.proto.
+const grpcWritePointsMethod = "/subscriber.Subscriber/WritePoints"
+
+// grpcMaxMessageSize is the maximum size of a response message.
+const grpcMaxMessageSize = 4 * 1024 * 1024
+
+// errStreamClosed is the error of a write to a closed stream.
+var errStreamClosed = errors.New("grpc stream closed")
+
+// GRPC supports writing points to a gRPC server implementing the Subscriber
+// service. Writes are sent in batches over a single WritePoints stream, which
+// is opened when needed and reopened after an error. Each batch is sent as a
+// request message for each database and time-to-live it writes to, and every
+// message must be acknowledged by a response before the next one is sent.
+type GRPC struct {
+	url       string
+	timeout   time.Duration
+	transport *http2.Transport
+
+	mu     sync.Mutex
+	stream *grpcStream
+
+	batcher *batcher
+}
+
+// NewGRPC returns a new gRPC points writer. The connection uses TLS if it is
+// enabled in the config, with tlsConfig as the base TLS config.
+func NewGRPC(addr string, c SinkConfig, tlsConfig *tls.Config) (*GRPC, error) {
+	tlsConfig, err := sinkTLSConfig(c, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := time.Duration(c.WriteTimeout)
+	g := &GRPC{timeout: timeout}
+	if tlsConfig != nil {
+		g.url = "https://" + addr + grpcWritePointsMethod
+		g.transport = &http2.Transport{TLSClientConfig: tlsConfig}
+	} else {
+		// gRPC without TLS uses HTTP/2 with prior knowledge.
+		g.url = "http://" + addr + grpcWritePointsMethod
+		g.transport = &http2.Transport{
+			AllowHTTP: true,
+			DialTLS: func(network, addr string, _ *tls.Config) (net.Conn, error) {
+				return net.DialTimeout(network, addr, timeout)
+			},
+		}
+	}
+	g.batcher = newBatcher(c, g.writeBatch)
+	return g, nil
+}
+
+// WritePoints writes points over gRPC transport. It returns once the batch of
+// the points was acknowledged by the server.
+func (g *GRPC) WritePoints(p *coordinator.WritePointsRequest) error {
+	return g.batcher.write(p)
+}
+
+// Close stops sending batches and closes the stream.
+func (g *GRPC) Close() error {
+	g.batcher.close()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.stream != nil {
+		g.stream.close()
+		g.stream = nil
+	}
+	g.transport.CloseIdleConnections()
+	return nil
+}
+
+// writeBatch sends a batch of writes, grouping consecutive writes to the
+// same database and time-to-live into a single message.
+func (g *GRPC) writeBatch(batch []*coordinator.WritePointsRequest) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var buf bytes.Buffer
+	for i := 0; i < len(batch); {
+		db, ttl := batch[i].Database, batch[i].TimeToLive
+
+		buf.Reset()
+		for ; i < len(batch) && batch[i].Database == db && batch[i].TimeToLive == ttl; i++ {
+			for _, pt := range batch[i].Points {
+				buf.WriteString(pt.String())
+				buf.WriteByte('\n')
+			}
+		}
+
+		msg, err := proto.Marshal(&internal.WritePointsRequest{
+			Database:   proto.String(db),
+			TimeToLive: proto.String(ttl),
+			Points:     buf.Bytes(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := g.writeMessage(msg); err != nil {
+			// The stream is in an unknown state after an error.
+			if g.stream != nil {
+				g.stream.close()
+				g.stream = nil
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMessage sends a request message and waits for its response.
+func (g *GRPC) writeMessage(msg []byte) error {
+	if g.stream == nil {
+		g.stream = g.openStream()
+	}
+	s := g.stream
+
+	// Abort the stream if the server doesn't respond in time.
+	var timedOut int32
+	timer := time.AfterFunc(g.timeout, func() {
+		atomic.StoreInt32(&timedOut, 1)
+		s.abort()
+	})
+
+	err := s.send(msg)
+	var resp internal.WritePointsResponse
+	if err == nil {
+		err = s.recv(&resp)
+	}
+
+	if !timer.Stop() && atomic.LoadInt32(&timedOut) == 1 {
+		return fmt.Errorf("grpc write timed out after %s", g.timeout)
+	}
+	return err
+}
+
+// grpcStream is a WritePoints stream, which is a single HTTP/2 request whose
+// body carries the request messages, and whose response body carries the
+// response messages.
+type grpcStream struct {
+	body   *io.PipeWriter
+	cancel context.CancelFunc
+
+	respc chan grpcResponse
+	resp  *http.Response
+}
+
+type grpcResponse struct {
+	resp *http.Response
+	err  error
+}
+
+// openStream starts the request of a new stream. The response arrives when
+// the server responds, which may not happen before the first message is sent.
+func (g *GRPC) openStream() *grpcStream {
+	pr, pw := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s := &grpcStream{
+		body:   pw,
+		cancel: cancel,
+		respc:  make(chan grpcResponse, 1),
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", g.url, pr)
+	req.Header.Set("Content-Type", "application/grpc+proto")
+	req.Header.Set("TE", "trailers")
+	req.Header.Set("User-Agent", "cnosdb-subscriber")
+
+	go func() {
+		resp, err := g.transport.RoundTrip(req)
+		s.respc <- grpcResponse{resp: resp, err: err}
+	}()
+	return s
+}
+
+// send sends a length-prefixed, uncompressed message.
+func (s *grpcStream) send(msg []byte) error {
+	var hdr [5]byte
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(msg)))
+	if _, err := s.body.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := s.body.Write(msg)
+	return err
+}
+
+// recv receives a message, or the status of the stream if the server ended it.
+func (s *grpcStream) recv(m proto.Message) error {
+	if s.resp == nil {
+		r := <-s.respc
+		if r.err != nil {
+			return r.err
+		}
+		s.resp = r.resp
+
+		if s.resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("grpc stream failed: %s", s.resp.Status)
+		}
+		// A stream that fails immediately has the status in the headers.
+		if err := grpcStatus(s.resp.Header); err != nil {
+			return err
+		}
+	}
+
+	var hdr [5]byte
+	if _, err := io.ReadFull(s.resp.Body, hdr[:]); err == io.EOF {
+		if err := grpcStatus(s.resp.Trailer); err != nil {
+			return err
+		}
+		return errors.New("grpc stream ended by the server")
+	} else if err != nil {
+		return err
+	}
+
+	if hdr[0] != 0 {
+		return errors.New("grpc compressed messages are not supported")
+	}
+	n := binary.BigEndian.Uint32(hdr[1:])
+	if n > grpcMaxMessageSize {
+		return fmt.Errorf("grpc message too large: %d bytes", n)
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(s.resp.Body, b); err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, m)
+}
+
+// abort cancels the request of the stream, which fails the pending send or
+// recv. It is safe to call concurrently with them.
+func (s *grpcStream) abort() {
+	s.cancel()
+	s.body.CloseWithError(errStreamClosed)
+}
+
+// close aborts the stream and releases the response.
+func (s *grpcStream) close() {
+	s.abort()
+	if s.resp != nil {
+		s.resp.Body.Close()
+	}
+}
+
+// grpcStatus returns the error of a gRPC status other than OK.
+func grpcStatus(h http.Header) error {
+	code := h.Get("Grpc-Status")
+	if code == "" || code == "0" {
+		return nil
+	}
+
+	msg := h.Get("Grpc-Message")
+	if s, err := url.PathUnescape(msg); err == nil {
+		msg = s
+	}
+	return fmt.Errorf("grpc status %s: %s", code, msg)
+}
@@ -0,0 +1,201 @@
+package subscriber
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	internal "github.com/cnosdatabase/cnosdb/server/subscriber/internal"
+	"github.com/gogo/protobuf/proto"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// grpcServer is a gRPC destination over HTTP/2 without TLS, implementing the
+// WritePoints method of the Subscriber service.
+type grpcServer struct {
+	*httptest.Server
+
+	// handle handles the messages of a stream, and returns the gRPC status
+	// ending the stream.
+	handle func(w http.ResponseWriter, r *http.Request) string
+
+	mu       sync.Mutex
+	streams  int
+	requests []*internal.WritePointsRequest
+}
+
+func newGRPCServer(handle func(s *grpcServer, w http.ResponseWriter, r *http.Request) string) *grpcServer {
+	s := &grpcServer{}
+	s.handle = func(w http.ResponseWriter, r *http.Request) string { return handle(s, w, r) }
+	s.Server = httptest.NewServer(h2c.NewHandler(http.HandlerFunc(s.serveStream), &http2.Server{}))
+	return s
+}
+
+func (s *grpcServer) serveStream(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != grpcWritePointsMethod || r.ProtoMajor != 2 {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.streams++
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	w.Header().Set("Trailer", "Grpc-Status")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+
+	w.Header().Set("Grpc-Status", s.handle(w, r))
+}
+
+// recv reads a request message of the stream, and returns false once the
+// stream ended.
+func (s *grpcServer) recv(r *http.Request) (*internal.WritePointsRequest, bool) {
+	var hdr [5]byte
+	if _, err := io.ReadFull(r.Body, hdr[:]); err != nil {
+		return nil, false
+	}
+	b := make([]byte, binary.BigEndian.Uint32(hdr[1:]))
+	if _, err := io.ReadFull(r.Body, b); err != nil {
+		return nil, false
+	}
+
+	var req internal.WritePointsRequest
+	if err := proto.Unmarshal(b, &req); err != nil {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, &req)
+	s.mu.Unlock()
+	return &req, true
+}
+
+// send writes a response message to the stream.
+func (s *grpcServer) send(w http.ResponseWriter, resp *internal.WritePointsResponse) {
+	b, _ := proto.Marshal(resp)
+	var hdr [5]byte
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(b)))
+	w.Write(hdr[:])
+	w.Write(b)
+	w.(http.Flusher).Flush()
+}
+
+// acknowledge acknowledges every request message of a stream.
+func acknowledge(s *grpcServer, w http.ResponseWriter, r *http.Request) string {
+	for {
+		req, ok := s.recv(r)
+		if !ok {
+			return "0"
+		}
+		n := bytes.Count(req.Points, []byte("\n"))
+		s.send(w, &internal.WritePointsResponse{PointsWritten: proto.Int64(int64(n))})
+	}
+}
+
+// newGRPC returns a gRPC points writer sending to the server.
+func newGRPC(t *testing.T, s *grpcServer, c SinkConfig) *GRPC {
+	t.Helper()
+	g, err := NewGRPC(strings.TrimPrefix(s.URL, "http://"), c, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestGRPC_WritePoints(t *testing.T) {
+	s := newGRPCServer(acknowledge)
+	defer s.Close()
+
+	g := newGRPC(t, s, newSinkConfig(3, 10, time.Hour, 5*time.Second))
+	defer g.Close()
+
+	// The writes are sent as one batch of 3 points, with a message for each
+	// database and time-to-live.
+	errs := writeAll(g.batcher,
+		newWrite(t, "db0", "ttl0", "cpu,host=a v=1 1\ncpu,host=b v=2 2"),
+		newWrite(t, "db1", "ttl1", "mem,host=a v=3 3"),
+	)
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	// The next batch is sent over the same stream.
+	if err := g.WritePoints(newWrite(t, "db0", "ttl0", "cpu,host=c v=4 4\ncpu,host=c v=5 5\ncpu,host=c v=6 6")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.streams != 1 {
+		t.Fatalf("got %d streams, exp 1", s.streams)
+	} else if len(s.requests) != 3 {
+		t.Fatalf("got %d messages, exp 3", len(s.requests))
+	}
+
+	points := make(map[string]string)
+	for _, req := range s.requests[:2] {
+		points[req.GetDatabase()+"."+req.GetTimeToLive()] = string(req.GetPoints())
+	}
+	if exp := "cpu,host=a v=1 1\ncpu,host=b v=2 2\n"; points["db0.ttl0"] != exp {
+		t.Fatalf("got points %q, exp %q", points["db0.ttl0"], exp)
+	}
+	if exp := "mem,host=a v=3 3\n"; points["db1.ttl1"] != exp {
+		t.Fatalf("got points %q, exp %q", points["db1.ttl1"], exp)
+	}
+	if exp := "cpu,host=c v=4 4\ncpu,host=c v=5 5\ncpu,host=c v=6 6\n"; string(s.requests[2].GetPoints()) != exp {
+		t.Fatalf("got points %q, exp %q", s.requests[2].GetPoints(), exp)
+	}
+}
+
+func TestGRPC_Status(t *testing.T) {
+	s := newGRPCServer(func(s *grpcServer, w http.ResponseWriter, r *http.Request) string {
+		s.recv(r)
+		return "13"
+	})
+	defer s.Close()
+
+	g := newGRPC(t, s, newSinkConfig(1, 10, time.Hour, 5*time.Second))
+	defer g.Close()
+
+	err := g.WritePoints(newWrite(t, "db0", "ttl0", "cpu v=1 1"))
+	if err == nil || !strings.Contains(err.Error(), "grpc status 13") {
+		t.Fatalf("got error %v, exp grpc status 13", err)
+	}
+
+	// The failed stream is replaced by a new one.
+	g.WritePoints(newWrite(t, "db0", "ttl0", "cpu v=2 2"))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.streams != 2 {
+		t.Fatalf("got %d streams, exp 2", s.streams)
+	}
+}
+
+func TestGRPC_Backpressure(t *testing.T) {
+	// The server reads the messages but never acknowledges them.
+	s := newGRPCServer(func(s *grpcServer, w http.ResponseWriter, r *http.Request) string {
+		s.recv(r)
+		<-r.Context().Done()
+		return "1"
+	})
+	defer s.Close()
+
+	g := newGRPC(t, s, newSinkConfig(1, 10, time.Hour, 100*time.Millisecond))
+	defer g.Close()
+
+	err := g.WritePoints(newWrite(t, "db0", "ttl0", "cpu v=1 1"))
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("got error %v, exp timeout", err)
+	}
+}
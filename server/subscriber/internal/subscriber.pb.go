@@ -0,0 +1,136 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: subscriber.proto
+
+package subscriber
+
+import (
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+type WritePointsRequest struct {
+	Database             *string  `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	TimeToLive           *string  `protobuf:"bytes,2,req,name=TimeToLive" json:"TimeToLive,omitempty"`
+	Points               []byte   `protobuf:"bytes,3,req,name=Points" json:"Points,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WritePointsRequest) Reset()         { *m = WritePointsRequest{} }
+func (m *WritePointsRequest) String() string { return proto.CompactTextString(m) }
+func (*WritePointsRequest) ProtoMessage()    {}
+func (*WritePointsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a004d881b890f1e0, []int{0}
+}
+func (m *WritePointsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WritePointsRequest.Unmarshal(m, b)
+}
+func (m *WritePointsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WritePointsRequest.Marshal(b, m, deterministic)
+}
+func (m *WritePointsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WritePointsRequest.Merge(m, src)
+}
+func (m *WritePointsRequest) XXX_Size() int {
+	return xxx_messageInfo_WritePointsRequest.Size(m)
+}
+func (m *WritePointsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WritePointsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WritePointsRequest proto.InternalMessageInfo
+
+func (m *WritePointsRequest) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *WritePointsRequest) GetTimeToLive() string {
+	if m != nil && m.TimeToLive != nil {
+		return *m.TimeToLive
+	}
+	return ""
+}
+
+func (m *WritePointsRequest) GetPoints() []byte {
+	if m != nil {
+		return m.Points
+	}
+	return nil
+}
+
+type WritePointsResponse struct {
+	PointsWritten        *int64   `protobuf:"varint,1,req,name=PointsWritten" json:"PointsWritten,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WritePointsResponse) Reset()         { *m = WritePointsResponse{} }
+func (m *WritePointsResponse) String() string { return proto.CompactTextString(m) }
+func (*WritePointsResponse) ProtoMessage()    {}
+func (*WritePointsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a004d881b890f1e0, []int{1}
+}
+func (m *WritePointsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WritePointsResponse.Unmarshal(m, b)
+}
+func (m *WritePointsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WritePointsResponse.Marshal(b, m, deterministic)
+}
+func (m *WritePointsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WritePointsResponse.Merge(m, src)
+}
+func (m *WritePointsResponse) XXX_Size() int {
+	return xxx_messageInfo_WritePointsResponse.Size(m)
+}
+func (m *WritePointsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WritePointsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WritePointsResponse proto.InternalMessageInfo
+
+func (m *WritePointsResponse) GetPointsWritten() int64 {
+	if m != nil && m.PointsWritten != nil {
+		return *m.PointsWritten
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*WritePointsRequest)(nil), "subscriber.WritePointsRequest")
+	proto.RegisterType((*WritePointsResponse)(nil), "subscriber.WritePointsResponse")
+}
+
+func init() { proto.RegisterFile("subscriber.proto", fileDescriptor_a004d881b890f1e0) }
+
+var fileDescriptor_a004d881b890f1e0 = []byte{
+	// 181 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x28, 0x2e, 0x4d, 0x2a,
+	0x4e, 0x2e, 0xca, 0x4c, 0x4a, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x42, 0x88,
+	0x28, 0x65, 0x70, 0x09, 0x85, 0x17, 0x65, 0x96, 0xa4, 0x06, 0xe4, 0x67, 0xe6, 0x95, 0x14, 0x07,
+	0xa5, 0x16, 0x96, 0xa6, 0x16, 0x97, 0x08, 0x49, 0x71, 0x71, 0xb8, 0x24, 0x96, 0x24, 0x26, 0x25,
+	0x16, 0xa7, 0x4a, 0x30, 0x2a, 0x30, 0x69, 0x70, 0x06, 0xc1, 0xf9, 0x42, 0x72, 0x5c, 0x5c, 0x21,
+	0x99, 0xb9, 0xa9, 0x21, 0xf9, 0x3e, 0x99, 0x65, 0xa9, 0x12, 0x4c, 0x60, 0x59, 0x24, 0x11, 0x21,
+	0x31, 0x2e, 0x36, 0x88, 0x61, 0x12, 0xcc, 0x0a, 0x4c, 0x1a, 0x3c, 0x41, 0x50, 0x9e, 0x92, 0x35,
+	0x97, 0x30, 0x8a, 0x4d, 0xc5, 0x05, 0xf9, 0x79, 0xc5, 0xa9, 0x42, 0x2a, 0x5c, 0xbc, 0x10, 0x11,
+	0x90, 0x64, 0x49, 0x6a, 0x1e, 0xd8, 0x3e, 0xe6, 0x20, 0x54, 0x41, 0xa3, 0x04, 0x2e, 0xae, 0x60,
+	0xb8, 0xa3, 0x85, 0x82, 0xb8, 0xb8, 0x91, 0x8c, 0x12, 0x92, 0xd3, 0x43, 0xf2, 0x22, 0xa6, 0x6f,
+	0xa4, 0xe4, 0x71, 0xca, 0x43, 0xdc, 0xa0, 0xc1, 0x68, 0xc0, 0x08, 0x18, 0x00, 0xf7, 0x3b, 0xaa,
+	0x27, 0x27, 0x01, 0x00, 0x00,
+}
@@ -4,9 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/cnosdatabase/cnosdb/meta"
 	"github.com/cnosdatabase/cnosdb/monitor"
@@ -56,8 +56,9 @@ type Service struct {
 	mu              sync.Mutex
 	conf            Config
 
-	subs  map[subEntry]chanWriter
-	subMu sync.RWMutex
+	subs    map[subEntry]chanWriter
+	durable map[subEntry]*durableWriter
+	subMu   sync.RWMutex
 }
 
 // NewService returns a subscriber service with given settings
@@ -154,6 +155,9 @@ func (s *Service) Statistics(tags map[string]string) []models.Statistic {
 	for _, sub := range s.subs {
 		statistics = append(statistics, sub.Statistics(tags)...)
 	}
+	for _, dw := range s.durable {
+		statistics = append(statistics, dw.Statistics(tags)...)
+	}
 	return statistics
 }
 
@@ -183,7 +187,7 @@ func (s *Service) Update() error {
 	}
 }
 
-func (s *Service) createSubscription(se subEntry, mode string, destinations []string) (PointsWriter, error) {
+func (s *Service) createSubscription(se subEntry, mode string, destinations []string) (*balancewriter, error) {
 	var bm BalanceMode
 	switch mode {
 	case "ALL":
@@ -203,7 +207,10 @@ func (s *Service) createSubscription(se subEntry, mode string, destinations []st
 		}
 		w, err := s.NewPointsWriter(*u)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create writer for destination: %s", dest)
+			for _, w := range writers {
+				closeWriter(w)
+			}
+			return nil, fmt.Errorf("failed to create writer for destination: %s: %s", dest, err)
 		}
 		writers = append(writers, w)
 		stats = append(stats, writerStats{dest: dest})
@@ -222,11 +229,51 @@ func (s *Service) createSubscription(se subEntry, mode string, destinations []st
 	}, nil
 }
 
+// createDurableSubscription creates the writer queueing the writes of a
+// durable subscription on disk.
+func (s *Service) createDurableSubscription(se subEntry, mode string, destinations []string) (*durableWriter, error) {
+	if s.conf.Dir == "" {
+		return nil, errors.New("durable subscriptions require the subscriber dir to be set")
+	}
+
+	bw, err := s.createSubscription(se, mode, destinations)
+	if err != nil {
+		return nil, err
+	}
+
+	dw := newDurableWriter(filepath.Join(s.conf.Dir, se.db, se.ttl, se.name), bw, s.conf, s.Logger)
+	if err := dw.Open(); err != nil {
+		bw.Close()
+		return nil, err
+	}
+	return dw, nil
+}
+
 // Points returns a channel into which write point requests can be sent.
 func (s *Service) Points() chan<- *coordinator.WritePointsRequest {
 	return s.points
 }
 
+// WriteDurable queues a write on disk for the durable subscriptions of its
+// database and time-to-live. The writes of other subscriptions are sent to
+// the Points channel. The write is dropped for the queues that are full or
+// failing, and the last error is returned.
+func (s *Service) WriteDurable(p *coordinator.WritePointsRequest) error {
+	s.subMu.RLock()
+	defer s.subMu.RUnlock()
+
+	var lastErr error
+	for se, dw := range s.durable {
+		if p.Database == se.db && p.TimeToLive == se.ttl {
+			if err := dw.WritePoints(p); err != nil {
+				atomic.AddInt64(&s.stats.WriteFailures, 1)
+				lastErr = err
+			}
+		}
+	}
+	return lastErr
+}
+
 // run read points from the points channel and writes them to the subscriptions.
 func (s *Service) run() {
 	var wg sync.WaitGroup
@@ -266,7 +313,17 @@ func (s *Service) close(wg *sync.WaitGroup) {
 	}
 	// Wait for them to finish
 	wg.Wait()
+	for _, cw := range s.subs {
+		closeWriter(cw.pw)
+	}
 	s.subs = nil
+
+	// Writes queued by durable subscriptions are delivered when the
+	// service is opened again.
+	for _, dw := range s.durable {
+		dw.Close()
+	}
+	s.durable = nil
 }
 
 func (s *Service) updateSubs(wg *sync.WaitGroup) {
@@ -276,6 +333,9 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 	if s.subs == nil {
 		s.subs = make(map[subEntry]chanWriter)
 	}
+	if s.durable == nil {
+		s.durable = make(map[subEntry]*durableWriter)
+	}
 
 	dbis := s.MetaClient.Databases()
 	allEntries := make(map[subEntry]bool)
@@ -292,6 +352,24 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 				if _, ok := s.subs[se]; ok {
 					continue
 				}
+				if _, ok := s.durable[se]; ok {
+					continue
+				}
+
+				if si.Durable {
+					dw, err := s.createDurableSubscription(se, si.Mode, si.Destinations)
+					if err != nil {
+						atomic.AddInt64(&s.stats.CreateFailures, 1)
+						s.Logger.Info("Subscription creation failed", zap.String("name", si.Name), zap.Error(err))
+						continue
+					}
+					s.durable[se] = dw
+					s.Logger.Info("Added new durable subscription",
+						logger.Database(se.db),
+						logger.TimeToLive(se.ttl))
+					continue
+				}
+
 				sub, err := s.createSubscription(se, si.Mode, si.Destinations)
 				if err != nil {
 					atomic.AddInt64(&s.stats.CreateFailures, 1)
@@ -304,11 +382,14 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 					pointsWritten: &s.stats.PointsWritten,
 					failures:      &s.stats.WriteFailures,
 					logger:        s.Logger,
+					running:       &sync.WaitGroup{},
 				}
 				for i := 0; i < s.conf.WriteConcurrency; i++ {
 					wg.Add(1)
+					cw.running.Add(1)
 					go func() {
 						defer wg.Done()
+						defer cw.running.Done()
 						cw.Run()
 					}()
 				}
@@ -323,8 +404,13 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 	// Remove deleted subs
 	for se := range s.subs {
 		if !allEntries[se] {
-			// Close the chanWriter
-			s.subs[se].Close()
+			// Close the chanWriter, and the writer once the pending writes are done.
+			cw := s.subs[se]
+			cw.Close()
+			go func() {
+				cw.running.Wait()
+				closeWriter(cw.pw)
+			}()
 
 			// Remove it from the set
 			delete(s.subs, se)
@@ -333,23 +419,30 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 				logger.TimeToLive(se.ttl))
 		}
 	}
+	for se, dw := range s.durable {
+		if !allEntries[se] {
+			// Drop the writes that were not delivered.
+			dw.Close()
+			if err := dw.Remove(); err != nil {
+				s.Logger.Info("Failed to remove subscription queue", zap.String("name", se.name), zap.Error(err))
+			}
+
+			delete(s.durable, se)
+			s.Logger.Info("Deleted old durable subscription",
+				logger.Database(se.db),
+				logger.TimeToLive(se.ttl))
+		}
+	}
 }
 
-// newPointsWriter returns a new PointsWriter from the given URL.
+// newPointsWriter returns a new PointsWriter from the given URL, using the
+// sink registered for its scheme.
 func (s *Service) newPointsWriter(u url.URL) (PointsWriter, error) {
-	switch u.Scheme {
-	case "udp":
-		return NewUDP(u.Host), nil
-	case "http":
-		return NewHTTP(u.String(), time.Duration(s.conf.HTTPTimeout))
-	case "https":
-		if s.conf.InsecureSkipVerify {
-			s.Logger.Warn("'insecure-skip-verify' is true. This will skip all certificate verifications.")
-		}
-		return NewHTTPS(u.String(), time.Duration(s.conf.HTTPTimeout), s.conf.InsecureSkipVerify, s.conf.CaCerts, s.conf.TLS)
-	default:
+	sink, ok := lookupSink(u.Scheme)
+	if !ok {
 		return nil, fmt.Errorf("unknown destination scheme %s", u.Scheme)
 	}
+	return sink(u, s.conf, s.Logger)
 }
 
 // chanWriter sends WritePointsRequest to a PointsWriter received over a channel.
@@ -359,6 +452,7 @@ type chanWriter struct {
 	pointsWritten *int64
 	failures      *int64
 	logger        *zap.Logger
+	running       *sync.WaitGroup
 }
 
 // Close closes the chanWriter.
@@ -412,6 +506,17 @@ type balancewriter struct {
 	i           int
 }
 
+// Close closes the writers of the destinations.
+func (b *balancewriter) Close() error {
+	var lastErr error
+	for _, w := range b.writers {
+		if err := closeWriter(w); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
 func (b *balancewriter) WritePoints(p *coordinator.WritePointsRequest) error {
 	var lastErr error
 	for range b.writers {
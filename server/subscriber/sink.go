@@ -0,0 +1,221 @@
+package subscriber
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/server/coordinator"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrSinkFull is returned when a write to a sink times out waiting for
+	// buffer space, because the destination can't keep up with the writes.
+	ErrSinkFull = errors.New("subscription sink buffer is full")
+
+	// ErrSinkClosed is returned when writing to a closed sink.
+	ErrSinkClosed = errors.New("subscription sink is closed")
+)
+
+// Sink returns a PointsWriter writing to the destination at u.
+type Sink func(u url.URL, c Config, logger *zap.Logger) (PointsWriter, error)
+
+var (
+	sinksMu sync.RWMutex
+	sinks   = make(map[string]Sink)
+)
+
+// RegisterSink registers the sink writing to the destinations with the URL
+// scheme. It panics if the scheme is already registered.
+func RegisterSink(scheme string, sink Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+
+	if _, ok := sinks[scheme]; ok {
+		panic(fmt.Sprintf("subscription sink already registered for scheme %s", scheme))
+	}
+	sinks[scheme] = sink
+}
+
+// lookupSink returns the sink registered for the URL scheme.
+func lookupSink(scheme string) (Sink, bool) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+
+	sink, ok := sinks[scheme]
+	return sink, ok
+}
+
+func init() {
+	RegisterSink("udp", func(u url.URL, c Config, logger *zap.Logger) (PointsWriter, error) {
+		return NewUDP(u.Host), nil
+	})
+	RegisterSink("http", func(u url.URL, c Config, logger *zap.Logger) (PointsWriter, error) {
+		return NewHTTP(u.String(), time.Duration(c.HTTPTimeout))
+	})
+	RegisterSink("https", func(u url.URL, c Config, logger *zap.Logger) (PointsWriter, error) {
+		if c.InsecureSkipVerify {
+			logger.Warn("'insecure-skip-verify' is true. This will skip all certificate verifications.")
+		}
+		return NewHTTPS(u.String(), time.Duration(c.HTTPTimeout), c.InsecureSkipVerify, c.CaCerts, c.TLS)
+	})
+	RegisterSink("tcp", func(u url.URL, c Config, logger *zap.Logger) (PointsWriter, error) {
+		if c.TCP.TLSEnabled && c.TCP.InsecureSkipVerify {
+			logger.Warn("'tcp.insecure-skip-verify' is true. This will skip all certificate verifications.")
+		}
+		return NewTCP(u.Host, c.TCP, c.TLS)
+	})
+	RegisterSink("grpc", func(u url.URL, c Config, logger *zap.Logger) (PointsWriter, error) {
+		if c.GRPC.TLSEnabled && c.GRPC.InsecureSkipVerify {
+			logger.Warn("'grpc.insecure-skip-verify' is true. This will skip all certificate verifications.")
+		}
+		return NewGRPC(u.Host, c.GRPC, c.TLS)
+	})
+}
+
+// closeWriter closes a PointsWriter if it holds resources, such as connections.
+func closeWriter(w PointsWriter) error {
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// sinkTLSConfig returns the TLS config of a sink, or nil if TLS is disabled.
+func sinkTLSConfig(c SinkConfig, tlsConfig *tls.Config) (*tls.Config, error) {
+	if !c.TLSEnabled {
+		return nil, nil
+	}
+
+	tlsConfig, err := createTLSConfig(c.CaCerts, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig == nil {
+		tlsConfig = new(tls.Config)
+	}
+	tlsConfig.InsecureSkipVerify = c.InsecureSkipVerify
+	return tlsConfig, nil
+}
+
+// batchWrite is a write waiting in a batcher for its batch to be sent.
+type batchWrite struct {
+	p   *coordinator.WritePointsRequest
+	err chan error
+}
+
+// batcher collects the writes to a sink into batches, which it sends one at
+// a time. A batch is sent when it reaches the batch size, or when the batch
+// timeout expires after its first write.
+//
+// Writes wait in a buffer while a batch is sent, and fail with ErrSinkFull
+// if the buffer stays full for the write timeout, so that a slow destination
+// pushes back on the writers instead of piling up writes in memory.
+type batcher struct {
+	size         int
+	timeout      time.Duration
+	writeTimeout time.Duration
+
+	// send sends a batch of writes to the destination.
+	send func(batch []*coordinator.WritePointsRequest) error
+
+	writes chan batchWrite
+	done   chan struct{}
+	wg     sync.WaitGroup
+	once   sync.Once
+}
+
+// newBatcher returns a batcher sending batches with send, and starts it.
+func newBatcher(c SinkConfig, send func(batch []*coordinator.WritePointsRequest) error) *batcher {
+	b := &batcher{
+		size:         c.BatchSize,
+		timeout:      time.Duration(c.BatchTimeout),
+		writeTimeout: time.Duration(c.WriteTimeout),
+		send:         send,
+		writes:       make(chan batchWrite, c.BufferSize),
+		done:         make(chan struct{}),
+	}
+	b.wg.Add(1)
+	go b.run()
+	return b
+}
+
+// write adds a write to a batch, and waits for the batch to be sent.
+func (b *batcher) write(p *coordinator.WritePointsRequest) error {
+	w := batchWrite{p: p, err: make(chan error, 1)}
+
+	timer := time.NewTimer(b.writeTimeout)
+	defer timer.Stop()
+
+	select {
+	case b.writes <- w:
+	case <-timer.C:
+		return ErrSinkFull
+	case <-b.done:
+		return ErrSinkClosed
+	}
+
+	select {
+	case err := <-w.err:
+		return err
+	case <-b.done:
+		return ErrSinkClosed
+	}
+}
+
+// close stops sending batches. Waiting writes fail with ErrSinkClosed.
+func (b *batcher) close() {
+	b.once.Do(func() { close(b.done) })
+	b.wg.Wait()
+}
+
+func (b *batcher) run() {
+	defer b.wg.Done()
+
+	var (
+		pending []batchWrite
+		points  int
+		timer   *time.Timer
+		timeout <-chan time.Time
+	)
+
+	flush := func() {
+		timer.Stop()
+		batch := make([]*coordinator.WritePointsRequest, len(pending))
+		for i := range pending {
+			batch[i] = pending[i].p
+		}
+		err := b.send(batch)
+		for _, w := range pending {
+			w.err <- err
+		}
+		pending, points, timer, timeout = pending[:0], 0, nil, nil
+	}
+
+	for {
+		select {
+		case w := <-b.writes:
+			if len(pending) == 0 {
+				timer = time.NewTimer(b.timeout)
+				timeout = timer.C
+			}
+			pending = append(pending, w)
+			points += len(w.p.Points)
+			if points >= b.size {
+				flush()
+			}
+		case <-timeout:
+			flush()
+		case <-b.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package subscriber
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/server/coordinator"
+	"github.com/cnosdatabase/common/pkg/toml"
+	"github.com/cnosdatabase/db/models"
+)
+
+// newSinkConfig returns a sink config with the batch size and timeouts.
+func newSinkConfig(batchSize, bufferSize int, batchTimeout, writeTimeout time.Duration) SinkConfig {
+	c := NewSinkConfig()
+	c.BatchSize = batchSize
+	c.BufferSize = bufferSize
+	c.BatchTimeout = toml.Duration(batchTimeout)
+	c.WriteTimeout = toml.Duration(writeTimeout)
+	return c
+}
+
+// newWrite returns a write of the points in the line protocol.
+func newWrite(t *testing.T, database, ttl, lines string) *coordinator.WritePointsRequest {
+	t.Helper()
+	points, err := models.ParsePointsString(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &coordinator.WritePointsRequest{Database: database, TimeToLive: ttl, Points: points}
+}
+
+// writeAll writes each write in its own goroutine, and returns their errors.
+func writeAll(b *batcher, writes ...*coordinator.WritePointsRequest) []error {
+	errs := make([]error, len(writes))
+	var wg sync.WaitGroup
+	for i, p := range writes {
+		wg.Add(1)
+		go func(i int, p *coordinator.WritePointsRequest) {
+			defer wg.Done()
+			errs[i] = b.write(p)
+		}(i, p)
+	}
+	wg.Wait()
+	return errs
+}
+
+func TestBatcher_BatchSize(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]*coordinator.WritePointsRequest
+	b := newBatcher(newSinkConfig(3, 10, time.Hour, time.Second), func(batch []*coordinator.WritePointsRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		batches = append(batches, batch)
+		return nil
+	})
+	defer b.close()
+
+	// The batch is sent once it holds 3 points, long before the batch timeout.
+	errs := writeAll(b,
+		newWrite(t, "db0", "ttl0", "cpu v=1 1"),
+		newWrite(t, "db0", "ttl0", "cpu v=2 2\ncpu v=3 3"),
+	)
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(batches) != 1 {
+		t.Fatalf("got %d batches, exp 1", len(batches))
+	} else if len(batches[0]) != 2 {
+		t.Fatalf("got %d writes in the batch, exp 2", len(batches[0]))
+	}
+}
+
+func TestBatcher_BatchTimeout(t *testing.T) {
+	sent := make(chan int, 1)
+	b := newBatcher(newSinkConfig(100, 10, 10*time.Millisecond, time.Second), func(batch []*coordinator.WritePointsRequest) error {
+		sent <- len(batch)
+		return nil
+	})
+	defer b.close()
+
+	if err := b.write(newWrite(t, "db0", "ttl0", "cpu v=1 1")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n := <-sent; n != 1 {
+		t.Fatalf("got %d writes in the batch, exp 1", n)
+	}
+}
+
+func TestBatcher_SendError(t *testing.T) {
+	errSend := errors.New("destination unavailable")
+	b := newBatcher(newSinkConfig(2, 10, time.Hour, time.Second), func(batch []*coordinator.WritePointsRequest) error {
+		return errSend
+	})
+	defer b.close()
+
+	// Every write of the failed batch fails.
+	errs := writeAll(b,
+		newWrite(t, "db0", "ttl0", "cpu v=1 1"),
+		newWrite(t, "db0", "ttl0", "cpu v=2 2"),
+	)
+	for _, err := range errs {
+		if err != errSend {
+			t.Fatalf("got error %v, exp %v", err, errSend)
+		}
+	}
+}
+
+func TestBatcher_Backpressure(t *testing.T) {
+	sending := make(chan struct{})
+	release := make(chan struct{})
+	b := newBatcher(newSinkConfig(1, 1, time.Hour, 50*time.Millisecond), func(batch []*coordinator.WritePointsRequest) error {
+		sending <- struct{}{}
+		<-release
+		return nil
+	})
+	defer b.close()
+
+	// The first write is being sent, and the second one fills the buffer.
+	errs := make(chan error, 2)
+	go func() { errs <- b.write(newWrite(t, "db0", "ttl0", "cpu v=1 1")) }()
+	<-sending
+	go func() { errs <- b.write(newWrite(t, "db0", "ttl0", "cpu v=2 2")) }()
+	for len(b.writes) < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The third write fails once it waited for buffer space for the write timeout.
+	if err := b.write(newWrite(t, "db0", "ttl0", "cpu v=3 3")); err != ErrSinkFull {
+		t.Fatalf("got error %v, exp %v", err, ErrSinkFull)
+	}
+
+	// The buffered write is sent once the destination catches up.
+	close(release)
+	<-sending
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
+
+func TestBatcher_Closed(t *testing.T) {
+	b := newBatcher(newSinkConfig(1, 1, time.Hour, time.Second), func(batch []*coordinator.WritePointsRequest) error {
+		return nil
+	})
+	b.close()
+
+	if err := b.write(newWrite(t, "db0", "ttl0", "cpu v=1 1")); err != ErrSinkClosed {
+		t.Fatalf("got error %v, exp %v", err, ErrSinkClosed)
+	}
+}
@@ -0,0 +1,110 @@
+package subscriber
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/cnosdatabase/cnosdb/server/coordinator"
+)
+
+// TCP supports writing points over a TCP connection using the line protocol.
+// Writes are sent in batches over a single connection, which is opened when
+// needed and reopened after an error.
+type TCP struct {
+	addr      string
+	timeout   time.Duration
+	tlsConfig *tls.Config
+
+	mu   sync.Mutex
+	conn net.Conn
+	buf  bytes.Buffer
+
+	batcher *batcher
+}
+
+// NewTCP returns a new TCP points writer. The connection uses TLS if it is
+// enabled in the config, with tlsConfig as the base TLS config.
+func NewTCP(addr string, c SinkConfig, tlsConfig *tls.Config) (*TCP, error) {
+	tlsConfig, err := sinkTLSConfig(c, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &TCP{
+		addr:      addr,
+		timeout:   time.Duration(c.WriteTimeout),
+		tlsConfig: tlsConfig,
+	}
+	t.batcher = newBatcher(c, t.writeBatch)
+	return t, nil
+}
+
+// WritePoints writes points over TCP transport. It returns once the batch of
+// the points was written to the connection.
+func (t *TCP) WritePoints(p *coordinator.WritePointsRequest) error {
+	return t.batcher.write(p)
+}
+
+// Close stops sending batches and closes the connection.
+func (t *TCP) Close() error {
+	t.batcher.close()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	return err
+}
+
+// writeBatch writes the points of a batch of writes to the connection.
+func (t *TCP) writeBatch(batch []*coordinator.WritePointsRequest) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.conn == nil {
+		conn, err := t.dial()
+		if err != nil {
+			return err
+		}
+		t.conn = conn
+	}
+
+	t.buf.Reset()
+	for _, p := range batch {
+		for _, pt := range p.Points {
+			t.buf.WriteString(pt.String())
+			t.buf.WriteByte('\n')
+		}
+	}
+
+	if err := t.conn.SetWriteDeadline(time.Now().Add(t.timeout)); err != nil {
+		t.reset()
+		return err
+	}
+	if _, err := t.conn.Write(t.buf.Bytes()); err != nil {
+		// A partial write leaves the connection in an unknown state.
+		t.reset()
+		return err
+	}
+	return nil
+}
+
+func (t *TCP) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: t.timeout}
+	if t.tlsConfig != nil {
+		return tls.DialWithDialer(dialer, "tcp", t.addr, t.tlsConfig)
+	}
+	return dialer.Dial("tcp", t.addr)
+}
+
+// reset closes the connection, so that the next batch opens a new one.
+func (t *TCP) reset() {
+	t.conn.Close()
+	t.conn = nil
+}
@@ -0,0 +1,122 @@
+package subscriber
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tcpServer is a TCP destination collecting the lines it receives.
+type tcpServer struct {
+	ln    net.Listener
+	lines chan string
+
+	mu    sync.Mutex
+	conns int
+}
+
+func newTCPServer(t *testing.T) *tcpServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &tcpServer{ln: ln, lines: make(chan string, 100)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns++
+			s.mu.Unlock()
+
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					s.lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+	return s
+}
+
+// next returns the next line received by the server.
+func (s *tcpServer) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case line := <-s.lines:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a line")
+		return ""
+	}
+}
+
+func TestTCP_WritePoints(t *testing.T) {
+	s := newTCPServer(t)
+	defer s.ln.Close()
+
+	w, err := NewTCP(s.ln.Addr().String(), newSinkConfig(2, 10, time.Hour, time.Second), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// The writes are sent as one batch of 2 points.
+	errs := writeAll(w.batcher,
+		newWrite(t, "db0", "ttl0", "cpu,host=a v=1 1"),
+		newWrite(t, "db0", "ttl0", "cpu,host=b v=2 2"),
+	)
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	got := map[string]bool{s.next(t): true, s.next(t): true}
+	for _, exp := range []string{"cpu,host=a v=1 1", "cpu,host=b v=2 2"} {
+		if !got[exp] {
+			t.Fatalf("line %q not received, got %v", exp, got)
+		}
+	}
+
+	// The next batch is sent over the same connection.
+	if err := w.WritePoints(newWrite(t, "db0", "ttl0", "cpu,host=c v=3 3\ncpu,host=c v=4 4")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if line := s.next(t); line != "cpu,host=c v=3 3" {
+		t.Fatalf("got line %q", line)
+	}
+	if line := s.next(t); line != "cpu,host=c v=4 4" {
+		t.Fatalf("got line %q", line)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conns != 1 {
+		t.Fatalf("got %d connections, exp 1", s.conns)
+	}
+}
+
+func TestTCP_Unavailable(t *testing.T) {
+	s := newTCPServer(t)
+	addr := s.ln.Addr().String()
+	s.ln.Close()
+
+	w, err := NewTCP(addr, newSinkConfig(1, 10, time.Hour, time.Second), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := w.WritePoints(newWrite(t, "db0", "ttl0", "cpu v=1 1")); err == nil {
+		t.Fatal("expected an error writing to a closed listener")
+	}
+}